@@ -1,42 +1,445 @@
 package bootstrap
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
 
+	"akeneo-migrator/internal/asset"
+	asset_syncing "akeneo-migrator/internal/asset/syncing"
+	asset_syncing_asset "akeneo-migrator/internal/asset/syncing_asset"
+	"akeneo-migrator/internal/association_type"
+	association_type_syncing "akeneo-migrator/internal/association_type/syncing"
+	association_type_syncing_all "akeneo-migrator/internal/association_type/syncing_all"
+	"akeneo-migrator/internal/attribute"
 	attribute_syncing "akeneo-migrator/internal/attribute/syncing"
+	attribute_syncing_all "akeneo-migrator/internal/attribute/syncing_all"
+	"akeneo-migrator/internal/attribute_group"
+	attribute_group_syncing "akeneo-migrator/internal/attribute_group/syncing"
+	"akeneo-migrator/internal/category"
 	category_syncing "akeneo-migrator/internal/category/syncing"
+	category_syncing_tree "akeneo-migrator/internal/category/syncing_tree"
+	category_verifying "akeneo-migrator/internal/category/verifying"
+	"akeneo-migrator/internal/channel"
+	channel_syncing "akeneo-migrator/internal/channel/syncing"
+	currency_syncing "akeneo-migrator/internal/currency/syncing"
+	"akeneo-migrator/internal/family"
 	family_syncing "akeneo-migrator/internal/family/syncing"
+	family_syncing_all "akeneo-migrator/internal/family/syncing_all"
+	family_verifying "akeneo-migrator/internal/family/verifying"
+	"akeneo-migrator/internal/job"
+	"akeneo-migrator/internal/job/retrying"
+	"akeneo-migrator/internal/measurement_family"
+	measurement_family_syncing "akeneo-migrator/internal/measurement_family/syncing"
+	migration_migrating "akeneo-migrator/internal/migration/migrating"
+	"akeneo-migrator/internal/plan"
+	"akeneo-migrator/internal/plan/applying"
 	"akeneo-migrator/internal/platform/client/akeneo"
+	"akeneo-migrator/internal/platform/client/cassette"
 	"akeneo-migrator/internal/platform/config"
+	"akeneo-migrator/internal/platform/mockserver"
+	"akeneo-migrator/internal/platform/runner"
 	akeneo_storage "akeneo-migrator/internal/platform/storage/akeneo"
+	file_storage "akeneo-migrator/internal/platform/storage/file"
 	"akeneo-migrator/internal/platform/web"
 	product_syncing "akeneo-migrator/internal/product/syncing"
+	product_syncing_dependencies "akeneo-migrator/internal/product/syncing_dependencies"
+	product_syncing_file "akeneo-migrator/internal/product/syncing_file"
+	product_syncing_model "akeneo-migrator/internal/product/syncing_model"
+	product_syncing_published "akeneo-migrator/internal/product/syncing_published"
+	product_syncing_search "akeneo-migrator/internal/product/syncing_search"
 	product_syncing_since "akeneo-migrator/internal/product/syncing_since"
+	product_validating "akeneo-migrator/internal/product/validating"
+	"akeneo-migrator/internal/reference_entity"
+	reference_entity_listing "akeneo-migrator/internal/reference_entity/listing"
 	"akeneo-migrator/internal/reference_entity/syncing"
+	reference_entity_syncing_record "akeneo-migrator/internal/reference_entity/syncing_record"
+	reference_entity_verifying "akeneo-migrator/internal/reference_entity/verifying"
+	"akeneo-migrator/internal/stats"
+	stats_counting "akeneo-migrator/internal/stats/counting"
+	structure_syncing "akeneo-migrator/internal/structure/syncing"
+	"akeneo-migrator/kit/anonymize"
+	"akeneo-migrator/kit/attributes"
 	"akeneo-migrator/kit/bus"
 	"akeneo-migrator/kit/bus/in_memory"
 	"akeneo-migrator/kit/bus/in_memory/middleware"
+	"akeneo-migrator/kit/channels"
+	"akeneo-migrator/kit/checksum"
 	"akeneo-migrator/kit/config/static/viper"
+	"akeneo-migrator/kit/conflict"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/families"
+	"akeneo-migrator/kit/labels"
+	"akeneo-migrator/kit/limit"
+	"akeneo-migrator/kit/locales"
+	"akeneo-migrator/kit/logger"
+	"akeneo-migrator/kit/progress"
+	"akeneo-migrator/kit/prune"
+	"akeneo-migrator/kit/session"
 
 	"github.com/spf13/cobra"
 )
 
 const CONTEXT = "akeneo-migrator"
 
+// RecordDirEnvVar enables recording of source and destination API calls into cassette files
+const RecordDirEnvVar = "AKENEO_RECORD_DIR"
+
+// progressInterval is the minimum time between two progress lines of a sync
+const progressInterval = 5 * time.Second
+
+// Output formats of the --output flag
+const (
+	// OutputText prints human-readable messages to stdout
+	OutputText = "text"
+	// OutputJSON prints the session report to stdout at the end of the run, the messages going to stderr
+	OutputJSON = "json"
+)
+
 // Application contains all application dependencies
 type Application struct {
 	Config     *config.Config
 	CommandBus bus.Bus
+	Jobs       *retrying.Service
+	// Plans stores the plan files written by plan and read by apply
+	Plans plan.Repository
+	// Session aggregates the results of the commands executed in this invocation
+	Session *session.Session
+	// Metrics collects the API calls of each instance when --metrics is set
+	Metrics map[string]*akeneo.MetricsCollector
+	// Anonymizer scrubs the synced values; the values it scrubbed are reported at the end of the run
+	Anonymizer *anonymize.Anonymizer
+	// Output receives the session report with --output json; it is nil in text mode
+	Output io.Writer
+	// Progress prints the progress reported by the syncs of the run
+	Progress *progress.Bar
+	// Logger receives the messages of the services and clients, filtered by -v and -q
+	Logger *logger.Logger
 }
 
-// Run initializes the application and executes CLI commands
-func Run() error {
+// Run initializes the application, executes CLI commands and returns the exit code of the run
+func Run() int {
 	// 0. Setup default environment variables if not defined
 	setupDefaultEnvironmentVariables()
 
+	// 1. Create application; dependencies are wired by commands that need the Akeneo instances
+	app := &Application{Session: session.New(), Logger: logger.Default()}
+
+	// 2. Create root command
+	rootCmd := &cobra.Command{
+		Use:   "akeneo-migrator",
+		Short: "CLI tool to migrate data between Akeneo instances",
+		Long: `akeneo-migrator is a CLI tool that allows you to synchronize data
+between different Akeneo PIM instances, including Reference Entities,
+products, categories and other elements.`,
+		PersistentPreRunE: app.setupOutput,
+		// Errors are printed with the exit code of the run
+		SilenceErrors: true,
+	}
+
+	rootCmd.PersistentFlags().String("output", OutputText, "Output format: text, or json to print the result of every command (counts, failed items, duration) to stdout, messages going to stderr")
+	rootCmd.PersistentFlags().String("report", "", "Write a JSON report of the session (all executed steps) to this file")
+	rootCmd.PersistentFlags().Bool("metrics", false, "Print the API calls made to each instance by endpoint at the end")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "Read and validate everything but only record the writes instead of sending them to destination")
+	rootCmd.PersistentFlags().String("failure-manifest", "", "Also write the items that failed during the run to this file, to be retried with retry-failed <file>")
+	rootCmd.PersistentFlags().StringSlice("locales", nil, "Only sync the product, product model and record values in these locales (e.g. en_US,fr_FR)")
+	rootCmd.PersistentFlags().StringSlice("channels", nil, "Only sync the scoped values in these channels, source=dest renaming a channel (e.g. ecommerce=web,print)")
+	rootCmd.PersistentFlags().Int("workers", 0, "Number of concurrent writes against destination run by record, asset and bulk product syncs (sync.workers by default)")
+	rootCmd.PersistentFlags().String("run-id", "", "Correlation ID sent in the X-Request-Id header of every API request (random by default)")
+	addConnectionFlags(rootCmd)
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Also print the debug messages of the syncs and the API payloads")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Only print warnings and errors, without the progress of the syncs")
+	rootCmd.PersistentFlags().String("log-format", string(logger.Console), "Format of the messages: console, or json for one JSON object per message")
+
+	// 3. Add commands
+	syncCmd := createSyncCommand(app)
+	rootCmd.AddCommand(syncCmd)
+
+	syncRecordCmd := createSyncRecordCommand(app)
+	rootCmd.AddCommand(syncRecordCmd)
+
+	syncAssetFamilyCmd := createSyncAssetFamilyCommand(app)
+	rootCmd.AddCommand(syncAssetFamilyCmd)
+
+	syncAssetCmd := createSyncAssetCommand(app)
+	rootCmd.AddCommand(syncAssetCmd)
+
+	syncProductCmd := createSyncProductCommand(app)
+	rootCmd.AddCommand(syncProductCmd)
+
+	syncProductModelCmd := createSyncProductModelCommand(app)
+	rootCmd.AddCommand(syncProductModelCmd)
+
+	validateProductsCmd := createValidateProductsCommand(app)
+	rootCmd.AddCommand(validateProductsCmd)
+
+	syncAttributeCmd := createSyncAttributeCommand(app)
+	rootCmd.AddCommand(syncAttributeCmd)
+
+	syncAllAttributesCmd := createSyncAllAttributesCommand(app)
+	rootCmd.AddCommand(syncAllAttributesCmd)
+
+	syncAttributeGroupCmd := createSyncAttributeGroupCommand(app)
+	rootCmd.AddCommand(syncAttributeGroupCmd)
+
+	syncAssociationTypeCmd := createSyncAssociationTypeCommand(app)
+	rootCmd.AddCommand(syncAssociationTypeCmd)
+
+	syncAllAssociationTypesCmd := createSyncAllAssociationTypesCommand(app)
+	rootCmd.AddCommand(syncAllAssociationTypesCmd)
+
+	syncCategoryCmd := createSyncCategoryCommand(app)
+	rootCmd.AddCommand(syncCategoryCmd)
+
+	syncCategoryTreeCmd := createSyncCategoryTreeCommand(app)
+	rootCmd.AddCommand(syncCategoryTreeCmd)
+
+	syncFamilyCmd := createSyncFamilyCommand(app)
+	rootCmd.AddCommand(syncFamilyCmd)
+
+	syncAllFamiliesCmd := createSyncAllFamiliesCommand(app)
+	rootCmd.AddCommand(syncAllFamiliesCmd)
+
+	syncChannelCmd := createSyncChannelCommand(app)
+	rootCmd.AddCommand(syncChannelCmd)
+
+	syncCurrenciesCmd := createSyncCurrenciesCommand(app)
+	rootCmd.AddCommand(syncCurrenciesCmd)
+
+	syncMeasurementFamiliesCmd := createSyncMeasurementFamiliesCommand(app)
+	rootCmd.AddCommand(syncMeasurementFamiliesCmd)
+
+	syncStructureCmd := createSyncStructureCommand(app)
+	rootCmd.AddCommand(syncStructureCmd)
+
+	migrateCmd := createMigrateCommand(app)
+	rootCmd.AddCommand(migrateCmd)
+
+	syncUpdatedProductsCmd := createSyncUpdatedProductsCommand(app)
+	rootCmd.AddCommand(syncUpdatedProductsCmd)
+
+	syncProductsBySearchCmd := createSyncProductsBySearchCommand(app)
+	rootCmd.AddCommand(syncProductsBySearchCmd)
+
+	syncProductsFromFileCmd := createSyncProductsFromFileCommand(app)
+	rootCmd.AddCommand(syncProductsFromFileCmd)
+
+	syncPublishedProductsCmd := createSyncPublishedProductsCommand(app)
+	rootCmd.AddCommand(syncPublishedProductsCmd)
+
+	verifyCmd := createVerifyCommand(app)
+	rootCmd.AddCommand(verifyCmd)
+
+	listReferenceEntitiesCmd := createListReferenceEntitiesCommand(app)
+	rootCmd.AddCommand(listReferenceEntitiesCmd)
+
+	statsCmd := createStatsCommand(app)
+	rootCmd.AddCommand(statsCmd)
+
+	runPairsCmd := createRunPairsCommand()
+	rootCmd.AddCommand(runPairsCmd)
+
+	webCmd := createWebCommand(app)
+	rootCmd.AddCommand(webCmd)
+
+	mockServerCmd := createMockServerCommand()
+	rootCmd.AddCommand(mockServerCmd)
+
+	retryFailedCmd := createRetryFailedCommand(app)
+	rootCmd.AddCommand(retryFailedCmd)
+
+	planCmd := createPlanCommand(app, rootCmd)
+	rootCmd.AddCommand(planCmd)
+
+	applyCmd := createApplyCommand(app)
+	rootCmd.AddCommand(applyCmd)
+
+	// 4. Execute root command; an interrupt cancels the in-flight Akeneo requests
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// The session is finished whether the command succeeded or not, so failed runs are reported too
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	app.finishSession(cmd)
+
+	return app.exitCode(err)
+}
+
+// finishSession prints the combined summary when several sync steps ran in this invocation
+// and writes the machine-readable session report when --report is set
+func (app *Application) finishSession(cmd *cobra.Command) {
+	app.Session.Finish()
+	if app.Progress != nil {
+		app.Progress.Finish()
+	}
+
+	if app.Session.Len() > 1 {
+		app.Session.Print(os.Stdout)
+	}
+
+	printScrubbed(app.Anonymizer)
+
+	for _, instance := range []string{"source", "destination"} {
+		if collector, ok := app.Metrics[instance]; ok {
+			printClientMetrics(instance, collector)
+		}
+	}
+
+	if reportPath, _ := cmd.Flags().GetString("report"); reportPath != "" { //nolint:errcheck // flag is optional
+		if err := app.Session.WriteJSON(reportPath); err != nil {
+			log.Printf("❌ %v\n", err)
+		} else {
+			fmt.Printf("🧾 Session report written to %s\n", reportPath)
+		}
+	}
+
+	if app.Output != nil {
+		if err := app.Session.Encode(app.Output); err != nil {
+			log.Printf("❌ %v\n", err)
+		}
+	}
+}
+
+// setupOutput checks the --output format. In JSON mode, stdout is kept for the session report and
+// every message printed during the run goes to stderr instead, so scripts can parse stdout as is.
+func (app *Application) setupOutput(cmd *cobra.Command, args []string) error {
+	// Arguments and flags are valid once here, so the errors of the run do not print the usage
+	cmd.SilenceUsage = true
+
+	output, _ := cmd.Flags().GetString("output") //nolint:errcheck // flag has default value
+	switch output {
+	case OutputText:
+	case OutputJSON:
+		app.Output = os.Stdout
+		os.Stdout = os.Stderr
+	default:
+		return fmt.Errorf("invalid --output %q, expected %s or %s", output, OutputText, OutputJSON)
+	}
+
+	return app.setupLogger(cmd)
+}
+
+// printScrubbed prints the number of values anonymized per attribute during the run
+func printScrubbed(anonymizer *anonymize.Anonymizer) {
+	scrubbed := anonymizer.Scrubbed()
+	if len(scrubbed) == 0 {
+		return
+	}
+
+	total := 0
+	fmt.Println("\n🕶️  Anonymized values:")
+	for _, item := range scrubbed {
+		fmt.Printf("   - %s (%s): %d\n", item.Attribute, item.Action, item.Values)
+		total += item.Values
+	}
+	fmt.Printf("   Total: %d\n", total)
+}
+
+// maxMetricsEndpoints is the number of endpoints printed per instance by --metrics
+const maxMetricsEndpoints = 10
+
+// printClientMetrics prints the endpoints where the calls to an instance spent the most time
+func printClientMetrics(instance string, collector *akeneo.MetricsCollector) {
+	endpoints := collector.Snapshot()
+	if len(endpoints) == 0 {
+		return
+	}
+
+	fmt.Printf("\n📈 API calls to the %s instance (by total time):\n", instance)
+	fmt.Printf("   %-6s %-60s %8s %7s %7s %9s %9s %10s\n", "METHOD", "ENDPOINT", "CALLS", "ERRORS", "RETRIES", "AVG", "MAX", "RECEIVED")
+	for i, metrics := range endpoints {
+		if i == maxMetricsEndpoints {
+			fmt.Printf("   ... %d more endpoints\n", len(endpoints)-maxMetricsEndpoints)
+			break
+		}
+
+		failed := 0
+		for status, count := range metrics.Statuses {
+			if status == 0 || status >= http.StatusBadRequest {
+				failed += count
+			}
+		}
+
+		fmt.Printf("   %-6s %-60s %8d %7d %7d %9s %9s %9dK\n",
+			metrics.Method, metrics.Endpoint, metrics.Requests, failed, metrics.Retries,
+			metrics.AverageDuration().Round(time.Millisecond), metrics.MaxDuration.Round(time.Millisecond), metrics.BytesReceived/1024)
+	}
+}
+
+// recordingTransport returns a cassette recorder for an instance when AKENEO_RECORD_DIR is set
+func recordingTransport(instance string) http.RoundTripper {
+	dir := os.Getenv(RecordDirEnvVar)
+	if dir == "" {
+		return nil
+	}
+
+	path := filepath.Join(dir, instance+".json")
+	fmt.Printf("📼 Recording %s API calls to %s\n", instance, path)
+	return cassette.NewRecorder(path, nil)
+}
+
+// pruneListLimit is the number of codes listed before asking to confirm a prune
+const pruneListLimit = 20
+
+// confirmPrune lists the destination items a prune would delete and asks for confirmation on the terminal,
+// unless assumeYes is set
+func confirmPrune(assumeYes bool) prune.Confirm {
+	return func(ctx context.Context, kind, scope string, codes []string) bool {
+		fmt.Printf("\n🗑️  %d %s(s) of '%s' exist in destination but not in source:\n", len(codes), kind, scope)
+		for i, code := range codes {
+			if i == pruneListLimit {
+				fmt.Printf("   ... and %d more\n", len(codes)-pruneListLimit)
+				break
+			}
+			fmt.Printf("   - %s\n", code)
+		}
+
+		if assumeYes {
+			return true
+		}
+
+		fmt.Print("Delete them from destination? [y/N] ")
+		answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && answer == "" {
+			return false
+		}
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		return answer == "y" || answer == "yes"
+	}
+}
+
+// initialize loads the configuration and wires clients, repositories, services and handlers.
+// It is used as PreRunE by every command that talks to the Akeneo instances.
+func (app *Application) initialize(cmd *cobra.Command, args []string) error {
+	// Syncs and the client read the dry-run mode from the context of the command
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun { //nolint:errcheck // flag is optional
+		cmd.SetContext(dryrun.With(cmd.Context()))
+	}
+
+	// Syncs report their progress through the context; long ones print it with their rate and ETA, unless -q is set
+	if app.Logger.Enabled(logger.Info) {
+		if app.Progress == nil {
+			app.Progress = progress.NewBar(os.Stdout, progressInterval)
+		}
+		cmd.SetContext(progress.With(cmd.Context(), app.Progress))
+	}
+
+	if app.CommandBus != nil {
+		return nil
+	}
+
 	// 1. Load configuration with Viper
 	viperConfig := viper.NewViperConfig()
 	err := viperConfig.LoadConfiguration(CONTEXT)
@@ -45,573 +448,4053 @@ func Run() error {
 	}
 
 	// 2. Create configuration
-	cfg, err := config.LoadConfig(viperConfig)
+	cfg, err := config.LoadConfig(viperConfig, connectionOverrides(cmd))
 	if err != nil {
 		return fmt.Errorf("error creating configuration: %w", err)
 	}
 
-	// 3. Create source client
+	// 3. Create source client; every request carries the run ID so the API logs can be matched to this run
+	if runID, _ := cmd.Flags().GetString("run-id"); runID != "" { //nolint:errcheck // flag is optional
+		app.Session.ID = runID
+	}
+	fmt.Printf("🔖 Run ID: %s\n", app.Session.ID)
 	sourceClient, err := akeneo.NewClient(akeneo.ClientConfig{
-		Host:     cfg.Source.Host,
-		ClientID: cfg.Source.ClientID,
-		Secret:   cfg.Source.Secret,
-		Username: cfg.Source.Username,
-		Password: cfg.Source.Password,
-	})
+		Host:        cfg.Source.Host,
+		ClientID:    cfg.Source.ClientID,
+		Secret:      cfg.Source.Secret,
+		Username:    cfg.Source.Username,
+		Password:    cfg.Source.Password,
+		AccessToken: cfg.Source.AccessToken,
+		Transport:   recordingTransport("source"),
+	}, append(app.clientOptions(cmd, cfg.AkeneoSource.API.TLS, "source"), sourceMappings(cfg.Mappings)...)...)
 	if err != nil {
 		return fmt.Errorf("error creating source client: %w", err)
 	}
 
 	// 4. Create destination client
 	destClient, err := akeneo.NewClient(akeneo.ClientConfig{
-		Host:     cfg.Dest.Host,
-		ClientID: cfg.Dest.ClientID,
-		Secret:   cfg.Dest.Secret,
-		Username: cfg.Dest.Username,
-		Password: cfg.Dest.Password,
-	})
+		Host:        cfg.Dest.Host,
+		ClientID:    cfg.Dest.ClientID,
+		Secret:      cfg.Dest.Secret,
+		Username:    cfg.Dest.Username,
+		Password:    cfg.Dest.Password,
+		AccessToken: cfg.Dest.AccessToken,
+		Transport:   recordingTransport("dest"),
+	}, app.clientOptions(cmd, cfg.AkeneoDest.API.TLS, "destination")...)
 	if err != nil {
 		return fmt.Errorf("error creating destination client: %w", err)
 	}
 
-	// 5. Create repositories
+	// 5. Detect the edition and version of both instances, then check they are compatible
+	detectInstance(cmd.Context(), sourceClient, &cfg.AkeneoSource.API, "source")
+	detectInstance(cmd.Context(), destClient, &cfg.AkeneoDest.API, "destination")
+	if err := cfg.CheckCompatibility(); err != nil {
+		return err
+	}
+
+	// 6. Create repositories
 	sourceRepository := akeneo_storage.NewSourceReferenceEntityRepository(sourceClient)
 	destRepository := akeneo_storage.NewDestReferenceEntityRepository(destClient)
+	sourceAssetRepo := akeneo_storage.NewSourceAssetRepository(sourceClient)
+	destAssetRepo := akeneo_storage.NewDestAssetRepository(destClient)
 	sourceProductRepo := akeneo_storage.NewSourceProductRepository(sourceClient)
 	destProductRepo := akeneo_storage.NewDestProductRepository(destClient)
+	sourcePublishedProductRepo := akeneo_storage.NewPublishedProductRepository(sourceClient)
+	destPublishedProductRepo := akeneo_storage.NewPublishedProductRepository(destClient)
 	sourceAttributeRepo := akeneo_storage.NewSourceAttributeRepository(sourceClient)
 	destAttributeRepo := akeneo_storage.NewDestAttributeRepository(destClient)
+	sourceAttributeGroupRepo := akeneo_storage.NewSourceAttributeGroupRepository(sourceClient)
+	destAttributeGroupRepo := akeneo_storage.NewDestAttributeGroupRepository(destClient)
+	sourceAssociationTypeRepo := akeneo_storage.NewSourceAssociationTypeRepository(sourceClient)
+	destAssociationTypeRepo := akeneo_storage.NewDestAssociationTypeRepository(destClient)
 	sourceCategoryRepo := akeneo_storage.NewSourceCategoryRepository(sourceClient)
 	destCategoryRepo := akeneo_storage.NewDestCategoryRepository(destClient)
 	sourceFamilyRepo := akeneo_storage.NewSourceFamilyRepository(sourceClient)
 	destFamilyRepo := akeneo_storage.NewDestFamilyRepository(destClient)
+	sourceChannelRepo := akeneo_storage.NewSourceChannelRepository(sourceClient)
+	destChannelRepo := akeneo_storage.NewDestChannelRepository(destClient)
+	sourceCurrencyRepo := akeneo_storage.NewSourceCurrencyRepository(sourceClient)
+	destCurrencyRepo := akeneo_storage.NewDestCurrencyRepository(destClient)
+	sourceMeasurementFamilyRepo := akeneo_storage.NewSourceMeasurementFamilyRepository(sourceClient)
+	destMeasurementFamilyRepo := akeneo_storage.NewDestMeasurementFamilyRepository(destClient)
+	sourceStructureRepo := akeneo_storage.NewStructureRepository(sourceClient)
+	destStructureRepo := akeneo_storage.NewStructureRepository(destClient)
+	jobRepo := file_storage.NewJobRepository(cfg.State.JobsDir())
+	planRepo := file_storage.NewPlanRepository()
+	manifestRepo := file_storage.NewManifestRepository()
+	identifierListRepo := file_storage.NewIdentifierListRepository()
+
+	// 7. Create services
+	labelStrategy, err := labels.ParseStrategy(cfg.Sync.LabelMerge)
+	if err != nil {
+		return err
+	}
+
+	productFieldStrategies, err := product_syncing.ParseFieldStrategies(cfg.Sync.ProductFields)
+	if err != nil {
+		return err
+	}
+
+	anonymizer, err := cfg.Anonymize.Anonymizer()
+	if err != nil {
+		return err
+	}
+	app.Anonymizer = anonymizer
+
+	transformer, err := cfg.Transform.Transformer()
+	if err != nil {
+		return err
+	}
+
+	localePolicy, err := locales.ParsePolicy(cfg.Sync.DisabledLocales)
+	if err != nil {
+		return err
+	}
+	// Destination locales are only fetched when values are written
+	localeChecker := locales.NewChecker(destChannelRepo.FindLocales, localePolicy)
+
+	// --locales and --channels replace the lists of the filter configuration
+	if filterLocales, _ := cmd.Flags().GetStringSlice("locales"); len(filterLocales) > 0 { //nolint:errcheck // flag is optional
+		cfg.Filter.Locales = filterLocales
+	}
+	if filterChannels, _ := cmd.Flags().GetStringSlice("channels"); len(filterChannels) > 0 { //nolint:errcheck // flag is optional
+		cfg.Filter.Channels = filterChannels
+	}
+	valueFilter, err := cfg.Filter.ValueFilter()
+	if err != nil {
+		return fmt.Errorf("invalid --channels: %w", err)
+	}
+	// Product sync commands replace the attribute lists with --include-attributes and --exclude-attributes
+	if included, _ := cmd.Flags().GetStringSlice("include-attributes"); len(included) > 0 { //nolint:errcheck // flag is optional
+		cfg.Filter.IncludeAttributes = included
+	}
+	if excluded, _ := cmd.Flags().GetStringSlice("exclude-attributes"); len(excluded) > 0 { //nolint:errcheck // flag is optional
+		cfg.Filter.ExcludeAttributes = excluded
+	}
+	productValueFilter, err := cfg.Filter.ProductValueFilter()
+	if err != nil {
+		return err
+	}
+
+	// --workers replaces the number of concurrent writes of the configuration
+	if workers, _ := cmd.Flags().GetInt("workers"); workers > 0 { //nolint:errcheck // flag is optional
+		cfg.Sync.Workers = workers
+	}
+
+	missingTargets := cfg.Sync.MissingTargets
+	if missingTargets == "" && cfg.Sync.AutoDeps {
+		missingTargets = string(product_syncing.TargetSync)
+	}
+	targetPolicy, err := product_syncing.ParseTargetPolicy(missingTargets)
+	if err != nil {
+		return err
+	}
+
+	// Commands override the default conflict strategy with --on-conflict
+	conflictStrategy, err := conflict.ParseStrategy(cfg.Sync.Conflicts)
+	if err != nil {
+		return err
+	}
+	conflictDetector := conflict.NewDetector(file_storage.NewBaselineRepository(cfg.State.BaselinesDir(cfg.Dest.Host)), askConflict())
+
+	productOptions := []product_syncing.Option{
+		product_syncing.WithFieldStrategies(productFieldStrategies),
+		product_syncing.WithConflictDetection(conflictDetector, conflictStrategy),
+		product_syncing.WithQuantifiedTargets(targetPolicy),
+		product_syncing.WithTransformer(transformer),
+		product_syncing.WithAnonymizer(anonymizer),
+		product_syncing.WithValueFilter(productValueFilter),
+		product_syncing.WithLocaleChecker(localeChecker),
+		product_syncing.WithWorkers(cfg.Sync.Workers),
+		product_syncing.WithLogger(app.Logger),
+	}
+
+	// Product sync commands strip the values of attributes missing in destination with --drop-missing-attributes
+	if dropMissing, _ := cmd.Flags().GetBool("drop-missing-attributes"); dropMissing { //nolint:errcheck // flag is optional
+		cfg.Sync.MissingAttributes = "drop"
+	}
+	if cfg.Sync.MissingAttributes == "drop" {
+		// Destination attributes are only fetched when values are written
+		productOptions = append(productOptions, product_syncing.WithAttributeChecker(attributes.NewChecker(destAttributeRepo.FindCodes)))
+	}
+
+	// Product sync commands create the options destination reports missing with --create-missing-options
+	if createOptions, _ := cmd.Flags().GetBool("create-missing-options"); createOptions { //nolint:errcheck // flag is optional
+		cfg.Sync.MissingOptions = "create"
+	}
+	if cfg.Sync.MissingOptions == "create" {
+		optionCreator := attribute_syncing.NewService(sourceAttributeRepo, destAttributeRepo,
+			attribute_syncing.WithLabelStrategy(labelStrategy),
+			attribute_syncing.WithAttributeMap(cfg.Mappings.AttributeMap()),
+		)
+		productOptions = append(productOptions, product_syncing.WithMissingOptions(optionCreator))
+	}
+
+	// Product sync commands leave image and file values out with --skip-media
+	if skipMedia, _ := cmd.Flags().GetBool("skip-media"); skipMedia { //nolint:errcheck // flag is optional
+		cfg.Sync.Media = "skip"
+	}
+	if cfg.Sync.Media == "skip" {
+		productOptions = append(productOptions, product_syncing.WithoutMedia())
+	}
+	if cfg.Sync.MediaRetries > 0 {
+		productOptions = append(productOptions, product_syncing.WithMediaRetries(cfg.Sync.MediaRetries, product_syncing.DefaultMediaRetryDelay))
+	}
+
+	// Product sync commands check the products and models linked by associations with --missing-associations
+	if missingAssociations, _ := cmd.Flags().GetString("missing-associations"); missingAssociations != "" { //nolint:errcheck // flag is optional
+		cfg.Sync.MissingAssociations = missingAssociations
+	}
+	associationPolicy, err := product_syncing.ParseAssociationPolicy(cfg.Sync.MissingAssociations)
+	if err != nil {
+		return err
+	}
+	if associationPolicy != "" {
+		productOptions = append(productOptions, product_syncing.WithAssociationTargets(associationPolicy, cfg.Sync.AssociationDepth))
+	}
+
+	// Product sync commands only send what differs from destination with --changed-only
+	if changedOnly, _ := cmd.Flags().GetBool("changed-only"); changedOnly { //nolint:errcheck // flag is optional
+		cfg.Sync.ChangedOnly = true
+	}
+	if cfg.Sync.ChangedOnly {
+		productOptions = append(productOptions, product_syncing.WithChangedOnly())
+	}
 
-	// 6. Create services
-	referenceEntitySyncer := syncing.NewService(sourceRepository, destRepository)
-	productSyncer := product_syncing.NewService(sourceProductRepo, destProductRepo)
-	productSinceSyncer := product_syncing_since.NewService(sourceProductRepo, destProductRepo)
-	attributeSyncer := attribute_syncing.NewService(sourceAttributeRepo, destAttributeRepo)
-	categorySyncer := category_syncing.NewService(sourceCategoryRepo, destCategoryRepo)
-	familySyncer := family_syncing.NewService(sourceFamilyRepo, destFamilyRepo)
+	associationTypeSyncer := association_type_syncing.NewService(sourceAssociationTypeRepo, destAssociationTypeRepo)
+	allAssociationTypesSyncer := association_type_syncing_all.NewService(sourceAssociationTypeRepo, destAssociationTypeRepo)
+	if cfg.Sync.AutoDeps {
+		// Association types missing in destination are created before the products using them
+		productOptions = append(productOptions, product_syncing.WithAssociationTypes(associationTypeSyncer))
+	}
+	// Products and models whose family variant has other axes or levels in destination fail before being written
+	productOptions = append(productOptions, product_syncing.WithVariantChecker(
+		family_syncing.NewService(sourceFamilyRepo, destFamilyRepo, family_syncing.WithFamilyMap(cfg.Mappings.FamilyMap())),
+	))
+	if cfg.AkeneoDest.API.ProductUUIDs() {
+		// Akeneo 7 and SaaS destinations key products by UUID
+		productOptions = append(productOptions, product_syncing.WithProductUUIDs(destProductRepo))
+	}
+
+	referenceEntityOptions := []syncing.Option{
+		syncing.WithLabelStrategy(labelStrategy),
+		syncing.WithTransformer(transformer.ForRecords()),
+		syncing.WithAnonymizer(anonymizer),
+		syncing.WithValueFilter(valueFilter),
+		syncing.WithLocaleChecker(localeChecker),
+		syncing.WithWorkers(cfg.Sync.Workers),
+		syncing.WithLogger(app.Logger),
+	}
+
+	// Prunes list the items they would delete and ask before deleting them, unless --yes is set
+	assumeYes, _ := cmd.Flags().GetBool("yes") //nolint:errcheck // flag is optional
+	referenceEntitySyncer := syncing.NewService(sourceRepository, destRepository,
+		append(referenceEntityOptions, syncing.WithPruneConfirmation(confirmPrune(assumeYes)))...)
+	recordSyncer := reference_entity_syncing_record.NewService(sourceRepository, destRepository, referenceEntityOptions...)
+	assetSyncer := asset_syncing.NewService(sourceAssetRepo, destAssetRepo,
+		asset_syncing.WithPruneConfirmation(confirmPrune(assumeYes)),
+		asset_syncing.WithWorkers(cfg.Sync.Workers),
+		asset_syncing.WithLogger(app.Logger),
+	)
+	assetItemSyncer := asset_syncing_asset.NewService(sourceAssetRepo, destAssetRepo, asset_syncing.WithLogger(app.Logger))
+	productSyncer := product_syncing.NewService(sourceProductRepo, destProductRepo, productOptions...)
+	productSinceSyncer := product_syncing_since.NewService(
+		sourceProductRepo,
+		destProductRepo,
+		file_storage.NewRunRepository(cfg.State.RunsFile(cfg.Source.Host, cfg.Dest.Host)),
+		productOptions...,
+	)
+	productSearchSyncer := product_syncing_search.NewService(sourceProductRepo, destProductRepo, productOptions...)
+	productFileSyncer := product_syncing_file.NewService(identifierListRepo, sourceProductRepo, destProductRepo, productOptions...)
+	publishedProductSyncer := product_syncing_published.NewService(
+		sourceProductRepo,
+		destProductRepo,
+		sourcePublishedProductRepo,
+		destPublishedProductRepo,
+		productOptions...,
+	)
+	productModelSyncer := product_syncing_model.NewService(sourceProductRepo, destProductRepo, productOptions...)
+	productValidator := product_validating.NewService(
+		identifierListRepo,
+		sourceProductRepo,
+		destStructureRepo,
+		product_validating.WithAttributeComparison(sourceStructureRepo),
+		product_validating.WithValueFilter(productValueFilter),
+	)
+	attributeOptions := []attribute_syncing.Option{
+		attribute_syncing.WithLabelStrategy(labelStrategy),
+		attribute_syncing.WithAttributeMap(cfg.Mappings.AttributeMap()),
+	}
+	attributeGroupSyncer := attribute_group_syncing.NewService(sourceAttributeGroupRepo, destAttributeGroupRepo)
+	// Groups are written without their attribute list, which attributes fill as they are synced
+	syncAttributeGroup := func(ctx context.Context, code string) error {
+		_, err := attributeGroupSyncer.Sync(ctx, code, attribute_group_syncing.SyncOptions{})
+		return err
+	}
+	attributeSyncer := attribute_syncing.NewService(sourceAttributeRepo, destAttributeRepo,
+		append(attributeOptions, attribute_syncing.WithGroupSync(syncAttributeGroup))...)
+	allAttributesSyncer := attribute_syncing_all.NewService(sourceAttributeRepo, destAttributeRepo, syncAttributeGroup, attributeOptions...)
+	categorySyncer := category_syncing.NewService(
+		sourceCategoryRepo,
+		destCategoryRepo,
+		category_syncing.WithMovePolicy(category_syncing.MovePolicy(cfg.Sync.CategoryMove)),
+	)
+	categoryTreeSyncer := category_syncing_tree.NewService(
+		sourceCategoryRepo,
+		destCategoryRepo,
+		category_syncing.WithMovePolicy(category_syncing.MovePolicy(cfg.Sync.CategoryMove)),
+	)
+	familyMap := family_syncing.WithFamilyMap(cfg.Mappings.FamilyMap())
+	familySyncer := family_syncing.NewService(sourceFamilyRepo, destFamilyRepo, familyMap)
+	allFamiliesSyncer := family_syncing_all.NewService(sourceFamilyRepo, destFamilyRepo, familyMap)
+	channelSyncer := channel_syncing.NewService(
+		sourceChannelRepo,
+		destChannelRepo,
+		channel_syncing.WithCategoryMap(cfg.Mappings.CategoryMap()),
+		channel_syncing.WithChannelMap(cfg.Mappings.ChannelMap()),
+		channel_syncing.WithCategoryTrees(categoryTreeSyncer),
+	)
+	currencySyncer := currency_syncing.NewService(sourceCurrencyRepo, destCurrencyRepo)
+	measurementFamilySyncer := measurement_family_syncing.NewService(sourceMeasurementFamilyRepo, destMeasurementFamilyRepo)
+	referenceEntityVerifier := reference_entity_verifying.NewService(sourceRepository, destRepository)
+	referenceEntityLister := reference_entity_listing.NewService(sourceRepository, destRepository)
+	counter := stats_counting.NewService(akeneo_storage.NewCountRepository(sourceClient), akeneo_storage.NewCountRepository(destClient))
+	categoryVerifier := category_verifying.NewService(sourceCategoryRepo, destCategoryRepo)
+	familyVerifier := family_verifying.NewService(sourceFamilyRepo, destFamilyRepo)
 
-	// 7. Create command bus with middlewares
+	// 8. Create command bus with middlewares; failures can be written to a manifest as well as queued
+	recordFailures := job.Recorder(jobRepo)
+	if manifestPath, _ := cmd.Flags().GetString("failure-manifest"); manifestPath != "" { //nolint:errcheck // flag is optional
+		recordFailures = job.ManifestRecorder(recordFailures, manifestRepo, manifestPath, app.Logger)
+	}
 	commandBus := inmemory.NewCommandBus(
-		middleware.Logging(),
+		middleware.Logging(app.Logger),
+		middleware.DryRun(app.Logger),
+		middleware.Session(app.Session),
+		middleware.FailureQueue(recordFailures, app.Logger),
 	)
+	structureSyncer := structure_syncing.NewService(commandBus, structureSteps(
+		cfg,
+		sourceRepository,
+		sourceCategoryRepo,
+		sourceChannelRepo,
+		sourceAssociationTypeRepo,
+	)...)
+	migrator := migration_migrating.NewService(
+		commandBus,
+		file_storage.NewCheckpointRepository(cfg.State.CheckpointFile(cfg.Dest.Host)),
+		migrationPhases(cfg, sourceAssetRepo)...,
+	)
+	failedItemsRetrier := retrying.NewService(jobRepo, commandBus, append(retryBuilders(cfg), retrying.WithManifests(manifestRepo))...)
+	productDependencySyncer := product_syncing_dependencies.NewService(
+		productValidator,
+		sourceStructureRepo,
+		destStructureRepo,
+		commandBus,
+		productDependencyBuilders()...,
+	)
+	planApplier := applying.NewService(planRepo, append(
+		planWriters(
+			productSyncer,
+			destRepository,
+			destAssetRepo,
+			destAttributeRepo,
+			destAttributeGroupRepo,
+			destAssociationTypeRepo,
+			destCategoryRepo,
+			destFamilyRepo,
+			destChannelRepo,
+			destMeasurementFamilyRepo,
+		),
+		// A plan is only applied to the destination it was computed for
+		applying.WithDestination(cfg.Dest.Host),
+	)...)
 
-	// 8. Register command handlers
+	// 9. Register command handlers
 	commandBus.Register(
 		syncing.SyncReferenceEntityCommandType,
 		syncing.NewCommandHandler(referenceEntitySyncer),
 	)
+	commandBus.Register(
+		reference_entity_syncing_record.SyncRecordCommandType,
+		reference_entity_syncing_record.NewCommandHandler(recordSyncer),
+	)
+	commandBus.Register(
+		asset_syncing.SyncAssetFamilyCommandType,
+		asset_syncing.NewCommandHandler(assetSyncer),
+	)
+	commandBus.Register(
+		asset_syncing_asset.SyncAssetsCommandType,
+		asset_syncing_asset.NewCommandHandler(assetItemSyncer),
+	)
 	commandBus.Register(
 		product_syncing.SyncProductCommandType,
 		product_syncing.NewCommandHandler(productSyncer),
 	)
+	commandBus.Register(
+		product_syncing_model.SyncProductModelCommandType,
+		product_syncing_model.NewCommandHandler(productModelSyncer),
+	)
+	commandBus.Register(
+		product_validating.ValidateProductsCommandType,
+		product_validating.NewCommandHandler(productValidator),
+	)
+	commandBus.Register(
+		product_syncing_dependencies.SyncDependenciesCommandType,
+		product_syncing_dependencies.NewCommandHandler(productDependencySyncer),
+	)
 	commandBus.Register(
 		product_syncing_since.SyncProductsSinceCommandType,
 		product_syncing_since.NewCommandHandler(productSinceSyncer),
 	)
+	commandBus.Register(
+		product_syncing_search.SyncProductsBySearchCommandType,
+		product_syncing_search.NewCommandHandler(productSearchSyncer),
+	)
+	commandBus.Register(
+		product_syncing_file.SyncProductsFromFileCommandType,
+		product_syncing_file.NewCommandHandler(productFileSyncer),
+	)
+	commandBus.Register(
+		product_syncing_published.SyncPublishedProductsCommandType,
+		product_syncing_published.NewCommandHandler(publishedProductSyncer),
+	)
 	commandBus.Register(
 		attribute_syncing.SyncAttributeCommandType,
 		attribute_syncing.NewCommandHandler(attributeSyncer),
 	)
+	commandBus.Register(
+		attribute_syncing_all.SyncAllAttributesCommandType,
+		attribute_syncing_all.NewCommandHandler(allAttributesSyncer),
+	)
+	commandBus.Register(
+		attribute_group_syncing.SyncAttributeGroupCommandType,
+		attribute_group_syncing.NewCommandHandler(attributeGroupSyncer),
+	)
 	commandBus.Register(
 		category_syncing.SyncCategoryCommandType,
 		category_syncing.NewCommandHandler(categorySyncer),
 	)
+	commandBus.Register(
+		category_syncing_tree.SyncCategoryTreeCommandType,
+		category_syncing_tree.NewCommandHandler(categoryTreeSyncer),
+	)
 	commandBus.Register(
 		family_syncing.SyncFamilyCommandType,
 		family_syncing.NewCommandHandler(familySyncer),
 	)
+	commandBus.Register(
+		family_syncing_all.SyncAllFamiliesCommandType,
+		family_syncing_all.NewCommandHandler(allFamiliesSyncer),
+	)
+	commandBus.Register(
+		channel_syncing.SyncChannelCommandType,
+		channel_syncing.NewCommandHandler(channelSyncer),
+	)
+	commandBus.Register(
+		association_type_syncing.SyncAssociationTypeCommandType,
+		association_type_syncing.NewCommandHandler(associationTypeSyncer),
+	)
+	commandBus.Register(
+		association_type_syncing_all.SyncAllAssociationTypesCommandType,
+		association_type_syncing_all.NewCommandHandler(allAssociationTypesSyncer),
+	)
+	commandBus.Register(
+		currency_syncing.SyncCurrenciesCommandType,
+		currency_syncing.NewCommandHandler(currencySyncer),
+	)
+	commandBus.Register(
+		measurement_family_syncing.SyncMeasurementFamiliesCommandType,
+		measurement_family_syncing.NewCommandHandler(measurementFamilySyncer),
+	)
+	commandBus.Register(
+		structure_syncing.SyncStructureCommandType,
+		structure_syncing.NewCommandHandler(structureSyncer),
+	)
+	commandBus.Register(
+		migration_migrating.MigrateCommandType,
+		migration_migrating.NewCommandHandler(migrator),
+	)
+	commandBus.Register(
+		reference_entity_verifying.VerifyReferenceEntityCommandType,
+		reference_entity_verifying.NewCommandHandler(referenceEntityVerifier),
+	)
+	commandBus.Register(
+		reference_entity_listing.ListReferenceEntitiesCommandType,
+		reference_entity_listing.NewCommandHandler(referenceEntityLister),
+	)
+	commandBus.Register(
+		stats_counting.CountCommandType,
+		stats_counting.NewCommandHandler(counter),
+	)
+	commandBus.Register(
+		category_verifying.VerifyCategoryCommandType,
+		category_verifying.NewCommandHandler(categoryVerifier),
+	)
+	commandBus.Register(
+		family_verifying.VerifyFamilyCommandType,
+		family_verifying.NewCommandHandler(familyVerifier),
+	)
+	commandBus.Register(
+		retrying.RetryFailedCommandType,
+		retrying.NewCommandHandler(failedItemsRetrier),
+	)
+	commandBus.Register(
+		applying.ApplyPlanCommandType,
+		applying.NewCommandHandler(planApplier),
+	)
+
+	// 10. Expose dependencies to the commands
+	app.Config = cfg
+	app.CommandBus = commandBus
+	app.Jobs = failedItemsRetrier
+	app.Plans = planRepo
+
+	return nil
+}
+
+// clientOptions returns the options of the client of an instance
+func (app *Application) clientOptions(cmd *cobra.Command, tls config.TLSConfig, instance string) []akeneo.Option {
+	options := []akeneo.Option{
+		akeneo.WithTLSConfig(clientTLS(tls, instance)),
+		akeneo.WithCorrelationID(akeneo.DefaultCorrelationHeader, app.Session.ID),
+	}
+
+	// Payloads exchanged with the API are only printed with --debug or -v
+	clientLog := app.Logger
+	if debug, _ := cmd.Flags().GetBool("debug"); debug { //nolint:errcheck // not every command has the flag
+		clientLog = clientLog.AtLevel(logger.Debug)
+	}
+	options = append(options, akeneo.WithLogger(clientLogger{logger: clientLog}))
+
+	if enabled, _ := cmd.Flags().GetBool("metrics"); enabled { //nolint:errcheck // flag is optional
+		if app.Metrics == nil {
+			app.Metrics = make(map[string]*akeneo.MetricsCollector)
+		}
+		collector := akeneo.NewMetricsCollector()
+		app.Metrics[instance] = collector
+		options = append(options, akeneo.WithMetrics(collector))
+	}
+
+	return options
+}
+
+// sourceMappings returns the options of the source client renaming source codes in everything it reads,
+// so every service sees destination codes
+func sourceMappings(mappings config.MappingsConfig) []akeneo.Option {
+	var options []akeneo.Option
+	if localeMapper := locales.NewMapper(mappings.LocaleMap()); localeMapper.Enabled() {
+		options = append(options, akeneo.WithMiddlewares(akeneo.TransformResponses(localeMapper.Apply)))
+	}
+	if channelMapper := channels.NewMapper(mappings.ChannelMap()); channelMapper.Enabled() {
+		options = append(options, akeneo.WithMiddlewares(akeneo.TransformResponses(channelMapper.Apply)))
+	}
+	if attributeMapper := attributes.NewMapper(mappings.AttributeMap()); attributeMapper.Enabled() {
+		options = append(options, akeneo.WithMiddlewares(akeneo.TransformResponses(attributeMapper.Apply)))
+	}
+	if familyMapper := families.NewMapper(mappings.FamilyMap()); familyMapper.Enabled() {
+		options = append(options, akeneo.WithMiddlewares(akeneo.TransformResponses(familyMapper.Apply)))
+	}
+
+	return options
+}
+
+// clientTLS converts the TLS settings of an instance, warning when the server certificate is not verified
+func clientTLS(tls config.TLSConfig, instance string) akeneo.TLSConfig {
+	if tls.InsecureSkipVerify {
+		fmt.Printf("⚠️  TLS certificate verification is disabled for the %s instance\n", instance)
+	}
+
+	return akeneo.TLSConfig{
+		CAFile:             tls.CAFile,
+		CertFile:           tls.CertFile,
+		KeyFile:            tls.KeyFile,
+		InsecureSkipVerify: tls.InsecureSkipVerify,
+	}
+}
+
+// detectInstance fills the edition and version of an instance that are not configured
+// from its system information. Instances older than Akeneo 7 do not expose it.
+func detectInstance(ctx context.Context, client *akeneo.Client, api *config.APIConfig, instance string) {
+	if api.Version != "" && api.Edition != "" {
+		return
+	}
+
+	info, err := client.GetSystemInformation(ctx)
+	if err != nil {
+		fmt.Printf("ℹ️  Could not detect the %s edition and version, using the configuration: %v\n", instance, err)
+		return
+	}
+
+	if api.Version == "" {
+		api.Version = info.Version
+	}
+	if api.Edition == "" {
+		api.Edition = info.Edition
+	}
+}
+
+// requiring wraps initialize to fail early when an instance lacks a feature used by the command
+func (app *Application) requiring(feature config.Feature) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := app.initialize(cmd, args); err != nil {
+			return err
+		}
+		return app.Config.RequireFeature(feature)
+	}
+}
+
+// productDependencyBuilders describes how the structure product hierarchies miss in destination is
+// synced by --with-dependencies
+func productDependencyBuilders() []product_syncing_dependencies.Option {
+	return []product_syncing_dependencies.Option{
+		// Attributes bring their options, and their group when it is missing as well
+		product_syncing_dependencies.WithBuilder(product_validating.KindAttribute,
+			func(missing product_validating.Missing, opts product_syncing_dependencies.SyncOptions) bus.Message {
+				return attribute_syncing.SyncAttributeCommand{Code: missing.Code, WithGroup: true, Debug: opts.Debug}
+			}),
+		product_syncing_dependencies.WithBuilder(product_validating.KindCategory,
+			func(missing product_validating.Missing, opts product_syncing_dependencies.SyncOptions) bus.Message {
+				return category_syncing.SyncCategoryCommand{Code: missing.Code, Debug: opts.Debug}
+			}),
+		product_syncing_dependencies.WithBuilder(product_validating.KindFamily,
+			func(missing product_validating.Missing, opts product_syncing_dependencies.SyncOptions) bus.Message {
+				return family_syncing.SyncFamilyCommand{Code: missing.Code, Debug: opts.Debug}
+			}),
+		product_syncing_dependencies.WithBuilder(product_validating.KindFamilyVariant,
+			func(missing product_validating.Missing, opts product_syncing_dependencies.SyncOptions) bus.Message {
+				return family_syncing.SyncFamilyCommand{
+					Code:         missing.Scope,
+					VariantsOnly: true,
+					Variants:     []string{missing.Code},
+					Debug:        opts.Debug,
+				}
+			}),
+	}
+}
+
+// retryBuilders describes how the failed items of each kind are reprocessed by retry-failed
+func retryBuilders(cfg *config.Config) []retrying.Option {
+	each := func(build func(code string) bus.Message) retrying.Builder {
+		return func(scope string, codes []string) []bus.Message {
+			messages := make([]bus.Message, len(codes))
+			for i, code := range codes {
+				messages[i] = build(code)
+			}
+			return messages
+		}
+	}
+
+	// A product or model is synced with the hierarchy below it
+	syncProduct := each(func(code string) bus.Message {
+		return product_syncing.SyncProductCommand{Identifier: code}
+	})
+
+	return []retrying.Option{
+		retrying.WithBuilder(product_syncing.KindProduct, syncProduct),
+		retrying.WithBuilder(product_syncing.KindProductModel, syncProduct),
+		retrying.WithBuilder(syncing.KindRecord, func(scope string, codes []string) []bus.Message {
+			return []bus.Message{syncing.SyncReferenceEntityCommand{EntityName: scope, Records: codes}}
+		}),
+		retrying.WithBuilder(syncing.KindReferenceEntity, each(func(code string) bus.Message {
+			return syncing.SyncReferenceEntityCommand{EntityName: code}
+		})),
+		retrying.WithBuilder(asset_syncing.KindAsset, func(scope string, codes []string) []bus.Message {
+			return []bus.Message{asset_syncing_asset.SyncAssetsCommand{FamilyCode: scope, Codes: codes}}
+		}),
+		retrying.WithBuilder(asset_syncing.KindAssetFamily, each(func(code string) bus.Message {
+			return asset_syncing.SyncAssetFamilyCommand{FamilyCode: code}
+		})),
+		retrying.WithBuilder(attribute_syncing.KindAttribute, each(func(code string) bus.Message {
+			return attribute_syncing.SyncAttributeCommand{Code: code}
+		})),
+		retrying.WithBuilder(attribute_group_syncing.KindAttributeGroup, each(func(code string) bus.Message {
+			return attribute_group_syncing.SyncAttributeGroupCommand{Code: code}
+		})),
+		retrying.WithBuilder(association_type_syncing.KindAssociationType, each(func(code string) bus.Message {
+			return association_type_syncing.SyncAssociationTypeCommand{Code: code}
+		})),
+		retrying.WithBuilder(category_syncing.KindCategory, each(func(code string) bus.Message {
+			return category_syncing.SyncCategoryCommand{Code: code}
+		})),
+		retrying.WithBuilder(family_syncing.KindFamily, each(func(code string) bus.Message {
+			return family_syncing.SyncFamilyCommand{Code: code}
+		})),
+		retrying.WithBuilder(family_syncing.KindFamilyVariants, each(func(code string) bus.Message {
+			return family_syncing.SyncFamilyCommand{Code: code, VariantsOnly: true}
+		})),
+		retrying.WithBuilder(channel_syncing.KindChannel, each(func(code string) bus.Message {
+			return channel_syncing.SyncChannelCommand{Code: code, AutoDeps: cfg.Sync.AutoDeps}
+		})),
+		// Currencies are checked again all at once
+		retrying.WithBuilder(currency_syncing.KindCurrency, func(scope string, codes []string) []bus.Message {
+			return []bus.Message{currency_syncing.SyncCurrenciesCommand{}}
+		}),
+		// Measurement families are reconciled in one call
+		retrying.WithBuilder(measurement_family_syncing.KindMeasurementFamily, func(scope string, codes []string) []bus.Message {
+			return []bus.Message{measurement_family_syncing.SyncMeasurementFamiliesCommand{Codes: codes}}
+		}),
+	}
+}
+
+// migrationPhases describes the commands dispatched by each phase of migrate: the structure as
+// sync-structure does, every asset family of the source, then every product hierarchy. Assets are
+// skipped when an instance does not support the Asset Manager.
+func migrationPhases(cfg *config.Config, assets asset.SourceRepository) []migration_migrating.Option {
+	phases := []migration_migrating.Option{
+		migration_migrating.WithPhase(migration_migrating.PhaseStructure, func(ctx context.Context, opts migration_migrating.MigrateOptions) ([]bus.Message, error) {
+			step := 0
+			progress := func(s structure_syncing.Step, commands int) {
+				step++
+				fmt.Printf("   📦 Step %d/%d: %s (%d commands)\n", step, len(structure_syncing.Order), s, commands)
+			}
+			return []bus.Message{structure_syncing.SyncStructureCommand{AutoDeps: opts.AutoDeps, Progress: progress, Debug: opts.Debug}}, nil
+		}),
+		migration_migrating.WithPhase(migration_migrating.PhaseProducts, func(ctx context.Context, opts migration_migrating.MigrateOptions) ([]bus.Message, error) {
+			// Every item was updated after the epoch, so the whole catalog is synced hierarchy by hierarchy
+			return []bus.Message{product_syncing_since.SyncProductsSinceCommand{
+				UpdatedSince: "1970-01-01T00:00:00",
+				ValuesOnly:   opts.ValuesOnly,
+				Debug:        opts.Debug,
+			}}, nil
+		}),
+	}
+
+	if cfg.RequireFeature(config.FeatureAssetManager) == nil {
+		phases = append(phases, migration_migrating.WithPhase(migration_migrating.PhaseAssets, func(ctx context.Context, opts migration_migrating.MigrateOptions) ([]bus.Message, error) {
+			codes, err := assets.FindFamilyCodes(ctx)
+			if err != nil {
+				return nil, err
+			}
+			messages := make([]bus.Message, 0, len(codes))
+			for _, code := range codes {
+				messages = append(messages, asset_syncing.SyncAssetFamilyCommand{FamilyCode: code, Debug: opts.Debug})
+			}
+			return messages, nil
+		}))
+	}
+
+	return phases
+}
+
+// structureSteps describes the commands dispatched by each step of sync-structure. Items are listed
+// in source; reference entities are skipped when an instance does not support them.
+func structureSteps(
+	cfg *config.Config,
+	referenceEntities reference_entity.EntityRepository,
+	categories category.SourceRepository,
+	channels channel.SourceRepository,
+	associationTypes association_type.SourceRepository,
+) []structure_syncing.Option {
+	each := func(list func(ctx context.Context) ([]string, error), build func(code string, opts structure_syncing.SyncOptions) bus.Message) structure_syncing.Builder {
+		return func(ctx context.Context, opts structure_syncing.SyncOptions) ([]bus.Message, error) {
+			codes, err := list(ctx)
+			if err != nil {
+				return nil, err
+			}
+			messages := make([]bus.Message, 0, len(codes))
+			for _, code := range codes {
+				messages = append(messages, build(code, opts))
+			}
+			return messages, nil
+		}
+	}
+	one := func(build func(opts structure_syncing.SyncOptions) bus.Message) structure_syncing.Builder {
+		return func(ctx context.Context, opts structure_syncing.SyncOptions) ([]bus.Message, error) {
+			return []bus.Message{build(opts)}, nil
+		}
+	}
+
+	steps := []structure_syncing.Option{
+		structure_syncing.WithStep(structure_syncing.StepMeasurementFamilies, one(func(opts structure_syncing.SyncOptions) bus.Message {
+			return measurement_family_syncing.SyncMeasurementFamiliesCommand{Debug: opts.Debug}
+		})),
+		structure_syncing.WithStep(structure_syncing.StepAttributes, one(func(opts structure_syncing.SyncOptions) bus.Message {
+			return attribute_syncing_all.SyncAllAttributesCommand{Debug: opts.Debug}
+		})),
+		structure_syncing.WithStep(structure_syncing.StepCategoryTrees, each(categories.FindRootCodes, func(code string, opts structure_syncing.SyncOptions) bus.Message {
+			return category_syncing_tree.SyncCategoryTreeCommand{Root: code, Debug: opts.Debug}
+		})),
+		structure_syncing.WithStep(structure_syncing.StepChannels, each(channels.FindCodes, func(code string, opts structure_syncing.SyncOptions) bus.Message {
+			return channel_syncing.SyncChannelCommand{Code: code, AutoDeps: opts.AutoDeps}
+		})),
+		structure_syncing.WithStep(structure_syncing.StepFamilies, one(func(opts structure_syncing.SyncOptions) bus.Message {
+			return family_syncing_all.SyncAllFamiliesCommand{Debug: opts.Debug}
+		})),
+		structure_syncing.WithStep(structure_syncing.StepAssociationTypes, each(associationTypes.FindCodes, func(code string, opts structure_syncing.SyncOptions) bus.Message {
+			return association_type_syncing.SyncAssociationTypeCommand{Code: code, Debug: opts.Debug}
+		})),
+	}
+
+	if cfg.RequireFeature(config.FeatureReferenceEntities) == nil {
+		listEntities := func(ctx context.Context) ([]string, error) {
+			entities, err := referenceEntities.FindEntities(ctx)
+			if err != nil {
+				return nil, err
+			}
+			codes := make([]string, 0, len(entities))
+			for _, entity := range entities {
+				if code, ok := entity["code"].(string); ok {
+					codes = append(codes, code)
+				}
+			}
+			return codes, nil
+		}
+		steps = append(steps, structure_syncing.WithStep(structure_syncing.StepReferenceEntities, each(listEntities, func(code string, opts structure_syncing.SyncOptions) bus.Message {
+			return syncing.SyncReferenceEntityCommand{EntityName: code, Debug: opts.Debug}
+		})))
+	}
+
+	return steps
+}
+
+// planWriters describes how the planned writes of each kind are sent to destination by apply.
+// Payloads are sent as planned; media files are not part of the plan payloads and cannot be applied.
+func planWriters(
+	products *product_syncing.Service,
+	referenceEntities *akeneo_storage.DestReferenceEntityRepository,
+	assets asset.DestRepository,
+	attributes attribute.DestRepository,
+	attributeGroups attribute_group.DestRepository,
+	associationTypes association_type.DestRepository,
+	categories category.DestRepository,
+	families family.DestRepository,
+	channels channel.DestRepository,
+	measurementFamilies measurement_family.DestRepository,
+) []applying.Option {
+	// Writers receive the payload of the write, decoded from the plan file
+	write := func(send func(ctx context.Context, w dryrun.Write, data map[string]interface{}) error) applying.Writer {
+		return func(ctx context.Context, w dryrun.Write) error {
+			data, err := applying.Payload(w)
+			if err != nil {
+				return err
+			}
+			return send(ctx, w, data)
+		}
+	}
+
+	// Batch endpoints report the errors by code instead of failing
+	batchError := func(failed map[string]error, err error, code string) error {
+		if err != nil {
+			return err
+		}
+		return failed[code]
+	}
+
+	return []applying.Option{
+		applying.WithWriter(product_syncing.KindProduct, write(func(ctx context.Context, w dryrun.Write, data map[string]interface{}) error {
+			return products.WriteProduct(ctx, w.Code, data)
+		})),
+		applying.WithWriter(product_syncing.KindProductModel, write(func(ctx context.Context, w dryrun.Write, data map[string]interface{}) error {
+			return products.WriteModel(ctx, w.Code, data)
+		})),
+		applying.WithWriter(syncing.KindReferenceEntity, write(func(ctx context.Context, w dryrun.Write, data map[string]interface{}) error {
+			return referenceEntities.SaveEntity(ctx, w.Code, data)
+		})),
+		applying.WithWriter(syncing.KindAttribute, write(func(ctx context.Context, w dryrun.Write, data map[string]interface{}) error {
+			return referenceEntities.SaveAttribute(ctx, w.Scope, w.Code, data)
+		})),
+		applying.WithWriter(syncing.KindRecord, write(func(ctx context.Context, w dryrun.Write, data map[string]interface{}) error {
+			return referenceEntities.Save(ctx, w.Scope, w.Code, data)
+		})),
+		// Deletions carry no payload
+		applying.WithWriter(syncing.KindRecordDeletion, func(ctx context.Context, w dryrun.Write) error {
+			return referenceEntities.Delete(ctx, w.Scope, w.Code)
+		}),
+		applying.WithWriter(asset_syncing.KindAssetFamily, write(func(ctx context.Context, w dryrun.Write, data map[string]interface{}) error {
+			return assets.SaveFamily(ctx, w.Code, data)
+		})),
+		applying.WithWriter(asset_syncing.KindAttribute, write(func(ctx context.Context, w dryrun.Write, data map[string]interface{}) error {
+			return assets.SaveAttribute(ctx, w.Scope, w.Code, data)
+		})),
+		// Asset attribute options are planned as attribute/option
+		applying.WithWriter(asset_syncing.KindAttributeOption, write(func(ctx context.Context, w dryrun.Write, data map[string]interface{}) error {
+			attributeCode, optionCode, ok := strings.Cut(w.Code, "/")
+			if !ok {
+				return fmt.Errorf("invalid asset attribute option '%s'", w.Code)
+			}
+			return assets.SaveAttributeOption(ctx, w.Scope, attributeCode, optionCode, data)
+		})),
+		applying.WithWriter(asset_syncing.KindAsset, write(func(ctx context.Context, w dryrun.Write, data map[string]interface{}) error {
+			failed, err := assets.SaveAll(ctx, w.Scope, []asset.Asset{data})
+			return batchError(failed, err, w.Code)
+		})),
+		applying.WithWriter(asset_syncing.KindAssetDeletion, func(ctx context.Context, w dryrun.Write) error {
+			return assets.Delete(ctx, w.Scope, w.Code)
+		}),
+		applying.WithWriter(attribute_syncing.KindAttribute, write(func(ctx context.Context, w dryrun.Write, data map[string]interface{}) error {
+			return attributes.Save(ctx, w.Code, data)
+		})),
+		applying.WithWriter(attribute_syncing.KindAttributeOption, write(func(ctx context.Context, w dryrun.Write, data map[string]interface{}) error {
+			return attributes.SaveOption(ctx, w.Scope, w.Code, data)
+		})),
+		applying.WithWriter(attribute_group_syncing.KindAttributeGroup, write(func(ctx context.Context, w dryrun.Write, data map[string]interface{}) error {
+			return attributeGroups.Save(ctx, w.Code, data)
+		})),
+		applying.WithWriter(association_type_syncing.KindAssociationType, write(func(ctx context.Context, w dryrun.Write, data map[string]interface{}) error {
+			return associationTypes.Save(ctx, w.Code, data)
+		})),
+		applying.WithWriter(category_syncing.KindCategory, write(func(ctx context.Context, w dryrun.Write, data map[string]interface{}) error {
+			return categories.Save(ctx, w.Code, data)
+		})),
+		applying.WithWriter(family_syncing.KindFamily, write(func(ctx context.Context, w dryrun.Write, data map[string]interface{}) error {
+			return families.Save(ctx, w.Code, data)
+		})),
+		applying.WithWriter(family_syncing.KindFamilyVariant, write(func(ctx context.Context, w dryrun.Write, data map[string]interface{}) error {
+			return families.SaveVariant(ctx, w.Scope, w.Code, data)
+		})),
+		applying.WithWriter(channel_syncing.KindChannel, write(func(ctx context.Context, w dryrun.Write, data map[string]interface{}) error {
+			return channels.Save(ctx, w.Code, data)
+		})),
+		applying.WithWriter(measurement_family_syncing.KindMeasurementFamily, write(func(ctx context.Context, w dryrun.Write, data map[string]interface{}) error {
+			failed, err := measurementFamilies.SaveAll(ctx, []measurement_family.MeasurementFamily{data})
+			return batchError(failed, err, w.Code)
+		})),
+	}
+}
+
+// createSyncCommand creates the sync command
+func createSyncCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync [entity-name]",
+		Short: "Synchronizes all records from a specific Reference Entity",
+		Long: `Synchronizes all records from a Reference Entity from the source Akeneo 
+to the destination Akeneo. Requires the entity name as an argument.
+
+With --prune, the records of the destination entity that no longer exist in
+source are listed and deleted once confirmed. --yes skips the confirmation.
+
+Example:
+  akeneo-migrator sync brands
+  akeneo-migrator sync brands --debug
+  akeneo-migrator sync brands --prune`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeCodes(referenceEntityCodes),
+		PreRunE:           app.requiring(config.FeatureReferenceEntities),
+		RunE:              runSyncCommand(app),
+	}
+
+	// Add debug mode flag
+	cmd.Flags().Bool("debug", false, "Enable debug mode to see record contents")
+	cmd.Flags().Bool("prune", false, "Delete the destination records missing from source, after confirmation")
+	cmd.Flags().Bool("yes", false, "Delete the records found by --prune without asking for confirmation")
+	addRangeFlags(cmd, "records")
+
+	return cmd
+}
+
+// runSyncCommand executes the synchronization logic
+func runSyncCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		entityName := args[0]
+		ctx := cmd.Context()
+
+		// Get debug flag
+		debug, _ := cmd.Flags().GetBool("debug") //nolint:errcheck // flag is optional
+
+		fmt.Printf("🚀 Starting synchronization for entity: %s\n", entityName)
+		if debug {
+			fmt.Println("🔍 Debug mode enabled")
+		}
+
+		// Execute synchronization using command bus
+		fmt.Printf("📋 Synchronizing Reference Entity '%s'...\n", entityName)
+		fmt.Println("   1️⃣  Syncing entity definition...")
+		fmt.Println("   2️⃣  Syncing attributes...")
+		fmt.Println("   3️⃣  Syncing records...")
+
+		pruneMissing, _ := cmd.Flags().GetBool("prune") //nolint:errcheck // flag is optional
+		selection, err := rangeFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		response, err := app.CommandBus.Dispatch(ctx, syncing.SyncReferenceEntityCommand{
+			EntityName: entityName,
+			Debug:      debug,
+			Prune:      pruneMissing,
+			Range:      selection,
+		})
+		if err != nil {
+			return fmt.Errorf("synchronization error: %w", err)
+		}
+
+		result, ok := response.Data.(*syncing.SyncResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		fmt.Printf("📊 Found %d records to synchronize\n", result.TotalRecords)
+
+		// Show progress for each record
+		if debug {
+			for _, syncErr := range result.Errors {
+				fmt.Printf("❌ Error in record '%s': %s\n", syncErr.Code, syncErr.Message)
+			}
+		}
+
+		// Final summary
+		fmt.Println("\n📋 Synchronization summary:")
+		fmt.Printf("   ✅ Successfully synchronized records: %d\n", result.SuccessCount)
+		fmt.Printf("   ❌ Records with errors: %d\n", result.ErrorCount)
+		fmt.Printf("   📊 Total processed: %d\n", result.TotalRecords)
+		if result.MediaFiles > 0 {
+			fmt.Printf("   🖼️  Media files copied: %d\n", result.MediaFiles)
+		}
+		printPruneSummary("records", result.PruneCandidates, result.Pruned, len(result.PruneErrors))
+		if debug {
+			for _, pruneErr := range result.PruneErrors {
+				fmt.Printf("❌ Error deleting record '%s': %s\n", pruneErr.Code, pruneErr.Message)
+			}
+		}
+
+		if result.ErrorCount > 0 {
+			fmt.Println("\n⚠️  Synchronization completed with some errors.")
+			if !debug {
+				fmt.Println("💡 Run with --debug to see error details")
+			}
+		} else {
+			fmt.Println("\n🎉 Synchronization completed successfully!")
+		}
+
+		return nil
+	}
+}
+
+// printPruneSummary prints the outcome of a prune, if one found items to delete
+func printPruneSummary(items string, candidates []string, pruned, failed int) {
+	if len(candidates) == 0 {
+		return
+	}
+	fmt.Printf("   🗑️  Destination %s missing from source: %d (%d deleted", items, len(candidates), pruned)
+	if failed > 0 {
+		fmt.Printf(", %d failed", failed)
+	}
+	fmt.Println(")")
+}
+
+// conflictFlagUsage describes the --on-conflict flag of the product sync commands
+const conflictFlagUsage = "Strategy for items edited in destination since their last sync: source-wins, dest-wins, abort or interactive (default sync.conflicts)"
+
+// conflictStrategyFlag returns the strategy of the --on-conflict flag, None when it is not set
+func conflictStrategyFlag(cmd *cobra.Command) (conflict.Strategy, error) {
+	name, _ := cmd.Flags().GetString("on-conflict") //nolint:errcheck // flag is optional
+	return conflict.ParseStrategy(name)
+}
+
+// addAttributeFilterFlags adds the flags shaping the values and links the product sync commands send
+func addAttributeFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("include-attributes", nil, "Only send the values of these attributes (default filter.includeAttributes)")
+	cmd.Flags().StringSlice("exclude-attributes", nil, "Do not send the values of these attributes (default filter.excludeAttributes)")
+	cmd.Flags().Bool("drop-missing-attributes", false, "Strip the values of attributes missing in destination instead of failing (default sync.missingAttributes)")
+	cmd.Flags().Bool("create-missing-options", false, "Create the attribute options destination reports missing, copied from source, and write the items again (default sync.missingOptions)")
+	cmd.Flags().Bool("skip-media", false, "Leave image and file values out instead of copying their files (default sync.media)")
+	cmd.Flags().String("missing-associations", "", "What to do with associated products missing in destination: fail, drop or sync (default sync.missingAssociations)")
+	cmd.Flags().Bool("changed-only", false, "Only send what differs from destination, skipping unchanged items (default sync.changedOnly)")
+}
+
+// addRangeFlags adds the flags selecting the items of a bulk sync, e.g. a few of them for a trial run
+func addRangeFlags(cmd *cobra.Command, items string) {
+	cmd.Flags().Int("limit", 0, fmt.Sprintf("Only sync this number of %s, e.g. for a trial run (default: all)", items))
+	cmd.Flags().Int("offset", 0, fmt.Sprintf("Skip this number of %s, in the order source lists them", items))
+}
+
+// rangeFlag returns the range of the --limit and --offset flags, printing it when it selects some items only
+func rangeFlag(cmd *cobra.Command) (limit.Range, error) {
+	offset, _ := cmd.Flags().GetInt("offset") //nolint:errcheck // flag has default value
+	count, _ := cmd.Flags().GetInt("limit")   //nolint:errcheck // flag has default value
+
+	selection := limit.Range{Offset: offset, Limit: count}
+	if err := selection.Validate(); err != nil {
+		return limit.Range{}, err
+	}
+	if !selection.IsZero() {
+		fmt.Printf("✂️  Trial run: only the %s are synced\n", selection)
+	}
+	return selection, nil
+}
+
+// addConnectionFlags adds the flags overriding the connection settings of the file. Secrets are
+// only read from the AKENEO_SOURCE_* and AKENEO_DEST_* environment variables, since flags show in
+// the process list.
+func addConnectionFlags(rootCmd *cobra.Command) {
+	for _, instance := range []string{"source", "dest"} {
+		rootCmd.PersistentFlags().String(instance+"-host", "", fmt.Sprintf("URL of the %s instance, replacing the one of the settings file", instance))
+		rootCmd.PersistentFlags().String(instance+"-client-id", "", fmt.Sprintf("Client ID of the %s connection, replacing the one of the settings file", instance))
+		rootCmd.PersistentFlags().String(instance+"-username", "", fmt.Sprintf("Username of the %s connection, replacing the one of the settings file", instance))
+	}
+}
+
+// connectionOverrides returns the connection settings given by flags
+func connectionOverrides(cmd *cobra.Command) config.Overrides {
+	flags := cmd.Root().PersistentFlags()
+	connection := func(instance string) config.Connection {
+		host, _ := flags.GetString(instance + "-host")          //nolint:errcheck // flag is optional
+		clientID, _ := flags.GetString(instance + "-client-id") //nolint:errcheck // flag is optional
+		username, _ := flags.GetString(instance + "-username")  //nolint:errcheck // flag is optional
+		return config.Connection{Host: host, ClientID: clientID, Username: username}
+	}
+	return config.Overrides{Source: connection("source"), Dest: connection("dest")}
+}
+
+// printConflicts prints the items edited in destination since their last sync
+func printConflicts(conflicts []conflict.Conflict) {
+	if len(conflicts) == 0 {
+		return
+	}
+
+	fmt.Printf("   ⚔️  Edited in destination since the last sync: %d\n", len(conflicts))
+	for _, c := range conflicts {
+		resolution := "kept"
+		if c.Overwritten {
+			resolution = "overwritten"
+		}
+		fmt.Printf("      - %s %s (synced at %s, updated at %s): %s\n", c.Kind, c.Code, c.Synced, c.Updated, resolution)
+	}
+}
+
+// printMissingTargets prints the association links dropped because their target is missing in destination
+func printMissingTargets(targets []product_syncing.MissingTarget) {
+	if len(targets) == 0 {
+		return
+	}
+
+	fmt.Printf("   🔗 Associations to items missing in destination: %d\n", len(targets))
+	for _, target := range targets {
+		fmt.Printf("      - %s\n", target)
+	}
+}
+
+// printDroppedAttributes prints the attributes left out of items because they are missing in destination
+func printDroppedAttributes(dropped []product_syncing.DroppedAttributes) {
+	if len(dropped) == 0 {
+		return
+	}
+
+	fmt.Printf("   🧹 Items written without attributes missing in destination: %d\n", len(dropped))
+	for _, item := range dropped {
+		fmt.Printf("      - %s\n", item)
+	}
+}
+
+// printUnchanged prints the number of products and models left untouched by changed-only updates
+func printUnchanged(count int) {
+	if count > 0 {
+		fmt.Printf("   ⏸️  Unchanged in destination, not written: %d\n", count)
+	}
+}
+
+// printSkipped prints the items of a hierarchy left out because a model above them could not be written
+func printSkipped(errs []product_syncing.SyncError) {
+	var skipped []product_syncing.SyncError
+	for _, syncErr := range errs {
+		if syncErr.FailedModel != "" {
+			skipped = append(skipped, syncErr)
+		}
+	}
+	if len(skipped) == 0 {
+		return
+	}
+
+	fmt.Printf("   ⏭️  Skipped under failed models: %d\n", len(skipped))
+	for _, syncErr := range skipped {
+		fmt.Printf("      - %s %s (under %s)\n", syncErr.Kind, syncErr.Code, syncErr.FailedModel)
+	}
+}
+
+// askConflict asks on the terminal whether to overwrite an item edited in destination since its last sync
+func askConflict() conflict.Ask {
+	return func(ctx context.Context, c conflict.Conflict) bool {
+		fmt.Printf("\n⚔️  %s %s was edited in destination since the last sync (synced at %s, updated at %s).\n", c.Kind, c.Code, c.Synced, c.Updated)
+		fmt.Print("Overwrite it with the source version? [y/N] ")
+		answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && answer == "" {
+			return false
+		}
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		return answer == "y" || answer == "yes"
+	}
+}
+
+// createSyncRecordCommand creates the sync-reference-entity-record command
+func createSyncRecordCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync-reference-entity-record [entity-name] [record-code]",
+		Short: "Synchronizes a single record of a Reference Entity",
+		Long: `Synchronizes one record of a Reference Entity from the source Akeneo to the
+destination Akeneo, including the media files of its image values. Useful after
+an editor changes a single brand or color, without re-running the full entity.
+
+The Reference Entity and its attributes must already exist in the destination.
+
+Example:
+  akeneo-migrator sync-reference-entity-record brands acme
+  akeneo-migrator sync-reference-entity-record colors red --debug`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeCodes(referenceEntityCodes),
+		PreRunE:           app.requiring(config.FeatureReferenceEntities),
+		RunE:              runSyncRecordCommand(app),
+	}
+
+	// Add debug mode flag
+	cmd.Flags().Bool("debug", false, "Enable debug mode to see error details")
+
+	return cmd
+}
+
+// runSyncRecordCommand executes the record synchronization logic
+func runSyncRecordCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		entityName := args[0]
+		code := args[1]
+		ctx := cmd.Context()
+
+		// Get debug flag
+		debug, _ := cmd.Flags().GetBool("debug") //nolint:errcheck // flag is optional
+
+		fmt.Printf("🚀 Starting synchronization of record '%s' of entity '%s'\n", code, entityName)
+		if debug {
+			fmt.Println("🔍 Debug mode enabled")
+		}
+
+		response, err := app.CommandBus.Dispatch(ctx, reference_entity_syncing_record.SyncRecordCommand{
+			EntityName: entityName,
+			Code:       code,
+			Debug:      debug,
+		})
+		if err != nil {
+			return fmt.Errorf("synchronization error: %w", err)
+		}
+
+		result, ok := response.Data.(*reference_entity_syncing_record.SyncResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		fmt.Println("\n📋 Synchronization summary:")
+		if result.Created {
+			fmt.Printf("   ✅ Record '%s' created\n", result.Code)
+		} else {
+			fmt.Printf("   ✅ Record '%s' updated\n", result.Code)
+		}
+		fmt.Printf("   🖼️  Media files copied: %d\n", result.MediaFiles)
+
+		fmt.Println("\n🎉 Synchronization completed successfully!")
+
+		return nil
+	}
+}
+
+// setupDefaultEnvironmentVariables sets up default environment variables
+func setupDefaultEnvironmentVariables() {
+	if os.Getenv("ENVIRONMENT") == "" {
+		_ = os.Setenv("ENVIRONMENT", "local")
+	}
+	if os.Getenv("CONFIG_PATH") == "" {
+		_ = os.Setenv("CONFIG_PATH", "akeneo-migrator")
+	}
+}
+
+// createSyncAssetFamilyCommand creates the sync-asset-family command
+func createSyncAssetFamilyCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync-asset-family [family-code]",
+		Short: "Synchronizes an asset family with its attributes and assets",
+		Long: `Synchronizes an asset family of the Asset Manager from the source Akeneo to the
+destination Akeneo: the family definition, its attributes and their options, then
+all its assets. Media files are downloaded from source and uploaded to destination.
+
+Requires the asset family code as an argument.
+
+With --prune, the assets of the destination family that no longer exist in
+source are listed and deleted once confirmed. --yes skips the confirmation.
+
+Example:
+  akeneo-migrator sync-asset-family packshots
+  akeneo-migrator sync-asset-family packshots --debug
+  akeneo-migrator sync-asset-family packshots --prune`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeCodes(assetFamilyCodes),
+		PreRunE:           app.requiring(config.FeatureAssetManager),
+		RunE:              runSyncAssetFamilyCommand(app),
+	}
+
+	// Add debug mode flag
+	cmd.Flags().Bool("debug", false, "Enable debug mode to see asset errors")
+	cmd.Flags().Bool("prune", false, "Delete the destination assets missing from source, after confirmation")
+	cmd.Flags().Bool("yes", false, "Delete the assets found by --prune without asking for confirmation")
+
+	return cmd
+}
+
+// runSyncAssetFamilyCommand executes the asset family synchronization logic
+func runSyncAssetFamilyCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		familyCode := args[0]
+		ctx := cmd.Context()
+
+		// Get debug flag
+		debug, _ := cmd.Flags().GetBool("debug") //nolint:errcheck // flag is optional
+
+		fmt.Printf("🚀 Starting synchronization for asset family: %s\n", familyCode)
+		if debug {
+			fmt.Println("🔍 Debug mode enabled")
+		}
+
+		fmt.Println("   1️⃣  Syncing family definition...")
+		fmt.Println("   2️⃣  Syncing attributes and options...")
+		fmt.Println("   3️⃣  Syncing assets...")
+
+		// Execute synchronization using command bus
+		pruneMissing, _ := cmd.Flags().GetBool("prune") //nolint:errcheck // flag is optional
+
+		response, err := app.CommandBus.Dispatch(ctx, asset_syncing.SyncAssetFamilyCommand{
+			FamilyCode: familyCode,
+			Debug:      debug,
+			Prune:      pruneMissing,
+		})
+		if err != nil {
+			return fmt.Errorf("synchronization error: %w", err)
+		}
+
+		result, ok := response.Data.(*asset_syncing.SyncResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		if debug {
+			for _, syncErr := range result.Errors {
+				fmt.Printf("❌ Error in asset '%s': %s\n", syncErr.Code, syncErr.Message)
+			}
+		}
+
+		// Final summary
+		fmt.Println("\n📋 Synchronization summary:")
+		fmt.Printf("   🧩 Attributes: %d (%d options)\n", result.Attributes, result.Options)
+		fmt.Printf("   🖼️  Media files copied: %d\n", result.MediaFiles)
+		fmt.Printf("   ✅ Successfully synchronized assets: %d\n", result.SuccessCount)
+		fmt.Printf("   ❌ Assets with errors: %d\n", result.ErrorCount)
+		fmt.Printf("   📊 Total processed: %d\n", result.TotalAssets)
+		printPruneSummary("assets", result.PruneCandidates, result.Pruned, len(result.PruneErrors))
+		if debug {
+			for _, pruneErr := range result.PruneErrors {
+				fmt.Printf("❌ Error deleting asset '%s': %s\n", pruneErr.Code, pruneErr.Message)
+			}
+		}
+
+		if result.ErrorCount > 0 {
+			fmt.Println("\n⚠️  Synchronization completed with some errors.")
+			if !debug {
+				fmt.Println("💡 Run with --debug to see error details")
+			}
+		} else {
+			fmt.Println("\n🎉 Synchronization completed successfully!")
+		}
+
+		return nil
+	}
+}
+
+// createSyncAssetCommand creates the sync-asset command
+func createSyncAssetCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync-asset [family-code] [asset-code...]",
+		Short: "Synchronizes assets of an asset family",
+		Long: `Synchronizes assets of an asset family from the source Akeneo to the destination
+Akeneo, including their media files, without the family definition. Useful after
+editors change a few assets, or to refresh the assets of a family whose structure
+is already migrated.
+
+Without asset codes, every asset of the family is synchronized in batches.
+
+The asset family and its attributes must already exist in the destination; run
+sync-asset-family first.
+
+Example:
+  akeneo-migrator sync-asset packshots shoe_front
+  akeneo-migrator sync-asset packshots shoe_front boot_front --debug
+  akeneo-migrator sync-asset packshots`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeCodes(assetFamilyCodes),
+		PreRunE:           app.requiring(config.FeatureAssetManager),
+		RunE:              runSyncAssetCommand(app),
+	}
+
+	// Add debug mode flag
+	cmd.Flags().Bool("debug", false, "Enable debug mode to see asset errors")
+
+	return cmd
+}
+
+// runSyncAssetCommand executes the asset synchronization logic
+func runSyncAssetCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		familyCode := args[0]
+		codes := args[1:]
+		ctx := cmd.Context()
+
+		// Get debug flag
+		debug, _ := cmd.Flags().GetBool("debug") //nolint:errcheck // flag is optional
+
+		if len(codes) > 0 {
+			fmt.Printf("🚀 Starting synchronization of %d assets of family '%s'\n", len(codes), familyCode)
+		} else {
+			fmt.Printf("🚀 Starting synchronization of all assets of family '%s'\n", familyCode)
+		}
+		if debug {
+			fmt.Println("🔍 Debug mode enabled")
+		}
+
+		response, err := app.CommandBus.Dispatch(ctx, asset_syncing_asset.SyncAssetsCommand{
+			FamilyCode: familyCode,
+			Codes:      codes,
+			Debug:      debug,
+		})
+		if err != nil {
+			return fmt.Errorf("synchronization error: %w", err)
+		}
+
+		result, ok := response.Data.(*asset_syncing_asset.SyncResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		if debug {
+			for _, failure := range result.FailedItems {
+				fmt.Printf("❌ Error in asset '%s': %s\n", failure.Code, failure.Error)
+			}
+		}
+
+		// Final summary
+		fmt.Println("\n📋 Synchronization summary:")
+		fmt.Printf("   🖼️  Media files copied: %d\n", result.MediaFiles)
+		fmt.Printf("   ✅ Successfully synchronized assets: %d\n", result.AssetsSynced)
+		fmt.Printf("   ❌ Assets with errors: %d\n", len(result.FailedItems))
+		fmt.Printf("   📊 Total processed: %d\n", result.TotalAssets)
+
+		if !result.Success {
+			fmt.Println("\n⚠️  Synchronization completed with some errors.")
+			if !debug {
+				fmt.Println("💡 Run with --debug to see error details")
+			}
+		} else {
+			fmt.Println("\n🎉 Synchronization completed successfully!")
+		}
+
+		return nil
+	}
+}
+
+// createSyncProductCommand creates the sync-product command
+func createSyncProductCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync-product [identifier]",
+		Short: "Synchronizes a product hierarchy by its common identifier",
+		Long: `Synchronizes a complete product hierarchy from the source Akeneo to the destination Akeneo.
+
+For SIMPLE products: Common → Child Products
+For CONFIGURABLE products: Common → Models → Variant Products
+
+Requires the common product/model identifier as an argument.
+
+With --values-only, products and models that already exist in the destination
+only receive their values; categories, groups, associations and the enabled
+flag are left untouched. New items are created with their full payload.
+
+With --preflight, the hierarchy is first checked as validate-products does and
+nothing is written when families, attributes or options are missing.
+
+With --with-dependencies, the families, family variants, attributes, options and
+categories the hierarchy needs are synced first when the destination lacks them.
+
+Example:
+  akeneo-migrator sync-product COMMON-001
+  akeneo-migrator sync-product COMMON-001 --values-only
+  akeneo-migrator sync-product COMMON-001 --preflight
+  akeneo-migrator sync-product COMMON-001 --with-dependencies
+  akeneo-migrator sync-product COMMON-001 --debug`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: app.initialize,
+		RunE:    runSyncProductCommand(app),
+	}
+
+	// Add flags
+	cmd.Flags().Bool("debug", false, "Enable debug mode to see product contents")
+	cmd.Flags().Bool("values-only", false, "Only send values for items that already exist in destination")
+	cmd.Flags().String("on-conflict", "", conflictFlagUsage)
+	cmd.Flags().Bool("preflight", false, preflightFlagUsage)
+	cmd.Flags().Bool("with-dependencies", false, withDependenciesFlagUsage)
+	addAttributeFilterFlags(cmd)
+
+	return cmd
+}
+
+// runSyncProductCommand executes the product synchronization logic
+func runSyncProductCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		identifier := args[0]
+		ctx := cmd.Context()
+
+		// Get flags
+		debug, _ := cmd.Flags().GetBool("debug")            //nolint:errcheck // flag is optional
+		valuesOnly, _ := cmd.Flags().GetBool("values-only") //nolint:errcheck // flag is optional
+
+		fmt.Printf("🚀 Starting synchronization for product: %s\n", identifier)
+		if debug {
+			fmt.Println("🔍 Debug mode enabled")
+		}
+		if valuesOnly {
+			fmt.Println("📝 Values-only mode: existing items only receive their values")
+		}
+		onConflict, err := conflictStrategyFlag(cmd)
+		if err != nil {
+			return err
+		}
+		if err := syncDependencies(cmd, app, product_syncing_dependencies.SyncDependenciesCommand{Identifiers: []string{identifier}, Debug: debug}); err != nil {
+			return err
+		}
+		if err := preflight(cmd, app, product_validating.ValidateProductsCommand{Identifiers: []string{identifier}}); err != nil {
+			return err
+		}
+
+		// Sync entire hierarchy
+		fmt.Printf("📥 Fetching product hierarchy for '%s' from source...\n", identifier)
+		response, err := app.CommandBus.Dispatch(ctx, product_syncing.SyncProductCommand{
+			Identifier: identifier,
+			ValuesOnly: valuesOnly,
+			OnConflict: onConflict,
+			Debug:      debug,
+		})
+
+		if err != nil {
+			return fmt.Errorf("synchronization error: %w", err)
+		}
+
+		result, ok := response.Data.(*product_syncing.SyncResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		// Show result
+		if result.Success {
+			fmt.Println("\n📋 Synchronization Summary:")
+			fmt.Printf("   📦 Models synced: %d\n", result.ModelsSynced)
+			fmt.Printf("   📦 Products synced: %d\n", result.ProductsSynced)
+			fmt.Printf("   📊 Total synced: %d\n", result.TotalSynced)
+			printConflicts(result.Conflicts)
+			printMissingTargets(result.MissingTargets)
+			printDroppedAttributes(result.DroppedAttributes)
+			printUnchanged(result.Unchanged)
+			printSkipped(result.Errors)
+			fmt.Printf("\n✅ Hierarchy '%s' synchronized successfully!\n", result.Identifier)
+		} else {
+			fmt.Printf("❌ Failed to synchronize '%s': %s\n", result.Identifier, result.Error)
+		}
+
+		return nil
+	}
+}
+
+// createSyncProductModelCommand creates the sync-product-model command
+func createSyncProductModelCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync-product-model [code]",
+		Short: "Synchronizes a single product model by its code",
+		Long: `Synchronizes exactly one product model from the source Akeneo to the destination
+Akeneo. Sibling models, sub-models and variant products are not touched.
+
+With --with-parents, the ancestor chain of the model is synchronized first,
+from the root down, so the model can be created when its parents are missing
+in the destination.
+
+Example:
+  akeneo-migrator sync-product-model MODEL-001-BLUE
+  akeneo-migrator sync-product-model MODEL-001-BLUE --with-parents
+  akeneo-migrator sync-product-model MODEL-001 --values-only`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: app.initialize,
+		RunE:    runSyncProductModelCommand(app),
+	}
+
+	// Add flags
+	cmd.Flags().Bool("debug", false, "Enable debug mode to see detailed sync information")
+	cmd.Flags().Bool("with-parents", false, "Also sync the ancestor chain of the model")
+	cmd.Flags().Bool("values-only", false, "Only send values for models that already exist in destination")
+	cmd.Flags().String("on-conflict", "", conflictFlagUsage)
+	addAttributeFilterFlags(cmd)
+
+	return cmd
+}
+
+// runSyncProductModelCommand executes the product model synchronization logic
+func runSyncProductModelCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		code := args[0]
+		ctx := cmd.Context()
+
+		// Get flags
+		debug, _ := cmd.Flags().GetBool("debug")              //nolint:errcheck // flag is optional
+		withParents, _ := cmd.Flags().GetBool("with-parents") //nolint:errcheck // flag is optional
+		valuesOnly, _ := cmd.Flags().GetBool("values-only")   //nolint:errcheck // flag is optional
+
+		fmt.Printf("🚀 Starting synchronization for product model: %s\n", code)
+		if debug {
+			fmt.Println("🔍 Debug mode enabled")
+		}
+		if withParents {
+			fmt.Println("🌳 Ancestor models are synchronized first")
+		}
+		if valuesOnly {
+			fmt.Println("📝 Values-only mode: existing items only receive their values")
+		}
+		onConflict, err := conflictStrategyFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		response, err := app.CommandBus.Dispatch(ctx, product_syncing_model.SyncProductModelCommand{
+			Code:        code,
+			WithParents: withParents,
+			ValuesOnly:  valuesOnly,
+			OnConflict:  onConflict,
+			Debug:       debug,
+		})
+		if err != nil {
+			return fmt.Errorf("synchronization error: %w", err)
+		}
+
+		result, ok := response.Data.(*product_syncing_model.SyncResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		// Show result
+		fmt.Println("\n📋 Synchronization Summary:")
+		if len(result.Parents) > 0 {
+			fmt.Printf("   🌳 Parents synced: %s\n", strings.Join(result.Parents, " → "))
+		}
+		fmt.Printf("   📦 Models synced: %d\n", result.ModelsSynced)
+		printConflicts(result.Conflicts)
+		printMissingTargets(result.MissingTargets)
+		printDroppedAttributes(result.DroppedAttributes)
+		printUnchanged(result.Unchanged)
+		fmt.Printf("\n✅ Product model '%s' synchronized successfully!\n", result.Code)
+
+		return nil
+	}
+}
+
+// createValidateProductsCommand creates the validate-products command
+func createValidateProductsCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate-products [identifier...]",
+		Short: "Checks that the destination has what product hierarchies need before syncing them",
+		Long: `Reads product hierarchies from the source, as sync-product does, and checks that
+the destination has their families, family variants, attributes and the options
+of their simple and multi select values. Nothing is written.
+
+Each missing prerequisite is listed with the number of products and models that
+rely on it, so they can be created before the sync instead of failing item by
+item with 422 errors. Use --sync-list to write the commands creating them, in
+the order they have to run, to a file.
+
+Attributes are reported even when sync.missingAttributes drops them, since the
+values would then be lost.
+
+Attributes whose type, localizable or scopable flag differ between source and
+destination are reported as incompatible: destination rejects their values.
+Attributes left out by the attribute filters are not checked.
+
+Pass the common identifiers as arguments, or --file with a list in any format
+sync-products-from-file reads. The command exits with status 1 when something
+is missing.
+
+Example:
+  akeneo-migrator validate-products COMMON-001 COMMON-002
+  akeneo-migrator validate-products --file identifiers.txt --sync-list sync-first.sh`,
+		PreRunE: app.initialize,
+		RunE:    runValidateProductsCommand(app),
+	}
+
+	// Add flags
+	cmd.Flags().String("file", "", "Validate the hierarchies listed in this file instead of the arguments")
+	cmd.Flags().String("sync-list", "", "Write the commands creating the missing prerequisites to this file")
+
+	return cmd
+}
+
+// runValidateProductsCommand executes the validation of product hierarchies
+func runValidateProductsCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		path, _ := cmd.Flags().GetString("file")          //nolint:errcheck // flag is optional
+		syncList, _ := cmd.Flags().GetString("sync-list") //nolint:errcheck // flag is optional
+
+		command := product_validating.ValidateProductsCommand{Identifiers: args, Path: path}
+		switch {
+		case path != "" && len(args) > 0:
+			return errors.New("pass either identifiers or --file, not both")
+		case path != "":
+			fmt.Printf("🔎 Validating the hierarchies listed in %s against the destination\n", path)
+		case len(args) > 0:
+			fmt.Printf("🔎 Validating %d hierarchies against the destination\n", len(args))
+		default:
+			return errors.New("pass the identifiers to validate or --file")
+		}
+
+		result, err := validateProducts(ctx, app, command)
+		if err != nil {
+			return err
+		}
+		printValidation(result)
+
+		if syncList != "" {
+			commands := result.SyncFirst()
+			content := ""
+			if len(commands) > 0 {
+				content = "akeneo-migrator " + strings.Join(commands, "\nakeneo-migrator ") + "\n"
+			}
+			if err := os.WriteFile(syncList, []byte(content), 0o644); err != nil {
+				return fmt.Errorf("error writing sync list: %w", err)
+			}
+			fmt.Printf("📝 Commands to run first written to %s\n", syncList)
+		}
+
+		if !result.Valid() {
+			fmt.Printf("\n❌ %d prerequisites missing in destination, %d attributes incompatible, %d hierarchies not read\n", len(result.Missing), len(result.Incompatible), len(result.Errors))
+			return exitError{code: ExitFailure}
+		}
+		fmt.Println("\n✅ The destination has everything the products need")
+		return nil
+	}
+}
+
+// preflightFlagUsage describes the --preflight flag of the product syncs
+const preflightFlagUsage = "Check that the destination has the families, attributes and options of the products first, and write nothing if not"
+
+// preflight validates the products of a sync when --preflight is set, stopping it before any write
+// when prerequisites are missing
+func preflight(cmd *cobra.Command, app *Application, command product_validating.ValidateProductsCommand) error {
+	enabled, _ := cmd.Flags().GetBool("preflight") //nolint:errcheck // flag is optional
+	if !enabled {
+		return nil
+	}
+
+	fmt.Println("🔎 Preflight: checking the destination prerequisites of the products...")
+	result, err := validateProducts(cmd.Context(), app, command)
+	if err != nil {
+		return err
+	}
+	if !result.Valid() {
+		printValidation(result)
+		fmt.Println("\n❌ Preflight failed: nothing was written")
+		return exitError{code: ExitFailure}
+	}
+	fmt.Printf("✅ Preflight passed: %d models and %d products can be written\n", result.Models, result.Products)
+	return nil
+}
+
+// withDependenciesFlagUsage describes the --with-dependencies flag of the product syncs
+const withDependenciesFlagUsage = "Sync the families, variants, attributes, options and categories the products need first when the destination lacks them"
+
+// syncDependencies syncs the structure the products of a sync miss in destination when
+// --with-dependencies is set. Prerequisites that fail are reported and the sync goes on.
+func syncDependencies(cmd *cobra.Command, app *Application, command product_syncing_dependencies.SyncDependenciesCommand) error {
+	enabled, _ := cmd.Flags().GetBool("with-dependencies") //nolint:errcheck // flag is optional
+	if !enabled {
+		return nil
+	}
+
+	fmt.Println("🧩 Syncing the structure the products need in destination...")
+	response, err := app.CommandBus.Dispatch(cmd.Context(), command)
+	if err != nil {
+		return fmt.Errorf("dependencies error: %w", err)
+	}
+
+	result, ok := response.Data.(*product_syncing_dependencies.SyncResult)
+	if !ok {
+		return errors.New("invalid response type")
+	}
+
+	if len(result.Dependencies) == 0 {
+		fmt.Println("✅ Nothing missing in destination")
+		return nil
+	}
+	synced, failed := 0, 0
+	for _, dependency := range result.Dependencies {
+		switch {
+		case dependency.Skipped:
+			fmt.Printf("   ⏭️  %s: not synced automatically\n", dependency.Missing)
+		case dependency.Error != "":
+			failed++
+			fmt.Printf("   ❌ %s: %s\n", dependency.Missing, dependency.Error)
+		default:
+			synced++
+			fmt.Printf("   ✅ %s\n", dependency.Missing)
+		}
+	}
+	fmt.Printf("🧩 Dependencies: %d synced, %d failed\n", synced, failed)
+	return nil
+}
+
+// validateProducts dispatches a validation and returns its result
+func validateProducts(ctx context.Context, app *Application, command product_validating.ValidateProductsCommand) (*product_validating.ValidationResult, error) {
+	response, err := app.CommandBus.Dispatch(ctx, command)
+	if err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	result, ok := response.Data.(*product_validating.ValidationResult)
+	if !ok {
+		return nil, errors.New("invalid response type")
+	}
+	return result, nil
+}
+
+// printValidation prints what a validation found missing in destination
+func printValidation(result *product_validating.ValidationResult) {
+	fmt.Println("\n📋 Validation Summary:")
+	fmt.Printf("   📄 Hierarchies: %d\n", len(result.Identifiers))
+	fmt.Printf("   📦 Models checked: %d\n", result.Models)
+	fmt.Printf("   📦 Products checked: %d\n", result.Products)
+	fmt.Printf("   ❓ Missing in destination: %d\n", len(result.Missing))
+
+	for _, missing := range result.Missing {
+		usedBy := strings.Join(missing.UsedBy, ", ")
+		if more := missing.Items - len(missing.UsedBy); more > 0 {
+			usedBy += fmt.Sprintf(" and %d more", more)
+		}
+		fmt.Printf("   - %s (%d items: %s)\n", missing, missing.Items, usedBy)
+	}
+	if len(result.Incompatible) > 0 {
+		fmt.Printf("   ⚔️  Incompatible attributes: %d\n", len(result.Incompatible))
+	}
+	for _, incompatible := range result.Incompatible {
+		usedBy := strings.Join(incompatible.UsedBy, ", ")
+		if more := incompatible.Items - len(incompatible.UsedBy); more > 0 {
+			usedBy += fmt.Sprintf(" and %d more", more)
+		}
+		fmt.Printf("   - attribute %s: %s (%d items: %s)\n", incompatible.Attribute, strings.Join(incompatible.Differences(), ", "), incompatible.Items, usedBy)
+		fmt.Printf("     → %s\n", incompatible.Fix())
+	}
+	for _, failure := range result.Errors {
+		fmt.Printf("❌ %s\n", failure)
+	}
+
+	if commands := result.SyncFirst(); len(commands) > 0 {
+		fmt.Println("\n🧭 Sync these first:")
+		for _, command := range commands {
+			fmt.Printf("   akeneo-migrator %s\n", command)
+		}
+	}
+}
+
+// createSyncAttributeCommand creates the sync-attribute command
+func createSyncAttributeCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync-attribute [code]",
+		Short: "Synchronizes an attribute by its code",
+		Long: `Synchronizes a single attribute from the source Akeneo to the destination Akeneo.
+
+The attribute group must exist in destination; use --with-group to sync it first,
+without its attribute list.
+
+Requires the attribute code as an argument.
+
+Example:
+  akeneo-migrator sync-attribute sku
+  akeneo-migrator sync-attribute description --with-group --debug`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeCodes(attributeCodes),
+		PreRunE:           app.initialize,
+		RunE:              runSyncAttributeCommand(app),
+	}
+
+	// Add debug flag
+	cmd.Flags().Bool("debug", false, "Enable debug mode to see attribute contents")
+	cmd.Flags().Bool("with-group", false, "Sync the attribute group before the attribute")
+
+	return cmd
+}
+
+// runSyncAttributeCommand executes the attribute synchronization logic
+func runSyncAttributeCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		code := args[0]
+		ctx := cmd.Context()
+
+		// Get flags
+		debug, _ := cmd.Flags().GetBool("debug")          //nolint:errcheck // flag is optional
+		withGroup, _ := cmd.Flags().GetBool("with-group") //nolint:errcheck // flag is optional
+
+		fmt.Printf("🚀 Starting synchronization for attribute: %s\n", code)
+		if debug {
+			fmt.Println("🔍 Debug mode enabled")
+		}
+
+		// Execute synchronization using command bus
+		response, err := app.CommandBus.Dispatch(ctx, attribute_syncing.SyncAttributeCommand{
+			Code:      code,
+			WithGroup: withGroup,
+			Debug:     debug,
+		})
+		if err != nil {
+			return fmt.Errorf("synchronization error: %w", err)
+		}
+
+		result, ok := response.Data.(*attribute_syncing.SyncResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		// Show result
+		if result.Success {
+			fmt.Printf("\n✅ Attribute '%s' synchronized successfully!\n", result.Code)
+			if result.DestCode != "" {
+				fmt.Printf("   🔀 Written to destination as: %s\n", result.DestCode)
+			}
+			if result.Group != "" {
+				fmt.Printf("   📁 Attribute group synced: %s\n", result.Group)
+			}
+			if result.OptionsSynced > 0 {
+				fmt.Printf("   📋 Attribute options synced: %d\n", result.OptionsSynced)
+			}
+			if len(result.OptionsErrors) > 0 {
+				fmt.Printf("   ⚠️  Option errors: %d\n", len(result.OptionsErrors))
+				if debug {
+					for _, errMsg := range result.OptionsErrors {
+						fmt.Printf("      - %s\n", errMsg)
+					}
+				}
+			}
+		} else {
+			fmt.Printf("❌ Failed to synchronize '%s': %s\n", result.Code, result.Error)
+		}
+
+		return nil
+	}
+}
+
+// createSyncAllAttributesCommand creates the sync-all-attributes command
+func createSyncAllAttributesCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync-all-attributes",
+		Short: "Synchronizes every attribute of the source",
+		Long: `Synchronizes every attribute of the source Akeneo to the destination Akeneo,
+with the options of select attributes.
+
+The catalog is written in three steps: the attribute groups used by the
+attributes (without their attribute list: attributes join their group as they
+are written), then the attributes, then the options of select attributes. Items
+are written in source order with their sort order. A progress line is printed
+as attributes are processed and the status of each group is listed at the end.
+An item that fails is reported and does not stop the others; failed items can
+be reprocessed with retry-failed.
+
+Example:
+  akeneo-migrator sync-all-attributes
+  akeneo-migrator sync-all-attributes --debug`,
+		Args:    cobra.NoArgs,
+		PreRunE: app.initialize,
+		RunE:    runSyncAllAttributesCommand(app),
+	}
+
+	// Add debug flag
+	cmd.Flags().Bool("debug", false, "Enable debug mode to see the result of every attribute")
+
+	return cmd
+}
+
+// runSyncAllAttributesCommand executes the synchronization of every attribute
+func runSyncAllAttributesCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		// Get debug flag
+		debug, _ := cmd.Flags().GetBool("debug") //nolint:errcheck // flag is optional
+
+		fmt.Println("🚀 Starting synchronization of all attributes")
+		if debug {
+			fmt.Println("🔍 Debug mode enabled")
+		}
+
+		progress := func(step attribute_syncing_all.Step, processed int, result *attribute_syncing.SyncResult) {
+			if step == attribute_syncing_all.StepOptions {
+				switch {
+				case len(result.OptionsErrors) > 0:
+					fmt.Printf("   [%d] ⚠️  %s: %d options synced, %d errors\n", processed, result.Code, result.OptionsSynced, len(result.OptionsErrors))
+					if debug {
+						for _, errMsg := range result.OptionsErrors {
+							fmt.Printf("      - %s\n", errMsg)
+						}
+					}
+				case debug:
+					fmt.Printf("   [%d] 📋 %s: %d options synced\n", processed, result.Code, result.OptionsSynced)
+				case processed%attribute_syncing_all.PageSize == 0:
+					fmt.Printf("   📊 Options of %d attributes processed...\n", processed)
+				}
+				return
+			}
+
+			switch {
+			case result.Error != "":
+				fmt.Printf("   [%d] ❌ %s: %s\n", processed, result.Code, result.Error)
+			case debug:
+				fmt.Printf("   [%d] ✅ %s\n", processed, result.Code)
+			case processed%attribute_syncing_all.PageSize == 0:
+				fmt.Printf("   📊 %d attributes processed...\n", processed)
+			}
+		}
+
+		// Execute synchronization using command bus
+		response, err := app.CommandBus.Dispatch(ctx, attribute_syncing_all.SyncAllAttributesCommand{
+			Progress: progress,
+			Debug:    debug,
+		})
+		if err != nil {
+			return fmt.Errorf("synchronization error: %w", err)
+		}
+
+		result, ok := response.Data.(*attribute_syncing_all.SyncResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		// Show summary
+		fmt.Println("\n📋 Synchronization summary:")
+		fmt.Printf("   ✅ Attributes synced: %d/%d\n", result.AttributesSynced, len(result.Attributes))
+		fmt.Printf("   📋 Options synced: %d\n", result.OptionsSynced)
+		fmt.Printf("   📁 Attribute groups synced: %d/%d\n", result.GroupsSynced, len(result.Groups))
+		for _, group := range result.Groups {
+			if group.Error != "" {
+				fmt.Printf("      ❌ %s: %s\n", group.Code, group.Error)
+			} else {
+				fmt.Printf("      ✅ %s\n", group.Code)
+			}
+		}
+
+		if result.Success {
+			fmt.Println("\n✅ All attributes synchronized successfully!")
+		} else {
+			fmt.Printf("\n⚠️  %d items with errors; run retry-failed to reprocess them\n", len(result.FailedItems))
+		}
+
+		return nil
+	}
+}
+
+// createSyncAttributeGroupCommand creates the sync-attribute-group command
+func createSyncAttributeGroupCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync-attribute-group [code]",
+		Short: "Synchronizes an attribute group by its code",
+		Long: `Synchronizes a single attribute group from the source Akeneo to the destination Akeneo.
+
+By default the group is written without its list of attributes: Akeneo rejects the
+group when one of them does not exist yet in destination, and attributes join their
+group when they are synchronized. Use --with-attributes once the attributes exist
+in destination to also move them into the group.
+
+Requires the attribute group code as an argument.
+
+Example:
+  akeneo-migrator sync-attribute-group marketing
+  akeneo-migrator sync-attribute-group technical --with-attributes --debug`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: app.initialize,
+		RunE:    runSyncAttributeGroupCommand(app),
+	}
+
+	// Add flags
+	cmd.Flags().Bool("debug", false, "Enable debug mode to see the attributes of the group")
+	cmd.Flags().Bool("with-attributes", false, "Also send the list of attributes of the group")
+
+	return cmd
+}
+
+// runSyncAttributeGroupCommand executes the attribute group synchronization logic
+func runSyncAttributeGroupCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		code := args[0]
+		ctx := cmd.Context()
+
+		// Get flags
+		debug, _ := cmd.Flags().GetBool("debug")                    //nolint:errcheck // flag is optional
+		withAttributes, _ := cmd.Flags().GetBool("with-attributes") //nolint:errcheck // flag is optional
+
+		fmt.Printf("🚀 Starting synchronization for attribute group: %s\n", code)
+		if debug {
+			fmt.Println("🔍 Debug mode enabled")
+		}
+
+		// Execute synchronization using command bus
+		response, err := app.CommandBus.Dispatch(ctx, attribute_group_syncing.SyncAttributeGroupCommand{
+			Code:           code,
+			WithAttributes: withAttributes,
+			Debug:          debug,
+		})
+		if err != nil {
+			return fmt.Errorf("synchronization error: %w", err)
+		}
+
+		result, ok := response.Data.(*attribute_group_syncing.SyncResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		// Show result
+		if result.Success {
+			fmt.Printf("\n✅ Attribute group '%s' synchronized successfully!\n", result.Code)
+			if withAttributes {
+				fmt.Printf("   📋 Attributes in group: %d\n", len(result.Attributes))
+				if debug {
+					for _, attributeCode := range result.Attributes {
+						fmt.Printf("      - %s\n", attributeCode)
+					}
+				}
+			}
+		} else {
+			fmt.Printf("❌ Failed to synchronize '%s': %s\n", result.Code, result.Error)
+		}
+
+		return nil
+	}
+}
+
+// createSyncAssociationTypeCommand creates the sync-association-type command
+func createSyncAssociationTypeCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync-association-type [code]",
+		Short: "Synchronizes an association type by its code",
+		Long: `Synchronizes a single association type from the source Akeneo to the destination Akeneo.
+
+Products and product models are rejected when one of their associations uses a type
+missing in destination. Sync the association types first, or enable sync.autoDeps to
+create them automatically while products are synchronized.
+
+Requires the association type code as an argument.
+
+Example:
+  akeneo-migrator sync-association-type X_SELL
+  akeneo-migrator sync-association-type PACK --debug`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeCodes(associationTypeCodes),
+		PreRunE:           app.initialize,
+		RunE:              runSyncAssociationTypeCommand(app),
+	}
+
+	// Add debug flag
+	cmd.Flags().Bool("debug", false, "Enable debug mode")
+
+	return cmd
+}
+
+// runSyncAssociationTypeCommand executes the association type synchronization logic
+func runSyncAssociationTypeCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		code := args[0]
+		ctx := cmd.Context()
+
+		// Get debug flag
+		debug, _ := cmd.Flags().GetBool("debug") //nolint:errcheck // flag is optional
+
+		fmt.Printf("🚀 Starting synchronization for association type: %s\n", code)
+		if debug {
+			fmt.Println("🔍 Debug mode enabled")
+		}
+
+		// Execute synchronization using command bus
+		response, err := app.CommandBus.Dispatch(ctx, association_type_syncing.SyncAssociationTypeCommand{
+			Code:  code,
+			Debug: debug,
+		})
+		if err != nil {
+			return fmt.Errorf("synchronization error: %w", err)
+		}
+
+		result, ok := response.Data.(*association_type_syncing.SyncResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		// Show result
+		if result.Success {
+			fmt.Printf("\n✅ Association type '%s' synchronized successfully!\n", result.Code)
+		} else {
+			fmt.Printf("❌ Failed to synchronize '%s': %s\n", result.Code, result.Error)
+		}
+
+		return nil
+	}
+}
+
+// createSyncAllAssociationTypesCommand creates the sync-all-association-types command
+func createSyncAllAssociationTypesCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync-all-association-types",
+		Short: "Synchronizes every association type of the source",
+		Long: `Synchronizes every association type of the source Akeneo to the destination Akeneo,
+one association type at a time.
+
+Run it before migrating products, so that their associations only use types that
+exist in destination. An association type that fails is reported and does not
+stop the others; failed association types can be reprocessed with retry-failed.
+
+Example:
+  akeneo-migrator sync-all-association-types
+  akeneo-migrator sync-all-association-types --debug`,
+		Args:    cobra.NoArgs,
+		PreRunE: app.initialize,
+		RunE:    runSyncAllAssociationTypesCommand(app),
+	}
+
+	// Add debug flag
+	cmd.Flags().Bool("debug", false, "Enable debug mode to see the result of every association type")
+
+	return cmd
+}
+
+// runSyncAllAssociationTypesCommand executes the synchronization of every association type
+func runSyncAllAssociationTypesCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		// Get debug flag
+		debug, _ := cmd.Flags().GetBool("debug") //nolint:errcheck // flag is optional
+
+		fmt.Println("🚀 Starting synchronization of all association types")
+		if debug {
+			fmt.Println("🔍 Debug mode enabled")
+		}
+
+		// Execute synchronization using command bus
+		response, err := app.CommandBus.Dispatch(ctx, association_type_syncing_all.SyncAllAssociationTypesCommand{
+			Debug: debug,
+		})
+		if err != nil {
+			return fmt.Errorf("synchronization error: %w", err)
+		}
+
+		result, ok := response.Data.(*association_type_syncing_all.SyncResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		// Show per-type results
+		for _, typeResult := range result.Types {
+			switch {
+			case typeResult.Error != "":
+				fmt.Printf("   ❌ %s: %s\n", typeResult.Code, typeResult.Error)
+			case debug:
+				fmt.Printf("   ✅ %s\n", typeResult.Code)
+			}
+		}
+
+		// Show summary
+		fmt.Printf("\n📊 Association types: %d/%d synced\n", result.TypesSynced, len(result.Types))
+		if result.Success {
+			fmt.Println("✅ All association types synchronized successfully!")
+		} else {
+			fmt.Printf("⚠️  %d association types with errors; run retry-failed to reprocess them\n", len(result.FailedItems))
+		}
+
+		return nil
+	}
+}
+
+// createSyncCategoryCommand creates the sync-category command
+func createSyncCategoryCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync-category [code]",
+		Short: "Synchronizes a category by its code",
+		Long: `Synchronizes a single category from the source Akeneo to the destination Akeneo.
+
+Requires the category code as an argument.
+
+Example:
+  akeneo-migrator sync-category master
+  akeneo-migrator sync-category clothing --debug`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: app.initialize,
+		RunE:    runSyncCategoryCommand(app),
+	}
+
+	// Add debug flag
+	cmd.Flags().Bool("debug", false, "Enable debug mode to see category contents")
+
+	return cmd
+}
+
+// runSyncCategoryCommand executes the category synchronization logic
+func runSyncCategoryCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		code := args[0]
+		ctx := cmd.Context()
+
+		// Get debug flag
+		debug, _ := cmd.Flags().GetBool("debug") //nolint:errcheck // flag is optional
+
+		fmt.Printf("🚀 Starting synchronization for category: %s\n", code)
+		if debug {
+			fmt.Println("🔍 Debug mode enabled")
+		}
+
+		// Execute synchronization using command bus
+		response, err := app.CommandBus.Dispatch(ctx, category_syncing.SyncCategoryCommand{
+			Code:  code,
+			Debug: debug,
+		})
+		if err != nil {
+			return fmt.Errorf("synchronization error: %w", err)
+		}
+
+		result, ok := response.Data.(*category_syncing.SyncResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		// Show detected move
+		if result.Move != nil {
+			if result.Move.Applied {
+				fmt.Printf("🔀 Category moved: '%s' → '%s'\n", result.Move.FromParent, result.Move.ToParent)
+			} else {
+				fmt.Printf("⚠️  Category parent differs ('%s' in destination, '%s' in source); move not applied (sync.categoryMove = warn)\n",
+					result.Move.FromParent, result.Move.ToParent)
+			}
+
+			if result.Move.AffectedProductsError != "" {
+				fmt.Printf("   ⚠️  Could not list affected products: %s\n", result.Move.AffectedProductsError)
+			} else {
+				fmt.Printf("   📦 Products whose category path changes: %d\n", len(result.Move.AffectedProducts))
+				if debug {
+					for _, identifier := range result.Move.AffectedProducts {
+						fmt.Printf("      - %s\n", identifier)
+					}
+				}
+			}
+		}
+
+		// Show result
+		if result.Success {
+			fmt.Printf("\n✅ Category '%s' synchronized successfully!\n", result.Code)
+		} else {
+			fmt.Printf("❌ Failed to synchronize '%s': %s\n", result.Code, result.Error)
+		}
+
+		return nil
+	}
+}
+
+// createSyncCategoryTreeCommand creates the sync-category-tree command
+func createSyncCategoryTreeCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync-category-tree [root]",
+		Short: "Synchronizes a category and all its descendants",
+		Long: `Synchronizes a category and every category below it from the source Akeneo
+to the destination Akeneo.
+
+The whole tree is fetched from the source first and ordered from the parent of
+each category, so each category is written before its children and parents
+always exist in destination. Categories whose parent is not in the tree
+(orphaned) or whose ancestors loop (cyclic) are reported before anything is
+written, and the tree is left untouched. When a category fails, its
+descendants are skipped and reported; failed and skipped categories can be
+reprocessed in order with retry-failed.
+
+Example:
+  akeneo-migrator sync-category-tree master
+  akeneo-migrator sync-category-tree clothing --debug`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeCodes(categoryTreeCodes),
+		PreRunE:           app.initialize,
+		RunE:              runSyncCategoryTreeCommand(app),
+	}
+
+	// Add debug flag
+	cmd.Flags().Bool("debug", false, "Enable debug mode to see every synced category")
+
+	return cmd
+}
+
+// runSyncCategoryTreeCommand executes the category tree synchronization logic
+func runSyncCategoryTreeCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		root := args[0]
+		ctx := cmd.Context()
+
+		// Get debug flag
+		debug, _ := cmd.Flags().GetBool("debug") //nolint:errcheck // flag is optional
+
+		fmt.Printf("🚀 Starting synchronization for category tree: %s\n", root)
+		if debug {
+			fmt.Println("🔍 Debug mode enabled")
+		}
+
+		// Execute synchronization using command bus
+		response, err := app.CommandBus.Dispatch(ctx, category_syncing_tree.SyncCategoryTreeCommand{
+			Root:  root,
+			Debug: debug,
+		})
+		if err != nil {
+			return fmt.Errorf("synchronization error: %w", err)
+		}
+
+		result, ok := response.Data.(*category_syncing_tree.SyncResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		if len(result.Problems) > 0 {
+			fmt.Printf("❌ Category tree '%s' cannot be ordered, nothing was written:\n", result.Root)
+			for _, problem := range result.Problems {
+				fmt.Printf("   - %s: %s parent '%s'\n", problem.Code, problem.Reason, problem.Parent)
+			}
+			return nil
+		}
+
+		// Show per-node results; successful nodes only in debug mode
+		for _, node := range result.Nodes {
+			indent := strings.Repeat("  ", node.Depth)
+			switch {
+			case node.Skipped:
+				fmt.Printf("   %s⏭️  %s (skipped)\n", indent, node.Result.Code)
+			case !node.Result.Success:
+				fmt.Printf("   %s❌ %s: %s\n", indent, node.Result.Code, node.Result.Error)
+			case node.Result.Move != nil && node.Result.Move.Applied:
+				fmt.Printf("   %s🔀 %s (moved from '%s')\n", indent, node.Result.Code, node.Result.Move.FromParent)
+			case node.Result.Move != nil:
+				fmt.Printf("   %s⚠️  %s (parent differs, move not applied)\n", indent, node.Result.Code)
+			case debug:
+				fmt.Printf("   %s✅ %s\n", indent, node.Result.Code)
+			}
+		}
+
+		// Show summary
+		fmt.Println("\n📋 Synchronization summary:")
+		fmt.Printf("   ✅ Categories synced: %d\n", result.CategoriesSynced)
+		if result.Moves > 0 {
+			fmt.Printf("   🔀 Categories with a different parent: %d\n", result.Moves)
+		}
+		if result.CategoriesSkipped > 0 {
+			fmt.Printf("   ⏭️  Categories skipped: %d\n", result.CategoriesSkipped)
+		}
+
+		if result.Success {
+			fmt.Printf("\n✅ Category tree '%s' synchronized successfully!\n", result.Root)
+		} else {
+			fmt.Printf("\n⚠️  %d categories not synchronized; run retry-failed to reprocess them\n", len(result.FailedItems))
+		}
+
+		return nil
+	}
+}
+
+// createSyncFamilyCommand creates the sync-family command
+func createSyncFamilyCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync-family [code]",
+		Short: "Synchronizes a family by its code",
+		Long: `Synchronizes a single family from the source Akeneo to the destination Akeneo.
+
+Requires the family code as an argument.
+
+By default the family structure and all its variants are synchronized.
+Use --with-variants=false to sync only the structure, or --variants-only to
+sync only the variants of a family already migrated. --variant restricts the
+synced variants to the given codes.
+
+Example:
+  akeneo-migrator sync-family clothing
+  akeneo-migrator sync-family clothing --with-variants=false
+  akeneo-migrator sync-family clothing --variants-only --variant clothing_color_size
+  akeneo-migrator sync-family accessories --debug`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeCodes(familyCodes),
+		PreRunE:           app.initialize,
+		RunE:              runSyncFamilyCommand(app),
+	}
+
+	// Add flags
+	cmd.Flags().Bool("debug", false, "Enable debug mode to see family contents")
+	cmd.Flags().Bool("with-variants", true, "Also sync the family variants")
+	cmd.Flags().Bool("variants-only", false, "Sync only the family variants, not the family structure")
+	cmd.Flags().StringSlice("variant", nil, "Variant codes to sync (default: all variants)")
+
+	return cmd
+}
+
+// runSyncFamilyCommand executes the family synchronization logic
+func runSyncFamilyCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		code := args[0]
+		ctx := cmd.Context()
+
+		// Get debug flag
+		debug, _ := cmd.Flags().GetBool("debug") //nolint:errcheck // flag is optional
+
+		withVariants, _ := cmd.Flags().GetBool("with-variants") //nolint:errcheck // flag has default value
+		variantsOnly, _ := cmd.Flags().GetBool("variants-only") //nolint:errcheck // flag is optional
+		variants, _ := cmd.Flags().GetStringSlice("variant")    //nolint:errcheck // flag is optional
+
+		if !withVariants && (variantsOnly || len(variants) > 0) {
+			return errors.New("--with-variants=false cannot be combined with --variants-only or --variant")
+		}
+
+		fmt.Printf("🚀 Starting synchronization for family: %s\n", code)
+		if debug {
+			fmt.Println("🔍 Debug mode enabled")
+		}
+		if variantsOnly {
+			fmt.Println("📋 Syncing variants only")
+		} else if !withVariants {
+			fmt.Println("📋 Skipping variants")
+		}
+
+		// Execute synchronization using command bus
+		response, err := app.CommandBus.Dispatch(ctx, family_syncing.SyncFamilyCommand{
+			Code:         code,
+			SkipVariants: !withVariants,
+			VariantsOnly: variantsOnly,
+			Variants:     variants,
+			Debug:        debug,
+		})
+		if err != nil {
+			return fmt.Errorf("synchronization error: %w", err)
+		}
+
+		result, ok := response.Data.(*family_syncing.SyncResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		// Show result
+		if result.Success {
+			if result.FamilySynced {
+				fmt.Printf("\n✅ Family '%s' synchronized successfully!\n", result.Code)
+			} else {
+				fmt.Printf("\n✅ Variants of family '%s' synchronized successfully!\n", result.Code)
+			}
+			if result.DestCode != "" {
+				fmt.Printf("   🔀 Written to destination as: %s\n", result.DestCode)
+			}
+			if result.VariantsSynced > 0 {
+				fmt.Printf("   📋 Family variants synced: %d\n", result.VariantsSynced)
+			}
+			if len(result.VariantConflicts) > 0 {
+				fmt.Printf("   ❌ Breaking variant conflicts: %d (not synchronized)\n", len(result.VariantConflicts))
+				for _, conflict := range result.VariantConflicts {
+					fmt.Printf("      - %s: %s\n", conflict.Code, conflict.Reason)
+				}
+				fmt.Println("   💡 Resolve these conflicts in destination before syncing products of this family")
+			}
+			if len(result.VariantsErrors) > 0 {
+				fmt.Printf("   ⚠️  Variant errors: %d\n", len(result.VariantsErrors))
+				if debug {
+					for _, errMsg := range result.VariantsErrors {
+						fmt.Printf("      - %s\n", errMsg)
+					}
+				}
+			}
+		} else {
+			fmt.Printf("❌ Failed to synchronize '%s': %s\n", result.Code, result.Error)
+		}
+
+		return nil
+	}
+}
+
+// createSyncAllFamiliesCommand creates the sync-all-families command
+func createSyncAllFamiliesCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync-all-families",
+		Short: "Synchronizes every family of the source",
+		Long: `Synchronizes every family of the source Akeneo to the destination Akeneo,
+with its variants, one family at a time.
+
+Families are listed page by page, so the command works whatever the size of the
+catalog. A family that fails is reported and does not stop the others; failed
+families can be reprocessed with retry-failed.
+
+Example:
+  akeneo-migrator sync-all-families
+  akeneo-migrator sync-all-families --with-variants=false
+  akeneo-migrator sync-all-families --debug`,
+		Args:    cobra.NoArgs,
+		PreRunE: app.initialize,
+		RunE:    runSyncAllFamiliesCommand(app),
+	}
+
+	// Add flags
+	cmd.Flags().Bool("debug", false, "Enable debug mode to see the result of every family")
+	cmd.Flags().Bool("with-variants", true, "Also sync the family variants")
+
+	return cmd
+}
+
+// runSyncAllFamiliesCommand executes the synchronization of every family
+func runSyncAllFamiliesCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		// Get flags
+		debug, _ := cmd.Flags().GetBool("debug")                //nolint:errcheck // flag is optional
+		withVariants, _ := cmd.Flags().GetBool("with-variants") //nolint:errcheck // flag has default value
+
+		fmt.Println("🚀 Starting synchronization of all families")
+		if debug {
+			fmt.Println("🔍 Debug mode enabled")
+		}
+		if !withVariants {
+			fmt.Println("📋 Skipping variants")
+		}
+
+		// Execute synchronization using command bus
+		response, err := app.CommandBus.Dispatch(ctx, family_syncing_all.SyncAllFamiliesCommand{
+			SkipVariants: !withVariants,
+			Debug:        debug,
+		})
+		if err != nil {
+			return fmt.Errorf("synchronization error: %w", err)
+		}
+
+		result, ok := response.Data.(*family_syncing_all.SyncResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		// Show per-family results
+		for _, familyResult := range result.Families {
+			switch {
+			case familyResult.Error != "":
+				fmt.Printf("   ❌ %s: %s\n", familyResult.Code, familyResult.Error)
+			case len(familyResult.VariantsErrors) > 0 || len(familyResult.VariantConflicts) > 0:
+				fmt.Printf("   ⚠️  %s: %d variants synced, %d errors, %d conflicts\n", familyResult.Code,
+					familyResult.VariantsSynced, len(familyResult.VariantsErrors), len(familyResult.VariantConflicts))
+				if debug {
+					for _, errMsg := range familyResult.VariantsErrors {
+						fmt.Printf("      - %s\n", errMsg)
+					}
+					for _, conflict := range familyResult.VariantConflicts {
+						fmt.Printf("      - %s: %s\n", conflict.Code, conflict.Reason)
+					}
+				}
+			case debug:
+				fmt.Printf("   ✅ %s: %d variants synced\n", familyResult.Code, familyResult.VariantsSynced)
+			}
+		}
+
+		// Show summary
+		fmt.Printf("\n📊 Families: %d/%d synced", result.FamiliesSynced, len(result.Families))
+		if withVariants {
+			fmt.Printf(", %d variants synced", result.VariantsSynced)
+		}
+		fmt.Println()
+
+		if result.Success {
+			fmt.Println("✅ All families synchronized successfully!")
+		} else {
+			fmt.Printf("⚠️  %d families with errors; run retry-failed to reprocess them\n", len(result.FailedItems))
+		}
+
+		return nil
+	}
+}
+
+// createSyncChannelCommand creates the sync-channel command
+func createSyncChannelCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync-channel [code]",
+		Short: "Synchronizes a channel by its code",
+		Long: `Synchronizes a single channel from the source Akeneo to the destination Akeneo.
+
+The category tree is remapped through the category mappings of the settings file,
+and the category tree, every locale and every currency of the channel are checked
+against the destination before writing. With --auto-deps, a missing category tree
+is synchronized from the source first (parents first, as sync-category-tree does),
+and locales that exist but are not activated are accepted, since saving the
+channel activates them. Currencies cannot be activated through the API.
+
+Requires the channel code as an argument.
+
+Example:
+  akeneo-migrator sync-channel ecommerce
+  akeneo-migrator sync-channel mobile --auto-deps`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeCodes(channelCodes),
+		PreRunE:           app.initialize,
+		RunE:              runSyncChannelCommand(app),
+	}
+
+	// Add flags
+	cmd.Flags().Bool("auto-deps", false, "Sync the missing category tree and activate missing locales in destination (default from sync.autoDeps)")
+
+	return cmd
+}
+
+// runSyncChannelCommand executes the channel synchronization logic
+func runSyncChannelCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		code := args[0]
+		ctx := cmd.Context()
+
+		// Get flags
+		autoDeps, _ := cmd.Flags().GetBool("auto-deps") //nolint:errcheck // flag is optional
+		autoDeps = autoDeps || app.Config.Sync.AutoDeps
+
+		fmt.Printf("🚀 Starting synchronization for channel: %s\n", code)
+
+		// Execute synchronization using command bus
+		response, err := app.CommandBus.Dispatch(ctx, channel_syncing.SyncChannelCommand{
+			Code:     code,
+			AutoDeps: autoDeps,
+		})
+
+		if err != nil {
+			return fmt.Errorf("synchronization error: %w", err)
+		}
+
+		result, ok := response.Data.(*channel_syncing.SyncResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		if len(result.MissingDependencies) > 0 {
+			fmt.Println("❌ Missing dependencies in destination:")
+			for _, dependency := range result.MissingDependencies {
+				fmt.Printf("   - %s\n", dependency)
+			}
+			if !autoDeps {
+				fmt.Println("💡 Run with --auto-deps to sync the category tree and activate existing locales")
+			}
+			return nil
+		}
+
+		// Show result
+		if result.Success {
+			fmt.Printf("\n✅ Channel '%s' synchronized successfully!\n", result.Code)
+			if result.DestCode != "" {
+				fmt.Printf("   🔀 Written to destination as: %s\n", result.DestCode)
+			}
+			if result.CategoryTreeMapped {
+				fmt.Printf("   🌳 Category tree remapped to: %s\n", result.CategoryTree)
+			}
+			if result.CategoryTreeSynced {
+				fmt.Printf("   🌳 Category tree synced: %s\n", result.CategoryTree)
+			}
+			if len(result.ActivatedLocales) > 0 {
+				fmt.Printf("   🌐 Locales activated: %s\n", strings.Join(result.ActivatedLocales, ", "))
+			}
+		} else {
+			fmt.Printf("❌ Failed to synchronize '%s': %s\n", result.Code, result.Error)
+		}
+
+		return nil
+	}
+}
+
+// createSyncCurrenciesCommand creates the sync-currencies command
+func createSyncCurrenciesCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync-currencies",
+		Short: "Reconciles the currencies enabled in source with the destination",
+		Long: `Compares the currencies enabled in the source Akeneo with the destination Akeneo.
+
+Price values in a currency that is not enabled in destination are rejected, so run
+this command before migrating products. The Akeneo API exposes currencies read-only:
+the currencies listed as disabled or unknown must be enabled in the destination
+settings, then checked again with retry-failed or by running this command again.
+
+Example:
+  akeneo-migrator sync-currencies
+  akeneo-migrator sync-currencies --debug`,
+		Args:    cobra.NoArgs,
+		PreRunE: app.initialize,
+		RunE:    runSyncCurrenciesCommand(app),
+	}
+
+	// Add debug flag
+	cmd.Flags().Bool("debug", false, "Enable debug mode to list every compared currency")
+
+	return cmd
+}
+
+// runSyncCurrenciesCommand executes the currency reconciliation logic
+func runSyncCurrenciesCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		// Get debug flag
+		debug, _ := cmd.Flags().GetBool("debug") //nolint:errcheck // flag is optional
+
+		fmt.Println("🚀 Reconciling currencies")
+		if debug {
+			fmt.Println("🔍 Debug mode enabled")
+		}
+
+		// Execute reconciliation using command bus
+		response, err := app.CommandBus.Dispatch(ctx, currency_syncing.SyncCurrenciesCommand{Debug: debug})
+		if err != nil {
+			return fmt.Errorf("synchronization error: %w", err)
+		}
+
+		result, ok := response.Data.(*currency_syncing.SyncResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		if debug {
+			fmt.Printf("   ✅ Enabled in both instances: %s\n", strings.Join(result.Enabled, ", "))
+			if len(result.DestOnly) > 0 {
+				fmt.Printf("   ℹ️  Enabled only in destination: %s\n", strings.Join(result.DestOnly, ", "))
+			}
+		}
+
+		// Show result
+		if result.Success {
+			fmt.Printf("\n✅ All %d currencies enabled in source are enabled in destination\n", len(result.Enabled))
+			return nil
+		}
+
+		fmt.Println("\n❌ Currencies to enable in destination before migrating prices:")
+		for _, code := range result.Disabled {
+			fmt.Printf("   - %s (not enabled)\n", code)
+		}
+		for _, code := range result.Unknown {
+			fmt.Printf("   - %s (unknown)\n", code)
+		}
+		fmt.Println("💡 The Akeneo API cannot enable currencies; enable them in the destination settings")
+
+		return nil
+	}
+}
+
+// createSyncMeasurementFamiliesCommand creates the sync-measurement-families command
+func createSyncMeasurementFamiliesCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync-measurement-families [codes...]",
+		Short: "Reconciles the measurement families and units with the destination",
+		Long: `Creates the measurement families missing in the destination Akeneo and adds the
+units missing in the existing ones.
+
+Metric values are rejected when their unit does not exist in destination, so run this
+command before synchronizing metric attributes and products. Akeneo does not allow
+changing the standard unit of a family: families whose standard unit differs are
+reported and not written. Units that convert differently are kept as they are in
+destination and listed as conflicts.
+
+Without arguments every measurement family of the source is reconciled.
+
+Example:
+  akeneo-migrator sync-measurement-families
+  akeneo-migrator sync-measurement-families Length Weight --debug`,
+		Args:    cobra.ArbitraryArgs,
+		PreRunE: app.initialize,
+		RunE:    runSyncMeasurementFamiliesCommand(app),
+	}
+
+	// Add debug flag
+	cmd.Flags().Bool("debug", false, "Enable debug mode to list the families already up to date")
+
+	return cmd
+}
+
+// runSyncMeasurementFamiliesCommand executes the measurement family synchronization logic
+func runSyncMeasurementFamiliesCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		// Get debug flag
+		debug, _ := cmd.Flags().GetBool("debug") //nolint:errcheck // flag is optional
+
+		fmt.Println("🚀 Reconciling measurement families")
+		if debug {
+			fmt.Println("🔍 Debug mode enabled")
+		}
+
+		// Execute synchronization using command bus
+		response, err := app.CommandBus.Dispatch(ctx, measurement_family_syncing.SyncMeasurementFamiliesCommand{
+			Codes: args,
+			Debug: debug,
+		})
+		if err != nil {
+			return fmt.Errorf("synchronization error: %w", err)
+		}
+
+		result, ok := response.Data.(*measurement_family_syncing.SyncResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		for _, family := range result.Families {
+			switch {
+			case family.Error != "":
+				fmt.Printf("   ❌ %s: %s\n", family.Code, family.Error)
+			case family.Created:
+				fmt.Printf("   ✅ %s created with %d units\n", family.Code, len(family.AddedUnits))
+			case len(family.AddedUnits) > 0:
+				fmt.Printf("   ✅ %s: added %s\n", family.Code, strings.Join(family.AddedUnits, ", "))
+			case debug:
+				fmt.Printf("   ✔️  %s is up to date\n", family.Code)
+			}
+
+			if len(family.Conflicts) > 0 {
+				fmt.Printf("   ⚠️  %s: units converting differently in destination: %s\n", family.Code, strings.Join(family.Conflicts, ", "))
+			}
+		}
+
+		// Show result
+		if result.Success {
+			fmt.Printf("\n✅ Measurement families reconciled: %d written, %d checked\n", result.Synced(), len(result.Families))
+		} else {
+			fmt.Printf("\n❌ %d measurement families could not be reconciled\n", len(result.Failures()))
+		}
+
+		return nil
+	}
+}
+
+// createSyncStructureCommand creates the sync-structure command
+func createSyncStructureCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync-structure",
+		Short: "Migrates the whole catalog structure in dependency order",
+		Long: `Migrates the complete catalog structure from the source Akeneo to the destination
+Akeneo, as the first step of an instance migration. Every item of the source is
+synchronized, one kind after the other, so each kind only depends on the ones before it:
+
+  1. Measurement families
+  2. Reference entities, with their attributes and records (skipped when an
+     instance does not support them)
+  3. Attribute groups, attributes and options
+  4. Category trees, parents first
+  5. Channels
+  6. Families and family variants
+  7. Association types
+
+An item that fails is reported and does not stop the migration; failed items are
+queued together and can be reprocessed with retry-failed. A step that fails as a
+whole (e.g. its items cannot be listed) stops the migration, since the next steps
+depend on it.
+
+Example:
+  akeneo-migrator sync-structure
+  akeneo-migrator sync-structure --auto-deps
+  akeneo-migrator plan structure.json sync-structure`,
+		Args:    cobra.NoArgs,
+		PreRunE: app.initialize,
+		RunE:    runSyncStructureCommand(app),
+	}
+
+	// Add flags
+	cmd.Flags().Bool("debug", false, "Enable debug mode")
+	cmd.Flags().Bool("auto-deps", false, "Activate the locales used by channels in destination (default from sync.autoDeps)")
+
+	return cmd
+}
+
+// runSyncStructureCommand executes the migration of the catalog structure
+func runSyncStructureCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		// Get flags
+		debug, _ := cmd.Flags().GetBool("debug")        //nolint:errcheck // flag is optional
+		autoDeps, _ := cmd.Flags().GetBool("auto-deps") //nolint:errcheck // flag is optional
+		autoDeps = autoDeps || app.Config.Sync.AutoDeps
+
+		fmt.Println("🚀 Starting migration of the catalog structure")
+		if debug {
+			fmt.Println("🔍 Debug mode enabled")
+		}
+
+		step := 0
+		progress := func(s structure_syncing.Step, commands int) {
+			step++
+			fmt.Printf("\n📦 Step %d/%d: %s (%d commands)\n", step, len(structure_syncing.Order), s, commands)
+		}
+
+		// Execute synchronization using command bus
+		response, err := app.CommandBus.Dispatch(ctx, structure_syncing.SyncStructureCommand{
+			AutoDeps: autoDeps,
+			Progress: progress,
+			Debug:    debug,
+		})
+		if err != nil {
+			return fmt.Errorf("synchronization error: %w", err)
+		}
+
+		result, ok := response.Data.(*structure_syncing.SyncResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		// Show per-step results
+		fmt.Println("\n📊 Structure migration:")
+		for _, stepResult := range result.Steps {
+			switch {
+			case stepResult.Skipped:
+				fmt.Printf("   ⏭️  %s: skipped\n", stepResult.Step)
+			case stepResult.Error != "":
+				fmt.Printf("   ❌ %s: %s\n", stepResult.Step, stepResult.Error)
+			case stepResult.Failed > 0:
+				fmt.Printf("   ⚠️  %s: %d synced, %d failed\n", stepResult.Step, stepResult.Synced, stepResult.Failed)
+			default:
+				fmt.Printf("   ✅ %s: %d synced\n", stepResult.Step, stepResult.Synced)
+			}
+		}
+
+		switch {
+		case result.Stopped != "":
+			fmt.Printf("\n❌ Migration stopped at %s; the next steps depend on it\n", result.Stopped)
+		case len(result.Failures()) > 0:
+			fmt.Printf("\n⚠️  %d items with errors; run retry-failed to reprocess them\n", len(result.Failures()))
+		default:
+			fmt.Println("\n✅ Catalog structure migrated successfully!")
+		}
+
+		return nil
+	}
+}
+
+// createMigrateCommand creates the migrate command
+func createMigrateCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrates the whole catalog in dependency order",
+		Long: `Migrates the whole catalog from the source Akeneo to the destination Akeneo, each
+phase only depending on the ones before it:
+
+  1. Structure, as sync-structure does: measurement families, reference entities
+     with their records, attributes, category trees, channels, families and
+     association types
+  2. Assets: every asset family with its assets (skipped when an instance does
+     not support the Asset Manager)
+  3. Products: every product model and product, hierarchy by hierarchy
+
+An item that fails is reported and does not stop the migration; failed items are
+queued together and can be reprocessed with retry-failed. A phase that fails as a
+whole or is interrupted stops the migration.
+
+A checkpoint is saved in the state store after each completed phase. Run the
+command again with --resume to skip the phases completed by the previous,
+unfinished migration; the report covers the resumed phases as well.
+
+Example:
+  akeneo-migrator migrate
+  akeneo-migrator migrate --resume
+  akeneo-migrator migrate --auto-deps --report migration.json`,
+		Args:    cobra.NoArgs,
+		PreRunE: app.initialize,
+		RunE:    runMigrateCommand(app),
+	}
+
+	// Add flags
+	cmd.Flags().Bool("debug", false, "Enable debug mode")
+	cmd.Flags().Bool("resume", false, "Skip the phases completed by the previous, unfinished migration")
+	cmd.Flags().Bool("auto-deps", false, "Activate the locales used by channels in destination (default from sync.autoDeps)")
+	cmd.Flags().Bool("values-only", false, "Only write the values of the products and models existing in destination")
+
+	return cmd
+}
+
+// runMigrateCommand executes the migration of the whole catalog
+func runMigrateCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		// Get flags
+		debug, _ := cmd.Flags().GetBool("debug")            //nolint:errcheck // flag is optional
+		resume, _ := cmd.Flags().GetBool("resume")          //nolint:errcheck // flag is optional
+		autoDeps, _ := cmd.Flags().GetBool("auto-deps")     //nolint:errcheck // flag is optional
+		valuesOnly, _ := cmd.Flags().GetBool("values-only") //nolint:errcheck // flag is optional
+		autoDeps = autoDeps || app.Config.Sync.AutoDeps
+
+		fmt.Println("🚀 Starting migration of the whole catalog")
+		if debug {
+			fmt.Println("🔍 Debug mode enabled")
+		}
+		if resume {
+			fmt.Println("⏯️  Resuming the previous migration")
+		}
+
+		progress := func(phase migration_migrating.Phase, commands int) {
+			fmt.Printf("\n🚚 Phase: %s (%d commands)\n", phase, commands)
+		}
+
+		// Execute migration using command bus
+		response, err := app.CommandBus.Dispatch(ctx, migration_migrating.MigrateCommand{
+			Resume:     resume,
+			AutoDeps:   autoDeps,
+			ValuesOnly: valuesOnly,
+			Progress:   progress,
+			Debug:      debug,
+		})
+		if err != nil {
+			return fmt.Errorf("migration error: %w", err)
+		}
+
+		result, ok := response.Data.(*migration_migrating.SyncResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		// Show the consolidated report
+		fmt.Printf("\n📊 Migration started at %s:\n", result.StartedAt.Local().Format("2006-01-02 15:04:05"))
+		for _, phaseResult := range result.Phases {
+			duration := phaseResult.Duration.Round(time.Second)
+			resumed := ""
+			if phaseResult.Resumed {
+				resumed = ", completed by a previous run"
+			}
+
+			switch {
+			case phaseResult.Skipped:
+				fmt.Printf("   ⏭️  %s: skipped%s\n", phaseResult.Phase, resumed)
+			case phaseResult.Error != "":
+				fmt.Printf("   ❌ %s: %s\n", phaseResult.Phase, phaseResult.Error)
+			case phaseResult.Failed > 0:
+				fmt.Printf("   ⚠️  %s: %d synced, %d failed (%s%s)\n", phaseResult.Phase, phaseResult.Synced, phaseResult.Failed, duration, resumed)
+			default:
+				fmt.Printf("   ✅ %s: %d synced (%s%s)\n", phaseResult.Phase, phaseResult.Synced, duration, resumed)
+			}
+		}
+
+		switch {
+		case result.Stopped != "":
+			fmt.Printf("\n❌ Migration stopped at %s; run migrate --resume to continue from there\n", result.Stopped)
+		case len(result.Failures()) > 0:
+			fmt.Printf("\n⚠️  %d items with errors; run retry-failed to reprocess them\n", len(result.Failures()))
+		default:
+			fmt.Println("\n✅ Catalog migrated successfully!")
+		}
+
+		return nil
+	}
+}
+
+// createSyncUpdatedProductsCommand creates the sync-updated-products command
+func createSyncUpdatedProductsCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync-updated-products [date]",
+		Short: "Synchronizes products updated since a specific date",
+		Long: `Synchronizes all products and their complete hierarchies that have been updated since a specific date.
+
+The date should be in ISO 8601 format: YYYY-MM-DDTHH:MM:SS
+
+For each updated product or model, the entire hierarchy will be synchronized:
+- If a model is updated, syncs the model and all its variants
+- If a product is updated, syncs its parent hierarchy (common → models → variants)
+
+Use --values-only to refresh enrichment data on a live destination without
+touching categories, groups, associations or the enabled flag.
+
+Use --until to replay a time window: only items updated after the start date
+and up to the end date (included) are synchronized.
+
+Use --since-last-run to pick up where the last successful run finished, up to
+now. The date is then only needed for the first run. A run is recorded in the
+state store when every item was written; otherwise the next one starts from the
+same date again.
+
+Example:
+  akeneo-migrator sync-updated-products 2024-01-01T00:00:00
+  akeneo-migrator sync-updated-products 2024-03-01T02:00:00 --until 2024-03-01T04:30:00
+  akeneo-migrator sync-updated-products 2024-01-01T00:00:00 --since-last-run
+  akeneo-migrator sync-updated-products 2024-01-01T00:00:00 --values-only
+  akeneo-migrator sync-updated-products 2024-01-15T10:30:00 --debug`,
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: app.initialize,
+		RunE:    runSyncUpdatedProductsCommand(app),
+	}
+
+	// Add flags
+	cmd.Flags().Bool("debug", false, "Enable debug mode to see detailed sync information")
+	cmd.Flags().Bool("values-only", false, "Only send values for items that already exist in destination")
+	cmd.Flags().String("until", "", "End of the time window (ISO 8601), included")
+	cmd.Flags().Bool("since-last-run", false, "Start where the last successful run finished, the date only being used for the first run")
+	cmd.Flags().String("on-conflict", "", conflictFlagUsage)
+	addAttributeFilterFlags(cmd)
+	addRangeFlags(cmd, "updated products and models")
+
+	return cmd
+}
+
+// runSyncUpdatedProductsCommand executes the updated products synchronization logic
+func runSyncUpdatedProductsCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		// Get flags
+		debug, _ := cmd.Flags().GetBool("debug")                 //nolint:errcheck // flag is optional
+		valuesOnly, _ := cmd.Flags().GetBool("values-only")      //nolint:errcheck // flag is optional
+		updatedUntil, _ := cmd.Flags().GetString("until")        //nolint:errcheck // flag is optional
+		sinceLastRun, _ := cmd.Flags().GetBool("since-last-run") //nolint:errcheck // flag is optional
+
+		updatedSince := ""
+		if len(args) > 0 {
+			updatedSince = args[0]
+		}
+
+		switch {
+		case sinceLastRun && updatedUntil != "":
+			return errors.New("--since-last-run syncs up to now and cannot be combined with --until")
+		case sinceLastRun:
+			fmt.Println("🚀 Starting synchronization of products updated since the last successful run")
+		case updatedSince == "":
+			return errors.New("pass the date to sync products updated since, or --since-last-run")
+		case updatedUntil != "":
+			fmt.Printf("🚀 Starting synchronization of products updated between %s and %s\n", updatedSince, updatedUntil)
+		default:
+			fmt.Printf("🚀 Starting synchronization of products updated since: %s\n", updatedSince)
+		}
+		if debug {
+			fmt.Println("🔍 Debug mode enabled")
+		}
+		if valuesOnly {
+			fmt.Println("📝 Values-only mode: existing items only receive their values")
+		}
+		onConflict, err := conflictStrategyFlag(cmd)
+		if err != nil {
+			return err
+		}
+		selection, err := rangeFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		// Execute synchronization using command bus
+		response, err := app.CommandBus.Dispatch(ctx, product_syncing_since.SyncProductsSinceCommand{
+			UpdatedSince: updatedSince,
+			UpdatedUntil: updatedUntil,
+			SinceLastRun: sinceLastRun,
+			ValuesOnly:   valuesOnly,
+			OnConflict:   onConflict,
+			Range:        selection,
+			Debug:        debug,
+		})
+		if err != nil {
+			return fmt.Errorf("synchronization error: %w", err)
+		}
 
-	// 9. Create application with dependencies
-	app := &Application{
-		Config:     cfg,
-		CommandBus: commandBus,
-	}
-
-	// 10. Create root command
-	rootCmd := &cobra.Command{
-		Use:   "akeneo-migrator",
-		Short: "CLI tool to migrate data between Akeneo instances",
-		Long: `akeneo-migrator is a CLI tool that allows you to synchronize data
-between different Akeneo PIM instances, including Reference Entities,
-products, categories and other elements.`,
-	}
-
-	// 11. Add commands
-	syncCmd := createSyncCommand(app)
-	rootCmd.AddCommand(syncCmd)
-
-	syncProductCmd := createSyncProductCommand(app)
-	rootCmd.AddCommand(syncProductCmd)
-
-	syncAttributeCmd := createSyncAttributeCommand(app)
-	rootCmd.AddCommand(syncAttributeCmd)
+		result, ok := response.Data.(*product_syncing_since.SyncResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
 
-	syncCategoryCmd := createSyncCategoryCommand(app)
-	rootCmd.AddCommand(syncCategoryCmd)
+		// Show result
+		fmt.Println("\n📋 Synchronization Summary:")
+		fmt.Printf("   📅 Updated since: %s\n", result.UpdatedSince)
+		if result.UpdatedUntil != "" {
+			fmt.Printf("   📅 Updated until: %s\n", result.UpdatedUntil)
+		}
+		fmt.Printf("   📦 Models synced: %d\n", result.ModelsSynced)
+		fmt.Printf("   📦 Products synced: %d\n", result.ProductsSynced)
+		fmt.Printf("   📊 Total synced: %d\n", result.TotalSynced)
+		printConflicts(result.Conflicts)
+		printMissingTargets(result.MissingTargets)
+		printDroppedAttributes(result.DroppedAttributes)
+		printUnchanged(result.Unchanged)
 
-	syncFamilyCmd := createSyncFamilyCommand(app)
-	rootCmd.AddCommand(syncFamilyCmd)
+		if len(result.Errors) > 0 {
+			fmt.Printf("   ⚠️  Errors: %d\n", len(result.Errors))
+			if debug {
+				fmt.Println("\n❌ Errors encountered:")
+				for _, errMsg := range result.Errors {
+					fmt.Printf("   - %s\n", errMsg)
+				}
+			}
+		}
 
-	syncUpdatedProductsCmd := createSyncUpdatedProductsCommand(app)
-	rootCmd.AddCommand(syncUpdatedProductsCmd)
+		if sinceLastRun {
+			if result.Recorded {
+				fmt.Printf("   🔖 Next --since-last-run starts at: %s\n", result.UpdatedUntil)
+			} else {
+				fmt.Printf("   🔖 Run not recorded, the next --since-last-run starts at %s again\n", result.UpdatedSince)
+			}
+		}
 
-	webCmd := createWebCommand(app)
-	rootCmd.AddCommand(webCmd)
+		if result.Success {
+			fmt.Println("\n✅ Synchronization completed successfully!")
+		} else {
+			fmt.Println("\n⚠️  Synchronization completed with errors")
+		}
 
-	// 12. Execute root command
-	return rootCmd.Execute()
+		return nil
+	}
 }
 
-// createSyncCommand creates the sync command
-func createSyncCommand(app *Application) *cobra.Command {
+// createSyncProductsBySearchCommand creates the sync-products command
+func createSyncProductsBySearchCommand(app *Application) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "sync [entity-name]",
-		Short: "Synchronizes all records from a specific Reference Entity",
-		Long: `Synchronizes all records from a Reference Entity from the source Akeneo 
-to the destination Akeneo. Requires the entity name as an argument.
+		Use:   "sync-products",
+		Short: "Synchronizes the products matching a search filter",
+		Long: `Synchronizes the products of the source matching a search filter, written in the
+search syntax of the Akeneo API, so any subset of the catalog can be migrated.
+
+The filter is sent as is: every property and operator supported by the source
+instance can be used, and localizable or scopable filters take their "locale" and
+"scope" in the condition. Only the matching products are written, in batches,
+without their hierarchy: parent models must already exist in the destination.
 
 Example:
-  akeneo-migrator sync brands
-  akeneo-migrator sync brands --debug`,
-		Args: cobra.ExactArgs(1),
-		Run:  runSyncCommand(app),
+  akeneo-migrator sync-products --search '{"enabled":[{"operator":"=","value":true}]}'
+  akeneo-migrator sync-products --search '{"family":[{"operator":"IN","value":["shoes"]}],"completeness":[{"operator":"=","value":100,"scope":"ecommerce"}]}'
+  akeneo-migrator sync-products --search '{"categories":[{"operator":"IN_CHILDREN","value":["master"]}]}' --values-only`,
+		Args:    cobra.NoArgs,
+		PreRunE: app.initialize,
+		RunE:    runSyncProductsBySearchCommand(app),
 	}
 
-	// Add debug mode flag
-	cmd.Flags().Bool("debug", false, "Enable debug mode to see record contents")
+	// Add flags
+	cmd.Flags().String("search", "", "Product search filter in the JSON syntax of the Akeneo API (required)")
+	cmd.Flags().Bool("debug", false, "Enable debug mode to see detailed sync information")
+	cmd.Flags().Bool("values-only", false, "Only send values for items that already exist in destination")
+	cmd.Flags().String("on-conflict", "", conflictFlagUsage)
+	addAttributeFilterFlags(cmd)
+	addRangeFlags(cmd, "matching products")
+	_ = cmd.MarkFlagRequired("search")
 
 	return cmd
 }
 
-// runSyncCommand executes the synchronization logic
-func runSyncCommand(app *Application) func(cmd *cobra.Command, args []string) {
-	return func(cmd *cobra.Command, args []string) {
-		entityName := args[0]
-		ctx := context.Background()
+// runSyncProductsBySearchCommand executes the synchronization of the products matching a search filter
+func runSyncProductsBySearchCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
 
-		// Get debug flag
-		debug, _ := cmd.Flags().GetBool("debug") //nolint:errcheck // flag is optional
+		// Get flags
+		search, _ := cmd.Flags().GetString("search")        //nolint:errcheck // flag is required
+		debug, _ := cmd.Flags().GetBool("debug")            //nolint:errcheck // flag is optional
+		valuesOnly, _ := cmd.Flags().GetBool("values-only") //nolint:errcheck // flag is optional
 
-		fmt.Printf("🚀 Starting synchronization for entity: %s\n", entityName)
+		fmt.Printf("🚀 Starting synchronization of the products matching: %s\n", search)
 		if debug {
 			fmt.Println("🔍 Debug mode enabled")
 		}
+		if valuesOnly {
+			fmt.Println("📝 Values-only mode: existing items only receive their values")
+		}
+		onConflict, err := conflictStrategyFlag(cmd)
+		if err != nil {
+			return err
+		}
+		selection, err := rangeFlag(cmd)
+		if err != nil {
+			return err
+		}
 
 		// Execute synchronization using command bus
-		fmt.Printf("📋 Synchronizing Reference Entity '%s'...\n", entityName)
-		fmt.Println("   1️⃣  Syncing entity definition...")
-		fmt.Println("   2️⃣  Syncing attributes...")
-		fmt.Println("   3️⃣  Syncing records...")
-
-		response, err := app.CommandBus.Dispatch(ctx, syncing.SyncReferenceEntityCommand{
-			EntityName: entityName,
+		response, err := app.CommandBus.Dispatch(ctx, product_syncing_search.SyncProductsBySearchCommand{
+			Search:     search,
+			ValuesOnly: valuesOnly,
+			OnConflict: onConflict,
+			Range:      selection,
 			Debug:      debug,
 		})
 		if err != nil {
-			log.Printf("❌ Synchronization error: %v\n", err)
-			return
+			return fmt.Errorf("synchronization error: %w", err)
 		}
 
-		result, ok := response.Data.(*syncing.SyncResult)
+		result, ok := response.Data.(*product_syncing_search.SyncResult)
 		if !ok {
-			log.Printf("❌ Invalid response type\n")
-			return
+			return errors.New("invalid response type")
 		}
 
-		fmt.Printf("📊 Found %d records to synchronize\n", result.TotalRecords)
+		// Show result
+		fmt.Println("\n📋 Synchronization Summary:")
+		fmt.Printf("   🔎 Matching products: %d\n", result.Matched)
+		fmt.Printf("   📦 Products synced: %d\n", result.ProductsSynced)
+		printConflicts(result.Conflicts)
+		printMissingTargets(result.MissingTargets)
+		printDroppedAttributes(result.DroppedAttributes)
+		printUnchanged(result.Unchanged)
 
-		// Show progress for each record
 		if debug {
-			for _, syncErr := range result.Errors {
-				fmt.Printf("❌ Error in record '%s': %s\n", syncErr.Code, syncErr.Message)
+			for _, failure := range result.FailedItems {
+				fmt.Printf("❌ Error in product '%s': %s\n", failure.Code, failure.Error)
 			}
 		}
 
-		// Final summary
-		fmt.Println("\n📋 Synchronization summary:")
-		fmt.Printf("   ✅ Successfully synchronized records: %d\n", result.SuccessCount)
-		fmt.Printf("   ❌ Records with errors: %d\n", result.ErrorCount)
-		fmt.Printf("   📊 Total processed: %d\n", result.TotalRecords)
-
-		if result.ErrorCount > 0 {
-			fmt.Println("\n⚠️  Synchronization completed with some errors.")
-			if !debug {
-				fmt.Println("💡 Run with --debug to see error details")
-			}
+		if result.Success {
+			fmt.Println("\n✅ Synchronization completed successfully!")
 		} else {
-			fmt.Println("\n🎉 Synchronization completed successfully!")
+			fmt.Printf("\n⚠️  Synchronization completed with %d errors\n", len(result.FailedItems))
 		}
-	}
-}
 
-// setupDefaultEnvironmentVariables sets up default environment variables
-func setupDefaultEnvironmentVariables() {
-	if os.Getenv("ENVIRONMENT") == "" {
-		_ = os.Setenv("ENVIRONMENT", "local")
-	}
-	if os.Getenv("CONFIG_PATH") == "" {
-		_ = os.Setenv("CONFIG_PATH", "akeneo-migrator")
+		return nil
 	}
 }
 
-// createSyncProductCommand creates the sync-product command
-func createSyncProductCommand(app *Application) *cobra.Command {
+// createSyncProductsFromFileCommand creates the sync-products-from-file command
+func createSyncProductsFromFileCommand(app *Application) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "sync-product [identifier]",
-		Short: "Synchronizes a product hierarchy by its common identifier",
-		Long: `Synchronizes a complete product hierarchy from the source Akeneo to the destination Akeneo.
+		Use:   "sync-products-from-file [file]",
+		Short: "Synchronizes the product hierarchies listed in a file",
+		Long: `Synchronizes the hierarchy of every product or product model identifier listed in
+a file, as sync-product does for a single identifier. Several hierarchies are
+synchronized at the same time, 4 unless --workers or sync.workers is set; tune it to
+the rate limits of the instances.
 
-For SIMPLE products: Common → Child Products
-For CONFIGURABLE products: Common → Models → Variant Products
+The format of the file depends on its extension:
+  .json  an array of identifiers, or of objects with an "identifier" field
+  .csv   the identifier, sku or code column, or the first column without header;
+         "," or ";" separated, so Akeneo exports can be used as is
+  other  one identifier per line; blank lines and lines starting with # are skipped
 
-Requires the common product/model identifier as an argument.
+Repeated identifiers are synchronized once. Identifiers that are not found and the
+items that fail are queued for retry-failed; add --failure-manifest to also write
+them to a file.
 
 Example:
-  akeneo-migrator sync-product COMMON-001
-  akeneo-migrator sync-product COMMON-001 --debug`,
-		Args: cobra.ExactArgs(1),
-		Run:  runSyncProductCommand(app),
+  akeneo-migrator sync-products-from-file identifiers.txt
+  akeneo-migrator sync-products-from-file export.csv --workers 8 --values-only
+  akeneo-migrator sync-products-from-file skus.json --failure-manifest reports/failures.json
+  akeneo-migrator sync-products-from-file identifiers.txt --preflight
+  akeneo-migrator sync-products-from-file identifiers.txt --with-dependencies`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: app.initialize,
+		RunE:    runSyncProductsFromFileCommand(app),
 	}
 
 	// Add flags
-	cmd.Flags().Bool("debug", false, "Enable debug mode to see product contents")
+	cmd.Flags().Bool("debug", false, "Enable debug mode to see detailed sync information")
+	cmd.Flags().Bool("values-only", false, "Only send values for items that already exist in destination")
+	cmd.Flags().String("on-conflict", "", conflictFlagUsage)
+	cmd.Flags().Bool("preflight", false, preflightFlagUsage)
+	cmd.Flags().Bool("with-dependencies", false, withDependenciesFlagUsage)
+	addAttributeFilterFlags(cmd)
+	addRangeFlags(cmd, "listed identifiers")
 
 	return cmd
 }
 
-// runSyncProductCommand executes the product synchronization logic
-func runSyncProductCommand(app *Application) func(cmd *cobra.Command, args []string) {
-	return func(cmd *cobra.Command, args []string) {
-		identifier := args[0]
-		ctx := context.Background()
+// runSyncProductsFromFileCommand executes the synchronization of the product hierarchies listed in a file
+func runSyncProductsFromFileCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		path := args[0]
+		ctx := cmd.Context()
 
-		// Get flags
-		debug, _ := cmd.Flags().GetBool("debug") //nolint:errcheck // flag is optional
+		// Get flags; --workers is resolved with the configuration
+		debug, _ := cmd.Flags().GetBool("debug")            //nolint:errcheck // flag is optional
+		valuesOnly, _ := cmd.Flags().GetBool("values-only") //nolint:errcheck // flag is optional
+		workers := app.Config.Sync.Workers
+		if workers < 1 {
+			workers = product_syncing_file.DefaultWorkers
+		}
 
-		fmt.Printf("🚀 Starting synchronization for product: %s\n", identifier)
+		onConflict, err := conflictStrategyFlag(cmd)
+		if err != nil {
+			return err
+		}
+		if onConflict == conflict.Interactive && workers > 1 {
+			// Questions from several workers would be mixed up in the terminal
+			fmt.Println("💬 Interactive conflict resolution: hierarchies are synchronized one at a time")
+			workers = 1
+		}
+
+		fmt.Printf("🚀 Starting synchronization of the hierarchies listed in %s (workers: %d)\n", path, workers)
 		if debug {
 			fmt.Println("🔍 Debug mode enabled")
 		}
+		if valuesOnly {
+			fmt.Println("📝 Values-only mode: existing items only receive their values")
+		}
+		selection, err := rangeFlag(cmd)
+		if err != nil {
+			return err
+		}
+		if err := syncDependencies(cmd, app, product_syncing_dependencies.SyncDependenciesCommand{Path: path, Debug: debug}); err != nil {
+			return err
+		}
+		if err := preflight(cmd, app, product_validating.ValidateProductsCommand{Path: path}); err != nil {
+			return err
+		}
 
-		// Sync entire hierarchy
-		fmt.Printf("📥 Fetching product hierarchy for '%s' from source...\n", identifier)
-		response, err := app.CommandBus.Dispatch(ctx, product_syncing.SyncProductCommand{
-			Identifier: identifier,
+		progress := func(done, total int, hierarchy product_syncing_file.HierarchyResult) {
+			switch {
+			case hierarchy.Error != "":
+				fmt.Printf("   [%d/%d] ❌ %s: %s\n", done, total, hierarchy.Identifier, hierarchy.Error)
+			case hierarchy.Failed > 0:
+				fmt.Printf("   [%d/%d] ⚠️  %s: %d synced, %d errors\n", done, total, hierarchy.Identifier, hierarchy.Synced, hierarchy.Failed)
+			default:
+				fmt.Printf("   [%d/%d] ✅ %s: %d synced\n", done, total, hierarchy.Identifier, hierarchy.Synced)
+			}
+		}
+
+		// Execute synchronization using command bus
+		response, err := app.CommandBus.Dispatch(ctx, product_syncing_file.SyncProductsFromFileCommand{
+			Path:       path,
+			Workers:    workers,
+			ValuesOnly: valuesOnly,
+			OnConflict: onConflict,
+			Progress:   progress,
+			Range:      selection,
 			Debug:      debug,
 		})
-
 		if err != nil {
-			log.Printf("❌ Synchronization error: %v\n", err)
-			return
+			return fmt.Errorf("synchronization error: %w", err)
 		}
 
-		result, ok := response.Data.(*product_syncing.SyncResult)
+		result, ok := response.Data.(*product_syncing_file.SyncResult)
 		if !ok {
-			log.Printf("❌ Invalid response type\n")
-			return
+			return errors.New("invalid response type")
 		}
 
 		// Show result
+		fmt.Println("\n📋 Synchronization Summary:")
+		fmt.Printf("   📄 Hierarchies listed: %d\n", len(result.Hierarchies))
+		fmt.Printf("   📋 Models synced: %d\n", result.ModelsSynced)
+		fmt.Printf("   📦 Products synced: %d\n", result.ProductsSynced)
+		fmt.Printf("   📊 Total synced: %d\n", result.TotalSynced)
+		printConflicts(result.Conflicts)
+		printMissingTargets(result.MissingTargets)
+		printDroppedAttributes(result.DroppedAttributes)
+		printUnchanged(result.Unchanged)
+
+		if debug {
+			for _, failure := range result.FailedItems {
+				fmt.Printf("❌ Error in %s '%s': %s\n", failure.Kind, failure.Code, failure.Error)
+			}
+		}
+
 		if result.Success {
-			fmt.Println("\n📋 Synchronization Summary:")
-			fmt.Printf("   📦 Models synced: %d\n", result.ModelsSynced)
-			fmt.Printf("   📦 Products synced: %d\n", result.ProductsSynced)
-			fmt.Printf("   📊 Total synced: %d\n", result.TotalSynced)
-			fmt.Printf("\n✅ Hierarchy '%s' synchronized successfully!\n", result.Identifier)
+			fmt.Println("\n✅ Synchronization completed successfully!")
 		} else {
-			fmt.Printf("❌ Failed to synchronize '%s': %s\n", result.Identifier, result.Error)
+			fmt.Printf("\n⚠️  %d items with errors; run retry-failed to reprocess them\n", len(result.FailedItems))
 		}
+
+		return nil
 	}
 }
 
-// createSyncAttributeCommand creates the sync-attribute command
-func createSyncAttributeCommand(app *Application) *cobra.Command {
+// createSyncPublishedProductsCommand creates the sync-published-products command
+func createSyncPublishedProductsCommand(app *Application) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "sync-attribute [code]",
-		Short: "Synchronizes an attribute by its code",
-		Long: `Synchronizes a single attribute from the source Akeneo to the destination Akeneo.
+		Use:   "sync-published-products",
+		Short: "Synchronizes the published products of the source (Enterprise Edition)",
+		Long: `Synchronizes the working copy of every product published in the source, then
+compares the published versions of both instances.
 
-Requires the attribute code as an argument.
+The Akeneo API cannot publish products: the products whose published version is
+missing or outdated in the destination are listed so they can be published there,
+for example with a mass edit. Use --publish-list to write their identifiers to a file.
+
+Parent models must already exist in the destination: run sync-product for the
+hierarchies of published variants first.
 
 Example:
-  akeneo-migrator sync-attribute sku
-  akeneo-migrator sync-attribute description --debug`,
-		Args: cobra.ExactArgs(1),
-		Run:  runSyncAttributeCommand(app),
+  akeneo-migrator sync-published-products
+  akeneo-migrator sync-published-products --publish-list to-publish.txt`,
+		Args:    cobra.NoArgs,
+		PreRunE: app.initialize,
+		RunE:    runSyncPublishedProductsCommand(app),
 	}
 
-	// Add debug flag
-	cmd.Flags().Bool("debug", false, "Enable debug mode to see attribute contents")
+	// Add flags
+	cmd.Flags().Bool("debug", false, "Enable debug mode to see detailed sync information")
+	cmd.Flags().Bool("values-only", false, "Only send values for items that already exist in destination")
+	cmd.Flags().String("publish-list", "", "Write the identifiers of the products to publish in destination to this file")
+	cmd.Flags().String("on-conflict", "", conflictFlagUsage)
+	addAttributeFilterFlags(cmd)
 
 	return cmd
 }
 
-// runSyncAttributeCommand executes the attribute synchronization logic
-func runSyncAttributeCommand(app *Application) func(cmd *cobra.Command, args []string) {
-	return func(cmd *cobra.Command, args []string) {
-		code := args[0]
-		ctx := context.Background()
+// runSyncPublishedProductsCommand executes the published products synchronization logic
+func runSyncPublishedProductsCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
 
-		// Get debug flag
-		debug, _ := cmd.Flags().GetBool("debug") //nolint:errcheck // flag is optional
+		debug, _ := cmd.Flags().GetBool("debug")                //nolint:errcheck // flag is optional
+		valuesOnly, _ := cmd.Flags().GetBool("values-only")     //nolint:errcheck // flag is optional
+		publishList, _ := cmd.Flags().GetString("publish-list") //nolint:errcheck // flag is optional
 
-		fmt.Printf("🚀 Starting synchronization for attribute: %s\n", code)
+		fmt.Println("🚀 Starting synchronization of published products")
 		if debug {
 			fmt.Println("🔍 Debug mode enabled")
 		}
+		onConflict, err := conflictStrategyFlag(cmd)
+		if err != nil {
+			return err
+		}
 
-		// Execute synchronization using command bus
-		response, err := app.CommandBus.Dispatch(ctx, attribute_syncing.SyncAttributeCommand{
-			Code:  code,
-			Debug: debug,
+		response, err := app.CommandBus.Dispatch(ctx, product_syncing_published.SyncPublishedProductsCommand{
+			ValuesOnly: valuesOnly,
+			OnConflict: onConflict,
+			Debug:      debug,
 		})
 		if err != nil {
-			log.Printf("❌ Synchronization error: %v\n", err)
-			return
+			return fmt.Errorf("synchronization error: %w", err)
 		}
 
-		result, ok := response.Data.(*attribute_syncing.SyncResult)
+		result, ok := response.Data.(*product_syncing_published.SyncResult)
 		if !ok {
-			log.Printf("❌ Invalid response type\n")
-			return
+			return errors.New("invalid response type")
 		}
 
-		// Show result
-		if result.Success {
-			fmt.Printf("\n✅ Attribute '%s' synchronized successfully!\n", result.Code)
-			if result.OptionsSynced > 0 {
-				fmt.Printf("   📋 Attribute options synced: %d\n", result.OptionsSynced)
-			}
-			if len(result.OptionsErrors) > 0 {
-				fmt.Printf("   ⚠️  Option errors: %d\n", len(result.OptionsErrors))
-				if debug {
-					for _, errMsg := range result.OptionsErrors {
-						fmt.Printf("      - %s\n", errMsg)
-					}
+		fmt.Println("\n📋 Synchronization Summary:")
+		fmt.Printf("   📰 Published in source: %d\n", result.Published)
+		fmt.Printf("   📦 Working copies synced: %d\n", result.ProductsSynced)
+		fmt.Printf("   ✅ Published version up to date: %d\n", result.UpToDate)
+		fmt.Printf("   📤 To publish in destination: %d\n", len(result.ToPublish))
+		printConflicts(result.Conflicts)
+		printMissingTargets(result.MissingTargets)
+		printDroppedAttributes(result.DroppedAttributes)
+		printUnchanged(result.Unchanged)
+
+		if debug {
+			for _, publication := range result.ToPublish {
+				if publication.FirstPublication {
+					fmt.Printf("   - %s (never published)\n", publication.Identifier)
+				} else {
+					fmt.Printf("   - %s\n", publication.Identifier)
 				}
 			}
+			for _, failure := range result.FailedItems {
+				fmt.Printf("❌ Error in product '%s': %s\n", failure.Code, failure.Error)
+			}
+		}
+
+		if publishList != "" && len(result.ToPublish) > 0 {
+			var identifiers strings.Builder
+			for _, publication := range result.ToPublish {
+				identifiers.WriteString(publication.Identifier + "\n")
+			}
+			if err := os.WriteFile(publishList, []byte(identifiers.String()), 0o644); err != nil {
+				log.Printf("❌ Error writing publish list: %v\n", err)
+			} else {
+				fmt.Printf("📝 Products to publish written to %s\n", publishList)
+			}
+		}
+
+		if result.Success {
+			fmt.Println("\n✅ Synchronization completed successfully!")
 		} else {
-			fmt.Printf("❌ Failed to synchronize '%s': %s\n", result.Code, result.Error)
+			fmt.Printf("\n⚠️  Synchronization completed with %d errors\n", len(result.FailedItems))
 		}
+
+		return nil
 	}
 }
 
-// createSyncCategoryCommand creates the sync-category command
-func createSyncCategoryCommand(app *Application) *cobra.Command {
+// verifyScopes are the scopes checked by the verify command
+var verifyScopes = []string{"entity", "family", "category", "category-tree"}
+
+// createVerifyCommand creates the verify command
+func createVerifyCommand(app *Application) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "sync-category [code]",
-		Short: "Synchronizes a category by its code",
-		Long: `Synchronizes a single category from the source Akeneo to the destination Akeneo.
+		Use:   "verify [scope] [code]",
+		Short: "Compares items between source and destination without writing",
+		Long: `Computes normalized checksums of the items of a scope on both Akeneo instances
+and reports mismatches, items missing in the destination and items that only
+exist in the destination. Nothing is written to either instance.
 
-Requires the category code as an argument.
+Available scopes:
+  entity         Reference Entity definition, attributes and records
+  family         Family and its variants
+  category       Single category
+  category-tree  Category and all its descendants
+
+Metadata such as _links, created and updated is ignored. The report ends with
+PASS or FAIL and the command exits with a non-zero status when differences are
+found, so it can be used as a post-migration acceptance check. Use --report-file
+to also write the report, with every discrepancy, to a JSON file.
 
 Example:
-  akeneo-migrator sync-category master
-  akeneo-migrator sync-category clothing --debug`,
-		Args: cobra.ExactArgs(1),
-		Run:  runSyncCategoryCommand(app),
+  akeneo-migrator verify entity brands
+  akeneo-migrator verify family clothing
+  akeneo-migrator verify category master --debug
+  akeneo-migrator verify category-tree master --report-file verify-master.json`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeVerifyCodes,
+		PreRunE:           app.initialize,
+		RunE:              runVerifyCommand(app),
 	}
 
 	// Add debug flag
-	cmd.Flags().Bool("debug", false, "Enable debug mode to see category contents")
+	cmd.Flags().Bool("debug", false, "Enable debug mode to see checksums of differing items")
+	cmd.Flags().String("report-file", "", "Also write the report as JSON to this file")
 
 	return cmd
 }
 
-// runSyncCategoryCommand executes the category synchronization logic
-func runSyncCategoryCommand(app *Application) func(cmd *cobra.Command, args []string) {
-	return func(cmd *cobra.Command, args []string) {
-		code := args[0]
-		ctx := context.Background()
+// runVerifyCommand executes the verification logic
+func runVerifyCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		scope := args[0]
+		code := args[1]
+		ctx := cmd.Context()
 
-		// Get debug flag
-		debug, _ := cmd.Flags().GetBool("debug") //nolint:errcheck // flag is optional
+		// Get flags
+		debug, _ := cmd.Flags().GetBool("debug")              //nolint:errcheck // flag is optional
+		reportFile, _ := cmd.Flags().GetString("report-file") //nolint:errcheck // flag is optional
 
-		fmt.Printf("🚀 Starting synchronization for category: %s\n", code)
-		if debug {
-			fmt.Println("🔍 Debug mode enabled")
+		var message bus.Message
+		switch scope {
+		case "entity":
+			message = reference_entity_verifying.VerifyReferenceEntityCommand{EntityName: code, Debug: debug}
+		case "family":
+			message = family_verifying.VerifyFamilyCommand{Code: code, Debug: debug}
+		case "category":
+			message = category_verifying.VerifyCategoryCommand{Code: code, Debug: debug}
+		case "category-tree":
+			message = category_verifying.VerifyCategoryCommand{Code: code, Tree: true, Debug: debug}
+		default:
+			return fmt.Errorf("unknown scope '%s' (expected entity, family, category or category-tree)", scope)
+		}
+
+		fmt.Printf("🔎 Verifying %s '%s' between source and destination...\n", scope, code)
+
+		response, err := app.CommandBus.Dispatch(ctx, message)
+		if err != nil {
+			return fmt.Errorf("verification error: %w", err)
+		}
+
+		report, ok := response.Data.(*checksum.Report)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		for _, difference := range report.Differences {
+			switch difference.Status {
+			case checksum.StatusMismatch:
+				fmt.Printf("   ≠  %s '%s' differs\n", difference.Kind, difference.Code)
+			case checksum.StatusMissing:
+				fmt.Printf("   ❌ %s '%s' is missing in destination\n", difference.Kind, difference.Code)
+			case checksum.StatusExtra:
+				fmt.Printf("   ➕ %s '%s' only exists in destination\n", difference.Kind, difference.Code)
+			}
+			if debug {
+				if difference.SourceChecksum != "" {
+					fmt.Printf("      source: %s\n", difference.SourceChecksum)
+				}
+				if difference.DestChecksum != "" {
+					fmt.Printf("      dest:   %s\n", difference.DestChecksum)
+				}
+				if difference.Detail != "" {
+					fmt.Printf("      detail: %s\n", difference.Detail)
+				}
+			}
+		}
+
+		// Final summary
+		fmt.Println("\n📋 Verification summary:")
+		fmt.Printf("   📊 Items in source: %d, in destination: %d\n", report.SourceCount(), report.DestCount())
+		fmt.Printf("   ✅ Matching: %d\n", report.Matches)
+		fmt.Printf("   ≠  Mismatched: %d\n", report.Count(checksum.StatusMismatch))
+		fmt.Printf("   ❌ Missing in destination: %d\n", report.Count(checksum.StatusMissing))
+		fmt.Printf("   ➕ Only in destination: %d\n", report.Count(checksum.StatusExtra))
+
+		if reportFile != "" {
+			if err := writeVerifyReport(reportFile, report); err != nil {
+				return fmt.Errorf("error writing verification report: %w", err)
+			}
+			fmt.Printf("📄 Verification report written to %s\n", reportFile)
+		}
+
+		if !report.OK() {
+			fmt.Println("\n❌ FAIL: instances differ.")
+			return exitError{code: ExitFailure}
 		}
 
-		// Execute synchronization using command bus
-		response, err := app.CommandBus.Dispatch(ctx, category_syncing.SyncCategoryCommand{
-			Code:  code,
-			Debug: debug,
-		})
+		fmt.Println("\n✅ PASS: source and destination are identical!")
+
+		return nil
+	}
+}
+
+// verifyReport is the JSON report written by verify --report-file
+type verifyReport struct {
+	*checksum.Report
+	Passed      bool `json:"passed"`
+	SourceCount int  `json:"source_count"`
+	DestCount   int  `json:"dest_count"`
+}
+
+// writeVerifyReport writes the outcome of a verification and its discrepancies to a JSON file
+func writeVerifyReport(path string, report *checksum.Report) error {
+	data, err := json.MarshalIndent(verifyReport{
+		Report:      report,
+		Passed:      report.OK(),
+		SourceCount: report.SourceCount(),
+		DestCount:   report.DestCount(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// createListReferenceEntitiesCommand creates the list-reference-entities command
+func createListReferenceEntitiesCommand(app *Application) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list-reference-entities",
+		Short: "Lists the Reference Entities of source and destination",
+		Long: `Lists the Reference Entities of both Akeneo instances side by side, with the
+number of locales each one is labelled in, so the codes to pass to sync can be
+found without opening the PIM. Nothing is written to either instance.
+
+Example:
+  akeneo-migrator list-reference-entities`,
+		Args:    cobra.NoArgs,
+		PreRunE: app.requiring(config.FeatureReferenceEntities),
+		RunE:    runListReferenceEntitiesCommand(app),
+	}
+}
+
+// runListReferenceEntitiesCommand executes the listing logic
+func runListReferenceEntitiesCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		response, err := app.CommandBus.Dispatch(cmd.Context(), reference_entity_listing.ListReferenceEntitiesCommand{})
 		if err != nil {
-			log.Printf("❌ Synchronization error: %v\n", err)
-			return
+			return fmt.Errorf("listing error: %w", err)
 		}
 
-		result, ok := response.Data.(*category_syncing.SyncResult)
+		result, ok := response.Data.(*reference_entity_listing.ListResult)
 		if !ok {
-			log.Printf("❌ Invalid response type\n")
-			return
+			return errors.New("invalid response type")
 		}
 
-		// Show result
-		if result.Success {
-			fmt.Printf("\n✅ Category '%s' synchronized successfully!\n", result.Code)
-		} else {
-			fmt.Printf("❌ Failed to synchronize '%s': %s\n", result.Code, result.Error)
+		if len(result.Entities) == 0 {
+			fmt.Println("ℹ️  No Reference Entities in source nor destination")
+			return nil
 		}
+
+		labels := func(count int) string {
+			if count < 0 {
+				return "-"
+			}
+			return fmt.Sprintf("%d", count)
+		}
+
+		fmt.Printf("%-40s %14s %14s\n", "CODE", "SOURCE LABELS", "DEST LABELS")
+		sourceOnly := 0
+		for _, entity := range result.Entities {
+			fmt.Printf("%-40s %14s %14s\n", entity.Code, labels(entity.SourceLabels), labels(entity.DestLabels))
+			if entity.InSource() && !entity.InDest() {
+				sourceOnly++
+			}
+		}
+
+		fmt.Printf("\n📊 %d Reference Entities, %d missing in destination (\"-\")\n", len(result.Entities), sourceOnly)
+
+		return nil
 	}
 }
 
-// createSyncFamilyCommand creates the sync-family command
-func createSyncFamilyCommand(app *Application) *cobra.Command {
+// createStatsCommand creates the stats command
+func createStatsCommand(app *Application) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "sync-family [code]",
-		Short: "Synchronizes a family by its code",
-		Long: `Synchronizes a single family from the source Akeneo to the destination Akeneo.
+		Use:   "stats",
+		Short: "Compares the number of objects of source and destination",
+		Long: `Counts the products, product models, families, attributes, categories and
+reference entity records of both Akeneo instances and shows them side by side with
+their delta, as a quick sanity check before and after a migration. Nothing is
+written to either instance.
 
-Requires the family code as an argument.
+Records do not report their count, so they are listed entity by entity, which takes
+a while on large catalogs; use --with-records=false to skip them. They are skipped
+as well when an instance does not support reference entities.
 
 Example:
-  akeneo-migrator sync-family clothing
-  akeneo-migrator sync-family accessories --debug`,
-		Args: cobra.ExactArgs(1),
-		Run:  runSyncFamilyCommand(app),
+  akeneo-migrator stats
+  akeneo-migrator stats --with-records=false`,
+		Args:    cobra.NoArgs,
+		PreRunE: app.initialize,
+		RunE:    runStatsCommand(app),
 	}
 
-	// Add debug flag
-	cmd.Flags().Bool("debug", false, "Enable debug mode to see family contents")
+	// Add flags
+	cmd.Flags().Bool("with-records", true, "Also count the records of every reference entity")
 
 	return cmd
 }
 
-// runSyncFamilyCommand executes the family synchronization logic
-func runSyncFamilyCommand(app *Application) func(cmd *cobra.Command, args []string) {
-	return func(cmd *cobra.Command, args []string) {
-		code := args[0]
-		ctx := context.Background()
-
-		// Get debug flag
-		debug, _ := cmd.Flags().GetBool("debug") //nolint:errcheck // flag is optional
+// runStatsCommand executes the comparison of the object counts
+func runStatsCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		withRecords, _ := cmd.Flags().GetBool("with-records") //nolint:errcheck // flag has default value
 
-		fmt.Printf("🚀 Starting synchronization for family: %s\n", code)
-		if debug {
-			fmt.Println("🔍 Debug mode enabled")
+		kinds := make([]stats.Kind, 0, len(stats.Kinds))
+		for _, kind := range stats.Kinds {
+			if kind == stats.KindReferenceRecords && (!withRecords || app.Config.RequireFeature(config.FeatureReferenceEntities) != nil) {
+				continue
+			}
+			kinds = append(kinds, kind)
 		}
 
-		// Execute synchronization using command bus
-		response, err := app.CommandBus.Dispatch(ctx, family_syncing.SyncFamilyCommand{
-			Code:  code,
-			Debug: debug,
-		})
+		response, err := app.CommandBus.Dispatch(cmd.Context(), stats_counting.CountCommand{Kinds: kinds})
 		if err != nil {
-			log.Printf("❌ Synchronization error: %v\n", err)
-			return
+			return fmt.Errorf("count error: %w", err)
 		}
 
-		result, ok := response.Data.(*family_syncing.SyncResult)
+		result, ok := response.Data.(*stats_counting.CountResult)
 		if !ok {
-			log.Printf("❌ Invalid response type\n")
-			return
+			return errors.New("invalid response type")
 		}
 
-		// Show result
-		if result.Success {
-			fmt.Printf("\n✅ Family '%s' synchronized successfully!\n", result.Code)
-			if result.VariantsSynced > 0 {
-				fmt.Printf("   📋 Family variants synced: %d\n", result.VariantsSynced)
+		value := func(count int, errMsg string) string {
+			if errMsg != "" {
+				return "error"
 			}
-			if len(result.VariantsErrors) > 0 {
-				fmt.Printf("   ⚠️  Variant errors: %d\n", len(result.VariantsErrors))
-				if debug {
-					for _, errMsg := range result.VariantsErrors {
-						fmt.Printf("      - %s\n", errMsg)
-					}
-				}
+			return fmt.Sprintf("%d", count)
+		}
+
+		fmt.Printf("%-28s %12s %12s %10s\n", "KIND", "SOURCE", "DEST", "DELTA")
+		var countErrors []string
+		differing := 0
+		for _, count := range result.Counts {
+			delta := "-"
+			switch {
+			case !count.Counted():
+				differing++
+			case count.Delta() != 0:
+				delta = fmt.Sprintf("%+d ⚠️", count.Delta())
+				differing++
+			default:
+				delta = "0"
+			}
+			fmt.Printf("%-28s %12s %12s %10s\n", count.Kind, value(count.Source, count.SourceError), value(count.Dest, count.DestError), delta)
+
+			if count.SourceError != "" {
+				countErrors = append(countErrors, fmt.Sprintf("source %s: %s", count.Kind, count.SourceError))
+			}
+			if count.DestError != "" {
+				countErrors = append(countErrors, fmt.Sprintf("destination %s: %s", count.Kind, count.DestError))
 			}
+		}
+
+		for _, errMsg := range countErrors {
+			fmt.Printf("   ❌ %s\n", errMsg)
+		}
+
+		if result.Matches() {
+			fmt.Println("\n✅ Source and destination have the same number of objects")
 		} else {
-			fmt.Printf("❌ Failed to synchronize '%s': %s\n", result.Code, result.Error)
+			fmt.Printf("\n⚠️  %d of %d kinds differ or could not be counted\n", differing, len(result.Counts))
 		}
+
+		return nil
 	}
 }
 
-// createSyncUpdatedProductsCommand creates the sync-updated-products command
-func createSyncUpdatedProductsCommand(app *Application) *cobra.Command {
+// createRunPairsCommand creates the run-pairs command
+func createRunPairsCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "sync-updated-products [date]",
-		Short: "Synchronizes products updated since a specific date",
-		Long: `Synchronizes all products and their complete hierarchies that have been updated since a specific date.
-
-The date should be in ISO 8601 format: YYYY-MM-DDTHH:MM:SS
+		Use:   "run-pairs -- [command] [args...]",
+		Short: "Runs a command for several configured instance pairs in parallel",
+		Long: `Runs the same migrator command for every instance pair configured under "pairs"
+in the settings file. Each pair runs in its own process, with its own clients,
+rate limits and log file, so one customer's failure never affects another.
 
-For each updated product or model, the entire hierarchy will be synchronized:
-- If a model is updated, syncs the model and all its variants
-- If a product is updated, syncs its parent hierarchy (common → models → variants)
+Separate the command to run from the run-pairs flags with "--".
 
 Example:
-  akeneo-migrator sync-updated-products 2024-01-01T00:00:00
-  akeneo-migrator sync-updated-products 2024-01-15T10:30:00 --debug`,
-		Args: cobra.ExactArgs(1),
-		Run:  runSyncUpdatedProductsCommand(app),
+  akeneo-migrator run-pairs -- sync brands
+  akeneo-migrator run-pairs --pairs acme,globex --parallel 2 -- sync-updated-products 2024-01-01T00:00:00`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runRunPairsCommand(),
 	}
 
-	// Add debug flag
-	cmd.Flags().Bool("debug", false, "Enable debug mode to see detailed sync information")
+	// Add flags
+	cmd.Flags().StringSlice("pairs", nil, "Comma-separated pair names to run (default: all configured pairs)")
+	cmd.Flags().Int("parallel", 4, "Maximum number of pairs running at the same time")
+	cmd.Flags().String("log-dir", "logs/pairs", "Directory where the log of each pair is written")
 
 	return cmd
 }
 
-// runSyncUpdatedProductsCommand executes the updated products synchronization logic
-func runSyncUpdatedProductsCommand(app *Application) func(cmd *cobra.Command, args []string) {
-	return func(cmd *cobra.Command, args []string) {
-		updatedSince := args[0]
-		ctx := context.Background()
+// runRunPairsCommand executes a command for every selected instance pair
+func runRunPairsCommand() func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
 
-		// Get debug flag
-		debug, _ := cmd.Flags().GetBool("debug") //nolint:errcheck // flag is optional
+		// Get flags
+		selectedPairs, _ := cmd.Flags().GetStringSlice("pairs") //nolint:errcheck // flag is optional
+		parallel, _ := cmd.Flags().GetInt("parallel")           //nolint:errcheck // flag has default value
+		logDir, _ := cmd.Flags().GetString("log-dir")           //nolint:errcheck // flag has default value
 
-		fmt.Printf("🚀 Starting synchronization of products updated since: %s\n", updatedSince)
-		if debug {
-			fmt.Println("🔍 Debug mode enabled")
+		viperConfig := viper.NewViperConfig()
+		if err := viperConfig.LoadConfiguration(CONTEXT); err != nil {
+			return fmt.Errorf("configuration error: %w", err)
 		}
 
-		// Execute synchronization using command bus
-		response, err := app.CommandBus.Dispatch(ctx, product_syncing_since.SyncProductsSinceCommand{
-			UpdatedSince: updatedSince,
-			Debug:        debug,
-		})
+		pairs, err := config.LoadPairs(viperConfig)
 		if err != nil {
-			log.Printf("❌ Synchronization error: %v\n", err)
-			return
+			return fmt.Errorf("configuration error: %w", err)
 		}
 
-		result, ok := response.Data.(*product_syncing_since.SyncResult)
-		if !ok {
-			log.Printf("❌ Invalid response type\n")
-			return
+		jobs := make([]runner.Job, 0, len(pairs))
+		for _, pair := range pairs {
+			if len(selectedPairs) > 0 && !containsString(selectedPairs, pair.Name) {
+				continue
+			}
+			jobs = append(jobs, runner.Job{Pair: pair.Name, Args: args})
 		}
 
-		// Show result
-		fmt.Println("\n📋 Synchronization Summary:")
-		fmt.Printf("   📅 Updated since: %s\n", result.UpdatedSince)
-		fmt.Printf("   📦 Models synced: %d\n", result.ModelsSynced)
-		fmt.Printf("   📦 Products synced: %d\n", result.ProductsSynced)
-		fmt.Printf("   📊 Total synced: %d\n", result.TotalSynced)
+		if len(jobs) == 0 {
+			return errors.New("none of the selected pairs is configured")
+		}
 
-		if len(result.Errors) > 0 {
-			fmt.Printf("   ⚠️  Errors: %d\n", len(result.Errors))
-			if debug {
-				fmt.Println("\n❌ Errors encountered:")
-				for _, errMsg := range result.Errors {
-					fmt.Printf("   - %s\n", errMsg)
-				}
+		binaryPath, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("could not determine executable path: %w", err)
+		}
+
+		fmt.Printf("🚀 Running '%s' for %d pairs (parallel: %d)\n", strings.Join(args, " "), len(jobs), parallel)
+		fmt.Printf("📁 Logs directory: %s\n", logDir)
+
+		pairRunner := runner.NewRunner(binaryPath, logDir, parallel, config.PairEnvVar)
+		results, err := pairRunner.Run(ctx, jobs, func(result runner.Result) {
+			switch result.ExitCode {
+			case ExitSuccess:
+				fmt.Printf("   ✅ [%s] completed (took %v)\n", result.Pair, result.Duration)
+			case ExitPartial:
+				fmt.Printf("   ⚠️  [%s] completed with failed items (see %s)\n", result.Pair, result.LogFile)
+			default:
+				fmt.Printf("   ❌ [%s] failed with exit code %d (see %s)\n", result.Pair, result.ExitCode, result.LogFile)
 			}
+		})
+		if err != nil {
+			return fmt.Errorf("error running pairs: %w", err)
 		}
 
-		if result.Success {
-			fmt.Println("\n✅ Synchronization completed successfully!")
-		} else {
-			fmt.Println("\n⚠️  Synchronization completed with errors")
+		failed, partial := 0, 0
+		for _, result := range results {
+			switch result.ExitCode {
+			case ExitSuccess:
+			case ExitPartial:
+				partial++
+			default:
+				failed++
+			}
+		}
+
+		// Final summary
+		fmt.Println("\n📋 Pairs summary:")
+		fmt.Printf("   ✅ Successful pairs: %d\n", len(results)-failed-partial)
+		if partial > 0 {
+			fmt.Printf("   ⚠️  Pairs with failed items: %d\n", partial)
+		}
+		fmt.Printf("   ❌ Failed pairs: %d\n", failed)
+
+		// Pairs with failed items make the run partial, like the items of a single sync
+		if code := outcome(len(results)-failed, failed+partial); code != ExitSuccess {
+			return exitError{code: code}
+		}
+		return nil
+	}
+}
+
+// containsString reports whether a slice contains the given value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
 		}
 	}
+	return false
 }
 
 // createWebCommand creates the web UI command
@@ -629,7 +4512,7 @@ The web UI allows you to:
 Example:
   akeneo-migrator web
   akeneo-migrator web --port 8080`,
-		Run: runWebCommand(app),
+		RunE: runWebCommand(app),
 	}
 
 	// Add port flag
@@ -639,8 +4522,8 @@ Example:
 }
 
 // runWebCommand starts the web server
-func runWebCommand(app *Application) func(cmd *cobra.Command, args []string) {
-	return func(cmd *cobra.Command, args []string) {
+func runWebCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
 		port, _ := cmd.Flags().GetString("port") //nolint:errcheck // flag has default value
 
 		// Get the path of the current executable
@@ -661,7 +4544,331 @@ func runWebCommand(app *Application) func(cmd *cobra.Command, args []string) {
 		server := web.NewServer(port, binaryPath)
 
 		if err := server.Start(); err != nil {
-			log.Fatalf("❌ Failed to start web server: %v\n", err)
+			return fmt.Errorf("failed to start web server: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// createMockServerCommand creates the mock-server command
+func createMockServerCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mock-server",
+		Short: "Start a fake Akeneo API for demos and local development",
+		Long: `Starts a fake Akeneo REST API backed by an in-memory catalog, so sync flows can be
+tried without a real PIM. Any credentials are accepted and writes are kept in memory.
+
+The catalog is seeded from an export directory where every JSON file holds an array of items
+and mirrors an API collection path:
+
+  products.json, product-models.json, attributes.json, categories.json, families.json,
+  channels.json, locales.json, currencies.json, reference-entities.json,
+  attributes/<code>/options.json, families/<code>/variants.json,
+  reference-entities/<code>/attributes.json, reference-entities/<code>/records.json
+
+Example:
+  akeneo-migrator mock-server --data ./export --port 8081`,
+		RunE: runMockServerCommand,
+	}
+
+	cmd.Flags().String("data", "", "Export directory used to seed the catalog")
+	cmd.Flags().String("port", "8081", "Port to run the mock server on")
+
+	return cmd
+}
+
+// runMockServerCommand starts the mock Akeneo server
+func runMockServerCommand(cmd *cobra.Command, args []string) error {
+	dataDir, _ := cmd.Flags().GetString("data") //nolint:errcheck // flag is optional
+	port, _ := cmd.Flags().GetString("port")    //nolint:errcheck // flag has default value
+
+	store := mockserver.NewStore()
+	if dataDir != "" {
+		var err error
+		if store, err = mockserver.LoadStore(dataDir); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("🧪 Starting mock Akeneo API\n")
+	fmt.Printf("📍 Server running on http://localhost:%s\n", port)
+
+	counts := store.Counts()
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("   📦 %s: %d\n", name, counts[name])
+	}
+	fmt.Printf("Press Ctrl+C to stop\n\n")
+
+	if err := mockserver.NewServer(port, store).Start(); err != nil {
+		return fmt.Errorf("failed to start mock server: %w", err)
+	}
+
+	return nil
+}
+
+// createRetryFailedCommand creates the retry-failed command
+func createRetryFailedCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retry-failed [job-id | manifest-file]",
+		Short: "Reprocesses the items that failed during a previous sync",
+		Long: `Every sync command queues the items it could not write (products, models,
+records, attributes, categories, families, channels...) as a job in the state
+store (state.dir, default .akeneo-migrator). Once the underlying issue is fixed,
+for example a missing attribute in the destination, retry-failed synchronizes
+those items again, and only them.
+
+Without a job ID, the latest job that has not been retried yet is used. Items
+that still fail are queued in a new job, printed at the end of the run.
+
+A failure manifest written by a sync run with --failure-manifest can be given
+instead of a job ID, e.g. when the state store of the failed run is not available.
+
+Example:
+  akeneo-migrator retry-failed
+  akeneo-migrator retry-failed 20240115-103000-a1b2
+  akeneo-migrator retry-failed failures.json
+  akeneo-migrator retry-failed --list`,
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: app.initialize,
+		RunE:    runRetryFailedCommand(app),
+	}
+
+	// Add flags
+	cmd.Flags().Bool("list", false, "List queued jobs instead of retrying")
+
+	return cmd
+}
+
+// runRetryFailedCommand executes the retry logic
+func runRetryFailedCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		list, _ := cmd.Flags().GetBool("list") //nolint:errcheck // flag is optional
+
+		if list {
+			jobs, err := app.Jobs.List(ctx)
+			if err != nil {
+				return fmt.Errorf("error listing jobs: %w", err)
+			}
+
+			if len(jobs) == 0 {
+				fmt.Println("📭 No queued jobs")
+				return nil
+			}
+
+			fmt.Println("🗂️  Queued jobs:")
+			for _, queued := range jobs {
+				status := "pending"
+				if !queued.Pending() {
+					status = "retried"
+				}
+				fmt.Printf("   %s  %-24s %4d items  %s\n", queued.ID, queued.Command, len(queued.Failures), status)
+			}
+			return nil
+		}
+
+		command := retrying.RetryFailedCommand{}
+		if len(args) > 0 {
+			// An existing file is a failure manifest, anything else a job ID
+			if info, statErr := os.Stat(args[0]); statErr == nil && !info.IsDir() {
+				command.Manifest = args[0]
+			} else {
+				command.JobID = args[0]
+			}
+		}
+
+		switch {
+		case command.Manifest != "":
+			fmt.Printf("🔁 Retrying failed items of manifest %s\n", command.Manifest)
+		case command.JobID != "":
+			fmt.Printf("🔁 Retrying failed items of job %s\n", command.JobID)
+		default:
+			fmt.Println("🔁 Retrying failed items of the latest pending job")
+		}
+
+		response, err := app.CommandBus.Dispatch(ctx, command)
+		if err != nil {
+			return fmt.Errorf("retry error: %w", err)
 		}
+
+		result, ok := response.Data.(*retrying.RetryResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		fmt.Println("\n📋 Retry summary:")
+		fmt.Printf("   🗂️  Job: %s\n", result.JobID)
+		fmt.Printf("   🔁 Items retried: %d\n", result.Retried)
+		fmt.Printf("   ✅ Items resolved: %d\n", result.Resolved)
+		fmt.Printf("   ❌ Items still failing: %d\n", len(result.Remaining))
+
+		if len(result.Remaining) == 0 {
+			fmt.Println("\n🎉 All failed items were synchronized!")
+			return nil
+		}
+
+		for _, failure := range result.Remaining {
+			code := failure.Code
+			if failure.Scope != "" {
+				code = failure.Scope + "/" + failure.Code
+			}
+			fmt.Printf("   - %s '%s': %s\n", failure.Kind, code, failure.Error)
+		}
+		fmt.Printf("\n⚠️  Remaining items queued as job %s (run: retry-failed %s)\n", result.NewJobID, result.NewJobID)
+
+		// The remaining items are queued by the retry itself, so they are not counted by the session
+		return exitError{code: outcome(result.Resolved, len(result.Remaining))}
+	}
+}
+
+// createPlanCommand creates the plan command
+func createPlanCommand(app *Application, root *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plan [plan-file] [sync-command] [args...]",
+		Short: "Computes the writes of a sync into a plan file, to be reviewed then applied",
+		Long: `Runs a sync command as a dry run and writes every create or update it would send
+to destination into a plan file. The plan can be reviewed (or committed for an
+approval process), then sent as is with apply: apply does not read the source
+again, so the destination receives exactly the reviewed payloads.
+
+Global flags go before the plan file; the arguments and flags after the sync
+command are the ones of the sync.
+
+Example:
+  akeneo-migrator plan plan.json sync-product COMMON-001 --values-only
+  akeneo-migrator plan plan.json sync-all-families
+  akeneo-migrator apply plan.json`,
+		Args:    cobra.MinimumNArgs(2),
+		PreRunE: app.initialize,
+		RunE:    runPlanCommand(app, root),
+	}
+
+	// Flags after the sync command belong to the sync
+	cmd.Flags().SetInterspersed(false)
+
+	return cmd
+}
+
+// runPlanCommand runs a sync command in dry-run mode and saves the writes it planned
+func runPlanCommand(app *Application, root *cobra.Command) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		path := args[0]
+		command := strings.Join(args[1:], " ")
+
+		sync, syncArgs, err := root.Find(args[1:])
+		if err != nil || !strings.HasPrefix(sync.Name(), "sync") {
+			return fmt.Errorf("'%s' is not a sync command", command)
+		}
+		if err := sync.ParseFlags(syncArgs); err != nil {
+			return err
+		}
+		syncArgs = sync.Flags().Args()
+		if err := sync.ValidateArgs(syncArgs); err != nil {
+			return err
+		}
+
+		// Every write of the sync is collected instead of being sent
+		ctx, planned := dryrun.Collect(dryrun.With(cmd.Context()))
+		sync.SetContext(ctx)
+		if sync.PreRunE != nil {
+			if err := sync.PreRunE(sync, syncArgs); err != nil {
+				return err
+			}
+		}
+
+		fmt.Printf("📝 Planning '%s', nothing is sent to destination\n", command)
+		if err := sync.RunE(sync, syncArgs); err != nil {
+			return err
+		}
+
+		computed := plan.New(app.Session.ID, command, app.Config.Dest.Host, planned(), time.Now())
+		if err := app.Plans.Save(ctx, path, computed); err != nil {
+			return err
+		}
+
+		fmt.Printf("\n📝 Plan %s: %d writes to %s\n", computed.ID, len(computed.Writes), computed.Destination)
+		counts := computed.Counts()
+		kinds := make([]string, 0, len(counts))
+		for kind := range counts {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		for _, kind := range kinds {
+			fmt.Printf("   %-28s %d\n", kind, counts[kind])
+		}
+		fmt.Printf("\n💾 Plan written to %s (run: apply %s)\n", path, path)
+
+		return nil
+	}
+}
+
+// createApplyCommand creates the apply command
+func createApplyCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply [plan-file]",
+		Short: "Sends the writes of a plan file to destination",
+		Long: `Sends the writes of a plan computed by the plan command to destination, in the
+order they were planned and with the planned payloads. The source is not read.
+
+The plan is refused when the destination host differs from the one it was
+computed for. Writes that fail are queued as a job, to be synchronized again
+from source with retry-failed. Media files are not part of a plan: run the sync
+to copy them.
+
+Example:
+  akeneo-migrator apply plan.json
+  akeneo-migrator apply plan.json --dry-run`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: app.initialize,
+		RunE:    runApplyCommand(app),
+	}
+
+	return cmd
+}
+
+// runApplyCommand executes the writes of a plan
+func runApplyCommand(app *Application) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		path := args[0]
+
+		fmt.Printf("🚀 Applying plan %s\n", path)
+
+		response, err := app.CommandBus.Dispatch(ctx, applying.ApplyPlanCommand{Path: path})
+		if err != nil {
+			return fmt.Errorf("apply error: %w", err)
+		}
+
+		result, ok := response.Data.(*applying.ApplyResult)
+		if !ok {
+			return errors.New("invalid response type")
+		}
+
+		fmt.Println("\n📋 Apply summary:")
+		fmt.Printf("   📝 Plan: %s (%s)\n", result.PlanID, result.Command)
+		fmt.Printf("   ✅ Writes applied: %d/%d\n", result.Applied, result.Writes)
+		fmt.Printf("   ❌ Writes failed: %d\n", len(result.Failed))
+
+		if len(result.Failed) == 0 {
+			fmt.Println("\n🎉 Plan applied!")
+			return nil
+		}
+
+		for _, failure := range result.Failed {
+			code := failure.Code
+			if failure.Scope != "" {
+				code = failure.Scope + "/" + failure.Code
+			}
+			fmt.Printf("   - %s '%s': %s\n", failure.Kind, code, failure.Error)
+		}
+
+		return nil
 	}
 }
@@ -0,0 +1,164 @@
+package bootstrap
+
+import (
+	"context"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"akeneo-migrator/internal/platform/client/akeneo"
+	"akeneo-migrator/internal/platform/config"
+	"akeneo-migrator/kit/config/static/viper"
+
+	"github.com/spf13/cobra"
+)
+
+// completionTimeout bounds the time spent fetching codes from source while the shell waits
+const completionTimeout = 10 * time.Second
+
+// completionPageSize is the number of items fetched per page by paginated completions
+const completionPageSize = 100
+
+// codeLister lists the codes of a kind of item of the source instance
+type codeLister func(ctx context.Context, client *akeneo.Client) ([]string, error)
+
+// Kinds of items whose codes are suggested by shell completion
+var (
+	referenceEntityCodes codeLister = func(ctx context.Context, client *akeneo.Client) ([]string, error) {
+		entities, err := client.GetReferenceEntities(ctx)
+		return codesOf(entities), err
+	}
+	assetFamilyCodes codeLister = func(ctx context.Context, client *akeneo.Client) ([]string, error) {
+		families, err := client.GetAssetFamilies(ctx)
+		return codesOf(families), err
+	}
+	familyCodes codeLister = func(ctx context.Context, client *akeneo.Client) ([]string, error) {
+		return pagedCodes(ctx, client.GetFamilies)
+	}
+	attributeCodes codeLister = func(ctx context.Context, client *akeneo.Client) ([]string, error) {
+		return pagedCodes(ctx, client.GetAttributes)
+	}
+	associationTypeCodes codeLister = func(ctx context.Context, client *akeneo.Client) ([]string, error) {
+		types, err := client.GetAssociationTypes(ctx)
+		return codesOf(types), err
+	}
+	channelCodes codeLister = func(ctx context.Context, client *akeneo.Client) ([]string, error) {
+		channels, err := client.GetChannels(ctx)
+		return codesOf(channels), err
+	}
+	categoryTreeCodes codeLister = func(ctx context.Context, client *akeneo.Client) ([]string, error) {
+		roots, err := client.GetRootCategories(ctx)
+		return codesOf(roots), err
+	}
+)
+
+// completeCodes suggests the codes listed from source for the first argument of a command
+func completeCodes(list codeLister) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return sourceCodes(cmd, list, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeVerifyCodes suggests the scopes of verify, then the codes of the chosen scope
+func completeVerifyCodes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return verifyScopes, cobra.ShellCompDirectiveNoFileComp
+	case 1:
+		listers := map[string]codeLister{
+			"entity":        referenceEntityCodes,
+			"family":        familyCodes,
+			"category-tree": categoryTreeCodes,
+		}
+		if list, ok := listers[args[0]]; ok {
+			return sourceCodes(cmd, list, toComplete), cobra.ShellCompDirectiveNoFileComp
+		}
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// sourceCodes lists the codes starting with prefix from the source instance of the configuration.
+// Completion output is read by the shell, so nothing is printed and errors only suggest nothing.
+func sourceCodes(cmd *cobra.Command, list codeLister, prefix string) []string {
+	viperConfig := viper.NewViperConfig()
+	if err := viperConfig.LoadConfiguration(CONTEXT); err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil
+	}
+	cfg, err := config.LoadConfig(viperConfig, connectionOverrides(cmd))
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil
+	}
+
+	client, err := akeneo.NewClient(akeneo.ClientConfig{
+		Host:        cfg.Source.Host,
+		ClientID:    cfg.Source.ClientID,
+		Secret:      cfg.Source.Secret,
+		Username:    cfg.Source.Username,
+		Password:    cfg.Source.Password,
+		AccessToken: cfg.Source.AccessToken,
+	},
+		// clientTLS is not used, since its warning would be read as a suggestion
+		akeneo.WithTLSConfig(akeneo.TLSConfig{
+			CAFile:             cfg.AkeneoSource.API.TLS.CAFile,
+			CertFile:           cfg.AkeneoSource.API.TLS.CertFile,
+			KeyFile:            cfg.AkeneoSource.API.TLS.KeyFile,
+			InsecureSkipVerify: cfg.AkeneoSource.API.TLS.InsecureSkipVerify,
+		}),
+		akeneo.WithLogger(log.New(io.Discard, "", 0)),
+	)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), completionTimeout)
+	defer cancel()
+
+	codes, err := list(ctx, client)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil
+	}
+
+	matching := make([]string, 0, len(codes))
+	for _, code := range codes {
+		if strings.HasPrefix(code, prefix) {
+			matching = append(matching, code)
+		}
+	}
+	sort.Strings(matching)
+	return matching
+}
+
+// pagedCodes lists the codes of every page of a paginated endpoint
+func pagedCodes[T ~map[string]interface{}](ctx context.Context, fetch func(ctx context.Context, page, limit int) ([]T, bool, error)) ([]string, error) {
+	var codes []string
+	for page := 1; ; page++ {
+		items, hasNext, err := fetch(ctx, page, completionPageSize)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, codesOf(items)...)
+		if !hasNext {
+			return codes, nil
+		}
+	}
+}
+
+// codesOf returns the codes of items
+func codesOf[T ~map[string]interface{}](items []T) []string {
+	codes := make([]string, 0, len(items))
+	for _, item := range items {
+		if code, ok := item["code"].(string); ok && code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
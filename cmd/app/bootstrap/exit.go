@@ -0,0 +1,54 @@
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+// Exit codes of the CLI, so CI pipelines can tell a failed migration from a partial one
+const (
+	// ExitSuccess means every item was synced
+	ExitSuccess = 0
+	// ExitFailure means the command failed as a whole, or every item it processed failed
+	ExitFailure = 1
+	// ExitPartial means the command completed but some items could not be synced
+	ExitPartial = 2
+)
+
+// exitError ends the run with code, for commands that already printed why they did not succeed
+type exitError struct {
+	code int
+}
+
+func (e exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+// outcome returns the exit code of a run that synced some items and failed on others
+func outcome(synced, failed int) int {
+	switch {
+	case failed == 0:
+		return ExitSuccess
+	case synced == 0:
+		return ExitFailure
+	default:
+		return ExitPartial
+	}
+}
+
+// exitCode prints the error the run ended with, if any, and returns its exit code. Commands
+// completing with failed items return no error, so their outcome is read from the session.
+func (app *Application) exitCode(err error) int {
+	var exit exitError
+	if errors.As(err, &exit) {
+		return exit.code
+	}
+	if err != nil {
+		log.Printf("❌ %v\n", err)
+		return ExitFailure
+	}
+
+	totals := app.Session.Totals()
+	return outcome(totals.Synced, totals.Failed+totals.Errors)
+}
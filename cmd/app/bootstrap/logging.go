@@ -0,0 +1,69 @@
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"akeneo-migrator/internal/platform/client/akeneo"
+	"akeneo-migrator/kit/logger"
+
+	"github.com/spf13/cobra"
+)
+
+// setupLogger creates the logger of the services and clients from -v, -q and --log-format.
+// It writes to the standard output of the moment, which is stderr with --output json.
+func (app *Application) setupLogger(cmd *cobra.Command) error {
+	flags := cmd.Root().PersistentFlags()
+	verbose, _ := flags.GetBool("verbose")   //nolint:errcheck // flag has default value
+	quiet, _ := flags.GetBool("quiet")       //nolint:errcheck // flag has default value
+	name, _ := flags.GetString("log-format") //nolint:errcheck // flag has default value
+
+	if verbose && quiet {
+		return errors.New("--verbose and --quiet cannot be used together")
+	}
+	format, err := logger.ParseFormat(name)
+	if err != nil {
+		return err
+	}
+
+	level := logger.Info
+	switch {
+	case verbose:
+		level = logger.Debug
+	case quiet:
+		level = logger.Warn
+	}
+
+	app.Logger = logger.New(os.Stdout, level, format)
+	return nil
+}
+
+// clientLogger sends the diagnostic messages of a client to the logger of the application
+type clientLogger struct {
+	logger *logger.Logger
+}
+
+// Printf writes messages the client prints whatever the log level, e.g. records dumped by --debug
+func (l clientLogger) Printf(format string, args ...interface{}) {
+	l.logger.Info(message(format, args...))
+}
+
+// Logf writes a message with the level of the logger matching the client level
+func (l clientLogger) Logf(level akeneo.Level, format string, args ...interface{}) {
+	levels := map[akeneo.Level]logger.Level{
+		akeneo.LevelDebug: logger.Debug,
+		akeneo.LevelInfo:  logger.Info,
+		akeneo.LevelWarn:  logger.Warn,
+	}
+	if !l.logger.Enabled(levels[level]) {
+		return
+	}
+	l.logger.Log(levels[level], message(format, args...))
+}
+
+// message formats a client message, without the line break the logger adds
+func message(format string, args ...interface{}) string {
+	return strings.TrimRight(fmt.Sprintf(format, args...), "\n")
+}
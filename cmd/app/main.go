@@ -1,13 +1,11 @@
 package main
 
 import (
-	"log"
+	"os"
 
 	"akeneo-migrator/cmd/app/bootstrap"
 )
 
 func main() {
-	if err := bootstrap.Run(); err != nil {
-		log.Fatal(err)
-	}
+	os.Exit(bootstrap.Run())
 }
@@ -0,0 +1,72 @@
+package asset
+
+import "context"
+
+// Family represents an asset family definition
+type Family map[string]interface{}
+
+// Attribute represents an attribute of an asset family
+type Attribute map[string]interface{}
+
+// AttributeOption represents an option of a single or multiple options asset attribute
+type AttributeOption map[string]interface{}
+
+// Asset represents an asset of an asset family
+type Asset map[string]interface{}
+
+// MediaFile represents a file referenced by a media file value of an asset
+type MediaFile struct {
+	Code     string
+	Filename string
+	Content  []byte
+}
+
+// SourceRepository defines read-only operations for the Asset Manager of the source
+type SourceRepository interface {
+	// FindFamilyCodes retrieves the codes of every asset family
+	FindFamilyCodes(ctx context.Context) ([]string, error)
+
+	// FindFamily retrieves an asset family definition
+	FindFamily(ctx context.Context, familyCode string) (Family, error)
+
+	// FindAttributes retrieves all attributes of an asset family
+	FindAttributes(ctx context.Context, familyCode string) ([]Attribute, error)
+
+	// FindAttributeOptions retrieves all options of an asset attribute
+	FindAttributeOptions(ctx context.Context, familyCode, attributeCode string) ([]AttributeOption, error)
+
+	// FindAsset retrieves an asset of an asset family
+	FindAsset(ctx context.Context, familyCode, code string) (Asset, error)
+
+	// StreamAssets processes the assets of an asset family in batches, as they are fetched.
+	// The callback is called for each batch of assets
+	StreamAssets(ctx context.Context, familyCode string, batchSize int, callback func([]Asset) error) error
+
+	// DownloadMediaFile retrieves the content of a media file
+	DownloadMediaFile(ctx context.Context, code string) (MediaFile, error)
+}
+
+// DestRepository defines write operations for the Asset Manager of the destination
+type DestRepository interface {
+	// SaveFamily creates or updates an asset family definition
+	SaveFamily(ctx context.Context, familyCode string, family Family) error
+
+	// SaveAttribute creates or updates an attribute of an asset family
+	SaveAttribute(ctx context.Context, familyCode, attributeCode string, attribute Attribute) error
+
+	// SaveAttributeOption creates or updates an option of an asset attribute
+	SaveAttributeOption(ctx context.Context, familyCode, attributeCode, optionCode string, option AttributeOption) error
+
+	// SaveAll creates or updates several assets of an asset family in batches.
+	// It returns the errors of the assets that were not written, indexed by code
+	SaveAll(ctx context.Context, familyCode string, assets []Asset) (map[string]error, error)
+
+	// FindCodes retrieves the codes of all assets of an asset family
+	FindCodes(ctx context.Context, familyCode string) ([]string, error)
+
+	// Delete deletes an asset of an asset family
+	Delete(ctx context.Context, familyCode, code string) error
+
+	// UploadMediaFile stores a media file and returns the code assigned to it
+	UploadMediaFile(ctx context.Context, file MediaFile) (string, error)
+}
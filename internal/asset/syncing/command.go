@@ -0,0 +1,31 @@
+package syncing
+
+import (
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/retry"
+)
+
+const SyncAssetFamilyCommandType bus.Type = "asset_family.sync"
+
+// SyncAssetFamilyCommand represents a command to sync an asset family
+type SyncAssetFamilyCommand struct {
+	FamilyCode string
+	// Assets limits the sync to these asset codes, skipping the family definition and attributes
+	Assets []string
+	// Prune deletes the destination assets missing from source; ignored when Assets is set
+	Prune bool
+	Debug bool
+}
+
+// Type returns the command type
+func (c SyncAssetFamilyCommand) Type() bus.Type {
+	return SyncAssetFamilyCommandType
+}
+
+// RetryItem returns the item targeted by the command
+func (c SyncAssetFamilyCommand) RetryItem() retry.Failure {
+	if len(c.Assets) == 1 {
+		return retry.Failure{Kind: KindAsset, Scope: c.FamilyCode, Code: c.Assets[0]}
+	}
+	return retry.Failure{Kind: KindAssetFamily, Code: c.FamilyCode}
+}
@@ -0,0 +1,40 @@
+package syncing
+
+import (
+	"context"
+
+	"akeneo-migrator/kit/bus"
+)
+
+// CommandHandler handles SyncAssetFamilyCommand
+type CommandHandler struct {
+	service *Service
+}
+
+// NewCommandHandler creates a new command handler
+func NewCommandHandler(service *Service) *CommandHandler {
+	return &CommandHandler{
+		service: service,
+	}
+}
+
+// Handle executes the sync command
+func (h *CommandHandler) Handle(ctx context.Context, msg bus.Message) (bus.Response, error) {
+	cmd, ok := msg.(SyncAssetFamilyCommand)
+	if !ok {
+		return bus.Response{}, nil
+	}
+
+	var result *SyncResult
+	var err error
+	if len(cmd.Assets) > 0 {
+		result, err = h.service.SyncAssets(ctx, cmd.FamilyCode, cmd.Assets)
+	} else {
+		result, err = h.service.Sync(ctx, cmd.FamilyCode, SyncOptions{Prune: cmd.Prune})
+	}
+	if err != nil {
+		return bus.Response{Error: err}, err
+	}
+
+	return bus.Response{Data: result}, nil
+}
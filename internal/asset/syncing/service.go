@@ -0,0 +1,582 @@
+package syncing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"akeneo-migrator/internal/asset"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/logger"
+	"akeneo-migrator/kit/progress"
+	"akeneo-migrator/kit/prune"
+	"akeneo-migrator/kit/retry"
+	"akeneo-migrator/kit/workers"
+)
+
+// Kinds of items reported as failures
+const (
+	KindAssetFamily = "asset_family"
+	// KindAsset is an asset; its scope is the asset family
+	KindAsset = "asset"
+)
+
+// Kinds of items only reported in planned writes
+const (
+	// KindAttribute is an asset attribute; its scope is the asset family
+	KindAttribute = "asset_attribute"
+	// KindAttributeOption is an option coded attribute/option; its scope is the asset family
+	KindAttributeOption = "asset_attribute_option"
+	KindMediaFile       = "media_file"
+	// KindAssetDeletion is an asset deleted by a prune; its scope is the asset family
+	KindAssetDeletion = "asset_deletion"
+)
+
+// AssetBatchSize is the number of assets fetched from source and written to destination at a time
+const AssetBatchSize = 100
+
+// MediaFileAttributeType is the type of the asset attributes holding media files
+const MediaFileAttributeType = "media_file"
+
+// optionAttributeTypes are the types of the asset attributes holding options
+var optionAttributeTypes = map[string]bool{
+	"single_option":    true,
+	"multiple_options": true,
+}
+
+// deferredFamilyFields reference attributes of the family, so they are only written
+// once the attributes exist in destination
+var deferredFamilyFields = []string{"attribute_as_main_media", "naming_convention", "product_link_rules", "transformations"}
+
+// Service handles the synchronization logic for asset families
+type Service struct {
+	sourceRepo   asset.SourceRepository
+	destRepo     asset.DestRepository
+	confirmPrune prune.Confirm
+	workers      int
+	logger       *logger.Logger
+}
+
+// Option configures the synchronization service
+type Option func(*Service)
+
+// WithPruneConfirmation sets how the deletion of destination assets missing from source is confirmed.
+// Without it, prunes only report the assets they would delete.
+func WithPruneConfirmation(confirm prune.Confirm) Option {
+	return func(s *Service) {
+		s.confirmPrune = confirm
+	}
+}
+
+// WithWorkers sets the number of asset batches written to destination at the same time
+func WithWorkers(workers int) Option {
+	return func(s *Service) {
+		s.workers = workers
+	}
+}
+
+// WithLogger sends the messages of the syncs to log instead of the standard output
+func WithLogger(log *logger.Logger) Option {
+	return func(s *Service) {
+		s.logger = log
+	}
+}
+
+// NewService creates a new instance of the synchronization service
+func NewService(sourceRepo asset.SourceRepository, destRepo asset.DestRepository, opts ...Option) *Service {
+	service := &Service{
+		sourceRepo: sourceRepo,
+		destRepo:   destRepo,
+		logger:     logger.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
+
+// Logger returns the logger of the service, used by the asset syncs composing it
+func (s *Service) Logger() *logger.Logger {
+	return s.logger
+}
+
+// SyncOptions contains the options of an asset family synchronization
+type SyncOptions struct {
+	// Prune deletes the destination assets missing from source, once the deletion is confirmed
+	Prune bool
+}
+
+// SyncResult contains the result of an asset family synchronization
+type SyncResult struct {
+	FamilyCode   string
+	Attributes   int
+	Options      int
+	MediaFiles   int
+	TotalAssets  int
+	SuccessCount int
+	ErrorCount   int
+	Errors       []SyncError
+	// PruneCandidates are the destination assets missing from source, found by a prune
+	PruneCandidates []string
+	// Pruned is the number of assets deleted; nothing is deleted when the prune is not confirmed
+	Pruned      int
+	PruneErrors []SyncError
+	// Planned are the writes recorded instead of being sent during a dry run
+	Planned []dryrun.Write
+}
+
+// SyncError represents an asset that could not be synchronized
+type SyncError struct {
+	Code    string
+	Message string
+}
+
+// Failures returns the assets that could not be synchronized
+func (r *SyncResult) Failures() []retry.Failure {
+	failures := make([]retry.Failure, 0, len(r.Errors))
+	for _, syncErr := range r.Errors {
+		failures = append(failures, retry.Failure{Kind: KindAsset, Scope: r.FamilyCode, Code: syncErr.Code, Error: syncErr.Message})
+	}
+	return failures
+}
+
+// Synced returns the number of assets written
+func (r *SyncResult) Synced() int {
+	return r.SuccessCount
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// add merges the outcome of a batch of assets into the result
+func (r *SyncResult) add(batch *SyncResult) {
+	r.TotalAssets += batch.TotalAssets
+	r.SuccessCount += batch.SuccessCount
+	r.ErrorCount += batch.ErrorCount
+	r.MediaFiles += batch.MediaFiles
+	r.Errors = append(r.Errors, batch.Errors...)
+}
+
+// mediaCache maps the source media files already copied to their destination code, shared by the
+// batches written at the same time
+type mediaCache struct {
+	mu    sync.Mutex
+	codes map[string]string
+}
+
+// get returns the destination code of a source media file
+func (c *mediaCache) get(sourceCode string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	destCode, ok := c.codes[sourceCode]
+	return destCode, ok
+}
+
+// set records the destination code of a source media file
+func (c *mediaCache) set(sourceCode, destCode string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.codes == nil {
+		c.codes = make(map[string]string)
+	}
+	c.codes[sourceCode] = destCode
+}
+
+// Sync synchronizes an asset family (definition + attributes + options + assets) from source to destination
+func (s *Service) Sync(ctx context.Context, familyCode string, opts SyncOptions) (*SyncResult, error) {
+	result := &SyncResult{
+		FamilyCode: familyCode,
+		Errors:     make([]SyncError, 0),
+	}
+	ctx, planned := dryrun.Collect(ctx)
+
+	// 1. Create or update the family without the fields referencing its attributes
+	family, err := s.sourceRepo.FindFamily(ctx, familyCode)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching asset family from source: %w", err)
+	}
+
+	base := make(asset.Family, len(family))
+	for key, value := range family {
+		base[key] = value
+	}
+	deferred := false
+	for _, field := range deferredFamilyFields {
+		if _, exists := base[field]; exists {
+			delete(base, field)
+			deferred = true
+		}
+	}
+
+	if err := s.saveFamily(ctx, familyCode, base); err != nil {
+		return nil, fmt.Errorf("error creating/updating asset family in destination: %w", err)
+	}
+
+	// 2. Sync attributes and their options
+	attributes, err := s.sourceRepo.FindAttributes(ctx, familyCode)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching attributes from source: %w", err)
+	}
+
+	for _, attribute := range attributes {
+		attributeCode, ok := attribute["code"].(string)
+		if !ok {
+			return nil, fmt.Errorf("could not extract attribute code from asset attribute")
+		}
+
+		if !dryrun.Record(ctx, dryrun.Write{Kind: KindAttribute, Scope: familyCode, Code: attributeCode, Data: attribute}) {
+			if err := s.destRepo.SaveAttribute(ctx, familyCode, attributeCode, attribute); err != nil {
+				return nil, fmt.Errorf("error creating/updating attribute %s in destination: %w", attributeCode, err)
+			}
+		}
+		result.Attributes++
+
+		if attributeType, _ := attribute["type"].(string); optionAttributeTypes[attributeType] {
+			if err := s.syncOptions(ctx, familyCode, attributeCode, result); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// 3. Write the fields referencing attributes now that they exist
+	if deferred {
+		if err := s.saveFamily(ctx, familyCode, family); err != nil {
+			return nil, fmt.Errorf("error updating asset family in destination: %w", err)
+		}
+	}
+
+	// 4. Stream assets from source, writing each batch as it arrives
+	var sourceCodes map[string]bool
+	if opts.Prune {
+		sourceCodes = make(map[string]bool)
+	}
+	err = s.streamAssets(ctx, familyCode, mediaAttributes(attributes), nil, sourceCodes, result)
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. Delete the destination assets missing from source
+	if opts.Prune {
+		if err := s.pruneAssets(ctx, familyCode, sourceCodes, result); err != nil {
+			return nil, err
+		}
+	}
+
+	result.Planned = planned()
+	return result, nil
+}
+
+// saveFamily writes an asset family, or only records the write during a dry run
+func (s *Service) saveFamily(ctx context.Context, familyCode string, family asset.Family) error {
+	if dryrun.Record(ctx, dryrun.Write{Kind: KindAssetFamily, Code: familyCode, Data: family}) {
+		return nil
+	}
+	return s.destRepo.SaveFamily(ctx, familyCode, family)
+}
+
+// SyncAssets synchronizes only the given assets of a family, assuming its definition and attributes
+// already exist in destination. Codes not found in source are reported as errors.
+func (s *Service) SyncAssets(ctx context.Context, familyCode string, codes []string) (*SyncResult, error) {
+	result := &SyncResult{
+		FamilyCode: familyCode,
+		Errors:     make([]SyncError, 0),
+	}
+	ctx, planned := dryrun.Collect(ctx)
+
+	attributes, err := s.sourceRepo.FindAttributes(ctx, familyCode)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching attributes from source: %w", err)
+	}
+
+	wanted := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		wanted[code] = true
+	}
+
+	if err := s.streamAssets(ctx, familyCode, mediaAttributes(attributes), wanted, nil, result); err != nil {
+		return nil, err
+	}
+
+	for _, code := range codes {
+		if wanted[code] {
+			result.TotalAssets++
+			result.ErrorCount++
+			result.Errors = append(result.Errors, SyncError{Code: code, Message: "asset not found in source"})
+		}
+	}
+
+	result.Planned = planned()
+	return result, nil
+}
+
+// pruneAssets deletes the destination assets that are not in source, once the deletion is confirmed.
+// Dry runs only record the deletions.
+func (s *Service) pruneAssets(ctx context.Context, familyCode string, sourceCodes map[string]bool, result *SyncResult) error {
+	destCodes, err := s.destRepo.FindCodes(ctx, familyCode)
+	if err != nil {
+		return fmt.Errorf("error fetching asset codes from destination: %w", err)
+	}
+
+	result.PruneCandidates = prune.Missing(sourceCodes, destCodes)
+	if len(result.PruneCandidates) == 0 {
+		return nil
+	}
+
+	if dryrun.Enabled(ctx) {
+		for _, code := range result.PruneCandidates {
+			dryrun.Record(ctx, dryrun.Write{Kind: KindAssetDeletion, Scope: familyCode, Code: code})
+		}
+		return nil
+	}
+
+	if s.confirmPrune == nil || !s.confirmPrune(ctx, KindAsset, familyCode, result.PruneCandidates) {
+		return nil
+	}
+
+	for _, code := range result.PruneCandidates {
+		if err := s.destRepo.Delete(ctx, familyCode, code); err != nil {
+			result.PruneErrors = append(result.PruneErrors, SyncError{Code: code, Message: err.Error()})
+			continue
+		}
+		result.Pruned++
+	}
+
+	return nil
+}
+
+// syncOptions copies the options of an asset attribute to destination
+func (s *Service) syncOptions(ctx context.Context, familyCode, attributeCode string, result *SyncResult) error {
+	options, err := s.sourceRepo.FindAttributeOptions(ctx, familyCode, attributeCode)
+	if err != nil {
+		return fmt.Errorf("error fetching options of attribute %s from source: %w", attributeCode, err)
+	}
+
+	for _, option := range options {
+		optionCode, ok := option["code"].(string)
+		if !ok {
+			continue
+		}
+
+		if dryrun.Record(ctx, dryrun.Write{Kind: KindAttributeOption, Scope: familyCode, Code: attributeCode + "/" + optionCode, Data: option}) {
+			result.Options++
+			continue
+		}
+		if err := s.destRepo.SaveAttributeOption(ctx, familyCode, attributeCode, optionCode, option); err != nil {
+			return fmt.Errorf("error creating/updating option %s of attribute %s in destination: %w", optionCode, attributeCode, err)
+		}
+		result.Options++
+	}
+
+	return nil
+}
+
+// streamAssets writes the assets of a family to destination batch by batch. When wanted is not nil,
+// only those assets are written and each one is removed from wanted once found. When seen is not nil,
+// the code of every source asset is added to it. Batches are written on the worker pool.
+func (s *Service) streamAssets(ctx context.Context, familyCode string, mediaAttributes map[string]bool, wanted, seen map[string]bool, result *SyncResult) error {
+	// The same file may be used by several assets, locales or channels
+	uploaded := &mediaCache{}
+
+	pool := workers.New(s.workers)
+	err := s.sourceRepo.StreamAssets(ctx, familyCode, AssetBatchSize, func(assets []asset.Asset) error {
+		selected := make([]asset.Asset, 0, len(assets))
+		for _, item := range assets {
+			code, ok := item["code"].(string)
+			if !ok {
+				continue
+			}
+			if seen != nil {
+				seen[code] = true
+			}
+			if wanted != nil {
+				if !wanted[code] {
+					continue
+				}
+				delete(wanted, code)
+			}
+			selected = append(selected, item)
+		}
+
+		pool.Go(func() {
+			batch := &SyncResult{}
+			s.writeAssets(ctx, familyCode, selected, mediaAttributes, uploaded, batch)
+			pool.Merge(func() { result.add(batch) })
+			progress.Done(ctx, batch.TotalAssets)
+		})
+		return nil
+	})
+	pool.Wait()
+	if err != nil {
+		return fmt.Errorf("error fetching assets from source: %w", err)
+	}
+
+	return nil
+}
+
+// SaveAssets writes a batch of assets of a family with their media files, assuming the family and
+// its attributes already exist in destination. The assets that fail are reported in the result.
+// mediaAttributes are the codes of the attributes holding media files, see FindMediaAttributes.
+func (s *Service) SaveAssets(ctx context.Context, familyCode string, assets []asset.Asset, mediaAttributes map[string]bool) *SyncResult {
+	result := &SyncResult{
+		FamilyCode: familyCode,
+		Errors:     make([]SyncError, 0),
+	}
+	s.writeAssets(ctx, familyCode, assets, mediaAttributes, &mediaCache{}, result)
+	return result
+}
+
+// FindMediaAttributes returns the codes of the attributes of a family holding media files
+func (s *Service) FindMediaAttributes(ctx context.Context, familyCode string) (map[string]bool, error) {
+	attributes, err := s.sourceRepo.FindAttributes(ctx, familyCode)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching attributes from source: %w", err)
+	}
+	return mediaAttributes(attributes), nil
+}
+
+// writeAssets copies the media files of a batch of assets and writes the batch, recording the
+// assets that fail. uploaded maps the source media files already copied to their destination code.
+func (s *Service) writeAssets(ctx context.Context, familyCode string, assets []asset.Asset, mediaAttributes map[string]bool, uploaded *mediaCache, result *SyncResult) {
+	codes := make([]string, 0, len(assets))
+	prepared := make([]asset.Asset, 0, len(assets))
+
+	for _, item := range assets {
+		code, ok := item["code"].(string)
+		if !ok {
+			continue
+		}
+		result.TotalAssets++
+
+		copied, err := s.copyMediaFiles(ctx, item, mediaAttributes, uploaded, result)
+		if err != nil {
+			result.ErrorCount++
+			result.Errors = append(result.Errors, SyncError{Code: code, Message: err.Error()})
+			continue
+		}
+
+		codes = append(codes, code)
+		prepared = append(prepared, copied)
+	}
+
+	if len(prepared) == 0 {
+		return
+	}
+
+	failed, err := s.saveAssets(ctx, familyCode, codes, prepared)
+	for _, code := range codes {
+		saveErr := err
+		if saveErr == nil {
+			saveErr = failed[code]
+		}
+
+		if saveErr != nil {
+			result.ErrorCount++
+			result.Errors = append(result.Errors, SyncError{Code: code, Message: saveErr.Error()})
+		} else {
+			result.SuccessCount++
+		}
+	}
+}
+
+// saveAssets writes a batch of assets, or only records the writes during a dry run
+func (s *Service) saveAssets(ctx context.Context, familyCode string, codes []string, assets []asset.Asset) (map[string]error, error) {
+	if !dryrun.Enabled(ctx) {
+		return s.destRepo.SaveAll(ctx, familyCode, assets)
+	}
+
+	for i, item := range assets {
+		dryrun.Record(ctx, dryrun.Write{Kind: KindAsset, Scope: familyCode, Code: codes[i], Data: item})
+	}
+	return nil, nil
+}
+
+// copyMediaFiles uploads the media files of an asset to destination and returns a copy of the
+// asset referencing the destination file codes. Dry runs record the uploads and keep the source codes.
+func (s *Service) copyMediaFiles(ctx context.Context, item asset.Asset, mediaAttributes map[string]bool, uploaded *mediaCache, result *SyncResult) (asset.Asset, error) {
+	values, ok := item["values"].(map[string]interface{})
+	if !ok || len(mediaAttributes) == 0 {
+		return item, nil
+	}
+
+	copiedValues := make(map[string]interface{}, len(values))
+	for attributeCode, value := range values {
+		copiedValues[attributeCode] = value
+	}
+
+	for attributeCode := range mediaAttributes {
+		entries, ok := values[attributeCode].([]interface{})
+		if !ok {
+			continue
+		}
+
+		copiedEntries := make([]interface{}, len(entries))
+		for i, entry := range entries {
+			copiedEntries[i] = entry
+
+			value, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			fileCode, ok := value["data"].(string)
+			if !ok || fileCode == "" {
+				continue
+			}
+
+			destCode, done := uploaded.get(fileCode)
+			if !done && dryrun.Record(ctx, dryrun.Write{Kind: KindMediaFile, Code: fileCode}) {
+				destCode, done = fileCode, true
+				uploaded.set(fileCode, destCode)
+				result.MediaFiles++
+			}
+			if !done {
+				file, err := s.sourceRepo.DownloadMediaFile(ctx, fileCode)
+				if err != nil {
+					return nil, fmt.Errorf("error downloading media file %s: %w", fileCode, err)
+				}
+
+				destCode, err = s.destRepo.UploadMediaFile(ctx, file)
+				if err != nil {
+					return nil, fmt.Errorf("error uploading media file %s: %w", fileCode, err)
+				}
+
+				uploaded.set(fileCode, destCode)
+				result.MediaFiles++
+			}
+
+			copiedValue := make(map[string]interface{}, len(value))
+			for key, field := range value {
+				copiedValue[key] = field
+			}
+			copiedValue["data"] = destCode
+			copiedEntries[i] = copiedValue
+		}
+
+		copiedValues[attributeCode] = copiedEntries
+	}
+
+	copied := make(asset.Asset, len(item))
+	for key, value := range item {
+		copied[key] = value
+	}
+	copied["values"] = copiedValues
+
+	return copied, nil
+}
+
+// mediaAttributes returns the codes of the attributes holding media files
+func mediaAttributes(attributes []asset.Attribute) map[string]bool {
+	result := make(map[string]bool)
+	for _, attribute := range attributes {
+		attributeCode, _ := attribute["code"].(string)
+		if attributeType, _ := attribute["type"].(string); attributeType == MediaFileAttributeType && attributeCode != "" {
+			result[attributeCode] = true
+		}
+	}
+	return result
+}
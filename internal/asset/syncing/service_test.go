@@ -0,0 +1,228 @@
+package syncing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"akeneo-migrator/internal/asset"
+)
+
+// Mock repositories
+type mockSourceRepo struct {
+	family     asset.Family
+	attributes []asset.Attribute
+	options    map[string][]asset.AttributeOption
+	assets     []asset.Asset
+	downloads  int
+}
+
+func (m *mockSourceRepo) FindFamilyCodes(ctx context.Context) ([]string, error) {
+	return []string{"packshots"}, nil
+}
+
+func (m *mockSourceRepo) FindFamily(ctx context.Context, familyCode string) (asset.Family, error) {
+	if m.family == nil {
+		return nil, errors.New("not found")
+	}
+	return m.family, nil
+}
+
+func (m *mockSourceRepo) FindAttributes(ctx context.Context, familyCode string) ([]asset.Attribute, error) {
+	return m.attributes, nil
+}
+
+func (m *mockSourceRepo) FindAttributeOptions(ctx context.Context, familyCode, attributeCode string) ([]asset.AttributeOption, error) {
+	return m.options[attributeCode], nil
+}
+
+func (m *mockSourceRepo) FindAsset(ctx context.Context, familyCode, code string) (asset.Asset, error) {
+	for _, item := range m.assets {
+		if item["code"] == code {
+			return item, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockSourceRepo) StreamAssets(ctx context.Context, familyCode string, batchSize int, callback func([]asset.Asset) error) error {
+	return callback(m.assets)
+}
+
+func (m *mockSourceRepo) DownloadMediaFile(ctx context.Context, code string) (asset.MediaFile, error) {
+	m.downloads++
+	return asset.MediaFile{Code: code, Filename: "front.jpg", Content: []byte("jpg")}, nil
+}
+
+type mockDestRepo struct {
+	families   []asset.Family
+	attributes []string
+	options    []string
+	assets     []asset.Asset
+	failed     map[string]error
+	codes      []string
+	deleted    []string
+}
+
+func (m *mockDestRepo) SaveFamily(ctx context.Context, familyCode string, family asset.Family) error {
+	m.families = append(m.families, family)
+	return nil
+}
+
+func (m *mockDestRepo) SaveAttribute(ctx context.Context, familyCode, attributeCode string, attribute asset.Attribute) error {
+	m.attributes = append(m.attributes, attributeCode)
+	return nil
+}
+
+func (m *mockDestRepo) SaveAttributeOption(ctx context.Context, familyCode, attributeCode, optionCode string, option asset.AttributeOption) error {
+	m.options = append(m.options, attributeCode+"."+optionCode)
+	return nil
+}
+
+func (m *mockDestRepo) SaveAll(ctx context.Context, familyCode string, assets []asset.Asset) (map[string]error, error) {
+	m.assets = append(m.assets, assets...)
+	return m.failed, nil
+}
+
+func (m *mockDestRepo) FindCodes(ctx context.Context, familyCode string) ([]string, error) {
+	return m.codes, nil
+}
+
+func (m *mockDestRepo) Delete(ctx context.Context, familyCode, code string) error {
+	m.deleted = append(m.deleted, code)
+	return nil
+}
+
+func (m *mockDestRepo) UploadMediaFile(ctx context.Context, file asset.MediaFile) (string, error) {
+	return "dest/" + file.Filename, nil
+}
+
+func mediaValue(code string) []interface{} {
+	return []interface{}{map[string]interface{}{"locale": nil, "channel": nil, "data": code}}
+}
+
+func TestSync_CopiesFamilyAttributesAndAssets(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		family: asset.Family{"code": "packshots", "attribute_as_main_media": "picture", "labels": map[string]interface{}{"en_US": "Packshots"}},
+		attributes: []asset.Attribute{
+			{"code": "picture", "type": "media_file"},
+			{"code": "angle", "type": "single_option"},
+			{"code": "notes", "type": "text"},
+		},
+		options: map[string][]asset.AttributeOption{
+			"angle": {{"code": "front"}, {"code": "back"}},
+		},
+		assets: []asset.Asset{
+			{"code": "shoe_front", "values": map[string]interface{}{"picture": mediaValue("1/2/3/abc_front.jpg")}},
+			{"code": "boot_front", "values": map[string]interface{}{"picture": mediaValue("1/2/3/abc_front.jpg")}},
+		},
+	}
+	destRepo := &mockDestRepo{}
+
+	service := NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background(), "packshots", SyncOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The family is created without its main media, which is set once the attributes exist
+	if len(destRepo.families) != 2 {
+		t.Fatalf("Expected the family to be written twice, got %d", len(destRepo.families))
+	}
+	if _, exists := destRepo.families[0]["attribute_as_main_media"]; exists {
+		t.Error("Expected the main media to be left out of the first write")
+	}
+	if destRepo.families[1]["attribute_as_main_media"] != "picture" {
+		t.Errorf("Expected the main media in the second write, got %v", destRepo.families[1])
+	}
+
+	if result.Attributes != 3 || len(destRepo.options) != 2 || result.Options != 2 {
+		t.Errorf("Expected 3 attributes and 2 options, got %d and %v", result.Attributes, destRepo.options)
+	}
+
+	// The shared file is downloaded and uploaded once
+	if sourceRepo.downloads != 1 || result.MediaFiles != 1 {
+		t.Errorf("Expected 1 media file copied, got %d downloads", sourceRepo.downloads)
+	}
+	picture := destRepo.assets[1]["values"].(map[string]interface{})["picture"].([]interface{})[0].(map[string]interface{})
+	if picture["data"] != "dest/front.jpg" {
+		t.Errorf("Expected the destination file code, got %v", picture["data"])
+	}
+	if result.SuccessCount != 2 || result.Synced() != 2 {
+		t.Errorf("Expected 2 assets synced, got %d", result.SuccessCount)
+	}
+}
+
+func TestSyncAssets_SyncsOnlyGivenAssets(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		assets: []asset.Asset{{"code": "shoe_front"}, {"code": "boot_front"}},
+	}
+	destRepo := &mockDestRepo{failed: map[string]error{"shoe_front": errors.New("rejected")}}
+
+	service := NewService(sourceRepo, destRepo)
+	result, err := service.SyncAssets(context.Background(), "packshots", []string{"shoe_front", "missing"})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(destRepo.families) != 0 || len(destRepo.attributes) != 0 {
+		t.Error("Expected the family definition to be skipped")
+	}
+	if len(destRepo.assets) != 1 {
+		t.Errorf("Expected only shoe_front to be sent, got %v", destRepo.assets)
+	}
+
+	failures := result.Failures()
+	if len(failures) != 2 || failures[0].Scope != "packshots" || failures[0].Kind != KindAsset {
+		t.Errorf("Expected shoe_front and missing to fail, got %v", failures)
+	}
+}
+
+func TestSync_FamilyNotFound(t *testing.T) {
+	service := NewService(&mockSourceRepo{}, &mockDestRepo{})
+
+	if _, err := service.Sync(context.Background(), "unknown", SyncOptions{}); err == nil {
+		t.Error("Expected an error for an unknown family")
+	}
+}
+
+func TestSync_PrunesDestinationAssetsOnceConfirmed(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		family: asset.Family{"code": "packshots"},
+		assets: []asset.Asset{{"code": "shoe_front"}},
+	}
+	destRepo := &mockDestRepo{codes: []string{"shoe_front", "shoe_back"}}
+
+	var asked []string
+	service := NewService(sourceRepo, destRepo, WithPruneConfirmation(func(ctx context.Context, kind, scope string, codes []string) bool {
+		asked = append(asked, codes...)
+		return true
+	}))
+
+	result, err := service.Sync(context.Background(), "packshots", SyncOptions{Prune: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(asked) != 1 || asked[0] != "shoe_back" {
+		t.Errorf("Expected the destination-only asset to be previewed, got %v", asked)
+	}
+	if result.Pruned != 1 || len(destRepo.deleted) != 1 || destRepo.deleted[0] != "shoe_back" {
+		t.Errorf("Expected shoe_back to be deleted, got %d (%v)", result.Pruned, destRepo.deleted)
+	}
+}
+
+func TestSync_PruneWithoutConfirmationDeletesNothing(t *testing.T) {
+	sourceRepo := &mockSourceRepo{family: asset.Family{"code": "packshots"}}
+	destRepo := &mockDestRepo{codes: []string{"shoe_back"}}
+
+	result, err := NewService(sourceRepo, destRepo).Sync(context.Background(), "packshots", SyncOptions{Prune: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.PruneCandidates) != 1 || result.Pruned != 0 || len(destRepo.deleted) != 0 {
+		t.Errorf("Expected the candidate to be reported but not deleted, got %+v", result)
+	}
+}
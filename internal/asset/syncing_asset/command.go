@@ -0,0 +1,30 @@
+package syncing_asset
+
+import (
+	"akeneo-migrator/internal/asset/syncing"
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/retry"
+)
+
+const SyncAssetsCommandType bus.Type = "asset.sync"
+
+// SyncAssetsCommand represents a command to sync assets of an asset family without its definition
+type SyncAssetsCommand struct {
+	FamilyCode string
+	// Codes are the assets to sync; every asset of the family is synced when empty
+	Codes []string
+	Debug bool
+}
+
+// Type returns the command type
+func (c SyncAssetsCommand) Type() bus.Type {
+	return SyncAssetsCommandType
+}
+
+// RetryItem returns the item targeted by the command
+func (c SyncAssetsCommand) RetryItem() retry.Failure {
+	if len(c.Codes) == 1 {
+		return retry.Failure{Kind: syncing.KindAsset, Scope: c.FamilyCode, Code: c.Codes[0]}
+	}
+	return retry.Failure{Kind: syncing.KindAssetFamily, Code: c.FamilyCode}
+}
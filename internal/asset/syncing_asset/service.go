@@ -0,0 +1,122 @@
+package syncing_asset
+
+import (
+	"context"
+	"fmt"
+
+	"akeneo-migrator/internal/asset"
+	"akeneo-migrator/internal/asset/syncing"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/logger"
+	"akeneo-migrator/kit/retry"
+)
+
+// Service handles the synchronization of assets without their asset family definition
+type Service struct {
+	sourceRepo     asset.SourceRepository
+	syncingService *syncing.Service
+}
+
+// NewService creates a new instance of the asset sync service
+// Options are passed to the composed asset family sync service
+func NewService(sourceRepo asset.SourceRepository, destRepo asset.DestRepository, opts ...syncing.Option) *Service {
+	return &Service{
+		sourceRepo:     sourceRepo,
+		syncingService: syncing.NewService(sourceRepo, destRepo, opts...),
+	}
+}
+
+// SyncResult contains the result of syncing assets of a family
+type SyncResult struct {
+	FamilyCode string
+	// Codes are the assets requested, empty when every asset of the family was synced
+	Codes        []string
+	TotalAssets  int
+	AssetsSynced int
+	MediaFiles   int
+	Success      bool
+	// FailedItems are the assets that could not be written or were not found in source
+	FailedItems []retry.Failure
+	// Planned are the writes recorded instead of being sent during a dry run
+	Planned []dryrun.Write
+}
+
+// Failures returns the assets that could not be synchronized
+func (r *SyncResult) Failures() []retry.Failure {
+	return r.FailedItems
+}
+
+// Synced returns the number of assets written
+func (r *SyncResult) Synced() int {
+	return r.AssetsSynced
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// Sync synchronizes assets of a family with their media files: the given codes, or every asset of
+// the family when codes is empty. The asset family and its attributes must already exist in
+// destination; use the asset family sync to create them.
+func (s *Service) Sync(ctx context.Context, familyCode string, codes []string) (*SyncResult, error) {
+	result := &SyncResult{FamilyCode: familyCode, Codes: codes}
+	ctx, planned := dryrun.Collect(ctx)
+
+	mediaAttributes, err := s.syncingService.FindMediaAttributes(ctx, familyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(codes) == 0 {
+		err = s.sourceRepo.StreamAssets(ctx, familyCode, syncing.AssetBatchSize, func(assets []asset.Asset) error {
+			s.syncingService.Logger().Info("   🔄 Syncing assets", logger.F("count", len(assets)), logger.F("read", result.TotalAssets+len(assets)))
+			s.save(ctx, familyCode, assets, mediaAttributes, result)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error fetching assets from source: %w", err)
+		}
+	} else {
+		batch := make([]asset.Asset, 0, syncing.AssetBatchSize)
+		seen := make(map[string]bool, len(codes))
+		for _, code := range codes {
+			if seen[code] {
+				continue
+			}
+			seen[code] = true
+
+			item, err := s.sourceRepo.FindAsset(ctx, familyCode, code)
+			if err != nil {
+				s.syncingService.Logger().Warn("   ⚠️  Asset not synced", logger.F("code", code), logger.Err(err))
+				result.TotalAssets++
+				result.FailedItems = append(result.FailedItems, retry.Failure{Kind: syncing.KindAsset, Scope: familyCode, Code: code, Error: err.Error()})
+				continue
+			}
+
+			batch = append(batch, item)
+			if len(batch) == syncing.AssetBatchSize {
+				s.save(ctx, familyCode, batch, mediaAttributes, result)
+				batch = batch[:0]
+			}
+		}
+		s.save(ctx, familyCode, batch, mediaAttributes, result)
+	}
+
+	result.Planned = planned()
+	result.Success = len(result.FailedItems) == 0
+	return result, nil
+}
+
+// save writes a batch of assets and adds its outcome to the result
+func (s *Service) save(ctx context.Context, familyCode string, assets []asset.Asset, mediaAttributes map[string]bool, result *SyncResult) {
+	if len(assets) == 0 {
+		return
+	}
+
+	batchResult := s.syncingService.SaveAssets(ctx, familyCode, assets, mediaAttributes)
+	result.TotalAssets += batchResult.TotalAssets
+	result.AssetsSynced += batchResult.SuccessCount
+	result.MediaFiles += batchResult.MediaFiles
+	result.FailedItems = append(result.FailedItems, batchResult.Failures()...)
+}
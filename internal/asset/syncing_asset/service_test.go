@@ -0,0 +1,146 @@
+package syncing_asset
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"akeneo-migrator/internal/asset"
+	"akeneo-migrator/internal/asset/syncing"
+)
+
+// mockSourceRepo serves the assets of a family whose "media" attribute holds files
+type mockSourceRepo struct {
+	assets  []asset.Asset
+	fetched []string
+}
+
+func (m *mockSourceRepo) FindFamilyCodes(ctx context.Context) ([]string, error) {
+	return nil, errors.New("unexpected family listing")
+}
+
+func (m *mockSourceRepo) FindFamily(ctx context.Context, familyCode string) (asset.Family, error) {
+	return nil, errors.New("unexpected family fetch")
+}
+
+func (m *mockSourceRepo) FindAttributes(ctx context.Context, familyCode string) ([]asset.Attribute, error) {
+	return []asset.Attribute{
+		{"code": "media", "type": syncing.MediaFileAttributeType},
+		{"code": "title", "type": "text"},
+	}, nil
+}
+
+func (m *mockSourceRepo) FindAttributeOptions(ctx context.Context, familyCode, attributeCode string) ([]asset.AttributeOption, error) {
+	return nil, errors.New("unexpected options fetch")
+}
+
+func (m *mockSourceRepo) FindAsset(ctx context.Context, familyCode, code string) (asset.Asset, error) {
+	m.fetched = append(m.fetched, code)
+	for _, item := range m.assets {
+		if item["code"] == code {
+			return item, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockSourceRepo) StreamAssets(ctx context.Context, familyCode string, batchSize int, callback func([]asset.Asset) error) error {
+	return callback(m.assets)
+}
+
+func (m *mockSourceRepo) DownloadMediaFile(ctx context.Context, code string) (asset.MediaFile, error) {
+	return asset.MediaFile{Code: code, Filename: "front.jpg", Content: []byte("jpg")}, nil
+}
+
+// mockDestRepo records the assets written
+type mockDestRepo struct {
+	assets []asset.Asset
+	failed map[string]error
+}
+
+func (m *mockDestRepo) SaveFamily(ctx context.Context, familyCode string, family asset.Family) error {
+	return errors.New("unexpected family save")
+}
+
+func (m *mockDestRepo) SaveAttribute(ctx context.Context, familyCode, attributeCode string, attribute asset.Attribute) error {
+	return errors.New("unexpected attribute save")
+}
+
+func (m *mockDestRepo) SaveAttributeOption(ctx context.Context, familyCode, attributeCode, optionCode string, option asset.AttributeOption) error {
+	return errors.New("unexpected option save")
+}
+
+func (m *mockDestRepo) SaveAll(ctx context.Context, familyCode string, assets []asset.Asset) (map[string]error, error) {
+	m.assets = append(m.assets, assets...)
+	return m.failed, nil
+}
+
+func (m *mockDestRepo) FindCodes(ctx context.Context, familyCode string) ([]string, error) {
+	return nil, errors.New("unexpected code listing")
+}
+
+func (m *mockDestRepo) Delete(ctx context.Context, familyCode, code string) error {
+	return errors.New("unexpected deletion")
+}
+
+func (m *mockDestRepo) UploadMediaFile(ctx context.Context, file asset.MediaFile) (string, error) {
+	return "dest/" + file.Filename, nil
+}
+
+func sourceAssets() []asset.Asset {
+	return []asset.Asset{
+		{"code": "shoe_front", "values": map[string]interface{}{
+			"media": []interface{}{map[string]interface{}{"locale": nil, "channel": nil, "data": "a/b/shoe_front.jpg"}},
+		}},
+		{"code": "boot_front"},
+		{"code": "sandal_front"},
+	}
+}
+
+func TestSync_SyncsGivenAssets(t *testing.T) {
+	sourceRepo := &mockSourceRepo{assets: sourceAssets()}
+	destRepo := &mockDestRepo{failed: map[string]error{"boot_front": errors.New("rejected")}}
+
+	service := NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background(), "packshots", []string{"shoe_front", "boot_front", "missing", "shoe_front"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(sourceRepo.fetched) != 3 {
+		t.Errorf("Expected each asset to be fetched once, got %v", sourceRepo.fetched)
+	}
+	if len(destRepo.assets) != 2 {
+		t.Fatalf("Expected shoe_front and boot_front to be sent, got %v", destRepo.assets)
+	}
+	media := destRepo.assets[0]["values"].(map[string]interface{})["media"].([]interface{})[0].(map[string]interface{})
+	if media["data"] != "dest/front.jpg" || result.MediaFiles != 1 {
+		t.Errorf("Expected the media file to be copied, got %v", media)
+	}
+
+	if result.TotalAssets != 3 || result.AssetsSynced != 1 || result.Success {
+		t.Errorf("Expected 1 of 3 assets synced, got %+v", result)
+	}
+	failures := result.Failures()
+	if len(failures) != 2 || failures[0].Code != "missing" || failures[1].Code != "boot_front" || failures[1].Scope != "packshots" {
+		t.Errorf("Expected missing and boot_front to fail, got %v", failures)
+	}
+}
+
+func TestSync_SyncsEveryAssetOfTheFamily(t *testing.T) {
+	sourceRepo := &mockSourceRepo{assets: sourceAssets()}
+	destRepo := &mockDestRepo{}
+
+	service := NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background(), "packshots", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(sourceRepo.fetched) != 0 {
+		t.Errorf("Expected the assets to be streamed, got single fetches %v", sourceRepo.fetched)
+	}
+	if len(destRepo.assets) != 3 || result.AssetsSynced != 3 || !result.Success {
+		t.Errorf("Expected the 3 assets synced, got %+v", result)
+	}
+}
@@ -0,0 +1,30 @@
+package association_type
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned when an association type does not exist in the instance
+var ErrNotFound = errors.New("association type not found")
+
+// AssociationType represents an association type
+type AssociationType map[string]interface{}
+
+// SourceRepository defines read-only operations for association types from source
+type SourceRepository interface {
+	// FindByCode retrieves an association type by its code
+	FindByCode(ctx context.Context, code string) (AssociationType, error)
+
+	// FindCodes retrieves the codes of every association type
+	FindCodes(ctx context.Context) ([]string, error)
+}
+
+// DestRepository defines read and write operations for association types in destination
+type DestRepository interface {
+	// FindByCode retrieves an association type by its code
+	FindByCode(ctx context.Context, code string) (AssociationType, error)
+
+	// Save creates or updates an association type
+	Save(ctx context.Context, code string, associationType AssociationType) error
+}
@@ -0,0 +1,24 @@
+package syncing
+
+import (
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/retry"
+)
+
+const SyncAssociationTypeCommandType bus.Type = "association_type.sync"
+
+// SyncAssociationTypeCommand represents a command to sync an association type
+type SyncAssociationTypeCommand struct {
+	Code  string
+	Debug bool
+}
+
+// Type returns the command type
+func (c SyncAssociationTypeCommand) Type() bus.Type {
+	return SyncAssociationTypeCommandType
+}
+
+// RetryItem returns the item targeted by the command
+func (c SyncAssociationTypeCommand) RetryItem() retry.Failure {
+	return retry.Failure{Kind: KindAssociationType, Code: c.Code}
+}
@@ -0,0 +1,133 @@
+package syncing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"akeneo-migrator/internal/association_type"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/retry"
+)
+
+// KindAssociationType is the kind of item reported as failure
+const KindAssociationType = "association_type"
+
+// Service handles association type synchronization
+type Service struct {
+	sourceRepo association_type.SourceRepository
+	destRepo   association_type.DestRepository
+
+	// known are the association types that exist in destination, filled by EnsureExists
+	mu    sync.Mutex
+	known map[string]bool
+}
+
+// NewService creates a new association type sync service
+func NewService(sourceRepo association_type.SourceRepository, destRepo association_type.DestRepository) *Service {
+	return &Service{
+		sourceRepo: sourceRepo,
+		destRepo:   destRepo,
+		known:      make(map[string]bool),
+	}
+}
+
+// SyncResult contains the result of a sync operation
+type SyncResult struct {
+	Code    string
+	Success bool
+	Error   string
+	// Planned are the writes recorded instead of being sent during a dry run
+	Planned []dryrun.Write
+}
+
+// Failures returns the association type when it could not be synchronized
+func (r *SyncResult) Failures() []retry.Failure {
+	if r.Success {
+		return nil
+	}
+	return []retry.Failure{{Kind: KindAssociationType, Code: r.Code, Error: r.Error}}
+}
+
+// Synced returns the number of association types written
+func (r *SyncResult) Synced() int {
+	if r.Success {
+		return 1
+	}
+	return 0
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// Sync synchronizes a single association type from source to destination
+func (s *Service) Sync(ctx context.Context, code string) (*SyncResult, error) {
+	result := &SyncResult{
+		Code:    code,
+		Success: false,
+	}
+	ctx, planned := dryrun.Collect(ctx)
+
+	// 1. Get association type from source
+	sourceType, err := s.sourceRepo.FindByCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching association type from source: %w", err)
+	}
+
+	// 2. Save association type to destination
+	if !dryrun.Record(ctx, dryrun.Write{Kind: KindAssociationType, Code: code, Data: sourceType}) {
+		err = s.destRepo.Save(ctx, code, sourceType)
+	}
+	if err != nil {
+		result.Success = false
+		result.Error = err.Error()
+		return result, fmt.Errorf("error saving association type to destination: %w", err)
+	}
+
+	s.mu.Lock()
+	s.known[code] = true
+	s.mu.Unlock()
+
+	result.Planned = planned()
+	result.Success = true
+	return result, nil
+}
+
+// EnsureExists synchronizes the association types that do not exist yet in destination and
+// returns their codes. Types found in destination are remembered, so each one is checked once.
+// A destination lookup that fails for another reason than a missing type is returned as is.
+func (s *Service) EnsureExists(ctx context.Context, codes []string) ([]string, error) {
+	var created []string
+
+	for _, code := range codes {
+		s.mu.Lock()
+		known := s.known[code]
+		s.mu.Unlock()
+		if known {
+			continue
+		}
+
+		_, err := s.destRepo.FindByCode(ctx, code)
+		if err == nil {
+			s.mu.Lock()
+			s.known[code] = true
+			s.mu.Unlock()
+			continue
+		}
+		if !errors.Is(err, association_type.ErrNotFound) {
+			return created, fmt.Errorf("error checking association type %s in destination: %w", code, err)
+		}
+
+		if _, err := s.Sync(ctx, code); err != nil {
+			return created, fmt.Errorf("error creating association type %s: %w", code, err)
+		}
+		created = append(created, code)
+	}
+
+	sort.Strings(created)
+	return created, nil
+}
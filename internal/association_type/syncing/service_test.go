@@ -0,0 +1,127 @@
+package syncing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"akeneo-migrator/internal/association_type"
+)
+
+// Mock repositories
+type mockSourceRepo struct {
+	types map[string]association_type.AssociationType
+}
+
+func (m *mockSourceRepo) FindByCode(ctx context.Context, code string) (association_type.AssociationType, error) {
+	associationType, exists := m.types[code]
+	if !exists {
+		return nil, association_type.ErrNotFound
+	}
+	return associationType, nil
+}
+
+func (m *mockSourceRepo) FindCodes(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+type mockDestRepo struct {
+	types   map[string]association_type.AssociationType
+	err     error
+	lookups int
+	saved   []string
+}
+
+func (m *mockDestRepo) FindByCode(ctx context.Context, code string) (association_type.AssociationType, error) {
+	m.lookups++
+	if m.err != nil {
+		return nil, m.err
+	}
+	associationType, exists := m.types[code]
+	if !exists {
+		return nil, association_type.ErrNotFound
+	}
+	return associationType, nil
+}
+
+func (m *mockDestRepo) Save(ctx context.Context, code string, associationType association_type.AssociationType) error {
+	m.saved = append(m.saved, code)
+	return nil
+}
+
+func TestSync_Success(t *testing.T) {
+	sourceRepo := &mockSourceRepo{types: map[string]association_type.AssociationType{
+		"PACK": {"code": "PACK", "is_quantified": true, "labels": map[string]interface{}{"en_US": "Pack"}},
+	}}
+	destRepo := &mockDestRepo{}
+
+	service := NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background(), "PACK")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !result.Success || result.Synced() != 1 {
+		t.Errorf("Expected success, got %+v", result)
+	}
+	if len(destRepo.saved) != 1 || destRepo.saved[0] != "PACK" {
+		t.Errorf("Expected PACK to be saved, got %v", destRepo.saved)
+	}
+}
+
+func TestEnsureExists_CreatesOnlyMissingTypes(t *testing.T) {
+	sourceRepo := &mockSourceRepo{types: map[string]association_type.AssociationType{
+		"X_SELL":  {"code": "X_SELL"},
+		"UPSELL":  {"code": "UPSELL"},
+		"PACK":    {"code": "PACK"},
+		"SUBSTIT": {"code": "SUBSTIT"},
+	}}
+	destRepo := &mockDestRepo{types: map[string]association_type.AssociationType{
+		"X_SELL": {"code": "X_SELL"},
+	}}
+
+	service := NewService(sourceRepo, destRepo)
+	created, err := service.EnsureExists(context.Background(), []string{"X_SELL", "UPSELL", "PACK"})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(created) != 2 || created[0] != "PACK" || created[1] != "UPSELL" {
+		t.Errorf("Expected PACK and UPSELL to be created, got %v", created)
+	}
+
+	// Types already checked or created are not looked up again
+	lookups := destRepo.lookups
+	created, err = service.EnsureExists(context.Background(), []string{"X_SELL", "UPSELL", "PACK"})
+	if err != nil || len(created) != 0 {
+		t.Errorf("Expected nothing to create, got %v (%v)", created, err)
+	}
+	if destRepo.lookups != lookups {
+		t.Errorf("Expected no new lookups, got %d", destRepo.lookups-lookups)
+	}
+}
+
+func TestEnsureExists_FailsWhenMissingInSource(t *testing.T) {
+	service := NewService(&mockSourceRepo{}, &mockDestRepo{})
+
+	if _, err := service.EnsureExists(context.Background(), []string{"UNKNOWN"}); err == nil {
+		t.Error("Expected an error for a type missing in both instances")
+	}
+}
+
+func TestEnsureExists_FailsWhenDestinationLookupFails(t *testing.T) {
+	sourceRepo := &mockSourceRepo{types: map[string]association_type.AssociationType{
+		"PACK": {"code": "PACK"},
+	}}
+	destRepo := &mockDestRepo{err: errors.New("unavailable")}
+
+	service := NewService(sourceRepo, destRepo)
+	created, err := service.EnsureExists(context.Background(), []string{"PACK"})
+
+	if err == nil {
+		t.Fatal("Expected the lookup error to be returned")
+	}
+	if len(created) != 0 || len(destRepo.saved) != 0 {
+		t.Errorf("Expected nothing to be created, got %v (saved %v)", created, destRepo.saved)
+	}
+}
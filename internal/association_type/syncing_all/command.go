@@ -0,0 +1,15 @@
+package syncing_all
+
+import "akeneo-migrator/kit/bus"
+
+const SyncAllAssociationTypesCommandType bus.Type = "association_type.sync_all"
+
+// SyncAllAssociationTypesCommand represents a command to sync every association type of the source
+type SyncAllAssociationTypesCommand struct {
+	Debug bool
+}
+
+// Type returns the command type
+func (c SyncAllAssociationTypesCommand) Type() bus.Type {
+	return SyncAllAssociationTypesCommandType
+}
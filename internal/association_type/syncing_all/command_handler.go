@@ -0,0 +1,33 @@
+package syncing_all
+
+import (
+	"context"
+
+	"akeneo-migrator/kit/bus"
+)
+
+// CommandHandler handles SyncAllAssociationTypesCommand
+type CommandHandler struct {
+	service *Service
+}
+
+// NewCommandHandler creates a new command handler
+func NewCommandHandler(service *Service) *CommandHandler {
+	return &CommandHandler{
+		service: service,
+	}
+}
+
+// Handle executes the sync command
+func (h *CommandHandler) Handle(ctx context.Context, msg bus.Message) (bus.Response, error) {
+	if _, ok := msg.(SyncAllAssociationTypesCommand); !ok {
+		return bus.Response{}, nil
+	}
+
+	result, err := h.service.Sync(ctx)
+	if err != nil {
+		return bus.Response{Error: err}, err
+	}
+
+	return bus.Response{Data: result}, nil
+}
@@ -0,0 +1,86 @@
+package syncing_all
+
+import (
+	"context"
+	"fmt"
+
+	"akeneo-migrator/internal/association_type"
+	"akeneo-migrator/internal/association_type/syncing"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/retry"
+)
+
+// Service synchronizes every association type of the source, one at a time
+type Service struct {
+	sourceRepo     association_type.SourceRepository
+	syncingService *syncing.Service
+}
+
+// NewService creates a new instance of the all association types sync service
+func NewService(sourceRepo association_type.SourceRepository, destRepo association_type.DestRepository) *Service {
+	return &Service{
+		sourceRepo:     sourceRepo,
+		syncingService: syncing.NewService(sourceRepo, destRepo),
+	}
+}
+
+// SyncResult contains the result of syncing every association type
+type SyncResult struct {
+	// Types are the results of each association type, in source order
+	Types       []*syncing.SyncResult
+	TypesSynced int
+	Success     bool
+	FailedItems []retry.Failure
+	// Planned are the writes recorded instead of being sent during a dry run, for the whole sync
+	Planned []dryrun.Write
+}
+
+// Failures returns the association types that could not be synchronized
+func (r *SyncResult) Failures() []retry.Failure {
+	return r.FailedItems
+}
+
+// Synced returns the number of association types written
+func (r *SyncResult) Synced() int {
+	return r.TypesSynced
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// Sync lists the association types of the source and synchronizes each one.
+// A type that fails is reported and does not stop the others.
+func (s *Service) Sync(ctx context.Context) (*SyncResult, error) {
+	result := &SyncResult{}
+	ctx, planned := dryrun.Collect(ctx)
+
+	codes, err := s.sourceRepo.FindCodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing association types from source: %w", err)
+	}
+
+	for _, code := range codes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		typeResult, err := s.syncingService.Sync(ctx, code)
+		if typeResult == nil {
+			typeResult = &syncing.SyncResult{Code: code, Error: err.Error()}
+		}
+		// Planned writes are kept once, in the result of the whole sync
+		typeResult.Planned = nil
+
+		result.Types = append(result.Types, typeResult)
+		result.FailedItems = append(result.FailedItems, typeResult.Failures()...)
+		if typeResult.Success {
+			result.TypesSynced++
+		}
+	}
+
+	result.Planned = planned()
+	result.Success = len(result.FailedItems) == 0
+	return result, nil
+}
@@ -0,0 +1,69 @@
+package syncing_all
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"akeneo-migrator/internal/association_type"
+)
+
+// mockSourceRepo serves association types by code, missing ones failing
+type mockSourceRepo struct {
+	codes   []string
+	missing string
+}
+
+func (m *mockSourceRepo) FindByCode(ctx context.Context, code string) (association_type.AssociationType, error) {
+	if code == m.missing {
+		return nil, errors.New("not found")
+	}
+	return association_type.AssociationType{"code": code}, nil
+}
+
+func (m *mockSourceRepo) FindCodes(ctx context.Context) ([]string, error) {
+	return m.codes, nil
+}
+
+// mockDestRepo rejects the association type named in failing
+type mockDestRepo struct {
+	failing string
+	saved   []string
+}
+
+func (m *mockDestRepo) FindByCode(ctx context.Context, code string) (association_type.AssociationType, error) {
+	return nil, errors.New("not found")
+}
+
+func (m *mockDestRepo) Save(ctx context.Context, code string, associationType association_type.AssociationType) error {
+	if code == m.failing {
+		return errors.New("validation error")
+	}
+	m.saved = append(m.saved, code)
+	return nil
+}
+
+func TestSync_SyncsEveryTypeAndReportsFailedOnes(t *testing.T) {
+	sourceRepo := &mockSourceRepo{codes: []string{"X_SELL", "UPSELL", "PACK", "SUBSTITUTION"}, missing: "PACK"}
+	destRepo := &mockDestRepo{failing: "UPSELL"}
+
+	result, err := NewService(sourceRepo, destRepo).Sync(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(destRepo.saved) != 2 || destRepo.saved[0] != "X_SELL" || destRepo.saved[1] != "SUBSTITUTION" {
+		t.Errorf("Expected X_SELL and SUBSTITUTION to be saved, got %v", destRepo.saved)
+	}
+	if len(result.Types) != 4 || result.TypesSynced != 2 || result.Success {
+		t.Errorf("Expected 2 of 4 types synced, got %+v", result)
+	}
+
+	failures := result.Failures()
+	if len(failures) != 2 || failures[0].Code != "UPSELL" || failures[1].Code != "PACK" {
+		t.Errorf("Expected UPSELL and PACK to fail, got %v", failures)
+	}
+	if result.Types[2].Error == "" {
+		t.Error("Expected the error of the missing type to be reported")
+	}
+}
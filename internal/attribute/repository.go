@@ -15,13 +15,23 @@ type SourceRepository interface {
 
 	// GetOptions retrieves all options for an attribute
 	GetOptions(ctx context.Context, attributeCode string) ([]AttributeOption, error)
+
+	// FindPage retrieves one page of attributes, pages being numbered from 1.
+	// hasNext tells whether more pages follow
+	FindPage(ctx context.Context, page, limit int) (attributes []Attribute, hasNext bool, err error)
 }
 
-// DestRepository defines write operations for attributes to destination
+// DestRepository defines read and write operations for attributes in destination
 type DestRepository interface {
 	// Save creates or updates an attribute
 	Save(ctx context.Context, code string, attribute Attribute) error
 
+	// GetOptions retrieves all options for an attribute
+	GetOptions(ctx context.Context, attributeCode string) ([]AttributeOption, error)
+
 	// SaveOption creates or updates an attribute option
 	SaveOption(ctx context.Context, attributeCode, optionCode string, option AttributeOption) error
+
+	// FindCodes retrieves the codes of every attribute
+	FindCodes(ctx context.Context) (map[string]bool, error)
 }
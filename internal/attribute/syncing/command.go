@@ -1,16 +1,26 @@
 package syncing
 
-import "akeneo-migrator/kit/bus"
+import (
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/retry"
+)
 
 const SyncAttributeCommandType bus.Type = "attribute.sync"
 
 // SyncAttributeCommand represents a command to sync an attribute
 type SyncAttributeCommand struct {
-	Code  string
-	Debug bool
+	Code string
+	// WithGroup syncs the group of the attribute before it
+	WithGroup bool
+	Debug     bool
 }
 
 // Type returns the command type
 func (c SyncAttributeCommand) Type() bus.Type {
 	return SyncAttributeCommandType
 }
+
+// RetryItem returns the item targeted by the command
+func (c SyncAttributeCommand) RetryItem() retry.Failure {
+	return retry.Failure{Kind: KindAttribute, Code: c.Code}
+}
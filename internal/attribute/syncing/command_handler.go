@@ -25,7 +25,7 @@ func (h *CommandHandler) Handle(ctx context.Context, msg bus.Message) (bus.Respo
 		return bus.Response{}, nil
 	}
 
-	result, err := h.service.Sync(ctx, cmd.Code)
+	result, err := h.service.Sync(ctx, cmd.Code, SyncOptions{WithGroup: cmd.WithGroup})
 	if err != nil {
 		return bus.Response{Error: err}, err
 	}
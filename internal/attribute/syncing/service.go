@@ -3,22 +3,79 @@ package syncing
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"akeneo-migrator/internal/attribute"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/labels"
+	"akeneo-migrator/kit/retry"
 )
 
+// KindAttribute is the kind of item reported as failure
+const KindAttribute = "attribute"
+
+// KindAttributeOption is an option of an attribute, its scope; only reported in planned writes
+const KindAttributeOption = "attribute_option"
+
 // Service handles attribute synchronization
 type Service struct {
-	sourceRepo attribute.SourceRepository
-	destRepo   attribute.DestRepository
+	sourceRepo    attribute.SourceRepository
+	destRepo      attribute.DestRepository
+	labelStrategy labels.Strategy
+	attributeMap  map[string]string
+	syncGroup     GroupSyncFunc
+}
+
+// GroupSyncFunc synchronizes an attribute group from source to destination, without its attribute list
+type GroupSyncFunc func(ctx context.Context, code string) error
+
+// Option configures the attribute sync service
+type Option func(*Service)
+
+// WithLabelStrategy sets how option labels are merged with destination translations
+func WithLabelStrategy(strategy labels.Strategy) Option {
+	return func(s *Service) {
+		if strategy != "" {
+			s.labelStrategy = strategy
+		}
+	}
+}
+
+// WithAttributeMap sets the source → destination attribute code mapping; a mapped attribute and its
+// options are written to destination under its destination code
+func WithAttributeMap(attributeMap map[string]string) Option {
+	return func(s *Service) {
+		s.attributeMap = attributeMap
+	}
+}
+
+// WithGroupSync sets how the group of an attribute is synced before it when SyncOptions.WithGroup is set
+func WithGroupSync(syncGroup GroupSyncFunc) Option {
+	return func(s *Service) {
+		s.syncGroup = syncGroup
+	}
 }
 
 // NewService creates a new attribute sync service
-func NewService(sourceRepo attribute.SourceRepository, destRepo attribute.DestRepository) *Service {
-	return &Service{
-		sourceRepo: sourceRepo,
-		destRepo:   destRepo,
+func NewService(sourceRepo attribute.SourceRepository, destRepo attribute.DestRepository, opts ...Option) *Service {
+	service := &Service{
+		sourceRepo:    sourceRepo,
+		destRepo:      destRepo,
+		labelStrategy: labels.Overwrite,
+	}
+
+	for _, opt := range opts {
+		opt(service)
 	}
+
+	return service
+}
+
+// SyncOptions contains per-run options of an attribute sync
+type SyncOptions struct {
+	// WithGroup syncs the group of the attribute before it, so destination does not reject the
+	// attribute because its group is missing
+	WithGroup bool
 }
 
 // SyncResult contains the result of a sync operation
@@ -28,16 +85,41 @@ type SyncResult struct {
 	Error         string
 	OptionsSynced int
 	OptionsErrors []string
+	// DestCode is the code of the attribute in destination when it is mapped to another code
+	DestCode string
+	// Group is the attribute group synced before the attribute, with SyncOptions.WithGroup
+	Group string
+	// Planned are the writes recorded instead of being sent during a dry run
+	Planned []dryrun.Write
 }
 
-// Sync synchronizes a single attribute from source to destination
-func (s *Service) Sync(ctx context.Context, code string) (*SyncResult, error) {
-	result := &SyncResult{
-		Code:          code,
-		Success:       false,
-		OptionsSynced: 0,
-		OptionsErrors: []string{},
+// Failures returns the attribute when it or some of its options could not be synchronized
+func (r *SyncResult) Failures() []retry.Failure {
+	if r.Error != "" {
+		return []retry.Failure{{Kind: KindAttribute, Code: r.Code, Error: r.Error}}
+	}
+	if len(r.OptionsErrors) > 0 {
+		return []retry.Failure{{Kind: KindAttribute, Code: r.Code, Error: strings.Join(r.OptionsErrors, "; ")}}
 	}
+	return nil
+}
+
+// Synced returns the number of attributes and options written
+func (r *SyncResult) Synced() int {
+	if !r.Success {
+		return r.OptionsSynced
+	}
+	return 1 + r.OptionsSynced
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// Sync synchronizes a single attribute from source to destination, then its options
+func (s *Service) Sync(ctx context.Context, code string, opts SyncOptions) (*SyncResult, error) {
+	ctx, planned := dryrun.Collect(ctx)
 
 	// 1. Get attribute from source
 	attributeData, err := s.sourceRepo.FindByCode(ctx, code)
@@ -45,41 +127,195 @@ func (s *Service) Sync(ctx context.Context, code string) (*SyncResult, error) {
 		return nil, fmt.Errorf("error fetching attribute from source: %w", err)
 	}
 
-	// 2. Save attribute to destination
-	err = s.destRepo.Save(ctx, code, attributeData)
+	// 2. Sync its group first when asked to
+	group, _ := attributeData["group"].(string)
+	if opts.WithGroup && group != "" && s.syncGroup != nil {
+		if err := s.syncGroup(ctx, group); err != nil {
+			result := &SyncResult{Code: code, Group: group, Error: fmt.Sprintf("attribute group %s: %v", group, err)}
+			return result, fmt.Errorf("error syncing attribute group %s: %w", group, err)
+		}
+	}
+
+	// 3. Save attribute to destination
+	result, err := s.Save(ctx, code, attributeData)
+	if err != nil {
+		return result, err
+	}
+	if opts.WithGroup && s.syncGroup != nil {
+		result.Group = group
+	}
+
+	// 4. Save its options
+	s.SaveOptions(ctx, result, attributeData)
+
+	result.Planned = planned()
+	return result, nil
+}
+
+// Save writes an attribute read from source to destination, under its destination code when it is
+// mapped, without its options. The returned result is filled by SaveOptions.
+func (s *Service) Save(ctx context.Context, code string, attributeData attribute.Attribute) (*SyncResult, error) {
+	result := &SyncResult{
+		Code:          code,
+		OptionsErrors: []string{},
+	}
+
+	destCode := code
+	if mapped, exists := s.attributeMap[code]; exists {
+		destCode = mapped
+		attributeData = withCode(attributeData, "code", mapped)
+		result.DestCode = mapped
+	}
+
+	var err error
+	if !dryrun.Record(ctx, dryrun.Write{Kind: KindAttribute, Code: destCode, Data: attributeData}) {
+		err = s.destRepo.Save(ctx, destCode, attributeData)
+	}
 	if err != nil {
-		result.Success = false
 		result.Error = err.Error()
 		return result, fmt.Errorf("error saving attribute to destination: %w", err)
 	}
 
-	// 3. Check if attribute is of type select (simple or multi)
-	attributeType, ok := attributeData["type"].(string)
-	if ok && (attributeType == "pim_catalog_simpleselect" || attributeType == "pim_catalog_multiselect") {
-		// 4. Get attribute options from source
-		options, err := s.sourceRepo.GetOptions(ctx, code)
-		if err != nil {
-			// Log error but don't fail the entire sync
-			result.OptionsErrors = append(result.OptionsErrors, fmt.Sprintf("error fetching options: %v", err))
+	result.Success = true
+	return result, nil
+}
+
+// SaveOptions writes the options of a select attribute (simple or multi) to destination, in source
+// order, once the attribute exists there. Errors are recorded in the result without failing the attribute.
+func (s *Service) SaveOptions(ctx context.Context, result *SyncResult, attributeData attribute.Attribute) {
+	attributeType, _ := attributeData["type"].(string)
+	if attributeType != "pim_catalog_simpleselect" && attributeType != "pim_catalog_multiselect" {
+		return
+	}
+
+	code, destCode := result.Code, result.Code
+	if result.DestCode != "" {
+		destCode = result.DestCode
+	}
+
+	// 1. Get attribute options from source
+	options, err := s.sourceRepo.GetOptions(ctx, code)
+	if err != nil {
+		// Log error but don't fail the entire sync
+		result.OptionsErrors = append(result.OptionsErrors, fmt.Sprintf("error fetching options: %v", err))
+		return
+	}
+
+	destOptions, err := s.findDestOptions(ctx, destCode)
+	if err != nil {
+		// Without destination labels the merge strategy cannot be honoured
+		result.OptionsErrors = append(result.OptionsErrors, fmt.Sprintf("error fetching destination options: %v", err))
+		return
+	}
+
+	// 2. Sync each option to destination
+	for _, option := range options {
+		optionCode, ok := option["code"].(string)
+		if !ok {
+			result.OptionsErrors = append(result.OptionsErrors, "option without code field")
+			continue
+		}
+
+		destOption, exists := destOptions[optionCode]
+		option = s.mergeOptionLabels(option, destOption, exists)
+		if destCode != code {
+			option = withCode(option, "attribute", destCode)
+		}
+
+		if dryrun.Record(ctx, dryrun.Write{Kind: KindAttributeOption, Scope: destCode, Code: optionCode, Data: option}) {
+			result.OptionsSynced++
+			continue
+		}
+
+		if err := s.destRepo.SaveOption(ctx, destCode, optionCode, option); err != nil {
+			result.OptionsErrors = append(result.OptionsErrors, fmt.Sprintf("option %s: %v", optionCode, err))
 		} else {
-			// 5. Sync each option to destination
-			for _, option := range options {
-				optionCode, ok := option["code"].(string)
-				if !ok {
-					result.OptionsErrors = append(result.OptionsErrors, "option without code field")
-					continue
-				}
-
-				err := s.destRepo.SaveOption(ctx, code, optionCode, option)
-				if err != nil {
-					result.OptionsErrors = append(result.OptionsErrors, fmt.Sprintf("option %s: %v", optionCode, err))
-				} else {
-					result.OptionsSynced++
-				}
-			}
+			result.OptionsSynced++
 		}
 	}
+}
 
-	result.Success = true
-	return result, nil
+// CreateOption copies a single option of an attribute from source to destination with its labels, e.g.
+// when a product write is rejected because destination lacks it. The option code is matched regardless
+// of case, as Akeneo does.
+func (s *Service) CreateOption(ctx context.Context, code, optionCode string) error {
+	options, err := s.sourceRepo.GetOptions(ctx, code)
+	if err != nil {
+		return fmt.Errorf("error fetching options of attribute %s from source: %w", code, err)
+	}
+
+	var option attribute.AttributeOption
+	for _, candidate := range options {
+		if candidateCode, _ := candidate["code"].(string); strings.EqualFold(candidateCode, optionCode) {
+			option, optionCode = candidate, candidateCode
+			break
+		}
+	}
+	if option == nil {
+		return fmt.Errorf("option %s of attribute %s does not exist in source", optionCode, code)
+	}
+
+	destCode := code
+	if mapped, exists := s.attributeMap[code]; exists {
+		destCode = mapped
+		option = withCode(option, "attribute", mapped)
+	}
+	option = s.mergeOptionLabels(option, nil, false)
+
+	if dryrun.Record(ctx, dryrun.Write{Kind: KindAttributeOption, Scope: destCode, Code: optionCode, Data: option}) {
+		return nil
+	}
+	if err := s.destRepo.SaveOption(ctx, destCode, optionCode, option); err != nil {
+		return fmt.Errorf("error saving option %s of attribute %s: %w", optionCode, destCode, err)
+	}
+	return nil
+}
+
+// withCode returns a copy of an item with a code field replaced
+func withCode[T ~map[string]interface{}](item T, field, code string) T {
+	result := make(T, len(item))
+	for key, value := range item {
+		result[key] = value
+	}
+	result[field] = code
+	return result
+}
+
+// findDestOptions returns the destination options indexed by code.
+// They are only needed when labels are merged, so nothing is fetched with the overwrite strategy.
+func (s *Service) findDestOptions(ctx context.Context, code string) (map[string]attribute.AttributeOption, error) {
+	destOptions := make(map[string]attribute.AttributeOption)
+	if s.labelStrategy == labels.Overwrite {
+		return destOptions, nil
+	}
+
+	options, err := s.destRepo.GetOptions(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, option := range options {
+		if optionCode, ok := option["code"].(string); ok {
+			destOptions[optionCode] = option
+		}
+	}
+
+	return destOptions, nil
+}
+
+// mergeOptionLabels applies the label strategy to an option without modifying the source data
+func (s *Service) mergeOptionLabels(option, destOption attribute.AttributeOption, exists bool) attribute.AttributeOption {
+	merged := make(attribute.AttributeOption, len(option))
+	for key, value := range option {
+		merged[key] = value
+	}
+
+	optionLabels, send := labels.MergeLabels(s.labelStrategy, option["labels"], destOption["labels"], exists)
+	if send {
+		merged["labels"] = optionLabels
+	} else {
+		delete(merged, "labels")
+	}
+
+	return merged
 }
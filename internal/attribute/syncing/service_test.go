@@ -6,11 +6,13 @@ import (
 	"testing"
 
 	"akeneo-migrator/internal/attribute"
+	"akeneo-migrator/kit/labels"
 )
 
 // Mock repositories
 type mockSourceRepo struct {
 	findByCodeFunc func(ctx context.Context, code string) (attribute.Attribute, error)
+	getOptionsFunc func(ctx context.Context, attributeCode string) ([]attribute.AttributeOption, error)
 }
 
 func (m *mockSourceRepo) FindByCode(ctx context.Context, code string) (attribute.Attribute, error) {
@@ -20,8 +22,21 @@ func (m *mockSourceRepo) FindByCode(ctx context.Context, code string) (attribute
 	return nil, nil
 }
 
+func (m *mockSourceRepo) FindPage(ctx context.Context, page, limit int) ([]attribute.Attribute, bool, error) {
+	return nil, false, nil
+}
+
+func (m *mockSourceRepo) GetOptions(ctx context.Context, attributeCode string) ([]attribute.AttributeOption, error) {
+	if m.getOptionsFunc != nil {
+		return m.getOptionsFunc(ctx, attributeCode)
+	}
+	return nil, nil
+}
+
 type mockDestRepo struct {
-	saveFunc func(ctx context.Context, code string, attr attribute.Attribute) error
+	saveFunc       func(ctx context.Context, code string, attr attribute.Attribute) error
+	getOptionsFunc func(ctx context.Context, attributeCode string) ([]attribute.AttributeOption, error)
+	saveOptionFunc func(ctx context.Context, attributeCode, optionCode string, option attribute.AttributeOption) error
 }
 
 func (m *mockDestRepo) Save(ctx context.Context, code string, attr attribute.Attribute) error {
@@ -31,6 +46,24 @@ func (m *mockDestRepo) Save(ctx context.Context, code string, attr attribute.Att
 	return nil
 }
 
+func (m *mockDestRepo) GetOptions(ctx context.Context, attributeCode string) ([]attribute.AttributeOption, error) {
+	if m.getOptionsFunc != nil {
+		return m.getOptionsFunc(ctx, attributeCode)
+	}
+	return nil, nil
+}
+
+func (m *mockDestRepo) SaveOption(ctx context.Context, attributeCode, optionCode string, option attribute.AttributeOption) error {
+	if m.saveOptionFunc != nil {
+		return m.saveOptionFunc(ctx, attributeCode, optionCode, option)
+	}
+	return nil
+}
+
+func (m *mockDestRepo) FindCodes(ctx context.Context) (map[string]bool, error) {
+	return nil, nil
+}
+
 func TestSync_Success(t *testing.T) {
 	sourceRepo := &mockSourceRepo{
 		findByCodeFunc: func(ctx context.Context, code string) (attribute.Attribute, error) {
@@ -48,7 +81,7 @@ func TestSync_Success(t *testing.T) {
 	}
 
 	service := NewService(sourceRepo, destRepo)
-	result, err := service.Sync(context.Background(), "sku")
+	result, err := service.Sync(context.Background(), "sku", SyncOptions{})
 
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
@@ -63,6 +96,50 @@ func TestSync_Success(t *testing.T) {
 	}
 }
 
+func TestSync_WritesMappedAttributeCode(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (attribute.Attribute, error) {
+			return attribute.Attribute{"code": code, "type": "pim_catalog_simpleselect"}, nil
+		},
+		getOptionsFunc: func(ctx context.Context, attributeCode string) ([]attribute.AttributeOption, error) {
+			if attributeCode != "color" {
+				t.Errorf("Expected options to be read from the source attribute, got %s", attributeCode)
+			}
+			return []attribute.AttributeOption{{"code": "red", "attribute": "color"}}, nil
+		},
+	}
+
+	var savedCode, optionAttribute string
+	var savedOption attribute.AttributeOption
+	destRepo := &mockDestRepo{
+		saveFunc: func(ctx context.Context, code string, attr attribute.Attribute) error {
+			savedCode = code
+			if attr["code"] != code {
+				t.Errorf("Expected the payload code to match, got %v", attr["code"])
+			}
+			return nil
+		},
+		saveOptionFunc: func(ctx context.Context, attributeCode, optionCode string, option attribute.AttributeOption) error {
+			optionAttribute = attributeCode
+			savedOption = option
+			return nil
+		},
+	}
+
+	service := NewService(sourceRepo, destRepo, WithAttributeMap(map[string]string{"color": "main_color"}))
+	result, err := service.Sync(context.Background(), "color", SyncOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.DestCode != "main_color" || savedCode != "main_color" {
+		t.Errorf("Expected the attribute to be written as main_color, got %s", savedCode)
+	}
+	if optionAttribute != "main_color" || savedOption["attribute"] != "main_color" {
+		t.Errorf("Expected options to be written to main_color, got %s (%v)", optionAttribute, savedOption)
+	}
+}
+
 func TestSync_SourceError(t *testing.T) {
 	sourceRepo := &mockSourceRepo{
 		findByCodeFunc: func(ctx context.Context, code string) (attribute.Attribute, error) {
@@ -73,7 +150,7 @@ func TestSync_SourceError(t *testing.T) {
 	destRepo := &mockDestRepo{}
 
 	service := NewService(sourceRepo, destRepo)
-	_, err := service.Sync(context.Background(), "sku")
+	_, err := service.Sync(context.Background(), "sku", SyncOptions{})
 
 	if err == nil {
 		t.Error("Expected error, got nil")
@@ -97,7 +174,7 @@ func TestSync_DestError(t *testing.T) {
 	}
 
 	service := NewService(sourceRepo, destRepo)
-	result, err := service.Sync(context.Background(), "sku")
+	result, err := service.Sync(context.Background(), "sku", SyncOptions{})
 
 	if err == nil {
 		t.Error("Expected error, got nil")
@@ -111,3 +188,209 @@ func TestSync_DestError(t *testing.T) {
 		t.Error("Expected error message in result")
 	}
 }
+
+func TestSync_OptionLabelsUnion(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (attribute.Attribute, error) {
+			return attribute.Attribute{"code": code, "type": "pim_catalog_simpleselect"}, nil
+		},
+		getOptionsFunc: func(ctx context.Context, attributeCode string) ([]attribute.AttributeOption, error) {
+			return []attribute.AttributeOption{
+				{"code": "red", "labels": map[string]interface{}{"en_US": "Red", "fr_FR": "Rouge"}},
+				{"code": "blue", "labels": map[string]interface{}{"en_US": "Blue"}},
+			}, nil
+		},
+	}
+
+	saved := map[string]attribute.AttributeOption{}
+	destRepo := &mockDestRepo{
+		getOptionsFunc: func(ctx context.Context, attributeCode string) ([]attribute.AttributeOption, error) {
+			return []attribute.AttributeOption{
+				{"code": "red", "labels": map[string]interface{}{"fr_FR": "Rouge vif"}},
+			}, nil
+		},
+		saveOptionFunc: func(ctx context.Context, attributeCode, optionCode string, option attribute.AttributeOption) error {
+			saved[optionCode] = option
+			return nil
+		},
+	}
+
+	service := NewService(sourceRepo, destRepo, WithLabelStrategy(labels.Union))
+	result, err := service.Sync(context.Background(), "color", SyncOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.OptionsSynced != 2 {
+		t.Errorf("Expected 2 options synced, got %d", result.OptionsSynced)
+	}
+
+	redLabels := saved["red"]["labels"].(map[string]interface{})
+	if redLabels["fr_FR"] != "Rouge vif" {
+		t.Errorf("Expected destination translation to be kept, got %v", redLabels["fr_FR"])
+	}
+	if redLabels["en_US"] != "Red" {
+		t.Errorf("Expected missing locale from source, got %v", redLabels["en_US"])
+	}
+
+	blueLabels := saved["blue"]["labels"].(map[string]interface{})
+	if blueLabels["en_US"] != "Blue" {
+		t.Errorf("Expected new option to get source labels, got %v", blueLabels)
+	}
+}
+
+func TestSync_SyncsOptionsAfterAttribute(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (attribute.Attribute, error) {
+			return attribute.Attribute{"code": code, "type": "pim_catalog_multiselect"}, nil
+		},
+		getOptionsFunc: func(ctx context.Context, attributeCode string) ([]attribute.AttributeOption, error) {
+			return []attribute.AttributeOption{
+				{"code": "cotton", "attribute": attributeCode},
+				{"code": "wool", "attribute": attributeCode},
+				{"code": "silk", "attribute": attributeCode},
+			}, nil
+		},
+	}
+
+	var calls []string
+	destRepo := &mockDestRepo{
+		saveFunc: func(ctx context.Context, code string, attr attribute.Attribute) error {
+			calls = append(calls, "attribute "+code)
+			return nil
+		},
+		saveOptionFunc: func(ctx context.Context, attributeCode, optionCode string, option attribute.AttributeOption) error {
+			calls = append(calls, "option "+optionCode)
+			if optionCode == "silk" {
+				return errors.New("rejected")
+			}
+			return nil
+		},
+	}
+
+	service := NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background(), "material", SyncOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(calls) != 4 || calls[0] != "attribute material" {
+		t.Errorf("Expected the attribute to be saved before its options, got %v", calls)
+	}
+
+	if !result.Success || result.OptionsSynced != 2 {
+		t.Errorf("Expected attribute and 2 options synced, got %+v", result)
+	}
+
+	if len(result.OptionsErrors) != 1 || len(result.Failures()) != 1 {
+		t.Errorf("Expected the rejected option to be reported, got %v", result.OptionsErrors)
+	}
+
+	if result.Synced() != 3 {
+		t.Errorf("Expected 3 items written, got %d", result.Synced())
+	}
+}
+
+func TestSync_SkipsOptionsOfOtherTypes(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (attribute.Attribute, error) {
+			return attribute.Attribute{"code": code, "type": "pim_catalog_text"}, nil
+		},
+		getOptionsFunc: func(ctx context.Context, attributeCode string) ([]attribute.AttributeOption, error) {
+			t.Error("Expected options not to be fetched for a text attribute")
+			return nil, nil
+		},
+	}
+
+	service := NewService(sourceRepo, &mockDestRepo{})
+	if _, err := service.Sync(context.Background(), "name", SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestSync_SyncsGroupFirst(t *testing.T) {
+	var writes []string
+	sourceRepo := &mockSourceRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (attribute.Attribute, error) {
+			return attribute.Attribute{"code": code, "type": "pim_catalog_text", "group": "marketing"}, nil
+		},
+	}
+	destRepo := &mockDestRepo{
+		saveFunc: func(ctx context.Context, code string, attr attribute.Attribute) error {
+			writes = append(writes, "attribute "+code)
+			return nil
+		},
+	}
+	rejectGroup := false
+	syncGroup := func(ctx context.Context, code string) error {
+		writes = append(writes, "group "+code)
+		if rejectGroup {
+			return errors.New("group rejected")
+		}
+		return nil
+	}
+	service := NewService(sourceRepo, destRepo, WithGroupSync(syncGroup))
+
+	// Without WithGroup, the group is left as it is
+	if _, err := service.Sync(context.Background(), "name", SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	result, err := service.Sync(context.Background(), "name", SyncOptions{WithGroup: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(writes) != 3 || writes[1] != "group marketing" || writes[2] != "attribute name" || result.Group != "marketing" {
+		t.Errorf("Expected the group to be written before the attribute, got %v", writes)
+	}
+
+	// A group that fails stops the attribute
+	rejectGroup = true
+	result, err = service.Sync(context.Background(), "name", SyncOptions{WithGroup: true})
+	if err == nil || result.Success || len(writes) != 4 {
+		t.Errorf("Expected the attribute not to be written after its group failed, got %v", writes)
+	}
+}
+
+func TestCreateOption_CopiesSourceOption(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		getOptionsFunc: func(ctx context.Context, attributeCode string) ([]attribute.AttributeOption, error) {
+			return []attribute.AttributeOption{
+				{"code": "red", "attribute": "color", "labels": map[string]interface{}{"en_US": "Red"}},
+				{"code": "Navy", "attribute": "color", "labels": map[string]interface{}{"en_US": "Navy blue"}},
+			}, nil
+		},
+	}
+
+	var saved []string
+	var savedOption attribute.AttributeOption
+	destRepo := &mockDestRepo{
+		saveOptionFunc: func(ctx context.Context, attributeCode, optionCode string, option attribute.AttributeOption) error {
+			saved = append(saved, attributeCode+"."+optionCode)
+			savedOption = option
+			return nil
+		},
+	}
+
+	service := NewService(sourceRepo, destRepo)
+
+	// Akeneo reports option codes in the case of the value sent
+	if err := service.CreateOption(context.Background(), "color", "navy"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(saved) != 1 || saved[0] != "color.Navy" {
+		t.Fatalf("Expected only color.Navy to be saved, got %v", saved)
+	}
+	if optionLabels, _ := savedOption["labels"].(map[string]interface{}); optionLabels["en_US"] != "Navy blue" {
+		t.Errorf("Expected the source labels to be copied, got %v", savedOption["labels"])
+	}
+
+	if err := service.CreateOption(context.Background(), "color", "teal"); err == nil {
+		t.Error("Expected an error for an option missing in source")
+	}
+	if len(saved) != 1 {
+		t.Errorf("Expected nothing saved for an option missing in source, got %v", saved)
+	}
+}
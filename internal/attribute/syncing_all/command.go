@@ -0,0 +1,17 @@
+package syncing_all
+
+import "akeneo-migrator/kit/bus"
+
+const SyncAllAttributesCommandType bus.Type = "attribute.sync_all"
+
+// SyncAllAttributesCommand represents a command to sync every attribute of the source
+type SyncAllAttributesCommand struct {
+	// Progress is called after each attribute; it may be nil
+	Progress ProgressFunc
+	Debug    bool
+}
+
+// Type returns the command type
+func (c SyncAllAttributesCommand) Type() bus.Type {
+	return SyncAllAttributesCommandType
+}
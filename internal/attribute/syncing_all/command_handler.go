@@ -0,0 +1,34 @@
+package syncing_all
+
+import (
+	"context"
+
+	"akeneo-migrator/kit/bus"
+)
+
+// CommandHandler handles SyncAllAttributesCommand
+type CommandHandler struct {
+	service *Service
+}
+
+// NewCommandHandler creates a new command handler
+func NewCommandHandler(service *Service) *CommandHandler {
+	return &CommandHandler{
+		service: service,
+	}
+}
+
+// Handle executes the sync command
+func (h *CommandHandler) Handle(ctx context.Context, msg bus.Message) (bus.Response, error) {
+	cmd, ok := msg.(SyncAllAttributesCommand)
+	if !ok {
+		return bus.Response{}, nil
+	}
+
+	result, err := h.service.Sync(ctx, SyncOptions{Progress: cmd.Progress})
+	if err != nil {
+		return bus.Response{Error: err}, err
+	}
+
+	return bus.Response{Data: result}, nil
+}
@@ -0,0 +1,205 @@
+package syncing_all
+
+import (
+	"context"
+
+	"akeneo-migrator/internal/attribute"
+	"akeneo-migrator/internal/attribute/syncing"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/retry"
+)
+
+// PageSize is the number of attributes fetched from source at a time
+const PageSize = 100
+
+// KindAttributeGroup is the kind of the attribute groups reported as failures, shared with
+// the attribute group sync so retry-failed reprocesses them
+const KindAttributeGroup = "attribute_group"
+
+// GroupSyncFunc synchronizes an attribute group from source to destination, without its attribute list
+type GroupSyncFunc func(ctx context.Context, code string) error
+
+// Step is a phase of the sync reported to the progress function
+type Step string
+
+const (
+	// StepAttributes is reported after each attribute is written
+	StepAttributes Step = "attributes"
+	// StepOptions is reported after the options of each select attribute are written
+	StepOptions Step = "options"
+)
+
+// ProgressFunc is called after each attribute of a step with the number of attributes processed
+// so far in that step
+type ProgressFunc func(step Step, processed int, result *syncing.SyncResult)
+
+// Service synchronizes every attribute of the source, with its options and group
+type Service struct {
+	sourceRepo     attribute.SourceRepository
+	syncGroup      GroupSyncFunc
+	syncingService *syncing.Service
+}
+
+// NewService creates a new instance of the all attributes sync service.
+// syncGroup creates the groups of the attributes before them; groups are not synced when it is nil.
+// Options are passed to the composed attribute sync service
+func NewService(sourceRepo attribute.SourceRepository, destRepo attribute.DestRepository, syncGroup GroupSyncFunc, opts ...syncing.Option) *Service {
+	return &Service{
+		sourceRepo:     sourceRepo,
+		syncGroup:      syncGroup,
+		syncingService: syncing.NewService(sourceRepo, destRepo, opts...),
+	}
+}
+
+// SyncOptions contains per-run options of the sync
+type SyncOptions struct {
+	Progress ProgressFunc
+}
+
+// GroupResult is the status of an attribute group synced before the attributes
+type GroupResult struct {
+	Code  string
+	Error string
+}
+
+// SyncResult contains the result of syncing every attribute
+type SyncResult struct {
+	// Groups are the results of each attribute group, in order of first use by an attribute
+	Groups []GroupResult
+	// Attributes are the results of each attribute, in source order
+	Attributes       []*syncing.SyncResult
+	AttributesSynced int
+	OptionsSynced    int
+	GroupsSynced     int
+	Success          bool
+	FailedItems      []retry.Failure
+	// Planned are the writes recorded instead of being sent during a dry run, for the whole sync
+	Planned []dryrun.Write
+}
+
+// Failures returns the attributes and groups that could not be synchronized
+func (r *SyncResult) Failures() []retry.Failure {
+	return r.FailedItems
+}
+
+// Synced returns the number of attributes, options and groups written
+func (r *SyncResult) Synced() int {
+	return r.AttributesSynced + r.OptionsSynced + r.GroupsSynced
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// Sync migrates the attribute catalog of the source in three steps, so that every write finds its
+// dependencies in destination: the groups of the attributes, then the attributes, then the options
+// of select attributes. Items are written in source order with their sort_order, keeping the order
+// of groups, attributes and options. An item that fails is reported and does not stop the others;
+// the options of an attribute that failed are skipped.
+func (s *Service) Sync(ctx context.Context, opts SyncOptions) (*SyncResult, error) {
+	result := &SyncResult{}
+	ctx, planned := dryrun.Collect(ctx)
+
+	attributes, err := s.findAttributes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. Groups first: destination rejects attributes of unknown groups
+	if s.syncGroup != nil {
+		for _, group := range attributeGroups(attributes) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			groupResult := GroupResult{Code: group}
+			if err := s.syncGroup(ctx, group); err != nil {
+				groupResult.Error = err.Error()
+				result.FailedItems = append(result.FailedItems, retry.Failure{Kind: KindAttributeGroup, Code: group, Error: err.Error()})
+			} else {
+				result.GroupsSynced++
+			}
+			result.Groups = append(result.Groups, groupResult)
+		}
+	}
+
+	// 2. Attributes, without their options
+	for _, attr := range attributes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		code, _ := attr["code"].(string)
+		// A failed attribute keeps its error in the result
+		attributeResult, _ := s.syncingService.Save(ctx, code, attr)
+		result.Attributes = append(result.Attributes, attributeResult)
+		if attributeResult.Success {
+			result.AttributesSynced++
+		}
+
+		if opts.Progress != nil {
+			opts.Progress(StepAttributes, len(result.Attributes), attributeResult)
+		}
+	}
+
+	// 3. Options of the attributes written
+	processed := 0
+	for i, attr := range attributes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		attributeResult := result.Attributes[i]
+		if attributeResult.Success {
+			s.syncingService.SaveOptions(ctx, attributeResult, attr)
+			result.OptionsSynced += attributeResult.OptionsSynced
+		}
+		result.FailedItems = append(result.FailedItems, attributeResult.Failures()...)
+
+		if attributeResult.OptionsSynced > 0 || len(attributeResult.OptionsErrors) > 0 {
+			processed++
+			if opts.Progress != nil {
+				opts.Progress(StepOptions, processed, attributeResult)
+			}
+		}
+	}
+
+	result.Planned = planned()
+	result.Success = len(result.FailedItems) == 0
+	return result, nil
+}
+
+// findAttributes reads every attribute of the source page by page, skipping those without code
+func (s *Service) findAttributes(ctx context.Context) ([]attribute.Attribute, error) {
+	var attributes []attribute.Attribute
+	for page := 1; ; page++ {
+		items, hasNext, err := s.sourceRepo.FindPage(ctx, page, PageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, attr := range items {
+			if code, _ := attr["code"].(string); code != "" {
+				attributes = append(attributes, attr)
+			}
+		}
+
+		if !hasNext {
+			return attributes, nil
+		}
+	}
+}
+
+// attributeGroups returns the groups of the attributes, in order of first use
+func attributeGroups(attributes []attribute.Attribute) []string {
+	var groups []string
+	seen := make(map[string]bool)
+	for _, attr := range attributes {
+		if group, _ := attr["group"].(string); group != "" && !seen[group] {
+			seen[group] = true
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
@@ -0,0 +1,171 @@
+package syncing_all
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"akeneo-migrator/internal/attribute"
+	"akeneo-migrator/internal/attribute/syncing"
+)
+
+// mockSourceRepo serves attributes in two pages
+type mockSourceRepo struct {
+	pages [][]attribute.Attribute
+}
+
+func (m *mockSourceRepo) FindByCode(ctx context.Context, code string) (attribute.Attribute, error) {
+	for _, page := range m.pages {
+		for _, attr := range page {
+			if attr["code"] == code {
+				return attr, nil
+			}
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockSourceRepo) GetOptions(ctx context.Context, attributeCode string) ([]attribute.AttributeOption, error) {
+	return []attribute.AttributeOption{{"code": "red"}, {"code": "blue"}}, nil
+}
+
+func (m *mockSourceRepo) FindPage(ctx context.Context, page, limit int) ([]attribute.Attribute, bool, error) {
+	return m.pages[page-1], page < len(m.pages), nil
+}
+
+// mockDestRepo rejects the attribute named in failing
+type mockDestRepo struct {
+	failing string
+}
+
+func (m *mockDestRepo) Save(ctx context.Context, code string, attr attribute.Attribute) error {
+	if code == m.failing {
+		return errors.New("validation error")
+	}
+	return nil
+}
+
+func (m *mockDestRepo) GetOptions(ctx context.Context, attributeCode string) ([]attribute.AttributeOption, error) {
+	return nil, nil
+}
+
+func (m *mockDestRepo) SaveOption(ctx context.Context, attributeCode, optionCode string, option attribute.AttributeOption) error {
+	return nil
+}
+
+func (m *mockDestRepo) FindCodes(ctx context.Context) (map[string]bool, error) {
+	return nil, nil
+}
+
+// recordingDestRepo records the attributes, with their sort order, and the options written
+type recordingDestRepo struct {
+	mockDestRepo
+	writes *[]string
+}
+
+func (m *recordingDestRepo) Save(ctx context.Context, code string, attr attribute.Attribute) error {
+	*m.writes = append(*m.writes, fmt.Sprintf("attribute %s %v", code, attr["sort_order"]))
+	return nil
+}
+
+func (m *recordingDestRepo) SaveOption(ctx context.Context, attributeCode, optionCode string, option attribute.AttributeOption) error {
+	*m.writes = append(*m.writes, "option "+attributeCode+" "+optionCode)
+	return nil
+}
+
+func TestSync_SyncsGroupsOnceBeforeAttributes(t *testing.T) {
+	sourceRepo := &mockSourceRepo{pages: [][]attribute.Attribute{
+		{
+			{"code": "name", "type": "pim_catalog_text", "group": "marketing"},
+			{"code": "color", "type": "pim_catalog_simpleselect", "group": "technical"},
+		},
+		{
+			{"code": "description", "type": "pim_catalog_textarea", "group": "marketing"},
+			{"code": "weight", "type": "pim_catalog_metric", "group": "logistics"},
+		},
+	}}
+	destRepo := &mockDestRepo{failing: "description"}
+
+	var groups []string
+	syncGroup := func(ctx context.Context, code string) error {
+		groups = append(groups, code)
+		if code == "logistics" {
+			return errors.New("group rejected")
+		}
+		return nil
+	}
+
+	progress := make(map[Step][]int)
+	result, err := NewService(sourceRepo, destRepo, syncGroup).Sync(context.Background(), SyncOptions{
+		Progress: func(step Step, processed int, attributeResult *syncing.SyncResult) {
+			progress[step] = append(progress[step], processed)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(groups) != 3 || groups[0] != "marketing" || groups[1] != "technical" || groups[2] != "logistics" {
+		t.Errorf("Expected each group to be synced once in order of appearance, got %v", groups)
+	}
+
+	if result.AttributesSynced != 3 || result.OptionsSynced != 2 || result.GroupsSynced != 2 {
+		t.Errorf("Expected 3 attributes, 2 options and 2 groups synced, got %+v", result)
+	}
+
+	if attributes := progress[StepAttributes]; len(attributes) != 4 || attributes[3] != 4 {
+		t.Errorf("Expected progress after each attribute, got %v", attributes)
+	}
+	if options := progress[StepOptions]; len(options) != 1 {
+		t.Errorf("Expected progress after the options of the select attribute, got %v", options)
+	}
+
+	if len(result.Groups) != 3 || result.Groups[2].Code != "logistics" || result.Groups[2].Error == "" {
+		t.Errorf("Expected the status of each group, got %+v", result.Groups)
+	}
+
+	// Groups are synced before the attributes, so they are reported first
+	failures := result.Failures()
+	if len(failures) != 2 || result.Success {
+		t.Fatalf("Expected the group and the attribute to be reported, got %v", failures)
+	}
+	if failures[0].Kind != KindAttributeGroup || failures[0].Code != "logistics" {
+		t.Errorf("Expected the logistics group to fail, got %v", failures[0])
+	}
+	if failures[1].Kind != syncing.KindAttribute || failures[1].Code != "description" {
+		t.Errorf("Expected the description attribute to fail, got %v", failures[1])
+	}
+}
+
+func TestSync_WritesGroupsThenAttributesThenOptions(t *testing.T) {
+	sourceRepo := &mockSourceRepo{pages: [][]attribute.Attribute{{
+		{"code": "color", "type": "pim_catalog_simpleselect", "group": "technical", "sort_order": 2},
+		{"code": "size", "type": "pim_catalog_simpleselect", "group": "technical", "sort_order": 1},
+	}}}
+
+	var writes []string
+	destRepo := &recordingDestRepo{writes: &writes}
+	syncGroup := func(ctx context.Context, code string) error {
+		writes = append(writes, "group "+code)
+		return nil
+	}
+
+	if _, err := NewService(sourceRepo, destRepo, syncGroup).Sync(context.Background(), SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{
+		"group technical",
+		"attribute color 2", "attribute size 1",
+		"option color red", "option color blue", "option size red", "option size blue",
+	}
+	if len(writes) != len(expected) {
+		t.Fatalf("Expected writes %v, got %v", expected, writes)
+	}
+	for i := range expected {
+		if writes[i] != expected[i] {
+			t.Errorf("Expected write %d to be %s, got %s", i, expected[i], writes[i])
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package attribute_group
+
+import "context"
+
+// AttributeGroup represents an attribute group
+type AttributeGroup map[string]interface{}
+
+// SourceRepository defines read-only operations for attribute groups from source
+type SourceRepository interface {
+	// FindByCode retrieves an attribute group by its code
+	FindByCode(ctx context.Context, code string) (AttributeGroup, error)
+}
+
+// DestRepository defines read and write operations for attribute groups in destination
+type DestRepository interface {
+	// Save creates or updates an attribute group
+	Save(ctx context.Context, code string, group AttributeGroup) error
+}
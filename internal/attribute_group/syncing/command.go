@@ -0,0 +1,25 @@
+package syncing
+
+import (
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/retry"
+)
+
+const SyncAttributeGroupCommandType bus.Type = "attribute_group.sync"
+
+// SyncAttributeGroupCommand represents a command to sync an attribute group
+type SyncAttributeGroupCommand struct {
+	Code           string
+	WithAttributes bool
+	Debug          bool
+}
+
+// Type returns the command type
+func (c SyncAttributeGroupCommand) Type() bus.Type {
+	return SyncAttributeGroupCommandType
+}
+
+// RetryItem returns the item targeted by the command
+func (c SyncAttributeGroupCommand) RetryItem() retry.Failure {
+	return retry.Failure{Kind: KindAttributeGroup, Code: c.Code}
+}
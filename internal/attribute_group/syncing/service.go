@@ -0,0 +1,120 @@
+package syncing
+
+import (
+	"context"
+	"fmt"
+
+	"akeneo-migrator/internal/attribute_group"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/retry"
+)
+
+// KindAttributeGroup is the kind of item reported as failure
+const KindAttributeGroup = "attribute_group"
+
+// Service handles attribute group synchronization
+type Service struct {
+	sourceRepo attribute_group.SourceRepository
+	destRepo   attribute_group.DestRepository
+}
+
+// NewService creates a new attribute group sync service
+func NewService(sourceRepo attribute_group.SourceRepository, destRepo attribute_group.DestRepository) *Service {
+	return &Service{
+		sourceRepo: sourceRepo,
+		destRepo:   destRepo,
+	}
+}
+
+// SyncOptions contains per-run options of an attribute group sync
+type SyncOptions struct {
+	// WithAttributes also sends the list of attributes of the group. Akeneo rejects the group when
+	// one of them does not exist in destination, so by default the list is left out and attributes
+	// join the group when they are synced themselves.
+	WithAttributes bool
+}
+
+// SyncResult contains the result of a sync operation
+type SyncResult struct {
+	Code       string
+	Success    bool
+	Error      string
+	Attributes []string
+	// Planned are the writes recorded instead of being sent during a dry run
+	Planned []dryrun.Write
+}
+
+// Failures returns the attribute group when it could not be synchronized
+func (r *SyncResult) Failures() []retry.Failure {
+	if r.Success {
+		return nil
+	}
+	return []retry.Failure{{Kind: KindAttributeGroup, Code: r.Code, Error: r.Error}}
+}
+
+// Synced returns the number of attribute groups written
+func (r *SyncResult) Synced() int {
+	if r.Success {
+		return 1
+	}
+	return 0
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// Sync synchronizes a single attribute group from source to destination
+func (s *Service) Sync(ctx context.Context, code string, opts SyncOptions) (*SyncResult, error) {
+	result := &SyncResult{
+		Code:       code,
+		Success:    false,
+		Attributes: []string{},
+	}
+	ctx, planned := dryrun.Collect(ctx)
+
+	// 1. Get attribute group from source
+	sourceGroup, err := s.sourceRepo.FindByCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching attribute group from source: %w", err)
+	}
+
+	groupData := make(attribute_group.AttributeGroup, len(sourceGroup))
+	for key, value := range sourceGroup {
+		groupData[key] = value
+	}
+
+	// 2. Keep the attribute list only when asked to
+	if opts.WithAttributes {
+		result.Attributes = stringList(groupData["attributes"])
+	} else {
+		delete(groupData, "attributes")
+	}
+
+	// 3. Save attribute group to destination
+	if !dryrun.Record(ctx, dryrun.Write{Kind: KindAttributeGroup, Code: code, Data: groupData}) {
+		err = s.destRepo.Save(ctx, code, groupData)
+	}
+	if err != nil {
+		result.Success = false
+		result.Error = err.Error()
+		return result, fmt.Errorf("error saving attribute group to destination: %w", err)
+	}
+
+	result.Planned = planned()
+	result.Success = true
+	return result, nil
+}
+
+// stringList converts a decoded JSON list to a list of strings
+func stringList(value interface{}) []string {
+	items, _ := value.([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if text, ok := item.(string); ok {
+			result = append(result, text)
+		}
+	}
+	return result
+}
@@ -0,0 +1,106 @@
+package syncing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"akeneo-migrator/internal/attribute_group"
+)
+
+// Mock repositories
+type mockSourceRepo struct {
+	group attribute_group.AttributeGroup
+	err   error
+}
+
+func (m *mockSourceRepo) FindByCode(ctx context.Context, code string) (attribute_group.AttributeGroup, error) {
+	return m.group, m.err
+}
+
+type mockDestRepo struct {
+	saved attribute_group.AttributeGroup
+	err   error
+}
+
+func (m *mockDestRepo) Save(ctx context.Context, code string, group attribute_group.AttributeGroup) error {
+	m.saved = group
+	return m.err
+}
+
+func newMarketingGroup() attribute_group.AttributeGroup {
+	return attribute_group.AttributeGroup{
+		"code":       "marketing",
+		"sort_order": float64(2),
+		"attributes": []interface{}{"name", "description"},
+		"labels":     map[string]interface{}{"en_US": "Marketing"},
+	}
+}
+
+func TestSync_LeavesAttributesOutByDefault(t *testing.T) {
+	sourceRepo := &mockSourceRepo{group: newMarketingGroup()}
+	destRepo := &mockDestRepo{}
+
+	service := NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background(), "marketing", SyncOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !result.Success || result.Synced() != 1 {
+		t.Errorf("Expected the group to be synced, got %+v", result)
+	}
+
+	if _, exists := destRepo.saved["attributes"]; exists {
+		t.Error("Expected the attribute list not to be sent")
+	}
+
+	if destRepo.saved["labels"] == nil || destRepo.saved["sort_order"] != float64(2) {
+		t.Errorf("Expected labels and sort order to be sent, got %v", destRepo.saved)
+	}
+
+	if _, exists := sourceRepo.group["attributes"]; !exists {
+		t.Error("Expected source group not to be modified")
+	}
+}
+
+func TestSync_WithAttributes(t *testing.T) {
+	destRepo := &mockDestRepo{}
+
+	service := NewService(&mockSourceRepo{group: newMarketingGroup()}, destRepo)
+	result, err := service.Sync(context.Background(), "marketing", SyncOptions{WithAttributes: true})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.Attributes) != 2 || result.Attributes[0] != "name" {
+		t.Errorf("Expected attributes name and description, got %v", result.Attributes)
+	}
+
+	if _, exists := destRepo.saved["attributes"]; !exists {
+		t.Error("Expected the attribute list to be sent")
+	}
+}
+
+func TestSync_DestError(t *testing.T) {
+	service := NewService(&mockSourceRepo{group: newMarketingGroup()}, &mockDestRepo{err: errors.New("dest error")})
+	result, err := service.Sync(context.Background(), "marketing", SyncOptions{})
+
+	if err == nil {
+		t.Error("Expected error, got nil")
+	}
+
+	if result.Success || len(result.Failures()) != 1 {
+		t.Errorf("Expected the group to be reported as failed, got %+v", result)
+	}
+}
+
+func TestSync_SourceError(t *testing.T) {
+	service := NewService(&mockSourceRepo{err: errors.New("source error")}, &mockDestRepo{})
+
+	if _, err := service.Sync(context.Background(), "marketing", SyncOptions{}); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
@@ -1,6 +1,12 @@
 package category
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned when a category does not exist in the instance
+var ErrNotFound = errors.New("category not found")
 
 // Category represents a category
 type Category map[string]interface{}
@@ -9,10 +15,25 @@ type Category map[string]interface{}
 type SourceRepository interface {
 	// FindByCode retrieves a category by its code
 	FindByCode(ctx context.Context, code string) (Category, error)
+
+	// FindChildren retrieves the direct children of a category
+	FindChildren(ctx context.Context, parentCode string) ([]Category, error)
+
+	// FindRootCodes retrieves the codes of the roots of every category tree
+	FindRootCodes(ctx context.Context) ([]string, error)
 }
 
-// DestRepository defines write operations for categories to destination
+// DestRepository defines read and write operations for categories in destination
 type DestRepository interface {
+	// FindByCode retrieves a category by its code
+	FindByCode(ctx context.Context, code string) (Category, error)
+
 	// Save creates or updates a category
 	Save(ctx context.Context, code string, category Category) error
+
+	// FindChildren retrieves the direct children of a category
+	FindChildren(ctx context.Context, parentCode string) ([]Category, error)
+
+	// FindProductIdentifiers retrieves the products classified in a category or its children
+	FindProductIdentifiers(ctx context.Context, code string) ([]string, error)
 }
@@ -1,6 +1,9 @@
 package syncing
 
-import "akeneo-migrator/kit/bus"
+import (
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/retry"
+)
 
 const SyncCategoryCommandType bus.Type = "category.sync"
 
@@ -14,3 +17,8 @@ type SyncCategoryCommand struct {
 func (c SyncCategoryCommand) Type() bus.Type {
 	return SyncCategoryCommandType
 }
+
+// RetryItem returns the item targeted by the command
+func (c SyncCategoryCommand) RetryItem() retry.Failure {
+	return retry.Failure{Kind: KindCategory, Code: c.Code}
+}
@@ -5,20 +5,55 @@ import (
 	"fmt"
 
 	"akeneo-migrator/internal/category"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/retry"
+)
+
+// KindCategory is the kind of item reported as failure
+const KindCategory = "category"
+
+// MovePolicy defines how a category whose parent differs in destination is handled
+type MovePolicy string
+
+const (
+	// MovePolicyApply moves the category under its source parent
+	MovePolicyApply MovePolicy = "apply"
+	// MovePolicyWarn keeps the destination parent and only reports the move
+	MovePolicyWarn MovePolicy = "warn"
 )
 
 // Service handles category synchronization
 type Service struct {
 	sourceRepo category.SourceRepository
 	destRepo   category.DestRepository
+	movePolicy MovePolicy
+}
+
+// Option configures the category sync service
+type Option func(*Service)
+
+// WithMovePolicy sets how detected category moves are handled
+func WithMovePolicy(policy MovePolicy) Option {
+	return func(s *Service) {
+		if policy != "" {
+			s.movePolicy = policy
+		}
+	}
 }
 
 // NewService creates a new category sync service
-func NewService(sourceRepo category.SourceRepository, destRepo category.DestRepository) *Service {
-	return &Service{
+func NewService(sourceRepo category.SourceRepository, destRepo category.DestRepository, opts ...Option) *Service {
+	service := &Service{
 		sourceRepo: sourceRepo,
 		destRepo:   destRepo,
+		movePolicy: MovePolicyApply,
+	}
+
+	for _, opt := range opts {
+		opt(service)
 	}
+
+	return service
 }
 
 // SyncResult contains the result of a sync operation
@@ -26,6 +61,40 @@ type SyncResult struct {
 	Code    string
 	Success bool
 	Error   string
+	Move    *Move
+	// Planned are the writes recorded instead of being sent during a dry run
+	Planned []dryrun.Write
+}
+
+// Failures returns the category when it could not be synchronized
+func (r *SyncResult) Failures() []retry.Failure {
+	if r.Error == "" {
+		return nil
+	}
+	return []retry.Failure{{Kind: KindCategory, Code: r.Code, Error: r.Error}}
+}
+
+// Synced returns the number of categories written
+func (r *SyncResult) Synced() int {
+	if r.Success {
+		return 1
+	}
+	return 0
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// Move describes a category whose parent differs between source and destination
+type Move struct {
+	FromParent string
+	ToParent   string
+	Applied    bool
+	// AffectedProducts are the destination products classified in the category or its children
+	AffectedProducts      []string
+	AffectedProductsError string
 }
 
 // Sync synchronizes a single category from source to destination
@@ -34,6 +103,7 @@ func (s *Service) Sync(ctx context.Context, code string) (*SyncResult, error) {
 		Code:    code,
 		Success: false,
 	}
+	ctx, planned := dryrun.Collect(ctx)
 
 	// 1. Get category from source
 	categoryData, err := s.sourceRepo.FindByCode(ctx, code)
@@ -41,14 +111,68 @@ func (s *Service) Sync(ctx context.Context, code string) (*SyncResult, error) {
 		return nil, fmt.Errorf("error fetching category from source: %w", err)
 	}
 
-	// 2. Save category to destination
-	err = s.destRepo.Save(ctx, code, categoryData)
+	// 2. Detect whether the category moved in the tree
+	destCategory, err := s.destRepo.FindByCode(ctx, code)
+	if err == nil && destCategory != nil {
+		fromParent := parentOf(destCategory)
+		toParent := parentOf(categoryData)
+
+		if fromParent != toParent {
+			result.Move = s.detectMove(ctx, code, fromParent, toParent)
+
+			if !result.Move.Applied {
+				// Keep the destination parent so only the other fields are updated
+				categoryData = withParent(categoryData, destCategory["parent"])
+			}
+		}
+	}
+
+	// 3. Save category to destination
+	if !dryrun.Record(ctx, dryrun.Write{Kind: KindCategory, Code: code, Data: categoryData}) {
+		err = s.destRepo.Save(ctx, code, categoryData)
+	}
 	if err != nil {
 		result.Success = false
 		result.Error = err.Error()
 		return result, fmt.Errorf("error saving category to destination: %w", err)
 	}
 
+	result.Planned = planned()
 	result.Success = true
 	return result, nil
 }
+
+// detectMove builds the move report, including the products whose category paths change
+func (s *Service) detectMove(ctx context.Context, code, fromParent, toParent string) *Move {
+	move := &Move{
+		FromParent:       fromParent,
+		ToParent:         toParent,
+		Applied:          s.movePolicy == MovePolicyApply,
+		AffectedProducts: []string{},
+	}
+
+	identifiers, err := s.destRepo.FindProductIdentifiers(ctx, code)
+	if err != nil {
+		move.AffectedProductsError = err.Error()
+		return move
+	}
+
+	move.AffectedProducts = identifiers
+	return move
+}
+
+// parentOf returns the parent code of a category, or an empty string for a root category
+func parentOf(cat category.Category) string {
+	parent, _ := cat["parent"].(string)
+	return parent
+}
+
+// withParent returns a copy of the category with the given parent
+func withParent(cat category.Category, parent interface{}) category.Category {
+	result := make(category.Category, len(cat))
+	for key, value := range cat {
+		result[key] = value
+	}
+	result["parent"] = parent
+	return result
+}
@@ -20,8 +20,25 @@ func (m *mockSourceRepo) FindByCode(ctx context.Context, code string) (category.
 	return nil, nil
 }
 
+func (m *mockSourceRepo) FindChildren(ctx context.Context, parentCode string) ([]category.Category, error) {
+	return nil, nil
+}
+
+func (m *mockSourceRepo) FindRootCodes(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
 type mockDestRepo struct {
-	saveFunc func(ctx context.Context, code string, cat category.Category) error
+	findByCodeFunc             func(ctx context.Context, code string) (category.Category, error)
+	saveFunc                   func(ctx context.Context, code string, cat category.Category) error
+	findProductIdentifiersFunc func(ctx context.Context, code string) ([]string, error)
+}
+
+func (m *mockDestRepo) FindByCode(ctx context.Context, code string) (category.Category, error) {
+	if m.findByCodeFunc != nil {
+		return m.findByCodeFunc(ctx, code)
+	}
+	return nil, nil
 }
 
 func (m *mockDestRepo) Save(ctx context.Context, code string, cat category.Category) error {
@@ -31,6 +48,17 @@ func (m *mockDestRepo) Save(ctx context.Context, code string, cat category.Categ
 	return nil
 }
 
+func (m *mockDestRepo) FindChildren(ctx context.Context, parentCode string) ([]category.Category, error) {
+	return nil, nil
+}
+
+func (m *mockDestRepo) FindProductIdentifiers(ctx context.Context, code string) ([]string, error) {
+	if m.findProductIdentifiersFunc != nil {
+		return m.findProductIdentifiersFunc(ctx, code)
+	}
+	return nil, nil
+}
+
 func TestSync_Success(t *testing.T) {
 	sourceRepo := &mockSourceRepo{
 		findByCodeFunc: func(ctx context.Context, code string) (category.Category, error) {
@@ -112,3 +140,111 @@ func TestSync_DestError(t *testing.T) {
 		t.Error("Expected error message in result")
 	}
 }
+
+func TestSync_MoveApplied(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (category.Category, error) {
+			return category.Category{"code": code, "parent": "clothing"}, nil
+		},
+	}
+
+	var savedParent interface{}
+	destRepo := &mockDestRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (category.Category, error) {
+			return category.Category{"code": code, "parent": "master"}, nil
+		},
+		findProductIdentifiersFunc: func(ctx context.Context, code string) ([]string, error) {
+			return []string{"SKU-1", "SKU-2"}, nil
+		},
+		saveFunc: func(ctx context.Context, code string, cat category.Category) error {
+			savedParent = cat["parent"]
+			return nil
+		},
+	}
+
+	service := NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background(), "shoes")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Move == nil {
+		t.Fatal("Expected move to be detected")
+	}
+
+	if result.Move.FromParent != "master" || result.Move.ToParent != "clothing" {
+		t.Errorf("Expected move master → clothing, got %s → %s", result.Move.FromParent, result.Move.ToParent)
+	}
+
+	if !result.Move.Applied {
+		t.Error("Expected move to be applied")
+	}
+
+	if len(result.Move.AffectedProducts) != 2 {
+		t.Errorf("Expected 2 affected products, got %d", len(result.Move.AffectedProducts))
+	}
+
+	if savedParent != "clothing" {
+		t.Errorf("Expected saved parent 'clothing', got %v", savedParent)
+	}
+}
+
+func TestSync_MoveWarnKeepsDestinationParent(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (category.Category, error) {
+			return category.Category{"code": code, "parent": "clothing", "labels": map[string]string{"en_US": "Shoes"}}, nil
+		},
+	}
+
+	var saved category.Category
+	destRepo := &mockDestRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (category.Category, error) {
+			return category.Category{"code": code, "parent": "master"}, nil
+		},
+		saveFunc: func(ctx context.Context, code string, cat category.Category) error {
+			saved = cat
+			return nil
+		},
+	}
+
+	service := NewService(sourceRepo, destRepo, WithMovePolicy(MovePolicyWarn))
+	result, err := service.Sync(context.Background(), "shoes")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Move == nil || result.Move.Applied {
+		t.Fatal("Expected move to be detected and not applied")
+	}
+
+	if saved["parent"] != "master" {
+		t.Errorf("Expected saved parent 'master', got %v", saved["parent"])
+	}
+
+	if saved["labels"] == nil {
+		t.Error("Expected other fields to still be synchronized")
+	}
+}
+
+func TestSync_NoMoveWhenParentUnchanged(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (category.Category, error) {
+			return category.Category{"code": code, "parent": "master"}, nil
+		},
+	}
+
+	destRepo := &mockDestRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (category.Category, error) {
+			return category.Category{"code": code, "parent": "master"}, nil
+		},
+	}
+
+	service := NewService(sourceRepo, destRepo)
+	result, _ := service.Sync(context.Background(), "shoes")
+
+	if result.Move != nil {
+		t.Errorf("Expected no move, got %+v", result.Move)
+	}
+}
@@ -0,0 +1,16 @@
+package syncing_tree
+
+import "akeneo-migrator/kit/bus"
+
+const SyncCategoryTreeCommandType bus.Type = "category.sync_tree"
+
+// SyncCategoryTreeCommand represents a command to sync a category and all its descendants
+type SyncCategoryTreeCommand struct {
+	Root  string
+	Debug bool
+}
+
+// Type returns the command type
+func (c SyncCategoryTreeCommand) Type() bus.Type {
+	return SyncCategoryTreeCommandType
+}
@@ -0,0 +1,291 @@
+package syncing_tree
+
+import (
+	"context"
+	"fmt"
+
+	"akeneo-migrator/internal/category"
+	"akeneo-migrator/internal/category/syncing"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/retry"
+)
+
+// Service synchronizes a category and all its descendants, parents first
+type Service struct {
+	sourceRepo     category.SourceRepository
+	destRepo       category.DestRepository
+	syncingService *syncing.Service
+}
+
+// NewService creates a new instance of the category tree sync service.
+// Options are passed to the composed category sync service
+func NewService(sourceRepo category.SourceRepository, destRepo category.DestRepository, opts ...syncing.Option) *Service {
+	return &Service{
+		sourceRepo:     sourceRepo,
+		destRepo:       destRepo,
+		syncingService: syncing.NewService(sourceRepo, destRepo, opts...),
+	}
+}
+
+// Node is the result of one category of the tree
+type Node struct {
+	// Depth is 0 for the root, 1 for its children, and so on
+	Depth  int
+	Result *syncing.SyncResult
+	// Skipped is true when the category was not written because its parent failed
+	Skipped bool
+}
+
+// Problem is a category of the tree that cannot be written under its parent
+type Problem struct {
+	Code   string
+	Parent string
+	// Reason is ProblemOrphaned or ProblemCyclic
+	Reason string
+}
+
+// Reasons of a tree problem
+const (
+	// ProblemOrphaned is a category whose parent is not part of the tree
+	ProblemOrphaned = "orphaned"
+	// ProblemCyclic is a category whose ancestors loop without reaching the root
+	ProblemCyclic = "cyclic"
+)
+
+// SyncResult contains the result of syncing a category tree
+type SyncResult struct {
+	Root string
+	// Problems are the orphaned and cyclic categories found in source; nothing is written when there are any
+	Problems []Problem
+	// Nodes are the categories of the tree in the order they were written (depth-first, parents first)
+	Nodes             []Node
+	CategoriesSynced  int
+	CategoriesSkipped int
+	Moves             int
+	Success           bool
+	FailedItems       []retry.Failure
+	// Planned are the writes recorded instead of being sent during a dry run, for the whole sync
+	Planned []dryrun.Write
+}
+
+// Failures returns the categories that were not synchronized, including the skipped ones
+func (r *SyncResult) Failures() []retry.Failure {
+	return r.FailedItems
+}
+
+// Synced returns the number of categories written
+func (r *SyncResult) Synced() int {
+	return r.CategoriesSynced
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// Sync fetches the tree below a root category in source, orders it parent-first from the parent of
+// each category, and writes each category before its children, so every parent exists in destination
+// when its children are written. Orphaned and cyclic categories are reported before anything is
+// written, and the tree is then left untouched. The descendants of a category that fails are
+// reported as skipped.
+func (s *Service) Sync(ctx context.Context, root string) (*SyncResult, error) {
+	result := &SyncResult{Root: root}
+	ctx, planned := dryrun.Collect(ctx)
+
+	tree, err := s.fetchTree(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, problems := tree.order()
+	if len(problems) > 0 {
+		result.Problems = problems
+		return result, nil
+	}
+
+	// failed maps the categories that were not written to the closest ancestor that failed
+	failed := make(map[string]string)
+	for _, node := range nodes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		code := node.code
+		if ancestor, skipped := failed[tree.parents[code]]; skipped && node.depth > 0 {
+			failed[code] = ancestor
+			result.appendNode(Node{
+				Depth:   node.depth,
+				Skipped: true,
+				Result:  &syncing.SyncResult{Code: code, Error: fmt.Sprintf("parent category %s was not synchronized", ancestor)},
+			})
+			result.CategoriesSkipped++
+			continue
+		}
+
+		categoryResult, err := s.syncingService.Sync(ctx, code)
+		if categoryResult == nil {
+			if node.depth == 0 {
+				return nil, err
+			}
+			categoryResult = &syncing.SyncResult{Code: code, Error: err.Error()}
+		}
+		// Planned writes are kept once, in the result of the whole tree
+		categoryResult.Planned = nil
+
+		if categoryResult.Success {
+			result.CategoriesSynced++
+		} else {
+			failed[code] = code
+		}
+		if categoryResult.Move != nil {
+			result.Moves++
+		}
+		result.appendNode(Node{Depth: node.depth, Result: categoryResult})
+	}
+
+	result.Planned = planned()
+	result.Success = len(result.FailedItems) == 0
+	return result, nil
+}
+
+// Exists reports whether a category exists in destination
+func (s *Service) Exists(ctx context.Context, code string) bool {
+	_, err := s.destRepo.FindByCode(ctx, code)
+	return err == nil
+}
+
+// EnsureExists synchronizes the tree below a root category when the root does not exist in
+// destination, and reports whether it was synchronized
+func (s *Service) EnsureExists(ctx context.Context, root string) (bool, error) {
+	if s.Exists(ctx, root) {
+		return false, nil
+	}
+
+	result, err := s.Sync(ctx, root)
+	if err != nil {
+		return false, err
+	}
+	if len(result.Problems) > 0 {
+		return false, fmt.Errorf("%d orphaned or cyclic categories", len(result.Problems))
+	}
+	if !result.Success {
+		return false, fmt.Errorf("%d categories not synchronized", len(result.FailedItems))
+	}
+
+	return true, nil
+}
+
+// appendNode adds a category to the result, with its failures
+func (r *SyncResult) appendNode(node Node) {
+	r.Nodes = append(r.Nodes, node)
+	r.FailedItems = append(r.FailedItems, node.Result.Failures()...)
+}
+
+// fetchTree lists the descendants of a root category in source, level by level.
+// A category listed twice is only fetched once.
+func (s *Service) fetchTree(ctx context.Context, root string) (*tree, error) {
+	t := &tree{root: root, parents: make(map[string]string), children: make(map[string][]string)}
+
+	seen := map[string]bool{root: true}
+	queue := []string{root}
+	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		code := queue[0]
+		queue = queue[1:]
+
+		children, err := s.sourceRepo.FindChildren(ctx, code)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching children of category %s from source: %w", code, err)
+		}
+
+		for _, child := range children {
+			childCode, _ := child["code"].(string)
+			if childCode == "" || seen[childCode] {
+				continue
+			}
+			seen[childCode] = true
+			queue = append(queue, childCode)
+
+			// The parent of the category decides where it goes, whatever category it was listed under
+			parent, _ := child["parent"].(string)
+			t.parents[childCode] = parent
+			t.listed = append(t.listed, childCode)
+		}
+	}
+
+	for _, code := range t.listed {
+		parent := t.parents[code]
+		t.children[parent] = append(t.children[parent], code)
+	}
+
+	return t, nil
+}
+
+// tree is a category tree fetched from source
+type tree struct {
+	root string
+	// parents maps each descendant to its parent code
+	parents map[string]string
+	// children maps each parent to its children, in source order
+	children map[string][]string
+	// listed are the descendants in the order they were found
+	listed []string
+}
+
+// orderedNode is a category of the tree in write order
+type orderedNode struct {
+	code  string
+	depth int
+}
+
+// order returns the categories reachable from the root through their parents, depth-first with
+// parents first, and the categories that are not: those under a parent missing from the tree are
+// orphaned, those whose ancestors loop are cyclic
+func (t *tree) order() ([]orderedNode, []Problem) {
+	var nodes []orderedNode
+	visited := make(map[string]bool)
+
+	var visit func(code string, depth int)
+	visit = func(code string, depth int) {
+		visited[code] = true
+		nodes = append(nodes, orderedNode{code: code, depth: depth})
+		for _, child := range t.children[code] {
+			visit(child, depth+1)
+		}
+	}
+	visit(t.root, 0)
+
+	var problems []Problem
+	for _, code := range t.listed {
+		if visited[code] {
+			continue
+		}
+
+		parent := t.parents[code]
+		if _, inTree := t.parents[parent]; !inTree && parent != t.root {
+			problems = append(problems, Problem{Code: code, Parent: parent, Reason: ProblemOrphaned})
+		} else if t.loops(code) {
+			problems = append(problems, Problem{Code: code, Parent: parent, Reason: ProblemCyclic})
+		}
+	}
+
+	return nodes, problems
+}
+
+// loops reports whether the ancestors of a category lead back to it
+func (t *tree) loops(code string) bool {
+	seen := map[string]bool{}
+	for current := t.parents[code]; current != t.root && !seen[current]; current = t.parents[current] {
+		if current == code {
+			return true
+		}
+		seen[current] = true
+		if _, inTree := t.parents[current]; !inTree {
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,204 @@
+package syncing_tree
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"akeneo-migrator/internal/category"
+	"akeneo-migrator/internal/category/syncing"
+)
+
+// mockSourceRepo serves a category tree indexed by parent code. parents overrides the parent
+// field of a listed category, as inconsistent source data would.
+type mockSourceRepo struct {
+	children map[string][]string
+	parents  map[string]string
+}
+
+func (m *mockSourceRepo) FindByCode(ctx context.Context, code string) (category.Category, error) {
+	for parent, children := range m.children {
+		for _, child := range children {
+			if child == code {
+				return category.Category{"code": code, "parent": parent}, nil
+			}
+		}
+	}
+	return category.Category{"code": code, "parent": nil}, nil
+}
+
+func (m *mockSourceRepo) FindChildren(ctx context.Context, parentCode string) ([]category.Category, error) {
+	var result []category.Category
+	for _, code := range m.children[parentCode] {
+		parent, overridden := m.parents[code]
+		if !overridden {
+			parent = parentCode
+		}
+		result = append(result, category.Category{"code": code, "parent": parent})
+	}
+	return result, nil
+}
+
+func (m *mockSourceRepo) FindRootCodes(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+// mockDestRepo records the written categories and rejects the one named in failing
+type mockDestRepo struct {
+	failing  string
+	saved    []string
+	existing map[string]bool
+}
+
+func (m *mockDestRepo) FindByCode(ctx context.Context, code string) (category.Category, error) {
+	if m.existing[code] {
+		return category.Category{"code": code}, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockDestRepo) Save(ctx context.Context, code string, cat category.Category) error {
+	if code == m.failing {
+		return errors.New("validation error")
+	}
+	m.saved = append(m.saved, code)
+	return nil
+}
+
+func (m *mockDestRepo) FindChildren(ctx context.Context, parentCode string) ([]category.Category, error) {
+	return nil, nil
+}
+
+func (m *mockDestRepo) FindProductIdentifiers(ctx context.Context, code string) ([]string, error) {
+	return nil, nil
+}
+
+func TestSync_WritesParentsBeforeChildren(t *testing.T) {
+	sourceRepo := &mockSourceRepo{children: map[string][]string{
+		"master":      {"clothing", "accessories"},
+		"clothing":    {"shirts", "trousers"},
+		"accessories": {"bags"},
+	}}
+	destRepo := &mockDestRepo{}
+
+	result, err := NewService(sourceRepo, destRepo).Sync(context.Background(), "master")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{"master", "clothing", "shirts", "trousers", "accessories", "bags"}
+	if len(destRepo.saved) != len(expected) {
+		t.Fatalf("Expected %v to be written, got %v", expected, destRepo.saved)
+	}
+	for i, code := range expected {
+		if destRepo.saved[i] != code {
+			t.Errorf("Expected %s at position %d, got %s", code, i, destRepo.saved[i])
+		}
+	}
+
+	if result.Nodes[2].Depth != 2 || !result.Success || result.CategoriesSynced != 6 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+}
+
+func TestSync_SkipsDescendantsOfFailedCategory(t *testing.T) {
+	sourceRepo := &mockSourceRepo{children: map[string][]string{
+		"master":   {"clothing", "accessories"},
+		"clothing": {"shirts"},
+		"shirts":   {"polos"},
+	}}
+	destRepo := &mockDestRepo{failing: "clothing"}
+
+	result, err := NewService(sourceRepo, destRepo).Sync(context.Background(), "master")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.CategoriesSynced != 2 || result.CategoriesSkipped != 2 || result.Success {
+		t.Errorf("Expected 2 synced and 2 skipped categories, got %+v", result)
+	}
+
+	failures := result.Failures()
+	if len(failures) != 3 || failures[0].Code != "clothing" || failures[1].Code != "shirts" || failures[2].Code != "polos" {
+		t.Errorf("Expected the failed category and its descendants in tree order, got %v", failures)
+	}
+	if failures[1].Kind != syncing.KindCategory {
+		t.Errorf("Expected skipped categories to be retried as categories, got %s", failures[1].Kind)
+	}
+}
+
+func TestSync_ReportsOrphanedAndCyclicCategoriesBeforeWriting(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		children: map[string][]string{
+			"master":   {"clothing", "legacy"},
+			"clothing": {"shirts"},
+			"shirts":   {"polos"},
+		},
+		parents: map[string]string{
+			// legacy claims a parent outside the tree; shirts and polos are each other's parent
+			"legacy": "archive",
+			"shirts": "polos",
+		},
+	}
+	destRepo := &mockDestRepo{}
+
+	result, err := NewService(sourceRepo, destRepo).Sync(context.Background(), "master")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(destRepo.saved) != 0 || result.Success {
+		t.Errorf("Expected nothing to be written, got %v", destRepo.saved)
+	}
+
+	expected := []Problem{
+		{Code: "legacy", Parent: "archive", Reason: ProblemOrphaned},
+		{Code: "shirts", Parent: "polos", Reason: ProblemCyclic},
+		{Code: "polos", Parent: "shirts", Reason: ProblemCyclic},
+	}
+	if len(result.Problems) != len(expected) {
+		t.Fatalf("Expected problems %v, got %v", expected, result.Problems)
+	}
+	for i, problem := range expected {
+		if result.Problems[i] != problem {
+			t.Errorf("Expected %v, got %v", problem, result.Problems[i])
+		}
+	}
+}
+
+func TestSync_OrdersCategoriesByTheirParent(t *testing.T) {
+	// shirts is listed under master but belongs to clothing, listed after it
+	sourceRepo := &mockSourceRepo{
+		children: map[string][]string{
+			"master": {"shirts", "clothing"},
+		},
+		parents: map[string]string{"shirts": "clothing"},
+	}
+	destRepo := &mockDestRepo{}
+
+	if _, err := NewService(sourceRepo, destRepo).Sync(context.Background(), "master"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(destRepo.saved) != 3 || destRepo.saved[1] != "clothing" || destRepo.saved[2] != "shirts" {
+		t.Errorf("Expected clothing to be written before shirts, got %v", destRepo.saved)
+	}
+}
+
+func TestEnsureExists_SyncsMissingTrees(t *testing.T) {
+	sourceRepo := &mockSourceRepo{children: map[string][]string{"master": {"clothing"}}}
+	destRepo := &mockDestRepo{existing: map[string]bool{"print": true}}
+	service := NewService(sourceRepo, destRepo)
+
+	if synced, err := service.EnsureExists(context.Background(), "print"); err != nil || synced {
+		t.Errorf("Expected an existing tree to be left as it is, got %v (%v)", synced, err)
+	}
+
+	synced, err := service.EnsureExists(context.Background(), "master")
+	if err != nil || !synced {
+		t.Fatalf("Expected the missing tree to be synchronized, got %v (%v)", synced, err)
+	}
+	if len(destRepo.saved) != 2 || destRepo.saved[0] != "master" {
+		t.Errorf("Expected master and clothing to be written, got %v", destRepo.saved)
+	}
+}
@@ -0,0 +1,18 @@
+package verifying
+
+import "akeneo-migrator/kit/bus"
+
+const VerifyCategoryCommandType bus.Type = "category.verify"
+
+// VerifyCategoryCommand represents a command to verify a category against the destination
+type VerifyCategoryCommand struct {
+	Code string
+	// Tree also verifies all the descendants of the category
+	Tree  bool
+	Debug bool
+}
+
+// Type returns the command type
+func (c VerifyCategoryCommand) Type() bus.Type {
+	return VerifyCategoryCommandType
+}
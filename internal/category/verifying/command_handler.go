@@ -0,0 +1,39 @@
+package verifying
+
+import (
+	"context"
+
+	"akeneo-migrator/kit/bus"
+)
+
+// CommandHandler handles VerifyCategoryCommand
+type CommandHandler struct {
+	service *Service
+}
+
+// NewCommandHandler creates a new command handler
+func NewCommandHandler(service *Service) *CommandHandler {
+	return &CommandHandler{
+		service: service,
+	}
+}
+
+// Handle executes the verify command
+func (h *CommandHandler) Handle(ctx context.Context, msg bus.Message) (bus.Response, error) {
+	cmd, ok := msg.(VerifyCategoryCommand)
+	if !ok {
+		return bus.Response{}, nil
+	}
+
+	verify := h.service.Verify
+	if cmd.Tree {
+		verify = h.service.VerifyTree
+	}
+
+	report, err := verify(ctx, cmd.Code)
+	if err != nil {
+		return bus.Response{Error: err}, err
+	}
+
+	return bus.Response{Data: report}, nil
+}
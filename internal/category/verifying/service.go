@@ -0,0 +1,111 @@
+package verifying
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"akeneo-migrator/internal/category"
+	"akeneo-migrator/kit/checksum"
+)
+
+// Service compares a category between source and destination without writing
+type Service struct {
+	sourceRepo category.SourceRepository
+	destRepo   category.DestRepository
+}
+
+// NewService creates a new category verify service
+func NewService(sourceRepo category.SourceRepository, destRepo category.DestRepository) *Service {
+	return &Service{
+		sourceRepo: sourceRepo,
+		destRepo:   destRepo,
+	}
+}
+
+// Verify compares a category using normalized checksums
+func (s *Service) Verify(ctx context.Context, code string) (*checksum.Report, error) {
+	report := checksum.NewReport("category", code)
+
+	sourceCategory, err := s.sourceRepo.FindByCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching category from source: %w", err)
+	}
+
+	destCategory, err := s.destRepo.FindByCode(ctx, code)
+	if errors.Is(err, category.ErrNotFound) {
+		report.Missing("category", code, err.Error())
+		return report, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching category from destination: %w", err)
+	}
+
+	if err := report.Compare("category", code, sourceCategory, destCategory); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// VerifyTree compares a category and all its descendants using normalized checksums. Destination
+// children of the verified categories that are not in the source subtree are reported as extra.
+func (s *Service) VerifyTree(ctx context.Context, code string) (*checksum.Report, error) {
+	report := checksum.NewReport("category tree", code)
+
+	root, err := s.sourceRepo.FindByCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching category from source: %w", err)
+	}
+
+	// Categories are compared parents first, breadth-first
+	inSource := map[string]bool{code: true}
+	var destChildCodes []string
+	queue := []category.Category{root}
+	for len(queue) > 0 {
+		sourceCategory := queue[0]
+		queue = queue[1:]
+		current, _ := sourceCategory["code"].(string)
+
+		sourceChildren, err := s.sourceRepo.FindChildren(ctx, current)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching children of category %s from source: %w", current, err)
+		}
+		for _, child := range sourceChildren {
+			childCode, _ := child["code"].(string)
+			inSource[childCode] = true
+		}
+		queue = append(queue, sourceChildren...)
+
+		destCategory, err := s.destRepo.FindByCode(ctx, current)
+		if errors.Is(err, category.ErrNotFound) {
+			report.Missing("category", current, err.Error())
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error fetching category %s from destination: %w", current, err)
+		}
+		if err := report.Compare("category", current, sourceCategory, destCategory); err != nil {
+			return nil, err
+		}
+
+		destChildren, err := s.destRepo.FindChildren(ctx, current)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching children of category %s from destination: %w", current, err)
+		}
+		for _, child := range destChildren {
+			childCode, _ := child["code"].(string)
+			destChildCodes = append(destChildCodes, childCode)
+		}
+	}
+
+	// Categories moved within the subtree are mismatches, not extra items
+	for _, childCode := range destChildCodes {
+		if !inSource[childCode] {
+			inSource[childCode] = true
+			report.Extra("category", childCode)
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,194 @@
+package verifying
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"akeneo-migrator/internal/category"
+	"akeneo-migrator/kit/checksum"
+)
+
+type mockSourceRepo struct {
+	findByCodeFunc func(ctx context.Context, code string) (category.Category, error)
+	children       map[string][]category.Category
+}
+
+func (m *mockSourceRepo) FindByCode(ctx context.Context, code string) (category.Category, error) {
+	if m.findByCodeFunc != nil {
+		return m.findByCodeFunc(ctx, code)
+	}
+	return nil, nil
+}
+
+func (m *mockSourceRepo) FindChildren(ctx context.Context, parentCode string) ([]category.Category, error) {
+	return m.children[parentCode], nil
+}
+
+func (m *mockSourceRepo) FindRootCodes(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+type mockDestRepo struct {
+	findByCodeFunc func(ctx context.Context, code string) (category.Category, error)
+	children       map[string][]category.Category
+	saveCalls      int
+}
+
+func (m *mockDestRepo) FindByCode(ctx context.Context, code string) (category.Category, error) {
+	if m.findByCodeFunc != nil {
+		return m.findByCodeFunc(ctx, code)
+	}
+	return nil, nil
+}
+
+func (m *mockDestRepo) Save(ctx context.Context, code string, cat category.Category) error {
+	m.saveCalls++
+	return nil
+}
+
+func (m *mockDestRepo) FindChildren(ctx context.Context, parentCode string) ([]category.Category, error) {
+	return m.children[parentCode], nil
+}
+
+func (m *mockDestRepo) FindProductIdentifiers(ctx context.Context, code string) ([]string, error) {
+	return nil, nil
+}
+
+func TestVerify_Match(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (category.Category, error) {
+			return category.Category{"code": code, "parent": "master", "labels": map[string]interface{}{"en_US": "Shoes"}}, nil
+		},
+	}
+
+	destRepo := &mockDestRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (category.Category, error) {
+			return category.Category{"code": code, "parent": "master", "labels": map[string]interface{}{"en_US": "Shoes"}, "updated": "2024-01-01"}, nil
+		},
+	}
+
+	service := NewService(sourceRepo, destRepo)
+	report, err := service.Verify(context.Background(), "shoes")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !report.OK() {
+		t.Errorf("Expected report to be OK, got %+v", report.Differences)
+	}
+
+	if destRepo.saveCalls != 0 {
+		t.Errorf("Expected no writes to destination, got %d", destRepo.saveCalls)
+	}
+}
+
+func TestVerify_Mismatch(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (category.Category, error) {
+			return category.Category{"code": code, "parent": "master"}, nil
+		},
+	}
+
+	destRepo := &mockDestRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (category.Category, error) {
+			return category.Category{"code": code, "parent": "clothing"}, nil
+		},
+	}
+
+	service := NewService(sourceRepo, destRepo)
+	report, _ := service.Verify(context.Background(), "shoes")
+
+	if report.Count(checksum.StatusMismatch) != 1 {
+		t.Errorf("Expected 1 mismatch, got %d", report.Count(checksum.StatusMismatch))
+	}
+}
+
+func TestVerify_SourceError(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (category.Category, error) {
+			return nil, errors.New("source error")
+		},
+	}
+
+	service := NewService(sourceRepo, &mockDestRepo{})
+	_, err := service.Verify(context.Background(), "shoes")
+
+	if err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestVerify_DestinationError(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (category.Category, error) {
+			return category.Category{"code": code}, nil
+		},
+	}
+	destRepo := &mockDestRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (category.Category, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	service := NewService(sourceRepo, destRepo)
+	_, err := service.Verify(context.Background(), "shoes")
+
+	if err == nil {
+		t.Fatal("Expected an error when the destination cannot be read, got nil")
+	}
+}
+
+func TestVerifyTree_ReportsSubtreeDifferences(t *testing.T) {
+	sourceCategories := map[string]category.Category{
+		"master":  {"code": "master"},
+		"shoes":   {"code": "shoes", "parent": "master"},
+		"boots":   {"code": "boots", "parent": "shoes"},
+		"sandals": {"code": "sandals", "parent": "master"},
+	}
+	sourceRepo := &mockSourceRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (category.Category, error) {
+			return sourceCategories[code], nil
+		},
+		children: map[string][]category.Category{
+			"master": {sourceCategories["shoes"], sourceCategories["sandals"]},
+			"shoes":  {sourceCategories["boots"]},
+		},
+	}
+
+	destCategories := map[string]category.Category{
+		"master": {"code": "master"},
+		"shoes":  {"code": "shoes", "parent": "master"},
+		"boots":  {"code": "boots", "parent": "master"},
+		"hats":   {"code": "hats", "parent": "master"},
+	}
+	destRepo := &mockDestRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (category.Category, error) {
+			if cat, ok := destCategories[code]; ok {
+				return cat, nil
+			}
+			return nil, category.ErrNotFound
+		},
+		children: map[string][]category.Category{
+			"master": {destCategories["shoes"], destCategories["boots"], destCategories["hats"]},
+		},
+	}
+
+	service := NewService(sourceRepo, destRepo)
+	report, err := service.VerifyTree(context.Background(), "master")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if report.Compared != 4 || report.Matches != 2 {
+		t.Errorf("Expected 4 categories compared and 2 matching, got %d and %d", report.Compared, report.Matches)
+	}
+	if report.Count(checksum.StatusMismatch) != 1 || report.Count(checksum.StatusMissing) != 1 {
+		t.Errorf("Expected boots to differ and sandals to be missing, got %+v", report.Differences)
+	}
+	// boots is listed under master in destination, but it exists in the source subtree
+	if report.Count(checksum.StatusExtra) != 1 || report.DestCount() != 4 {
+		t.Errorf("Expected only hats to be extra, got %+v", report.Differences)
+	}
+}
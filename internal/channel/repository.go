@@ -0,0 +1,27 @@
+package channel
+
+import "context"
+
+// Channel represents a channel
+type Channel map[string]interface{}
+
+// SourceRepository defines read-only operations for channels from source
+type SourceRepository interface {
+	// FindByCode retrieves a channel by its code
+	FindByCode(ctx context.Context, code string) (Channel, error)
+
+	// FindCodes retrieves the codes of every channel
+	FindCodes(ctx context.Context) ([]string, error)
+}
+
+// DestRepository defines read and write operations for channels in destination
+type DestRepository interface {
+	// Save creates or updates a channel
+	Save(ctx context.Context, code string, channel Channel) error
+
+	// FindLocales retrieves all locale codes with their activation status
+	FindLocales(ctx context.Context) (map[string]bool, error)
+
+	// FindCurrencies retrieves all currency codes with their activation status
+	FindCurrencies(ctx context.Context) (map[string]bool, error)
+}
@@ -0,0 +1,24 @@
+package syncing
+
+import (
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/retry"
+)
+
+const SyncChannelCommandType bus.Type = "channel.sync"
+
+// SyncChannelCommand represents a command to sync a channel
+type SyncChannelCommand struct {
+	Code     string
+	AutoDeps bool
+}
+
+// Type returns the command type
+func (c SyncChannelCommand) Type() bus.Type {
+	return SyncChannelCommandType
+}
+
+// RetryItem returns the item targeted by the command
+func (c SyncChannelCommand) RetryItem() retry.Failure {
+	return retry.Failure{Kind: KindChannel, Code: c.Code}
+}
@@ -0,0 +1,283 @@
+package syncing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"akeneo-migrator/internal/channel"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/retry"
+)
+
+// KindChannel is the kind of item reported as failure
+const KindChannel = "channel"
+
+// Service handles channel synchronization
+type Service struct {
+	sourceRepo  channel.SourceRepository
+	destRepo    channel.DestRepository
+	categoryMap map[string]string
+	channelMap  map[string]string
+	trees       CategoryTreeEnsurer
+}
+
+// CategoryTreeEnsurer checks the category tree of a channel in destination and creates it from source
+type CategoryTreeEnsurer interface {
+	// Exists reports whether a category exists in destination
+	Exists(ctx context.Context, code string) bool
+	// EnsureExists synchronizes a category tree from source when its root does not exist in
+	// destination, and reports whether it was synchronized
+	EnsureExists(ctx context.Context, root string) (bool, error)
+}
+
+// Option configures the channel sync service
+type Option func(*Service)
+
+// WithCategoryMap sets the source → destination category code mapping used for the category tree
+func WithCategoryMap(categoryMap map[string]string) Option {
+	return func(s *Service) {
+		s.categoryMap = categoryMap
+	}
+}
+
+// WithChannelMap sets the source → destination channel code mapping; a mapped channel is written
+// to destination under its destination code
+func WithChannelMap(channelMap map[string]string) Option {
+	return func(s *Service) {
+		s.channelMap = channelMap
+	}
+}
+
+// WithCategoryTrees checks that the category tree of a channel exists in destination before it is
+// written; with auto-deps, a missing tree is synchronized from source first
+func WithCategoryTrees(trees CategoryTreeEnsurer) Option {
+	return func(s *Service) {
+		s.trees = trees
+	}
+}
+
+// NewService creates a new channel sync service
+func NewService(sourceRepo channel.SourceRepository, destRepo channel.DestRepository, opts ...Option) *Service {
+	service := &Service{
+		sourceRepo:  sourceRepo,
+		destRepo:    destRepo,
+		categoryMap: map[string]string{},
+		channelMap:  map[string]string{},
+	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
+
+// SyncOptions contains per-run options of a channel sync
+type SyncOptions struct {
+	// AutoDeps allows locales that are not yet activated, saving the channel activates them,
+	// and synchronizes a missing category tree from source
+	AutoDeps bool
+}
+
+// SyncResult contains the result of a sync operation
+type SyncResult struct {
+	Code                string
+	Success             bool
+	Error               string
+	CategoryTree        string
+	CategoryTreeMapped  bool
+	CategoryTreeSynced  bool
+	ActivatedLocales    []string
+	MissingDependencies []string
+	// DestCode is the code of the channel in destination when it is mapped to another code
+	DestCode string
+	// Planned are the writes recorded instead of being sent during a dry run
+	Planned []dryrun.Write
+}
+
+// Failures returns the channel when it could not be synchronized
+func (r *SyncResult) Failures() []retry.Failure {
+	if r.Success {
+		return nil
+	}
+	message := r.Error
+	if message == "" && len(r.MissingDependencies) > 0 {
+		message = "missing dependencies: " + strings.Join(r.MissingDependencies, ", ")
+	}
+	return []retry.Failure{{Kind: KindChannel, Code: r.Code, Error: message}}
+}
+
+// Synced returns the number of channels written
+func (r *SyncResult) Synced() int {
+	if r.Success {
+		return 1
+	}
+	return 0
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// Sync synchronizes a single channel from source to destination
+func (s *Service) Sync(ctx context.Context, code string, opts SyncOptions) (*SyncResult, error) {
+	result := &SyncResult{
+		Code:                code,
+		Success:             false,
+		ActivatedLocales:    []string{},
+		MissingDependencies: []string{},
+	}
+	ctx, planned := dryrun.Collect(ctx)
+
+	// 1. Get channel from source
+	sourceChannel, err := s.sourceRepo.FindByCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching channel from source: %w", err)
+	}
+
+	channelData := make(channel.Channel, len(sourceChannel))
+	for key, value := range sourceChannel {
+		channelData[key] = value
+	}
+
+	// 2. Remap the channel code and the category tree reference
+	destCode := code
+	if mapped, exists := s.channelMap[code]; exists {
+		destCode = mapped
+		channelData["code"] = mapped
+		result.DestCode = mapped
+	}
+
+	if tree, ok := channelData["category_tree"].(string); ok {
+		if mapped, exists := s.categoryMap[tree]; exists {
+			channelData["category_tree"] = mapped
+			tree = mapped
+			result.CategoryTreeMapped = true
+		}
+		result.CategoryTree = tree
+	}
+
+	// 3. Validate the category tree, locales and currencies against the destination
+	if err := s.checkCategoryTree(ctx, opts, result); err != nil {
+		return nil, err
+	}
+	if err := s.checkLocales(ctx, channelData, opts, result); err != nil {
+		return nil, err
+	}
+	if err := s.checkCurrencies(ctx, channelData, result); err != nil {
+		return nil, err
+	}
+
+	if len(result.MissingDependencies) > 0 {
+		// Nothing is written; the result lists what has to be fixed first
+		result.Error = fmt.Sprintf("missing dependencies in destination: %s", strings.Join(result.MissingDependencies, ", "))
+		return result, nil
+	}
+
+	// 4. Save channel to destination
+	if !dryrun.Record(ctx, dryrun.Write{Kind: KindChannel, Code: destCode, Data: channelData}) {
+		err = s.destRepo.Save(ctx, destCode, channelData)
+	}
+	if err != nil {
+		result.Success = false
+		result.Error = err.Error()
+		return result, fmt.Errorf("error saving channel to destination: %w", err)
+	}
+
+	result.Planned = planned()
+	result.Success = true
+	return result, nil
+}
+
+// checkCategoryTree verifies that the category tree of the channel exists in destination. With
+// auto-deps a missing tree is synchronized from source, unless it is mapped to another code: the
+// source tree would then be written under its source code.
+func (s *Service) checkCategoryTree(ctx context.Context, opts SyncOptions, result *SyncResult) error {
+	if s.trees == nil || result.CategoryTree == "" {
+		return nil
+	}
+
+	if !opts.AutoDeps || result.CategoryTreeMapped {
+		if !s.trees.Exists(ctx, result.CategoryTree) {
+			result.MissingDependencies = append(result.MissingDependencies, fmt.Sprintf("category tree %s (unknown)", result.CategoryTree))
+		}
+		return nil
+	}
+
+	synced, err := s.trees.EnsureExists(ctx, result.CategoryTree)
+	if err != nil {
+		result.MissingDependencies = append(result.MissingDependencies, fmt.Sprintf("category tree %s (%v)", result.CategoryTree, err))
+		return nil
+	}
+	result.CategoryTreeSynced = synced
+
+	return nil
+}
+
+// checkLocales verifies that every channel locale exists and is activated in destination.
+// Akeneo activates a locale when a channel uses it, so inactive locales are accepted with auto-deps.
+func (s *Service) checkLocales(ctx context.Context, channelData channel.Channel, opts SyncOptions, result *SyncResult) error {
+	locales := stringList(channelData["locales"])
+	if len(locales) == 0 {
+		return nil
+	}
+
+	destLocales, err := s.destRepo.FindLocales(ctx)
+	if err != nil {
+		return fmt.Errorf("error fetching locales from destination: %w", err)
+	}
+
+	for _, locale := range locales {
+		enabled, exists := destLocales[locale]
+		switch {
+		case !exists:
+			result.MissingDependencies = append(result.MissingDependencies, fmt.Sprintf("locale %s (unknown)", locale))
+		case !enabled && opts.AutoDeps:
+			result.ActivatedLocales = append(result.ActivatedLocales, locale)
+		case !enabled:
+			result.MissingDependencies = append(result.MissingDependencies, fmt.Sprintf("locale %s (not activated)", locale))
+		}
+	}
+
+	return nil
+}
+
+// checkCurrencies verifies that every channel currency is activated in destination.
+// The Akeneo API cannot activate currencies, so they must be enabled beforehand.
+func (s *Service) checkCurrencies(ctx context.Context, channelData channel.Channel, result *SyncResult) error {
+	currencies := stringList(channelData["currencies"])
+	if len(currencies) == 0 {
+		return nil
+	}
+
+	destCurrencies, err := s.destRepo.FindCurrencies(ctx)
+	if err != nil {
+		return fmt.Errorf("error fetching currencies from destination: %w", err)
+	}
+
+	for _, currency := range currencies {
+		enabled, exists := destCurrencies[currency]
+		switch {
+		case !exists:
+			result.MissingDependencies = append(result.MissingDependencies, fmt.Sprintf("currency %s (unknown)", currency))
+		case !enabled:
+			result.MissingDependencies = append(result.MissingDependencies, fmt.Sprintf("currency %s (not activated)", currency))
+		}
+	}
+
+	return nil
+}
+
+// stringList converts a decoded JSON list to a list of strings
+func stringList(value interface{}) []string {
+	items, _ := value.([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if text, ok := item.(string); ok {
+			result = append(result, text)
+		}
+	}
+	return result
+}
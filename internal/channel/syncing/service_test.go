@@ -0,0 +1,215 @@
+package syncing
+
+import (
+	"context"
+	"testing"
+
+	"akeneo-migrator/internal/channel"
+)
+
+// Mock repositories
+type mockSourceRepo struct {
+	channel channel.Channel
+}
+
+func (m *mockSourceRepo) FindByCode(ctx context.Context, code string) (channel.Channel, error) {
+	return m.channel, nil
+}
+
+func (m *mockSourceRepo) FindCodes(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+type mockDestRepo struct {
+	locales    map[string]bool
+	currencies map[string]bool
+	saved      channel.Channel
+	savedCode  string
+}
+
+func (m *mockDestRepo) Save(ctx context.Context, code string, ch channel.Channel) error {
+	m.saved = ch
+	m.savedCode = code
+	return nil
+}
+
+func (m *mockDestRepo) FindLocales(ctx context.Context) (map[string]bool, error) {
+	return m.locales, nil
+}
+
+func (m *mockDestRepo) FindCurrencies(ctx context.Context) (map[string]bool, error) {
+	return m.currencies, nil
+}
+
+// mockTrees serves the categories of destination and records the trees it synchronizes
+type mockTrees struct {
+	existing map[string]bool
+	synced   []string
+}
+
+func (m *mockTrees) Exists(ctx context.Context, code string) bool {
+	return m.existing[code]
+}
+
+func (m *mockTrees) EnsureExists(ctx context.Context, root string) (bool, error) {
+	if m.existing[root] {
+		return false, nil
+	}
+	m.synced = append(m.synced, root)
+	return true, nil
+}
+
+func newEcommerceChannel() channel.Channel {
+	return channel.Channel{
+		"code":          "ecommerce",
+		"category_tree": "master",
+		"locales":       []interface{}{"en_US", "fr_FR"},
+		"currencies":    []interface{}{"EUR"},
+	}
+}
+
+func TestSync_RemapsCategoryTree(t *testing.T) {
+	sourceRepo := &mockSourceRepo{channel: newEcommerceChannel()}
+	destRepo := &mockDestRepo{
+		locales:    map[string]bool{"en_US": true, "fr_FR": true},
+		currencies: map[string]bool{"EUR": true},
+	}
+
+	service := NewService(sourceRepo, destRepo, WithCategoryMap(map[string]string{"master": "web_catalog"}))
+	result, err := service.Sync(context.Background(), "ecommerce", SyncOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !result.CategoryTreeMapped || result.CategoryTree != "web_catalog" {
+		t.Errorf("Expected category tree mapped to 'web_catalog', got %s", result.CategoryTree)
+	}
+
+	if destRepo.saved["category_tree"] != "web_catalog" {
+		t.Errorf("Expected saved category tree 'web_catalog', got %v", destRepo.saved["category_tree"])
+	}
+
+	if sourceRepo.channel["category_tree"] != "master" {
+		t.Error("Expected source channel not to be modified")
+	}
+}
+
+func TestSync_WritesMappedChannelCode(t *testing.T) {
+	sourceRepo := &mockSourceRepo{channel: newEcommerceChannel()}
+	destRepo := &mockDestRepo{
+		locales:    map[string]bool{"en_US": true, "fr_FR": true},
+		currencies: map[string]bool{"EUR": true},
+	}
+
+	service := NewService(sourceRepo, destRepo, WithChannelMap(map[string]string{"ecommerce": "web"}))
+	result, err := service.Sync(context.Background(), "ecommerce", SyncOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.DestCode != "web" || destRepo.savedCode != "web" || destRepo.saved["code"] != "web" {
+		t.Errorf("Expected the channel to be written as web, got %s (%v)", destRepo.savedCode, destRepo.saved["code"])
+	}
+
+	if sourceRepo.channel["code"] != "ecommerce" {
+		t.Error("Expected source channel not to be modified")
+	}
+}
+
+func TestSync_InactiveLocaleWithoutAutoDeps(t *testing.T) {
+	sourceRepo := &mockSourceRepo{channel: newEcommerceChannel()}
+	destRepo := &mockDestRepo{
+		locales:    map[string]bool{"en_US": true, "fr_FR": false},
+		currencies: map[string]bool{"EUR": true},
+	}
+
+	service := NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background(), "ecommerce", SyncOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Success {
+		t.Error("Expected success to be false")
+	}
+
+	if len(result.MissingDependencies) != 1 {
+		t.Errorf("Expected 1 missing dependency, got %v", result.MissingDependencies)
+	}
+
+	if destRepo.saved != nil {
+		t.Error("Expected channel not to be saved")
+	}
+}
+
+func TestSync_InactiveLocaleWithAutoDeps(t *testing.T) {
+	sourceRepo := &mockSourceRepo{channel: newEcommerceChannel()}
+	destRepo := &mockDestRepo{
+		locales:    map[string]bool{"en_US": true, "fr_FR": false},
+		currencies: map[string]bool{"EUR": true},
+	}
+
+	service := NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background(), "ecommerce", SyncOptions{AutoDeps: true})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.ActivatedLocales) != 1 || result.ActivatedLocales[0] != "fr_FR" {
+		t.Errorf("Expected fr_FR to be activated, got %v", result.ActivatedLocales)
+	}
+}
+
+func TestSync_InactiveCurrencyFails(t *testing.T) {
+	sourceRepo := &mockSourceRepo{channel: newEcommerceChannel()}
+	destRepo := &mockDestRepo{
+		locales:    map[string]bool{"en_US": true, "fr_FR": true},
+		currencies: map[string]bool{"EUR": false},
+	}
+
+	service := NewService(sourceRepo, destRepo)
+	result, _ := service.Sync(context.Background(), "ecommerce", SyncOptions{AutoDeps: true})
+
+	if result.Success {
+		t.Error("Expected success to be false")
+	}
+
+	if len(result.MissingDependencies) != 1 {
+		t.Errorf("Expected 1 missing dependency, got %v", result.MissingDependencies)
+	}
+}
+
+func TestSync_ChecksCategoryTree(t *testing.T) {
+	sourceRepo := &mockSourceRepo{channel: newEcommerceChannel()}
+	destRepo := &mockDestRepo{
+		locales:    map[string]bool{"en_US": true, "fr_FR": true},
+		currencies: map[string]bool{"EUR": true},
+	}
+	trees := &mockTrees{}
+	service := NewService(sourceRepo, destRepo, WithCategoryTrees(trees))
+
+	// Without auto-deps, the missing tree stops the channel
+	result, err := service.Sync(context.Background(), "ecommerce", SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Success || len(result.MissingDependencies) != 1 || result.MissingDependencies[0] != "category tree master (unknown)" {
+		t.Errorf("Expected the missing category tree to be reported, got %v", result.MissingDependencies)
+	}
+	if destRepo.saved != nil || len(trees.synced) != 0 {
+		t.Error("Expected nothing to be written")
+	}
+
+	// With auto-deps, the tree is synchronized before the channel
+	result, err = service.Sync(context.Background(), "ecommerce", SyncOptions{AutoDeps: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !result.Success || !result.CategoryTreeSynced || len(trees.synced) != 1 || trees.synced[0] != "master" {
+		t.Errorf("Expected the master tree to be synchronized, got %+v", result)
+	}
+}
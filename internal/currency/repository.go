@@ -0,0 +1,19 @@
+package currency
+
+import "context"
+
+// Currency represents a currency
+type Currency map[string]interface{}
+
+// SourceRepository defines read-only operations for currencies from source
+type SourceRepository interface {
+	// FindAll retrieves all currency codes with their activation status
+	FindAll(ctx context.Context) (map[string]bool, error)
+}
+
+// DestRepository defines read operations for currencies in destination.
+// The Akeneo API exposes currencies read-only, so there is nothing to write.
+type DestRepository interface {
+	// FindAll retrieves all currency codes with their activation status
+	FindAll(ctx context.Context) (map[string]bool, error)
+}
@@ -0,0 +1,17 @@
+package syncing
+
+import (
+	"akeneo-migrator/kit/bus"
+)
+
+const SyncCurrenciesCommandType bus.Type = "currency.sync"
+
+// SyncCurrenciesCommand represents a command to reconcile the enabled currencies
+type SyncCurrenciesCommand struct {
+	Debug bool
+}
+
+// Type returns the command type
+func (c SyncCurrenciesCommand) Type() bus.Type {
+	return SyncCurrenciesCommandType
+}
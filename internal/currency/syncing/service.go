@@ -0,0 +1,108 @@
+package syncing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"akeneo-migrator/internal/currency"
+	"akeneo-migrator/kit/retry"
+)
+
+// KindCurrency is the kind of item reported as failure
+const KindCurrency = "currency"
+
+// Service reconciles the currencies enabled in source with the destination
+type Service struct {
+	sourceRepo currency.SourceRepository
+	destRepo   currency.DestRepository
+}
+
+// NewService creates a new currency sync service
+func NewService(sourceRepo currency.SourceRepository, destRepo currency.DestRepository) *Service {
+	return &Service{
+		sourceRepo: sourceRepo,
+		destRepo:   destRepo,
+	}
+}
+
+// SyncResult contains the result of a currency reconciliation
+type SyncResult struct {
+	Success bool
+	// Enabled are the source currencies already enabled in destination
+	Enabled []string
+	// Disabled are the source currencies that exist in destination but are not enabled
+	Disabled []string
+	// Unknown are the source currencies that do not exist in destination
+	Unknown []string
+	// DestOnly are the currencies enabled in destination but not in source
+	DestOnly []string
+}
+
+// Failures returns the source currencies that are not usable in destination
+func (r *SyncResult) Failures() []retry.Failure {
+	failures := make([]retry.Failure, 0, len(r.Disabled)+len(r.Unknown))
+	for _, code := range r.Disabled {
+		failures = append(failures, retry.Failure{Kind: KindCurrency, Code: code, Error: "not enabled in destination"})
+	}
+	for _, code := range r.Unknown {
+		failures = append(failures, retry.Failure{Kind: KindCurrency, Code: code, Error: "unknown in destination"})
+	}
+	return failures
+}
+
+// Synced returns the number of currencies written; currencies are read-only in the API
+func (r *SyncResult) Synced() int {
+	return 0
+}
+
+// Sync compares the currencies enabled in source with the destination. Price values in a currency
+// that is not enabled in destination are rejected, so every such currency is reported.
+func (s *Service) Sync(ctx context.Context) (*SyncResult, error) {
+	result := &SyncResult{
+		Enabled:  []string{},
+		Disabled: []string{},
+		Unknown:  []string{},
+		DestOnly: []string{},
+	}
+
+	sourceCurrencies, err := s.sourceRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching currencies from source: %w", err)
+	}
+
+	destCurrencies, err := s.destRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching currencies from destination: %w", err)
+	}
+
+	for code, enabled := range sourceCurrencies {
+		if !enabled {
+			continue
+		}
+
+		destEnabled, exists := destCurrencies[code]
+		switch {
+		case !exists:
+			result.Unknown = append(result.Unknown, code)
+		case !destEnabled:
+			result.Disabled = append(result.Disabled, code)
+		default:
+			result.Enabled = append(result.Enabled, code)
+		}
+	}
+
+	for code, enabled := range destCurrencies {
+		if enabled && !sourceCurrencies[code] {
+			result.DestOnly = append(result.DestOnly, code)
+		}
+	}
+
+	sort.Strings(result.Enabled)
+	sort.Strings(result.Disabled)
+	sort.Strings(result.Unknown)
+	sort.Strings(result.DestOnly)
+
+	result.Success = len(result.Disabled) == 0 && len(result.Unknown) == 0
+	return result, nil
+}
@@ -0,0 +1,74 @@
+package syncing
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// Mock repositories
+type mockRepo struct {
+	currencies map[string]bool
+	err        error
+}
+
+func (m *mockRepo) FindAll(ctx context.Context) (map[string]bool, error) {
+	return m.currencies, m.err
+}
+
+func TestSync_ReportsCurrenciesToEnable(t *testing.T) {
+	sourceRepo := &mockRepo{currencies: map[string]bool{"EUR": true, "USD": true, "GBP": true, "JPY": true, "CHF": false}}
+	destRepo := &mockRepo{currencies: map[string]bool{"EUR": true, "USD": false, "CHF": false, "SEK": true}}
+
+	service := NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Success {
+		t.Error("Expected success to be false")
+	}
+
+	if len(result.Enabled) != 1 || result.Enabled[0] != "EUR" {
+		t.Errorf("Expected EUR enabled, got %v", result.Enabled)
+	}
+	if len(result.Disabled) != 1 || result.Disabled[0] != "USD" {
+		t.Errorf("Expected USD disabled, got %v", result.Disabled)
+	}
+	if len(result.Unknown) != 2 || result.Unknown[0] != "GBP" || result.Unknown[1] != "JPY" {
+		t.Errorf("Expected GBP and JPY unknown, got %v", result.Unknown)
+	}
+	if len(result.DestOnly) != 1 || result.DestOnly[0] != "SEK" {
+		t.Errorf("Expected SEK only in destination, got %v", result.DestOnly)
+	}
+
+	if len(result.Failures()) != 3 {
+		t.Errorf("Expected 3 failures, got %v", result.Failures())
+	}
+}
+
+func TestSync_AllCurrenciesEnabled(t *testing.T) {
+	sourceRepo := &mockRepo{currencies: map[string]bool{"EUR": true, "USD": false}}
+	destRepo := &mockRepo{currencies: map[string]bool{"EUR": true, "USD": false}}
+
+	service := NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !result.Success || len(result.Failures()) != 0 {
+		t.Errorf("Expected success without failures, got %+v", result)
+	}
+}
+
+func TestSync_DestError(t *testing.T) {
+	service := NewService(&mockRepo{currencies: map[string]bool{"EUR": true}}, &mockRepo{err: errors.New("dest error")})
+
+	if _, err := service.Sync(context.Background()); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
@@ -1,6 +1,12 @@
 package family
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned when a family does not exist in the instance
+var ErrNotFound = errors.New("family not found")
 
 // Family represents a family
 type Family map[string]interface{}
@@ -15,10 +21,20 @@ type SourceRepository interface {
 
 	// GetVariants retrieves all variants for a family
 	GetVariants(ctx context.Context, familyCode string) ([]FamilyVariant, error)
+
+	// FindPage retrieves one page of families, pages being numbered from 1.
+	// hasNext tells whether more pages follow
+	FindPage(ctx context.Context, page, limit int) (families []Family, hasNext bool, err error)
 }
 
-// DestRepository defines write operations for families to destination
+// DestRepository defines read and write operations for families in destination
 type DestRepository interface {
+	// FindByCode retrieves a family by its code
+	FindByCode(ctx context.Context, code string) (Family, error)
+
+	// GetVariants retrieves all variants for a family
+	GetVariants(ctx context.Context, familyCode string) ([]FamilyVariant, error)
+
 	// Save creates or updates a family
 	Save(ctx context.Context, code string, family Family) error
 
@@ -1,16 +1,27 @@
 package syncing
 
-import "akeneo-migrator/kit/bus"
+import (
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/retry"
+)
 
 const SyncFamilyCommandType bus.Type = "family.sync"
 
 // SyncFamilyCommand represents a command to sync a family
 type SyncFamilyCommand struct {
-	Code  string
-	Debug bool
+	Code         string
+	SkipVariants bool
+	VariantsOnly bool
+	Variants     []string
+	Debug        bool
 }
 
 // Type returns the command type
 func (c SyncFamilyCommand) Type() bus.Type {
 	return SyncFamilyCommandType
 }
+
+// RetryItem returns the item targeted by the command
+func (c SyncFamilyCommand) RetryItem() retry.Failure {
+	return retry.Failure{Kind: KindFamily, Code: c.Code}
+}
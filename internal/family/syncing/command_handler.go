@@ -25,7 +25,11 @@ func (h *CommandHandler) Handle(ctx context.Context, msg bus.Message) (bus.Respo
 		return bus.Response{}, nil
 	}
 
-	result, err := h.service.Sync(ctx, cmd.Code)
+	result, err := h.service.Sync(ctx, cmd.Code, SyncOptions{
+		SkipVariants: cmd.SkipVariants,
+		VariantsOnly: cmd.VariantsOnly,
+		Variants:     cmd.Variants,
+	})
 	if err != nil {
 		return bus.Response{Error: err}, err
 	}
@@ -2,23 +2,66 @@ package syncing
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"akeneo-migrator/internal/family"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/retry"
+)
+
+// Kinds of items reported as failures
+const (
+	KindFamily = "family"
+	// KindFamilyVariants is a family whose variants have to be synchronized again
+	KindFamilyVariants = "family_variants"
+	// KindFamilyVariant is a variant of a family, its scope; only reported in planned writes
+	KindFamilyVariant = "family_variant"
 )
 
 // Service handles family synchronization
 type Service struct {
 	sourceRepo family.SourceRepository
 	destRepo   family.DestRepository
+	familyMap  map[string]string
+}
+
+// Option configures the family sync service
+type Option func(*Service)
+
+// WithFamilyMap sets the source → destination family code mapping; a mapped family and its variants
+// are written to destination under its destination code
+func WithFamilyMap(familyMap map[string]string) Option {
+	return func(s *Service) {
+		s.familyMap = familyMap
+	}
 }
 
 // NewService creates a new family sync service
-func NewService(sourceRepo family.SourceRepository, destRepo family.DestRepository) *Service {
-	return &Service{
+func NewService(sourceRepo family.SourceRepository, destRepo family.DestRepository, opts ...Option) *Service {
+	service := &Service{
 		sourceRepo: sourceRepo,
 		destRepo:   destRepo,
+		familyMap:  map[string]string{},
 	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
+
+// SyncOptions contains per-run options of a family sync
+type SyncOptions struct {
+	// SkipVariants syncs only the family structure
+	SkipVariants bool
+	// VariantsOnly syncs only the variants of a family already migrated
+	VariantsOnly bool
+	// Variants restricts the synced variants to these codes; empty means all variants
+	Variants []string
 }
 
 // SyncResult contains the result of a sync operation
@@ -26,40 +69,136 @@ type SyncResult struct {
 	Code           string
 	Success        bool
 	Error          string
+	FamilySynced   bool
 	VariantsSynced int
 	VariantsErrors []string
+	// VariantConflicts are variants whose axes or levels differ in destination; they are not written
+	VariantConflicts []VariantConflict
+	// DestCode is the code of the family in destination when it is mapped to another code
+	DestCode string
+	// Planned are the writes recorded instead of being sent during a dry run
+	Planned []dryrun.Write
+}
+
+// Failures returns the family when it or some of its variants could not be synchronized.
+// Variant conflicts are not returned: they need a manual fix in destination.
+func (r *SyncResult) Failures() []retry.Failure {
+	if r.Error != "" {
+		return []retry.Failure{{Kind: KindFamily, Code: r.Code, Error: r.Error}}
+	}
+	if len(r.VariantsErrors) > 0 {
+		return []retry.Failure{{Kind: KindFamilyVariants, Code: r.Code, Error: strings.Join(r.VariantsErrors, "; ")}}
+	}
+	return nil
+}
+
+// Synced returns the number of families and variants written
+func (r *SyncResult) Synced() int {
+	synced := r.VariantsSynced
+	if r.FamilySynced {
+		synced++
+	}
+	return synced
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// VariantConflict describes a breaking structural difference of a family variant
+type VariantConflict struct {
+	Code   string
+	Reason string
 }
 
 // Sync synchronizes a single family from source to destination
-func (s *Service) Sync(ctx context.Context, code string) (*SyncResult, error) {
+func (s *Service) Sync(ctx context.Context, code string, opts SyncOptions) (*SyncResult, error) {
 	result := &SyncResult{
-		Code:           code,
-		Success:        false,
-		VariantsSynced: 0,
-		VariantsErrors: []string{},
+		Code:             code,
+		Success:          false,
+		VariantsSynced:   0,
+		VariantsErrors:   []string{},
+		VariantConflicts: []VariantConflict{},
 	}
+	ctx, planned := dryrun.Collect(ctx)
 
-	// 1. Get family from source
-	familyData, err := s.sourceRepo.FindByCode(ctx, code)
-	if err != nil {
-		return nil, fmt.Errorf("error fetching family from source: %w", err)
+	if opts.SkipVariants && opts.VariantsOnly {
+		return nil, fmt.Errorf("cannot skip variants and sync only variants at the same time")
 	}
 
-	// 2. Save family to destination
-	err = s.destRepo.Save(ctx, code, familyData)
-	if err != nil {
-		result.Success = false
-		result.Error = err.Error()
-		return result, fmt.Errorf("error saving family to destination: %w", err)
+	destCode := code
+	if mapped, exists := s.familyMap[code]; exists {
+		destCode = mapped
+		result.DestCode = mapped
+	}
+
+	if !opts.VariantsOnly {
+		// 1. Get family from source
+		familyData, err := s.sourceRepo.FindByCode(ctx, code)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching family from source: %w", err)
+		}
+
+		// 2. Save family to destination, under its destination code when it is mapped
+		if destCode != code {
+			familyData = withCode(familyData, destCode)
+		}
+		if !dryrun.Record(ctx, dryrun.Write{Kind: KindFamily, Code: destCode, Data: familyData}) {
+			err = s.destRepo.Save(ctx, destCode, familyData)
+		}
+		if err != nil {
+			result.Success = false
+			result.Error = err.Error()
+			return result, fmt.Errorf("error saving family to destination: %w", err)
+		}
+
+		result.FamilySynced = true
+	}
+
+	if opts.SkipVariants {
+		result.Planned = planned()
+		result.Success = true
+		return result, nil
 	}
 
 	// 3. Get family variants from source
 	variants, err := s.sourceRepo.GetVariants(ctx, code)
+	if err == nil && len(opts.Variants) > 0 {
+		variants = selectVariants(variants, opts.Variants, result)
+	}
 	if err != nil {
 		// Log error but don't fail the entire sync
 		result.VariantsErrors = append(result.VariantsErrors, fmt.Sprintf("error fetching variants: %v", err))
+	} else if destVariants, err := s.destRepo.GetVariants(ctx, destCode); err != nil {
+		// Without the destination variants axis changes cannot be detected, so nothing is written
+		result.VariantsErrors = append(result.VariantsErrors, fmt.Sprintf("error fetching destination variants: %v", err))
 	} else {
-		// 4. Sync each variant to destination
+		destByCode := make(map[string]family.FamilyVariant, len(destVariants))
+		for _, destVariant := range destVariants {
+			if variantCode, ok := destVariant["code"].(string); ok {
+				destByCode[variantCode] = destVariant
+			}
+		}
+
+		// 4. Detect axis conflicts before writing any variant
+		conflicts := make(map[string]bool)
+		for _, variant := range variants {
+			variantCode, _ := variant["code"].(string)
+			destVariant, exists := destByCode[variantCode]
+			if !exists {
+				continue
+			}
+			if reason := detectAxisConflict(variant, destVariant); reason != "" {
+				conflicts[variantCode] = true
+				result.VariantConflicts = append(result.VariantConflicts, VariantConflict{
+					Code:   variantCode,
+					Reason: reason,
+				})
+			}
+		}
+
+		// 5. Sync each variant to destination
 		for _, variant := range variants {
 			variantCode, ok := variant["code"].(string)
 			if !ok {
@@ -67,7 +206,16 @@ func (s *Service) Sync(ctx context.Context, code string) (*SyncResult, error) {
 				continue
 			}
 
-			err := s.destRepo.SaveVariant(ctx, code, variantCode, variant)
+			if conflicts[variantCode] {
+				continue
+			}
+
+			if dryrun.Record(ctx, dryrun.Write{Kind: KindFamilyVariant, Scope: destCode, Code: variantCode, Data: variant}) {
+				result.VariantsSynced++
+				continue
+			}
+
+			err := s.destRepo.SaveVariant(ctx, destCode, variantCode, variant)
 			if err != nil {
 				result.VariantsErrors = append(result.VariantsErrors, fmt.Sprintf("variant %s: %v", variantCode, err))
 			} else {
@@ -76,6 +224,136 @@ func (s *Service) Sync(ctx context.Context, code string) (*SyncResult, error) {
 		}
 	}
 
+	result.Planned = planned()
 	result.Success = true
 	return result, nil
 }
+
+// withCode returns a copy of a family with its code replaced
+func withCode(familyData family.Family, code string) family.Family {
+	result := make(family.Family, len(familyData))
+	for key, value := range familyData {
+		result[key] = value
+	}
+	result["code"] = code
+	return result
+}
+
+// CheckVariant compares the axes and levels of a family variant in source and destination before
+// products are written. It returns why they differ, empty when they match or when the variant does
+// not exist in destination yet.
+func (s *Service) CheckVariant(ctx context.Context, familyCode, variantCode string) (string, error) {
+	sourceVariants, err := s.sourceRepo.GetVariants(ctx, familyCode)
+	if err != nil {
+		return "", fmt.Errorf("error fetching variants of family %s from source: %w", familyCode, err)
+	}
+
+	destCode := familyCode
+	if mapped, exists := s.familyMap[familyCode]; exists {
+		destCode = mapped
+	}
+	destVariants, err := s.destRepo.GetVariants(ctx, destCode)
+	if errors.Is(err, family.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error fetching variants of family %s from destination: %w", destCode, err)
+	}
+
+	sourceVariant := findVariant(sourceVariants, variantCode)
+	destVariant := findVariant(destVariants, variantCode)
+	if sourceVariant == nil || destVariant == nil {
+		return "", nil
+	}
+
+	return detectAxisConflict(sourceVariant, destVariant), nil
+}
+
+// findVariant returns the variant with the given code, nil when there is none
+func findVariant(variants []family.FamilyVariant, code string) family.FamilyVariant {
+	for _, variant := range variants {
+		if variantCode, _ := variant["code"].(string); variantCode == code {
+			return variant
+		}
+	}
+	return nil
+}
+
+// detectAxisConflict compares the level structure and axes of a variant in source and destination.
+// Akeneo does not allow changing them once the variant exists, so any difference is breaking.
+func detectAxisConflict(source, dest family.FamilyVariant) string {
+	sourceAxes := axesByLevel(source)
+	destAxes := axesByLevel(dest)
+
+	if len(sourceAxes) != len(destAxes) {
+		return fmt.Sprintf("level count differs (source: %d, destination: %d)", len(sourceAxes), len(destAxes))
+	}
+
+	levels := make([]int, 0, len(sourceAxes))
+	for level := range sourceAxes {
+		levels = append(levels, level)
+	}
+	sort.Ints(levels)
+
+	for _, level := range levels {
+		destLevelAxes, exists := destAxes[level]
+		if !exists {
+			return fmt.Sprintf("level %d does not exist in destination", level)
+		}
+		if sourceAxes[level] != destLevelAxes {
+			return fmt.Sprintf("axes of level %d differ (source: %s, destination: %s)", level, sourceAxes[level], destLevelAxes)
+		}
+	}
+
+	return ""
+}
+
+// axesByLevel returns the sorted, comma-separated axes of each level of a variant
+func axesByLevel(variant family.FamilyVariant) map[int]string {
+	result := make(map[int]string)
+
+	sets, _ := variant["variant_attribute_sets"].([]interface{})
+	for _, set := range sets {
+		attributeSet, ok := set.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		level, _ := attributeSet["level"].(float64)
+		rawAxes, _ := attributeSet["axes"].([]interface{})
+
+		axes := make([]string, 0, len(rawAxes))
+		for _, axis := range rawAxes {
+			if axisCode, ok := axis.(string); ok {
+				axes = append(axes, axisCode)
+			}
+		}
+		sort.Strings(axes)
+
+		result[int(level)] = strings.Join(axes, ",")
+	}
+
+	return result
+}
+
+// selectVariants keeps only the requested variants, reporting requested codes missing in source
+func selectVariants(variants []family.FamilyVariant, codes []string, result *SyncResult) []family.FamilyVariant {
+	byCode := make(map[string]family.FamilyVariant, len(variants))
+	for _, variant := range variants {
+		if variantCode, ok := variant["code"].(string); ok {
+			byCode[variantCode] = variant
+		}
+	}
+
+	selected := make([]family.FamilyVariant, 0, len(codes))
+	for _, variantCode := range codes {
+		variant, exists := byCode[variantCode]
+		if !exists {
+			result.VariantsErrors = append(result.VariantsErrors, fmt.Sprintf("variant %s: not found in source", variantCode))
+			continue
+		}
+		selected = append(selected, variant)
+	}
+
+	return selected
+}
@@ -0,0 +1,290 @@
+package syncing
+
+import (
+	"context"
+	"testing"
+
+	"akeneo-migrator/internal/family"
+	"akeneo-migrator/kit/dryrun"
+)
+
+// Mock repositories
+type mockSourceRepo struct {
+	getVariantsFunc func(ctx context.Context, familyCode string) ([]family.FamilyVariant, error)
+}
+
+func (m *mockSourceRepo) FindByCode(ctx context.Context, code string) (family.Family, error) {
+	return family.Family{"code": code}, nil
+}
+
+func (m *mockSourceRepo) FindPage(ctx context.Context, page, limit int) ([]family.Family, bool, error) {
+	return nil, false, nil
+}
+
+func (m *mockSourceRepo) GetVariants(ctx context.Context, familyCode string) ([]family.FamilyVariant, error) {
+	if m.getVariantsFunc != nil {
+		return m.getVariantsFunc(ctx, familyCode)
+	}
+	return nil, nil
+}
+
+type mockDestRepo struct {
+	getVariantsFunc func(ctx context.Context, familyCode string) ([]family.FamilyVariant, error)
+	savedFamily     bool
+	savedCode       string
+	savedVariants   []string
+}
+
+func (m *mockDestRepo) FindByCode(ctx context.Context, code string) (family.Family, error) {
+	return nil, nil
+}
+
+func (m *mockDestRepo) GetVariants(ctx context.Context, familyCode string) ([]family.FamilyVariant, error) {
+	if m.getVariantsFunc != nil {
+		return m.getVariantsFunc(ctx, familyCode)
+	}
+	return nil, nil
+}
+
+func (m *mockDestRepo) Save(ctx context.Context, code string, fam family.Family) error {
+	m.savedFamily = true
+	m.savedCode = code
+	return nil
+}
+
+func (m *mockDestRepo) SaveVariant(ctx context.Context, familyCode, variantCode string, variant family.FamilyVariant) error {
+	m.savedVariants = append(m.savedVariants, variantCode)
+	return nil
+}
+
+func variantWithAxes(code string, axesByLevel ...[]interface{}) family.FamilyVariant {
+	sets := make([]interface{}, len(axesByLevel))
+	for i, axes := range axesByLevel {
+		sets[i] = map[string]interface{}{"level": float64(i + 1), "axes": axes}
+	}
+	return family.FamilyVariant{"code": code, "variant_attribute_sets": sets}
+}
+
+func TestSync_VariantAxisConflict(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		getVariantsFunc: func(ctx context.Context, familyCode string) ([]family.FamilyVariant, error) {
+			return []family.FamilyVariant{
+				variantWithAxes("by_size", []interface{}{"size"}),
+				variantWithAxes("by_color_size", []interface{}{"color"}, []interface{}{"size"}),
+				variantWithAxes("by_material", []interface{}{"material"}),
+				variantWithAxes("new_variant", []interface{}{"color"}),
+			}, nil
+		},
+	}
+
+	destRepo := &mockDestRepo{
+		getVariantsFunc: func(ctx context.Context, familyCode string) ([]family.FamilyVariant, error) {
+			return []family.FamilyVariant{
+				variantWithAxes("by_size", []interface{}{"size"}),
+				variantWithAxes("by_color_size", []interface{}{"color"}),
+				variantWithAxes("by_material", []interface{}{"fabric"}),
+			}, nil
+		},
+	}
+
+	service := NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background(), "shoes", SyncOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.VariantConflicts) != 2 {
+		t.Fatalf("Expected 2 conflicts, got %d: %+v", len(result.VariantConflicts), result.VariantConflicts)
+	}
+
+	if result.VariantsSynced != 2 {
+		t.Errorf("Expected 2 variants synced, got %d", result.VariantsSynced)
+	}
+
+	for _, code := range destRepo.savedVariants {
+		if code == "by_color_size" || code == "by_material" {
+			t.Errorf("Expected conflicting variant %s not to be written", code)
+		}
+	}
+}
+
+func TestSync_AxesOrderIsIgnored(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		getVariantsFunc: func(ctx context.Context, familyCode string) ([]family.FamilyVariant, error) {
+			return []family.FamilyVariant{variantWithAxes("by_color_size", []interface{}{"color", "size"})}, nil
+		},
+	}
+
+	destRepo := &mockDestRepo{
+		getVariantsFunc: func(ctx context.Context, familyCode string) ([]family.FamilyVariant, error) {
+			return []family.FamilyVariant{variantWithAxes("by_color_size", []interface{}{"size", "color"})}, nil
+		},
+	}
+
+	service := NewService(sourceRepo, destRepo)
+	result, _ := service.Sync(context.Background(), "shoes", SyncOptions{})
+
+	if len(result.VariantConflicts) != 0 {
+		t.Errorf("Expected no conflicts, got %+v", result.VariantConflicts)
+	}
+}
+
+func TestCheckVariant(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		getVariantsFunc: func(ctx context.Context, familyCode string) ([]family.FamilyVariant, error) {
+			return []family.FamilyVariant{
+				variantWithAxes("by_size", []interface{}{"size"}),
+				variantWithAxes("by_color_size", []interface{}{"color"}, []interface{}{"size"}),
+			}, nil
+		},
+	}
+
+	destRepo := &mockDestRepo{
+		getVariantsFunc: func(ctx context.Context, familyCode string) ([]family.FamilyVariant, error) {
+			if familyCode != "shoes" {
+				return nil, family.ErrNotFound
+			}
+			return []family.FamilyVariant{
+				variantWithAxes("by_size", []interface{}{"size"}),
+				variantWithAxes("by_color_size", []interface{}{"color"}),
+			}, nil
+		},
+	}
+
+	service := NewService(sourceRepo, destRepo)
+
+	if reason, err := service.CheckVariant(context.Background(), "shoes", "by_size"); err != nil || reason != "" {
+		t.Errorf("Expected matching variant to pass, got %q (%v)", reason, err)
+	}
+	if reason, err := service.CheckVariant(context.Background(), "shoes", "by_color_size"); err != nil || reason == "" {
+		t.Errorf("Expected changed levels to be reported, got %q (%v)", reason, err)
+	}
+	if reason, err := service.CheckVariant(context.Background(), "bags", "by_size"); err != nil || reason != "" {
+		t.Errorf("Expected a family missing in destination to pass, got %q (%v)", reason, err)
+	}
+}
+
+func TestSync_SkipVariants(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		getVariantsFunc: func(ctx context.Context, familyCode string) ([]family.FamilyVariant, error) {
+			return []family.FamilyVariant{variantWithAxes("by_size", []interface{}{"size"})}, nil
+		},
+	}
+	destRepo := &mockDestRepo{}
+
+	service := NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background(), "shoes", SyncOptions{SkipVariants: true})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !result.FamilySynced || !destRepo.savedFamily {
+		t.Error("Expected family to be synced")
+	}
+
+	if len(destRepo.savedVariants) != 0 {
+		t.Errorf("Expected no variants to be written, got %v", destRepo.savedVariants)
+	}
+}
+
+func TestSync_WritesMappedFamilyCode(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		getVariantsFunc: func(ctx context.Context, familyCode string) ([]family.FamilyVariant, error) {
+			return []family.FamilyVariant{variantWithAxes("by_size", []interface{}{"size"})}, nil
+		},
+	}
+	var destVariantsOf string
+	destRepo := &mockDestRepo{
+		getVariantsFunc: func(ctx context.Context, familyCode string) ([]family.FamilyVariant, error) {
+			destVariantsOf = familyCode
+			return nil, nil
+		},
+	}
+
+	service := NewService(sourceRepo, destRepo, WithFamilyMap(map[string]string{"shoes": "footwear"}))
+	result, err := service.Sync(context.Background(), "shoes", SyncOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.DestCode != "footwear" || destRepo.savedCode != "footwear" {
+		t.Errorf("Expected the family to be written as footwear, got %s", destRepo.savedCode)
+	}
+
+	if destVariantsOf != "footwear" || len(destRepo.savedVariants) != 1 {
+		t.Errorf("Expected the variants to be compared and written under footwear, got %s %v", destVariantsOf, destRepo.savedVariants)
+	}
+}
+
+func TestSync_VariantsOnlyWithSelection(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		getVariantsFunc: func(ctx context.Context, familyCode string) ([]family.FamilyVariant, error) {
+			return []family.FamilyVariant{
+				variantWithAxes("by_size", []interface{}{"size"}),
+				variantWithAxes("by_color", []interface{}{"color"}),
+			}, nil
+		},
+	}
+	destRepo := &mockDestRepo{}
+
+	service := NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background(), "shoes", SyncOptions{
+		VariantsOnly: true,
+		Variants:     []string{"by_color", "unknown"},
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.FamilySynced || destRepo.savedFamily {
+		t.Error("Expected family structure not to be written")
+	}
+
+	if len(destRepo.savedVariants) != 1 || destRepo.savedVariants[0] != "by_color" {
+		t.Errorf("Expected only by_color to be written, got %v", destRepo.savedVariants)
+	}
+
+	if len(result.VariantsErrors) != 1 {
+		t.Errorf("Expected 1 variant error for unknown code, got %v", result.VariantsErrors)
+	}
+}
+
+func TestSync_ConflictingModes(t *testing.T) {
+	service := NewService(&mockSourceRepo{}, &mockDestRepo{})
+	_, err := service.Sync(context.Background(), "shoes", SyncOptions{SkipVariants: true, VariantsOnly: true})
+
+	if err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestSync_DryRunRecordsWrites(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		getVariantsFunc: func(ctx context.Context, familyCode string) ([]family.FamilyVariant, error) {
+			return []family.FamilyVariant{variantWithAxes("by_size", []interface{}{"size"})}, nil
+		},
+	}
+	destRepo := &mockDestRepo{}
+
+	service := NewService(sourceRepo, destRepo)
+	result, err := service.Sync(dryrun.With(context.Background()), "shoes", SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if destRepo.savedFamily || len(destRepo.savedVariants) != 0 {
+		t.Errorf("Expected nothing written to destination, got family %v and variants %v", destRepo.savedFamily, destRepo.savedVariants)
+	}
+
+	planned := result.PlannedWrites()
+	if len(planned) != 2 || planned[0].Kind != KindFamily || planned[1].Kind != KindFamilyVariant || planned[1].Scope != "shoes" {
+		t.Errorf("Expected the family and its variant to be planned, got %+v", planned)
+	}
+	if result.Synced() != 2 {
+		t.Errorf("Expected the planned items to be counted, got %d", result.Synced())
+	}
+}
@@ -0,0 +1,16 @@
+package syncing_all
+
+import "akeneo-migrator/kit/bus"
+
+const SyncAllFamiliesCommandType bus.Type = "family.sync_all"
+
+// SyncAllFamiliesCommand represents a command to sync every family of the source
+type SyncAllFamiliesCommand struct {
+	SkipVariants bool
+	Debug        bool
+}
+
+// Type returns the command type
+func (c SyncAllFamiliesCommand) Type() bus.Type {
+	return SyncAllFamiliesCommandType
+}
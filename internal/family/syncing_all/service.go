@@ -0,0 +1,101 @@
+package syncing_all
+
+import (
+	"context"
+
+	"akeneo-migrator/internal/family"
+	"akeneo-migrator/internal/family/syncing"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/retry"
+)
+
+// PageSize is the number of families fetched from source at a time
+const PageSize = 100
+
+// Service synchronizes every family of the source, one at a time
+type Service struct {
+	sourceRepo     family.SourceRepository
+	syncingService *syncing.Service
+}
+
+// NewService creates a new instance of the all families sync service
+func NewService(sourceRepo family.SourceRepository, destRepo family.DestRepository, opts ...syncing.Option) *Service {
+	return &Service{
+		sourceRepo:     sourceRepo,
+		syncingService: syncing.NewService(sourceRepo, destRepo, opts...),
+	}
+}
+
+// SyncResult contains the result of syncing every family
+type SyncResult struct {
+	// Families are the results of each family, in source order
+	Families       []*syncing.SyncResult
+	FamiliesSynced int
+	VariantsSynced int
+	Success        bool
+	FailedItems    []retry.Failure
+	// Planned are the writes recorded instead of being sent during a dry run, for the whole sync
+	Planned []dryrun.Write
+}
+
+// Failures returns the families that could not be synchronized, completely or partially
+func (r *SyncResult) Failures() []retry.Failure {
+	return r.FailedItems
+}
+
+// Synced returns the number of families and variants written
+func (r *SyncResult) Synced() int {
+	return r.FamiliesSynced + r.VariantsSynced
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// Sync walks the families of the source page by page and synchronizes each one with its variants.
+// A family that fails is reported and does not stop the others.
+func (s *Service) Sync(ctx context.Context, opts syncing.SyncOptions) (*SyncResult, error) {
+	result := &SyncResult{}
+	ctx, planned := dryrun.Collect(ctx)
+
+	for page := 1; ; page++ {
+		families, hasNext, err := s.sourceRepo.FindPage(ctx, page, PageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fam := range families {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			code, _ := fam["code"].(string)
+			if code == "" {
+				continue
+			}
+
+			familyResult, err := s.syncingService.Sync(ctx, code, opts)
+			if familyResult == nil {
+				familyResult = &syncing.SyncResult{Code: code, Error: err.Error()}
+			}
+			// Planned writes are kept once, in the result of the whole sync
+			familyResult.Planned = nil
+
+			result.Families = append(result.Families, familyResult)
+			result.FailedItems = append(result.FailedItems, familyResult.Failures()...)
+			if familyResult.FamilySynced {
+				result.FamiliesSynced++
+			}
+			result.VariantsSynced += familyResult.VariantsSynced
+		}
+
+		if !hasNext {
+			break
+		}
+	}
+
+	result.Planned = planned()
+	result.Success = len(result.FailedItems) == 0
+	return result, nil
+}
@@ -0,0 +1,109 @@
+package syncing_all
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"akeneo-migrator/internal/family"
+	"akeneo-migrator/internal/family/syncing"
+)
+
+// mockSourceRepo serves families in pages of the requested size
+type mockSourceRepo struct {
+	codes []string
+	pages []int
+}
+
+func (m *mockSourceRepo) FindByCode(ctx context.Context, code string) (family.Family, error) {
+	return family.Family{"code": code}, nil
+}
+
+func (m *mockSourceRepo) GetVariants(ctx context.Context, familyCode string) ([]family.FamilyVariant, error) {
+	return []family.FamilyVariant{{"code": familyCode + "_size"}}, nil
+}
+
+func (m *mockSourceRepo) FindPage(ctx context.Context, page, limit int) ([]family.Family, bool, error) {
+	m.pages = append(m.pages, page)
+
+	start := (page - 1) * limit
+	end := start + limit
+	if end > len(m.codes) {
+		end = len(m.codes)
+	}
+
+	families := make([]family.Family, 0, end-start)
+	for _, code := range m.codes[start:end] {
+		families = append(families, family.Family{"code": code})
+	}
+	return families, end < len(m.codes), nil
+}
+
+// mockDestRepo rejects the family named in failing
+type mockDestRepo struct {
+	failing string
+	saved   []string
+}
+
+func (m *mockDestRepo) FindByCode(ctx context.Context, code string) (family.Family, error) {
+	return nil, errors.New("not found")
+}
+
+func (m *mockDestRepo) GetVariants(ctx context.Context, familyCode string) ([]family.FamilyVariant, error) {
+	return nil, nil
+}
+
+func (m *mockDestRepo) Save(ctx context.Context, code string, fam family.Family) error {
+	if code == m.failing {
+		return errors.New("validation error")
+	}
+	m.saved = append(m.saved, code)
+	return nil
+}
+
+func (m *mockDestRepo) SaveVariant(ctx context.Context, familyCode, variantCode string, variant family.FamilyVariant) error {
+	return nil
+}
+
+func TestSync_WalksEveryPageAndReportsFailedFamilies(t *testing.T) {
+	codes := make([]string, PageSize+1)
+	for i := range codes {
+		codes[i] = fmt.Sprintf("family_%03d", i)
+	}
+	sourceRepo := &mockSourceRepo{codes: codes}
+	destRepo := &mockDestRepo{failing: codes[3]}
+
+	result, err := NewService(sourceRepo, destRepo).Sync(context.Background(), syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(sourceRepo.pages) != 2 {
+		t.Errorf("Expected 2 pages to be fetched, got %v", sourceRepo.pages)
+	}
+
+	if len(result.Families) != len(codes) || result.FamiliesSynced != len(codes)-1 || result.VariantsSynced != len(codes)-1 {
+		t.Errorf("Expected every family but one to be synced with its variant, got %d families, %d synced, %d variants",
+			len(result.Families), result.FamiliesSynced, result.VariantsSynced)
+	}
+
+	failures := result.Failures()
+	if len(failures) != 1 || failures[0].Kind != syncing.KindFamily || failures[0].Code != codes[3] || result.Success {
+		t.Errorf("Expected %s to be reported as failed, got %v", codes[3], failures)
+	}
+}
+
+func TestSync_SkipsVariants(t *testing.T) {
+	sourceRepo := &mockSourceRepo{codes: []string{"clothing"}}
+	destRepo := &mockDestRepo{}
+
+	result, err := NewService(sourceRepo, destRepo).Sync(context.Background(), syncing.SyncOptions{SkipVariants: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.FamiliesSynced != 1 || result.VariantsSynced != 0 || !result.Success {
+		t.Errorf("Expected only the family structure to be synced, got %+v", result)
+	}
+}
@@ -0,0 +1,16 @@
+package verifying
+
+import "akeneo-migrator/kit/bus"
+
+const VerifyFamilyCommandType bus.Type = "family.verify"
+
+// VerifyFamilyCommand represents a command to verify a family against the destination
+type VerifyFamilyCommand struct {
+	Code  string
+	Debug bool
+}
+
+// Type returns the command type
+func (c VerifyFamilyCommand) Type() bus.Type {
+	return VerifyFamilyCommandType
+}
@@ -0,0 +1,91 @@
+package verifying
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"akeneo-migrator/internal/family"
+	"akeneo-migrator/kit/checksum"
+)
+
+// Service compares a family and its variants between source and destination without writing
+type Service struct {
+	sourceRepo family.SourceRepository
+	destRepo   family.DestRepository
+}
+
+// NewService creates a new family verify service
+func NewService(sourceRepo family.SourceRepository, destRepo family.DestRepository) *Service {
+	return &Service{
+		sourceRepo: sourceRepo,
+		destRepo:   destRepo,
+	}
+}
+
+// Verify compares a family and its variants using normalized checksums
+func (s *Service) Verify(ctx context.Context, code string) (*checksum.Report, error) {
+	report := checksum.NewReport("family", code)
+
+	// 1. Compare the family definition
+	sourceFamily, err := s.sourceRepo.FindByCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching family from source: %w", err)
+	}
+
+	destFamily, err := s.destRepo.FindByCode(ctx, code)
+	if errors.Is(err, family.ErrNotFound) {
+		// Without the family there are no variants to compare on the destination
+		report.Missing("family", code, err.Error())
+		return report, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching family from destination: %w", err)
+	}
+
+	if err := report.Compare("family", code, sourceFamily, destFamily); err != nil {
+		return nil, err
+	}
+
+	// 2. Compare variants
+	sourceVariants, err := s.sourceRepo.GetVariants(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching variants from source: %w", err)
+	}
+
+	destVariants, err := s.destRepo.GetVariants(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching variants from destination: %w", err)
+	}
+
+	destByCode := make(map[string]family.FamilyVariant, len(destVariants))
+	for _, variant := range destVariants {
+		if variantCode, ok := variant["code"].(string); ok {
+			destByCode[variantCode] = variant
+		}
+	}
+
+	for _, variant := range sourceVariants {
+		variantCode, ok := variant["code"].(string)
+		if !ok {
+			continue
+		}
+
+		destVariant, exists := destByCode[variantCode]
+		delete(destByCode, variantCode)
+		if !exists {
+			report.Missing("family_variant", variantCode, "")
+			continue
+		}
+
+		if err := report.Compare("family_variant", variantCode, variant, destVariant); err != nil {
+			return nil, err
+		}
+	}
+
+	for variantCode := range destByCode {
+		report.Extra("family_variant", variantCode)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,166 @@
+package verifying
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"akeneo-migrator/internal/family"
+	"akeneo-migrator/kit/checksum"
+)
+
+type mockSourceRepo struct {
+	findByCodeFunc  func(ctx context.Context, code string) (family.Family, error)
+	getVariantsFunc func(ctx context.Context, familyCode string) ([]family.FamilyVariant, error)
+}
+
+func (m *mockSourceRepo) FindByCode(ctx context.Context, code string) (family.Family, error) {
+	if m.findByCodeFunc != nil {
+		return m.findByCodeFunc(ctx, code)
+	}
+	return nil, nil
+}
+
+func (m *mockSourceRepo) FindPage(ctx context.Context, page, limit int) ([]family.Family, bool, error) {
+	return nil, false, nil
+}
+
+func (m *mockSourceRepo) GetVariants(ctx context.Context, familyCode string) ([]family.FamilyVariant, error) {
+	if m.getVariantsFunc != nil {
+		return m.getVariantsFunc(ctx, familyCode)
+	}
+	return nil, nil
+}
+
+type mockDestRepo struct {
+	findByCodeFunc  func(ctx context.Context, code string) (family.Family, error)
+	getVariantsFunc func(ctx context.Context, familyCode string) ([]family.FamilyVariant, error)
+	saveCalls       int
+}
+
+func (m *mockDestRepo) FindByCode(ctx context.Context, code string) (family.Family, error) {
+	if m.findByCodeFunc != nil {
+		return m.findByCodeFunc(ctx, code)
+	}
+	return nil, nil
+}
+
+func (m *mockDestRepo) GetVariants(ctx context.Context, familyCode string) ([]family.FamilyVariant, error) {
+	if m.getVariantsFunc != nil {
+		return m.getVariantsFunc(ctx, familyCode)
+	}
+	return nil, nil
+}
+
+func (m *mockDestRepo) Save(ctx context.Context, code string, fam family.Family) error {
+	m.saveCalls++
+	return nil
+}
+
+func (m *mockDestRepo) SaveVariant(ctx context.Context, familyCode, variantCode string, variant family.FamilyVariant) error {
+	m.saveCalls++
+	return nil
+}
+
+func TestVerify_ReportsDifferences(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (family.Family, error) {
+			return family.Family{"code": code, "attributes": []interface{}{"sku", "name"}}, nil
+		},
+		getVariantsFunc: func(ctx context.Context, familyCode string) ([]family.FamilyVariant, error) {
+			return []family.FamilyVariant{
+				{"code": "by_size", "variant_attribute_sets": []interface{}{"size"}},
+				{"code": "by_color", "variant_attribute_sets": []interface{}{"color"}},
+			}, nil
+		},
+	}
+
+	destRepo := &mockDestRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (family.Family, error) {
+			return family.Family{"code": code, "attributes": []interface{}{"name", "sku"}, "updated": "2024-01-01"}, nil
+		},
+		getVariantsFunc: func(ctx context.Context, familyCode string) ([]family.FamilyVariant, error) {
+			return []family.FamilyVariant{
+				{"code": "by_size", "variant_attribute_sets": []interface{}{"color"}},
+				{"code": "legacy"},
+			}, nil
+		},
+	}
+
+	service := NewService(sourceRepo, destRepo)
+	report, err := service.Verify(context.Background(), "shoes")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if report.Matches != 1 {
+		t.Errorf("Expected 1 match, got %d", report.Matches)
+	}
+
+	if report.Count(checksum.StatusMismatch) != 1 {
+		t.Errorf("Expected 1 mismatch, got %d", report.Count(checksum.StatusMismatch))
+	}
+
+	if report.Count(checksum.StatusMissing) != 1 {
+		t.Errorf("Expected 1 missing item, got %d", report.Count(checksum.StatusMissing))
+	}
+
+	if report.Count(checksum.StatusExtra) != 1 {
+		t.Errorf("Expected 1 extra item, got %d", report.Count(checksum.StatusExtra))
+	}
+
+	if destRepo.saveCalls != 0 {
+		t.Errorf("Expected no writes to destination, got %d", destRepo.saveCalls)
+	}
+}
+
+func TestVerify_MissingFamily(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (family.Family, error) {
+			return family.Family{"code": code}, nil
+		},
+	}
+
+	destRepo := &mockDestRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (family.Family, error) {
+			return nil, family.ErrNotFound
+		},
+	}
+
+	service := NewService(sourceRepo, destRepo)
+	report, err := service.Verify(context.Background(), "shoes")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if report.OK() {
+		t.Error("Expected report not to be OK")
+	}
+
+	if report.Count(checksum.StatusMissing) != 1 {
+		t.Errorf("Expected 1 missing item, got %d", report.Count(checksum.StatusMissing))
+	}
+}
+
+func TestVerify_DestinationError(t *testing.T) {
+	sourceRepo := &mockSourceRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (family.Family, error) {
+			return family.Family{"code": code}, nil
+		},
+	}
+
+	destRepo := &mockDestRepo{
+		findByCodeFunc: func(ctx context.Context, code string) (family.Family, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	service := NewService(sourceRepo, destRepo)
+	_, err := service.Verify(context.Background(), "shoes")
+
+	if err == nil {
+		t.Fatal("Expected an error when the destination cannot be read, got nil")
+	}
+}
@@ -0,0 +1,114 @@
+package job
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/logger"
+	"akeneo-migrator/kit/retry"
+)
+
+// ErrNotFound is returned when a job does not exist in the state store
+var ErrNotFound = errors.New("job not found")
+
+// Job is a sync run whose failed items were queued to be retried
+type Job struct {
+	ID        string    `json:"id"`
+	Command   string    `json:"command"`
+	CreatedAt time.Time `json:"createdAt"`
+	// RetryOf is the job whose remaining failures this job contains
+	RetryOf string `json:"retryOf,omitempty"`
+	// RetriedAt is set once the failures of the job have been retried
+	RetriedAt *time.Time `json:"retriedAt,omitempty"`
+	// RetriedBy is the job holding the failures that remained after the retry
+	RetriedBy string          `json:"retriedBy,omitempty"`
+	Failures  []retry.Failure `json:"failures"`
+}
+
+// New creates a job for the failures of a command, identified by its creation time
+func New(command string, failures []retry.Failure, now time.Time) Job {
+	suffix := make([]byte, 2)
+	_, _ = rand.Read(suffix)
+
+	return Job{
+		ID:        now.UTC().Format("20060102-150405") + "-" + hex.EncodeToString(suffix),
+		Command:   command,
+		CreatedAt: now.UTC(),
+		Failures:  failures,
+	}
+}
+
+// Pending reports whether the job still has failures that have not been retried
+func (j Job) Pending() bool {
+	return j.RetriedAt == nil && len(j.Failures) > 0
+}
+
+// Recorder returns the function used by the failure queue to store the failures of a command as a new job
+func Recorder(repo Repository) retry.RecordFunc {
+	return func(ctx context.Context, command bus.Type, failures []retry.Failure) (string, error) {
+		j := New(string(command), failures, time.Now())
+		if err := repo.Save(ctx, j); err != nil {
+			return "", err
+		}
+		return j.ID, nil
+	}
+}
+
+// ManifestRecorder wraps a recorder so the failures of the whole run are also written to a manifest
+// file, which retry-failed accepts instead of a job ID. The manifest is rewritten each time
+// a command of the run records failures.
+func ManifestRecorder(record retry.RecordFunc, manifests ManifestRepository, path string, log *logger.Logger) retry.RecordFunc {
+	var mu sync.Mutex
+	var manifest *Job
+
+	return func(ctx context.Context, command bus.Type, failures []retry.Failure) (string, error) {
+		id, err := record(ctx, command, failures)
+		if err != nil {
+			return "", err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if manifest == nil {
+			created := New(string(command), nil, time.Now())
+			manifest = &created
+		}
+		manifest.Failures = append(manifest.Failures, failures...)
+
+		// The failures are queued anyway, so a manifest that cannot be written does not fail the command
+		if err := manifests.Write(ctx, path, *manifest); err != nil {
+			log.Warn("⚠️  Could not write failure manifest", logger.F("path", path), logger.Err(err))
+			return id, nil
+		}
+		log.Info("📄 Failures written, run retry-failed with the file", logger.F("path", path))
+
+		return id, nil
+	}
+}
+
+// Repository persists jobs in the state store
+type Repository interface {
+	// Save creates or updates a job
+	Save(ctx context.Context, job Job) error
+
+	// FindByID retrieves a job by its ID, returning ErrNotFound when it does not exist
+	FindByID(ctx context.Context, id string) (Job, error)
+
+	// FindAll retrieves all jobs, oldest first
+	FindAll(ctx context.Context) ([]Job, error)
+}
+
+// ManifestRepository reads and writes jobs as manifest files chosen by the user
+type ManifestRepository interface {
+	// Write saves a job to path, replacing the file if it exists
+	Write(ctx context.Context, path string, job Job) error
+
+	// Read retrieves the job saved in path, returning ErrNotFound when the file does not exist
+	Read(ctx context.Context, path string) (Job, error)
+}
@@ -0,0 +1,18 @@
+package retrying
+
+import "akeneo-migrator/kit/bus"
+
+const RetryFailedCommandType bus.Type = "job.retry_failed"
+
+// RetryFailedCommand represents a command to reprocess the failed items of a job
+type RetryFailedCommand struct {
+	// JobID is the job to retry; the latest pending job is used when empty
+	JobID string
+	// Manifest is a failure manifest file to retry instead of a job of the state store
+	Manifest string
+}
+
+// Type returns the command type
+func (c RetryFailedCommand) Type() bus.Type {
+	return RetryFailedCommandType
+}
@@ -0,0 +1,40 @@
+package retrying
+
+import (
+	"context"
+
+	"akeneo-migrator/kit/bus"
+)
+
+// CommandHandler handles RetryFailedCommand
+type CommandHandler struct {
+	service *Service
+}
+
+// NewCommandHandler creates a new command handler
+func NewCommandHandler(service *Service) *CommandHandler {
+	return &CommandHandler{
+		service: service,
+	}
+}
+
+// Handle executes the retry command
+func (h *CommandHandler) Handle(ctx context.Context, msg bus.Message) (bus.Response, error) {
+	cmd, ok := msg.(RetryFailedCommand)
+	if !ok {
+		return bus.Response{}, nil
+	}
+
+	var result *RetryResult
+	var err error
+	if cmd.Manifest != "" {
+		result, err = h.service.RetryManifest(ctx, cmd.Manifest)
+	} else {
+		result, err = h.service.Retry(ctx, cmd.JobID)
+	}
+	if err != nil {
+		return bus.Response{Error: err}, err
+	}
+
+	return bus.Response{Data: result}, nil
+}
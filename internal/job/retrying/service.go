@@ -0,0 +1,262 @@
+package retrying
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"akeneo-migrator/internal/job"
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/retry"
+)
+
+// Builder creates the commands reprocessing failed items of one kind sharing the same scope
+type Builder func(scope string, codes []string) []bus.Message
+
+// Service reprocesses the failed items of a job by dispatching sync commands for them only
+type Service struct {
+	repo       job.Repository
+	dispatcher bus.Bus
+	builders   map[string]Builder
+	manifests  job.ManifestRepository
+	now        func() time.Time
+}
+
+// Option configures the retry service
+type Option func(*Service)
+
+// WithBuilder registers how failed items of a kind are reprocessed
+func WithBuilder(kind string, builder Builder) Option {
+	return func(s *Service) {
+		s.builders[kind] = builder
+	}
+}
+
+// WithManifests lets jobs be retried from failure manifest files
+func WithManifests(manifests job.ManifestRepository) Option {
+	return func(s *Service) {
+		s.manifests = manifests
+	}
+}
+
+// NewService creates a new instance of the retry service
+func NewService(repo job.Repository, dispatcher bus.Bus, opts ...Option) *Service {
+	service := &Service{
+		repo:       repo,
+		dispatcher: dispatcher,
+		builders:   make(map[string]Builder),
+		now:        time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
+
+// RetryResult contains the result of a retry
+type RetryResult struct {
+	JobID    string
+	Retried  int
+	Resolved int
+	// Remaining are the items that still fail, queued under NewJobID
+	Remaining []retry.Failure
+	NewJobID  string
+}
+
+// Synced returns the number of retried items that no longer fail
+func (r *RetryResult) Synced() int {
+	return r.Resolved
+}
+
+// group contains the failed items of one kind and scope, in the order they were queued
+type group struct {
+	kind  string
+	scope string
+	codes []string
+}
+
+// Retry reprocesses the failed items of a job, or of the latest pending job when jobID is empty.
+// Items that still fail are queued in a new job and the retried job is marked as done.
+func (s *Service) Retry(ctx context.Context, jobID string) (*RetryResult, error) {
+	retried, err := s.findJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.retry(ctx, retried)
+}
+
+// RetryManifest reprocesses the failed items listed in a manifest file. The job of the manifest
+// is imported into the state store, so it is marked as done like any other retried job.
+func (s *Service) RetryManifest(ctx context.Context, path string) (*RetryResult, error) {
+	if s.manifests == nil {
+		return nil, fmt.Errorf("failure manifests are not supported")
+	}
+
+	manifest, err := s.manifests.Read(ctx, path)
+	if errors.Is(err, job.ErrNotFound) {
+		return nil, fmt.Errorf("manifest %s not found", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// A manifest retried before is found in the state store with its retry status
+	stored, err := s.repo.FindByID(ctx, manifest.ID)
+	switch {
+	case err == nil:
+		return s.retry(ctx, stored)
+	case !errors.Is(err, job.ErrNotFound):
+		return nil, err
+	}
+
+	if !dryrun.Enabled(ctx) {
+		if err := s.repo.Save(ctx, manifest); err != nil {
+			return nil, fmt.Errorf("error importing manifest %s: %w", path, err)
+		}
+	}
+
+	return s.retry(ctx, manifest)
+}
+
+// retry reprocesses the failed items of a job
+func (s *Service) retry(ctx context.Context, retried job.Job) (*RetryResult, error) {
+	if retried.RetriedAt != nil {
+		if retried.RetriedBy != "" {
+			return nil, fmt.Errorf("job %s was already retried, remaining failures are in job %s", retried.ID, retried.RetriedBy)
+		}
+		return nil, fmt.Errorf("job %s was already retried", retried.ID)
+	}
+
+	groups := groupFailures(retried.Failures)
+	result := &RetryResult{
+		JobID: retried.ID,
+	}
+
+	// Failures are collected by the retry itself instead of being queued by each command
+	retryCtx := retry.WithoutRecording(ctx)
+
+	for _, g := range groups {
+		result.Retried += len(g.codes)
+
+		builder, ok := s.builders[g.kind]
+		if !ok {
+			for _, code := range g.codes {
+				result.Remaining = append(result.Remaining, retry.Failure{
+					Kind:  g.kind,
+					Scope: g.scope,
+					Code:  code,
+					Error: fmt.Sprintf("items of kind '%s' cannot be retried", g.kind),
+				})
+			}
+			continue
+		}
+
+		for _, msg := range builder(g.scope, g.codes) {
+			response, dispatchErr := s.dispatcher.Dispatch(retryCtx, msg)
+			result.Remaining = append(result.Remaining, retry.Collect(msg, response, dispatchErr)...)
+		}
+	}
+
+	result.Resolved = countResolved(groups, result.Remaining)
+
+	// Nothing was written during a dry run, so the job stays pending
+	if dryrun.Enabled(ctx) {
+		return result, nil
+	}
+
+	now := s.now()
+	if len(result.Remaining) > 0 {
+		next := job.New(retried.Command, result.Remaining, now)
+		next.RetryOf = retried.ID
+		if err := s.repo.Save(ctx, next); err != nil {
+			return nil, fmt.Errorf("error queuing remaining failures: %w", err)
+		}
+		result.NewJobID = next.ID
+		retried.RetriedBy = next.ID
+	}
+
+	retried.RetriedAt = &now
+	if err := s.repo.Save(ctx, retried); err != nil {
+		return nil, fmt.Errorf("error updating job %s: %w", retried.ID, err)
+	}
+
+	return result, nil
+}
+
+// List returns all the jobs of the state store, oldest first
+func (s *Service) List(ctx context.Context) ([]job.Job, error) {
+	return s.repo.FindAll(ctx)
+}
+
+// findJob returns the given job, or the latest pending one when no ID is given
+func (s *Service) findJob(ctx context.Context, jobID string) (job.Job, error) {
+	if jobID != "" {
+		found, err := s.repo.FindByID(ctx, jobID)
+		if errors.Is(err, job.ErrNotFound) {
+			return job.Job{}, fmt.Errorf("job %s not found", jobID)
+		}
+		return found, err
+	}
+
+	jobs, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return job.Job{}, err
+	}
+
+	for i := len(jobs) - 1; i >= 0; i-- {
+		if jobs[i].Pending() {
+			return jobs[i], nil
+		}
+	}
+
+	return job.Job{}, fmt.Errorf("no pending job with failed items")
+}
+
+// groupFailures groups failures by kind and scope, dropping duplicated items
+func groupFailures(failures []retry.Failure) []*group {
+	groups := make([]*group, 0)
+	index := make(map[string]*group)
+	seen := make(map[string]bool)
+
+	for _, failure := range failures {
+		key := failure.Kind + "\x00" + failure.Scope
+		if seen[key+"\x00"+failure.Code] {
+			continue
+		}
+		seen[key+"\x00"+failure.Code] = true
+
+		g, ok := index[key]
+		if !ok {
+			g = &group{kind: failure.Kind, scope: failure.Scope}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		g.codes = append(g.codes, failure.Code)
+	}
+
+	return groups
+}
+
+// countResolved counts the retried items that no longer fail
+func countResolved(groups []*group, remaining []retry.Failure) int {
+	failing := make(map[retry.Failure]bool, len(remaining))
+	for _, failure := range remaining {
+		failing[retry.Failure{Kind: failure.Kind, Scope: failure.Scope, Code: failure.Code}] = true
+	}
+
+	resolved := 0
+	for _, g := range groups {
+		for _, code := range g.codes {
+			if !failing[retry.Failure{Kind: g.kind, Scope: g.scope, Code: code}] {
+				resolved++
+			}
+		}
+	}
+
+	return resolved
+}
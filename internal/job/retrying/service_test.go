@@ -0,0 +1,310 @@
+package retrying_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"akeneo-migrator/internal/job"
+	"akeneo-migrator/internal/job/retrying"
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/retry"
+)
+
+// MockRepository is an in-memory job repository for testing
+type MockRepository struct {
+	jobs map[string]job.Job
+}
+
+func (m *MockRepository) Save(ctx context.Context, j job.Job) error {
+	m.jobs[j.ID] = j
+	return nil
+}
+
+func (m *MockRepository) FindByID(ctx context.Context, id string) (job.Job, error) {
+	j, ok := m.jobs[id]
+	if !ok {
+		return job.Job{}, job.ErrNotFound
+	}
+	return j, nil
+}
+
+func (m *MockRepository) FindAll(ctx context.Context) ([]job.Job, error) {
+	jobs := make([]job.Job, 0, len(m.jobs))
+	for _, j := range m.jobs {
+		jobs = append(jobs, j)
+	}
+	for i := 1; i < len(jobs); i++ {
+		for k := i; k > 0 && jobs[k].CreatedAt.Before(jobs[k-1].CreatedAt); k-- {
+			jobs[k], jobs[k-1] = jobs[k-1], jobs[k]
+		}
+	}
+	return jobs, nil
+}
+
+// syncRecordsCommand is a fake command syncing the records of a reference entity
+type syncRecordsCommand struct {
+	Entity  string
+	Records []string
+}
+
+func (c syncRecordsCommand) Type() bus.Type {
+	return "records.sync"
+}
+
+// syncRecordsResult reports the records that still fail
+type syncRecordsResult struct {
+	failures []retry.Failure
+}
+
+func (r syncRecordsResult) Failures() []retry.Failure {
+	return r.failures
+}
+
+// MockBus dispatches messages to a single function
+type MockBus struct {
+	dispatchFunc func(ctx context.Context, msg bus.Message) (bus.Response, error)
+}
+
+func (m *MockBus) Dispatch(ctx context.Context, msg bus.Message) (bus.Response, error) {
+	return m.dispatchFunc(ctx, msg)
+}
+
+func (m *MockBus) Register(msgType bus.Type, handler bus.Handler) {}
+
+func newRepository(jobs ...job.Job) *MockRepository {
+	repo := &MockRepository{jobs: map[string]job.Job{}}
+	for _, j := range jobs {
+		repo.jobs[j.ID] = j
+	}
+	return repo
+}
+
+func recordsBuilder(scope string, codes []string) []bus.Message {
+	return []bus.Message{syncRecordsCommand{Entity: scope, Records: codes}}
+}
+
+func TestRetry_QueuesRemainingFailures(t *testing.T) {
+	failed := job.Job{
+		ID:        "job-1",
+		Command:   "reference_entity.sync",
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Failures: []retry.Failure{
+			{Kind: "record", Scope: "brands", Code: "acme"},
+			{Kind: "record", Scope: "brands", Code: "globex"},
+			{Kind: "record", Scope: "colors", Code: "red"},
+			{Kind: "record", Scope: "brands", Code: "acme"},
+		},
+	}
+	repo := newRepository(failed)
+
+	var dispatched []syncRecordsCommand
+	dispatcher := &MockBus{
+		dispatchFunc: func(ctx context.Context, msg bus.Message) (bus.Response, error) {
+			if !retry.RecordingDisabled(ctx) {
+				t.Error("Expected failures not to be queued by retried commands")
+			}
+
+			cmd := msg.(syncRecordsCommand)
+			dispatched = append(dispatched, cmd)
+
+			result := syncRecordsResult{}
+			if cmd.Entity == "brands" {
+				result.failures = []retry.Failure{{Kind: "record", Scope: "brands", Code: "globex", Error: "missing attribute"}}
+			}
+			return bus.Response{Data: result}, nil
+		},
+	}
+
+	service := retrying.NewService(repo, dispatcher, retrying.WithBuilder("record", recordsBuilder))
+	result, err := service.Retry(context.Background(), "job-1")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(dispatched) != 2 {
+		t.Fatalf("Expected one command per scope, got %d", len(dispatched))
+	}
+	if len(dispatched[0].Records) != 2 || dispatched[0].Records[0] != "acme" || dispatched[0].Records[1] != "globex" {
+		t.Errorf("Expected duplicated failures to be retried once, got %v", dispatched[0].Records)
+	}
+
+	if result.Retried != 3 || result.Resolved != 2 {
+		t.Errorf("Expected 3 retried and 2 resolved, got %d and %d", result.Retried, result.Resolved)
+	}
+
+	if result.NewJobID == "" {
+		t.Fatal("Expected remaining failures to be queued in a new job")
+	}
+
+	next := repo.jobs[result.NewJobID]
+	if next.RetryOf != "job-1" || len(next.Failures) != 1 || next.Failures[0].Code != "globex" {
+		t.Errorf("Expected new job with the remaining failure, got %+v", next)
+	}
+
+	original := repo.jobs["job-1"]
+	if original.RetriedAt == nil || original.RetriedBy != result.NewJobID {
+		t.Errorf("Expected original job to be marked as retried, got %+v", original)
+	}
+
+	if _, err := service.Retry(context.Background(), "job-1"); err == nil {
+		t.Error("Expected error when retrying a job twice")
+	}
+}
+
+func TestRetry_LatestPendingJob(t *testing.T) {
+	retriedAt := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	repo := newRepository(
+		job.Job{ID: "old", CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Failures: []retry.Failure{{Kind: "product", Code: "SKU-1"}}},
+		job.Job{ID: "pending", CreatedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Failures: []retry.Failure{{Kind: "product", Code: "SKU-2"}}},
+		job.Job{ID: "done", CreatedAt: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), RetriedAt: &retriedAt, Failures: []retry.Failure{{Kind: "product", Code: "SKU-3"}}},
+	)
+
+	dispatcher := &MockBus{
+		dispatchFunc: func(ctx context.Context, msg bus.Message) (bus.Response, error) {
+			return bus.Response{}, nil
+		},
+	}
+
+	service := retrying.NewService(repo, dispatcher, retrying.WithBuilder("product", recordsBuilder))
+	result, err := service.Retry(context.Background(), "")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.JobID != "pending" {
+		t.Errorf("Expected latest pending job to be retried, got %s", result.JobID)
+	}
+
+	if result.Resolved != 1 || result.NewJobID != "" {
+		t.Errorf("Expected all failures resolved without a new job, got %+v", result)
+	}
+}
+
+func TestRetry_CommandFailure(t *testing.T) {
+	repo := newRepository(job.Job{ID: "job-1", Failures: []retry.Failure{
+		{Kind: "family", Code: "shoes"},
+		{Kind: "unknown", Code: "x"},
+	}})
+
+	dispatcher := &MockBus{
+		dispatchFunc: func(ctx context.Context, msg bus.Message) (bus.Response, error) {
+			err := errors.New("family not found")
+			return bus.Response{Error: err}, err
+		},
+	}
+
+	builder := func(scope string, codes []string) []bus.Message {
+		return []bus.Message{familyCommand{Code: codes[0]}}
+	}
+
+	service := retrying.NewService(repo, dispatcher, retrying.WithBuilder("family", builder))
+	result, err := service.Retry(context.Background(), "job-1")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Resolved != 0 || len(result.Remaining) != 2 {
+		t.Fatalf("Expected both items to remain, got %+v", result.Remaining)
+	}
+
+	if result.Remaining[0].Code != "shoes" || result.Remaining[0].Error != "family not found" {
+		t.Errorf("Expected failed command item to remain with its error, got %+v", result.Remaining[0])
+	}
+}
+
+func TestRetry_JobNotFound(t *testing.T) {
+	service := retrying.NewService(newRepository(), &MockBus{})
+
+	if _, err := service.Retry(context.Background(), "missing"); err == nil {
+		t.Error("Expected error for unknown job")
+	}
+}
+
+// familyCommand is a fake command targeting a single family
+type familyCommand struct {
+	Code string
+}
+
+func (c familyCommand) Type() bus.Type {
+	return "family.sync"
+}
+
+func (c familyCommand) RetryItem() retry.Failure {
+	return retry.Failure{Kind: "family", Code: c.Code}
+}
+
+// MockManifests is an in-memory manifest repository for testing
+type MockManifests struct {
+	manifests map[string]job.Job
+}
+
+func (m *MockManifests) Write(ctx context.Context, path string, j job.Job) error {
+	m.manifests[path] = j
+	return nil
+}
+
+func (m *MockManifests) Read(ctx context.Context, path string) (job.Job, error) {
+	j, ok := m.manifests[path]
+	if !ok {
+		return job.Job{}, job.ErrNotFound
+	}
+	return j, nil
+}
+
+func TestRetryManifest_ImportsJob(t *testing.T) {
+	manifests := &MockManifests{manifests: map[string]job.Job{
+		"failures.json": {
+			ID:       "job-9",
+			Command:  "reference_entity.sync",
+			Failures: []retry.Failure{{Kind: "record", Scope: "brands", Code: "acme", Error: "missing attribute"}},
+		},
+	}}
+	repo := newRepository()
+
+	var dispatched []syncRecordsCommand
+	dispatcher := &MockBus{
+		dispatchFunc: func(ctx context.Context, msg bus.Message) (bus.Response, error) {
+			dispatched = append(dispatched, msg.(syncRecordsCommand))
+			return bus.Response{Data: syncRecordsResult{}}, nil
+		},
+	}
+
+	service := retrying.NewService(repo, dispatcher,
+		retrying.WithBuilder("record", recordsBuilder),
+		retrying.WithManifests(manifests),
+	)
+
+	result, err := service.RetryManifest(context.Background(), "failures.json")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.JobID != "job-9" || result.Resolved != 1 {
+		t.Errorf("Expected the manifest job to be resolved, got %+v", result)
+	}
+	if len(dispatched) != 1 || dispatched[0].Entity != "brands" || dispatched[0].Records[0] != "acme" {
+		t.Errorf("Expected the manifest items to be retried, got %+v", dispatched)
+	}
+
+	imported, ok := repo.jobs["job-9"]
+	if !ok || imported.RetriedAt == nil {
+		t.Fatalf("Expected the manifest job to be imported and marked as retried, got %+v", imported)
+	}
+
+	if _, err := service.RetryManifest(context.Background(), "failures.json"); err == nil {
+		t.Error("Expected an error when retrying a manifest twice")
+	}
+}
+
+func TestRetryManifest_NotFound(t *testing.T) {
+	service := retrying.NewService(newRepository(), &MockBus{}, retrying.WithManifests(&MockManifests{manifests: map[string]job.Job{}}))
+
+	if _, err := service.RetryManifest(context.Background(), "missing.json"); err == nil {
+		t.Error("Expected error for unknown manifest")
+	}
+}
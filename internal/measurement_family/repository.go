@@ -0,0 +1,22 @@
+package measurement_family
+
+import "context"
+
+// MeasurementFamily represents a measurement family with its units
+type MeasurementFamily map[string]interface{}
+
+// SourceRepository defines read-only operations for measurement families from source
+type SourceRepository interface {
+	// FindAll retrieves all measurement families
+	FindAll(ctx context.Context) ([]MeasurementFamily, error)
+}
+
+// DestRepository defines read and write operations for measurement families in destination
+type DestRepository interface {
+	// FindAll retrieves all measurement families
+	FindAll(ctx context.Context) ([]MeasurementFamily, error)
+
+	// SaveAll creates or updates several measurement families in one call.
+	// It returns the errors of the families that were not written, indexed by code.
+	SaveAll(ctx context.Context, families []MeasurementFamily) (map[string]error, error)
+}
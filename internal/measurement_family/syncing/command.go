@@ -0,0 +1,19 @@
+package syncing
+
+import (
+	"akeneo-migrator/kit/bus"
+)
+
+const SyncMeasurementFamiliesCommandType bus.Type = "measurement_family.sync"
+
+// SyncMeasurementFamiliesCommand represents a command to reconcile measurement families
+type SyncMeasurementFamiliesCommand struct {
+	// Codes limits the sync to some measurement families; all of them are synced when empty
+	Codes []string
+	Debug bool
+}
+
+// Type returns the command type
+func (c SyncMeasurementFamiliesCommand) Type() bus.Type {
+	return SyncMeasurementFamiliesCommandType
+}
@@ -0,0 +1,259 @@
+package syncing
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"akeneo-migrator/internal/measurement_family"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/retry"
+)
+
+// KindMeasurementFamily is the kind of item reported as failure
+const KindMeasurementFamily = "measurement_family"
+
+// Service reconciles the measurement families and units of source with the destination
+type Service struct {
+	sourceRepo measurement_family.SourceRepository
+	destRepo   measurement_family.DestRepository
+}
+
+// NewService creates a new measurement family sync service
+func NewService(sourceRepo measurement_family.SourceRepository, destRepo measurement_family.DestRepository) *Service {
+	return &Service{
+		sourceRepo: sourceRepo,
+		destRepo:   destRepo,
+	}
+}
+
+// SyncOptions contains per-run options of a measurement family sync
+type SyncOptions struct {
+	// Codes limits the sync to some measurement families; all of them are synced when empty
+	Codes []string
+}
+
+// FamilyResult contains the result of one measurement family
+type FamilyResult struct {
+	Code    string
+	Created bool
+	// AddedUnits are the source units written to destination
+	AddedUnits []string
+	// Conflicts are the units kept as they are in destination because they convert differently
+	Conflicts []string
+	Error     string
+}
+
+// SyncResult contains the result of a measurement family reconciliation
+type SyncResult struct {
+	Success  bool
+	Families []FamilyResult
+	// Planned are the writes recorded instead of being sent during a dry run
+	Planned []dryrun.Write
+}
+
+// Failures returns the measurement families that could not be reconciled
+func (r *SyncResult) Failures() []retry.Failure {
+	var failures []retry.Failure
+	for _, family := range r.Families {
+		if family.Error != "" {
+			failures = append(failures, retry.Failure{Kind: KindMeasurementFamily, Code: family.Code, Error: family.Error})
+		}
+	}
+	return failures
+}
+
+// Synced returns the number of measurement families written
+func (r *SyncResult) Synced() int {
+	synced := 0
+	for _, family := range r.Families {
+		if family.Error == "" && (family.Created || len(family.AddedUnits) > 0) {
+			synced++
+		}
+	}
+	return synced
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// Sync creates the measurement families missing in destination and adds the missing units to
+// the existing ones, so metric values of source can be written. Akeneo does not allow changing
+// the standard unit of a family, so a family whose standard unit differs is reported and skipped.
+func (s *Service) Sync(ctx context.Context, opts SyncOptions) (*SyncResult, error) {
+	result := &SyncResult{Families: []FamilyResult{}}
+	ctx, planned := dryrun.Collect(ctx)
+
+	// 1. Get measurement families from both instances
+	sourceFamilies, err := s.sourceRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching measurement families from source: %w", err)
+	}
+
+	destFamilies, err := s.destRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching measurement families from destination: %w", err)
+	}
+
+	sourceByCode := indexByCode(sourceFamilies)
+	destByCode := indexByCode(destFamilies)
+
+	codes := opts.Codes
+	if len(codes) == 0 {
+		codes = make([]string, 0, len(sourceByCode))
+		for code := range sourceByCode {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	// 2. Reconcile each family
+	var payloads []measurement_family.MeasurementFamily
+	positions := make(map[string]int)
+
+	for _, code := range codes {
+		familyResult := FamilyResult{Code: code, AddedUnits: []string{}, Conflicts: []string{}}
+
+		sourceFamily, exists := sourceByCode[code]
+		if !exists {
+			familyResult.Error = "measurement family not found in source"
+			result.Families = append(result.Families, familyResult)
+			continue
+		}
+
+		payload, err := reconcile(sourceFamily, destByCode[code], &familyResult)
+		if err != nil {
+			familyResult.Error = err.Error()
+		} else if payload != nil {
+			positions[code] = len(result.Families)
+			payloads = append(payloads, payload)
+		}
+
+		result.Families = append(result.Families, familyResult)
+	}
+
+	// 3. Save the families that changed in one call; dry runs only record them
+	if len(payloads) > 0 && dryrun.Enabled(ctx) {
+		for _, payload := range payloads {
+			code, _ := payload["code"].(string)
+			dryrun.Record(ctx, dryrun.Write{Kind: KindMeasurementFamily, Code: code, Data: payload})
+		}
+	} else if len(payloads) > 0 {
+		failed, err := s.destRepo.SaveAll(ctx, payloads)
+		if err != nil {
+			return nil, fmt.Errorf("error saving measurement families to destination: %w", err)
+		}
+
+		for code, saveErr := range failed {
+			if position, exists := positions[code]; exists {
+				result.Families[position].Error = saveErr.Error()
+			}
+		}
+	}
+
+	result.Planned = planned()
+	result.Success = len(result.Failures()) == 0
+	return result, nil
+}
+
+// reconcile builds the payload that brings a destination family up to date with the source one.
+// It returns nil when nothing has to be written.
+func reconcile(source, dest measurement_family.MeasurementFamily, result *FamilyResult) (measurement_family.MeasurementFamily, error) {
+	sourceUnits := units(source)
+
+	if dest == nil {
+		payload := make(measurement_family.MeasurementFamily, len(source))
+		for key, value := range source {
+			payload[key] = value
+		}
+
+		result.Created = true
+		result.AddedUnits = sortedKeys(sourceUnits)
+		return payload, nil
+	}
+
+	if source["standard_unit_code"] != dest["standard_unit_code"] {
+		return nil, fmt.Errorf("standard unit is %v in source and %v in destination", source["standard_unit_code"], dest["standard_unit_code"])
+	}
+
+	destUnits := units(dest)
+	merged := make(map[string]interface{}, len(destUnits)+len(sourceUnits))
+	for unitCode, unit := range destUnits {
+		merged[unitCode] = unit
+	}
+
+	for _, unitCode := range sortedKeys(sourceUnits) {
+		destUnit, exists := destUnits[unitCode]
+		if !exists {
+			merged[unitCode] = sourceUnits[unitCode]
+			result.AddedUnits = append(result.AddedUnits, unitCode)
+			continue
+		}
+
+		if !reflect.DeepEqual(conversion(sourceUnits[unitCode]), conversion(destUnit)) {
+			result.Conflicts = append(result.Conflicts, unitCode)
+		}
+	}
+
+	if len(result.AddedUnits) == 0 {
+		return nil, nil
+	}
+
+	return measurement_family.MeasurementFamily{
+		"code":               result.Code,
+		"labels":             mergeLabels(dest["labels"], source["labels"]),
+		"standard_unit_code": dest["standard_unit_code"],
+		"units":              merged,
+	}, nil
+}
+
+// indexByCode indexes measurement families by code
+func indexByCode(families []measurement_family.MeasurementFamily) map[string]measurement_family.MeasurementFamily {
+	index := make(map[string]measurement_family.MeasurementFamily, len(families))
+	for _, family := range families {
+		if code, ok := family["code"].(string); ok {
+			index[code] = family
+		}
+	}
+	return index
+}
+
+// units returns the units of a measurement family, indexed by code
+func units(family measurement_family.MeasurementFamily) map[string]interface{} {
+	result, _ := family["units"].(map[string]interface{})
+	if result == nil {
+		return map[string]interface{}{}
+	}
+	return result
+}
+
+// conversion returns the operations converting a unit from the standard unit
+func conversion(unit interface{}) interface{} {
+	fields, _ := unit.(map[string]interface{})
+	return fields["convert_from_standard"]
+}
+
+// mergeLabels keeps the destination labels and adds or overrides them with the source ones
+func mergeLabels(dest, source interface{}) map[string]interface{} {
+	merged := make(map[string]interface{})
+	for _, labels := range []interface{}{dest, source} {
+		values, _ := labels.(map[string]interface{})
+		for locale, label := range values {
+			merged[locale] = label
+		}
+	}
+	return merged
+}
+
+// sortedKeys returns the keys of a map in alphabetical order
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
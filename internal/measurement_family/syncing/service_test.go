@@ -0,0 +1,148 @@
+package syncing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"akeneo-migrator/internal/measurement_family"
+)
+
+// Mock repositories
+type mockSourceRepo struct {
+	families []measurement_family.MeasurementFamily
+}
+
+func (m *mockSourceRepo) FindAll(ctx context.Context) ([]measurement_family.MeasurementFamily, error) {
+	return m.families, nil
+}
+
+type mockDestRepo struct {
+	families []measurement_family.MeasurementFamily
+	saved    []measurement_family.MeasurementFamily
+	failed   map[string]error
+}
+
+func (m *mockDestRepo) FindAll(ctx context.Context) ([]measurement_family.MeasurementFamily, error) {
+	return m.families, nil
+}
+
+func (m *mockDestRepo) SaveAll(ctx context.Context, families []measurement_family.MeasurementFamily) (map[string]error, error) {
+	m.saved = append(m.saved, families...)
+	return m.failed, nil
+}
+
+func unit(operator, value string) map[string]interface{} {
+	return map[string]interface{}{
+		"convert_from_standard": []interface{}{map[string]interface{}{"operator": operator, "value": value}},
+	}
+}
+
+func TestSync_CreatesFamiliesAndAddsMissingUnits(t *testing.T) {
+	sourceRepo := &mockSourceRepo{families: []measurement_family.MeasurementFamily{
+		{"code": "Length", "standard_unit_code": "METER", "labels": map[string]interface{}{"en_US": "Length"}, "units": map[string]interface{}{
+			"METER":      unit("mul", "1"),
+			"CENTIMETER": unit("mul", "0.01"),
+			"INCH":       unit("mul", "0.0254"),
+		}},
+		{"code": "Weight", "standard_unit_code": "KILOGRAM", "units": map[string]interface{}{
+			"KILOGRAM": unit("mul", "1"),
+		}},
+	}}
+	destRepo := &mockDestRepo{families: []measurement_family.MeasurementFamily{
+		{"code": "Length", "standard_unit_code": "METER", "labels": map[string]interface{}{"fr_FR": "Longueur"}, "units": map[string]interface{}{
+			"METER":      unit("mul", "1"),
+			"CENTIMETER": unit("mul", "0.01"),
+		}},
+	}}
+
+	service := NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background(), SyncOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !result.Success {
+		t.Errorf("Expected success, got %+v", result.Families)
+	}
+
+	length, weight := result.Families[0], result.Families[1]
+	if length.Created || len(length.AddedUnits) != 1 || length.AddedUnits[0] != "INCH" {
+		t.Errorf("Expected INCH added to Length, got %+v", length)
+	}
+	if !weight.Created {
+		t.Errorf("Expected Weight to be created, got %+v", weight)
+	}
+
+	if len(destRepo.saved) != 2 {
+		t.Fatalf("Expected 2 families saved, got %d", len(destRepo.saved))
+	}
+	saved := destRepo.saved[0]
+	if len(saved["units"].(map[string]interface{})) != 3 {
+		t.Errorf("Expected the destination units to be kept, got %v", saved["units"])
+	}
+	if len(saved["labels"].(map[string]interface{})) != 2 {
+		t.Errorf("Expected labels of both instances, got %v", saved["labels"])
+	}
+
+	if result.Synced() != 2 {
+		t.Errorf("Expected 2 families synced, got %d", result.Synced())
+	}
+}
+
+func TestSync_SkipsFamiliesWithAnotherStandardUnit(t *testing.T) {
+	sourceRepo := &mockSourceRepo{families: []measurement_family.MeasurementFamily{
+		{"code": "Length", "standard_unit_code": "METER", "units": map[string]interface{}{"METER": unit("mul", "1")}},
+		{"code": "Weight", "standard_unit_code": "KILOGRAM", "units": map[string]interface{}{"KILOGRAM": unit("mul", "1"), "GRAM": unit("mul", "0.001")}},
+	}}
+	destRepo := &mockDestRepo{families: []measurement_family.MeasurementFamily{
+		{"code": "Length", "standard_unit_code": "CENTIMETER", "units": map[string]interface{}{"CENTIMETER": unit("mul", "1")}},
+		{"code": "Weight", "standard_unit_code": "KILOGRAM", "units": map[string]interface{}{"KILOGRAM": unit("mul", "1"), "GRAM": unit("mul", "0.01")}},
+	}}
+
+	service := NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background(), SyncOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Success {
+		t.Error("Expected success to be false")
+	}
+
+	failures := result.Failures()
+	if len(failures) != 1 || failures[0].Code != "Length" || failures[0].Kind != KindMeasurementFamily {
+		t.Errorf("Expected Length to fail, got %v", failures)
+	}
+
+	weight := result.Families[1]
+	if len(weight.Conflicts) != 1 || weight.Conflicts[0] != "GRAM" {
+		t.Errorf("Expected GRAM conflict, got %+v", weight)
+	}
+	if len(destRepo.saved) != 0 {
+		t.Errorf("Expected nothing saved, got %v", destRepo.saved)
+	}
+}
+
+func TestSync_ReportsRejectedFamilies(t *testing.T) {
+	sourceRepo := &mockSourceRepo{families: []measurement_family.MeasurementFamily{
+		{"code": "Length", "standard_unit_code": "METER", "units": map[string]interface{}{"METER": unit("mul", "1")}},
+		{"code": "Weight", "standard_unit_code": "KILOGRAM", "units": map[string]interface{}{"KILOGRAM": unit("mul", "1")}},
+	}}
+	destRepo := &mockDestRepo{failed: map[string]error{"Weight": errors.New("rejected")}}
+
+	service := NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background(), SyncOptions{Codes: []string{"Weight", "Volume"}})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(destRepo.saved) != 1 {
+		t.Errorf("Expected only Weight to be saved, got %v", destRepo.saved)
+	}
+
+	failures := result.Failures()
+	if len(failures) != 2 || failures[0].Code != "Volume" || failures[1].Code != "Weight" {
+		t.Errorf("Expected Volume and Weight to fail, got %v", failures)
+	}
+}
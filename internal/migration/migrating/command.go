@@ -0,0 +1,23 @@
+package migrating
+
+import "akeneo-migrator/kit/bus"
+
+const MigrateCommandType bus.Type = "migration.migrate"
+
+// MigrateCommand represents a command to migrate the whole catalog
+type MigrateCommand struct {
+	// Resume skips the phases completed by the previous, unfinished migration
+	Resume bool
+	// AutoDeps lets channels activate the destination locales they use
+	AutoDeps bool
+	// ValuesOnly only writes the values of the products and models existing in destination
+	ValuesOnly bool
+	// Progress is called when each phase starts; it may be nil
+	Progress ProgressFunc
+	Debug    bool
+}
+
+// Type returns the command type
+func (c MigrateCommand) Type() bus.Type {
+	return MigrateCommandType
+}
@@ -0,0 +1,40 @@
+package migrating
+
+import (
+	"context"
+
+	"akeneo-migrator/kit/bus"
+)
+
+// CommandHandler handles MigrateCommand
+type CommandHandler struct {
+	service *Service
+}
+
+// NewCommandHandler creates a new command handler
+func NewCommandHandler(service *Service) *CommandHandler {
+	return &CommandHandler{
+		service: service,
+	}
+}
+
+// Handle executes the migrate command
+func (h *CommandHandler) Handle(ctx context.Context, msg bus.Message) (bus.Response, error) {
+	cmd, ok := msg.(MigrateCommand)
+	if !ok {
+		return bus.Response{}, nil
+	}
+
+	result, err := h.service.Migrate(ctx, MigrateOptions{
+		Resume:     cmd.Resume,
+		AutoDeps:   cmd.AutoDeps,
+		ValuesOnly: cmd.ValuesOnly,
+		Progress:   cmd.Progress,
+		Debug:      cmd.Debug,
+	})
+	if err != nil {
+		return bus.Response{Error: err}, err
+	}
+
+	return bus.Response{Data: result}, nil
+}
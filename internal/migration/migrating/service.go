@@ -0,0 +1,262 @@
+package migrating
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"akeneo-migrator/internal/migration"
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/retry"
+	"akeneo-migrator/kit/session"
+)
+
+// Phase is a stage of the migration, each one depending on the items written by the phases before it
+type Phase string
+
+const (
+	// PhaseStructure migrates the catalog structure, reference entities and their records included
+	PhaseStructure Phase = "structure"
+	// PhaseAssets migrates the asset families with their assets
+	PhaseAssets Phase = "assets"
+	// PhaseProducts migrates the product models and products, hierarchy by hierarchy
+	PhaseProducts Phase = "products"
+)
+
+// Order is the order in which the phases run: assets use the structure, and products use both
+// the structure and the assets they link to.
+var Order = []Phase{
+	PhaseStructure,
+	PhaseAssets,
+	PhaseProducts,
+}
+
+// Builder creates the commands of a phase, listing the items to migrate in source when needed
+type Builder func(ctx context.Context, opts MigrateOptions) ([]bus.Message, error)
+
+// ProgressFunc is called when a phase starts, with the number of commands it dispatches
+type ProgressFunc func(phase Phase, commands int)
+
+// stopper is implemented by the results of commands that can stop before their end, like
+// sync-structure, which then fail as a whole
+type stopper interface {
+	Err() error
+}
+
+// Service migrates a whole catalog by dispatching the commands of each phase in order, saving a
+// checkpoint after each phase
+type Service struct {
+	dispatcher  bus.Bus
+	checkpoints migration.CheckpointRepository
+	builders    map[Phase]Builder
+	now         func() time.Time
+}
+
+// Option configures the migration service
+type Option func(*Service)
+
+// WithPhase registers how the commands of a phase are created. Phases without a builder are
+// skipped, e.g. assets on editions without Asset Manager.
+func WithPhase(phase Phase, builder Builder) Option {
+	return func(s *Service) {
+		s.builders[phase] = builder
+	}
+}
+
+// NewService creates a new instance of the migration service
+func NewService(dispatcher bus.Bus, checkpoints migration.CheckpointRepository, opts ...Option) *Service {
+	service := &Service{
+		dispatcher:  dispatcher,
+		checkpoints: checkpoints,
+		builders:    make(map[Phase]Builder),
+		now:         time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
+
+// MigrateOptions contains the options of a migration
+type MigrateOptions struct {
+	// Resume skips the phases completed by the previous, unfinished migration
+	Resume bool
+	// AutoDeps lets channels activate the destination locales they use
+	AutoDeps bool
+	// ValuesOnly only writes the values of the products and models existing in destination
+	ValuesOnly bool
+	// Progress is called when each phase starts; it may be nil
+	Progress ProgressFunc
+	Debug    bool
+}
+
+// SyncResult contains the consolidated result of a migration, including the phases completed
+// by previous runs when it was resumed
+type SyncResult struct {
+	StartedAt time.Time
+	Phases    []migration.PhaseResult
+	// Stopped is the phase that failed as a whole or was interrupted; the phases after it did not run
+	Stopped Phase
+	// Finished is set once every phase of the migration has been completed
+	Finished bool
+	// Planned are the writes recorded instead of being sent during a dry run
+	Planned []dryrun.Write
+}
+
+// Synced returns the number of items written by the phases run this time
+func (r *SyncResult) Synced() int {
+	synced := 0
+	for _, phase := range r.Phases {
+		if !phase.Resumed {
+			synced += phase.Synced
+		}
+	}
+	return synced
+}
+
+// Failures returns the items that could not be migrated by the phases run this time; the failures
+// of resumed phases were queued by the run that completed them
+func (r *SyncResult) Failures() []retry.Failure {
+	var failures []retry.Failure
+	for _, phase := range r.Phases {
+		if !phase.Resumed {
+			failures = append(failures, phase.Failures...)
+		}
+	}
+	return failures
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// Migrate runs every phase in order. Items failing in a phase are collected and the migration goes
+// on, but a phase failing as a whole or interrupted stops it, since the phases after it depend on
+// its items. The checkpoint is saved after each completed phase, so the migration can be resumed;
+// dry runs neither read nor save it.
+func (s *Service) Migrate(ctx context.Context, opts MigrateOptions) (*SyncResult, error) {
+	ctx, planned := dryrun.Collect(ctx)
+	dryRun := dryrun.Enabled(ctx)
+
+	checkpoint := migration.New(s.now())
+	if opts.Resume && !dryRun {
+		previous, err := s.checkpoints.Find(ctx)
+		if err != nil && !errors.Is(err, migration.ErrNotFound) {
+			return nil, err
+		}
+		// A finished migration is not resumed, the catalog is migrated again
+		if err == nil && !previous.Finished() {
+			checkpoint = previous
+		}
+	}
+
+	result := &SyncResult{StartedAt: checkpoint.StartedAt}
+
+	// Failures are collected by the migration itself instead of being queued by each command
+	phaseCtx := retry.WithoutRecording(ctx)
+
+	for _, phase := range Order {
+		if completed, ok := checkpoint.Completed(string(phase)); ok {
+			completed.Resumed = true
+			result.Phases = append(result.Phases, completed)
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			result.Phases = append(result.Phases, migration.PhaseResult{Phase: string(phase), Error: "interrupted: " + err.Error()})
+			result.Stopped = phase
+			break
+		}
+
+		phaseResult, stop := s.runPhase(phaseCtx, phase, opts)
+		result.Phases = append(result.Phases, phaseResult)
+		if stop {
+			result.Stopped = phase
+			break
+		}
+
+		checkpoint.Phases = append(checkpoint.Phases, phaseResult)
+		if !dryRun {
+			if err := s.checkpoints.Save(ctx, checkpoint); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if result.Stopped == "" {
+		finishedAt := s.now().UTC()
+		checkpoint.FinishedAt = &finishedAt
+		result.Finished = true
+		if !dryRun {
+			if err := s.checkpoints.Save(ctx, checkpoint); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result.Planned = planned()
+	return result, nil
+}
+
+// runPhase dispatches the commands of a phase and reports whether the migration must stop
+func (s *Service) runPhase(ctx context.Context, phase Phase, opts MigrateOptions) (migration.PhaseResult, bool) {
+	phaseResult := migration.PhaseResult{Phase: string(phase)}
+	startedAt := s.now()
+	finish := func(stop bool) (migration.PhaseResult, bool) {
+		phaseResult.FinishedAt = s.now().UTC()
+		phaseResult.Duration = phaseResult.FinishedAt.Sub(startedAt)
+		return phaseResult, stop
+	}
+
+	builder, ok := s.builders[phase]
+	if !ok {
+		phaseResult.Skipped = true
+		return finish(false)
+	}
+
+	messages, err := builder(ctx, opts)
+	if err != nil {
+		phaseResult.Error = fmt.Sprintf("error listing the items of the %s phase: %v", phase, err)
+		return finish(true)
+	}
+
+	phaseResult.Commands = len(messages)
+	if opts.Progress != nil {
+		opts.Progress(phase, len(messages))
+	}
+
+	for _, msg := range messages {
+		if err := ctx.Err(); err != nil {
+			phaseResult.Error = "interrupted: " + err.Error()
+			return finish(true)
+		}
+
+		response, dispatchErr := s.dispatcher.Dispatch(ctx, msg)
+		if counter, ok := response.Data.(session.Counter); ok {
+			phaseResult.Synced += counter.Synced()
+		}
+
+		failures := retry.Collect(msg, response, dispatchErr)
+		phaseResult.Failed += len(failures)
+		phaseResult.Failures = append(phaseResult.Failures, failures...)
+
+		// A command that does not target a single item failed as a whole
+		if dispatchErr != nil && len(failures) == 0 {
+			phaseResult.Error = dispatchErr.Error()
+			return finish(true)
+		}
+		if stopped, ok := response.Data.(stopper); ok {
+			if err := stopped.Err(); err != nil {
+				phaseResult.Error = err.Error()
+				return finish(true)
+			}
+		}
+	}
+
+	return finish(false)
+}
@@ -0,0 +1,208 @@
+package migrating_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"akeneo-migrator/internal/migration"
+	"akeneo-migrator/internal/migration/migrating"
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/retry"
+)
+
+// syncCommand is a fake command migrating one kind of item
+type syncCommand struct {
+	Kind string
+	Code string
+}
+
+func (c syncCommand) Type() bus.Type {
+	return bus.Type(c.Kind + ".sync")
+}
+
+// syncResult reports the items written and the ones that failed
+type syncResult struct {
+	synced   int
+	failures []retry.Failure
+	err      error
+}
+
+func (r syncResult) Synced() int {
+	return r.synced
+}
+
+func (r syncResult) Failures() []retry.Failure {
+	return r.failures
+}
+
+func (r syncResult) Err() error {
+	return r.err
+}
+
+// MockBus dispatches messages to a single function
+type MockBus struct {
+	dispatchFunc func(ctx context.Context, msg bus.Message) (bus.Response, error)
+}
+
+func (m *MockBus) Dispatch(ctx context.Context, msg bus.Message) (bus.Response, error) {
+	return m.dispatchFunc(ctx, msg)
+}
+
+func (m *MockBus) Register(msgType bus.Type, handler bus.Handler) {}
+
+// MockCheckpoints keeps the checkpoint in memory
+type MockCheckpoints struct {
+	checkpoint *migration.Checkpoint
+	saves      int
+}
+
+func (m *MockCheckpoints) Find(ctx context.Context) (migration.Checkpoint, error) {
+	if m.checkpoint == nil {
+		return migration.Checkpoint{}, migration.ErrNotFound
+	}
+	return *m.checkpoint, nil
+}
+
+func (m *MockCheckpoints) Save(ctx context.Context, checkpoint migration.Checkpoint) error {
+	m.checkpoint = &checkpoint
+	m.saves++
+	return nil
+}
+
+// commands builds a phase dispatching one command per code
+func commands(kind string, codes ...string) migrating.Builder {
+	return func(ctx context.Context, opts migrating.MigrateOptions) ([]bus.Message, error) {
+		messages := make([]bus.Message, 0, len(codes))
+		for _, code := range codes {
+			messages = append(messages, syncCommand{Kind: kind, Code: code})
+		}
+		return messages, nil
+	}
+}
+
+func TestMigrate_RunsPhasesInOrderAndSavesCheckpoints(t *testing.T) {
+	var dispatched []string
+	dispatcher := &MockBus{dispatchFunc: func(ctx context.Context, msg bus.Message) (bus.Response, error) {
+		if !retry.RecordingDisabled(ctx) {
+			t.Error("Expected failures of nested commands not to be queued")
+		}
+		cmd := msg.(syncCommand)
+		dispatched = append(dispatched, cmd.Kind+":"+cmd.Code)
+
+		if cmd.Code == "packshots" {
+			return bus.Response{Data: syncResult{synced: 3, failures: []retry.Failure{{Kind: "asset", Code: "front", Error: "invalid media"}}}}, nil
+		}
+		return bus.Response{Data: syncResult{synced: 1}}, nil
+	}}
+	checkpoints := &MockCheckpoints{}
+
+	// Registered out of order on purpose
+	service := migrating.NewService(dispatcher, checkpoints,
+		migrating.WithPhase(migrating.PhaseProducts, commands("product", "all")),
+		migrating.WithPhase(migrating.PhaseAssets, commands("asset_family", "packshots", "notices")),
+		migrating.WithPhase(migrating.PhaseStructure, commands("structure", "all")),
+	)
+
+	result, err := service.Migrate(context.Background(), migrating.MigrateOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{"structure:all", "asset_family:packshots", "asset_family:notices", "product:all"}
+	if len(dispatched) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, dispatched)
+	}
+	for i := range expected {
+		if dispatched[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, dispatched)
+			break
+		}
+	}
+
+	if !result.Finished || result.Synced() != 6 || len(result.Failures()) != 1 || result.Failures()[0].Code != "front" {
+		t.Errorf("Expected a finished migration with 6 synced items and front to fail, got %+v", result)
+	}
+	if checkpoints.saves != 4 || !checkpoints.checkpoint.Finished() || len(checkpoints.checkpoint.Phases) != 3 {
+		t.Errorf("Expected a checkpoint per phase and a finished checkpoint, got %d saves %+v", checkpoints.saves, checkpoints.checkpoint)
+	}
+}
+
+func TestMigrate_ResumesAfterStoppedPhase(t *testing.T) {
+	productsFail := true
+	var dispatched []string
+	dispatcher := &MockBus{dispatchFunc: func(ctx context.Context, msg bus.Message) (bus.Response, error) {
+		cmd := msg.(syncCommand)
+		dispatched = append(dispatched, cmd.Kind)
+		if cmd.Kind == "product" && productsFail {
+			return bus.Response{}, errors.New("source unavailable")
+		}
+		return bus.Response{Data: syncResult{synced: 1}}, nil
+	}}
+	checkpoints := &MockCheckpoints{}
+
+	service := migrating.NewService(dispatcher, checkpoints,
+		migrating.WithPhase(migrating.PhaseStructure, commands("structure", "all")),
+		migrating.WithPhase(migrating.PhaseProducts, commands("product", "all")),
+	)
+
+	result, err := service.Migrate(context.Background(), migrating.MigrateOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Stopped != migrating.PhaseProducts || result.Finished || checkpoints.checkpoint.Finished() {
+		t.Fatalf("Expected the migration to stop at products, got %+v", result)
+	}
+	if !result.Phases[1].Skipped {
+		t.Errorf("Expected assets to be skipped, got %+v", result.Phases[1])
+	}
+
+	productsFail = false
+	dispatched = nil
+	result, err = service.Migrate(context.Background(), migrating.MigrateOptions{Resume: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(dispatched) != 1 || dispatched[0] != "product" {
+		t.Errorf("Expected only products to run again, got %v", dispatched)
+	}
+	if !result.Finished || !result.Phases[0].Resumed || result.Phases[0].Synced != 1 || result.Synced() != 1 {
+		t.Errorf("Expected the structure phase to be resumed from the checkpoint, got %+v", result)
+	}
+
+	// A finished migration starts over
+	dispatched = nil
+	if _, err := service.Migrate(context.Background(), migrating.MigrateOptions{Resume: true}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(dispatched) != 2 {
+		t.Errorf("Expected every phase to run again, got %v", dispatched)
+	}
+}
+
+func TestMigrate_StopsWhenCommandStops(t *testing.T) {
+	dispatcher := &MockBus{dispatchFunc: func(ctx context.Context, msg bus.Message) (bus.Response, error) {
+		if msg.(syncCommand).Kind != "structure" {
+			t.Errorf("Expected nothing to run after the structure, got %v", msg)
+		}
+		return bus.Response{Data: syncResult{synced: 2, err: errors.New("attributes: forbidden")}}, nil
+	}}
+	checkpoints := &MockCheckpoints{}
+
+	service := migrating.NewService(dispatcher, checkpoints,
+		migrating.WithPhase(migrating.PhaseStructure, commands("structure", "all")),
+		migrating.WithPhase(migrating.PhaseAssets, commands("asset_family", "packshots")),
+	)
+
+	result, err := service.Migrate(context.Background(), migrating.MigrateOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Stopped != migrating.PhaseStructure || result.Phases[0].Error != "attributes: forbidden" {
+		t.Errorf("Expected the migration to stop at the structure, got %+v", result)
+	}
+	if checkpoints.checkpoint != nil {
+		t.Errorf("Expected no checkpoint to be saved, got %+v", checkpoints.checkpoint)
+	}
+}
@@ -0,0 +1,70 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"akeneo-migrator/kit/retry"
+)
+
+// ErrNotFound is returned when no migration has been started for the destination
+var ErrNotFound = errors.New("checkpoint not found")
+
+// PhaseResult is the outcome of a phase of a migration
+type PhaseResult struct {
+	Phase string `json:"phase"`
+	// Skipped is set when the phase does not apply, e.g. assets on instances without Asset Manager
+	Skipped bool `json:"skipped,omitempty"`
+	// Resumed is set when the phase was completed by a previous run and did not run again
+	Resumed  bool            `json:"resumed,omitempty"`
+	Commands int             `json:"commands"`
+	Synced   int             `json:"synced"`
+	Failed   int             `json:"failed"`
+	Failures []retry.Failure `json:"failures,omitempty"`
+	// Error is set when the phase could not run as a whole, which stops the migration
+	Error      string        `json:"error,omitempty"`
+	Duration   time.Duration `json:"duration"`
+	FinishedAt time.Time     `json:"finishedAt"`
+}
+
+// Checkpoint records the phases of a migration completed so far, so an interrupted migration
+// can be resumed where it stopped
+type Checkpoint struct {
+	StartedAt time.Time `json:"startedAt"`
+	// FinishedAt is set once every phase has been completed
+	FinishedAt *time.Time    `json:"finishedAt,omitempty"`
+	Phases     []PhaseResult `json:"phases"`
+}
+
+// New starts the checkpoint of a migration
+func New(now time.Time) Checkpoint {
+	return Checkpoint{
+		StartedAt: now.UTC(),
+		Phases:    make([]PhaseResult, 0),
+	}
+}
+
+// Completed returns the result of a phase completed by the migration
+func (c Checkpoint) Completed(phase string) (PhaseResult, bool) {
+	for _, result := range c.Phases {
+		if result.Phase == phase {
+			return result, true
+		}
+	}
+	return PhaseResult{}, false
+}
+
+// Finished reports whether every phase of the migration has been completed
+func (c Checkpoint) Finished() bool {
+	return c.FinishedAt != nil
+}
+
+// CheckpointRepository persists the checkpoint of the migration to a destination
+type CheckpointRepository interface {
+	// Find retrieves the checkpoint, returning ErrNotFound when no migration has been started
+	Find(ctx context.Context) (Checkpoint, error)
+
+	// Save creates or replaces the checkpoint
+	Save(ctx context.Context, checkpoint Checkpoint) error
+}
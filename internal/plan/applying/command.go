@@ -0,0 +1,16 @@
+package applying
+
+import "akeneo-migrator/kit/bus"
+
+const ApplyPlanCommandType bus.Type = "plan.apply"
+
+// ApplyPlanCommand represents a command to send the writes of a plan file to destination
+type ApplyPlanCommand struct {
+	// Path is the plan file written by the plan command
+	Path string
+}
+
+// Type returns the command type
+func (c ApplyPlanCommand) Type() bus.Type {
+	return ApplyPlanCommandType
+}
@@ -0,0 +1,143 @@
+package applying
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"akeneo-migrator/internal/plan"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/retry"
+)
+
+// Writer sends one planned write to destination
+type Writer func(ctx context.Context, write dryrun.Write) error
+
+// Service sends the writes of a plan to destination, exactly as they were planned
+type Service struct {
+	repo        plan.Repository
+	writers     map[string]Writer
+	destination string
+}
+
+// Option configures the apply service
+type Option func(*Service)
+
+// WithWriter registers how planned writes of a kind are sent to destination
+func WithWriter(kind string, writer Writer) Option {
+	return func(s *Service) {
+		s.writers[kind] = writer
+	}
+}
+
+// WithDestination refuses the plans computed for another destination host
+func WithDestination(host string) Option {
+	return func(s *Service) {
+		s.destination = host
+	}
+}
+
+// NewService creates a new instance of the apply service
+func NewService(repo plan.Repository, opts ...Option) *Service {
+	service := &Service{
+		repo:    repo,
+		writers: make(map[string]Writer),
+	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
+
+// ApplyResult contains the result of applying a plan
+type ApplyResult struct {
+	PlanID  string
+	Command string
+	// Writes is the number of writes in the plan
+	Writes  int
+	Applied int
+	Failed  []retry.Failure
+	// Planned are the writes recorded instead of being sent during a dry run
+	Planned []dryrun.Write
+}
+
+// Synced returns the number of writes sent to destination
+func (r *ApplyResult) Synced() int {
+	return r.Applied
+}
+
+// Failures returns the writes that could not be applied, so they can be retried from source
+func (r *ApplyResult) Failures() []retry.Failure {
+	return r.Failed
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *ApplyResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// Apply sends the writes of the plan stored in path to destination, in the order they were planned.
+// A write that fails does not stop the others; it is reported in the result.
+func (s *Service) Apply(ctx context.Context, path string) (*ApplyResult, error) {
+	ctx, planned := dryrun.Collect(ctx)
+
+	p, err := s.repo.Find(ctx, path)
+	if errors.Is(err, plan.ErrNotFound) {
+		return nil, fmt.Errorf("plan %s not found", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.destination != "" && p.Destination != s.destination {
+		return nil, fmt.Errorf("plan %s was computed for destination %s, not %s", p.ID, p.Destination, s.destination)
+	}
+
+	result := &ApplyResult{
+		PlanID:  p.ID,
+		Command: p.Command,
+		Writes:  len(p.Writes),
+	}
+
+	for _, write := range p.Writes {
+		writer, ok := s.writers[write.Kind]
+		if !ok {
+			result.Failed = append(result.Failed, failure(write, fmt.Errorf("writes of kind '%s' cannot be applied, run the sync instead", write.Kind)))
+			continue
+		}
+
+		if dryrun.Record(ctx, write) {
+			result.Applied++
+			continue
+		}
+
+		if err := writer(ctx, write); err != nil {
+			result.Failed = append(result.Failed, failure(write, err))
+			continue
+		}
+		result.Applied++
+	}
+
+	result.Planned = planned()
+	return result, nil
+}
+
+// Payload returns the data of a planned write as the JSON object it was decoded from
+func Payload(write dryrun.Write) (map[string]interface{}, error) {
+	data, ok := write.Data.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s '%s' has no payload in the plan", write.Kind, write.Code)
+	}
+	return data, nil
+}
+
+func failure(write dryrun.Write, err error) retry.Failure {
+	return retry.Failure{
+		Kind:  write.Kind,
+		Scope: write.Scope,
+		Code:  write.Code,
+		Error: err.Error(),
+	}
+}
@@ -0,0 +1,152 @@
+package applying_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"akeneo-migrator/internal/plan"
+	"akeneo-migrator/internal/plan/applying"
+	"akeneo-migrator/kit/dryrun"
+)
+
+// MockRepository is an in-memory plan repository for testing
+type MockRepository struct {
+	plans map[string]plan.Plan
+}
+
+func (m *MockRepository) Save(ctx context.Context, path string, p plan.Plan) error {
+	m.plans[path] = p
+	return nil
+}
+
+func (m *MockRepository) Find(ctx context.Context, path string) (plan.Plan, error) {
+	p, ok := m.plans[path]
+	if !ok {
+		return plan.Plan{}, plan.ErrNotFound
+	}
+	return p, nil
+}
+
+func newRepository(p plan.Plan) *MockRepository {
+	return &MockRepository{plans: map[string]plan.Plan{"plan.json": p}}
+}
+
+func TestApply_SendsWritesInOrder(t *testing.T) {
+	repo := newRepository(plan.New("run-1", "sync-family shoes", "https://dest.example.com", []dryrun.Write{
+		{Kind: "family", Code: "shoes", Data: map[string]interface{}{"code": "shoes"}},
+		{Kind: "family_variant", Scope: "shoes", Code: "by_size", Data: map[string]interface{}{"code": "by_size"}},
+	}, time.Now()))
+
+	var sent []string
+	record := func(ctx context.Context, write dryrun.Write) error {
+		if _, err := applying.Payload(write); err != nil {
+			return err
+		}
+		sent = append(sent, write.Kind+":"+write.Code)
+		return nil
+	}
+
+	service := applying.NewService(repo,
+		applying.WithDestination("https://dest.example.com"),
+		applying.WithWriter("family", record),
+		applying.WithWriter("family_variant", record),
+	)
+
+	result, err := service.Apply(context.Background(), "plan.json")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.PlanID != "run-1" || result.Writes != 2 || result.Applied != 2 || len(result.Failed) != 0 {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+	if len(sent) != 2 || sent[0] != "family:shoes" || sent[1] != "family_variant:by_size" {
+		t.Errorf("Expected the writes in plan order, got %v", sent)
+	}
+}
+
+func TestApply_ReportsFailedWrites(t *testing.T) {
+	repo := newRepository(plan.New("run-1", "sync-product SKU-1", "", []dryrun.Write{
+		{Kind: "product", Code: "SKU-1", Data: map[string]interface{}{"identifier": "SKU-1"}},
+		{Kind: "media_file", Scope: "SKU-1", Code: "a/b/c/photo.jpg"},
+		{Kind: "product", Code: "SKU-2", Data: map[string]interface{}{"identifier": "SKU-2"}},
+	}, time.Now()))
+
+	service := applying.NewService(repo, applying.WithWriter("product", func(ctx context.Context, write dryrun.Write) error {
+		if write.Code == "SKU-1" {
+			return errors.New("unknown attribute")
+		}
+		return nil
+	}))
+
+	result, err := service.Apply(context.Background(), "plan.json")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Applied != 1 {
+		t.Errorf("Expected 1 applied write, got %d", result.Applied)
+	}
+	failures := result.Failures()
+	if len(failures) != 2 {
+		t.Fatalf("Expected 2 failures, got %+v", failures)
+	}
+	if failures[0].Code != "SKU-1" || failures[0].Error != "unknown attribute" {
+		t.Errorf("Unexpected product failure: %+v", failures[0])
+	}
+	if failures[1].Kind != "media_file" || failures[1].Scope != "SKU-1" {
+		t.Errorf("Expected the media file without writer to fail, got %+v", failures[1])
+	}
+}
+
+func TestApply_RefusesPlanOfAnotherDestination(t *testing.T) {
+	repo := newRepository(plan.New("run-1", "sync-channel ecommerce", "https://staging.example.com", []dryrun.Write{
+		{Kind: "channel", Code: "ecommerce", Data: map[string]interface{}{"code": "ecommerce"}},
+	}, time.Now()))
+
+	called := false
+	service := applying.NewService(repo,
+		applying.WithDestination("https://prod.example.com"),
+		applying.WithWriter("channel", func(ctx context.Context, write dryrun.Write) error {
+			called = true
+			return nil
+		}),
+	)
+
+	if _, err := service.Apply(context.Background(), "plan.json"); err == nil {
+		t.Fatal("Expected an error for a plan computed for another destination")
+	}
+	if called {
+		t.Error("Expected nothing to be written")
+	}
+}
+
+func TestApply_PlanNotFound(t *testing.T) {
+	service := applying.NewService(&MockRepository{plans: map[string]plan.Plan{}})
+
+	if _, err := service.Apply(context.Background(), "missing.json"); err == nil {
+		t.Fatal("Expected an error for a missing plan")
+	}
+}
+
+func TestApply_DryRunOnlyRecordsWrites(t *testing.T) {
+	repo := newRepository(plan.New("run-1", "sync-attribute color", "", []dryrun.Write{
+		{Kind: "attribute", Code: "color", Data: map[string]interface{}{"code": "color"}},
+	}, time.Now()))
+
+	service := applying.NewService(repo, applying.WithWriter("attribute", func(ctx context.Context, write dryrun.Write) error {
+		t.Error("Expected no write during a dry run")
+		return nil
+	}))
+
+	result, err := service.Apply(dryrun.With(context.Background()), "plan.json")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.PlannedWrites()) != 1 || result.PlannedWrites()[0].Code != "color" {
+		t.Errorf("Expected the write to be recorded, got %+v", result.PlannedWrites())
+	}
+}
@@ -0,0 +1,57 @@
+package plan
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"akeneo-migrator/kit/dryrun"
+)
+
+// ErrNotFound is returned when a plan file does not exist
+var ErrNotFound = errors.New("plan not found")
+
+// Plan is the list of writes computed by a dry run, saved to be reviewed then applied as is
+type Plan struct {
+	// ID is the run ID of the invocation that computed the plan
+	ID string `json:"id"`
+	// Command is the command line whose writes were planned, e.g. "sync-product COMMON-001"
+	Command string `json:"command"`
+	// Destination is the host the writes are meant for; the plan is only applied to it
+	Destination string         `json:"destination"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	Writes      []dryrun.Write `json:"writes"`
+}
+
+// New creates a plan for the writes planned by a command
+func New(id, command, destination string, writes []dryrun.Write, now time.Time) Plan {
+	if writes == nil {
+		writes = []dryrun.Write{}
+	}
+
+	return Plan{
+		ID:          id,
+		Command:     command,
+		Destination: destination,
+		CreatedAt:   now.UTC(),
+		Writes:      writes,
+	}
+}
+
+// Counts returns the number of planned writes by kind
+func (p Plan) Counts() map[string]int {
+	counts := make(map[string]int)
+	for _, write := range p.Writes {
+		counts[write.Kind]++
+	}
+	return counts
+}
+
+// Repository persists plans in files chosen by the user
+type Repository interface {
+	// Save writes a plan to path, replacing the file if it exists
+	Save(ctx context.Context, path string, plan Plan) error
+
+	// Find reads the plan stored in path, returning ErrNotFound when the file does not exist
+	Find(ctx context.Context, path string) (Plan, error)
+}
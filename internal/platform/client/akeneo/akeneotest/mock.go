@@ -0,0 +1,741 @@
+package akeneotest
+
+import (
+	"context"
+	"fmt"
+
+	"akeneo-migrator/internal/platform/client/akeneo"
+)
+
+// MockAPI is an akeneo.API whose operations are set per test. Calling an operation
+// without a function returns an error naming it, so unexpected calls fail loudly.
+type MockAPI struct {
+	GetReferenceEntitiesFunc             func(context.Context) ([]akeneo.ReferenceEntity, error)
+	GetReferenceEntityFunc               func(context.Context, string) (akeneo.ReferenceEntity, error)
+	PatchReferenceEntityFunc             func(context.Context, string, akeneo.ReferenceEntity) error
+	GetReferenceEntityAttributesFunc     func(context.Context, string) ([]akeneo.ReferenceEntityAttribute, error)
+	PatchReferenceEntityAttributeFunc    func(context.Context, string, string, akeneo.ReferenceEntityAttribute) error
+	GetReferenceEntityRecordsFunc        func(context.Context, string) ([]akeneo.ReferenceEntityRecord, error)
+	StreamReferenceEntityRecordsFunc     func(context.Context, string, int, func([]akeneo.ReferenceEntityRecord) error) error
+	GetReferenceEntityRecordFunc         func(context.Context, string, string) (akeneo.ReferenceEntityRecord, error)
+	PatchReferenceEntityRecordFunc       func(context.Context, string, string, akeneo.ReferenceEntityRecord) error
+	PatchReferenceEntityRecordsFunc      func(context.Context, string, []akeneo.ReferenceEntityRecord) (map[string]error, error)
+	DeleteReferenceEntityRecordFunc      func(context.Context, string, string) error
+	DownloadReferenceEntityMediaFileFunc func(context.Context, string) ([]byte, error)
+	UploadReferenceEntityMediaFileFunc   func(context.Context, string, []byte) (string, error)
+	GetProductFunc                       func(context.Context, string) (akeneo.Product, error)
+	PatchProductFunc                     func(context.Context, string, akeneo.Product) error
+	PatchProductsFunc                    func(context.Context, []akeneo.Product) (map[string]error, error)
+	GetProductModelFunc                  func(context.Context, string) (akeneo.ProductModel, error)
+	PatchProductModelFunc                func(context.Context, string, akeneo.ProductModel) error
+	PatchProductModelsFunc               func(context.Context, []akeneo.ProductModel) (map[string]error, error)
+	GetProductsByParentFunc              func(context.Context, string) ([]akeneo.Product, error)
+	GetProductModelsByParentFunc         func(context.Context, string) ([]akeneo.ProductModel, error)
+	GetProductIdentifiersByCategoryFunc  func(context.Context, string) ([]string, error)
+	GetProductsUpdatedSinceFunc          func(context.Context, string) ([]akeneo.Product, error)
+	GetProductModelsUpdatedSinceFunc     func(context.Context, string) ([]akeneo.ProductModel, error)
+	StreamProductsUpdatedSinceFunc       func(context.Context, string, string, int, func([]akeneo.Product) error) error
+	StreamProductsBySearchFunc           func(context.Context, string, int, func([]akeneo.Product) error) error
+	StreamProductModelsUpdatedSinceFunc  func(context.Context, string, string, int, func([]akeneo.ProductModel) error) error
+	DownloadMediaFileFunc                func(context.Context, string) ([]byte, error)
+	UploadMediaFileFunc                  func(context.Context, akeneo.MediaFileTarget, string, []byte) (string, error)
+	GetIdentifierAttributeFunc           func(context.Context) (string, error)
+	GetProductByUUIDFunc                 func(context.Context, string) (akeneo.Product, error)
+	GetProductUUIDsFunc                  func(context.Context, string, []string) (map[string]string, error)
+	PatchProductByUUIDFunc               func(context.Context, string, akeneo.Product) error
+	PatchProductsByUUIDFunc              func(context.Context, []akeneo.Product) (map[string]error, error)
+	GetPublishedProductFunc              func(context.Context, string) (akeneo.PublishedProduct, error)
+	StreamPublishedProductsFunc          func(context.Context, int, func([]akeneo.PublishedProduct) error) error
+	GetSystemInformationFunc             func(context.Context) (*akeneo.SystemInformation, error)
+	CountItemsFunc                       func(context.Context, string) (int, error)
+	CountMatchingFunc                    func(context.Context, string, string) (int, error)
+	CountUpdatedSinceFunc                func(context.Context, string, string, string) (int, error)
+	GetAttributesFunc                    func(context.Context, int, int) ([]akeneo.Attribute, bool, error)
+	GetAttributeFunc                     func(context.Context, string) (akeneo.Attribute, error)
+	PatchAttributeFunc                   func(context.Context, string, akeneo.Attribute) error
+	GetAttributeOptionsFunc              func(context.Context, string) ([]akeneo.AttributeOption, error)
+	PatchAttributeOptionFunc             func(context.Context, string, string, akeneo.AttributeOption) error
+	GetAttributeGroupFunc                func(context.Context, string) (akeneo.AttributeGroup, error)
+	PatchAttributeGroupFunc              func(context.Context, string, akeneo.AttributeGroup) error
+	GetAssociationTypesFunc              func(context.Context) ([]akeneo.AssociationType, error)
+	GetAssociationTypeFunc               func(context.Context, string) (akeneo.AssociationType, error)
+	PatchAssociationTypeFunc             func(context.Context, string, akeneo.AssociationType) error
+	GetCategoryFunc                      func(context.Context, string) (akeneo.Category, error)
+	GetRootCategoriesFunc                func(context.Context) ([]akeneo.Category, error)
+	GetCategoriesByParentFunc            func(context.Context, string) ([]akeneo.Category, error)
+	PatchCategoryFunc                    func(context.Context, string, akeneo.Category) error
+	GetFamiliesFunc                      func(context.Context, int, int) ([]akeneo.Family, bool, error)
+	GetFamilyFunc                        func(context.Context, string) (akeneo.Family, error)
+	PatchFamilyFunc                      func(context.Context, string, akeneo.Family) error
+	GetFamilyVariantsFunc                func(context.Context, string) ([]akeneo.FamilyVariant, error)
+	PatchFamilyVariantFunc               func(context.Context, string, string, akeneo.FamilyVariant) error
+	GetChannelsFunc                      func(context.Context) ([]akeneo.Channel, error)
+	GetChannelFunc                       func(context.Context, string) (akeneo.Channel, error)
+	PatchChannelFunc                     func(context.Context, string, akeneo.Channel) error
+	GetLocalesFunc                       func(context.Context) ([]akeneo.Locale, error)
+	GetCurrencyFunc                      func(context.Context, string) (akeneo.Currency, error)
+	GetCurrenciesFunc                    func(context.Context) ([]akeneo.Currency, error)
+	GetAssetFamiliesFunc                 func(context.Context) ([]akeneo.AssetFamily, error)
+	GetAssetFamilyFunc                   func(context.Context, string) (akeneo.AssetFamily, error)
+	PatchAssetFamilyFunc                 func(context.Context, string, akeneo.AssetFamily) error
+	GetAssetFamilyAttributesFunc         func(context.Context, string) ([]akeneo.AssetFamilyAttribute, error)
+	PatchAssetFamilyAttributeFunc        func(context.Context, string, string, akeneo.AssetFamilyAttribute) error
+	GetAssetAttributeOptionsFunc         func(context.Context, string, string) ([]akeneo.AssetAttributeOption, error)
+	PatchAssetAttributeOptionFunc        func(context.Context, string, string, string, akeneo.AssetAttributeOption) error
+	GetAssetFunc                         func(context.Context, string, string) (akeneo.Asset, error)
+	StreamAssetsFunc                     func(context.Context, string, int, func([]akeneo.Asset) error) error
+	PatchAssetsFunc                      func(context.Context, string, []akeneo.Asset) (map[string]error, error)
+	DeleteAssetFunc                      func(context.Context, string, string) error
+	DownloadAssetMediaFileFunc           func(context.Context, string) ([]byte, error)
+	UploadAssetMediaFileFunc             func(context.Context, string, []byte) (string, error)
+	GetMeasurementFamiliesFunc           func(context.Context) ([]akeneo.MeasurementFamily, error)
+	PatchMeasurementFamiliesFunc         func(context.Context, []akeneo.MeasurementFamily) (map[string]error, error)
+}
+
+// Mock must implement the API
+var _ akeneo.API = (*MockAPI)(nil)
+
+// notConfigured is returned by operations the test did not set
+func notConfigured(operation string) error {
+	return fmt.Errorf("akeneotest: %s is not configured", operation)
+}
+
+// GetReferenceEntities calls GetReferenceEntitiesFunc
+func (m *MockAPI) GetReferenceEntities(ctx context.Context) ([]akeneo.ReferenceEntity, error) {
+	if m.GetReferenceEntitiesFunc != nil {
+		return m.GetReferenceEntitiesFunc(ctx)
+	}
+	return nil, notConfigured("GetReferenceEntities")
+}
+
+// GetReferenceEntity calls GetReferenceEntityFunc
+func (m *MockAPI) GetReferenceEntity(ctx context.Context, entityCode string) (akeneo.ReferenceEntity, error) {
+	if m.GetReferenceEntityFunc != nil {
+		return m.GetReferenceEntityFunc(ctx, entityCode)
+	}
+	return nil, notConfigured("GetReferenceEntity")
+}
+
+// PatchReferenceEntity calls PatchReferenceEntityFunc
+func (m *MockAPI) PatchReferenceEntity(ctx context.Context, entityCode string, entity akeneo.ReferenceEntity) error {
+	if m.PatchReferenceEntityFunc != nil {
+		return m.PatchReferenceEntityFunc(ctx, entityCode, entity)
+	}
+	return notConfigured("PatchReferenceEntity")
+}
+
+// GetReferenceEntityAttributes calls GetReferenceEntityAttributesFunc
+func (m *MockAPI) GetReferenceEntityAttributes(ctx context.Context, entityCode string) ([]akeneo.ReferenceEntityAttribute, error) {
+	if m.GetReferenceEntityAttributesFunc != nil {
+		return m.GetReferenceEntityAttributesFunc(ctx, entityCode)
+	}
+	return nil, notConfigured("GetReferenceEntityAttributes")
+}
+
+// PatchReferenceEntityAttribute calls PatchReferenceEntityAttributeFunc
+func (m *MockAPI) PatchReferenceEntityAttribute(ctx context.Context, entityCode string, attributeCode string, attribute akeneo.ReferenceEntityAttribute) error {
+	if m.PatchReferenceEntityAttributeFunc != nil {
+		return m.PatchReferenceEntityAttributeFunc(ctx, entityCode, attributeCode, attribute)
+	}
+	return notConfigured("PatchReferenceEntityAttribute")
+}
+
+// GetReferenceEntityRecords calls GetReferenceEntityRecordsFunc
+func (m *MockAPI) GetReferenceEntityRecords(ctx context.Context, entityName string) ([]akeneo.ReferenceEntityRecord, error) {
+	if m.GetReferenceEntityRecordsFunc != nil {
+		return m.GetReferenceEntityRecordsFunc(ctx, entityName)
+	}
+	return nil, notConfigured("GetReferenceEntityRecords")
+}
+
+// StreamReferenceEntityRecords calls StreamReferenceEntityRecordsFunc
+func (m *MockAPI) StreamReferenceEntityRecords(ctx context.Context, entityName string, batchSize int, callback func([]akeneo.ReferenceEntityRecord) error) error {
+	if m.StreamReferenceEntityRecordsFunc != nil {
+		return m.StreamReferenceEntityRecordsFunc(ctx, entityName, batchSize, callback)
+	}
+	return notConfigured("StreamReferenceEntityRecords")
+}
+
+// GetReferenceEntityRecord calls GetReferenceEntityRecordFunc
+func (m *MockAPI) GetReferenceEntityRecord(ctx context.Context, entityName string, code string) (akeneo.ReferenceEntityRecord, error) {
+	if m.GetReferenceEntityRecordFunc != nil {
+		return m.GetReferenceEntityRecordFunc(ctx, entityName, code)
+	}
+	return nil, notConfigured("GetReferenceEntityRecord")
+}
+
+// PatchReferenceEntityRecord calls PatchReferenceEntityRecordFunc
+func (m *MockAPI) PatchReferenceEntityRecord(ctx context.Context, entityName string, code string, record akeneo.ReferenceEntityRecord) error {
+	if m.PatchReferenceEntityRecordFunc != nil {
+		return m.PatchReferenceEntityRecordFunc(ctx, entityName, code, record)
+	}
+	return notConfigured("PatchReferenceEntityRecord")
+}
+
+// PatchReferenceEntityRecords calls PatchReferenceEntityRecordsFunc
+func (m *MockAPI) PatchReferenceEntityRecords(ctx context.Context, entityName string, records []akeneo.ReferenceEntityRecord) (map[string]error, error) {
+	if m.PatchReferenceEntityRecordsFunc != nil {
+		return m.PatchReferenceEntityRecordsFunc(ctx, entityName, records)
+	}
+	return nil, notConfigured("PatchReferenceEntityRecords")
+}
+
+// DeleteReferenceEntityRecord calls DeleteReferenceEntityRecordFunc
+func (m *MockAPI) DeleteReferenceEntityRecord(ctx context.Context, entityName, code string) error {
+	if m.DeleteReferenceEntityRecordFunc != nil {
+		return m.DeleteReferenceEntityRecordFunc(ctx, entityName, code)
+	}
+	return notConfigured("DeleteReferenceEntityRecord")
+}
+
+// DownloadReferenceEntityMediaFile calls DownloadReferenceEntityMediaFileFunc
+func (m *MockAPI) DownloadReferenceEntityMediaFile(ctx context.Context, code string) ([]byte, error) {
+	if m.DownloadReferenceEntityMediaFileFunc != nil {
+		return m.DownloadReferenceEntityMediaFileFunc(ctx, code)
+	}
+	return nil, notConfigured("DownloadReferenceEntityMediaFile")
+}
+
+// UploadReferenceEntityMediaFile calls UploadReferenceEntityMediaFileFunc
+func (m *MockAPI) UploadReferenceEntityMediaFile(ctx context.Context, filename string, content []byte) (string, error) {
+	if m.UploadReferenceEntityMediaFileFunc != nil {
+		return m.UploadReferenceEntityMediaFileFunc(ctx, filename, content)
+	}
+	return "", notConfigured("UploadReferenceEntityMediaFile")
+}
+
+// GetProduct calls GetProductFunc
+func (m *MockAPI) GetProduct(ctx context.Context, identifier string) (akeneo.Product, error) {
+	if m.GetProductFunc != nil {
+		return m.GetProductFunc(ctx, identifier)
+	}
+	return nil, notConfigured("GetProduct")
+}
+
+// PatchProduct calls PatchProductFunc
+func (m *MockAPI) PatchProduct(ctx context.Context, identifier string, productData akeneo.Product) error {
+	if m.PatchProductFunc != nil {
+		return m.PatchProductFunc(ctx, identifier, productData)
+	}
+	return notConfigured("PatchProduct")
+}
+
+// PatchProducts calls PatchProductsFunc
+func (m *MockAPI) PatchProducts(ctx context.Context, products []akeneo.Product) (map[string]error, error) {
+	if m.PatchProductsFunc != nil {
+		return m.PatchProductsFunc(ctx, products)
+	}
+	return nil, notConfigured("PatchProducts")
+}
+
+// GetProductModel calls GetProductModelFunc
+func (m *MockAPI) GetProductModel(ctx context.Context, code string) (akeneo.ProductModel, error) {
+	if m.GetProductModelFunc != nil {
+		return m.GetProductModelFunc(ctx, code)
+	}
+	return nil, notConfigured("GetProductModel")
+}
+
+// PatchProductModel calls PatchProductModelFunc
+func (m *MockAPI) PatchProductModel(ctx context.Context, code string, model akeneo.ProductModel) error {
+	if m.PatchProductModelFunc != nil {
+		return m.PatchProductModelFunc(ctx, code, model)
+	}
+	return notConfigured("PatchProductModel")
+}
+
+// PatchProductModels calls PatchProductModelsFunc
+func (m *MockAPI) PatchProductModels(ctx context.Context, models []akeneo.ProductModel) (map[string]error, error) {
+	if m.PatchProductModelsFunc != nil {
+		return m.PatchProductModelsFunc(ctx, models)
+	}
+	return nil, notConfigured("PatchProductModels")
+}
+
+// GetProductsByParent calls GetProductsByParentFunc
+func (m *MockAPI) GetProductsByParent(ctx context.Context, parentCode string) ([]akeneo.Product, error) {
+	if m.GetProductsByParentFunc != nil {
+		return m.GetProductsByParentFunc(ctx, parentCode)
+	}
+	return nil, notConfigured("GetProductsByParent")
+}
+
+// GetProductModelsByParent calls GetProductModelsByParentFunc
+func (m *MockAPI) GetProductModelsByParent(ctx context.Context, parentCode string) ([]akeneo.ProductModel, error) {
+	if m.GetProductModelsByParentFunc != nil {
+		return m.GetProductModelsByParentFunc(ctx, parentCode)
+	}
+	return nil, notConfigured("GetProductModelsByParent")
+}
+
+// GetProductIdentifiersByCategory calls GetProductIdentifiersByCategoryFunc
+func (m *MockAPI) GetProductIdentifiersByCategory(ctx context.Context, categoryCode string) ([]string, error) {
+	if m.GetProductIdentifiersByCategoryFunc != nil {
+		return m.GetProductIdentifiersByCategoryFunc(ctx, categoryCode)
+	}
+	return nil, notConfigured("GetProductIdentifiersByCategory")
+}
+
+// GetProductsUpdatedSince calls GetProductsUpdatedSinceFunc
+func (m *MockAPI) GetProductsUpdatedSince(ctx context.Context, updatedSince string) ([]akeneo.Product, error) {
+	if m.GetProductsUpdatedSinceFunc != nil {
+		return m.GetProductsUpdatedSinceFunc(ctx, updatedSince)
+	}
+	return nil, notConfigured("GetProductsUpdatedSince")
+}
+
+// GetProductModelsUpdatedSince calls GetProductModelsUpdatedSinceFunc
+func (m *MockAPI) GetProductModelsUpdatedSince(ctx context.Context, updatedSince string) ([]akeneo.ProductModel, error) {
+	if m.GetProductModelsUpdatedSinceFunc != nil {
+		return m.GetProductModelsUpdatedSinceFunc(ctx, updatedSince)
+	}
+	return nil, notConfigured("GetProductModelsUpdatedSince")
+}
+
+// StreamProductsUpdatedSince calls StreamProductsUpdatedSinceFunc
+func (m *MockAPI) StreamProductsUpdatedSince(ctx context.Context, updatedSince string, updatedUntil string, batchSize int, callback func([]akeneo.Product) error) error {
+	if m.StreamProductsUpdatedSinceFunc != nil {
+		return m.StreamProductsUpdatedSinceFunc(ctx, updatedSince, updatedUntil, batchSize, callback)
+	}
+	return notConfigured("StreamProductsUpdatedSince")
+}
+
+// StreamProductsBySearch calls StreamProductsBySearchFunc
+func (m *MockAPI) StreamProductsBySearch(ctx context.Context, search string, batchSize int, callback func([]akeneo.Product) error) error {
+	if m.StreamProductsBySearchFunc != nil {
+		return m.StreamProductsBySearchFunc(ctx, search, batchSize, callback)
+	}
+	return notConfigured("StreamProductsBySearch")
+}
+
+// StreamProductModelsUpdatedSince calls StreamProductModelsUpdatedSinceFunc
+func (m *MockAPI) StreamProductModelsUpdatedSince(ctx context.Context, updatedSince string, updatedUntil string, batchSize int, callback func([]akeneo.ProductModel) error) error {
+	if m.StreamProductModelsUpdatedSinceFunc != nil {
+		return m.StreamProductModelsUpdatedSinceFunc(ctx, updatedSince, updatedUntil, batchSize, callback)
+	}
+	return notConfigured("StreamProductModelsUpdatedSince")
+}
+
+// DownloadMediaFile calls DownloadMediaFileFunc
+func (m *MockAPI) DownloadMediaFile(ctx context.Context, code string) ([]byte, error) {
+	if m.DownloadMediaFileFunc != nil {
+		return m.DownloadMediaFileFunc(ctx, code)
+	}
+	return nil, notConfigured("DownloadMediaFile")
+}
+
+// UploadMediaFile calls UploadMediaFileFunc
+func (m *MockAPI) UploadMediaFile(ctx context.Context, target akeneo.MediaFileTarget, filename string, content []byte) (string, error) {
+	if m.UploadMediaFileFunc != nil {
+		return m.UploadMediaFileFunc(ctx, target, filename, content)
+	}
+	return "", notConfigured("UploadMediaFile")
+}
+
+// GetIdentifierAttribute calls GetIdentifierAttributeFunc
+func (m *MockAPI) GetIdentifierAttribute(ctx context.Context) (string, error) {
+	if m.GetIdentifierAttributeFunc != nil {
+		return m.GetIdentifierAttributeFunc(ctx)
+	}
+	return "", notConfigured("GetIdentifierAttribute")
+}
+
+// GetProductByUUID calls GetProductByUUIDFunc
+func (m *MockAPI) GetProductByUUID(ctx context.Context, uuid string) (akeneo.Product, error) {
+	if m.GetProductByUUIDFunc != nil {
+		return m.GetProductByUUIDFunc(ctx, uuid)
+	}
+	return nil, notConfigured("GetProductByUUID")
+}
+
+// GetProductUUIDs calls GetProductUUIDsFunc
+func (m *MockAPI) GetProductUUIDs(ctx context.Context, identifierAttribute string, identifiers []string) (map[string]string, error) {
+	if m.GetProductUUIDsFunc != nil {
+		return m.GetProductUUIDsFunc(ctx, identifierAttribute, identifiers)
+	}
+	return nil, notConfigured("GetProductUUIDs")
+}
+
+// PatchProductByUUID calls PatchProductByUUIDFunc
+func (m *MockAPI) PatchProductByUUID(ctx context.Context, uuid string, productData akeneo.Product) error {
+	if m.PatchProductByUUIDFunc != nil {
+		return m.PatchProductByUUIDFunc(ctx, uuid, productData)
+	}
+	return notConfigured("PatchProductByUUID")
+}
+
+// PatchProductsByUUID calls PatchProductsByUUIDFunc
+func (m *MockAPI) PatchProductsByUUID(ctx context.Context, products []akeneo.Product) (map[string]error, error) {
+	if m.PatchProductsByUUIDFunc != nil {
+		return m.PatchProductsByUUIDFunc(ctx, products)
+	}
+	return nil, notConfigured("PatchProductsByUUID")
+}
+
+// GetPublishedProduct calls GetPublishedProductFunc
+func (m *MockAPI) GetPublishedProduct(ctx context.Context, identifier string) (akeneo.PublishedProduct, error) {
+	if m.GetPublishedProductFunc != nil {
+		return m.GetPublishedProductFunc(ctx, identifier)
+	}
+	return nil, notConfigured("GetPublishedProduct")
+}
+
+// StreamPublishedProducts calls StreamPublishedProductsFunc
+func (m *MockAPI) StreamPublishedProducts(ctx context.Context, batchSize int, callback func([]akeneo.PublishedProduct) error) error {
+	if m.StreamPublishedProductsFunc != nil {
+		return m.StreamPublishedProductsFunc(ctx, batchSize, callback)
+	}
+	return notConfigured("StreamPublishedProducts")
+}
+
+// GetSystemInformation calls GetSystemInformationFunc
+func (m *MockAPI) GetSystemInformation(ctx context.Context) (*akeneo.SystemInformation, error) {
+	if m.GetSystemInformationFunc != nil {
+		return m.GetSystemInformationFunc(ctx)
+	}
+	return nil, notConfigured("GetSystemInformation")
+}
+
+// CountItems calls CountItemsFunc
+func (m *MockAPI) CountItems(ctx context.Context, resource string) (int, error) {
+	if m.CountItemsFunc != nil {
+		return m.CountItemsFunc(ctx, resource)
+	}
+	return 0, notConfigured("CountItems")
+}
+
+// CountMatching calls CountMatchingFunc
+func (m *MockAPI) CountMatching(ctx context.Context, resource, search string) (int, error) {
+	if m.CountMatchingFunc != nil {
+		return m.CountMatchingFunc(ctx, resource, search)
+	}
+	return 0, notConfigured("CountMatching")
+}
+
+// CountUpdatedSince calls CountUpdatedSinceFunc
+func (m *MockAPI) CountUpdatedSince(ctx context.Context, resource, updatedSince, updatedUntil string) (int, error) {
+	if m.CountUpdatedSinceFunc != nil {
+		return m.CountUpdatedSinceFunc(ctx, resource, updatedSince, updatedUntil)
+	}
+	return 0, notConfigured("CountUpdatedSince")
+}
+
+// GetAttributes calls GetAttributesFunc
+func (m *MockAPI) GetAttributes(ctx context.Context, page, limit int) ([]akeneo.Attribute, bool, error) {
+	if m.GetAttributesFunc != nil {
+		return m.GetAttributesFunc(ctx, page, limit)
+	}
+	return nil, false, notConfigured("GetAttributes")
+}
+
+// GetAttribute calls GetAttributeFunc
+func (m *MockAPI) GetAttribute(ctx context.Context, code string) (akeneo.Attribute, error) {
+	if m.GetAttributeFunc != nil {
+		return m.GetAttributeFunc(ctx, code)
+	}
+	return nil, notConfigured("GetAttribute")
+}
+
+// PatchAttribute calls PatchAttributeFunc
+func (m *MockAPI) PatchAttribute(ctx context.Context, code string, attribute akeneo.Attribute) error {
+	if m.PatchAttributeFunc != nil {
+		return m.PatchAttributeFunc(ctx, code, attribute)
+	}
+	return notConfigured("PatchAttribute")
+}
+
+// GetAttributeOptions calls GetAttributeOptionsFunc
+func (m *MockAPI) GetAttributeOptions(ctx context.Context, attributeCode string) ([]akeneo.AttributeOption, error) {
+	if m.GetAttributeOptionsFunc != nil {
+		return m.GetAttributeOptionsFunc(ctx, attributeCode)
+	}
+	return nil, notConfigured("GetAttributeOptions")
+}
+
+// PatchAttributeOption calls PatchAttributeOptionFunc
+func (m *MockAPI) PatchAttributeOption(ctx context.Context, attributeCode string, optionCode string, option akeneo.AttributeOption) error {
+	if m.PatchAttributeOptionFunc != nil {
+		return m.PatchAttributeOptionFunc(ctx, attributeCode, optionCode, option)
+	}
+	return notConfigured("PatchAttributeOption")
+}
+
+// GetAttributeGroup calls GetAttributeGroupFunc
+func (m *MockAPI) GetAttributeGroup(ctx context.Context, code string) (akeneo.AttributeGroup, error) {
+	if m.GetAttributeGroupFunc != nil {
+		return m.GetAttributeGroupFunc(ctx, code)
+	}
+	return nil, notConfigured("GetAttributeGroup")
+}
+
+// PatchAttributeGroup calls PatchAttributeGroupFunc
+func (m *MockAPI) PatchAttributeGroup(ctx context.Context, code string, group akeneo.AttributeGroup) error {
+	if m.PatchAttributeGroupFunc != nil {
+		return m.PatchAttributeGroupFunc(ctx, code, group)
+	}
+	return notConfigured("PatchAttributeGroup")
+}
+
+// GetAssociationTypes calls GetAssociationTypesFunc
+func (m *MockAPI) GetAssociationTypes(ctx context.Context) ([]akeneo.AssociationType, error) {
+	if m.GetAssociationTypesFunc != nil {
+		return m.GetAssociationTypesFunc(ctx)
+	}
+	return nil, notConfigured("GetAssociationTypes")
+}
+
+// GetAssociationType calls GetAssociationTypeFunc
+func (m *MockAPI) GetAssociationType(ctx context.Context, code string) (akeneo.AssociationType, error) {
+	if m.GetAssociationTypeFunc != nil {
+		return m.GetAssociationTypeFunc(ctx, code)
+	}
+	return nil, notConfigured("GetAssociationType")
+}
+
+// PatchAssociationType calls PatchAssociationTypeFunc
+func (m *MockAPI) PatchAssociationType(ctx context.Context, code string, associationType akeneo.AssociationType) error {
+	if m.PatchAssociationTypeFunc != nil {
+		return m.PatchAssociationTypeFunc(ctx, code, associationType)
+	}
+	return notConfigured("PatchAssociationType")
+}
+
+// GetCategory calls GetCategoryFunc
+func (m *MockAPI) GetCategory(ctx context.Context, code string) (akeneo.Category, error) {
+	if m.GetCategoryFunc != nil {
+		return m.GetCategoryFunc(ctx, code)
+	}
+	return nil, notConfigured("GetCategory")
+}
+
+// GetRootCategories calls GetRootCategoriesFunc
+func (m *MockAPI) GetRootCategories(ctx context.Context) ([]akeneo.Category, error) {
+	if m.GetRootCategoriesFunc != nil {
+		return m.GetRootCategoriesFunc(ctx)
+	}
+	return nil, notConfigured("GetRootCategories")
+}
+
+// GetCategoriesByParent calls GetCategoriesByParentFunc
+func (m *MockAPI) GetCategoriesByParent(ctx context.Context, parentCode string) ([]akeneo.Category, error) {
+	if m.GetCategoriesByParentFunc != nil {
+		return m.GetCategoriesByParentFunc(ctx, parentCode)
+	}
+	return nil, notConfigured("GetCategoriesByParent")
+}
+
+// PatchCategory calls PatchCategoryFunc
+func (m *MockAPI) PatchCategory(ctx context.Context, code string, categoryData akeneo.Category) error {
+	if m.PatchCategoryFunc != nil {
+		return m.PatchCategoryFunc(ctx, code, categoryData)
+	}
+	return notConfigured("PatchCategory")
+}
+
+// GetFamilies calls GetFamiliesFunc
+func (m *MockAPI) GetFamilies(ctx context.Context, page, limit int) ([]akeneo.Family, bool, error) {
+	if m.GetFamiliesFunc != nil {
+		return m.GetFamiliesFunc(ctx, page, limit)
+	}
+	return nil, false, notConfigured("GetFamilies")
+}
+
+// GetFamily calls GetFamilyFunc
+func (m *MockAPI) GetFamily(ctx context.Context, code string) (akeneo.Family, error) {
+	if m.GetFamilyFunc != nil {
+		return m.GetFamilyFunc(ctx, code)
+	}
+	return nil, notConfigured("GetFamily")
+}
+
+// PatchFamily calls PatchFamilyFunc
+func (m *MockAPI) PatchFamily(ctx context.Context, code string, familyData akeneo.Family) error {
+	if m.PatchFamilyFunc != nil {
+		return m.PatchFamilyFunc(ctx, code, familyData)
+	}
+	return notConfigured("PatchFamily")
+}
+
+// GetFamilyVariants calls GetFamilyVariantsFunc
+func (m *MockAPI) GetFamilyVariants(ctx context.Context, familyCode string) ([]akeneo.FamilyVariant, error) {
+	if m.GetFamilyVariantsFunc != nil {
+		return m.GetFamilyVariantsFunc(ctx, familyCode)
+	}
+	return nil, notConfigured("GetFamilyVariants")
+}
+
+// PatchFamilyVariant calls PatchFamilyVariantFunc
+func (m *MockAPI) PatchFamilyVariant(ctx context.Context, familyCode string, variantCode string, variant akeneo.FamilyVariant) error {
+	if m.PatchFamilyVariantFunc != nil {
+		return m.PatchFamilyVariantFunc(ctx, familyCode, variantCode, variant)
+	}
+	return notConfigured("PatchFamilyVariant")
+}
+
+// GetChannels calls GetChannelsFunc
+func (m *MockAPI) GetChannels(ctx context.Context) ([]akeneo.Channel, error) {
+	if m.GetChannelsFunc != nil {
+		return m.GetChannelsFunc(ctx)
+	}
+	return nil, notConfigured("GetChannels")
+}
+
+// GetChannel calls GetChannelFunc
+func (m *MockAPI) GetChannel(ctx context.Context, code string) (akeneo.Channel, error) {
+	if m.GetChannelFunc != nil {
+		return m.GetChannelFunc(ctx, code)
+	}
+	return nil, notConfigured("GetChannel")
+}
+
+// PatchChannel calls PatchChannelFunc
+func (m *MockAPI) PatchChannel(ctx context.Context, code string, channel akeneo.Channel) error {
+	if m.PatchChannelFunc != nil {
+		return m.PatchChannelFunc(ctx, code, channel)
+	}
+	return notConfigured("PatchChannel")
+}
+
+// GetLocales calls GetLocalesFunc
+func (m *MockAPI) GetLocales(ctx context.Context) ([]akeneo.Locale, error) {
+	if m.GetLocalesFunc != nil {
+		return m.GetLocalesFunc(ctx)
+	}
+	return nil, notConfigured("GetLocales")
+}
+
+// GetCurrency calls GetCurrencyFunc
+func (m *MockAPI) GetCurrency(ctx context.Context, code string) (akeneo.Currency, error) {
+	if m.GetCurrencyFunc != nil {
+		return m.GetCurrencyFunc(ctx, code)
+	}
+	return nil, notConfigured("GetCurrency")
+}
+
+// GetCurrencies calls GetCurrenciesFunc
+func (m *MockAPI) GetCurrencies(ctx context.Context) ([]akeneo.Currency, error) {
+	if m.GetCurrenciesFunc != nil {
+		return m.GetCurrenciesFunc(ctx)
+	}
+	return nil, notConfigured("GetCurrencies")
+}
+
+// GetMeasurementFamilies calls GetMeasurementFamiliesFunc
+func (m *MockAPI) GetMeasurementFamilies(ctx context.Context) ([]akeneo.MeasurementFamily, error) {
+	if m.GetMeasurementFamiliesFunc != nil {
+		return m.GetMeasurementFamiliesFunc(ctx)
+	}
+	return nil, notConfigured("GetMeasurementFamilies")
+}
+
+// PatchMeasurementFamilies calls PatchMeasurementFamiliesFunc
+func (m *MockAPI) PatchMeasurementFamilies(ctx context.Context, families []akeneo.MeasurementFamily) (map[string]error, error) {
+	if m.PatchMeasurementFamiliesFunc != nil {
+		return m.PatchMeasurementFamiliesFunc(ctx, families)
+	}
+	return nil, notConfigured("PatchMeasurementFamilies")
+}
+
+// GetAssetFamilies calls GetAssetFamiliesFunc
+func (m *MockAPI) GetAssetFamilies(ctx context.Context) ([]akeneo.AssetFamily, error) {
+	if m.GetAssetFamiliesFunc != nil {
+		return m.GetAssetFamiliesFunc(ctx)
+	}
+	return nil, notConfigured("GetAssetFamilies")
+}
+
+// GetAssetFamily calls GetAssetFamilyFunc
+func (m *MockAPI) GetAssetFamily(ctx context.Context, familyCode string) (akeneo.AssetFamily, error) {
+	if m.GetAssetFamilyFunc != nil {
+		return m.GetAssetFamilyFunc(ctx, familyCode)
+	}
+	return nil, notConfigured("GetAssetFamily")
+}
+
+// PatchAssetFamily calls PatchAssetFamilyFunc
+func (m *MockAPI) PatchAssetFamily(ctx context.Context, familyCode string, family akeneo.AssetFamily) error {
+	if m.PatchAssetFamilyFunc != nil {
+		return m.PatchAssetFamilyFunc(ctx, familyCode, family)
+	}
+	return notConfigured("PatchAssetFamily")
+}
+
+// GetAssetFamilyAttributes calls GetAssetFamilyAttributesFunc
+func (m *MockAPI) GetAssetFamilyAttributes(ctx context.Context, familyCode string) ([]akeneo.AssetFamilyAttribute, error) {
+	if m.GetAssetFamilyAttributesFunc != nil {
+		return m.GetAssetFamilyAttributesFunc(ctx, familyCode)
+	}
+	return nil, notConfigured("GetAssetFamilyAttributes")
+}
+
+// PatchAssetFamilyAttribute calls PatchAssetFamilyAttributeFunc
+func (m *MockAPI) PatchAssetFamilyAttribute(ctx context.Context, familyCode, attributeCode string, attribute akeneo.AssetFamilyAttribute) error {
+	if m.PatchAssetFamilyAttributeFunc != nil {
+		return m.PatchAssetFamilyAttributeFunc(ctx, familyCode, attributeCode, attribute)
+	}
+	return notConfigured("PatchAssetFamilyAttribute")
+}
+
+// GetAssetAttributeOptions calls GetAssetAttributeOptionsFunc
+func (m *MockAPI) GetAssetAttributeOptions(ctx context.Context, familyCode, attributeCode string) ([]akeneo.AssetAttributeOption, error) {
+	if m.GetAssetAttributeOptionsFunc != nil {
+		return m.GetAssetAttributeOptionsFunc(ctx, familyCode, attributeCode)
+	}
+	return nil, notConfigured("GetAssetAttributeOptions")
+}
+
+// PatchAssetAttributeOption calls PatchAssetAttributeOptionFunc
+func (m *MockAPI) PatchAssetAttributeOption(ctx context.Context, familyCode, attributeCode, optionCode string, option akeneo.AssetAttributeOption) error {
+	if m.PatchAssetAttributeOptionFunc != nil {
+		return m.PatchAssetAttributeOptionFunc(ctx, familyCode, attributeCode, optionCode, option)
+	}
+	return notConfigured("PatchAssetAttributeOption")
+}
+
+// GetAsset calls GetAssetFunc
+func (m *MockAPI) GetAsset(ctx context.Context, familyCode, code string) (akeneo.Asset, error) {
+	if m.GetAssetFunc != nil {
+		return m.GetAssetFunc(ctx, familyCode, code)
+	}
+	return nil, notConfigured("GetAsset")
+}
+
+// StreamAssets calls StreamAssetsFunc
+func (m *MockAPI) StreamAssets(ctx context.Context, familyCode string, batchSize int, callback func([]akeneo.Asset) error) error {
+	if m.StreamAssetsFunc != nil {
+		return m.StreamAssetsFunc(ctx, familyCode, batchSize, callback)
+	}
+	return notConfigured("StreamAssets")
+}
+
+// PatchAssets calls PatchAssetsFunc
+func (m *MockAPI) PatchAssets(ctx context.Context, familyCode string, assets []akeneo.Asset) (map[string]error, error) {
+	if m.PatchAssetsFunc != nil {
+		return m.PatchAssetsFunc(ctx, familyCode, assets)
+	}
+	return nil, notConfigured("PatchAssets")
+}
+
+// DeleteAsset calls DeleteAssetFunc
+func (m *MockAPI) DeleteAsset(ctx context.Context, familyCode, code string) error {
+	if m.DeleteAssetFunc != nil {
+		return m.DeleteAssetFunc(ctx, familyCode, code)
+	}
+	return notConfigured("DeleteAsset")
+}
+
+// DownloadAssetMediaFile calls DownloadAssetMediaFileFunc
+func (m *MockAPI) DownloadAssetMediaFile(ctx context.Context, code string) ([]byte, error) {
+	if m.DownloadAssetMediaFileFunc != nil {
+		return m.DownloadAssetMediaFileFunc(ctx, code)
+	}
+	return nil, notConfigured("DownloadAssetMediaFile")
+}
+
+// UploadAssetMediaFile calls UploadAssetMediaFileFunc
+func (m *MockAPI) UploadAssetMediaFile(ctx context.Context, filename string, content []byte) (string, error) {
+	if m.UploadAssetMediaFileFunc != nil {
+		return m.UploadAssetMediaFileFunc(ctx, filename, content)
+	}
+	return "", notConfigured("UploadAssetMediaFile")
+}
@@ -0,0 +1,112 @@
+package akeneo
+
+import "context"
+
+// API is the set of Akeneo operations used by the repositories. *Client implements it;
+// akeneotest.MockAPI implements it for unit tests.
+type API interface {
+	// Reference entities
+	GetReferenceEntities(ctx context.Context) ([]ReferenceEntity, error)
+	GetReferenceEntity(ctx context.Context, entityCode string) (ReferenceEntity, error)
+	PatchReferenceEntity(ctx context.Context, entityCode string, entity ReferenceEntity) error
+	GetReferenceEntityAttributes(ctx context.Context, entityCode string) ([]ReferenceEntityAttribute, error)
+	PatchReferenceEntityAttribute(ctx context.Context, entityCode, attributeCode string, attribute ReferenceEntityAttribute) error
+	GetReferenceEntityRecords(ctx context.Context, entityName string) ([]ReferenceEntityRecord, error)
+	StreamReferenceEntityRecords(ctx context.Context, entityName string, batchSize int, callback func([]ReferenceEntityRecord) error) error
+	GetReferenceEntityRecord(ctx context.Context, entityName, code string) (ReferenceEntityRecord, error)
+	PatchReferenceEntityRecord(ctx context.Context, entityName, code string, record ReferenceEntityRecord) error
+	PatchReferenceEntityRecords(ctx context.Context, entityName string, records []ReferenceEntityRecord) (map[string]error, error)
+	DeleteReferenceEntityRecord(ctx context.Context, entityName, code string) error
+	DownloadReferenceEntityMediaFile(ctx context.Context, code string) ([]byte, error)
+	UploadReferenceEntityMediaFile(ctx context.Context, filename string, content []byte) (string, error)
+
+	// Products and product models
+	GetProduct(ctx context.Context, identifier string) (Product, error)
+	PatchProduct(ctx context.Context, identifier string, productData Product) error
+	PatchProducts(ctx context.Context, products []Product) (map[string]error, error)
+	GetProductModel(ctx context.Context, code string) (ProductModel, error)
+	PatchProductModel(ctx context.Context, code string, model ProductModel) error
+	PatchProductModels(ctx context.Context, models []ProductModel) (map[string]error, error)
+	GetProductsByParent(ctx context.Context, parentCode string) ([]Product, error)
+	GetProductModelsByParent(ctx context.Context, parentCode string) ([]ProductModel, error)
+	GetProductIdentifiersByCategory(ctx context.Context, categoryCode string) ([]string, error)
+	GetProductsUpdatedSince(ctx context.Context, updatedSince string) ([]Product, error)
+	GetProductModelsUpdatedSince(ctx context.Context, updatedSince string) ([]ProductModel, error)
+	StreamProductsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]Product) error) error
+	StreamProductsBySearch(ctx context.Context, search string, batchSize int, callback func([]Product) error) error
+	StreamProductModelsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]ProductModel) error) error
+	DownloadMediaFile(ctx context.Context, code string) ([]byte, error)
+	UploadMediaFile(ctx context.Context, target MediaFileTarget, filename string, content []byte) (string, error)
+	GetIdentifierAttribute(ctx context.Context) (string, error)
+	GetProductByUUID(ctx context.Context, uuid string) (Product, error)
+	GetProductUUIDs(ctx context.Context, identifierAttribute string, identifiers []string) (map[string]string, error)
+	PatchProductByUUID(ctx context.Context, uuid string, productData Product) error
+	PatchProductsByUUID(ctx context.Context, products []Product) (map[string]error, error)
+	GetPublishedProduct(ctx context.Context, identifier string) (PublishedProduct, error)
+	StreamPublishedProducts(ctx context.Context, batchSize int, callback func([]PublishedProduct) error) error
+
+	// Attributes
+	GetAttributes(ctx context.Context, page, limit int) ([]Attribute, bool, error)
+	GetAttribute(ctx context.Context, code string) (Attribute, error)
+	PatchAttribute(ctx context.Context, code string, attribute Attribute) error
+	GetAttributeOptions(ctx context.Context, attributeCode string) ([]AttributeOption, error)
+	PatchAttributeOption(ctx context.Context, attributeCode, optionCode string, option AttributeOption) error
+
+	// Attribute groups
+	GetAttributeGroup(ctx context.Context, code string) (AttributeGroup, error)
+	PatchAttributeGroup(ctx context.Context, code string, group AttributeGroup) error
+
+	// Association types
+	GetAssociationTypes(ctx context.Context) ([]AssociationType, error)
+	GetAssociationType(ctx context.Context, code string) (AssociationType, error)
+	PatchAssociationType(ctx context.Context, code string, associationType AssociationType) error
+
+	// Categories
+	GetCategory(ctx context.Context, code string) (Category, error)
+	GetRootCategories(ctx context.Context) ([]Category, error)
+	GetCategoriesByParent(ctx context.Context, parentCode string) ([]Category, error)
+	PatchCategory(ctx context.Context, code string, categoryData Category) error
+
+	// Families
+	GetFamilies(ctx context.Context, page, limit int) ([]Family, bool, error)
+	GetFamily(ctx context.Context, code string) (Family, error)
+	PatchFamily(ctx context.Context, code string, familyData Family) error
+	GetFamilyVariants(ctx context.Context, familyCode string) ([]FamilyVariant, error)
+	PatchFamilyVariant(ctx context.Context, familyCode, variantCode string, variant FamilyVariant) error
+
+	// Channels, locales and currencies
+	GetChannels(ctx context.Context) ([]Channel, error)
+	GetChannel(ctx context.Context, code string) (Channel, error)
+	PatchChannel(ctx context.Context, code string, channel Channel) error
+	GetLocales(ctx context.Context) ([]Locale, error)
+	GetCurrency(ctx context.Context, code string) (Currency, error)
+	GetCurrencies(ctx context.Context) ([]Currency, error)
+
+	// Asset Manager
+	GetAssetFamilies(ctx context.Context) ([]AssetFamily, error)
+	GetAssetFamily(ctx context.Context, familyCode string) (AssetFamily, error)
+	PatchAssetFamily(ctx context.Context, familyCode string, family AssetFamily) error
+	GetAssetFamilyAttributes(ctx context.Context, familyCode string) ([]AssetFamilyAttribute, error)
+	PatchAssetFamilyAttribute(ctx context.Context, familyCode, attributeCode string, attribute AssetFamilyAttribute) error
+	GetAssetAttributeOptions(ctx context.Context, familyCode, attributeCode string) ([]AssetAttributeOption, error)
+	PatchAssetAttributeOption(ctx context.Context, familyCode, attributeCode, optionCode string, option AssetAttributeOption) error
+	GetAsset(ctx context.Context, familyCode, code string) (Asset, error)
+	StreamAssets(ctx context.Context, familyCode string, batchSize int, callback func([]Asset) error) error
+	PatchAssets(ctx context.Context, familyCode string, assets []Asset) (map[string]error, error)
+	DeleteAsset(ctx context.Context, familyCode, code string) error
+	DownloadAssetMediaFile(ctx context.Context, code string) ([]byte, error)
+	UploadAssetMediaFile(ctx context.Context, filename string, content []byte) (string, error)
+
+	// Measurement families
+	GetMeasurementFamilies(ctx context.Context) ([]MeasurementFamily, error)
+	PatchMeasurementFamilies(ctx context.Context, families []MeasurementFamily) (map[string]error, error)
+
+	// System
+	GetSystemInformation(ctx context.Context) (*SystemInformation, error)
+	CountItems(ctx context.Context, resource string) (int, error)
+	CountMatching(ctx context.Context, resource, search string) (int, error)
+	CountUpdatedSince(ctx context.Context, resource, updatedSince, updatedUntil string) (int, error)
+}
+
+// Client must implement API
+var _ API = (*Client)(nil)
@@ -0,0 +1,412 @@
+package akeneo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// AssetFamily represents an asset family definition
+type AssetFamily map[string]interface{}
+
+// AssetFamilyAttribute represents an attribute of an asset family
+type AssetFamilyAttribute map[string]interface{}
+
+// AssetAttributeOption represents an option of a single or multiple options asset attribute
+type AssetAttributeOption map[string]interface{}
+
+// Asset represents an asset of an asset family
+type Asset map[string]interface{}
+
+// GetAssetFamilies retrieves every asset family definition, following the search_after cursors of Akeneo
+func (c *Client) GetAssetFamilies(ctx context.Context) ([]AssetFamily, error) {
+	var families []AssetFamily
+
+	err := streamPages(ctx, c, "/api/rest/v1/asset-families", "asset families", func(page []AssetFamily) error {
+		families = append(families, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return families, nil
+}
+
+// GetAssetFamily retrieves an asset family definition
+func (c *Client) GetAssetFamily(ctx context.Context, familyCode string) (AssetFamily, error) {
+	var family AssetFamily
+	if err := c.getJSON(ctx, fmt.Sprintf("asset-families/%s", familyCode), "asset family '"+familyCode+"'", &family); err != nil {
+		return nil, err
+	}
+	return family, nil
+}
+
+// PatchAssetFamily creates or updates an asset family definition
+func (c *Client) PatchAssetFamily(ctx context.Context, familyCode string, family AssetFamily) error {
+	return c.patchJSON(ctx, fmt.Sprintf("asset-families/%s", familyCode), "asset family "+familyCode, cleanLinks(family))
+}
+
+// GetAssetFamilyAttributes retrieves all attributes of an asset family; the endpoint is not paginated
+func (c *Client) GetAssetFamilyAttributes(ctx context.Context, familyCode string) ([]AssetFamilyAttribute, error) {
+	var attributes []AssetFamilyAttribute
+	if err := c.getJSON(ctx, fmt.Sprintf("asset-families/%s/attributes", familyCode), "attributes of asset family '"+familyCode+"'", &attributes); err != nil {
+		return nil, err
+	}
+	return attributes, nil
+}
+
+// PatchAssetFamilyAttribute creates or updates an attribute of an asset family
+func (c *Client) PatchAssetFamilyAttribute(ctx context.Context, familyCode, attributeCode string, attribute AssetFamilyAttribute) error {
+	return c.patchJSON(ctx, fmt.Sprintf("asset-families/%s/attributes/%s", familyCode, attributeCode), "asset attribute "+attributeCode, cleanLinks(attribute))
+}
+
+// GetAssetAttributeOptions retrieves all options of an asset attribute; the endpoint is not paginated
+func (c *Client) GetAssetAttributeOptions(ctx context.Context, familyCode, attributeCode string) ([]AssetAttributeOption, error) {
+	var options []AssetAttributeOption
+	if err := c.getJSON(ctx, fmt.Sprintf("asset-families/%s/attributes/%s/options", familyCode, attributeCode), "options of asset attribute '"+attributeCode+"'", &options); err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+// PatchAssetAttributeOption creates or updates an option of an asset attribute
+func (c *Client) PatchAssetAttributeOption(ctx context.Context, familyCode, attributeCode, optionCode string, option AssetAttributeOption) error {
+	return c.patchJSON(ctx, fmt.Sprintf("asset-families/%s/attributes/%s/options/%s", familyCode, attributeCode, optionCode), "asset attribute option "+optionCode, cleanLinks(option))
+}
+
+// GetAsset retrieves an asset of an asset family
+func (c *Client) GetAsset(ctx context.Context, familyCode, code string) (Asset, error) {
+	var item Asset
+	if err := c.getJSON(ctx, fmt.Sprintf("asset-families/%s/assets/%s", familyCode, code), "asset '"+code+"'", &item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// StreamAssets processes the assets of an asset family page by page, following the search_after
+// cursors of Akeneo. The callback is called for each page of batchSize assets
+func (c *Client) StreamAssets(ctx context.Context, familyCode string, batchSize int, callback func([]Asset) error) error {
+	requestURI := fmt.Sprintf("/api/rest/v1/asset-families/%s/assets?limit=%d", familyCode, batchSize)
+	return streamPages(ctx, c, requestURI, "assets", callback)
+}
+
+// DeleteAsset deletes an asset of an asset family
+func (c *Client) DeleteAsset(ctx context.Context, familyCode, code string) error {
+	return c.deleteJSON(ctx, fmt.Sprintf("asset-families/%s/assets/%s", familyCode, code), "asset "+code)
+}
+
+// PatchAssets creates or updates several assets of an asset family, up to 100 per call.
+// It returns the errors of the assets that were not written, indexed by code.
+func (c *Client) PatchAssets(ctx context.Context, familyCode string, assets []Asset) (map[string]error, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return nil, err
+	}
+
+	failed := make(map[string]error)
+
+	for start := 0; start < len(assets); start += maxCollectionSize {
+		end := start + maxCollectionSize
+		if end > len(assets) {
+			end = len(assets)
+		}
+
+		codes := make([]string, 0, end-start)
+		chunk := make([]Asset, 0, end-start)
+		for _, asset := range assets[start:end] {
+			code, _ := asset["code"].(string)
+			if code == "" {
+				return nil, fmt.Errorf("asset without code cannot be sent in a collection")
+			}
+			codes = append(codes, code)
+			chunk = append(chunk, c.cleanAsset(asset))
+		}
+
+		statuses, err := c.sendAssets(ctx, familyCode, chunk)
+		if err != nil {
+			for _, code := range codes {
+				failed[code] = err
+			}
+			continue
+		}
+
+		for _, status := range statuses {
+			if status.StatusCode >= http.StatusBadRequest {
+				failed[status.Code] = lineError("asset "+status.Code, status)
+			}
+		}
+	}
+
+	return failed, nil
+}
+
+// sendAssets sends one chunk of assets and decodes the status of each asset
+func (c *Client) sendAssets(ctx context.Context, familyCode string, assets []Asset) ([]CollectionLineResult, error) {
+	jsonData, err := json.Marshal(assets)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/api/rest/v1/asset-families/%s/assets", c.config.Host, familyCode)
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token())
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("error updating assets: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var statuses []CollectionLineResult
+	if err := json.NewDecoder(resp.Body).Decode(&statuses); err != nil {
+		return nil, fmt.Errorf("error decoding assets response: %w", err)
+	}
+
+	return statuses, nil
+}
+
+// DownloadAssetMediaFile downloads the content of an asset media file
+func (c *Client) DownloadAssetMediaFile(ctx context.Context, code string) ([]byte, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/api/rest/v1/asset-media-files/%s", c.config.Host, code)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token())
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("asset media file '%s' %w", code, ErrNotFound)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("error downloading asset media file: %d - %s", resp.StatusCode, string(body))
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// UploadAssetMediaFile uploads an asset media file and returns the code assigned to it
+func (c *Client) UploadAssetMediaFile(ctx context.Context, filename string, content []byte) (string, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return "", err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(content); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf("%s/api/rest/v1/asset-media-files", c.config.Host)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, &body)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token())
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := c.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+
+		if resp.StatusCode == http.StatusUnprocessableEntity {
+			var errorResponse AkeneoErrorResponse
+			if parseErr := json.Unmarshal(respBody, &errorResponse); parseErr == nil {
+				return "", newValidationError("asset media file "+filename, errorResponse)
+			}
+		}
+
+		return "", fmt.Errorf("error uploading asset media file %s: %d - %s", filename, resp.StatusCode, string(respBody))
+	}
+
+	// Akeneo returns the code of the uploaded file in a response header
+	code := resp.Header.Get("Asset-Media-File-Code")
+	if code == "" {
+		return "", fmt.Errorf("no asset media file code returned for %s", filename)
+	}
+
+	return code, nil
+}
+
+// cleanAsset removes fields that should not be sent in write operations
+func (c *Client) cleanAsset(asset Asset) Asset {
+	cleaned := make(Asset)
+
+	// List of fields to exclude (metadata fields from API responses)
+	excludedFields := map[string]bool{
+		"_links":  true,
+		"created": true,
+		"updated": true,
+	}
+
+	for key, value := range asset {
+		if !excludedFields[key] && value != nil {
+			cleaned[key] = value
+		}
+	}
+
+	return cleaned
+}
+
+// getJSON retrieves a single resource and decodes it into target. A 404 is reported as ErrNotFound
+func (c *Client) getJSON(ctx context.Context, resource, what string, target interface{}) error {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/rest/v1/%s", c.config.Host, resource)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token())
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s %w", what, ErrNotFound)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("error fetching %s: %d - %s", what, resp.StatusCode, string(body))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// patchJSON creates or updates a single resource
+func (c *Client) patchJSON(ctx context.Context, resource, what string, payload interface{}) error {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return err
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/rest/v1/%s", c.config.Host, resource)
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(jsonData))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token())
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+
+		if resp.StatusCode == http.StatusUnprocessableEntity {
+			var errorResponse AkeneoErrorResponse
+			if parseErr := json.Unmarshal(body, &errorResponse); parseErr == nil {
+				return newValidationError(what, errorResponse)
+			}
+		}
+
+		return fmt.Errorf("error updating %s: %d - %s", what, resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// deleteJSON deletes a single resource. A 404 is reported as ErrNotFound
+func (c *Client) deleteJSON(ctx context.Context, resource, what string) error {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/rest/v1/%s", c.config.Host, resource)
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token())
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s %w", what, ErrNotFound)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("error deleting %s: %d - %s", what, resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// cleanLinks returns a copy of a resource without its _links metadata
+func cleanLinks[T ~map[string]interface{}](resource T) T {
+	cleaned := make(T, len(resource))
+	for key, value := range resource {
+		if key != "_links" {
+			cleaned[key] = value
+		}
+	}
+	return cleaned
+}
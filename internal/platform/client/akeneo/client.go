@@ -2,12 +2,15 @@ package akeneo
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -18,12 +21,26 @@ type ClientConfig struct {
 	Secret   string
 	Username string
 	Password string
+	// AccessToken is the token of an Akeneo App. When set, it is sent as is instead of
+	// requesting a token with the connection credentials, which are then ignored.
+	AccessToken string
+	// Transport overrides the HTTP transport, e.g. to record or replay cassettes
+	Transport http.RoundTripper
 }
 
 // Client represents a client for the Akeneo API
 type Client struct {
-	config      ClientConfig
-	httpClient  *http.Client
+	config     ClientConfig
+	httpClient *http.Client
+	logger     Logger
+	logLevel   Level
+	limiter    *rateLimiter
+	// send sends a request through the middlewares
+	send    NextFunc
+	metrics MetricsRecorder
+
+	// tokenMu guards the token: one goroutine refreshes it while the others wait
+	tokenMu     sync.Mutex
 	accessToken string
 	tokenExpiry time.Time
 }
@@ -54,31 +71,90 @@ type AkeneoFieldError struct {
 	Message  string `json:"message"`
 }
 
-// NewClient creates a new Akeneo client
-func NewClient(config ClientConfig) (*Client, error) {
+// NewClient creates a new Akeneo client. Options tune the HTTP client, logging and retries.
+func NewClient(config ClientConfig, opts ...Option) (*Client, error) {
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	httpClient, err := newHTTPClient(config, options)
+	if err != nil {
+		return nil, fmt.Errorf("TLS configuration error: %w", err)
+	}
+
 	client := &Client{
-		config: config,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		config:     config,
+		httpClient: httpClient,
+		logger:     options.logger,
+		logLevel:   options.logLevel,
+		limiter:    newRateLimiter(options.retryPolicy),
+		metrics:    options.metrics,
+	}
+
+	// Identification headers are set first so the other middlewares see them, writes of dry runs
+	// are stopped before reaching them, and metrics are measured closest to the transport
+	headers := make(map[string]string)
+	if options.userAgent != "" {
+		headers["User-Agent"] = options.userAgent
+	}
+	if options.correlationID != "" {
+		headers[options.correlationHeader] = options.correlationID
 	}
+	middlewares := []Middleware{headersMiddleware(headers), dryRunMiddleware}
+	middlewares = append(middlewares, options.middlewares...)
+	if options.metrics != nil {
+		middlewares = append(middlewares, metricsMiddleware(options.metrics))
+	}
+	client.send = chain(httpClient.Do, middlewares)
 
 	// Get access token
-	if err := client.authenticate(); err != nil {
+	if err := client.ensureValidToken(context.Background()); err != nil {
 		return nil, fmt.Errorf("authentication error: %w", err)
 	}
 
 	return client, nil
 }
 
-// authenticate obtains an OAuth2 access token
-func (c *Client) authenticate() error {
+// newHTTPClient builds the HTTP client from the options. The transport of the configuration,
+// used to record or replay cassettes, takes precedence over the one of a given HTTP client;
+// without either, a transport tuned by the transport and TLS configurations is built.
+func newHTTPClient(config ClientConfig, options clientOptions) (*http.Client, error) {
+	httpClient := &http.Client{Timeout: defaultTimeout}
+	if options.httpClient != nil {
+		copied := *options.httpClient
+		httpClient = &copied
+	}
+
+	if options.timeout > 0 {
+		httpClient.Timeout = options.timeout
+	}
+	if config.Transport != nil {
+		httpClient.Transport = config.Transport
+	}
+	if httpClient.Transport == nil {
+		transport := newTransport(options.transport)
+		if options.tls.enabled() {
+			tlsConfig, err := options.tls.build()
+			if err != nil {
+				return nil, err
+			}
+			transport.TLSClientConfig = tlsConfig
+		}
+		httpClient.Transport = transport
+	}
+
+	return httpClient, nil
+}
+
+// authenticate obtains an OAuth2 access token. The caller must hold tokenMu.
+func (c *Client) authenticate(ctx context.Context) error {
 	data := url.Values{}
 	data.Set("grant_type", "password")
 	data.Set("username", c.config.Username)
 	data.Set("password", c.config.Password)
 
-	req, err := http.NewRequest("POST", c.config.Host+"/api/oauth/v1/token", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.config.Host+"/api/oauth/v1/token", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
@@ -86,7 +162,7 @@ func (c *Client) authenticate() error {
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.SetBasicAuth(c.config.ClientID, c.config.Secret)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
@@ -108,78 +184,115 @@ func (c *Client) authenticate() error {
 	return nil
 }
 
-// ensureValidToken verifies the token is valid and renews it if necessary
-func (c *Client) ensureValidToken() error {
-	if time.Now().After(c.tokenExpiry.Add(-5 * time.Minute)) {
-		return c.authenticate()
-	}
-	return nil
-}
-
-// GetReferenceEntityRecords retrieves all records from a Reference Entity
-func (c *Client) GetReferenceEntityRecords(entityName string) ([]ReferenceEntityRecord, error) {
-	if err := c.ensureValidToken(); err != nil {
-		return nil, err
-	}
-
-	var allRecords []ReferenceEntityRecord
-	page := 1
-	limit := 100
-
-	for {
-		url := fmt.Sprintf("%s/api/rest/v1/reference-entities/%s/records?page=%d&limit=%d",
-			c.config.Host, entityName, page, limit)
+// do sends a request, waiting while the API throttles the client. Throttled requests (429)
+// are replayed up to the MaxRetries of the retry policy, then a RateLimitError is returned.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := c.limiter.wait(req.Context()); err != nil {
+			return nil, err
+		}
 
-		req, err := http.NewRequest("GET", url, nil)
+		resp, err := c.send(req)
 		if err != nil {
 			return nil, err
 		}
 
-		req.Header.Set("Authorization", "Bearer "+c.accessToken)
-		req.Header.Set("Content-Type", "application/json")
+		c.limiter.observe(resp)
 
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			return nil, err
+		if resp.StatusCode != http.StatusTooManyRequests {
+			return resp, nil
 		}
-		defer func() { _ = resp.Body.Close() }()
 
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			return nil, fmt.Errorf("error fetching records: %d - %s", resp.StatusCode, string(body))
+		_ = resp.Body.Close()
+
+		// Give up after the last replay, or when the consumed request body cannot be rebuilt
+		if attempt >= c.limiter.policy.MaxRetries || (req.Body != nil && req.GetBody == nil) {
+			return nil, &RateLimitError{RetryAfter: c.limiter.retryAfter(resp)}
 		}
 
-		var response struct {
-			Embedded struct {
-				Items []ReferenceEntityRecord `json:"items"`
-			} `json:"_embedded"`
-			Links struct {
-				Next *struct {
-					Href string `json:"href"`
-				} `json:"next"`
-			} `json:"_links"`
+		c.logf(LevelInfo, "⏳ Rate limited on %s %s, retrying (%d/%d)\n", req.Method, req.URL.Path, attempt+1, c.limiter.policy.MaxRetries)
+		if c.metrics != nil {
+			c.metrics.RecordRetry(req.Method, endpointOf(req.URL.Path))
 		}
 
-		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-			return nil, err
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("error rebuilding throttled request: %w", err)
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
 		}
+	}
+}
 
-		allRecords = append(allRecords, response.Embedded.Items...)
+// logf sends a message to the logger when its level is enabled
+func (c *Client) logf(level Level, format string, args ...interface{}) {
+	if leveled, ok := c.logger.(LevelLogger); ok {
+		leveled.Logf(level, format, args...)
+		return
+	}
+	if level >= c.logLevel {
+		c.logger.Printf(format, args...)
+	}
+}
 
-		// If no next page, finish
-		if response.Links.Next == nil {
-			break
-		}
+// debugEnabled tells whether debug messages are printed, so payloads are only serialized when needed
+func (c *Client) debugEnabled() bool {
+	_, leveled := c.logger.(LevelLogger)
+	return leveled || c.logLevel <= LevelDebug
+}
 
-		page++
+// ensureValidToken verifies the token is valid and renews it if necessary
+func (c *Client) ensureValidToken(ctx context.Context) error {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	// App tokens do not expire: they stay valid until the App is disconnected
+	if c.config.AccessToken != "" {
+		c.accessToken = c.config.AccessToken
+		return nil
+	}
+
+	if time.Now().After(c.tokenExpiry.Add(-5 * time.Minute)) {
+		return c.authenticate(ctx)
+	}
+	return nil
+}
+
+// token returns the current access token
+func (c *Client) token() string {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	return c.accessToken
+}
+
+// GetReferenceEntityRecords retrieves all records from a Reference Entity
+func (c *Client) GetReferenceEntityRecords(ctx context.Context, entityName string) ([]ReferenceEntityRecord, error) {
+	var allRecords []ReferenceEntityRecord
+
+	err := c.StreamReferenceEntityRecords(ctx, entityName, defaultPageSize, func(records []ReferenceEntityRecord) error {
+		allRecords = append(allRecords, records...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return allRecords, nil
 }
 
+// StreamReferenceEntityRecords processes the records of a Reference Entity page by page, so memory
+// stays flat whatever the number of records. The callback is called for each page of batchSize records
+func (c *Client) StreamReferenceEntityRecords(ctx context.Context, entityName string, batchSize int, callback func([]ReferenceEntityRecord) error) error {
+	requestURI := fmt.Sprintf("/api/rest/v1/reference-entities/%s/records?limit=%d", entityName, batchSize)
+	return streamPages(ctx, c, requestURI, "records", callback)
+}
+
 // PatchReferenceEntityRecord creates or updates a record in a Reference Entity
-func (c *Client) PatchReferenceEntityRecord(entityName, code string, record ReferenceEntityRecord) error {
-	if err := c.ensureValidToken(); err != nil {
+func (c *Client) PatchReferenceEntityRecord(ctx context.Context, entityName, code string, record ReferenceEntityRecord) error {
+	if err := c.ensureValidToken(ctx); err != nil {
 		return err
 	}
 
@@ -194,15 +307,15 @@ func (c *Client) PatchReferenceEntityRecord(entityName, code string, record Refe
 	url := fmt.Sprintf("%s/api/rest/v1/reference-entities/%s/records/%s",
 		c.config.Host, entityName, code)
 
-	req, err := http.NewRequest("PATCH", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Authorization", "Bearer "+c.token())
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
@@ -215,7 +328,7 @@ func (c *Client) PatchReferenceEntityRecord(entityName, code string, record Refe
 		if resp.StatusCode == http.StatusUnprocessableEntity {
 			var errorResponse AkeneoErrorResponse
 			if parseErr := json.Unmarshal(body, &errorResponse); parseErr == nil {
-				return fmt.Errorf("validation error in record %s: %s", code, c.formatAkeneoErrors(errorResponse))
+				return newValidationError("record "+code, errorResponse)
 			}
 		}
 
@@ -225,6 +338,141 @@ func (c *Client) PatchReferenceEntityRecord(entityName, code string, record Refe
 	return nil
 }
 
+// GetReferenceEntityRecord retrieves a single record of a Reference Entity
+func (c *Client) GetReferenceEntityRecord(ctx context.Context, entityName, code string) (ReferenceEntityRecord, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/api/rest/v1/reference-entities/%s/records/%s", c.config.Host, entityName, code)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token())
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("record '%s' %w in reference entity '%s'", code, ErrNotFound, entityName)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("error fetching record: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var record ReferenceEntityRecord
+	if err := json.NewDecoder(resp.Body).Decode(&record); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// DeleteReferenceEntityRecord deletes a record of a Reference Entity
+func (c *Client) DeleteReferenceEntityRecord(ctx context.Context, entityName, code string) error {
+	return c.deleteJSON(ctx, fmt.Sprintf("reference-entities/%s/records/%s", entityName, code), "record "+code)
+}
+
+// DownloadReferenceEntityMediaFile downloads the content of a Reference Entity media file
+func (c *Client) DownloadReferenceEntityMediaFile(ctx context.Context, code string) ([]byte, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/api/rest/v1/reference-entities-media-files/%s", c.config.Host, code)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token())
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("media file '%s' %w", code, ErrNotFound)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("error downloading media file: %d - %s", resp.StatusCode, string(body))
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// UploadReferenceEntityMediaFile uploads a Reference Entity media file and returns the code assigned to it
+func (c *Client) UploadReferenceEntityMediaFile(ctx context.Context, filename string, content []byte) (string, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return "", err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(content); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf("%s/api/rest/v1/reference-entities-media-files", c.config.Host)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, &body)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token())
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := c.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+
+		// For 422 errors, try to parse Akeneo error response
+		if resp.StatusCode == http.StatusUnprocessableEntity {
+			var errorResponse AkeneoErrorResponse
+			if parseErr := json.Unmarshal(respBody, &errorResponse); parseErr == nil {
+				return "", newValidationError("media file "+filename, errorResponse)
+			}
+		}
+
+		return "", fmt.Errorf("error uploading media file %s: %d - %s", filename, resp.StatusCode, string(respBody))
+	}
+
+	// Akeneo returns the code of the uploaded file in a response header
+	code := resp.Header.Get("Reference-Entities-Media-File-Code")
+	if code == "" {
+		return "", fmt.Errorf("no media file code returned for %s", filename)
+	}
+
+	return code, nil
+}
+
 // cleanRecord removes fields that should not be sent in write operations
 func (c *Client) cleanRecord(record ReferenceEntityRecord) ReferenceEntityRecord {
 	cleaned := make(ReferenceEntityRecord)
@@ -250,52 +498,45 @@ func (c *Client) cleanRecord(record ReferenceEntityRecord) ReferenceEntityRecord
 	return cleaned
 }
 
-// formatAkeneoErrors formats Akeneo errors to display useful information
-func (c *Client) formatAkeneoErrors(errorResponse AkeneoErrorResponse) string {
-	if len(errorResponse.Errors) == 0 {
-		return errorResponse.Message
-	}
+// GetReferenceEntities retrieves all Reference Entity definitions, following the pages of the list
+func (c *Client) GetReferenceEntities(ctx context.Context) ([]ReferenceEntity, error) {
+	var entities []ReferenceEntity
 
-	var errorMessages []string
-	for _, fieldError := range errorResponse.Errors {
-		errorMessages = append(errorMessages, fmt.Sprintf("Field '%s': %s", fieldError.Property, fieldError.Message))
+	err := streamPages(ctx, c, "/api/rest/v1/reference-entities", "reference entities", func(page []ReferenceEntity) error {
+		entities = append(entities, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return fmt.Sprintf("%s. Details: %s", errorResponse.Message, strings.Join(errorMessages, "; "))
-}
-
-// DebugRecord prints the content of a record for debugging purposes
-func (c *Client) DebugRecord(entityName, code string, record ReferenceEntityRecord) {
-	cleanRecord := c.cleanRecord(record)
-	if jsonData, err := json.MarshalIndent(cleanRecord, "", "  "); err == nil {
-		fmt.Printf("🔍 DEBUG - Record %s/%s:\n%s\n", entityName, code, string(jsonData))
-	}
+	return entities, nil
 }
 
 // Get ReferenceEntity retrieves a Reference Entity definition
-func (c *Client) GetReferenceEntity(entityCode string) (ReferenceEntity, error) {
-	if err := c.ensureValidToken(); err != nil {
+func (c *Client) GetReferenceEntity(ctx context.Context, entityCode string) (ReferenceEntity, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
 		return nil, err
 	}
 
 	url := fmt.Sprintf("%s/api/rest/v1/reference-entities/%s", c.config.Host, entityCode)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Authorization", "Bearer "+c.token())
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("reference entity '%s' not found", entityCode)
+		return nil, fmt.Errorf("reference entity '%s' %w", entityCode, ErrNotFound)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -312,8 +553,8 @@ func (c *Client) GetReferenceEntity(entityCode string) (ReferenceEntity, error)
 }
 
 // PatchReferenceEntity creates or updates a Reference Entity definition
-func (c *Client) PatchReferenceEntity(entityCode string, entity ReferenceEntity) error {
-	if err := c.ensureValidToken(); err != nil {
+func (c *Client) PatchReferenceEntity(ctx context.Context, entityCode string, entity ReferenceEntity) error {
+	if err := c.ensureValidToken(ctx); err != nil {
 		return err
 	}
 
@@ -327,15 +568,15 @@ func (c *Client) PatchReferenceEntity(entityCode string, entity ReferenceEntity)
 
 	url := fmt.Sprintf("%s/api/rest/v1/reference-entities/%s", c.config.Host, entityCode)
 
-	req, err := http.NewRequest("PATCH", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Authorization", "Bearer "+c.token())
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
@@ -348,7 +589,7 @@ func (c *Client) PatchReferenceEntity(entityCode string, entity ReferenceEntity)
 		if resp.StatusCode == http.StatusUnprocessableEntity {
 			var errorResponse AkeneoErrorResponse
 			if parseErr := json.Unmarshal(body, &errorResponse); parseErr == nil {
-				return fmt.Errorf("validation error in reference entity %s: %s", entityCode, c.formatAkeneoErrors(errorResponse))
+				return newValidationError("reference entity "+entityCode, errorResponse)
 			}
 		}
 
@@ -384,22 +625,22 @@ func (c *Client) cleanReferenceEntity(entity ReferenceEntity) ReferenceEntity {
 type ReferenceEntityAttribute map[string]interface{}
 
 // GetReferenceEntityAttributes retrieves all attributes from a Reference Entity
-func (c *Client) GetReferenceEntityAttributes(entityCode string) ([]ReferenceEntityAttribute, error) {
-	if err := c.ensureValidToken(); err != nil {
+func (c *Client) GetReferenceEntityAttributes(ctx context.Context, entityCode string) ([]ReferenceEntityAttribute, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
 		return nil, err
 	}
 
 	url := fmt.Sprintf("%s/api/rest/v1/reference-entities/%s/attributes", c.config.Host, entityCode)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Authorization", "Bearer "+c.token())
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -416,8 +657,7 @@ func (c *Client) GetReferenceEntityAttributes(entityCode string) ([]ReferenceEnt
 		return nil, err
 	}
 
-	// Debug: print raw response
-	fmt.Printf("🔍 DEBUG - Raw attributes response:\n%s\n", string(body))
+	c.logf(LevelDebug, "🔍 DEBUG - Raw attributes response:\n%s\n", string(body))
 
 	// Try to unmarshal as array first (most common format)
 	var attributes []ReferenceEntityAttribute
@@ -450,14 +690,15 @@ func (c *Client) GetReferenceEntityAttributes(entityCode string) ([]ReferenceEnt
 }
 
 // PatchReferenceEntityAttribute creates or updates a Reference Entity attribute
-func (c *Client) PatchReferenceEntityAttribute(entityCode, attributeCode string, attribute ReferenceEntityAttribute) error {
-	if err := c.ensureValidToken(); err != nil {
+func (c *Client) PatchReferenceEntityAttribute(ctx context.Context, entityCode, attributeCode string, attribute ReferenceEntityAttribute) error {
+	if err := c.ensureValidToken(ctx); err != nil {
 		return err
 	}
 
-	// Debug: print original attribute
-	if originalJSON, err := json.MarshalIndent(attribute, "", "  "); err == nil {
-		fmt.Printf("🔍 DEBUG - Original attribute %s:\n%s\n", attributeCode, string(originalJSON))
+	if c.debugEnabled() {
+		if originalJSON, err := json.MarshalIndent(attribute, "", "  "); err == nil {
+			c.logf(LevelDebug, "🔍 DEBUG - Original attribute %s:\n%s\n", attributeCode, string(originalJSON))
+		}
 	}
 
 	// Clean fields that should not be sent
@@ -474,32 +715,20 @@ func (c *Client) PatchReferenceEntityAttribute(entityCode, attributeCode string,
 
 	jsonData := buf.Bytes()
 
-	// Debug: print what we're sending
-	fmt.Printf("🔍 DEBUG - Sending attribute %s:\n%s\n", attributeCode, string(jsonData))
-
-	// Additional debug: verify by unmarshalling back
-	var debugCheck map[string]interface{}
-	if err := json.Unmarshal(jsonData, &debugCheck); err == nil {
-		fmt.Printf("🔍 DEBUG - Labels type in JSON: %T, value: %v\n", debugCheck["labels"], debugCheck["labels"])
-	}
-
-	// Extra debug: check raw bytes of labels field
-	if labelsJSON, err := json.Marshal(debugCheck["labels"]); err == nil {
-		fmt.Printf("🔍 DEBUG - Labels as JSON bytes: %s\n", string(labelsJSON))
-	}
+	c.logf(LevelDebug, "🔍 DEBUG - Sending attribute %s:\n%s\n", attributeCode, string(jsonData))
 
 	url := fmt.Sprintf("%s/api/rest/v1/reference-entities/%s/attributes/%s",
 		c.config.Host, entityCode, attributeCode)
 
-	req, err := http.NewRequest("PATCH", url, bytes.NewReader(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(jsonData))
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Authorization", "Bearer "+c.token())
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
@@ -512,7 +741,7 @@ func (c *Client) PatchReferenceEntityAttribute(entityCode, attributeCode string,
 		if resp.StatusCode == http.StatusUnprocessableEntity {
 			var errorResponse AkeneoErrorResponse
 			if parseErr := json.Unmarshal(body, &errorResponse); parseErr == nil {
-				return fmt.Errorf("validation error in attribute %s: %s", attributeCode, c.formatAkeneoErrors(errorResponse))
+				return newValidationError("attribute "+attributeCode, errorResponse)
 			}
 		}
 
@@ -691,7 +920,7 @@ func (c *Client) normalizeLabels(labels interface{}, attributeCode string) map[s
 	}
 
 	// If we couldn't convert, log warning and return default label
-	fmt.Printf("⚠️  Warning: Could not normalize labels, using default. Original type: %T, value: %v\n", labels, labels)
+	c.logf(LevelWarn, "⚠️  Warning: Could not normalize labels, using default. Original type: %T, value: %v\n", labels, labels)
 	result["en_US"] = attributeCode
 	return result
 }
@@ -716,29 +945,29 @@ func (c *Client) normalizeArray(value interface{}) interface{} {
 type Product map[string]interface{}
 
 // GetProduct retrieves a product by its identifier
-func (c *Client) GetProduct(identifier string) (Product, error) {
-	if err := c.ensureValidToken(); err != nil {
+func (c *Client) GetProduct(ctx context.Context, identifier string) (Product, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
 		return nil, err
 	}
 
 	url := fmt.Sprintf("%s/api/rest/v1/products/%s", c.config.Host, identifier)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Authorization", "Bearer "+c.token())
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("product '%s' not found", identifier)
+		return nil, fmt.Errorf("product '%s' %w", identifier, ErrNotFound)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -755,8 +984,8 @@ func (c *Client) GetProduct(identifier string) (Product, error) {
 }
 
 // PatchProduct creates or updates a product
-func (c *Client) PatchProduct(identifier string, productData Product) error {
-	if err := c.ensureValidToken(); err != nil {
+func (c *Client) PatchProduct(ctx context.Context, identifier string, productData Product) error {
+	if err := c.ensureValidToken(ctx); err != nil {
 		return err
 	}
 
@@ -770,15 +999,15 @@ func (c *Client) PatchProduct(identifier string, productData Product) error {
 
 	url := fmt.Sprintf("%s/api/rest/v1/products/%s", c.config.Host, identifier)
 
-	req, err := http.NewRequest("PATCH", url, bytes.NewReader(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(jsonData))
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Authorization", "Bearer "+c.token())
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
@@ -791,7 +1020,7 @@ func (c *Client) PatchProduct(identifier string, productData Product) error {
 		if resp.StatusCode == http.StatusUnprocessableEntity {
 			var errorResponse AkeneoErrorResponse
 			if parseErr := json.Unmarshal(body, &errorResponse); parseErr == nil {
-				return fmt.Errorf("validation error in product %s: %s", identifier, c.formatAkeneoErrors(errorResponse))
+				return newValidationError("product "+identifier, errorResponse)
 			}
 		}
 
@@ -818,37 +1047,86 @@ func (c *Client) cleanProduct(productData Product) Product {
 			cleaned[key] = value
 		}
 	}
+	cleanQuantifiedAssociations(cleaned)
 
 	return cleaned
 }
 
+// cleanQuantifiedAssociations removes the UUIDs of the quantified association links that carry an identifier:
+// UUIDs are specific to the instance the item was read from, identifiers are not
+func cleanQuantifiedAssociations(item map[string]interface{}) {
+	associations, ok := item["quantified_associations"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	cleaned := make(map[string]interface{}, len(associations))
+	for associationType, links := range associations {
+		linksByKind, ok := links.(map[string]interface{})
+		if !ok {
+			cleaned[associationType] = links
+			continue
+		}
+
+		cleanedLinks := make(map[string]interface{}, len(linksByKind))
+		for kind, list := range linksByKind {
+			entries, ok := list.([]interface{})
+			if !ok {
+				cleanedLinks[kind] = list
+				continue
+			}
+
+			cleanedEntries := make([]interface{}, len(entries))
+			for i, raw := range entries {
+				cleanedEntries[i] = raw
+
+				entry, ok := raw.(map[string]interface{})
+				if _, hasIdentifier := entry["identifier"]; !ok || !hasIdentifier {
+					continue
+				}
+				link := make(map[string]interface{}, len(entry))
+				for key, value := range entry {
+					if key != "uuid" {
+						link[key] = value
+					}
+				}
+				cleanedEntries[i] = link
+			}
+			cleanedLinks[kind] = cleanedEntries
+		}
+		cleaned[associationType] = cleanedLinks
+	}
+
+	item["quantified_associations"] = cleaned
+}
+
 // ProductModel represents a product model
 type ProductModel map[string]interface{}
 
 // GetProductModel retrieves a product model by its code
-func (c *Client) GetProductModel(code string) (ProductModel, error) {
-	if err := c.ensureValidToken(); err != nil {
+func (c *Client) GetProductModel(ctx context.Context, code string) (ProductModel, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
 		return nil, err
 	}
 
 	url := fmt.Sprintf("%s/api/rest/v1/product-models/%s", c.config.Host, code)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Authorization", "Bearer "+c.token())
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("product model '%s' not found", code)
+		return nil, fmt.Errorf("product model '%s' %w", code, ErrNotFound)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -865,8 +1143,8 @@ func (c *Client) GetProductModel(code string) (ProductModel, error) {
 }
 
 // PatchProductModel creates or updates a product model
-func (c *Client) PatchProductModel(code string, model ProductModel) error {
-	if err := c.ensureValidToken(); err != nil {
+func (c *Client) PatchProductModel(ctx context.Context, code string, model ProductModel) error {
+	if err := c.ensureValidToken(ctx); err != nil {
 		return err
 	}
 
@@ -880,15 +1158,15 @@ func (c *Client) PatchProductModel(code string, model ProductModel) error {
 
 	url := fmt.Sprintf("%s/api/rest/v1/product-models/%s", c.config.Host, code)
 
-	req, err := http.NewRequest("PATCH", url, bytes.NewReader(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(jsonData))
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Authorization", "Bearer "+c.token())
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
@@ -900,7 +1178,7 @@ func (c *Client) PatchProductModel(code string, model ProductModel) error {
 		if resp.StatusCode == http.StatusUnprocessableEntity {
 			var errorResponse AkeneoErrorResponse
 			if parseErr := json.Unmarshal(body, &errorResponse); parseErr == nil {
-				return fmt.Errorf("validation error in product model %s: %s", code, c.formatAkeneoErrors(errorResponse))
+				return newValidationError("product model "+code, errorResponse)
 			}
 		}
 
@@ -911,172 +1189,105 @@ func (c *Client) PatchProductModel(code string, model ProductModel) error {
 }
 
 // GetProductsByParent retrieves all products with a specific parent
-func (c *Client) GetProductsByParent(parentCode string) ([]Product, error) {
-	if err := c.ensureValidToken(); err != nil {
+func (c *Client) GetProductsByParent(ctx context.Context, parentCode string) ([]Product, error) {
+	var allProducts []Product
+
+	params := url.Values{}
+	params.Set("search", fmt.Sprintf(`{"parent":[{"operator":"=","value":"%s"}]}`, parentCode))
+
+	err := streamSearchAfter(ctx, c, "products", params, defaultPageSize, "products by parent", func(items []Product) error {
+		allProducts = append(allProducts, items...)
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	var allProducts []Product
-	page := 1
-	limit := 100
+	return allProducts, nil
+}
 
-	for {
-		url := fmt.Sprintf("%s/api/rest/v1/products?search={\"parent\":[{\"operator\":\"=\",\"value\":\"%s\"}]}&page=%d&limit=%d",
-			c.config.Host, parentCode, page, limit)
+// GetProductModelsByParent retrieves all product models with a specific parent
+func (c *Client) GetProductModelsByParent(ctx context.Context, parentCode string) ([]ProductModel, error) {
+	var allModels []ProductModel
 
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return nil, err
-		}
+	params := url.Values{}
+	params.Set("search", fmt.Sprintf(`{"parent":[{"operator":"=","value":"%s"}]}`, parentCode))
 
-		req.Header.Set("Authorization", "Bearer "+c.accessToken)
-		req.Header.Set("Content-Type", "application/json")
+	err := streamSearchAfter(ctx, c, "product-models", params, defaultPageSize, "product models by parent", func(items []ProductModel) error {
+		allModels = append(allModels, items...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			return nil, err
-		}
-		defer func() { _ = resp.Body.Close() }()
+	return allModels, nil
+}
 
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			return nil, fmt.Errorf("error fetching products by parent: %d - %s", resp.StatusCode, string(body))
-		}
+// cleanProductModel removes fields that should not be sent in write operations
+func (c *Client) cleanProductModel(model ProductModel) ProductModel {
+	cleaned := make(ProductModel)
 
-		var response struct {
-			Embedded struct {
-				Items []Product `json:"items"`
-			} `json:"_embedded"`
-			Links struct {
-				Next *struct {
-					Href string `json:"href"`
-				} `json:"next"`
-			} `json:"_links"`
-		}
+	// List of fields to exclude
+	excludedFields := map[string]bool{
+		"_links":  true,
+		"created": true,
+		"updated": true,
+	}
 
-		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-			return nil, err
+	for key, value := range model {
+		if !excludedFields[key] && value != nil {
+			cleaned[key] = value
 		}
+	}
+	cleanQuantifiedAssociations(cleaned)
 
-		allProducts = append(allProducts, response.Embedded.Items...)
+	return cleaned
+}
 
-		if response.Links.Next == nil {
-			break
-		}
+// Attribute represents an attribute
+type Attribute map[string]interface{}
 
-		page++
+// GetAttributes retrieves one page of attributes, pages being numbered from 1.
+// hasNext tells whether more pages follow.
+func (c *Client) GetAttributes(ctx context.Context, page, limit int) ([]Attribute, bool, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return nil, false, err
 	}
 
-	return allProducts, nil
-}
-
-// GetProductModelsByParent retrieves all product models with a specific parent
-func (c *Client) GetProductModelsByParent(parentCode string) ([]ProductModel, error) {
-	if err := c.ensureValidToken(); err != nil {
-		return nil, err
-	}
-
-	var allModels []ProductModel
-	page := 1
-	limit := 100
-
-	for {
-		url := fmt.Sprintf("%s/api/rest/v1/product-models?search={\"parent\":[{\"operator\":\"=\",\"value\":\"%s\"}]}&page=%d&limit=%d",
-			c.config.Host, parentCode, page, limit)
-
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		req.Header.Set("Authorization", "Bearer "+c.accessToken)
-		req.Header.Set("Content-Type", "application/json")
-
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			return nil, err
-		}
-		defer func() { _ = resp.Body.Close() }()
-
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			return nil, fmt.Errorf("error fetching product models by parent: %d - %s", resp.StatusCode, string(body))
-		}
-
-		var response struct {
-			Embedded struct {
-				Items []ProductModel `json:"items"`
-			} `json:"_embedded"`
-			Links struct {
-				Next *struct {
-					Href string `json:"href"`
-				} `json:"next"`
-			} `json:"_links"`
-		}
-
-		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-			return nil, err
-		}
-
-		allModels = append(allModels, response.Embedded.Items...)
-
-		if response.Links.Next == nil {
-			break
-		}
-
-		page++
-	}
-
-	return allModels, nil
-}
-
-// cleanProductModel removes fields that should not be sent in write operations
-func (c *Client) cleanProductModel(model ProductModel) ProductModel {
-	cleaned := make(ProductModel)
-
-	// List of fields to exclude
-	excludedFields := map[string]bool{
-		"_links":  true,
-		"created": true,
-		"updated": true,
-	}
-
-	for key, value := range model {
-		if !excludedFields[key] && value != nil {
-			cleaned[key] = value
-		}
+	requestURI := fmt.Sprintf("/api/rest/v1/attributes?page=%d&limit=%d", page, limit)
+	result, err := fetchPage[Attribute](ctx, c, requestURI, "attributes")
+	if err != nil {
+		return nil, false, err
 	}
 
-	return cleaned
+	return result.Embedded.Items, result.Links.Next != nil, nil
 }
 
-// Attribute represents an attribute
-type Attribute map[string]interface{}
-
 // GetAttribute retrieves an attribute by its code
-func (c *Client) GetAttribute(code string) (Attribute, error) {
-	if err := c.ensureValidToken(); err != nil {
+func (c *Client) GetAttribute(ctx context.Context, code string) (Attribute, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
 		return nil, err
 	}
 
 	url := fmt.Sprintf("%s/api/rest/v1/attributes/%s", c.config.Host, code)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Authorization", "Bearer "+c.token())
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("attribute '%s' not found", code)
+		return nil, fmt.Errorf("attribute '%s' %w", code, ErrNotFound)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -1093,8 +1304,8 @@ func (c *Client) GetAttribute(code string) (Attribute, error) {
 }
 
 // PatchAttribute creates or updates an attribute
-func (c *Client) PatchAttribute(code string, attribute Attribute) error {
-	if err := c.ensureValidToken(); err != nil {
+func (c *Client) PatchAttribute(ctx context.Context, code string, attribute Attribute) error {
+	if err := c.ensureValidToken(ctx); err != nil {
 		return err
 	}
 
@@ -1108,15 +1319,15 @@ func (c *Client) PatchAttribute(code string, attribute Attribute) error {
 
 	url := fmt.Sprintf("%s/api/rest/v1/attributes/%s", c.config.Host, code)
 
-	req, err := http.NewRequest("PATCH", url, bytes.NewReader(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(jsonData))
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Authorization", "Bearer "+c.token())
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
@@ -1128,7 +1339,7 @@ func (c *Client) PatchAttribute(code string, attribute Attribute) error {
 		if resp.StatusCode == http.StatusUnprocessableEntity {
 			var errorResponse AkeneoErrorResponse
 			if parseErr := json.Unmarshal(body, &errorResponse); parseErr == nil {
-				return fmt.Errorf("validation error in attribute %s: %s", code, c.formatAkeneoErrors(errorResponse))
+				return newValidationError("attribute "+code, errorResponse)
 			}
 		}
 
@@ -1160,29 +1371,29 @@ func (c *Client) cleanAttribute(attribute Attribute) Attribute {
 type Category map[string]interface{}
 
 // GetCategory retrieves a category by its code
-func (c *Client) GetCategory(code string) (Category, error) {
-	if err := c.ensureValidToken(); err != nil {
+func (c *Client) GetCategory(ctx context.Context, code string) (Category, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
 		return nil, err
 	}
 
 	url := fmt.Sprintf("%s/api/rest/v1/categories/%s", c.config.Host, code)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Authorization", "Bearer "+c.token())
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("category '%s' not found", code)
+		return nil, fmt.Errorf("category '%s' %w", code, ErrNotFound)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -1199,8 +1410,8 @@ func (c *Client) GetCategory(code string) (Category, error) {
 }
 
 // PatchCategory creates or updates a category
-func (c *Client) PatchCategory(code string, categoryData Category) error {
-	if err := c.ensureValidToken(); err != nil {
+func (c *Client) PatchCategory(ctx context.Context, code string, categoryData Category) error {
+	if err := c.ensureValidToken(ctx); err != nil {
 		return err
 	}
 
@@ -1214,15 +1425,15 @@ func (c *Client) PatchCategory(code string, categoryData Category) error {
 
 	url := fmt.Sprintf("%s/api/rest/v1/categories/%s", c.config.Host, code)
 
-	req, err := http.NewRequest("PATCH", url, bytes.NewReader(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(jsonData))
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Authorization", "Bearer "+c.token())
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
@@ -1234,7 +1445,7 @@ func (c *Client) PatchCategory(code string, categoryData Category) error {
 		if resp.StatusCode == http.StatusUnprocessableEntity {
 			var errorResponse AkeneoErrorResponse
 			if parseErr := json.Unmarshal(body, &errorResponse); parseErr == nil {
-				return fmt.Errorf("validation error in category %s: %s", code, c.formatAkeneoErrors(errorResponse))
+				return newValidationError("category "+code, errorResponse)
 			}
 		}
 
@@ -1244,6 +1455,46 @@ func (c *Client) PatchCategory(code string, categoryData Category) error {
 	return nil
 }
 
+// GetRootCategories retrieves the roots of the category trees, following the pages of the list
+func (c *Client) GetRootCategories(ctx context.Context) ([]Category, error) {
+	var categories []Category
+
+	params := url.Values{}
+	params.Set("search", `{"is_root":[{"operator":"=","value":true}]}`)
+	params.Set("limit", fmt.Sprint(defaultPageSize))
+
+	requestURI := "/api/rest/v1/categories?" + params.Encode()
+	err := streamPages(ctx, c, requestURI, "root categories", func(page []Category) error {
+		categories = append(categories, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// GetCategoriesByParent retrieves the direct children of a category, following the pages of the list
+func (c *Client) GetCategoriesByParent(ctx context.Context, parentCode string) ([]Category, error) {
+	var categories []Category
+
+	params := url.Values{}
+	params.Set("search", fmt.Sprintf(`{"parent":[{"operator":"=","value":"%s"}]}`, parentCode))
+	params.Set("limit", fmt.Sprint(defaultPageSize))
+
+	requestURI := "/api/rest/v1/categories?" + params.Encode()
+	err := streamPages(ctx, c, requestURI, "categories by parent", func(page []Category) error {
+		categories = append(categories, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
 // cleanCategory removes fields that should not be sent in write operations
 func (c *Client) cleanCategory(categoryData Category) Category {
 	cleaned := make(Category)
@@ -1264,33 +1515,72 @@ func (c *Client) cleanCategory(categoryData Category) Category {
 	return cleaned
 }
 
+// GetProductIdentifiersByCategory retrieves the identifiers of all products classified
+// in a category or any of its children
+func (c *Client) GetProductIdentifiersByCategory(ctx context.Context, categoryCode string) ([]string, error) {
+	var identifiers []string
+
+	params := url.Values{}
+	params.Set("search", fmt.Sprintf(`{"categories":[{"operator":"IN_CHILDREN","value":["%s"]}]}`, categoryCode))
+
+	err := streamSearchAfter(ctx, c, "products", params, defaultPageSize, "products by category", func(items []Product) error {
+		for _, item := range items {
+			if identifier, ok := item["identifier"].(string); ok {
+				identifiers = append(identifiers, identifier)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return identifiers, nil
+}
+
 // Family represents a family
 type Family map[string]interface{}
 
+// GetFamilies retrieves one page of families, pages being numbered from 1.
+// hasNext tells whether more pages follow.
+func (c *Client) GetFamilies(ctx context.Context, page, limit int) ([]Family, bool, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return nil, false, err
+	}
+
+	requestURI := fmt.Sprintf("/api/rest/v1/families?page=%d&limit=%d", page, limit)
+	result, err := fetchPage[Family](ctx, c, requestURI, "families")
+	if err != nil {
+		return nil, false, err
+	}
+
+	return result.Embedded.Items, result.Links.Next != nil, nil
+}
+
 // GetFamily retrieves a family by its code
-func (c *Client) GetFamily(code string) (Family, error) {
-	if err := c.ensureValidToken(); err != nil {
+func (c *Client) GetFamily(ctx context.Context, code string) (Family, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
 		return nil, err
 	}
 
 	url := fmt.Sprintf("%s/api/rest/v1/families/%s", c.config.Host, code)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Authorization", "Bearer "+c.token())
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("family '%s' not found", code)
+		return nil, fmt.Errorf("family '%s' %w", code, ErrNotFound)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -1307,8 +1597,8 @@ func (c *Client) GetFamily(code string) (Family, error) {
 }
 
 // PatchFamily creates or updates a family
-func (c *Client) PatchFamily(code string, familyData Family) error {
-	if err := c.ensureValidToken(); err != nil {
+func (c *Client) PatchFamily(ctx context.Context, code string, familyData Family) error {
+	if err := c.ensureValidToken(ctx); err != nil {
 		return err
 	}
 
@@ -1322,15 +1612,15 @@ func (c *Client) PatchFamily(code string, familyData Family) error {
 
 	url := fmt.Sprintf("%s/api/rest/v1/families/%s", c.config.Host, code)
 
-	req, err := http.NewRequest("PATCH", url, bytes.NewReader(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(jsonData))
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Authorization", "Bearer "+c.token())
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
@@ -1342,7 +1632,7 @@ func (c *Client) PatchFamily(code string, familyData Family) error {
 		if resp.StatusCode == http.StatusUnprocessableEntity {
 			var errorResponse AkeneoErrorResponse
 			if parseErr := json.Unmarshal(body, &errorResponse); parseErr == nil {
-				return fmt.Errorf("validation error in family %s: %s", code, c.formatAkeneoErrors(errorResponse))
+				return newValidationError("family "+code, errorResponse)
 			}
 		}
 
@@ -1374,8 +1664,8 @@ func (c *Client) cleanFamily(familyData Family) Family {
 type FamilyVariant map[string]interface{}
 
 // GetFamilyVariants retrieves all variants for a family
-func (c *Client) GetFamilyVariants(familyCode string) ([]FamilyVariant, error) {
-	if err := c.ensureValidToken(); err != nil {
+func (c *Client) GetFamilyVariants(ctx context.Context, familyCode string) ([]FamilyVariant, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
 		return nil, err
 	}
 
@@ -1387,22 +1677,22 @@ func (c *Client) GetFamilyVariants(familyCode string) ([]FamilyVariant, error) {
 		url := fmt.Sprintf("%s/api/rest/v1/families/%s/variants?page=%d&limit=%d",
 			c.config.Host, familyCode, page, limit)
 
-		req, err := http.NewRequest("GET", url, nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 		if err != nil {
 			return nil, err
 		}
 
-		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+		req.Header.Set("Authorization", "Bearer "+c.token())
 		req.Header.Set("Content-Type", "application/json")
 
-		resp, err := c.httpClient.Do(req)
+		resp, err := c.do(req)
 		if err != nil {
 			return nil, err
 		}
 		defer func() { _ = resp.Body.Close() }()
 
 		if resp.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("family '%s' not found or has no variants", familyCode)
+			return nil, fmt.Errorf("family '%s' %w or has no variants", familyCode, ErrNotFound)
 		}
 
 		if resp.StatusCode != http.StatusOK {
@@ -1439,8 +1729,8 @@ func (c *Client) GetFamilyVariants(familyCode string) ([]FamilyVariant, error) {
 }
 
 // PatchFamilyVariant creates or updates a family variant
-func (c *Client) PatchFamilyVariant(familyCode, variantCode string, variant FamilyVariant) error {
-	if err := c.ensureValidToken(); err != nil {
+func (c *Client) PatchFamilyVariant(ctx context.Context, familyCode, variantCode string, variant FamilyVariant) error {
+	if err := c.ensureValidToken(ctx); err != nil {
 		return err
 	}
 
@@ -1455,15 +1745,15 @@ func (c *Client) PatchFamilyVariant(familyCode, variantCode string, variant Fami
 	url := fmt.Sprintf("%s/api/rest/v1/families/%s/variants/%s",
 		c.config.Host, familyCode, variantCode)
 
-	req, err := http.NewRequest("PATCH", url, bytes.NewReader(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(jsonData))
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Authorization", "Bearer "+c.token())
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
@@ -1475,7 +1765,7 @@ func (c *Client) PatchFamilyVariant(familyCode, variantCode string, variant Fami
 		if resp.StatusCode == http.StatusUnprocessableEntity {
 			var errorResponse AkeneoErrorResponse
 			if parseErr := json.Unmarshal(body, &errorResponse); parseErr == nil {
-				return fmt.Errorf("validation error in family variant %s: %s", variantCode, c.formatAkeneoErrors(errorResponse))
+				return newValidationError("family variant "+variantCode, errorResponse)
 			}
 		}
 
@@ -1504,169 +1794,189 @@ func (c *Client) cleanFamilyVariant(variant FamilyVariant) FamilyVariant {
 }
 
 // GetProductsUpdatedSince retrieves all products updated since a specific date
-func (c *Client) GetProductsUpdatedSince(updatedSince string) ([]Product, error) {
-	if err := c.ensureValidToken(); err != nil {
+func (c *Client) GetProductsUpdatedSince(ctx context.Context, updatedSince string) ([]Product, error) {
+	// Filter by updated date (get ALL products, including variants)
+	searchQuery, err := updatedSearchQuery(updatedSince, "")
+	if err != nil {
 		return nil, err
 	}
 
-	// Parse the input date and ensure it's in UTC
-	var parsedTime time.Time
-	var err error
+	var allProducts []Product
+
+	params := url.Values{}
+	params.Set("search", searchQuery)
 
-	// Try parsing with timezone first
-	parsedTime, err = time.Parse(time.RFC3339, updatedSince)
+	err = streamSearchAfter(ctx, c, "products", params, defaultPageSize, "updated products", func(items []Product) error {
+		allProducts = append(allProducts, items...)
+		return nil
+	})
 	if err != nil {
-		// Try parsing without timezone (assume UTC)
-		parsedTime, err = time.Parse("2006-01-02T15:04:05", updatedSince)
-		if err != nil {
-			// Try with space instead of T
-			parsedTime, err = time.Parse("2006-01-02 15:04:05", updatedSince)
-			if err != nil {
-				return nil, fmt.Errorf("invalid date format: %s (expected ISO 8601 format like 2024-01-01T00:00:00)", updatedSince)
-			}
-		}
+		return nil, err
 	}
 
-	utcTime := parsedTime.UTC()
-	akeneoDate := utcTime.Format("2006-01-02 15:04:05")
+	c.logf(LevelDebug, "🔍 [DEBUG] Total products fetched: %d\n", len(allProducts))
+	return allProducts, nil
+}
 
-	var allProducts []Product
-	page := 1
-	limit := 100
+// GetProductModelsUpdatedSince retrieves all product models updated since a specific date
+func (c *Client) GetProductModelsUpdatedSince(ctx context.Context, updatedSince string) ([]ProductModel, error) {
+	// Filter by updated date (get ALL models, including child models)
+	searchQuery, err := updatedSearchQuery(updatedSince, "")
+	if err != nil {
+		return nil, err
+	}
 
-	for {
-		// Build search query JSON: filter by updated date (get ALL products, including variants)
-		searchQuery := fmt.Sprintf(
-			`{"updated":[{"operator":">","value":"%s"}]}`,
-			akeneoDate,
-		)
-
-		// Build URL using url.Values for proper encoding
-		baseURL := fmt.Sprintf("%s/api/rest/v1/products", c.config.Host)
-		params := url.Values{}
-		params.Add("search", searchQuery)
-		params.Add("page", fmt.Sprintf("%d", page))
-		params.Add("limit", fmt.Sprintf("%d", limit))
-
-		fullURL := baseURL + "?" + params.Encode()
-
-		req, err := http.NewRequest("GET", fullURL, nil)
-		if err != nil {
-			return nil, err
-		}
+	var allModels []ProductModel
 
-		req.Header.Set("Authorization", "Bearer "+c.accessToken)
-		req.Header.Set("Content-Type", "application/json")
+	params := url.Values{}
+	params.Set("search", searchQuery)
 
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			return nil, err
-		}
-		defer func() { _ = resp.Body.Close() }()
+	err = streamSearchAfter(ctx, c, "product-models", params, defaultPageSize, "updated product models", func(items []ProductModel) error {
+		allModels = append(allModels, items...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			return nil, fmt.Errorf("error fetching updated products: %d - %s", resp.StatusCode, string(body))
-		}
+	return allModels, nil
+}
 
-		var response struct {
-			Embedded struct {
-				Items []Product `json:"items"`
-			} `json:"_embedded"`
-			Links struct {
-				Next *struct {
-					Href string `json:"href"`
-				} `json:"next"`
-			} `json:"_links"`
-		}
+// StreamProductsUpdatedSince processes products updated since a specific date in batches
+// An optional updatedUntil closes the window. The callback is called for each page of
+// results, allowing memory-efficient processing
+func (c *Client) StreamProductsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]Product) error) error {
+	searchQuery, err := updatedSearchQuery(updatedSince, updatedUntil)
+	if err != nil {
+		return err
+	}
 
-		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-			return nil, err
+	params := url.Values{}
+	params.Set("search", searchQuery)
+
+	// Process each batch immediately via callback
+	return streamSearchAfter(ctx, c, "products", params, batchSize, "updated products", func(items []Product) error {
+		if err := callback(items); err != nil {
+			return fmt.Errorf("error processing batch: %w", err)
 		}
+		return nil
+	})
+}
 
-		allProducts = append(allProducts, response.Embedded.Items...)
+// StreamProductsBySearch processes the products matching a product query search filter in batches.
+// The filter is sent as is in the search parameter, e.g. {"enabled":[{"operator":"=","value":true}]}.
+// The callback is called for each page of results, allowing memory-efficient processing
+func (c *Client) StreamProductsBySearch(ctx context.Context, search string, batchSize int, callback func([]Product) error) error {
+	params := url.Values{}
+	params.Set("search", search)
 
-		if response.Links.Next == nil {
-			break
+	return streamSearchAfter(ctx, c, "products", params, batchSize, "products by search", func(items []Product) error {
+		if err := callback(items); err != nil {
+			return fmt.Errorf("error processing batch: %w", err)
 		}
+		return nil
+	})
+}
 
-		page++
+// StreamProductModelsUpdatedSince processes product models updated since a specific date in batches
+// An optional updatedUntil closes the window. The callback is called for each page of
+// results, allowing memory-efficient processing
+func (c *Client) StreamProductModelsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]ProductModel) error) error {
+	searchQuery, err := updatedSearchQuery(updatedSince, updatedUntil)
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("🔍 [DEBUG] Total products fetched: %d\n", len(allProducts))
-	return allProducts, nil
+	params := url.Values{}
+	params.Set("search", searchQuery)
+
+	// Process each batch immediately via callback
+	return streamSearchAfter(ctx, c, "product-models", params, batchSize, "updated product models", func(items []ProductModel) error {
+		if err := callback(items); err != nil {
+			return fmt.Errorf("error processing batch: %w", err)
+		}
+		return nil
+	})
 }
 
-// GetProductModelsUpdatedSince retrieves all product models updated since a specific date
-func (c *Client) GetProductModelsUpdatedSince(updatedSince string) ([]ProductModel, error) {
-	if err := c.ensureValidToken(); err != nil {
-		return nil, err
+// updatedSearchQuery builds the search filter for items updated after updatedSince and,
+// when updatedUntil is set, up to updatedUntil included. Consecutive windows sharing a bound
+// therefore never overlap nor leave gaps.
+func updatedSearchQuery(updatedSince, updatedUntil string) (string, error) {
+	since, err := toAkeneoDate(updatedSince)
+	if err != nil {
+		return "", err
 	}
 
-	// Parse the input date and ensure it's in UTC
-	var parsedTime time.Time
-	var err error
+	if updatedUntil == "" {
+		return fmt.Sprintf(`{"updated":[{"operator":">","value":"%s"}]}`, since), nil
+	}
 
-	// Try parsing with timezone first
-	parsedTime, err = time.Parse(time.RFC3339, updatedSince)
+	until, err := toAkeneoDate(updatedUntil)
 	if err != nil {
-		// Try parsing without timezone (assume UTC)
-		parsedTime, err = time.Parse("2006-01-02T15:04:05", updatedSince)
-		if err != nil {
-			// Try with space instead of T
-			parsedTime, err = time.Parse("2006-01-02 15:04:05", updatedSince)
-			if err != nil {
-				return nil, fmt.Errorf("invalid date format: %s (expected ISO 8601 format like 2024-01-01T00:00:00)", updatedSince)
-			}
+		return "", err
+	}
+
+	return fmt.Sprintf(
+		`{"updated":[{"operator":">","value":"%s"},{"operator":"BETWEEN","value":["%s","%s"]}]}`,
+		since, since, until,
+	), nil
+}
+
+// toAkeneoDate converts an ISO 8601 date to the UTC yyyy-mm-dd hh:mm:ss format expected by search filters.
+// Dates without timezone are assumed to be UTC; seconds may be omitted, as in the dates sent by the web UI
+func toAkeneoDate(value string) (string, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02T15:04"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.UTC().Format("2006-01-02 15:04:05"), nil
 		}
 	}
+	return "", fmt.Errorf("invalid date format: %s (expected ISO 8601 format like 2024-01-01T00:00:00)", value)
+}
 
-	// Convert to UTC and format as yyyy-mm-dd hh:mm:ss (Akeneo API format per documentation)
-	utcTime := parsedTime.UTC()
-	akeneoDate := utcTime.Format("2006-01-02 15:04:05") // Format: yyyy-mm-dd hh:mm:ss
+// AttributeOption represents an attribute option
+type AttributeOption map[string]interface{}
 
-	var allModels []ProductModel
+// GetAttributeOptions retrieves all options for an attribute
+func (c *Client) GetAttributeOptions(ctx context.Context, attributeCode string) ([]AttributeOption, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return nil, err
+	}
+
+	var allOptions []AttributeOption
 	page := 1
 	limit := 100
 
 	for {
-		// Build search query JSON: filter by updated date (get ALL models, including child models)
-		searchQuery := fmt.Sprintf(
-			`{"updated":[{"operator":">","value":"%s"}]}`,
-			akeneoDate,
-		)
-
-		// Build URL using url.Values for proper encoding
-		baseURL := fmt.Sprintf("%s/api/rest/v1/product-models", c.config.Host)
-		params := url.Values{}
-		params.Add("search", searchQuery)
-		params.Add("page", fmt.Sprintf("%d", page))
-		params.Add("limit", fmt.Sprintf("%d", limit))
-
-		fullURL := baseURL + "?" + params.Encode()
-
-		req, err := http.NewRequest("GET", fullURL, nil)
+		url := fmt.Sprintf("%s/api/rest/v1/attributes/%s/options?page=%d&limit=%d",
+			c.config.Host, attributeCode, page, limit)
+
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 		if err != nil {
 			return nil, err
 		}
 
-		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+		req.Header.Set("Authorization", "Bearer "+c.token())
 		req.Header.Set("Content-Type", "application/json")
 
-		resp, err := c.httpClient.Do(req)
+		resp, err := c.do(req)
 		if err != nil {
 			return nil, err
 		}
 		defer func() { _ = resp.Body.Close() }()
 
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("attribute '%s' %w or has no options", attributeCode, ErrNotFound)
+		}
+
 		if resp.StatusCode != http.StatusOK {
 			body, _ := io.ReadAll(resp.Body)
-			return nil, fmt.Errorf("error fetching updated product models: %d - %s", resp.StatusCode, string(body))
+			return nil, fmt.Errorf("error fetching attribute options: %d - %s", resp.StatusCode, string(body))
 		}
 
 		var response struct {
 			Embedded struct {
-				Items []ProductModel `json:"items"`
+				Items []AttributeOption `json:"items"`
 			} `json:"_embedded"`
 			Links struct {
 				Next *struct {
@@ -1679,8 +1989,9 @@ func (c *Client) GetProductModelsUpdatedSince(updatedSince string) ([]ProductMod
 			return nil, err
 		}
 
-		allModels = append(allModels, response.Embedded.Items...)
+		allOptions = append(allOptions, response.Embedded.Items...)
 
+		// If no next page, finish
 		if response.Links.Next == nil {
 			break
 		}
@@ -1688,181 +1999,238 @@ func (c *Client) GetProductModelsUpdatedSince(updatedSince string) ([]ProductMod
 		page++
 	}
 
-	return allModels, nil
+	return allOptions, nil
 }
 
-// StreamProductsUpdatedSince processes products updated since a specific date in batches
-// The callback is called for each page of results, allowing memory-efficient processing
-func (c *Client) StreamProductsUpdatedSince(updatedSince string, batchSize int, callback func([]Product) error) error {
-	if err := c.ensureValidToken(); err != nil {
+// PatchAttributeOption creates or updates an attribute option
+func (c *Client) PatchAttributeOption(ctx context.Context, attributeCode, optionCode string, option AttributeOption) error {
+	if err := c.ensureValidToken(ctx); err != nil {
 		return err
 	}
 
-	// Parse the input date and ensure it's in UTC
-	var parsedTime time.Time
-	var err error
+	// Clean fields that should not be sent
+	cleanOption := c.cleanAttributeOption(option)
 
-	// Try parsing with timezone first
-	parsedTime, err = time.Parse(time.RFC3339, updatedSince)
+	jsonData, err := json.Marshal(cleanOption)
 	if err != nil {
-		// Try parsing without timezone (assume UTC)
-		parsedTime, err = time.Parse("2006-01-02T15:04:05", updatedSince)
-		if err != nil {
-			// Try with space instead of T
-			parsedTime, err = time.Parse("2006-01-02 15:04:05", updatedSince)
-			if err != nil {
-				return fmt.Errorf("invalid date format: %s (expected ISO 8601 format like 2024-01-01T00:00:00)", updatedSince)
-			}
-		}
+		return err
 	}
 
-	// Convert to UTC and format as yyyy-mm-dd hh:mm:ss (Akeneo API format per documentation)
-	utcTime := parsedTime.UTC()
-	akeneoDate := utcTime.Format("2006-01-02 15:04:05") // Format: yyyy-mm-dd hh:mm:ss
-
-	page := 1
-	limit := batchSize
+	url := fmt.Sprintf("%s/api/rest/v1/attributes/%s/options/%s",
+		c.config.Host, attributeCode, optionCode)
 
-	for {
-		// Build search query JSON: filter by updated date (get ALL products, including variants)
-		searchQuery := fmt.Sprintf(
-			`{"updated":[{"operator":">","value":"%s"}]}`,
-			akeneoDate,
-		)
-
-		// Build URL using url.Values for proper encoding
-		baseURL := fmt.Sprintf("%s/api/rest/v1/products", c.config.Host)
-		params := url.Values{}
-		params.Add("search", searchQuery)
-		params.Add("page", fmt.Sprintf("%d", page))
-		params.Add("limit", fmt.Sprintf("%d", limit))
-
-		fullURL := baseURL + "?" + params.Encode()
-
-		req, err := http.NewRequest("GET", fullURL, nil)
-		if err != nil {
-			return err
-		}
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(jsonData))
+	if err != nil {
+		return err
+	}
 
-		req.Header.Set("Authorization", "Bearer "+c.accessToken)
-		req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token())
+	req.Header.Set("Content-Type", "application/json")
 
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			return err
-		}
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
 
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			_ = resp.Body.Close()
-			return fmt.Errorf("error fetching updated products: %d - %s", resp.StatusCode, string(body))
-		}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
 
-		var response struct {
-			Embedded struct {
-				Items []Product `json:"items"`
-			} `json:"_embedded"`
-			Links struct {
-				Next *struct {
-					Href string `json:"href"`
-				} `json:"next"`
-			} `json:"_links"`
+		if resp.StatusCode == http.StatusUnprocessableEntity {
+			var errorResponse AkeneoErrorResponse
+			if parseErr := json.Unmarshal(body, &errorResponse); parseErr == nil {
+				return newValidationError("attribute option "+optionCode, errorResponse)
+			}
 		}
 
-		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-			_ = resp.Body.Close()
-			return err
-		}
-		_ = resp.Body.Close()
+		return fmt.Errorf("error updating attribute option %s: %d - %s", optionCode, resp.StatusCode, string(body))
+	}
 
-		// Process this batch immediately via callback
-		if len(response.Embedded.Items) > 0 {
-			if err := callback(response.Embedded.Items); err != nil {
-				return fmt.Errorf("error processing batch: %w", err)
-			}
-		}
+	return nil
+}
 
-		// Check if there are more pages
-		if response.Links.Next == nil {
-			break
+// cleanAttributeOption removes fields that should not be sent in write operations
+func (c *Client) cleanAttributeOption(option AttributeOption) AttributeOption {
+	cleaned := make(AttributeOption)
+
+	// List of fields to exclude
+	excludedFields := map[string]bool{
+		"_links":    true,
+		"attribute": true, // Attribute code is in the URL
+	}
+
+	for key, value := range option {
+		if !excludedFields[key] && value != nil {
+			cleaned[key] = value
 		}
+	}
 
-		page++
+	return cleaned
+}
+
+// Channel represents a channel
+type Channel map[string]interface{}
+
+// Locale represents a locale
+type Locale map[string]interface{}
+
+// Currency represents a currency
+type Currency map[string]interface{}
+
+// GetChannels retrieves every channel, following the pages of the list
+func (c *Client) GetChannels(ctx context.Context) ([]Channel, error) {
+	var channels []Channel
+
+	requestURI := fmt.Sprintf("/api/rest/v1/channels?limit=%d", defaultPageSize)
+	err := streamPages(ctx, c, requestURI, "channels", func(page []Channel) error {
+		channels = append(channels, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return channels, nil
 }
 
-// StreamProductModelsUpdatedSince processes product models updated since a specific date in batches
-// The callback is called for each page of results, allowing memory-efficient processing
-func (c *Client) StreamProductModelsUpdatedSince(updatedSince string, batchSize int, callback func([]ProductModel) error) error {
-	if err := c.ensureValidToken(); err != nil {
+// GetChannel retrieves a channel by its code
+func (c *Client) GetChannel(ctx context.Context, code string) (Channel, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/api/rest/v1/channels/%s", c.config.Host, code)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token())
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("channel '%s' %w", code, ErrNotFound)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("error fetching channel: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var channel Channel
+	if err := json.NewDecoder(resp.Body).Decode(&channel); err != nil {
+		return nil, err
+	}
+
+	return channel, nil
+}
+
+// PatchChannel creates or updates a channel
+func (c *Client) PatchChannel(ctx context.Context, code string, channel Channel) error {
+	if err := c.ensureValidToken(ctx); err != nil {
 		return err
 	}
 
-	// Parse the input date and ensure it's in UTC
-	var parsedTime time.Time
-	var err error
+	// Clean fields that should not be sent
+	cleanChannel := c.cleanChannel(channel)
 
-	// Try parsing with timezone first
-	parsedTime, err = time.Parse(time.RFC3339, updatedSince)
+	jsonData, err := json.Marshal(cleanChannel)
 	if err != nil {
-		// Try parsing without timezone (assume UTC)
-		parsedTime, err = time.Parse("2006-01-02T15:04:05", updatedSince)
-		if err != nil {
-			// Try with space instead of T
-			parsedTime, err = time.Parse("2006-01-02 15:04:05", updatedSince)
-			if err != nil {
-				return fmt.Errorf("invalid date format: %s (expected ISO 8601 format like 2024-01-01T00:00:00)", updatedSince)
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/rest/v1/channels/%s", c.config.Host, code)
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(jsonData))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token())
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+
+		if resp.StatusCode == http.StatusUnprocessableEntity {
+			var errorResponse AkeneoErrorResponse
+			if parseErr := json.Unmarshal(body, &errorResponse); parseErr == nil {
+				return newValidationError("channel "+code, errorResponse)
 			}
 		}
+
+		return fmt.Errorf("error updating channel %s: %d - %s", code, resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// cleanChannel removes fields that should not be sent in write operations
+func (c *Client) cleanChannel(channel Channel) Channel {
+	cleaned := make(Channel)
+
+	// List of fields to exclude (metadata fields from API responses)
+	excludedFields := map[string]bool{
+		"_links": true,
+	}
+
+	for key, value := range channel {
+		if !excludedFields[key] && value != nil {
+			cleaned[key] = value
+		}
 	}
 
-	// Convert to UTC and format as yyyy-mm-dd hh:mm:ss (Akeneo API format per documentation)
-	utcTime := parsedTime.UTC()
-	akeneoDate := utcTime.Format("2006-01-02 15:04:05") // Format: yyyy-mm-dd hh:mm:ss
+	return cleaned
+}
+
+// GetLocales retrieves all locales, activated or not
+func (c *Client) GetLocales(ctx context.Context) ([]Locale, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return nil, err
+	}
 
+	var allLocales []Locale
 	page := 1
-	limit := batchSize
+	limit := 100
 
 	for {
-		// Build search query JSON: filter by updated date (get ALL models, including child models)
-		searchQuery := fmt.Sprintf(
-			`{"updated":[{"operator":">","value":"%s"}]}`,
-			akeneoDate,
-		)
-
-		// Build URL using url.Values for proper encoding
-		baseURL := fmt.Sprintf("%s/api/rest/v1/product-models", c.config.Host)
-		params := url.Values{}
-		params.Add("search", searchQuery)
-		params.Add("page", fmt.Sprintf("%d", page))
-		params.Add("limit", fmt.Sprintf("%d", limit))
-
-		fullURL := baseURL + "?" + params.Encode()
-
-		req, err := http.NewRequest("GET", fullURL, nil)
+		url := fmt.Sprintf("%s/api/rest/v1/locales?page=%d&limit=%d", c.config.Host, page, limit)
+
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+		req.Header.Set("Authorization", "Bearer "+c.token())
 		req.Header.Set("Content-Type", "application/json")
 
-		resp, err := c.httpClient.Do(req)
+		resp, err := c.do(req)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		defer func() { _ = resp.Body.Close() }()
 
 		if resp.StatusCode != http.StatusOK {
 			body, _ := io.ReadAll(resp.Body)
-			_ = resp.Body.Close()
-			return fmt.Errorf("error fetching updated product models: %d - %s", resp.StatusCode, string(body))
+			return nil, fmt.Errorf("error fetching locales: %d - %s", resp.StatusCode, string(body))
 		}
 
 		var response struct {
 			Embedded struct {
-				Items []ProductModel `json:"items"`
+				Items []Locale `json:"items"`
 			} `json:"_embedded"`
 			Links struct {
 				Next *struct {
@@ -1872,19 +2240,11 @@ func (c *Client) StreamProductModelsUpdatedSince(updatedSince string, batchSize
 		}
 
 		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-			_ = resp.Body.Close()
-			return err
+			return nil, err
 		}
-		_ = resp.Body.Close()
 
-		// Process this batch immediately via callback
-		if len(response.Embedded.Items) > 0 {
-			if err := callback(response.Embedded.Items); err != nil {
-				return fmt.Errorf("error processing batch: %w", err)
-			}
-		}
+		allLocales = append(allLocales, response.Embedded.Items...)
 
-		// Check if there are more pages
 		if response.Links.Next == nil {
 			break
 		}
@@ -1892,52 +2252,83 @@ func (c *Client) StreamProductModelsUpdatedSince(updatedSince string, batchSize
 		page++
 	}
 
-	return nil
+	return allLocales, nil
 }
 
-// AttributeOption represents an attribute option
-type AttributeOption map[string]interface{}
+// GetCurrency retrieves a currency by its code
+func (c *Client) GetCurrency(ctx context.Context, code string) (Currency, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return nil, err
+	}
 
-// GetAttributeOptions retrieves all options for an attribute
-func (c *Client) GetAttributeOptions(attributeCode string) ([]AttributeOption, error) {
-	if err := c.ensureValidToken(); err != nil {
+	url := fmt.Sprintf("%s/api/rest/v1/currencies/%s", c.config.Host, code)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
 		return nil, err
 	}
 
-	var allOptions []AttributeOption
+	req.Header.Set("Authorization", "Bearer "+c.token())
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("currency '%s' %w", code, ErrNotFound)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("error fetching currency: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var currency Currency
+	if err := json.NewDecoder(resp.Body).Decode(&currency); err != nil {
+		return nil, err
+	}
+
+	return currency, nil
+}
+
+// GetCurrencies retrieves all currencies, activated or not
+func (c *Client) GetCurrencies(ctx context.Context) ([]Currency, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return nil, err
+	}
+
+	var allCurrencies []Currency
 	page := 1
 	limit := 100
 
 	for {
-		url := fmt.Sprintf("%s/api/rest/v1/attributes/%s/options?page=%d&limit=%d",
-			c.config.Host, attributeCode, page, limit)
+		url := fmt.Sprintf("%s/api/rest/v1/currencies?page=%d&limit=%d", c.config.Host, page, limit)
 
-		req, err := http.NewRequest("GET", url, nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 		if err != nil {
 			return nil, err
 		}
 
-		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+		req.Header.Set("Authorization", "Bearer "+c.token())
 		req.Header.Set("Content-Type", "application/json")
 
-		resp, err := c.httpClient.Do(req)
+		resp, err := c.do(req)
 		if err != nil {
 			return nil, err
 		}
 		defer func() { _ = resp.Body.Close() }()
 
-		if resp.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("attribute '%s' not found or has no options", attributeCode)
-		}
-
 		if resp.StatusCode != http.StatusOK {
 			body, _ := io.ReadAll(resp.Body)
-			return nil, fmt.Errorf("error fetching attribute options: %d - %s", resp.StatusCode, string(body))
+			return nil, fmt.Errorf("error fetching currencies: %d - %s", resp.StatusCode, string(body))
 		}
 
 		var response struct {
 			Embedded struct {
-				Items []AttributeOption `json:"items"`
+				Items []Currency `json:"items"`
 			} `json:"_embedded"`
 			Links struct {
 				Next *struct {
@@ -1950,9 +2341,8 @@ func (c *Client) GetAttributeOptions(attributeCode string) ([]AttributeOption, e
 			return nil, err
 		}
 
-		allOptions = append(allOptions, response.Embedded.Items...)
+		allCurrencies = append(allCurrencies, response.Embedded.Items...)
 
-		// If no next page, finish
 		if response.Links.Next == nil {
 			break
 		}
@@ -1960,35 +2350,76 @@ func (c *Client) GetAttributeOptions(attributeCode string) ([]AttributeOption, e
 		page++
 	}
 
-	return allOptions, nil
+	return allCurrencies, nil
 }
 
-// PatchAttributeOption creates or updates an attribute option
-func (c *Client) PatchAttributeOption(attributeCode, optionCode string, option AttributeOption) error {
-	if err := c.ensureValidToken(); err != nil {
+// AttributeGroup represents an attribute group
+type AttributeGroup map[string]interface{}
+
+// GetAttributeGroup retrieves an attribute group by its code
+func (c *Client) GetAttributeGroup(ctx context.Context, code string) (AttributeGroup, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/api/rest/v1/attribute-groups/%s", c.config.Host, code)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token())
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("attribute group '%s' %w", code, ErrNotFound)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("error fetching attribute group: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var group AttributeGroup
+	if err := json.NewDecoder(resp.Body).Decode(&group); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// PatchAttributeGroup creates or updates an attribute group
+func (c *Client) PatchAttributeGroup(ctx context.Context, code string, group AttributeGroup) error {
+	if err := c.ensureValidToken(ctx); err != nil {
 		return err
 	}
 
 	// Clean fields that should not be sent
-	cleanOption := c.cleanAttributeOption(option)
+	cleanGroup := c.cleanAttributeGroup(group)
 
-	jsonData, err := json.Marshal(cleanOption)
+	jsonData, err := json.Marshal(cleanGroup)
 	if err != nil {
 		return err
 	}
 
-	url := fmt.Sprintf("%s/api/rest/v1/attributes/%s/options/%s",
-		c.config.Host, attributeCode, optionCode)
+	url := fmt.Sprintf("%s/api/rest/v1/attribute-groups/%s", c.config.Host, code)
 
-	req, err := http.NewRequest("PATCH", url, bytes.NewReader(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(jsonData))
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Authorization", "Bearer "+c.token())
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
@@ -2000,27 +2431,284 @@ func (c *Client) PatchAttributeOption(attributeCode, optionCode string, option A
 		if resp.StatusCode == http.StatusUnprocessableEntity {
 			var errorResponse AkeneoErrorResponse
 			if parseErr := json.Unmarshal(body, &errorResponse); parseErr == nil {
-				return fmt.Errorf("validation error in attribute option %s: %s", optionCode, c.formatAkeneoErrors(errorResponse))
+				return newValidationError("attribute group "+code, errorResponse)
 			}
 		}
 
-		return fmt.Errorf("error updating attribute option %s: %d - %s", optionCode, resp.StatusCode, string(body))
+		return fmt.Errorf("error updating attribute group %s: %d - %s", code, resp.StatusCode, string(body))
 	}
 
 	return nil
 }
 
-// cleanAttributeOption removes fields that should not be sent in write operations
-func (c *Client) cleanAttributeOption(option AttributeOption) AttributeOption {
-	cleaned := make(AttributeOption)
+// cleanAttributeGroup removes fields that should not be sent in write operations
+func (c *Client) cleanAttributeGroup(group AttributeGroup) AttributeGroup {
+	cleaned := make(AttributeGroup)
 
-	// List of fields to exclude
+	// List of fields to exclude (metadata fields from API responses)
 	excludedFields := map[string]bool{
-		"_links":    true,
-		"attribute": true, // Attribute code is in the URL
+		"_links": true,
 	}
 
-	for key, value := range option {
+	for key, value := range group {
+		if !excludedFields[key] && value != nil {
+			cleaned[key] = value
+		}
+	}
+
+	return cleaned
+}
+
+// MeasurementFamily represents a measurement family with its units
+type MeasurementFamily map[string]interface{}
+
+// GetMeasurementFamilies retrieves all measurement families; the endpoint is not paginated
+func (c *Client) GetMeasurementFamilies(ctx context.Context) ([]MeasurementFamily, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/api/rest/v1/measurement-families", c.config.Host)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token())
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("error fetching measurement families: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var families []MeasurementFamily
+	if err := json.NewDecoder(resp.Body).Decode(&families); err != nil {
+		return nil, err
+	}
+
+	return families, nil
+}
+
+// PatchMeasurementFamilies creates or updates several measurement families, up to 100 per call.
+// It returns the errors of the families that were not written, indexed by code.
+func (c *Client) PatchMeasurementFamilies(ctx context.Context, families []MeasurementFamily) (map[string]error, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return nil, err
+	}
+
+	failed := make(map[string]error)
+
+	for start := 0; start < len(families); start += maxCollectionSize {
+		end := start + maxCollectionSize
+		if end > len(families) {
+			end = len(families)
+		}
+
+		codes := make([]string, 0, end-start)
+		chunk := make([]MeasurementFamily, 0, end-start)
+		for _, family := range families[start:end] {
+			code, _ := family["code"].(string)
+			if code == "" {
+				return nil, fmt.Errorf("measurement family without code cannot be sent")
+			}
+			codes = append(codes, code)
+			chunk = append(chunk, c.cleanMeasurementFamily(family))
+		}
+
+		statuses, err := c.sendMeasurementFamilies(ctx, chunk)
+		if err != nil {
+			for _, code := range codes {
+				failed[code] = err
+			}
+			continue
+		}
+
+		for _, status := range statuses {
+			if status.StatusCode >= http.StatusBadRequest {
+				failed[status.Code] = lineError("measurement family "+status.Code, status)
+			}
+		}
+	}
+
+	return failed, nil
+}
+
+// sendMeasurementFamilies sends one chunk of measurement families and decodes the status of each family
+func (c *Client) sendMeasurementFamilies(ctx context.Context, families []MeasurementFamily) ([]CollectionLineResult, error) {
+	jsonData, err := json.Marshal(families)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/api/rest/v1/measurement-families", c.config.Host)
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token())
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("error updating measurement families: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var statuses []CollectionLineResult
+	if err := json.NewDecoder(resp.Body).Decode(&statuses); err != nil {
+		return nil, fmt.Errorf("error decoding measurement families response: %w", err)
+	}
+
+	return statuses, nil
+}
+
+// cleanMeasurementFamily removes fields that should not be sent in write operations
+func (c *Client) cleanMeasurementFamily(family MeasurementFamily) MeasurementFamily {
+	cleaned := make(MeasurementFamily)
+
+	// List of fields to exclude (metadata fields from API responses)
+	excludedFields := map[string]bool{
+		"_links": true,
+	}
+
+	for key, value := range family {
+		if !excludedFields[key] && value != nil {
+			cleaned[key] = value
+		}
+	}
+
+	return cleaned
+}
+
+// AssociationType represents an association type
+type AssociationType map[string]interface{}
+
+// GetAssociationTypes retrieves every association type, following the pages of the list
+func (c *Client) GetAssociationTypes(ctx context.Context) ([]AssociationType, error) {
+	var associationTypes []AssociationType
+
+	requestURI := fmt.Sprintf("/api/rest/v1/association-types?limit=%d", defaultPageSize)
+	err := streamPages(ctx, c, requestURI, "association types", func(page []AssociationType) error {
+		associationTypes = append(associationTypes, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return associationTypes, nil
+}
+
+// GetAssociationType retrieves an association type by its code
+func (c *Client) GetAssociationType(ctx context.Context, code string) (AssociationType, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/api/rest/v1/association-types/%s", c.config.Host, code)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token())
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("association type '%s' %w", code, ErrNotFound)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("error fetching association type: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var associationType AssociationType
+	if err := json.NewDecoder(resp.Body).Decode(&associationType); err != nil {
+		return nil, err
+	}
+
+	return associationType, nil
+}
+
+// PatchAssociationType creates or updates an association type
+func (c *Client) PatchAssociationType(ctx context.Context, code string, associationType AssociationType) error {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return err
+	}
+
+	// Clean fields that should not be sent
+	cleanAssociationType := c.cleanAssociationType(associationType)
+
+	jsonData, err := json.Marshal(cleanAssociationType)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/rest/v1/association-types/%s", c.config.Host, code)
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(jsonData))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token())
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+
+		if resp.StatusCode == http.StatusUnprocessableEntity {
+			var errorResponse AkeneoErrorResponse
+			if parseErr := json.Unmarshal(body, &errorResponse); parseErr == nil {
+				return newValidationError("association type "+code, errorResponse)
+			}
+		}
+
+		return fmt.Errorf("error updating association type %s: %d - %s", code, resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// cleanAssociationType removes fields that should not be sent in write operations
+func (c *Client) cleanAssociationType(associationType AssociationType) AssociationType {
+	cleaned := make(AssociationType)
+
+	// List of fields to exclude (metadata fields from API responses)
+	excludedFields := map[string]bool{
+		"_links": true,
+	}
+
+	for key, value := range associationType {
 		if !excludedFields[key] && value != nil {
 			cleaned[key] = value
 		}
@@ -2028,3 +2716,130 @@ func (c *Client) cleanAttributeOption(option AttributeOption) AttributeOption {
 
 	return cleaned
 }
+
+// MediaFileTarget is the product or product model value an uploaded media file is attached to
+type MediaFileTarget struct {
+	// Identifier is the product receiving the file; ModelCode is used when Identifier is empty
+	Identifier string
+	ModelCode  string
+	Attribute  string
+	Locale     string
+	Scope      string
+}
+
+// DownloadMediaFile downloads the content of a product media file
+func (c *Client) DownloadMediaFile(ctx context.Context, code string) ([]byte, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/api/rest/v1/media-files/%s/download", c.config.Host, code)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token())
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("media file '%s' %w", code, ErrNotFound)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("error downloading media file: %d - %s", resp.StatusCode, string(body))
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// UploadMediaFile uploads a media file attached to a value of a product or product model and
+// returns the code assigned to it. Akeneo sets the value itself, so the target must exist.
+func (c *Client) UploadMediaFile(ctx context.Context, target MediaFileTarget, filename string, content []byte) (string, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return "", err
+	}
+
+	field, owner := "product", map[string]interface{}{"identifier": target.Identifier}
+	if target.Identifier == "" {
+		field, owner = "product_model", map[string]interface{}{"code": target.ModelCode}
+	}
+	owner["attribute"] = target.Attribute
+	owner["locale"] = nullable(target.Locale)
+	owner["scope"] = nullable(target.Scope)
+
+	ownerJSON, err := json.Marshal(owner)
+	if err != nil {
+		return "", err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField(field, string(ownerJSON)); err != nil {
+		return "", err
+	}
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(content); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf("%s/api/rest/v1/media-files", c.config.Host)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, &body)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token())
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := c.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+
+		// For 422 errors, try to parse Akeneo error response
+		if resp.StatusCode == http.StatusUnprocessableEntity {
+			var errorResponse AkeneoErrorResponse
+			if parseErr := json.Unmarshal(respBody, &errorResponse); parseErr == nil {
+				return "", newValidationError("media file "+filename, errorResponse)
+			}
+		}
+
+		return "", fmt.Errorf("error uploading media file %s: %d - %s", filename, resp.StatusCode, string(respBody))
+	}
+
+	// Akeneo returns the URL of the uploaded file, whose code may contain slashes
+	location := resp.Header.Get("Location")
+	_, code, found := strings.Cut(location, "/media-files/")
+	if !found || code == "" {
+		return "", fmt.Errorf("no media file code returned for %s", filename)
+	}
+
+	return code, nil
+}
+
+// nullable converts an empty locale or scope to the JSON null expected by Akeneo
+func nullable(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,1171 @@
+package akeneo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"akeneo-migrator/internal/platform/client/cassette"
+	"akeneo-migrator/kit/dryrun"
+)
+
+// newReplayClient creates a client answering from a recorded cassette
+func newReplayClient(t *testing.T, name string) (*Client, *cassette.Replayer) {
+	t.Helper()
+
+	replayer, err := cassette.LoadReplayer("testdata/cassettes/" + name + ".json")
+	if err != nil {
+		t.Fatalf("Expected cassette to load, got %v", err)
+	}
+
+	client, err := NewClient(ClientConfig{
+		Host:      cassette.Host,
+		ClientID:  "client",
+		Secret:    "secret",
+		Username:  "user",
+		Password:  "password",
+		Transport: replayer,
+	})
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	return client, replayer
+}
+
+func TestClient_GetProduct(t *testing.T) {
+	client, _ := newReplayClient(t, "products")
+
+	product, err := client.GetProduct(context.Background(), "SKU-001")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if product["identifier"] != "SKU-001" || product["parent"] != "MODEL-001" {
+		t.Errorf("Unexpected product: %v", product)
+	}
+
+	_, err = client.GetProduct(context.Background(), "MISSING")
+	if !errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
+
+func TestClient_GetProductsByParentFollowsPages(t *testing.T) {
+	client, _ := newReplayClient(t, "products")
+
+	products, err := client.GetProductsByParent(context.Background(), "MODEL-001")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(products) != 2 {
+		t.Fatalf("Expected 2 products across both pages, got %d", len(products))
+	}
+
+	if products[1]["identifier"] != "SKU-002" {
+		t.Errorf("Expected second page product, got %v", products[1]["identifier"])
+	}
+}
+
+func TestClient_PatchProductValidationError(t *testing.T) {
+	client, _ := newReplayClient(t, "products")
+
+	err := client.PatchProduct(context.Background(), "SKU-001", Product{
+		"identifier": "SKU-001",
+		"values":     map[string]interface{}{"weight": []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "heavy"}}},
+		"updated":    "2024-03-02T10:30:00+00:00",
+	})
+
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+
+	if !strings.Contains(err.Error(), "Field 'values': This value should be a valid number.") {
+		t.Errorf("Expected field errors in message, got %q", err.Error())
+	}
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Fields) != 1 || validationErr.Item != "product SKU-001" {
+		t.Errorf("Expected a ValidationError with the rejected field, got %#v", err)
+	}
+}
+
+func TestClient_UnrecordedRequestFails(t *testing.T) {
+	client, _ := newReplayClient(t, "products")
+
+	if _, err := client.GetProductModel(context.Background(), "UNKNOWN"); err == nil {
+		t.Error("Expected error for a request missing from the cassette")
+	}
+}
+
+func TestUpdatedSearchQuery(t *testing.T) {
+	query, err := updatedSearchQuery("2024-03-01T02:00:00+01:00", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if query != `{"updated":[{"operator":">","value":"2024-03-01 01:00:00"}]}` {
+		t.Errorf("Unexpected open window query: %s", query)
+	}
+
+	query, err = updatedSearchQuery("2024-03-01T02:00:00", "2024-03-01 04:30:00")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := `{"updated":[{"operator":">","value":"2024-03-01 02:00:00"},{"operator":"BETWEEN","value":["2024-03-01 02:00:00","2024-03-01 04:30:00"]}]}`
+	if query != expected {
+		t.Errorf("Expected %s, got %s", expected, query)
+	}
+
+	query, err = updatedSearchQuery("2024-03-01T02:00", "")
+	if err != nil {
+		t.Fatalf("Expected dates without seconds to be accepted, got %v", err)
+	}
+	if query != `{"updated":[{"operator":">","value":"2024-03-01 02:00:00"}]}` {
+		t.Errorf("Unexpected query for a date without seconds: %s", query)
+	}
+
+	if _, err := updatedSearchQuery("2024-03-01T02:00:00", "yesterday"); err == nil {
+		t.Error("Expected error for invalid end date")
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func jsonResponse(status int, body string, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Content-Type", "application/json")
+	return &http.Response{StatusCode: status, Header: header, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func TestClient_ReplaysThrottledRequests(t *testing.T) {
+	patches := 0
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+		}
+
+		body, _ := io.ReadAll(req.Body)
+		if !strings.Contains(string(body), "SKU-001") {
+			t.Errorf("Expected replayed request to keep its body, got %q", body)
+		}
+
+		patches++
+		if patches == 1 {
+			return jsonResponse(http.StatusTooManyRequests, `{"code":429,"message":"Too many requests"}`, http.Header{"Retry-After": {"0"}}), nil
+		}
+		return jsonResponse(http.StatusNoContent, "", nil), nil
+	})
+
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test", Transport: transport})
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	if err := client.PatchProduct(context.Background(), "SKU-001", Product{"identifier": "SKU-001"}); err != nil {
+		t.Fatalf("Expected throttled request to succeed once replayed, got %v", err)
+	}
+
+	if patches != 2 {
+		t.Errorf("Expected 2 attempts, got %d", patches)
+	}
+}
+
+func TestClient_PatchProductsSendsCollectionsInChunks(t *testing.T) {
+	var chunks []int
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+		}
+
+		if req.Header.Get("Content-Type") != collectionContentType {
+			t.Errorf("Expected collection content type, got %s", req.Header.Get("Content-Type"))
+		}
+
+		body, _ := io.ReadAll(req.Body)
+		lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+		chunks = append(chunks, len(lines))
+
+		var response strings.Builder
+		for i := range lines {
+			if len(chunks) == 1 && i == 1 {
+				fmt.Fprintf(&response, `{"line":2,"identifier":"SKU-1","status_code":422,"message":"Validation failed.","errors":[{"property":"family","message":"Unknown family"}]}`+"\n")
+				continue
+			}
+			fmt.Fprintf(&response, `{"line":%d,"status_code":204}`+"\n", i+1)
+		}
+		return jsonResponse(http.StatusOK, response.String(), nil), nil
+	})
+
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test", Transport: transport})
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	products := make([]Product, 150)
+	for i := range products {
+		products[i] = Product{"identifier": fmt.Sprintf("SKU-%d", i), "updated": "2024-01-01T00:00:00+00:00"}
+	}
+
+	failed, err := client.PatchProducts(context.Background(), products)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(chunks) != 2 || chunks[0] != 100 || chunks[1] != 50 {
+		t.Errorf("Expected chunks of 100 and 50 items, got %v", chunks)
+	}
+
+	if len(failed) != 1 || !strings.Contains(fmt.Sprint(failed["SKU-1"]), "Unknown family") {
+		t.Errorf("Expected SKU-1 to be rejected, got %v", failed)
+	}
+}
+
+func TestClient_PatchReferenceEntityRecordsReportsRejectedRecords(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+		}
+
+		if req.URL.Path != "/api/rest/v1/reference-entities/brands/records" {
+			t.Errorf("Unexpected path %s", req.URL.Path)
+		}
+
+		body, _ := io.ReadAll(req.Body)
+		if strings.Contains(string(body), "_links") {
+			t.Errorf("Expected records to be cleaned, got %s", body)
+		}
+
+		return jsonResponse(http.StatusOK, `[{"code":"acme","status_code":204},{"code":"globex","status_code":422,"message":"Validation failed.","errors":[{"property":"values","message":"Unknown attribute"}]}]`, nil), nil
+	})
+
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test", Transport: transport})
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	failed, err := client.PatchReferenceEntityRecords(context.Background(), "brands", []ReferenceEntityRecord{
+		{"code": "acme", "_links": map[string]interface{}{}},
+		{"code": "globex"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(failed) != 1 || !strings.Contains(fmt.Sprint(failed["globex"]), "Unknown attribute") {
+		t.Errorf("Expected globex to be rejected, got %v", failed)
+	}
+}
+
+func TestClient_UploadMediaFileAttachesItToAProductModel(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+		}
+
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("Expected a multipart body, got %v", err)
+		}
+		owner := req.MultipartForm.Value["product_model"]
+		if len(owner) != 1 || owner[0] != `{"attribute":"picture","code":"MODEL-001","locale":null,"scope":"ecommerce"}` {
+			t.Errorf("Unexpected product model part %v", owner)
+		}
+		if files := req.MultipartForm.File["file"]; len(files) != 1 || files[0].Filename != "shoe.jpg" {
+			t.Errorf("Expected the file part to be named shoe.jpg, got %v", files)
+		}
+
+		header := http.Header{"Location": []string{"http://akeneo.test/api/rest/v1/media-files/a/b/c/1234_shoe.jpg"}}
+		return jsonResponse(http.StatusCreated, "", header), nil
+	})
+
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test", Transport: transport})
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	code, err := client.UploadMediaFile(context.Background(), MediaFileTarget{ModelCode: "MODEL-001", Attribute: "picture", Scope: "ecommerce"}, "shoe.jpg", []byte("jpg"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if code != "a/b/c/1234_shoe.jpg" {
+		t.Errorf("Expected the code from the Location header, got '%s'", code)
+	}
+}
+
+func TestClient_PatchProductsByUUIDReportsRejectedProductsByUUID(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+		}
+
+		if req.URL.Path != "/api/rest/v1/products-uuid" {
+			t.Errorf("Unexpected path %s", req.URL.Path)
+		}
+
+		return jsonResponse(http.StatusOK, `{"line":1,"uuid":"aaaa","status_code":201}`+"\n"+
+			`{"line":2,"uuid":"bbbb","status_code":422,"message":"Validation failed.","errors":[{"property":"family","message":"Unknown family"}]}`+"\n", nil), nil
+	})
+
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test", Transport: transport})
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	failed, err := client.PatchProductsByUUID(context.Background(), []Product{{"uuid": "aaaa"}, {"uuid": "bbbb"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(failed) != 1 || !strings.Contains(fmt.Sprint(failed["bbbb"]), "Unknown family") {
+		t.Errorf("Expected bbbb to be rejected, got %v", failed)
+	}
+}
+
+func TestClient_AbortsRequestsWhenContextIsDone(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+		}
+
+		// Simulate a slow API: answer only once the request is abandoned
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test", Transport: transport})
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.GetProduct(ctx, "SKU-001"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded error, got %v", err)
+	}
+}
+
+func TestClient_ReturnsRateLimitErrorAfterLastReplay(t *testing.T) {
+	attempts := 0
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+		}
+
+		attempts++
+		return jsonResponse(http.StatusTooManyRequests, `{"code":429,"message":"Too many requests"}`, http.Header{"Retry-After": {"0"}}), nil
+	})
+
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test", Transport: transport})
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	_, err = client.GetProduct(context.Background(), "SKU-001")
+
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("Expected a RateLimitError, got %v", err)
+	}
+
+	if attempts != maxRateLimitRetries+1 {
+		t.Errorf("Expected %d attempts, got %d", maxRateLimitRetries+1, attempts)
+	}
+}
+
+func TestRateLimiter_DelaysRequests(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var waits []time.Duration
+
+	limiter := newRateLimiter(DefaultRetryPolicy())
+	limiter.now = func() time.Time { return now }
+	limiter.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	limiter.observe(&http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"30"}}})
+	_ = limiter.wait(context.Background())
+
+	limiter.observe(&http.Response{StatusCode: http.StatusOK, Header: http.Header{
+		"X-Ratelimit-Remaining": {"0"},
+		"X-Ratelimit-Reset":     {fmt.Sprint(now.Add(time.Minute).Unix())},
+	}})
+	_ = limiter.wait(context.Background())
+
+	limiter.observe(&http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}})
+	_ = limiter.wait(context.Background())
+
+	expected := []time.Duration{30 * time.Second, time.Minute, time.Minute}
+	if len(waits) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, waits)
+	}
+	for i := range expected {
+		if waits[i] != expected[i] {
+			t.Errorf("Wait %d: expected %v, got %v", i, expected[i], waits[i])
+		}
+	}
+}
+
+func TestRateLimiter_WaitHonoursContext(t *testing.T) {
+	limiter := newRateLimiter(DefaultRetryPolicy())
+	limiter.observe(&http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"60"}}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := limiter.wait(ctx); err == nil {
+		t.Error("Expected wait to stop when the context is canceled")
+	}
+}
+
+func TestNewClient_AppliesOptions(t *testing.T) {
+	attempts := 0
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+		}
+
+		attempts++
+		return jsonResponse(http.StatusTooManyRequests, `{"code":429,"message":"Too many requests"}`, http.Header{"Retry-After": {"0"}}), nil
+	})
+
+	var logs bytes.Buffer
+	client, err := NewClient(
+		ClientConfig{Host: "http://akeneo.test"},
+		WithHTTPClient(&http.Client{Transport: transport, Timeout: time.Minute}),
+		WithTimeout(5*time.Second),
+		WithLogger(log.New(&logs, "", 0)),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 1, DefaultDelay: time.Second, MaxDelay: time.Second}),
+	)
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	if client.httpClient.Timeout != 5*time.Second {
+		t.Errorf("Expected a 5s timeout, got %v", client.httpClient.Timeout)
+	}
+
+	_, err = client.GetProduct(context.Background(), "SKU-001")
+
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("Expected a RateLimitError, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts with MaxRetries 1, got %d", attempts)
+	}
+	if !strings.Contains(logs.String(), "Rate limited") {
+		t.Errorf("Expected the retry to be logged, got %q", logs.String())
+	}
+}
+
+func TestNewClient_KeepsTimeoutOfGivenHTTPClient(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+	})
+
+	httpClient := &http.Client{Transport: transport, Timeout: time.Minute}
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test"}, WithHTTPClient(httpClient))
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	if client.httpClient.Timeout != time.Minute {
+		t.Errorf("Expected the given timeout to be kept, got %v", client.httpClient.Timeout)
+	}
+	if client.httpClient == httpClient {
+		t.Error("Expected the given HTTP client to be copied")
+	}
+}
+
+func TestClient_GetSystemInformationReportsMissingEndpoint(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+		}
+		if req.URL.Host == "old.akeneo.test" {
+			return jsonResponse(http.StatusNotFound, `{"code":404,"message":"No route found"}`, nil), nil
+		}
+		return jsonResponse(http.StatusOK, `{"version":"7.0.12","edition":"EE"}`, nil), nil
+	})
+
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test", Transport: transport})
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	info, err := client.GetSystemInformation(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if info.Version != "7.0.12" || info.Edition != "EE" {
+		t.Errorf("Unexpected system information %+v", info)
+	}
+
+	// Versions older than Akeneo 7 do not expose the endpoint
+	oldClient, err := NewClient(ClientConfig{Host: "http://old.akeneo.test", Transport: transport})
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+	if _, err := oldClient.GetSystemInformation(context.Background()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestClient_GetReferenceEntitiesFollowsPages(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+		}
+		if req.URL.Query().Get("search_after") == "" {
+			return jsonResponse(http.StatusOK, `{"_links":{"next":{"href":"http://akeneo.test/api/rest/v1/reference-entities?search_after=brands"}},"_embedded":{"items":[{"code":"brands"}]}}`, nil), nil
+		}
+		return jsonResponse(http.StatusOK, `{"_links":{},"_embedded":{"items":[{"code":"designers"}]}}`, nil), nil
+	})
+
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test", Transport: transport})
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	entities, err := client.GetReferenceEntities(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(entities) != 2 || entities[0]["code"] != "brands" || entities[1]["code"] != "designers" {
+		t.Errorf("Expected the entities of both pages, got %v", entities)
+	}
+}
+
+func TestClient_GetAttributesRequestsOnePage(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+		}
+		if req.URL.Query().Get("page") != "2" || req.URL.Query().Get("limit") != "50" {
+			t.Errorf("Unexpected query %s", req.URL.RawQuery)
+		}
+		return jsonResponse(http.StatusOK, `{"_links":{"next":{"href":"http://akeneo.test/api/rest/v1/attributes?page=3&limit=50"}},"_embedded":{"items":[{"code":"sku"}]}}`, nil), nil
+	})
+
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test", Transport: transport})
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	attributes, hasNext, err := client.GetAttributes(context.Background(), 2, 50)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(attributes) != 1 || attributes[0]["code"] != "sku" || !hasNext {
+		t.Errorf("Expected one attribute and a next page, got %v (next: %v)", attributes, hasNext)
+	}
+}
+
+func TestClient_CountItemsReadsItemsCount(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+		}
+		if req.URL.Query().Get("with_count") != "true" || req.URL.Query().Get("limit") != "1" {
+			t.Errorf("Unexpected query %s", req.URL.RawQuery)
+		}
+		if strings.HasSuffix(req.URL.Path, "/families") {
+			return jsonResponse(http.StatusOK, `{"_links":{},"items_count":42,"_embedded":{"items":[{"code":"shoes"}]}}`, nil), nil
+		}
+		return jsonResponse(http.StatusOK, `{"_links":{},"_embedded":{"items":[]}}`, nil), nil
+	})
+
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test", Transport: transport})
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	count, err := client.CountItems(context.Background(), "families")
+	if err != nil || count != 42 {
+		t.Errorf("Expected 42 families, got %d (%v)", count, err)
+	}
+	if _, err := client.CountItems(context.Background(), "reference-entities/brands/records"); err == nil {
+		t.Error("Expected an error for an endpoint without items_count")
+	}
+}
+
+func TestClient_CountUpdatedSinceSendsTheWindow(t *testing.T) {
+	var search string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+		}
+		search = req.URL.Query().Get("search")
+		return jsonResponse(http.StatusOK, `{"_links":{},"items_count":7,"_embedded":{"items":[]}}`, nil), nil
+	})
+
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test", Transport: transport})
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	count, err := client.CountUpdatedSince(context.Background(), "products", "2024-01-01T00:00:00", "")
+	if err != nil || count != 7 {
+		t.Errorf("Expected 7 products, got %d (%v)", count, err)
+	}
+	if search != `{"updated":[{"operator":">","value":"2024-01-01 00:00:00"}]}` {
+		t.Errorf("Expected the updated filter to be sent, got %s", search)
+	}
+}
+
+func TestClient_StreamProductModelsUpdatedSinceFollowsPages(t *testing.T) {
+	var requests []*http.Request
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+		}
+		requests = append(requests, req)
+		if req.URL.Query().Get("search_after") == "" {
+			return jsonResponse(http.StatusOK, `{"_links":{"next":{"href":"http://akeneo.test/api/rest/v1/product-models?search_after=MODEL-002&limit=2"}},"_embedded":{"items":[{"code":"MODEL-001"},{"code":"MODEL-002"}]}}`, nil), nil
+		}
+		return jsonResponse(http.StatusOK, `{"_links":{},"_embedded":{"items":[{"code":"MODEL-003"}]}}`, nil), nil
+	})
+
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test", Transport: transport})
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	var batches []int
+	err = client.StreamProductModelsUpdatedSince(context.Background(), "2024-01-01T00:00:00", "2024-02-01T00:00:00", 2, func(models []ProductModel) error {
+		batches = append(batches, len(models))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(batches) != 2 || batches[0] != 2 || batches[1] != 1 {
+		t.Errorf("Expected batches of 2 and 1 models, got %v", batches)
+	}
+
+	query := requests[0].URL.Query()
+	expected := `{"updated":[{"operator":">","value":"2024-01-01 00:00:00"},{"operator":"BETWEEN","value":["2024-01-01 00:00:00","2024-02-01 00:00:00"]}]}`
+	if query.Get("search") != expected {
+		t.Errorf("Expected the updated window to be sent, got %s", query.Get("search"))
+	}
+	if query.Get("pagination_type") != "search_after" || query.Get("limit") != "2" {
+		t.Errorf("Expected search_after pages of 2 items, got %s", requests[0].URL.RawQuery)
+	}
+
+	// A callback error stops the stream
+	requests = nil
+	err = client.StreamProductModelsUpdatedSince(context.Background(), "2024-01-01T00:00:00", "", 2, func(models []ProductModel) error {
+		return errors.New("disk full")
+	})
+	if err == nil || !strings.Contains(err.Error(), "disk full") || len(requests) != 1 {
+		t.Errorf("Expected the stream to stop at the first batch, got %v after %d requests", err, len(requests))
+	}
+}
+
+func TestClient_PatchProductSendsQuantifiedAssociationsByIdentifier(t *testing.T) {
+	var sent map[string]interface{}
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+		}
+
+		if err := json.NewDecoder(req.Body).Decode(&sent); err != nil {
+			t.Errorf("Expected a JSON body, got %v", err)
+		}
+		return jsonResponse(http.StatusNoContent, "", nil), nil
+	})
+
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test", Transport: transport})
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	err = client.PatchProduct(context.Background(), "PACK-1", Product{
+		"identifier": "PACK-1",
+		"quantified_associations": map[string]interface{}{
+			"PACK": map[string]interface{}{
+				"products": []interface{}{map[string]interface{}{"identifier": "SKU-1", "uuid": "aaaa", "quantity": 2}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	pack := sent["quantified_associations"].(map[string]interface{})["PACK"].(map[string]interface{})
+	link := pack["products"].([]interface{})[0].(map[string]interface{})
+	if _, hasUUID := link["uuid"]; hasUUID || link["identifier"] != "SKU-1" || link["quantity"] != float64(2) {
+		t.Errorf("Expected the link to keep its identifier and quantity only, got %v", link)
+	}
+}
+
+func TestNewClient_TunesTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "" {
+			t.Errorf("Expected compression to be disabled, got Accept-Encoding %q", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"token","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	config := DefaultTransportConfig()
+	config.MaxConnsPerHost = 4
+	config.DisableCompression = true
+
+	client, err := NewClient(ClientConfig{Host: server.URL}, WithTransportConfig(config))
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected an *http.Transport, got %T", client.httpClient.Transport)
+	}
+	if transport.MaxConnsPerHost != 4 || transport.MaxIdleConnsPerHost != 32 || !transport.DisableCompression {
+		t.Errorf("Expected the transport configuration to be applied, got %d conns, %d idle conns, compression disabled %v",
+			transport.MaxConnsPerHost, transport.MaxIdleConnsPerHost, transport.DisableCompression)
+	}
+}
+
+func TestNewClient_KeepsTransportOfGivenHTTPClient(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+	})
+
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test"}, WithHTTPClient(&http.Client{Transport: transport}))
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	if _, tuned := client.httpClient.Transport.(*http.Transport); tuned {
+		t.Error("Expected the transport of the given HTTP client to be kept")
+	}
+}
+
+func TestNewClient_TrustsCustomCertificateAuthority(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"token","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, bundle, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewClient(ClientConfig{Host: server.URL}); err == nil {
+		t.Error("Expected the certificate of an unknown authority to be rejected")
+	}
+
+	if _, err := NewClient(ClientConfig{Host: server.URL}, WithTLSConfig(TLSConfig{CAFile: caFile})); err != nil {
+		t.Errorf("Expected the custom authority to be trusted, got %v", err)
+	}
+
+	if _, err := NewClient(ClientConfig{Host: server.URL}, WithTLSConfig(TLSConfig{InsecureSkipVerify: true})); err != nil {
+		t.Errorf("Expected the verification to be skipped, got %v", err)
+	}
+
+	if _, err := NewClient(ClientConfig{Host: server.URL}, WithTLSConfig(TLSConfig{CertFile: caFile})); err == nil {
+		t.Error("Expected an error for a client certificate without key")
+	}
+}
+
+func TestClient_RefreshesTokenOnceForConcurrentCalls(t *testing.T) {
+	var mu sync.Mutex
+	authentications := 0
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			mu.Lock()
+			defer mu.Unlock()
+			authentications++
+
+			// The first token expires within the refresh margin, the next one does not
+			expiresIn := 60
+			if authentications > 1 {
+				expiresIn = 3600
+			}
+			return jsonResponse(http.StatusOK, fmt.Sprintf(`{"access_token":"token-%d","expires_in":%d}`, authentications, expiresIn), nil), nil
+		}
+
+		if req.Header.Get("Authorization") != "Bearer token-2" {
+			t.Errorf("Expected the refreshed token, got %q", req.Header.Get("Authorization"))
+		}
+		return jsonResponse(http.StatusOK, `{"identifier":"SKU-001"}`, nil), nil
+	})
+
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test", Transport: transport})
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetProduct(context.Background(), "SKU-001"); err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if authentications != 2 {
+		t.Errorf("Expected the token to be refreshed once, got %d authentications", authentications)
+	}
+}
+
+func TestClient_SendsAppAccessToken(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			t.Error("Expected no token request with an App access token")
+		}
+		if req.Header.Get("Authorization") != "Bearer app-token" {
+			t.Errorf("Expected the App token, got %q", req.Header.Get("Authorization"))
+		}
+		return jsonResponse(http.StatusOK, `{"identifier":"SKU-001"}`, nil), nil
+	})
+
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test", AccessToken: "app-token", Transport: transport})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := client.GetProduct(context.Background(), "SKU-001"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestNewClient_AppliesMiddlewaresInOrder(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+		}
+
+		if req.Header.Get("X-Tenant") != "acme" {
+			t.Errorf("Expected the tenant header, got %q", req.Header.Get("X-Tenant"))
+		}
+		return jsonResponse(http.StatusOK, `{"identifier":"SKU-001"}`, nil), nil
+	})
+
+	var calls []string
+	tenant := func(req *http.Request, next NextFunc) (*http.Response, error) {
+		calls = append(calls, "tenant "+req.URL.Path)
+		req.Header.Set("X-Tenant", "acme")
+		return next(req)
+	}
+	capture := func(req *http.Request, next NextFunc) (*http.Response, error) {
+		resp, err := next(req)
+		if err != nil {
+			return nil, err
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		calls = append(calls, "capture "+string(body))
+		return resp, nil
+	}
+
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test", Transport: transport}, WithMiddlewares(tenant), WithMiddlewares(capture))
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	productData, err := client.GetProduct(context.Background(), "SKU-001")
+	if err != nil || productData["identifier"] != "SKU-001" {
+		t.Fatalf("Expected the captured response to stay readable, got %v (%v)", productData, err)
+	}
+
+	expected := []string{
+		"tenant /api/oauth/v1/token",
+		`capture {"access_token":"token","expires_in":3600}`,
+		"tenant /api/rest/v1/products/SKU-001",
+		`capture {"identifier":"SKU-001"}`,
+	}
+	if strings.Join(calls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected middlewares to wrap every request in order, got %v", calls)
+	}
+}
+
+func TestTransformResponses_RewritesJSONBodies(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+		}
+		return jsonResponse(http.StatusOK, `{"identifier":"SKU-001","family":"shoes","values":{"weight":[{"locale":null,"scope":null,"data":12.50}]}}`, nil), nil
+	})
+
+	rename := func(data interface{}) interface{} {
+		item := data.(map[string]interface{})
+		item["family"] = "footwear"
+		return item
+	}
+
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test", Transport: transport}, WithMiddlewares(TransformResponses(rename)))
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	productData, err := client.GetProduct(context.Background(), "SKU-001")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if productData["family"] != "footwear" {
+		t.Errorf("Expected the transformed family, got %v", productData["family"])
+	}
+
+	weight := productData["values"].(map[string]interface{})["weight"].([]interface{})[0].(map[string]interface{})
+	if weight["data"] != 12.5 {
+		t.Errorf("Expected numbers to be kept, got %v", weight["data"])
+	}
+}
+
+func TestNewClient_SendsIdentificationHeaders(t *testing.T) {
+	var seen []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		seen = append(seen, req.URL.Path+" "+req.Header.Get("User-Agent")+" "+req.Header.Get("X-Run"))
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+		}
+		return jsonResponse(http.StatusOK, `{"identifier":"SKU-001"}`, nil), nil
+	})
+
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test", Transport: transport},
+		WithUserAgent("migrator/test"), WithCorrelationID("X-Run", "run-42"))
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+	if _, err := client.GetProduct(context.Background(), "SKU-001"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{
+		"/api/oauth/v1/token migrator/test run-42",
+		"/api/rest/v1/products/SKU-001 migrator/test run-42",
+	}
+	if strings.Join(seen, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected every request to carry the headers, got %v", seen)
+	}
+}
+
+func TestNewClient_SendsDefaultUserAgent(t *testing.T) {
+	var userAgent, requestID string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		userAgent, requestID = req.Header.Get("User-Agent"), req.Header.Get(DefaultCorrelationHeader)
+		return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+	})
+
+	if _, err := NewClient(ClientConfig{Host: "http://akeneo.test", Transport: transport}); err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	if userAgent != DefaultUserAgent || requestID != "" {
+		t.Errorf("Expected default User-Agent without correlation ID, got %q and %q", userAgent, requestID)
+	}
+}
+
+func TestClient_RefusesWritesDuringDryRun(t *testing.T) {
+	var sent []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		sent = append(sent, req.Method+" "+req.URL.Path)
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+		}
+		return jsonResponse(http.StatusOK, `{"identifier":"SKU-001"}`, nil), nil
+	})
+
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test", Transport: transport})
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	ctx := dryrun.With(context.Background())
+	if _, err := client.GetProduct(ctx, "SKU-001"); err != nil {
+		t.Fatalf("Expected reads to be sent during a dry run, got %v", err)
+	}
+	err = client.PatchProduct(ctx, "SKU-001", Product{"identifier": "SKU-001"})
+	if !errors.Is(err, ErrDryRun) {
+		t.Errorf("Expected ErrDryRun, got %v", err)
+	}
+
+	if len(sent) != 2 || sent[1] != "GET /api/rest/v1/products/SKU-001" {
+		t.Errorf("Expected only the token and read requests to be sent, got %v", sent)
+	}
+}
+
+func TestNewClient_CollectsMetrics(t *testing.T) {
+	attempts := 0
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+		}
+
+		attempts++
+		if attempts == 1 {
+			// The body of a throttled response is closed unread: its announced length is counted
+			resp := jsonResponse(http.StatusTooManyRequests, `{"code":429}`, http.Header{"Retry-After": {"0"}})
+			resp.ContentLength = int64(len(`{"code":429}`))
+			return resp, nil
+		}
+		return jsonResponse(http.StatusOK, `{"identifier":"SKU-001"}`, nil), nil
+	})
+
+	collector := NewMetricsCollector()
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test", Transport: transport}, WithMetrics(collector))
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	if _, err := client.GetProduct(context.Background(), "SKU-001"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var products *EndpointMetrics
+	for _, metrics := range collector.Snapshot() {
+		if metrics.Method == http.MethodGet && metrics.Endpoint == "/api/rest/v1/products/{code}" {
+			products = &metrics
+		}
+	}
+	if products == nil {
+		t.Fatalf("Expected metrics for the product endpoint, got %+v", collector.Snapshot())
+	}
+
+	if products.Requests != 2 || products.Retries != 1 || products.Statuses[429] != 1 || products.Statuses[200] != 1 {
+		t.Errorf("Expected a throttled request and its replay, got %+v", products)
+	}
+	if products.BytesReceived != int64(len(`{"code":429}`)+len(`{"identifier":"SKU-001"}`)) {
+		t.Errorf("Expected the bytes of both responses, got %d", products.BytesReceived)
+	}
+}
+
+func TestEndpointOf(t *testing.T) {
+	paths := map[string]string{
+		"/api/rest/v1/products":                                 "/api/rest/v1/products",
+		"/api/rest/v1/products/SKU-001":                         "/api/rest/v1/products/{code}",
+		"/api/rest/v1/reference-entities/brand/records/acme":    "/api/rest/v1/reference-entities/{code}/records/{code}",
+		"/api/rest/v1/media-files/a/b/c/abc_file.jpg/download":  "/api/rest/v1/media-files/{code}/download",
+		"/api/oauth/v1/token":                                   "/api/oauth/v1/token",
+		"/api/rest/v1/reference-entities/brand/attributes/name": "/api/rest/v1/reference-entities/{code}/attributes/{code}",
+	}
+
+	for path, expected := range paths {
+		if endpoint := endpointOf(path); endpoint != expected {
+			t.Errorf("Expected %s for %s, got %s", expected, path, endpoint)
+		}
+	}
+}
+
+// levelRecorder records the messages of the client with their level
+type levelRecorder struct {
+	levels []Level
+}
+
+func (r *levelRecorder) Printf(format string, args ...interface{}) {
+	r.levels = append(r.levels, LevelInfo)
+}
+
+func (r *levelRecorder) Logf(level Level, format string, args ...interface{}) {
+	r.levels = append(r.levels, level)
+}
+
+func TestClient_PrintsPayloadsOnlyAtDebugLevel(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+		}
+		return jsonResponse(http.StatusOK, `[{"code":"name","type":"text","labels":{"en_US":"Name"}}]`, nil), nil
+	})
+	config := ClientConfig{Host: "http://akeneo.test", Transport: transport}
+
+	var logs bytes.Buffer
+	client, err := NewClient(config, WithLogger(log.New(&logs, "", 0)))
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+	if _, err := client.GetReferenceEntityAttributes(context.Background(), "brand"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("Expected no payload dump by default, got %q", logs.String())
+	}
+
+	client, err = NewClient(config, WithLogger(log.New(&logs, "", 0)), WithLogLevel(LevelDebug))
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+	if _, err := client.GetReferenceEntityAttributes(context.Background(), "brand"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(logs.String(), `"code":"name"`) {
+		t.Errorf("Expected the payload to be dumped at debug level, got %q", logs.String())
+	}
+
+	// Level loggers receive every message with its level
+	recorder := &levelRecorder{}
+	client, err = NewClient(config, WithLogger(recorder))
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+	if _, err := client.GetReferenceEntityAttributes(context.Background(), "brand"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(recorder.levels) != 1 || recorder.levels[0] != LevelDebug {
+		t.Errorf("Expected a debug message, got %v", recorder.levels)
+	}
+}
+
+func TestClient_DeleteReferenceEntityRecord(t *testing.T) {
+	var sent []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/token") {
+			return jsonResponse(http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil), nil
+		}
+		sent = append(sent, req.Method+" "+req.URL.Path)
+		if strings.HasSuffix(req.URL.Path, "/gone") {
+			return jsonResponse(http.StatusNotFound, `{"code":404}`, nil), nil
+		}
+		return jsonResponse(http.StatusNoContent, ``, nil), nil
+	})
+
+	client, err := NewClient(ClientConfig{Host: "http://akeneo.test", Transport: transport})
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+
+	if err := client.DeleteReferenceEntityRecord(context.Background(), "brands", "acme"); err != nil {
+		t.Fatalf("Expected record to be deleted, got %v", err)
+	}
+	if err := client.DeleteReferenceEntityRecord(context.Background(), "brands", "gone"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing record, got %v", err)
+	}
+
+	if len(sent) != 2 || sent[0] != "DELETE /api/rest/v1/reference-entities/brands/records/acme" {
+		t.Errorf("Unexpected requests: %v", sent)
+	}
+}
@@ -0,0 +1,247 @@
+package akeneo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxCollectionSize is the maximum number of items Akeneo accepts in one collection call
+const maxCollectionSize = 100
+
+// collectionContentType is the content type of Akeneo collection endpoints (one JSON item per line)
+const collectionContentType = "application/vnd.akeneo.collection+json"
+
+// CollectionLineResult represents the status of one item sent to a collection endpoint.
+// Product endpoints answer with one line per item; the records endpoint answers with a JSON array.
+type CollectionLineResult struct {
+	Line       int                `json:"line"`
+	Identifier string             `json:"identifier,omitempty"`
+	UUID       string             `json:"uuid,omitempty"`
+	Code       string             `json:"code,omitempty"`
+	StatusCode int                `json:"status_code"`
+	Message    string             `json:"message,omitempty"`
+	Errors     []AkeneoFieldError `json:"errors,omitempty"`
+}
+
+// PatchProducts creates or updates several products with the collection endpoint, up to 100 per call.
+// It returns the errors of the products that were not written, indexed by identifier.
+func (c *Client) PatchProducts(ctx context.Context, products []Product) (map[string]error, error) {
+	items := make([]map[string]interface{}, len(products))
+	for i, productData := range products {
+		items[i] = c.cleanProduct(productData)
+	}
+
+	return c.patchCollection(ctx, "products", "identifier", "product", items)
+}
+
+// PatchProductModels creates or updates several product models with the collection endpoint, up to 100 per call.
+// It returns the errors of the models that were not written, indexed by code.
+func (c *Client) PatchProductModels(ctx context.Context, models []ProductModel) (map[string]error, error) {
+	items := make([]map[string]interface{}, len(models))
+	for i, model := range models {
+		items[i] = c.cleanProductModel(model)
+	}
+
+	return c.patchCollection(ctx, "product-models", "code", "product model", items)
+}
+
+// patchCollection sends items to a collection endpoint in chunks. When a whole call fails,
+// every item of its chunk is reported with the error of the call.
+func (c *Client) patchCollection(ctx context.Context, resource, key, kind string, items []map[string]interface{}) (map[string]error, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return nil, err
+	}
+
+	failed := make(map[string]error)
+
+	for start := 0; start < len(items); start += maxCollectionSize {
+		end := start + maxCollectionSize
+		if end > len(items) {
+			end = len(items)
+		}
+		chunk := items[start:end]
+
+		codes := make([]string, len(chunk))
+		var body bytes.Buffer
+		for i, item := range chunk {
+			codes[i], _ = item[key].(string)
+			if codes[i] == "" {
+				return nil, fmt.Errorf("%s without %s cannot be sent in a collection", kind, key)
+			}
+
+			line, err := json.Marshal(item)
+			if err != nil {
+				return nil, err
+			}
+			body.Write(line)
+			body.WriteByte('\n')
+		}
+
+		lines, err := c.sendCollection(ctx, resource, body.Bytes())
+		if err != nil {
+			for _, code := range codes {
+				failed[code] = err
+			}
+			continue
+		}
+
+		for _, line := range lines {
+			if line.StatusCode < http.StatusBadRequest {
+				continue
+			}
+
+			code := line.Identifier
+			if key == "uuid" {
+				code = line.UUID
+			}
+			if code == "" {
+				code = line.Code
+			}
+			if code == "" && line.Line >= 1 && line.Line <= len(codes) {
+				code = codes[line.Line-1]
+			}
+
+			failed[code] = lineError(kind+" "+code, line)
+		}
+	}
+
+	return failed, nil
+}
+
+// lineError builds the error of an item rejected in a collection call
+func lineError(item string, line CollectionLineResult) error {
+	errorResponse := AkeneoErrorResponse{Code: line.StatusCode, Message: line.Message, Errors: line.Errors}
+	if line.StatusCode == http.StatusUnprocessableEntity {
+		return newValidationError(item, errorResponse)
+	}
+	return fmt.Errorf("error updating %s: %d - %s", item, line.StatusCode, formatAkeneoErrors(errorResponse))
+}
+
+// sendCollection sends one chunk of newline-delimited items and decodes the result of each line
+func (c *Client) sendCollection(ctx context.Context, resource string, body []byte) ([]CollectionLineResult, error) {
+	url := fmt.Sprintf("%s/api/rest/v1/%s", c.config.Host, resource)
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token())
+	req.Header.Set("Content-Type", collectionContentType)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		responseBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("error updating %s: %d - %s", resource, resp.StatusCode, string(responseBody))
+	}
+
+	var lines []CollectionLineResult
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var line CollectionLineResult
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, fmt.Errorf("error decoding %s collection response: %w", resource, err)
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s collection response: %w", resource, err)
+	}
+
+	return lines, nil
+}
+
+// PatchReferenceEntityRecords creates or updates several records of a Reference Entity, up to 100 per call.
+// It returns the errors of the records that were not written, indexed by code.
+func (c *Client) PatchReferenceEntityRecords(ctx context.Context, entityName string, records []ReferenceEntityRecord) (map[string]error, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return nil, err
+	}
+
+	failed := make(map[string]error)
+
+	for start := 0; start < len(records); start += maxCollectionSize {
+		end := start + maxCollectionSize
+		if end > len(records) {
+			end = len(records)
+		}
+
+		codes := make([]string, 0, end-start)
+		chunk := make([]ReferenceEntityRecord, 0, end-start)
+		for _, record := range records[start:end] {
+			code, _ := record["code"].(string)
+			if code == "" {
+				return nil, fmt.Errorf("record without code cannot be sent in a collection")
+			}
+			codes = append(codes, code)
+			chunk = append(chunk, c.cleanRecord(record))
+		}
+
+		statuses, err := c.sendRecords(ctx, entityName, chunk)
+		if err != nil {
+			for _, code := range codes {
+				failed[code] = err
+			}
+			continue
+		}
+
+		for _, status := range statuses {
+			if status.StatusCode >= http.StatusBadRequest {
+				failed[status.Code] = lineError("record "+status.Code, status)
+			}
+		}
+	}
+
+	return failed, nil
+}
+
+// sendRecords sends one chunk of records and decodes the status of each record
+func (c *Client) sendRecords(ctx context.Context, entityName string, records []ReferenceEntityRecord) ([]CollectionLineResult, error) {
+	jsonData, err := json.Marshal(records)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/api/rest/v1/reference-entities/%s/records", c.config.Host, entityName)
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token())
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("error updating records: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var statuses []CollectionLineResult
+	if err := json.NewDecoder(resp.Body).Decode(&statuses); err != nil {
+		return nil, fmt.Errorf("error decoding records response: %w", err)
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,64 @@
+package akeneo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is wrapped by the errors returned when the requested item does not exist (404).
+// Use errors.Is to detect it.
+var ErrNotFound = errors.New("not found")
+
+// ErrDryRun is wrapped by the errors returned when a write is attempted in a dry-run context
+// (see kit/dryrun). Syncs record their writes instead of sending them; this guards the others.
+var ErrDryRun = errors.New("not sent during a dry run")
+
+// ValidationError is returned when Akeneo rejects an item (422). Use errors.As to inspect it.
+type ValidationError struct {
+	// Item names the rejected item, e.g. "product SKU-001"
+	Item    string
+	Message string
+	Fields  []AkeneoFieldError
+}
+
+// Error formats the message with the details of each rejected field
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validation error in %s: %s", e.Item, formatAkeneoErrors(AkeneoErrorResponse{Message: e.Message, Errors: e.Fields}))
+}
+
+// RateLimitError is returned when the API still throttles a request (429) after the last replay.
+// Use errors.As to inspect it.
+type RateLimitError struct {
+	// RetryAfter is the delay requested by the API before the next call
+	RetryAfter time.Duration
+}
+
+// Error reports the delay requested by the API
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limited by the Akeneo API (retry after %s)", e.RetryAfter)
+}
+
+// newValidationError builds the error of an item rejected by Akeneo
+func newValidationError(item string, errorResponse AkeneoErrorResponse) *ValidationError {
+	return &ValidationError{
+		Item:    item,
+		Message: errorResponse.Message,
+		Fields:  errorResponse.Errors,
+	}
+}
+
+// formatAkeneoErrors formats Akeneo errors to display useful information
+func formatAkeneoErrors(errorResponse AkeneoErrorResponse) string {
+	if len(errorResponse.Errors) == 0 {
+		return errorResponse.Message
+	}
+
+	var errorMessages []string
+	for _, fieldError := range errorResponse.Errors {
+		errorMessages = append(errorMessages, fmt.Sprintf("Field '%s': %s", fieldError.Property, fieldError.Message))
+	}
+
+	return fmt.Sprintf("%s. Details: %s", errorResponse.Message, strings.Join(errorMessages, "; "))
+}
@@ -0,0 +1,229 @@
+package akeneo
+
+import (
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LatencyBuckets are the upper bounds of the latency histogram of MetricsCollector
+var LatencyBuckets = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// RequestMetrics describes one HTTP exchange with the API
+type RequestMetrics struct {
+	Method string
+	// Endpoint is the request path where item codes are replaced by {code}, e.g. /api/rest/v1/products/{code}
+	Endpoint string
+	// Status is the response status, 0 when no response was received
+	Status int
+	// Duration runs from the request until the response body is closed
+	Duration      time.Duration
+	BytesSent     int64
+	BytesReceived int64
+}
+
+// MetricsRecorder receives the metrics of the client, e.g. to export them to a monitoring system.
+// Implementations must be safe for concurrent use.
+type MetricsRecorder interface {
+	// RecordRequest is called once per HTTP exchange, replays of throttled requests included
+	RecordRequest(metrics RequestMetrics)
+	// RecordRetry is called when a throttled request is about to be replayed
+	RecordRetry(method, endpoint string)
+}
+
+// WithMetrics sends the metrics of every request to recorder
+func WithMetrics(recorder MetricsRecorder) Option {
+	return func(o *clientOptions) {
+		o.metrics = recorder
+	}
+}
+
+// metricsMiddleware measures the requests sent to the API
+func metricsMiddleware(recorder MetricsRecorder) Middleware {
+	return func(req *http.Request, next NextFunc) (*http.Response, error) {
+		metrics := RequestMetrics{
+			Method:    req.Method,
+			Endpoint:  endpointOf(req.URL.Path),
+			BytesSent: req.ContentLength,
+		}
+		if metrics.BytesSent < 0 {
+			metrics.BytesSent = 0
+		}
+		start := time.Now()
+
+		resp, err := next(req)
+		if err != nil {
+			metrics.Duration = time.Since(start)
+			recorder.RecordRequest(metrics)
+			return nil, err
+		}
+
+		metrics.Status = resp.StatusCode
+		resp.Body = &measuredBody{ReadCloser: resp.Body, done: func(received int64) {
+			metrics.Duration = time.Since(start)
+			// Bodies closed without being read, like the ones of throttled requests, count their announced length
+			metrics.BytesReceived = received
+			if resp.ContentLength > received {
+				metrics.BytesReceived = resp.ContentLength
+			}
+			recorder.RecordRequest(metrics)
+		}}
+		return resp, nil
+	}
+}
+
+// measuredBody counts the bytes read from a response body and reports them once closed
+type measuredBody struct {
+	io.ReadCloser
+	received int64
+	once     sync.Once
+	done     func(received int64)
+}
+
+func (b *measuredBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.received += int64(n)
+	return n, err
+}
+
+func (b *measuredBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.done(b.received) })
+	return err
+}
+
+// endpointOf replaces the item codes of an API path by {code}, so requests group by endpoint.
+// REST paths alternate resources and codes: /api/rest/v1/{resource}/{code}/{resource}/{code}.
+func endpointOf(path string) string {
+	const prefix = "/api/rest/v1/"
+	if !strings.HasPrefix(path, prefix) {
+		return path
+	}
+
+	segments := strings.Split(strings.TrimPrefix(path, prefix), "/")
+	// Media file codes contain slashes: everything between the resource and "download" is the code
+	if len(segments) > 2 && segments[len(segments)-1] == "download" {
+		segments = []string{segments[0], "{code}", "download"}
+	}
+
+	for i := 1; i < len(segments); i += 2 {
+		segments[i] = "{code}"
+	}
+
+	return prefix + strings.Join(segments, "/")
+}
+
+// EndpointMetrics aggregates the requests sent to one endpoint
+type EndpointMetrics struct {
+	Method   string
+	Endpoint string
+	Requests int
+	// Statuses counts the responses by status, 0 counting the requests without response
+	Statuses      map[int]int
+	Retries       int
+	TotalDuration time.Duration
+	MaxDuration   time.Duration
+	// Latency counts the requests per bucket of LatencyBuckets, the last entry counting the slower ones
+	Latency       []int
+	BytesSent     int64
+	BytesReceived int64
+}
+
+// AverageDuration returns the mean duration of the requests
+func (m EndpointMetrics) AverageDuration() time.Duration {
+	if m.Requests == 0 {
+		return 0
+	}
+	return m.TotalDuration / time.Duration(m.Requests)
+}
+
+// MetricsCollector is an in-memory MetricsRecorder aggregating requests by endpoint
+type MetricsCollector struct {
+	mu        sync.Mutex
+	endpoints map[string]*EndpointMetrics
+}
+
+// NewMetricsCollector creates an empty collector
+func NewMetricsCollector() *MetricsCollector {
+	return &MetricsCollector{endpoints: make(map[string]*EndpointMetrics)}
+}
+
+// endpoint returns the metrics of an endpoint, creating them when needed. The caller must hold mu.
+func (c *MetricsCollector) endpoint(method, endpoint string) *EndpointMetrics {
+	key := method + " " + endpoint
+	metrics, ok := c.endpoints[key]
+	if !ok {
+		metrics = &EndpointMetrics{
+			Method:   method,
+			Endpoint: endpoint,
+			Statuses: make(map[int]int),
+			Latency:  make([]int, len(LatencyBuckets)+1),
+		}
+		c.endpoints[key] = metrics
+	}
+	return metrics
+}
+
+// RecordRequest implements MetricsRecorder
+func (c *MetricsCollector) RecordRequest(request RequestMetrics) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	metrics := c.endpoint(request.Method, request.Endpoint)
+	metrics.Requests++
+	metrics.Statuses[request.Status]++
+	metrics.TotalDuration += request.Duration
+	if request.Duration > metrics.MaxDuration {
+		metrics.MaxDuration = request.Duration
+	}
+	metrics.BytesSent += request.BytesSent
+	metrics.BytesReceived += request.BytesReceived
+
+	bucket := sort.Search(len(LatencyBuckets), func(i int) bool { return request.Duration <= LatencyBuckets[i] })
+	metrics.Latency[bucket]++
+}
+
+// RecordRetry implements MetricsRecorder
+func (c *MetricsCollector) RecordRetry(method, endpoint string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.endpoint(method, endpoint).Retries++
+}
+
+// Snapshot returns a copy of the metrics of every endpoint, the slowest in total first
+func (c *MetricsCollector) Snapshot() []EndpointMetrics {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshot := make([]EndpointMetrics, 0, len(c.endpoints))
+	for _, metrics := range c.endpoints {
+		copied := *metrics
+		copied.Statuses = make(map[int]int, len(metrics.Statuses))
+		for status, count := range metrics.Statuses {
+			copied.Statuses[status] = count
+		}
+		copied.Latency = append([]int(nil), metrics.Latency...)
+		snapshot = append(snapshot, copied)
+	}
+
+	sort.Slice(snapshot, func(i, j int) bool {
+		if snapshot[i].TotalDuration != snapshot[j].TotalDuration {
+			return snapshot[i].TotalDuration > snapshot[j].TotalDuration
+		}
+		return snapshot[i].Method+" "+snapshot[i].Endpoint < snapshot[j].Method+" "+snapshot[j].Endpoint
+	})
+	return snapshot
+}
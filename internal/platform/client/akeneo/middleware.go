@@ -0,0 +1,91 @@
+package akeneo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"akeneo-migrator/kit/dryrun"
+)
+
+// NextFunc sends a request further down the middleware chain
+type NextFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps every request sent to the API, including token requests and replays of
+// throttled requests. It calls next to send the request and may inspect or replace the response.
+// A middleware reading the request or response body must restore it for the rest of the chain.
+type Middleware func(req *http.Request, next NextFunc) (*http.Response, error)
+
+// chain returns the function sending a request through the middlewares, the first one being the outermost
+func chain(send NextFunc, middlewares []Middleware) NextFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		middleware := middlewares[i]
+		next := send
+		send = func(req *http.Request) (*http.Response, error) {
+			return middleware(req, next)
+		}
+	}
+
+	return send
+}
+
+// headersMiddleware sets the given headers on every request, replacing the values set by the client
+func headersMiddleware(headers map[string]string) Middleware {
+	return func(req *http.Request, next NextFunc) (*http.Response, error) {
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		return next(req)
+	}
+}
+
+// dryRunMiddleware refuses the requests writing to the API when their context is a dry run,
+// so a sync that does not record its writes cannot change the destination either
+func dryRunMiddleware(req *http.Request, next NextFunc) (*http.Response, error) {
+	if req.Method != http.MethodGet && !strings.HasSuffix(req.URL.Path, "/token") && dryrun.Enabled(req.Context()) {
+		return nil, fmt.Errorf("%s %s %w", req.Method, req.URL.Path, ErrDryRun)
+	}
+	return next(req)
+}
+
+// TransformResponses rewrites the JSON body of every successful GET response with transform, which
+// receives and returns the decoded body, e.g. to rename source codes before any service reads them.
+// Numbers are decoded as json.Number so they are written back unchanged.
+func TransformResponses(transform func(data interface{}) interface{}) Middleware {
+	return func(req *http.Request, next NextFunc) (*http.Response, error) {
+		resp, err := next(req)
+		if err != nil || req.Method != http.MethodGet || resp.StatusCode >= 300 ||
+			!strings.Contains(resp.Header.Get("Content-Type"), "json") {
+			return resp, err
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading response of %s: %w", req.URL.Path, err)
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		var data interface{}
+		if err := decoder.Decode(&data); err != nil {
+			// Not a JSON document after all: let the client report it
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			return resp, nil
+		}
+
+		transformed, err := json.Marshal(transform(data))
+		if err != nil {
+			return nil, fmt.Errorf("error encoding transformed response of %s: %w", req.URL.Path, err)
+		}
+
+		resp.Body = io.NopCloser(bytes.NewReader(transformed))
+		resp.ContentLength = int64(len(transformed))
+		resp.Header.Set("Content-Length", strconv.Itoa(len(transformed)))
+		return resp, nil
+	}
+}
@@ -0,0 +1,273 @@
+package akeneo
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+// defaultTimeout is the HTTP timeout used when none is configured
+const defaultTimeout = 30 * time.Second
+
+// DefaultUserAgent is the User-Agent of the requests when none is configured
+const DefaultUserAgent = "akeneo-migrator"
+
+// DefaultCorrelationHeader is the header carrying the correlation ID when no header is given
+const DefaultCorrelationHeader = "X-Request-Id"
+
+// Logger receives the diagnostic messages of the client. *log.Logger implements it.
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+// Level is the severity of a diagnostic message
+type Level int
+
+const (
+	// LevelDebug messages dump payloads and internal details; they may contain catalog data
+	LevelDebug Level = iota
+	// LevelInfo messages report the normal progress of the client, e.g. replays of throttled requests
+	LevelInfo
+	// LevelWarn messages report data the client had to fix or guess
+	LevelWarn
+)
+
+// LevelLogger is a Logger that receives the level of each message. A logger implementing it
+// is given every message and filters them itself: WithLogLevel only applies to other loggers.
+type LevelLogger interface {
+	Logger
+	Logf(level Level, format string, args ...interface{})
+}
+
+// RetryPolicy defines how throttled (429) requests are replayed
+type RetryPolicy struct {
+	// MaxRetries is the number of times a throttled request is replayed before a RateLimitError is returned
+	MaxRetries int
+	// DefaultDelay is the delay applied to a 429 without a usable Retry-After header
+	DefaultDelay time.Duration
+	// MaxDelay caps the delay requested by the server
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:   maxRateLimitRetries,
+		DefaultDelay: defaultRetryAfter,
+		MaxDelay:     maxRetryAfter,
+	}
+}
+
+// TransportConfig tunes the connections of the HTTP transport built by the client.
+// The defaults keep connections open between calls, as migrations send thousands of them to the same host.
+type TransportConfig struct {
+	// MaxIdleConnsPerHost is the number of idle connections kept open for reuse
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost caps the connections opened to the host, 0 meaning no limit
+	MaxConnsPerHost int
+	// IdleConnTimeout closes connections left idle for longer
+	IdleConnTimeout time.Duration
+	// KeepAlive is the interval of TCP keep-alive probes; a negative value disables them
+	KeepAlive time.Duration
+	// DisableCompression stops asking for gzip-compressed responses
+	DisableCompression bool
+}
+
+// DefaultTransportConfig returns the transport configuration used when none is configured
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		MaxIdleConnsPerHost: 32,
+		MaxConnsPerHost:     64,
+		IdleConnTimeout:     90 * time.Second,
+		KeepAlive:           30 * time.Second,
+	}
+}
+
+// TLSConfig configures how the client authenticates the server and itself,
+// e.g. for on-premise instances behind an internal certificate authority
+type TLSConfig struct {
+	// CAFile is a PEM bundle of root certificates trusted in addition to the system ones
+	CAFile string
+	// CertFile and KeyFile are the PEM client certificate and key presented to the server
+	CertFile string
+	KeyFile  string
+	// InsecureSkipVerify accepts any server certificate. Only meant for test instances.
+	InsecureSkipVerify bool
+}
+
+// enabled tells whether the configuration changes the default TLS settings
+func (c TLSConfig) enabled() bool {
+	return c.CAFile != "" || c.CertFile != "" || c.KeyFile != "" || c.InsecureSkipVerify
+}
+
+// build loads the certificates of the configuration
+func (c TLSConfig) build() (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA bundle: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in CA bundle %s", c.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if c.CertFile != "" || c.KeyFile != "" {
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, fmt.Errorf("client certificate and key must be given together")
+		}
+
+		certificate, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	return config, nil
+}
+
+// newTransport builds an HTTP transport from the defaults of the standard library and the given configuration
+func newTransport(config TransportConfig) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: config.KeepAlive}
+	transport.DialContext = dialer.DialContext
+	transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+	if transport.MaxIdleConns < config.MaxIdleConnsPerHost {
+		transport.MaxIdleConns = config.MaxIdleConnsPerHost
+	}
+	transport.MaxConnsPerHost = config.MaxConnsPerHost
+	transport.IdleConnTimeout = config.IdleConnTimeout
+	// The transport asks for gzip and decompresses responses itself unless compression is disabled
+	transport.DisableCompression = config.DisableCompression
+
+	return transport
+}
+
+// Option customizes a Client created by NewClient
+type Option func(*clientOptions)
+
+// clientOptions collects the options before the client is built
+type clientOptions struct {
+	timeout     time.Duration
+	httpClient  *http.Client
+	logger      Logger
+	logLevel    Level
+	retryPolicy RetryPolicy
+	transport   TransportConfig
+	tls         TLSConfig
+	middlewares []Middleware
+	metrics     MetricsRecorder
+	userAgent   string
+	// correlationHeader and correlationID are sent with every request when the ID is set
+	correlationHeader string
+	correlationID     string
+}
+
+// defaultOptions returns the options of a client created without options
+func defaultOptions() clientOptions {
+	return clientOptions{
+		logger:      log.New(os.Stdout, "", 0),
+		logLevel:    LevelInfo,
+		retryPolicy: DefaultRetryPolicy(),
+		transport:   DefaultTransportConfig(),
+		userAgent:   DefaultUserAgent,
+	}
+}
+
+// WithTimeout sets the timeout of every HTTP call (30s by default)
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithHTTPClient uses a copy of the given HTTP client, e.g. to configure proxies or TLS.
+// The client keeps its own timeout unless WithTimeout is also given, and its own transport when it has one.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *clientOptions) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithLogger sends the diagnostic messages of the client to logger instead of stdout
+func WithLogger(logger Logger) Option {
+	return func(o *clientOptions) {
+		o.logger = logger
+	}
+}
+
+// WithLogLevel sets the minimum level of the messages sent to the logger (LevelInfo by default).
+// LevelDebug prints the payloads exchanged with the API.
+func WithLogLevel(level Level) Option {
+	return func(o *clientOptions) {
+		o.logLevel = level
+	}
+}
+
+// WithRetryPolicy sets how throttled requests are replayed
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *clientOptions) {
+		o.retryPolicy = policy
+	}
+}
+
+// WithTransportConfig tunes the connection pool, keep-alive and compression of the HTTP transport.
+// Start from DefaultTransportConfig to change only some settings.
+func WithTransportConfig(config TransportConfig) Option {
+	return func(o *clientOptions) {
+		o.transport = config
+	}
+}
+
+// WithTLSConfig trusts a custom certificate authority, presents a client certificate or skips
+// the verification of the server certificate. It applies to the transport built by the client.
+func WithTLSConfig(config TLSConfig) Option {
+	return func(o *clientOptions) {
+		o.tls = config
+	}
+}
+
+// WithMiddlewares wraps every request and response of the client, e.g. to add headers or capture payloads.
+// Middlewares run in the given order; the option can be repeated to append more.
+func WithMiddlewares(middlewares ...Middleware) Option {
+	return func(o *clientOptions) {
+		o.middlewares = append(o.middlewares, middlewares...)
+	}
+}
+
+// WithUserAgent sets the User-Agent of every request (DefaultUserAgent by default)
+func WithUserAgent(userAgent string) Option {
+	return func(o *clientOptions) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithCorrelationID sends id in the given header of every request, so the logs of the API can be
+// matched to a run of the migrator. An empty header means DefaultCorrelationHeader.
+func WithCorrelationID(header, id string) Option {
+	return func(o *clientOptions) {
+		if header == "" {
+			header = DefaultCorrelationHeader
+		}
+		o.correlationHeader = header
+		o.correlationID = id
+	}
+}
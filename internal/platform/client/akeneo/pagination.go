@@ -0,0 +1,145 @@
+package akeneo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// defaultPageSize is the number of items requested per page when listing
+const defaultPageSize = 100
+
+// listResponse is a page of an Akeneo list endpoint
+type listResponse[T any] struct {
+	Embedded struct {
+		Items []T `json:"items"`
+	} `json:"_embedded"`
+	Links struct {
+		Next *struct {
+			Href string `json:"href"`
+		} `json:"next"`
+	} `json:"_links"`
+	// ItemsCount is only returned when the page is requested with_count
+	ItemsCount *int `json:"items_count"`
+}
+
+// CountItems returns the number of items of a list endpoint, e.g. "products" or "families", reading
+// the items_count Akeneo returns with a single-item page. Endpoints that do not report their count,
+// like reference entity records, return an error.
+func (c *Client) CountItems(ctx context.Context, resource string) (int, error) {
+	return c.CountMatching(ctx, resource, "")
+}
+
+// CountMatching returns the number of items of a list endpoint matching a search filter, in the JSON
+// search syntax of the Akeneo API; an empty search counts every item, as CountItems does
+func (c *Client) CountMatching(ctx context.Context, resource, search string) (int, error) {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return 0, err
+	}
+
+	query := url.Values{}
+	query.Set("limit", "1")
+	query.Set("with_count", "true")
+	if search != "" {
+		query.Set("search", search)
+	}
+
+	page, err := fetchPage[map[string]interface{}](ctx, c, fmt.Sprintf("/api/rest/v1/%s?%s", resource, query.Encode()), resource)
+	if err != nil {
+		return 0, err
+	}
+	if page.ItemsCount == nil {
+		return 0, fmt.Errorf("%s do not report their count", resource)
+	}
+
+	return *page.ItemsCount, nil
+}
+
+// CountUpdatedSince returns the number of products or product models updated since a date, or in
+// the window closed by updatedUntil when it is set; resource is "products" or "product-models"
+func (c *Client) CountUpdatedSince(ctx context.Context, resource, updatedSince, updatedUntil string) (int, error) {
+	searchQuery, err := updatedSearchQuery(updatedSince, updatedUntil)
+	if err != nil {
+		return 0, err
+	}
+	return c.CountMatching(ctx, resource, searchQuery)
+}
+
+// streamSearchAfter lists products or product models with search_after pagination and calls fn
+// with each page as it arrives. Unlike page numbers, search_after is not capped by Akeneo and
+// stays fast on deep pages, so catalogs with more than 10k matching items are fully traversed.
+func streamSearchAfter[T any](ctx context.Context, c *Client, resource string, params url.Values, limit int, what string, fn func([]T) error) error {
+	query := url.Values{}
+	for key, values := range params {
+		query[key] = values
+	}
+	query.Set("pagination_type", "search_after")
+	query.Set("limit", strconv.Itoa(limit))
+
+	return streamPages(ctx, c, fmt.Sprintf("/api/rest/v1/%s?%s", resource, query.Encode()), what, fn)
+}
+
+// streamPages calls fn with each page of a list endpoint, following the next links until the last page.
+// The cursor is taken from the next link, which is resolved against the configured host.
+func streamPages[T any](ctx context.Context, c *Client, requestURI, what string, fn func([]T) error) error {
+	if err := c.ensureValidToken(ctx); err != nil {
+		return err
+	}
+
+	for requestURI != "" {
+		page, err := fetchPage[T](ctx, c, requestURI, what)
+		if err != nil {
+			return err
+		}
+
+		if len(page.Embedded.Items) > 0 {
+			if err := fn(page.Embedded.Items); err != nil {
+				return err
+			}
+		}
+
+		requestURI = ""
+		if page.Links.Next != nil {
+			next, err := url.Parse(page.Links.Next.Href)
+			if err != nil {
+				return fmt.Errorf("invalid next link %q: %w", page.Links.Next.Href, err)
+			}
+			requestURI = next.RequestURI()
+		}
+	}
+
+	return nil
+}
+
+// fetchPage retrieves one page of a list endpoint
+func fetchPage[T any](ctx context.Context, c *Client, requestURI, what string) (*listResponse[T], error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.config.Host+requestURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token())
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("error fetching %s: %d - %s", what, resp.StatusCode, string(body))
+	}
+
+	var page listResponse[T]
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, err
+	}
+
+	return &page, nil
+}
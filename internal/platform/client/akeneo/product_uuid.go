@@ -0,0 +1,127 @@
+package akeneo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// IdentifierAttributeType is the type of the attribute holding product identifiers
+const IdentifierAttributeType = "pim_catalog_identifier"
+
+// GetIdentifierAttribute returns the code of the main identifier attribute.
+// UUID endpoints carry the product identifier as a value of this attribute.
+func (c *Client) GetIdentifierAttribute(ctx context.Context) (string, error) {
+	params := url.Values{}
+	params.Set("search", fmt.Sprintf(`{"type":[{"operator":"=","value":"%s"}]}`, IdentifierAttributeType))
+
+	var codes []string
+	requestURI := "/api/rest/v1/attributes?" + params.Encode()
+	err := streamPages(ctx, c, requestURI, "identifier attribute", func(attributes []Attribute) error {
+		for _, attribute := range attributes {
+			code, _ := attribute["code"].(string)
+			if main, _ := attribute["is_main_identifier"].(bool); main {
+				codes = append([]string{code}, codes...)
+				continue
+			}
+			codes = append(codes, code)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if len(codes) == 0 || codes[0] == "" {
+		return "", fmt.Errorf("identifier attribute %w", ErrNotFound)
+	}
+
+	return codes[0], nil
+}
+
+// GetProductByUUID retrieves a product by its UUID
+func (c *Client) GetProductByUUID(ctx context.Context, uuid string) (Product, error) {
+	var productData Product
+	if err := c.getJSON(ctx, "products-uuid/"+uuid, "product '"+uuid+"'", &productData); err != nil {
+		return nil, err
+	}
+
+	return productData, nil
+}
+
+// GetProductUUIDs returns the UUIDs of the products with the given identifiers, indexed by identifier.
+// Identifiers unknown to the instance are left out.
+func (c *Client) GetProductUUIDs(ctx context.Context, identifierAttribute string, identifiers []string) (map[string]string, error) {
+	uuids := make(map[string]string, len(identifiers))
+
+	for start := 0; start < len(identifiers); start += maxCollectionSize {
+		end := start + maxCollectionSize
+		if end > len(identifiers) {
+			end = len(identifiers)
+		}
+
+		filter, err := json.Marshal(map[string]interface{}{
+			identifierAttribute: []map[string]interface{}{{"operator": "IN", "value": identifiers[start:end]}},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		params := url.Values{}
+		params.Set("search", string(filter))
+		params.Set("attributes", identifierAttribute)
+
+		err = streamSearchAfter(ctx, c, "products-uuid", params, maxCollectionSize, "product UUIDs", func(products []Product) error {
+			for _, productData := range products {
+				uuid, _ := productData["uuid"].(string)
+				if identifier := productIdentifier(productData, identifierAttribute); identifier != "" && uuid != "" {
+					uuids[identifier] = uuid
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return uuids, nil
+}
+
+// productIdentifier returns the identifier of a product read from a UUID endpoint
+func productIdentifier(productData Product, identifierAttribute string) string {
+	if identifier, ok := productData["identifier"].(string); ok {
+		return identifier
+	}
+
+	values, _ := productData["values"].(map[string]interface{})
+	entries, _ := values[identifierAttribute].([]interface{})
+	for _, entry := range entries {
+		value, _ := entry.(map[string]interface{})
+		if identifier, ok := value["data"].(string); ok {
+			return identifier
+		}
+	}
+
+	return ""
+}
+
+// PatchProductByUUID creates or updates a product by its UUID
+func (c *Client) PatchProductByUUID(ctx context.Context, uuid string, productData Product) error {
+	cleaned := c.cleanProduct(productData)
+	cleaned["uuid"] = uuid
+
+	return c.patchJSON(ctx, "products-uuid/"+uuid, "product "+uuid, cleaned)
+}
+
+// PatchProductsByUUID creates or updates several products keyed by their "uuid" with the collection endpoint,
+// up to 100 per call. It returns the errors of the products that were not written, indexed by UUID.
+func (c *Client) PatchProductsByUUID(ctx context.Context, products []Product) (map[string]error, error) {
+	items := make([]map[string]interface{}, len(products))
+	for i, productData := range products {
+		items[i] = c.cleanProduct(productData)
+	}
+
+	return c.patchCollection(ctx, "products-uuid", "uuid", "product", items)
+}
@@ -0,0 +1,25 @@
+package akeneo
+
+import (
+	"context"
+	"net/url"
+)
+
+// PublishedProduct represents the published version of a product (Enterprise Edition)
+type PublishedProduct map[string]interface{}
+
+// GetPublishedProduct retrieves the published version of a product by its identifier
+func (c *Client) GetPublishedProduct(ctx context.Context, identifier string) (PublishedProduct, error) {
+	var published PublishedProduct
+	if err := c.getJSON(ctx, "published-products/"+identifier, "published product '"+identifier+"'", &published); err != nil {
+		return nil, err
+	}
+
+	return published, nil
+}
+
+// StreamPublishedProducts processes the published products in batches of batchSize.
+// The endpoint is read-only: the API cannot publish products.
+func (c *Client) StreamPublishedProducts(ctx context.Context, batchSize int, callback func([]PublishedProduct) error) error {
+	return streamSearchAfter(ctx, c, "published-products", url.Values{}, batchSize, "published products", callback)
+}
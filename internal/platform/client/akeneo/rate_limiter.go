@@ -0,0 +1,134 @@
+package akeneo
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// maxRateLimitRetries is the default number of times a throttled request is replayed
+	maxRateLimitRetries = 5
+
+	// defaultRetryAfter is the default delay applied to a 429 without a usable Retry-After header
+	defaultRetryAfter = 5 * time.Second
+
+	// maxRetryAfter caps the delay requested by the server by default
+	maxRetryAfter = 5 * time.Minute
+)
+
+// rateLimiter honours the throttling headers sent by Akeneo. A 429 response delays every
+// subsequent request until Retry-After has elapsed; X-RateLimit-Remaining reaching zero delays
+// requests until X-RateLimit-Reset.
+type rateLimiter struct {
+	policy RetryPolicy
+	now    func() time.Time
+	sleep  func(ctx context.Context, d time.Duration) error
+
+	mu          sync.Mutex
+	nextAllowed time.Time
+}
+
+// newRateLimiter creates a rate limiter that lets requests through until the API throttles them
+func newRateLimiter(policy RetryPolicy) *rateLimiter {
+	return &rateLimiter{
+		policy: policy,
+		now:    time.Now,
+		sleep:  sleepContext,
+	}
+}
+
+// wait blocks until requests are allowed again
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	delay := l.nextAllowed.Sub(l.now())
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	return l.sleep(ctx, delay)
+}
+
+// observe reads the throttling headers of a response and pushes back the next allowed request
+func (l *rateLimiter) observe(resp *http.Response) {
+	var delay time.Duration
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		delay = l.retryAfter(resp)
+	} else if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		delay = parseRateLimitReset(resp.Header.Get("X-RateLimit-Reset"), l.now())
+	}
+
+	if delay <= 0 {
+		return
+	}
+	if delay > l.policy.MaxDelay {
+		delay = l.policy.MaxDelay
+	}
+
+	until := l.now().Add(delay)
+
+	l.mu.Lock()
+	if until.After(l.nextAllowed) {
+		l.nextAllowed = until
+	}
+	l.mu.Unlock()
+}
+
+// retryAfter returns the delay requested by a 429 response, capped by the retry policy
+func (l *rateLimiter) retryAfter(resp *http.Response) time.Duration {
+	delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), l.now())
+	if !ok {
+		delay = l.policy.DefaultDelay
+	}
+	if delay > l.policy.MaxDelay {
+		delay = l.policy.MaxDelay
+	}
+	return delay
+}
+
+// parseRetryAfter reads a Retry-After header, either a number of seconds or an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return date.Sub(now), true
+	}
+
+	return 0, false
+}
+
+// parseRateLimitReset reads an X-RateLimit-Reset header, either a number of seconds until the
+// reset or the Unix timestamp of the reset
+func parseRateLimitReset(value string, now time.Time) time.Duration {
+	reset, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || reset <= 0 {
+		return 0
+	}
+
+	// Values larger than a year of seconds are timestamps
+	if reset > 365*24*60*60 {
+		return time.Unix(reset, 0).Sub(now)
+	}
+
+	return time.Duration(reset) * time.Second
+}
+
+// sleepContext waits for a duration unless the context is done first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,21 @@
+package akeneo
+
+import "context"
+
+// SystemInformation describes the version and edition of an Akeneo instance
+type SystemInformation struct {
+	Version string `json:"version"`
+	// Edition is "CE", "EE", "Serenity" (SaaS), "GE" (Growth Edition) or "FT" (free trial)
+	Edition string `json:"edition"`
+}
+
+// GetSystemInformation retrieves the version and edition of the instance.
+// The endpoint exists since Akeneo 7 and on SaaS; older versions answer with ErrNotFound.
+func (c *Client) GetSystemInformation(ctx context.Context) (*SystemInformation, error) {
+	var info SystemInformation
+	if err := c.getJSON(ctx, "system-information", "system information", &info); err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
@@ -0,0 +1,77 @@
+package cassette
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == tokenPath {
+			_, _ = io.WriteString(w, `{"access_token":"secret-token","expires_in":3600}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"code":"shoes","_links":{"self":{"href":"http://`+r.Host+`/api/rest/v1/families/shoes"}}}`)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	client := &http.Client{Transport: NewRecorder(path, nil)}
+
+	form := url.Values{"grant_type": {"password"}, "username": {"admin"}, "password": {"hunter2"}}
+	resp, err := client.PostForm(server.URL+tokenPath, form)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	_ = resp.Body.Close()
+
+	resp, err = client.Get(server.URL + "/api/rest/v1/families/shoes")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	_ = resp.Body.Close()
+
+	recorded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Expected cassette to load, got %v", err)
+	}
+
+	if len(recorded.Interactions) != 2 {
+		t.Fatalf("Expected 2 interactions, got %d", len(recorded.Interactions))
+	}
+
+	token := recorded.Interactions[0]
+	if strings.Contains(token.Request.Body, "hunter2") || strings.Contains(token.Request.Body, "admin") {
+		t.Errorf("Expected credentials to be redacted, got %s", token.Request.Body)
+	}
+	if strings.Contains(token.Response.Body, "secret-token") {
+		t.Errorf("Expected token to be redacted, got %s", token.Response.Body)
+	}
+
+	family := recorded.Interactions[1]
+	if strings.Contains(family.Response.Body, server.URL) || !strings.Contains(family.Response.Body, Host) {
+		t.Errorf("Expected instance URL to be replaced, got %s", family.Response.Body)
+	}
+
+	replayClient := &http.Client{Transport: NewReplayer(recorded)}
+	resp, err = replayClient.Get(Host + "/api/rest/v1/families/shoes")
+	if err != nil {
+		t.Fatalf("Expected replayed response, got %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if !strings.Contains(string(body), `"code":"shoes"`) {
+		t.Errorf("Unexpected replayed body: %s", body)
+	}
+
+	if _, err := replayClient.Get(Host + "/api/rest/v1/families/shoes"); err == nil {
+		t.Error("Expected interactions to be consumed once")
+	}
+}
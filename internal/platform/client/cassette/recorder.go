@@ -0,0 +1,127 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// tokenPath is the Akeneo authentication endpoint, whose bodies contain credentials
+const tokenPath = "/api/oauth/v1/token"
+
+// Host replaces the recorded instance URL in bodies, such as pagination links
+const Host = "http://akeneo.test"
+
+// secretFields are JSON fields redacted from recorded bodies
+var secretFields = []string{"access_token", "refresh_token", "password", "secret"}
+
+// Recorder is a RoundTripper that forwards requests and records sanitized interactions.
+// The cassette file is rewritten after every interaction, so nothing is lost if a run aborts.
+type Recorder struct {
+	transport http.RoundTripper
+	path      string
+	cassette  Cassette
+	mu        sync.Mutex
+}
+
+// NewRecorder creates a recorder writing to path; transport defaults to http.DefaultTransport
+func NewRecorder(path string, transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Recorder{transport: transport, path: path}
+}
+
+// RoundTrip performs the request and records it
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.Body != nil {
+		var err error
+		if requestBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	interaction := Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    req.URL.RequestURI(),
+			Body:   sanitize(req.URL, string(requestBody)),
+		},
+		Response: Response{
+			Status:      resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			Body:        sanitize(req.URL, string(responseBody)),
+		},
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	if err := r.cassette.Save(r.path); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// sanitize removes credentials, tokens and the instance URL from a recorded body
+func sanitize(requestURL *url.URL, body string) string {
+	if body == "" {
+		return body
+	}
+
+	body = strings.ReplaceAll(body, requestURL.Scheme+"://"+requestURL.Host, Host)
+	body = strings.ReplaceAll(body, strings.ReplaceAll(requestURL.Scheme+"://"+requestURL.Host, "/", `\/`), Host)
+
+	if requestURL.Path == tokenPath {
+		if form, err := url.ParseQuery(body); err == nil && !strings.HasPrefix(body, "{") {
+			for key := range form {
+				if key != "grant_type" {
+					form.Set(key, Redacted)
+				}
+			}
+			return form.Encode()
+		}
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &document); err != nil {
+		return body
+	}
+
+	redacted := false
+	for _, field := range secretFields {
+		if _, exists := document[field]; exists {
+			document[field] = Redacted
+			redacted = true
+		}
+	}
+	if !redacted {
+		return body
+	}
+
+	data, err := json.Marshal(document)
+	if err != nil {
+		return body
+	}
+	return string(data)
+}
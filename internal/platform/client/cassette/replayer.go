@@ -0,0 +1,89 @@
+package cassette
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Replayer is a RoundTripper serving recorded interactions instead of calling the network.
+// Requests are matched by method and URL, in recording order, so repeated calls to the
+// same endpoint return successive responses.
+type Replayer struct {
+	cassette *Cassette
+	used     []bool
+	mu       sync.Mutex
+}
+
+// NewReplayer creates a replayer for a cassette
+func NewReplayer(cassette *Cassette) *Replayer {
+	return &Replayer{
+		cassette: cassette,
+		used:     make([]bool, len(cassette.Interactions)),
+	}
+}
+
+// LoadReplayer creates a replayer from a cassette file
+func LoadReplayer(path string) (*Replayer, error) {
+	cassette, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayer(cassette), nil
+}
+
+// RoundTrip returns the next recorded response matching the request
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || interaction.Request.Method != req.Method || interaction.Request.URL != req.URL.RequestURI() {
+			continue
+		}
+
+		// Authentication can happen any number of times, so token responses are never consumed
+		if req.URL.Path != tokenPath {
+			r.used[i] = true
+		}
+
+		header := make(http.Header)
+		if interaction.Response.ContentType != "" {
+			header.Set("Content-Type", interaction.Response.ContentType)
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+			StatusCode:    interaction.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("cassette: no recorded interaction for %s %s", req.Method, req.URL.RequestURI())
+}
+
+// Remaining returns the number of recorded interactions that were never replayed
+func (r *Replayer) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	remaining := 0
+	for i, interaction := range r.cassette.Interactions {
+		if !r.used[i] && interaction.Request.URL != tokenPath {
+			remaining++
+		}
+	}
+	return remaining
+}
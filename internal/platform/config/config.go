@@ -2,18 +2,286 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
 
+	"akeneo-migrator/kit/anonymize"
 	kit_config "akeneo-migrator/kit/config/static"
+	"akeneo-migrator/kit/conflict"
+	"akeneo-migrator/kit/filter"
+	"akeneo-migrator/kit/labels"
+	"akeneo-migrator/kit/locales"
+	"akeneo-migrator/kit/transform"
 
 	"github.com/spf13/viper"
 )
 
+// PairEnvVar selects one of the configured instance pairs
+const PairEnvVar = "AKENEO_PAIR"
+
+// Prefixes of the environment variables overriding the connection settings of the file,
+// e.g. AKENEO_SOURCE_HOST or AKENEO_DEST_ACCESS_TOKEN
+const (
+	SourceEnvPrefix = "AKENEO_SOURCE_"
+	DestEnvPrefix   = "AKENEO_DEST_"
+)
+
 // Config contains the configuration for source and destination
 type Config struct {
+	AkeneoSource AkeneoSource    `json:"akeneoSource" mapstructure:"akeneoSource"`
+	AkeneoDest   AkeneoDest      `json:"akeneoDest" mapstructure:"akeneoDest"`
+	Pairs        []Pair          `json:"pairs" mapstructure:"pairs"`
+	Sync         SyncConfig      `json:"sync" mapstructure:"sync"`
+	Mappings     MappingsConfig  `json:"mappings" mapstructure:"mappings"`
+	Anonymize    AnonymizeConfig `json:"anonymize" mapstructure:"anonymize"`
+	Filter       FilterConfig    `json:"filter" mapstructure:"filter"`
+	Transform    TransformConfig `json:"transform" mapstructure:"transform"`
+	State        StateConfig     `json:"state" mapstructure:"state"`
+	Source       Source          `json:"source" mapstructure:"source"`
+	Dest         Dest            `json:"dest" mapstructure:"dest"`
+}
+
+// Pair contains a named source → destination instance pair
+type Pair struct {
+	Name         string       `json:"name" mapstructure:"name"`
 	AkeneoSource AkeneoSource `json:"akeneoSource" mapstructure:"akeneoSource"`
 	AkeneoDest   AkeneoDest   `json:"akeneoDest" mapstructure:"akeneoDest"`
-	Source       Source       `json:"source" mapstructure:"source"`
-	Dest         Dest         `json:"dest" mapstructure:"dest"`
+}
+
+// SyncConfig contains the synchronization policies
+type SyncConfig struct {
+	// CategoryMove defines how categories whose parent changed are handled: "apply" (default) or "warn"
+	CategoryMove string `json:"categoryMove" mapstructure:"categoryMove"`
+	// LabelMerge defines how attribute option and record labels are merged: "overwrite" (default), "keep" or "union"
+	LabelMerge string `json:"labelMerge" mapstructure:"labelMerge"`
+	// AutoDeps lets sync commands satisfy missing dependencies in destination when possible
+	AutoDeps bool `json:"autoDeps" mapstructure:"autoDeps"`
+	// DisabledLocales defines what happens to values in locales not enabled in destination: "fail" (default) or "drop"
+	DisabledLocales string `json:"disabledLocales" mapstructure:"disabledLocales"`
+	// MissingAttributes defines what happens to product and model values of attributes missing in destination:
+	// "fail" (default) sends them and lets destination reject the item, "drop" strips them
+	MissingAttributes string `json:"missingAttributes" mapstructure:"missingAttributes"`
+	// MissingOptions defines what happens to products and models rejected because an attribute option does not
+	// exist in destination: "fail" (default) reports them, "create" copies the options from source and writes them again
+	MissingOptions string `json:"missingOptions" mapstructure:"missingOptions"`
+	// Media defines what happens to the image and file values of products and models: "copy" (default)
+	// downloads the files from source and uploads them to destination, "skip" leaves them out
+	Media string `json:"media" mapstructure:"media"`
+	// MediaRetries is the number of times a media file failing to download or upload is tried again: 2 when not set
+	MediaRetries int `json:"mediaRetries" mapstructure:"mediaRetries"`
+	// MissingTargets defines what happens to quantified association targets missing in destination: "drop" (default) or "sync"
+	MissingTargets string `json:"missingTargets" mapstructure:"missingTargets"`
+	// MissingAssociations defines what happens to association targets missing in destination: "fail" (default)
+	// sends the links and lets destination reject the item, "drop" removes them, "sync" syncs the targets first
+	MissingAssociations string `json:"missingAssociations" mapstructure:"missingAssociations"`
+	// AssociationDepth is the number of levels of association targets synced through each other: 2 when not set
+	AssociationDepth int `json:"associationDepth" mapstructure:"associationDepth"`
+	// ChangedOnly sends only the values and fields of existing products and models that differ from destination
+	ChangedOnly bool `json:"changedOnly" mapstructure:"changedOnly"`
+	// ProductFields defines a strategy per top-level product field ("values", "categories",
+	// "associations", "quantified_associations", "enabled") for items that already exist: "overwrite", "merge" or "keep"
+	ProductFields map[string]string `json:"productFields" mapstructure:"productFields"`
+	// Conflicts defines what happens to products and models edited in destination since their last sync:
+	// "source-wins", "dest-wins", "abort" or "interactive". Empty (default) disables conflict detection
+	Conflicts string `json:"conflicts" mapstructure:"conflicts"`
+	// Workers is the number of concurrent writes against destination run by record, asset and bulk product
+	// syncs: one at a time when not set
+	Workers int `json:"workers" mapstructure:"workers"`
+}
+
+// MappingsConfig contains source → destination code mappings.
+// Rules are lists instead of objects because configuration keys are case-insensitive.
+type MappingsConfig struct {
+	Categories []MappingRule `json:"categories" mapstructure:"categories"`
+	Locales    []MappingRule `json:"locales" mapstructure:"locales"`
+	Channels   []MappingRule `json:"channels" mapstructure:"channels"`
+	Attributes []MappingRule `json:"attributes" mapstructure:"attributes"`
+	Families   []MappingRule `json:"families" mapstructure:"families"`
+}
+
+// MappingRule maps a source code to a destination code
+type MappingRule struct {
+	From string `json:"from" mapstructure:"from"`
+	To   string `json:"to" mapstructure:"to"`
+}
+
+// CategoryMap returns the category mapping indexed by source code
+func (m MappingsConfig) CategoryMap() map[string]string {
+	return rulesToMap(m.Categories)
+}
+
+// LocaleMap returns the locale mapping indexed by source code
+func (m MappingsConfig) LocaleMap() map[string]string {
+	return rulesToMap(m.Locales)
+}
+
+// ChannelMap returns the channel mapping indexed by source code
+func (m MappingsConfig) ChannelMap() map[string]string {
+	return rulesToMap(m.Channels)
+}
+
+// AttributeMap returns the attribute mapping indexed by source code
+func (m MappingsConfig) AttributeMap() map[string]string {
+	return rulesToMap(m.Attributes)
+}
+
+// FamilyMap returns the family mapping indexed by source code
+func (m MappingsConfig) FamilyMap() map[string]string {
+	return rulesToMap(m.Families)
+}
+
+// rulesToMap indexes mapping rules by source code
+func rulesToMap(rules []MappingRule) map[string]string {
+	result := make(map[string]string, len(rules))
+	for _, rule := range rules {
+		result[rule.From] = rule.To
+	}
+	return result
+}
+
+// AnonymizeConfig contains the anonymization rules applied to synced values
+type AnonymizeConfig struct {
+	Salt  string          `json:"salt" mapstructure:"salt"`
+	Rules []AnonymizeRule `json:"rules" mapstructure:"rules"`
+}
+
+// AnonymizeRule anonymizes the values of one attribute: "hash", "faker", "constant" or "drop"
+type AnonymizeRule struct {
+	Attribute string      `json:"attribute" mapstructure:"attribute"`
+	Action    string      `json:"action" mapstructure:"action"`
+	Value     interface{} `json:"value" mapstructure:"value"`
+	Faker     string      `json:"faker" mapstructure:"faker"`
+}
+
+// Anonymizer builds the anonymizer described by the configuration
+func (a AnonymizeConfig) Anonymizer() (*anonymize.Anonymizer, error) {
+	rules := make([]anonymize.Rule, len(a.Rules))
+	for i, rule := range a.Rules {
+		rules[i] = anonymize.Rule{
+			Attribute: rule.Attribute,
+			Action:    anonymize.Action(rule.Action),
+			Value:     rule.Value,
+			Faker:     rule.Faker,
+		}
+	}
+	return anonymize.New(rules, a.Salt)
+}
+
+// FilterConfig restricts the product, product model and record values sent to destination.
+// Attribute lists only apply to products and product models.
+type FilterConfig struct {
+	// Locales keeps localizable values in these locales only. Empty (default) keeps every locale
+	Locales []string `json:"locales" mapstructure:"locales"`
+	// Channels keeps scoped values in these channels only, "source=dest" renaming a channel.
+	// Empty (default) keeps every channel
+	Channels []string `json:"channels" mapstructure:"channels"`
+	// IncludeAttributes keeps the product and model values of these attributes only. Empty (default) keeps every attribute
+	IncludeAttributes []string `json:"includeAttributes" mapstructure:"includeAttributes"`
+	// ExcludeAttributes removes the product and model values of these attributes
+	ExcludeAttributes []string `json:"excludeAttributes" mapstructure:"excludeAttributes"`
+}
+
+// ValueFilter builds the value filter of records described by the configuration
+func (f FilterConfig) ValueFilter() (*filter.Filter, error) {
+	return filter.New(filter.Rules{Locales: f.Locales, Channels: f.Channels})
+}
+
+// ProductValueFilter builds the value filter of products and product models described by the configuration
+func (f FilterConfig) ProductValueFilter() (*filter.Filter, error) {
+	return filter.New(filter.Rules{
+		Locales:            f.Locales,
+		Channels:           f.Channels,
+		Attributes:         f.IncludeAttributes,
+		ExcludedAttributes: f.ExcludeAttributes,
+	})
+}
+
+// TransformConfig contains the expression rules computing product values
+type TransformConfig struct {
+	Rules []TransformRule `json:"rules" mapstructure:"rules"`
+}
+
+// TransformRule computes the data of an attribute with an expression, optionally under a condition
+type TransformRule struct {
+	Attribute  string `json:"attribute" mapstructure:"attribute"`
+	Expression string `json:"expression" mapstructure:"expression"`
+	When       string `json:"when" mapstructure:"when"`
+	// Locale and Scope restrict the rule to the values in this locale and channel
+	Locale string `json:"locale" mapstructure:"locale"`
+	Scope  string `json:"scope" mapstructure:"scope"`
+}
+
+// Transformer builds the transformer described by the configuration
+func (t TransformConfig) Transformer() (*transform.Transformer, error) {
+	rules := make([]transform.Rule, len(t.Rules))
+	for i, rule := range t.Rules {
+		rules[i] = transform.Rule{
+			Attribute:  rule.Attribute,
+			Expression: rule.Expression,
+			When:       rule.When,
+			Locale:     rule.Locale,
+			Scope:      rule.Scope,
+		}
+	}
+	return transform.New(rules)
+}
+
+// DefaultStateDir is the state store directory used when none is configured
+const DefaultStateDir = ".akeneo-migrator"
+
+// StateConfig contains the location of the local state store
+type StateConfig struct {
+	Dir string `json:"dir" mapstructure:"dir"`
+}
+
+// JobsDir returns the directory where the jobs with failed items are stored
+func (s StateConfig) JobsDir() string {
+	dir := s.Dir
+	if dir == "" {
+		dir = DefaultStateDir
+	}
+	return filepath.Join(dir, "jobs")
+}
+
+// BaselinesDir returns the directory where the update dates of the items synced to a destination host are stored
+func (s StateConfig) BaselinesDir(host string) string {
+	dir := s.Dir
+	if dir == "" {
+		dir = DefaultStateDir
+	}
+	return filepath.Join(dir, "baselines", hostName(host))
+}
+
+// CheckpointFile returns the file where the progress of the migration to a destination host is stored
+func (s StateConfig) CheckpointFile(host string) string {
+	dir := s.Dir
+	if dir == "" {
+		dir = DefaultStateDir
+	}
+	return filepath.Join(dir, "migrations", hostName(host)+".json")
+}
+
+// RunsFile returns the file where the end of the last successful incremental syncs from a source host
+// to a destination host is stored
+func (s StateConfig) RunsFile(source, dest string) string {
+	dir := s.Dir
+	if dir == "" {
+		dir = DefaultStateDir
+	}
+	return filepath.Join(dir, "runs", hostName(dest), hostName(source)+".json")
+}
+
+// hostName turns a host URL into a name usable in file paths
+func hostName(host string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://"))
 }
 
 // AkeneoSource contains the source Akeneo configuration from JSON
@@ -30,6 +298,148 @@ type AkeneoDest struct {
 type APIConfig struct {
 	URL         string      `json:"url" mapstructure:"url"`
 	Credentials Credentials `json:"credentials" mapstructure:"credentials"`
+	// Version is the Akeneo version of the instance, e.g. "6.0", "7.0" or "saas"
+	Version string `json:"version" mapstructure:"version"`
+	// Edition is the Akeneo edition of the instance: "CE", "EE", "Serenity" (SaaS), "GE" (Growth Edition) or "FT".
+	// Version and edition are detected at startup when left empty.
+	Edition string `json:"edition" mapstructure:"edition"`
+	// TLS configures the certificates used to reach the instance
+	TLS TLSConfig `json:"tls" mapstructure:"tls"`
+}
+
+// TLSConfig contains the certificates of instances using an internal certificate authority
+type TLSConfig struct {
+	// CAFile is a PEM bundle of root certificates trusted in addition to the system ones
+	CAFile string `json:"caFile" mapstructure:"caFile"`
+	// CertFile and KeyFile are a PEM client certificate and its key
+	CertFile string `json:"certFile" mapstructure:"certFile"`
+	KeyFile  string `json:"keyFile" mapstructure:"keyFile"`
+	// InsecureSkipVerify disables the verification of the server certificate
+	InsecureSkipVerify bool `json:"insecureSkipVerify" mapstructure:"insecureSkipVerify"`
+}
+
+// Feature is an Akeneo feature not available on every edition or version
+type Feature string
+
+const (
+	FeatureProductUUIDs      Feature = "product UUIDs"
+	FeatureReferenceEntities Feature = "reference entities"
+	FeatureAssetManager      Feature = "asset manager"
+)
+
+// ProductUUIDs tells whether the instance keys products by UUID (Akeneo 7 and later, SaaS)
+func (a APIConfig) ProductUUIDs() bool {
+	if a.saas() {
+		return true
+	}
+
+	major, ok := a.majorVersion()
+	return ok && major >= 7
+}
+
+// Supports tells whether the instance provides a feature.
+// An instance whose edition is unknown is assumed to provide every feature.
+func (a APIConfig) Supports(feature Feature) bool {
+	edition := strings.ToLower(strings.TrimSpace(a.Edition))
+
+	switch feature {
+	case FeatureProductUUIDs:
+		return a.ProductUUIDs()
+	case FeatureReferenceEntities:
+		return edition != "ce" && edition != "ge"
+	case FeatureAssetManager:
+		// The Asset Manager replaced the PAM in Enterprise Edition 3.2
+		if edition == "ce" || edition == "ge" {
+			return false
+		}
+		major, minor, ok := a.version()
+		return a.saas() || !ok || major > 3 || (major == 3 && minor >= 2)
+	}
+
+	return true
+}
+
+// saas tells whether the instance is a SaaS (Serenity or Growth Edition) instance
+func (a APIConfig) saas() bool {
+	version := strings.ToLower(strings.TrimSpace(a.Version))
+	if version == "saas" || version == "serenity" || version == "growth" {
+		return true
+	}
+
+	edition := strings.ToLower(strings.TrimSpace(a.Edition))
+	return edition == "serenity" || edition == "ge" || edition == "ft"
+}
+
+// majorVersion returns the major version of an on-premise instance
+func (a APIConfig) majorVersion() (int, bool) {
+	major, _, ok := a.version()
+	return major, ok
+}
+
+// version returns the major and minor version of an on-premise instance
+func (a APIConfig) version() (int, int, bool) {
+	version := strings.ToLower(strings.TrimSpace(a.Version))
+	majorPart, rest, _ := strings.Cut(strings.TrimPrefix(version, "v"), ".")
+	major, err := strconv.Atoi(majorPart)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	minorPart, _, _ := strings.Cut(rest, ".")
+	minor, _ := strconv.Atoi(minorPart) //nolint:errcheck // a missing minor version means .0
+	return major, minor, true
+}
+
+// RequireFeature returns an error naming the instance that lacks a feature, checking source then destination
+func (c *Config) RequireFeature(feature Feature) error {
+	if !c.AkeneoSource.API.Supports(feature) {
+		return fmt.Errorf("%s are not available on the source instance (%s)", feature, c.AkeneoSource.API.describe())
+	}
+	if !c.AkeneoDest.API.Supports(feature) {
+		return fmt.Errorf("%s are not available on the destination instance (%s)", feature, c.AkeneoDest.API.describe())
+	}
+	return nil
+}
+
+// CheckCompatibility returns an error when data cannot be migrated from source to destination:
+// a destination older than the source does not accept its data model.
+// Instances whose version is unknown are not checked.
+func (c *Config) CheckCompatibility() error {
+	source, dest := c.AkeneoSource.API, c.AkeneoDest.API
+	if dest.saas() {
+		return nil
+	}
+
+	destMajor, destOK := dest.majorVersion()
+	if !destOK {
+		return nil
+	}
+
+	// SaaS instances follow the data model of the latest on-premise version
+	if source.saas() && destMajor < 7 {
+		return fmt.Errorf("incompatible instances: a SaaS source requires a destination on Akeneo 7 or later (%s)", dest.describe())
+	}
+
+	if sourceMajor, ok := source.majorVersion(); ok && !source.saas() && sourceMajor > destMajor {
+		return fmt.Errorf("incompatible instances: the destination (%s) is older than the source (%s)", dest.describe(), source.describe())
+	}
+
+	return nil
+}
+
+// describe returns the edition and version of the instance for messages
+func (a APIConfig) describe() string {
+	parts := make([]string, 0, 2)
+	if a.Edition != "" {
+		parts = append(parts, a.Edition)
+	}
+	if a.Version != "" {
+		parts = append(parts, a.Version)
+	}
+	if len(parts) == 0 {
+		return "unknown version"
+	}
+	return strings.Join(parts, " ")
 }
 
 // Credentials contains the access credentials
@@ -38,59 +448,151 @@ type Credentials struct {
 	Secret   string `json:"secret" mapstructure:"secret"`
 	Username string `json:"username" mapstructure:"username"`
 	Password string `json:"password" mapstructure:"password"`
+	// AccessToken is the token of an Akeneo App, used instead of the connection credentials
+	AccessToken string `json:"accessToken" mapstructure:"accessToken"`
 }
 
 // Source contains the source Akeneo configuration (for compatibility)
 type Source struct {
-	Host     string `json:"host" mapstructure:"host"`
-	ClientID string `json:"clientId" mapstructure:"clientId"`
-	Secret   string `json:"secret" mapstructure:"secret"`
-	Username string `json:"username" mapstructure:"username"`
-	Password string `json:"password" mapstructure:"password"`
+	Host        string `json:"host" mapstructure:"host"`
+	ClientID    string `json:"clientId" mapstructure:"clientId"`
+	Secret      string `json:"secret" mapstructure:"secret"`
+	Username    string `json:"username" mapstructure:"username"`
+	Password    string `json:"password" mapstructure:"password"`
+	AccessToken string `json:"accessToken" mapstructure:"accessToken"`
 }
 
 // Dest contains the destination Akeneo configuration (for compatibility)
 type Dest struct {
-	Host     string `json:"host" mapstructure:"host"`
-	ClientID string `json:"clientId" mapstructure:"clientId"`
-	Secret   string `json:"secret" mapstructure:"secret"`
-	Username string `json:"username" mapstructure:"username"`
-	Password string `json:"password" mapstructure:"password"`
+	Host        string `json:"host" mapstructure:"host"`
+	ClientID    string `json:"clientId" mapstructure:"clientId"`
+	Secret      string `json:"secret" mapstructure:"secret"`
+	Username    string `json:"username" mapstructure:"username"`
+	Password    string `json:"password" mapstructure:"password"`
+	AccessToken string `json:"accessToken" mapstructure:"accessToken"`
+}
+
+// Connection contains the connection settings of an instance given outside the settings file.
+// Empty fields keep the value of the file.
+type Connection struct {
+	Host        string
+	ClientID    string
+	Secret      string
+	Username    string
+	Password    string
+	AccessToken string
+}
+
+// merge returns the connection with its empty fields taken from fallback
+func (c Connection) merge(fallback Connection) Connection {
+	pick := func(value, fallback string) string {
+		if value != "" {
+			return value
+		}
+		return fallback
+	}
+	return Connection{
+		Host:        pick(c.Host, fallback.Host),
+		ClientID:    pick(c.ClientID, fallback.ClientID),
+		Secret:      pick(c.Secret, fallback.Secret),
+		Username:    pick(c.Username, fallback.Username),
+		Password:    pick(c.Password, fallback.Password),
+		AccessToken: pick(c.AccessToken, fallback.AccessToken),
+	}
+}
+
+// connectionFromEnv reads the connection settings of the environment variables starting with prefix:
+// HOST, CLIENT_ID, SECRET, USERNAME, PASSWORD and ACCESS_TOKEN
+func connectionFromEnv(prefix string) Connection {
+	return Connection{
+		Host:        os.Getenv(prefix + "HOST"),
+		ClientID:    os.Getenv(prefix + "CLIENT_ID"),
+		Secret:      os.Getenv(prefix + "SECRET"),
+		Username:    os.Getenv(prefix + "USERNAME"),
+		Password:    os.Getenv(prefix + "PASSWORD"),
+		AccessToken: os.Getenv(prefix + "ACCESS_TOKEN"),
+	}
+}
+
+// Overrides replaces the connection settings of the file, so one settings file can target
+// different instance pairs, e.g. from CLI flags
+type Overrides struct {
+	Source Connection
+	Dest   Connection
+}
+
+// apply replaces the connection settings of the configuration by the overrides, then by the
+// AKENEO_SOURCE_* and AKENEO_DEST_* environment variables when fromEnv is set, the overrides
+// taking precedence
+func (o Overrides) apply(config *Config, fromEnv bool) {
+	var sourceEnv, destEnv Connection
+	if fromEnv {
+		sourceEnv = connectionFromEnv(SourceEnvPrefix)
+		destEnv = connectionFromEnv(DestEnvPrefix)
+	}
+
+	source := o.Source.merge(sourceEnv).merge(Connection(config.Source))
+	config.Source = Source(source)
+	config.AkeneoSource.API.URL = source.Host
+
+	dest := o.Dest.merge(destEnv).merge(Connection(config.Dest))
+	config.Dest = Dest(dest)
+	config.AkeneoDest.API.URL = dest.Host
 }
 
 // LoadConfig loads the configuration using Viper
-func LoadConfig(configLoader kit_config.ConfigurationLoader) (*Config, error) {
-	// Get Viper instance for the context
-	v := viper.Get("akeneo-migrator").(viper.Viper)
+// If AKENEO_PAIR is set, the named pair replaces the top-level source and destination.
+// The connection settings of the overrides and environment variables then replace the ones of the file.
+// A selected pair ignores the environment variables, which the runner passes to every pair it runs.
+func LoadConfig(configLoader kit_config.ConfigurationLoader, overrides Overrides) (*Config, error) {
+	config, err := unmarshalConfig()
+	if err != nil {
+		return nil, err
+	}
 
-	config := &Config{}
+	if err := validatePairs(config.Pairs); err != nil {
+		return nil, err
+	}
 
-	// Unmarshal the complete configuration
-	if err := v.Unmarshal(config); err != nil {
-		return nil, fmt.Errorf("error deserializing configuration: %w", err)
+	pairName := os.Getenv(PairEnvVar)
+	if pairName != "" {
+		pair, found := config.FindPair(pairName)
+		if !found {
+			return nil, fmt.Errorf("pair '%s' is not configured", pairName)
+		}
+		config.AkeneoSource = pair.AkeneoSource
+		config.AkeneoDest = pair.AkeneoDest
+	} else if config.AkeneoSource.API.URL == "" && config.AkeneoDest.API.URL == "" && len(config.Pairs) > 0 {
+		// Without a top-level pair, the first configured pair is the default one
+		config.AkeneoSource = config.Pairs[0].AkeneoSource
+		config.AkeneoDest = config.Pairs[0].AkeneoDest
 	}
 
 	// Map from JSON structure to compatibility structure
 	if config.AkeneoSource.API.URL != "" {
 		config.Source = Source{
-			Host:     config.AkeneoSource.API.URL,
-			ClientID: config.AkeneoSource.API.Credentials.ClientID,
-			Secret:   config.AkeneoSource.API.Credentials.Secret,
-			Username: config.AkeneoSource.API.Credentials.Username,
-			Password: config.AkeneoSource.API.Credentials.Password,
+			Host:        config.AkeneoSource.API.URL,
+			ClientID:    config.AkeneoSource.API.Credentials.ClientID,
+			Secret:      config.AkeneoSource.API.Credentials.Secret,
+			Username:    config.AkeneoSource.API.Credentials.Username,
+			Password:    config.AkeneoSource.API.Credentials.Password,
+			AccessToken: config.AkeneoSource.API.Credentials.AccessToken,
 		}
 	}
 
 	if config.AkeneoDest.API.URL != "" {
 		config.Dest = Dest{
-			Host:     config.AkeneoDest.API.URL,
-			ClientID: config.AkeneoDest.API.Credentials.ClientID,
-			Secret:   config.AkeneoDest.API.Credentials.Secret,
-			Username: config.AkeneoDest.API.Credentials.Username,
-			Password: config.AkeneoDest.API.Credentials.Password,
+			Host:        config.AkeneoDest.API.URL,
+			ClientID:    config.AkeneoDest.API.Credentials.ClientID,
+			Secret:      config.AkeneoDest.API.Credentials.Secret,
+			Username:    config.AkeneoDest.API.Credentials.Username,
+			Password:    config.AkeneoDest.API.Credentials.Password,
+			AccessToken: config.AkeneoDest.API.Credentials.AccessToken,
 		}
 	}
 
+	overrides.apply(config, pairName == "")
+
 	// Validate configuration
 	if err := validateConfig(config); err != nil {
 		return nil, err
@@ -99,20 +601,164 @@ func LoadConfig(configLoader kit_config.ConfigurationLoader) (*Config, error) {
 	return config, nil
 }
 
+// LoadPairs loads only the configured instance pairs, without validating connections
+func LoadPairs(configLoader kit_config.ConfigurationLoader) ([]Pair, error) {
+	config, err := unmarshalConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(config.Pairs) == 0 {
+		return nil, fmt.Errorf("no instance pairs configured")
+	}
+
+	if err := validatePairs(config.Pairs); err != nil {
+		return nil, err
+	}
+
+	return config.Pairs, nil
+}
+
+// validatePairs checks that every pair has a unique name usable as a file name,
+// since run-pairs writes the log of each pair to <name>.log
+func validatePairs(pairs []Pair) error {
+	seen := make(map[string]bool, len(pairs))
+	for i, pair := range pairs {
+		switch {
+		case pair.Name == "":
+			return fmt.Errorf("pair #%d has no name", i+1)
+		case pair.Name == "." || pair.Name == ".." || strings.ContainsAny(pair.Name, `/\`):
+			return fmt.Errorf("invalid pair name '%s': path separators are not allowed", pair.Name)
+		case seen[pair.Name]:
+			return fmt.Errorf("pair '%s' is configured more than once", pair.Name)
+		}
+		seen[pair.Name] = true
+	}
+	return nil
+}
+
+// FindPair returns the pair with the given name
+func (c *Config) FindPair(name string) (Pair, bool) {
+	for _, pair := range c.Pairs {
+		if pair.Name == name {
+			return pair, true
+		}
+	}
+	return Pair{}, false
+}
+
+// unmarshalConfig reads the raw configuration from the Viper context
+func unmarshalConfig() (*Config, error) {
+	// Get Viper instance for the context
+	v := viper.Get("akeneo-migrator").(viper.Viper)
+
+	config := &Config{}
+
+	// Unmarshal the complete configuration
+	if err := v.Unmarshal(config); err != nil {
+		return nil, fmt.Errorf("error deserializing configuration: %w", err)
+	}
+
+	return config, nil
+}
+
 func validateConfig(config *Config) error {
-	// Validate source configuration
-	if config.Source.Host == "" || config.Source.ClientID == "" ||
+	// Validate source configuration: an App token replaces the connection credentials
+	if config.Source.Host == "" || (config.Source.AccessToken == "" && (config.Source.ClientID == "" ||
 		config.Source.Secret == "" || config.Source.Username == "" ||
-		config.Source.Password == "" {
+		config.Source.Password == "")) {
 		return fmt.Errorf("incomplete SOURCE configuration")
 	}
 
 	// Validate destination configuration
-	if config.Dest.Host == "" || config.Dest.ClientID == "" ||
+	if config.Dest.Host == "" || (config.Dest.AccessToken == "" && (config.Dest.ClientID == "" ||
 		config.Dest.Secret == "" || config.Dest.Username == "" ||
-		config.Dest.Password == "" {
+		config.Dest.Password == "")) {
 		return fmt.Errorf("incomplete DEST configuration")
 	}
 
+	// Validate TLS settings
+	for name, tls := range map[string]TLSConfig{"source": config.AkeneoSource.API.TLS, "destination": config.AkeneoDest.API.TLS} {
+		if (tls.CertFile == "") != (tls.KeyFile == "") {
+			return fmt.Errorf("incomplete %s TLS configuration: certFile and keyFile must be set together", name)
+		}
+	}
+
+	// Validate synchronization policies
+	switch config.Sync.CategoryMove {
+	case "", "apply", "warn":
+	default:
+		return fmt.Errorf("invalid sync.categoryMove '%s' (expected apply or warn)", config.Sync.CategoryMove)
+	}
+
+	if _, err := labels.ParseStrategy(config.Sync.LabelMerge); err != nil {
+		return fmt.Errorf("invalid sync.labelMerge: %w", err)
+	}
+
+	if _, err := locales.ParsePolicy(config.Sync.DisabledLocales); err != nil {
+		return fmt.Errorf("invalid sync.disabledLocales: %w", err)
+	}
+
+	switch config.Sync.MissingAttributes {
+	case "", "fail", "drop":
+	default:
+		return fmt.Errorf("invalid sync.missingAttributes '%s' (expected fail or drop)", config.Sync.MissingAttributes)
+	}
+
+	switch config.Sync.MissingOptions {
+	case "", "fail", "create":
+	default:
+		return fmt.Errorf("invalid sync.missingOptions '%s' (expected fail or create)", config.Sync.MissingOptions)
+	}
+
+	switch config.Sync.Media {
+	case "", "copy", "skip":
+	default:
+		return fmt.Errorf("invalid sync.media '%s' (expected copy or skip)", config.Sync.Media)
+	}
+
+	if config.Sync.MediaRetries < 0 {
+		return fmt.Errorf("invalid sync.mediaRetries %d (expected 0 or more)", config.Sync.MediaRetries)
+	}
+
+	switch config.Sync.MissingTargets {
+	case "", "drop", "sync":
+	default:
+		return fmt.Errorf("invalid sync.missingTargets '%s' (expected drop or sync)", config.Sync.MissingTargets)
+	}
+
+	switch config.Sync.MissingAssociations {
+	case "", "fail", "drop", "sync":
+	default:
+		return fmt.Errorf("invalid sync.missingAssociations '%s' (expected fail, drop or sync)", config.Sync.MissingAssociations)
+	}
+
+	if config.Sync.AssociationDepth < 0 {
+		return fmt.Errorf("invalid sync.associationDepth %d (expected 0 or more)", config.Sync.AssociationDepth)
+	}
+
+	if _, err := conflict.ParseStrategy(config.Sync.Conflicts); err != nil {
+		return fmt.Errorf("invalid sync.conflicts: %w", err)
+	}
+
+	if _, err := config.Anonymize.Anonymizer(); err != nil {
+		return fmt.Errorf("invalid anonymize configuration: %w", err)
+	}
+
+	if _, err := config.Filter.ValueFilter(); err != nil {
+		return fmt.Errorf("invalid filter configuration: %w", err)
+	}
+
+	if _, err := config.Transform.Transformer(); err != nil {
+		return fmt.Errorf("invalid transform configuration: %w", err)
+	}
+
+	// Validate mappings
+	for _, rule := range config.Mappings.Categories {
+		if rule.From == "" || rule.To == "" {
+			return fmt.Errorf("invalid category mapping: both 'from' and 'to' are required")
+		}
+	}
+
 	return nil
 }
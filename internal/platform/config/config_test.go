@@ -0,0 +1,103 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestOverrides_TakePrecedenceOverEnvironmentAndFile(t *testing.T) {
+	t.Setenv("AKENEO_SOURCE_HOST", "https://env-source.example.com")
+	t.Setenv("AKENEO_SOURCE_SECRET", "env-secret")
+	t.Setenv("AKENEO_DEST_ACCESS_TOKEN", "env-token")
+
+	config := &Config{
+		Source: Source{Host: "https://file-source.example.com", ClientID: "file-client", Secret: "file-secret"},
+		Dest:   Dest{Host: "https://file-dest.example.com", AccessToken: "file-token"},
+	}
+	overrides := Overrides{
+		Source: Connection{Host: "https://flag-source.example.com"},
+		Dest:   Connection{Host: "https://flag-dest.example.com"},
+	}
+
+	overrides.apply(config, true)
+
+	expectedSource := Source{Host: "https://flag-source.example.com", ClientID: "file-client", Secret: "env-secret"}
+	if config.Source != expectedSource {
+		t.Errorf("Expected source %+v, got %+v", expectedSource, config.Source)
+	}
+	expectedDest := Dest{Host: "https://flag-dest.example.com", AccessToken: "env-token"}
+	if config.Dest != expectedDest {
+		t.Errorf("Expected destination %+v, got %+v", expectedDest, config.Dest)
+	}
+	if config.AkeneoSource.API.URL != "https://flag-source.example.com" {
+		t.Errorf("Expected the source URL to follow the host, got %s", config.AkeneoSource.API.URL)
+	}
+}
+
+func TestOverrides_CompleteAConnectionMissingInTheFile(t *testing.T) {
+	t.Setenv("AKENEO_DEST_HOST", "https://env-dest.example.com")
+	t.Setenv("AKENEO_DEST_ACCESS_TOKEN", "env-token")
+
+	config := &Config{Source: Source{Host: "https://file-source.example.com", AccessToken: "token"}}
+	Overrides{}.apply(config, true)
+
+	if err := validateConfig(config); err != nil {
+		t.Errorf("Expected the destination of the environment to be valid, got %v", err)
+	}
+}
+
+func TestLoadConfig_PairIgnoresEnvironmentOverrides(t *testing.T) {
+	v := viper.New()
+	v.SetConfigType("json")
+	settings := `{"pairs": [{
+		"name": "acme",
+		"akeneoSource": {"api": {"url": "https://acme-source.example.com", "credentials": {"accessToken": "source-token"}}},
+		"akeneoDest": {"api": {"url": "https://acme-dest.example.com", "credentials": {"accessToken": "dest-token"}}}
+	}]}`
+	if err := v.ReadConfig(strings.NewReader(settings)); err != nil {
+		t.Fatalf("Expected valid settings, got %v", err)
+	}
+	viper.Set("akeneo-migrator", *v)
+	t.Cleanup(viper.Reset)
+
+	// The runner passes the variables exported for the default pair to every pair it runs
+	t.Setenv(PairEnvVar, "acme")
+	t.Setenv("AKENEO_SOURCE_HOST", "https://env-source.example.com")
+	t.Setenv("AKENEO_DEST_ACCESS_TOKEN", "env-token")
+
+	config, err := LoadConfig(nil, Overrides{Dest: Connection{Host: "https://flag-dest.example.com"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if config.Source.Host != "https://acme-source.example.com" {
+		t.Errorf("Expected the source of the pair, got %s", config.Source.Host)
+	}
+	if config.Dest.AccessToken != "dest-token" {
+		t.Errorf("Expected the token of the pair, got %s", config.Dest.AccessToken)
+	}
+	if config.Dest.Host != "https://flag-dest.example.com" {
+		t.Errorf("Expected the flag to replace the destination of the pair, got %s", config.Dest.Host)
+	}
+}
+
+func TestValidatePairs(t *testing.T) {
+	if err := validatePairs([]Pair{{Name: "acme"}, {Name: "globex"}}); err != nil {
+		t.Errorf("Expected distinct names to be valid, got %v", err)
+	}
+
+	invalid := map[string][]Pair{
+		"no name":        {{Name: ""}},
+		"path separator": {{Name: "../acme"}},
+		"backslash":      {{Name: `acme\prod`}},
+		"parent":         {{Name: ".."}},
+		"duplicate":      {{Name: "acme"}, {Name: "acme"}},
+	}
+	for name, pairs := range invalid {
+		if err := validatePairs(pairs); err == nil {
+			t.Errorf("Expected %s to be rejected", name)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package mockserver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Filter is one condition of an Akeneo search query
+type Filter struct {
+	Operator string      `json:"operator"`
+	Value    interface{} `json:"value"`
+}
+
+// matches reports whether an item satisfies every filter of a search query.
+// Supported operators: =, !=, IN, NOT IN, IN_CHILDREN, EMPTY, NOT EMPTY, >, <, BETWEEN.
+func (s *Server) matches(item Item, filters map[string][]Filter) bool {
+	for field, conditions := range filters {
+		value := item[field]
+		// is_root is not a field of categories: a root is a category without parent
+		if field == "is_root" {
+			value = item["parent"] == nil
+		}
+
+		for _, condition := range conditions {
+			if !s.matchesFilter(value, condition) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (s *Server) matchesFilter(fieldValue interface{}, filter Filter) bool {
+	switch strings.ToUpper(filter.Operator) {
+	case "=":
+		return fmt.Sprint(fieldValue) == fmt.Sprint(filter.Value)
+	case "!=":
+		return fmt.Sprint(fieldValue) != fmt.Sprint(filter.Value)
+	case "IN":
+		return intersects(fieldValue, toStrings(filter.Value))
+	case "NOT IN":
+		return !intersects(fieldValue, toStrings(filter.Value))
+	case "IN_CHILDREN":
+		categories := make(map[string]bool)
+		for _, code := range toStrings(filter.Value) {
+			for child := range s.store.childCategories(code) {
+				categories[child] = true
+			}
+		}
+		for _, code := range toStrings(fieldValue) {
+			if categories[code] {
+				return true
+			}
+		}
+		return false
+	case "EMPTY":
+		return len(toStrings(fieldValue)) == 0
+	case "NOT EMPTY":
+		return len(toStrings(fieldValue)) > 0
+	case ">":
+		return compare(fieldValue, filter.Value) > 0
+	case "<":
+		return compare(fieldValue, filter.Value) < 0
+	case "BETWEEN":
+		bounds, ok := filter.Value.([]interface{})
+		if !ok || len(bounds) != 2 {
+			return false
+		}
+		return compare(fieldValue, bounds[0]) >= 0 && compare(fieldValue, bounds[1]) <= 0
+	default:
+		return false
+	}
+}
+
+// compare compares two values as dates when possible, as text otherwise
+func compare(a, b interface{}) int {
+	textA, textB := fmt.Sprint(a), fmt.Sprint(b)
+
+	timeA, okA := parseTime(textA)
+	timeB, okB := parseTime(textB)
+	if okA && okB {
+		return timeA.Compare(timeB)
+	}
+
+	return strings.Compare(textA, textB)
+}
+
+// intersects reports whether a field (single value or list) contains one of the codes
+func intersects(fieldValue interface{}, codes []string) bool {
+	wanted := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		wanted[code] = true
+	}
+	for _, value := range toStrings(fieldValue) {
+		if wanted[value] {
+			return true
+		}
+	}
+	return false
+}
+
+// toStrings converts a single value or a list to a list of strings
+func toStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			result = append(result, fmt.Sprint(item))
+		}
+		return result
+	case string:
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
@@ -0,0 +1,433 @@
+package mockserver
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	tokenPath  = "/api/oauth/v1/token"
+	apiPrefix  = "/api/rest/v1/"
+	mockToken  = "mock-access-token"
+	defaultPer = 10
+	maxPer     = 100
+
+	collectionContentType = "application/vnd.akeneo.collection+json"
+)
+
+// Server serves a fake Akeneo API backed by a Store
+type Server struct {
+	port  string
+	store *Store
+}
+
+// NewServer creates a new mock Akeneo server
+func NewServer(port string, store *Store) *Server {
+	return &Server{
+		port:  port,
+		store: store,
+	}
+}
+
+// Start starts the mock server
+func (s *Server) Start() error {
+	return http.ListenAndServe(":"+s.port, s.Handler()) //nolint:gosec // local development server
+}
+
+// Handler returns the HTTP handler of the fake API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(tokenPath, s.handleToken)
+	mux.HandleFunc(apiPrefix+"system-information", s.handleSystemInformation)
+	mux.HandleFunc(apiPrefix, s.handleResource)
+	return mux
+}
+
+// handleToken accepts any credentials
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token":  mockToken,
+		"expires_in":    3600,
+		"token_type":    "bearer",
+		"scope":         nil,
+		"refresh_token": mockToken,
+	})
+}
+
+// handleSystemInformation presents the fake API as an Enterprise Edition keying products by identifier
+func (s *Server) handleSystemInformation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"version": "6.0.0",
+		"edition": "EE",
+	})
+}
+
+// handleResource serves collections (odd number of path segments) and items (even number)
+func (s *Server) handleResource(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		writeError(w, http.StatusUnauthorized, "Authentication is required")
+		return
+	}
+
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, apiPrefix), "/"), "/")
+
+	if len(segments)%2 == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			s.handleList(w, r, strings.Join(segments, "/"))
+		case http.MethodPatch:
+			if strings.HasPrefix(r.URL.Path, apiPrefix+"reference-entities/") || strings.HasPrefix(r.URL.Path, apiPrefix+"asset-families/") ||
+				r.URL.Path == apiPrefix+"measurement-families" {
+				s.handleRecordsPatch(w, r, strings.Join(segments, "/"))
+				return
+			}
+			s.handleCollectionPatch(w, r, strings.Join(segments, "/"))
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+		}
+		return
+	}
+
+	name := strings.Join(segments[:len(segments)-1], "/")
+	code := segments[len(segments)-1]
+
+	switch r.Method {
+	case http.MethodGet:
+		item, exists := s.store.Get(name, code)
+		if !exists {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Resource `%s` does not exist.", code))
+			return
+		}
+		writeJSON(w, http.StatusOK, withLinks(r, item))
+
+	case http.MethodPatch:
+		var patch Item
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid json message received")
+			return
+		}
+
+		if s.store.Patch(name, code, patch) {
+			w.Header().Set("Location", requestBase(r)+r.URL.Path)
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	case http.MethodDelete:
+		if !s.store.Delete(name, code) {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Resource `%s` does not exist.", code))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+	}
+}
+
+// handleList serves a paginated, optionally filtered collection
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request, name string) {
+	items := s.store.List(name)
+
+	if search := r.URL.Query().Get("search"); search != "" {
+		var filters map[string][]Filter
+		if err := json.Unmarshal([]byte(search), &filters); err != nil {
+			writeError(w, http.StatusBadRequest, "Search query parameter should be valid JSON.")
+			return
+		}
+
+		filtered := make([]Item, 0, len(items))
+		for _, item := range items {
+			if s.matches(item, filters) {
+				filtered = append(filtered, item)
+			}
+		}
+		items = filtered
+	}
+
+	// Reference entity and asset family attributes, asset attribute options and measurement families
+	// are not paginated by Akeneo
+	if notPaginated(name) {
+		writeJSON(w, http.StatusOK, items)
+		return
+	}
+
+	limit := queryInt(r, "limit", defaultPer)
+	if limit > maxPer {
+		limit = maxPer
+	}
+
+	if r.URL.Query().Get("pagination_type") == "search_after" {
+		s.writeSearchAfterPage(w, r, name, items, limit)
+		return
+	}
+
+	page := queryInt(r, "page", 1)
+
+	start := (page - 1) * limit
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + limit
+	if end > len(items) {
+		end = len(items)
+	}
+
+	embedded := make([]Item, 0, end-start)
+	for _, item := range items[start:end] {
+		embedded = append(embedded, withLinks(r, item))
+	}
+
+	links := map[string]interface{}{
+		"self":  map[string]string{"href": pageURL(r, page)},
+		"first": map[string]string{"href": pageURL(r, 1)},
+	}
+	if page > 1 {
+		links["previous"] = map[string]string{"href": pageURL(r, page-1)}
+	}
+	if end < len(items) {
+		links["next"] = map[string]string{"href": pageURL(r, page+1)}
+	}
+
+	body := map[string]interface{}{
+		"_links":       links,
+		"current_page": page,
+		"_embedded":    map[string]interface{}{"items": embedded},
+	}
+	if r.URL.Query().Get("with_count") == "true" {
+		body["items_count"] = len(items)
+	}
+
+	writeJSON(w, http.StatusOK, body)
+}
+
+// handleCollectionPatch creates or updates the items of a newline-delimited collection,
+// answering with one status line per item like Akeneo does
+func (s *Server) handleCollectionPatch(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Header.Get("Content-Type") != collectionContentType {
+		writeError(w, http.StatusUnsupportedMediaType, fmt.Sprintf("The \"%s\" content type is expected.", collectionContentType))
+		return
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) != "" {
+			lines = append(lines, scanner.Text())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid collection received")
+		return
+	}
+	if len(lines) > maxPer {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Too many resources to process, %d is the maximum allowed.", maxPer))
+		return
+	}
+
+	w.Header().Set("Content-Type", collectionContentType)
+	w.WriteHeader(http.StatusOK)
+
+	field := codeField(name)
+	encoder := json.NewEncoder(w)
+	for i, line := range lines {
+		status := map[string]interface{}{"line": i + 1}
+
+		var patch Item
+		code := ""
+		if err := json.Unmarshal([]byte(line), &patch); err == nil {
+			code = itemCode(name, patch)
+		}
+
+		switch {
+		case patch == nil:
+			status["status_code"] = http.StatusBadRequest
+			status["message"] = "Invalid json message received"
+		case code == "":
+			status["status_code"] = http.StatusUnprocessableEntity
+			status["message"] = fmt.Sprintf("The %s field is required.", field)
+		default:
+			status[field] = code
+			status["status_code"] = http.StatusNoContent
+			if s.store.Patch(name, code, patch) {
+				status["status_code"] = http.StatusCreated
+			}
+		}
+
+		if err := encoder.Encode(status); err != nil {
+			log.Printf("⚠️  Error writing response: %v", err)
+			return
+		}
+	}
+}
+
+// handleRecordsPatch creates or updates a JSON array of records, assets or measurement families,
+// answering with the status of each item
+func (s *Server) handleRecordsPatch(w http.ResponseWriter, r *http.Request, name string) {
+	var records []Item
+	if err := json.NewDecoder(r.Body).Decode(&records); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid json message received")
+		return
+	}
+	if len(records) > maxPer {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Too many resources to process, %d is the maximum allowed.", maxPer))
+		return
+	}
+
+	statuses := make([]map[string]interface{}, 0, len(records))
+	for _, record := range records {
+		code := itemCode(name, record)
+		if code == "" {
+			statuses = append(statuses, map[string]interface{}{
+				"status_code": http.StatusUnprocessableEntity,
+				"message":     "The code field is required.",
+			})
+			continue
+		}
+
+		status := http.StatusNoContent
+		if s.store.Patch(name, code, record) {
+			status = http.StatusCreated
+		}
+		statuses = append(statuses, map[string]interface{}{"code": code, "status_code": status})
+	}
+
+	writeJSON(w, http.StatusOK, statuses)
+}
+
+// notPaginated reports whether Akeneo answers a collection with a plain JSON array
+func notPaginated(name string) bool {
+	switch {
+	case name == "measurement-families":
+		return true
+	case strings.HasPrefix(name, "reference-entities/"), strings.HasPrefix(name, "asset-families/"):
+		return strings.HasSuffix(name, "/attributes") || strings.HasSuffix(name, "/options")
+	}
+	return false
+}
+
+// writeSearchAfterPage writes the page following the item named by the search_after cursor.
+// The cursor is the code of the last item of the previous page.
+func (s *Server) writeSearchAfterPage(w http.ResponseWriter, r *http.Request, name string, items []Item, limit int) {
+	start := 0
+	if cursor := r.URL.Query().Get("search_after"); cursor != "" {
+		start = len(items)
+		for i, item := range items {
+			if itemCode(name, item) == cursor {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	end := start + limit
+	if end > len(items) {
+		end = len(items)
+	}
+
+	embedded := make([]Item, 0, end-start)
+	for _, item := range items[start:end] {
+		embedded = append(embedded, withLinks(r, item))
+	}
+
+	first := r.URL.Query()
+	first.Del("search_after")
+	links := map[string]interface{}{
+		"self":  map[string]string{"href": requestBase(r) + r.URL.RequestURI()},
+		"first": map[string]string{"href": requestBase(r) + r.URL.Path + "?" + first.Encode()},
+	}
+	if end < len(items) {
+		next := r.URL.Query()
+		next.Set("search_after", itemCode(name, items[end-1]))
+		links["next"] = map[string]string{"href": requestBase(r) + r.URL.Path + "?" + next.Encode()}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"_links":    links,
+		"_embedded": map[string]interface{}{"items": embedded},
+	})
+}
+
+// withLinks adds the self link of an item
+func withLinks(r *http.Request, item Item) Item {
+	result := make(Item, len(item)+1)
+	for key, value := range item {
+		result[key] = value
+	}
+
+	code, _ := item["code"].(string)
+	if identifier, ok := item["identifier"].(string); ok {
+		code = identifier
+	}
+	result["_links"] = map[string]interface{}{
+		"self": map[string]string{"href": requestBase(r) + strings.TrimSuffix(r.URL.Path, "/"+code) + "/" + code},
+	}
+	return result
+}
+
+// pageURL returns the URL of another page of the current list
+func pageURL(r *http.Request, page int) string {
+	query := r.URL.Query()
+	query.Set("page", strconv.Itoa(page))
+	return requestBase(r) + r.URL.Path + "?" + query.Encode()
+}
+
+// requestBase returns the scheme and host the request was sent to
+func requestBase(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// queryInt reads a positive integer query parameter
+func queryInt(r *http.Request, name string, fallback int) int {
+	value, err := strconv.Atoi(r.URL.Query().Get(name))
+	if err != nil || value < 1 {
+		return fallback
+	}
+	return value
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("⚠️  Error writing response: %v", err)
+	}
+}
+
+// writeError writes an Akeneo-like error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{"code": status, "message": message})
+}
+
+// parseTime parses the date formats used by Akeneo items and search filters
+func parseTime(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02T15:04:05"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,283 @@
+package mockserver
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"akeneo-migrator/internal/platform/client/akeneo"
+)
+
+func writeExport(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func newTestClient(t *testing.T, store *Store) *akeneo.Client {
+	t.Helper()
+
+	server := httptest.NewServer(NewServer("", store).Handler())
+	t.Cleanup(server.Close)
+
+	client, err := akeneo.NewClient(akeneo.ClientConfig{Host: server.URL, ClientID: "id", Secret: "secret", Username: "user", Password: "pass"})
+	if err != nil {
+		t.Fatalf("Expected client to authenticate, got %v", err)
+	}
+	return client
+}
+
+func TestServer_ServesExport(t *testing.T) {
+	dir := writeExport(t, map[string]string{
+		"products.json": `[
+			{"identifier": "SKU-1", "parent": "MODEL-1", "categories": ["boots"], "updated": "2024-02-01T10:00:00+00:00"},
+			{"identifier": "SKU-2", "parent": "MODEL-1", "categories": ["sandals"], "updated": "2024-06-01T10:00:00+00:00"},
+			{"identifier": "SKU-3", "parent": "MODEL-2", "categories": [], "updated": "2023-01-01T10:00:00+00:00"}
+		]`,
+		"categories.json":               `[{"code": "master"}, {"code": "shoes", "parent": "master"}, {"code": "boots", "parent": "shoes"}, {"code": "sandals", "parent": "master"}]`,
+		"attributes/color/options.json": `[{"code": "red", "attribute": "color"}, {"code": "blue", "attribute": "color"}]`,
+	})
+
+	store, err := LoadStore(dir)
+	if err != nil {
+		t.Fatalf("Expected export to load, got %v", err)
+	}
+
+	client := newTestClient(t, store)
+
+	product, err := client.GetProduct(context.Background(), "SKU-1")
+	if err != nil || product["parent"] != "MODEL-1" {
+		t.Fatalf("Expected SKU-1, got %v (%v)", product, err)
+	}
+
+	if _, err := client.GetProduct(context.Background(), "UNKNOWN"); err == nil {
+		t.Error("Expected not found error")
+	}
+
+	children, err := client.GetProductsByParent(context.Background(), "MODEL-1")
+	if err != nil || len(children) != 2 {
+		t.Errorf("Expected 2 children, got %d (%v)", len(children), err)
+	}
+
+	inShoes, err := client.GetProductIdentifiersByCategory(context.Background(), "shoes")
+	if err != nil || len(inShoes) != 1 || inShoes[0] != "SKU-1" {
+		t.Errorf("Expected only SKU-1 in shoes subtree, got %v (%v)", inShoes, err)
+	}
+
+	updated, err := client.GetProductsUpdatedSince(context.Background(), "2024-01-01T00:00:00")
+	if err != nil || len(updated) != 2 {
+		t.Errorf("Expected 2 updated products, got %d (%v)", len(updated), err)
+	}
+
+	options, err := client.GetAttributeOptions(context.Background(), "color")
+	if err != nil || len(options) != 2 {
+		t.Errorf("Expected 2 options, got %d (%v)", len(options), err)
+	}
+}
+
+func TestServer_PatchMergesValues(t *testing.T) {
+	client := newTestClient(t, NewStore())
+
+	err := client.PatchProduct(context.Background(), "SKU-1", akeneo.Product{
+		"identifier": "SKU-1",
+		"values": map[string]interface{}{
+			"name": []interface{}{map[string]interface{}{"locale": "en_US", "scope": nil, "data": "Boot"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected product to be created, got %v", err)
+	}
+
+	err = client.PatchProduct(context.Background(), "SKU-1", akeneo.Product{
+		"identifier": "SKU-1",
+		"values": map[string]interface{}{
+			"name": []interface{}{map[string]interface{}{"locale": "fr_FR", "scope": nil, "data": "Botte"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected product to be updated, got %v", err)
+	}
+
+	product, err := client.GetProduct(context.Background(), "SKU-1")
+	if err != nil {
+		t.Fatalf("Expected product, got %v", err)
+	}
+
+	names := product["values"].(map[string]interface{})["name"].([]interface{})
+	if len(names) != 2 {
+		t.Errorf("Expected values merged per locale, got %v", names)
+	}
+}
+
+func TestServer_PatchCollection(t *testing.T) {
+	store := NewStore()
+	client := newTestClient(t, store)
+
+	failed, err := client.PatchProducts(context.Background(), []akeneo.Product{
+		{"identifier": "SKU-1", "family": "shoes"},
+		{"identifier": "SKU-2", "family": "shoes"},
+	})
+	if err != nil || len(failed) != 0 {
+		t.Fatalf("Expected products to be written, got %v (%v)", failed, err)
+	}
+
+	if len(store.List("products")) != 2 {
+		t.Errorf("Expected 2 products in store, got %d", len(store.List("products")))
+	}
+
+	failed, err = client.PatchProductModels(context.Background(), []akeneo.ProductModel{{"code": "MODEL-1"}})
+	if err != nil || len(failed) != 0 {
+		t.Fatalf("Expected model to be written, got %v (%v)", failed, err)
+	}
+
+	failed, err = client.PatchReferenceEntityRecords(context.Background(), "brands", []akeneo.ReferenceEntityRecord{
+		{"code": "acme", "values": map[string]interface{}{}},
+		{"code": "globex", "values": map[string]interface{}{}},
+	})
+	if err != nil || len(failed) != 0 {
+		t.Fatalf("Expected records to be written, got %v (%v)", failed, err)
+	}
+
+	if len(store.List("reference-entities/brands/records")) != 2 {
+		t.Errorf("Expected 2 records in store, got %d", len(store.List("reference-entities/brands/records")))
+	}
+}
+
+func TestServer_DeleteRecord(t *testing.T) {
+	store := NewStore()
+	client := newTestClient(t, store)
+
+	failed, err := client.PatchReferenceEntityRecords(context.Background(), "brands", []akeneo.ReferenceEntityRecord{
+		{"code": "acme", "values": map[string]interface{}{}},
+		{"code": "globex", "values": map[string]interface{}{}},
+	})
+	if err != nil || len(failed) != 0 {
+		t.Fatalf("Expected records to be written, got %v (%v)", failed, err)
+	}
+
+	if err := client.DeleteReferenceEntityRecord(context.Background(), "brands", "acme"); err != nil {
+		t.Fatalf("Expected record to be deleted, got %v", err)
+	}
+
+	records := store.List("reference-entities/brands/records")
+	if len(records) != 1 || records[0]["code"] != "globex" {
+		t.Errorf("Expected only globex left, got %v", records)
+	}
+
+	if err := client.DeleteReferenceEntityRecord(context.Background(), "brands", "acme"); err == nil {
+		t.Error("Expected not found error deleting a missing record")
+	}
+}
+
+func TestServer_SearchAfterPagination(t *testing.T) {
+	store := NewStore()
+	for i := 0; i < 250; i++ {
+		store.Patch("products", fmt.Sprintf("SKU-%03d", i), Item{"identifier": fmt.Sprintf("SKU-%03d", i)})
+	}
+	client := newTestClient(t, store)
+
+	var batches []int
+	seen := map[string]bool{}
+	err := client.StreamProductsUpdatedSince(context.Background(), "2000-01-01T00:00:00", "", 100, func(products []akeneo.Product) error {
+		batches = append(batches, len(products))
+		for _, product := range products {
+			seen[product["identifier"].(string)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(batches) != 3 || batches[2] != 50 {
+		t.Errorf("Expected batches of 100, 100 and 50, got %v", batches)
+	}
+	if len(seen) != 250 {
+		t.Errorf("Expected every product once, got %d", len(seen))
+	}
+}
+
+func TestServer_StreamsRecords(t *testing.T) {
+	store := NewStore()
+	for i := 0; i < 150; i++ {
+		store.Patch("reference-entities/brands/records", fmt.Sprintf("brand_%03d", i), Item{"code": fmt.Sprintf("brand_%03d", i)})
+	}
+	client := newTestClient(t, store)
+
+	var batches []int
+	err := client.StreamReferenceEntityRecords(context.Background(), "brands", 100, func(records []akeneo.ReferenceEntityRecord) error {
+		batches = append(batches, len(records))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(batches) != 2 || batches[0] != 100 || batches[1] != 50 {
+		t.Errorf("Expected batches of 100 and 50 records, got %v", batches)
+	}
+}
+
+func TestServer_MeasurementFamilies(t *testing.T) {
+	store := NewStore()
+	client := newTestClient(t, store)
+
+	failed, err := client.PatchMeasurementFamilies(context.Background(), []akeneo.MeasurementFamily{
+		{"code": "Length", "standard_unit_code": "METER", "units": map[string]interface{}{}},
+		{"code": "Weight", "standard_unit_code": "KILOGRAM", "units": map[string]interface{}{}},
+	})
+	if err != nil || len(failed) != 0 {
+		t.Fatalf("Expected measurement families to be written, got %v (%v)", failed, err)
+	}
+
+	families, err := client.GetMeasurementFamilies(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(families) != 2 {
+		t.Errorf("Expected 2 measurement families, got %d", len(families))
+	}
+}
+
+func TestServer_Assets(t *testing.T) {
+	store := NewStore()
+	client := newTestClient(t, store)
+	ctx := context.Background()
+
+	if err := client.PatchAssetFamilyAttribute(ctx, "packshots", "angle", akeneo.AssetFamilyAttribute{"code": "angle", "type": "single_option"}); err != nil {
+		t.Fatalf("Expected attribute to be written, got %v", err)
+	}
+	attributes, err := client.GetAssetFamilyAttributes(ctx, "packshots")
+	if err != nil || len(attributes) != 1 {
+		t.Errorf("Expected 1 attribute, got %v (%v)", attributes, err)
+	}
+
+	failed, err := client.PatchAssets(ctx, "packshots", []akeneo.Asset{{"code": "front"}, {"code": "back"}})
+	if err != nil || len(failed) != 0 {
+		t.Fatalf("Expected assets to be written, got %v (%v)", failed, err)
+	}
+
+	var codes []string
+	err = client.StreamAssets(ctx, "packshots", 100, func(assets []akeneo.Asset) error {
+		for _, asset := range assets {
+			codes = append(codes, asset["code"].(string))
+		}
+		return nil
+	})
+	if err != nil || len(codes) != 2 {
+		t.Errorf("Expected 2 assets, got %v (%v)", codes, err)
+	}
+}
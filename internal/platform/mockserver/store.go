@@ -0,0 +1,304 @@
+package mockserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Item is a resource stored by the mock server
+type Item map[string]interface{}
+
+// collection keeps items in insertion order
+type collection struct {
+	codes []string
+	items map[string]Item
+}
+
+// Store is an in-memory Akeneo catalog. Collections are named after their API path,
+// e.g. "products", "attributes/color/options" or "reference-entities/brands/records".
+type Store struct {
+	collections map[string]*collection
+	mu          sync.RWMutex
+}
+
+// NewStore creates an empty store
+func NewStore() *Store {
+	return &Store{collections: make(map[string]*collection)}
+}
+
+// LoadStore seeds a store from an export directory. Every JSON file holds an array of items
+// and its path mirrors the API collection: products.json, product-models.json,
+// attributes/color/options.json, families/shoes/variants.json, reference-entities/brands/records.json...
+func LoadStore(dir string) (*Store, error) {
+	store := NewStore()
+
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		relative, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(strings.TrimSuffix(relative, ".json"))
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var items []Item
+		if err := json.Unmarshal(data, &items); err != nil {
+			return fmt.Errorf("error decoding %s: %w", relative, err)
+		}
+
+		for _, item := range items {
+			code := itemCode(name, item)
+			if code == "" {
+				return fmt.Errorf("item without code in %s", relative)
+			}
+			store.put(name, code, item)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error loading export directory: %w", err)
+	}
+
+	return store, nil
+}
+
+// Counts returns the number of items per collection
+func (s *Store) Counts() map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[string]int, len(s.collections))
+	for name, c := range s.collections {
+		counts[name] = len(c.codes)
+	}
+	return counts
+}
+
+// Get returns an item of a collection
+func (s *Store) Get(name, code string) (Item, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, exists := s.collections[name]
+	if !exists {
+		return nil, false
+	}
+	item, exists := c.items[code]
+	return item, exists
+}
+
+// List returns the items of a collection in insertion order
+func (s *Store) List(name string) []Item {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, exists := s.collections[name]
+	if !exists {
+		return []Item{}
+	}
+
+	items := make([]Item, len(c.codes))
+	for i, code := range c.codes {
+		items[i] = c.items[code]
+	}
+	return items
+}
+
+// Patch creates or updates an item the way Akeneo does: top-level fields are replaced,
+// values are merged per attribute, locale and channel. It reports whether the item was created.
+func (s *Store) Patch(name, code string, patch Item) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	existing, exists := s.lookup(name, code)
+	item := make(Item, len(existing)+len(patch))
+	for key, value := range existing {
+		item[key] = value
+	}
+
+	for key, value := range patch {
+		if key == "values" {
+			item[key] = mergeValues(existing["values"], value)
+			continue
+		}
+		item[key] = value
+	}
+
+	item[codeField(name)] = code
+	if !exists {
+		item["created"] = now
+	}
+	if _, hasUpdated := existing["updated"]; hasUpdated || !exists {
+		item["updated"] = now
+	}
+
+	s.putLocked(name, code, item)
+	return !exists
+}
+
+// Delete removes an item of a collection and reports whether it existed
+func (s *Store) Delete(name, code string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, exists := s.collections[name]
+	if !exists {
+		return false
+	}
+	if _, exists := c.items[code]; !exists {
+		return false
+	}
+
+	delete(c.items, code)
+	for i, existing := range c.codes {
+		if existing == code {
+			c.codes = append(c.codes[:i], c.codes[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func (s *Store) lookup(name, code string) (Item, bool) {
+	c, exists := s.collections[name]
+	if !exists {
+		return nil, false
+	}
+	item, exists := c.items[code]
+	return item, exists
+}
+
+func (s *Store) put(name, code string, item Item) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.putLocked(name, code, item)
+}
+
+func (s *Store) putLocked(name, code string, item Item) {
+	c, exists := s.collections[name]
+	if !exists {
+		c = &collection{items: make(map[string]Item)}
+		s.collections[name] = c
+	}
+
+	if _, exists := c.items[code]; !exists {
+		c.codes = append(c.codes, code)
+	}
+	c.items[code] = item
+}
+
+// childCategories returns a category and all its descendants
+func (s *Store) childCategories(code string) map[string]bool {
+	children := make(map[string][]string)
+	for _, category := range s.List("categories") {
+		parent, _ := category["parent"].(string)
+		child, _ := category["code"].(string)
+		children[parent] = append(children[parent], child)
+	}
+
+	result := map[string]bool{code: true}
+	queue := []string{code}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, child := range children[current] {
+			if !result[child] {
+				result[child] = true
+				queue = append(queue, child)
+			}
+		}
+	}
+	return result
+}
+
+// codeField returns the field identifying items of a collection
+func codeField(name string) string {
+	if name == "products" {
+		return "identifier"
+	}
+	return "code"
+}
+
+// itemCode extracts the code of an item
+func itemCode(name string, item Item) string {
+	code, _ := item[codeField(name)].(string)
+	return code
+}
+
+// mergeValues merges value patches into existing values per attribute, locale and channel
+func mergeValues(existing, patch interface{}) interface{} {
+	patchValues, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	existingValues, _ := existing.(map[string]interface{})
+
+	merged := make(map[string]interface{}, len(existingValues)+len(patchValues))
+	for attributeCode, entries := range existingValues {
+		merged[attributeCode] = entries
+	}
+
+	for attributeCode, entries := range patchValues {
+		current, _ := merged[attributeCode].([]interface{})
+		result := append([]interface{}{}, current...)
+
+		newEntries, _ := entries.([]interface{})
+		for _, entry := range newEntries {
+			value, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			replaced := false
+			for i, existingEntry := range result {
+				if existingValue, ok := existingEntry.(map[string]interface{}); ok && sameContext(existingValue, value) {
+					result[i] = value
+					replaced = true
+					break
+				}
+			}
+			if !replaced {
+				result = append(result, value)
+			}
+		}
+
+		// A null data removes the value, like Akeneo does
+		kept := make([]interface{}, 0, len(result))
+		for _, entry := range result {
+			if value, ok := entry.(map[string]interface{}); ok && value["data"] == nil {
+				continue
+			}
+			kept = append(kept, entry)
+		}
+		if len(kept) == 0 {
+			delete(merged, attributeCode)
+			continue
+		}
+		merged[attributeCode] = kept
+	}
+
+	return merged
+}
+
+// sameContext reports whether two values target the same locale and channel
+func sameContext(a, b map[string]interface{}) bool {
+	return a["locale"] == b["locale"] && a["scope"] == b["scope"] && a["channel"] == b["channel"]
+}
@@ -0,0 +1,115 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Job represents a CLI invocation executed for a single instance pair
+type Job struct {
+	Pair string
+	Args []string
+}
+
+// Result contains the outcome of a job
+type Result struct {
+	Pair     string
+	ExitCode int
+	Duration time.Duration
+	LogFile  string
+	Error    string
+}
+
+// Runner executes jobs for several instance pairs in parallel.
+// Every job runs in its own process, so each pair gets its own clients,
+// rate limits and log file.
+type Runner struct {
+	binaryPath string
+	logDir     string
+	parallel   int
+	pairEnv    string
+}
+
+// NewRunner creates a new pair runner
+func NewRunner(binaryPath, logDir string, parallel int, pairEnv string) *Runner {
+	if parallel < 1 {
+		parallel = 1
+	}
+	return &Runner{
+		binaryPath: binaryPath,
+		logDir:     logDir,
+		parallel:   parallel,
+		pairEnv:    pairEnv,
+	}
+}
+
+// Run executes all jobs with bounded parallelism and returns their results in the same order
+func (r *Runner) Run(ctx context.Context, jobs []Job, onDone func(Result)) ([]Result, error) {
+	if err := os.MkdirAll(r.logDir, 0o750); err != nil {
+		return nil, fmt.Errorf("error creating log directory: %w", err)
+	}
+
+	results := make([]Result, len(jobs))
+	semaphore := make(chan struct{}, r.parallel)
+	var wg sync.WaitGroup
+	var doneMux sync.Mutex
+
+	for i, job := range jobs {
+		wg.Add(1)
+		go func(i int, job Job) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			results[i] = r.runJob(ctx, job)
+
+			if onDone != nil {
+				doneMux.Lock()
+				onDone(results[i])
+				doneMux.Unlock()
+			}
+		}(i, job)
+	}
+
+	wg.Wait()
+	return results, nil
+}
+
+// runJob executes a single job, writing its output to the pair log file
+func (r *Runner) runJob(ctx context.Context, job Job) Result {
+	start := time.Now()
+	result := Result{
+		Pair:    job.Pair,
+		LogFile: filepath.Join(r.logDir, job.Pair+".log"),
+	}
+
+	logFile, err := os.Create(result.LogFile)
+	if err != nil {
+		result.ExitCode = -1
+		result.Error = fmt.Sprintf("error creating log file: %v", err)
+		return result
+	}
+	defer func() { _ = logFile.Close() }()
+
+	cmd := exec.CommandContext(ctx, r.binaryPath, job.Args...) //nolint:gosec // binary and args come from the operator
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", r.pairEnv, job.Pair))
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+
+	if err := cmd.Run(); err != nil {
+		result.ExitCode = -1
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			result.ExitCode = exitErr.ExitCode()
+		}
+		result.Error = err.Error()
+	}
+
+	result.Duration = time.Since(start)
+	return result
+}
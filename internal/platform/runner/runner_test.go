@@ -0,0 +1,52 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestRun_IsolatesPairs(t *testing.T) {
+	shell, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+
+	logDir := t.TempDir()
+	runner := NewRunner(shell, logDir, 2, "TEST_PAIR")
+
+	jobs := []Job{
+		{Pair: "customer-a", Args: []string{"-c", "echo pair=$TEST_PAIR"}},
+		{Pair: "customer-b", Args: []string{"-c", "echo pair=$TEST_PAIR; exit 3"}},
+	}
+
+	results, err := runner.Run(context.Background(), jobs, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+
+	if results[0].ExitCode != 0 {
+		t.Errorf("Expected exit code 0 for customer-a, got %d", results[0].ExitCode)
+	}
+
+	if results[1].ExitCode != 3 {
+		t.Errorf("Expected exit code 3 for customer-b, got %d", results[1].ExitCode)
+	}
+
+	for _, result := range results {
+		content, err := os.ReadFile(result.LogFile)
+		if err != nil {
+			t.Fatalf("Expected log file for %s, got %v", result.Pair, err)
+		}
+
+		if !strings.Contains(string(content), "pair="+result.Pair) {
+			t.Errorf("Expected log of %s to contain its own pair, got %q", result.Pair, string(content))
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package akeneo
+
+import (
+	"context"
+	"fmt"
+
+	"akeneo-migrator/internal/asset"
+	"akeneo-migrator/internal/platform/client/akeneo"
+)
+
+// SourceAssetRepository implements asset.SourceRepository for Akeneo
+type SourceAssetRepository struct {
+	client akeneo.API
+}
+
+// NewSourceAssetRepository creates a new source asset repository
+func NewSourceAssetRepository(client akeneo.API) asset.SourceRepository {
+	return &SourceAssetRepository{
+		client: client,
+	}
+}
+
+// FindFamilyCodes retrieves the codes of every asset family
+func (r *SourceAssetRepository) FindFamilyCodes(ctx context.Context) ([]string, error) {
+	families, err := r.client.GetAssetFamilies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching asset families: %w", err)
+	}
+
+	codes := make([]string, 0, len(families))
+	for _, family := range families {
+		if code, ok := family["code"].(string); ok {
+			codes = append(codes, code)
+		}
+	}
+	return codes, nil
+}
+
+// FindFamily retrieves an asset family definition
+func (r *SourceAssetRepository) FindFamily(ctx context.Context, familyCode string) (asset.Family, error) {
+	family, err := r.client.GetAssetFamily(ctx, familyCode)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching asset family %s: %w", familyCode, err)
+	}
+	return asset.Family(family), nil
+}
+
+// FindAttributes retrieves all attributes of an asset family
+func (r *SourceAssetRepository) FindAttributes(ctx context.Context, familyCode string) ([]asset.Attribute, error) {
+	attributes, err := r.client.GetAssetFamilyAttributes(ctx, familyCode)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching attributes of asset family %s: %w", familyCode, err)
+	}
+
+	result := make([]asset.Attribute, len(attributes))
+	for i, attribute := range attributes {
+		result[i] = asset.Attribute(attribute)
+	}
+	return result, nil
+}
+
+// FindAttributeOptions retrieves all options of an asset attribute
+func (r *SourceAssetRepository) FindAttributeOptions(ctx context.Context, familyCode, attributeCode string) ([]asset.AttributeOption, error) {
+	options, err := r.client.GetAssetAttributeOptions(ctx, familyCode, attributeCode)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching options of asset attribute %s: %w", attributeCode, err)
+	}
+
+	result := make([]asset.AttributeOption, len(options))
+	for i, option := range options {
+		result[i] = asset.AttributeOption(option)
+	}
+	return result, nil
+}
+
+// FindAsset retrieves an asset of an asset family
+func (r *SourceAssetRepository) FindAsset(ctx context.Context, familyCode, code string) (asset.Asset, error) {
+	item, err := r.client.GetAsset(ctx, familyCode, code)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching asset %s of family %s: %w", code, familyCode, err)
+	}
+	return asset.Asset(item), nil
+}
+
+// StreamAssets processes the assets of an asset family in batches, as they are fetched
+func (r *SourceAssetRepository) StreamAssets(ctx context.Context, familyCode string, batchSize int, callback func([]asset.Asset) error) error {
+	return r.client.StreamAssets(ctx, familyCode, batchSize, func(assets []akeneo.Asset) error {
+		batch := make([]asset.Asset, len(assets))
+		for i, item := range assets {
+			batch[i] = asset.Asset(item)
+		}
+		return callback(batch)
+	})
+}
+
+// DownloadMediaFile retrieves the content of a media file
+func (r *SourceAssetRepository) DownloadMediaFile(ctx context.Context, code string) (asset.MediaFile, error) {
+	content, err := r.client.DownloadAssetMediaFile(ctx, code)
+	if err != nil {
+		return asset.MediaFile{}, err
+	}
+
+	return asset.MediaFile{Code: code, Filename: mediaFilename(code), Content: content}, nil
+}
+
+// DestAssetRepository implements asset.DestRepository for Akeneo
+type DestAssetRepository struct {
+	client akeneo.API
+}
+
+// NewDestAssetRepository creates a new destination asset repository
+func NewDestAssetRepository(client akeneo.API) asset.DestRepository {
+	return &DestAssetRepository{
+		client: client,
+	}
+}
+
+// SaveFamily creates or updates an asset family definition
+func (r *DestAssetRepository) SaveFamily(ctx context.Context, familyCode string, family asset.Family) error {
+	if err := r.client.PatchAssetFamily(ctx, familyCode, akeneo.AssetFamily(family)); err != nil {
+		return fmt.Errorf("error saving asset family %s: %w", familyCode, err)
+	}
+	return nil
+}
+
+// SaveAttribute creates or updates an attribute of an asset family
+func (r *DestAssetRepository) SaveAttribute(ctx context.Context, familyCode, attributeCode string, attribute asset.Attribute) error {
+	if err := r.client.PatchAssetFamilyAttribute(ctx, familyCode, attributeCode, akeneo.AssetFamilyAttribute(attribute)); err != nil {
+		return fmt.Errorf("error saving asset attribute %s: %w", attributeCode, err)
+	}
+	return nil
+}
+
+// SaveAttributeOption creates or updates an option of an asset attribute
+func (r *DestAssetRepository) SaveAttributeOption(ctx context.Context, familyCode, attributeCode, optionCode string, option asset.AttributeOption) error {
+	if err := r.client.PatchAssetAttributeOption(ctx, familyCode, attributeCode, optionCode, akeneo.AssetAttributeOption(option)); err != nil {
+		return fmt.Errorf("error saving option %s of asset attribute %s: %w", optionCode, attributeCode, err)
+	}
+	return nil
+}
+
+// SaveAll creates or updates several assets of an asset family in batches
+func (r *DestAssetRepository) SaveAll(ctx context.Context, familyCode string, assets []asset.Asset) (map[string]error, error) {
+	items := make([]akeneo.Asset, len(assets))
+	for i, item := range assets {
+		items[i] = akeneo.Asset(item)
+	}
+	return r.client.PatchAssets(ctx, familyCode, items)
+}
+
+// FindCodes retrieves the codes of all assets of an asset family, page by page
+func (r *DestAssetRepository) FindCodes(ctx context.Context, familyCode string) ([]string, error) {
+	var codes []string
+	err := r.client.StreamAssets(ctx, familyCode, codesPageSize, func(assets []akeneo.Asset) error {
+		for _, item := range assets {
+			if code, ok := item["code"].(string); ok {
+				codes = append(codes, code)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing assets of family %s: %w", familyCode, err)
+	}
+
+	return codes, nil
+}
+
+// Delete deletes an asset of an asset family
+func (r *DestAssetRepository) Delete(ctx context.Context, familyCode, code string) error {
+	if err := r.client.DeleteAsset(ctx, familyCode, code); err != nil {
+		return fmt.Errorf("error deleting asset %s: %w", code, err)
+	}
+	return nil
+}
+
+// UploadMediaFile stores a media file and returns the code assigned to it
+func (r *DestAssetRepository) UploadMediaFile(ctx context.Context, file asset.MediaFile) (string, error) {
+	return r.client.UploadAssetMediaFile(ctx, file.Filename, file.Content)
+}
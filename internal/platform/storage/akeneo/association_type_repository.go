@@ -0,0 +1,82 @@
+package akeneo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"akeneo-migrator/internal/association_type"
+	"akeneo-migrator/internal/platform/client/akeneo"
+)
+
+// SourceAssociationTypeRepository implements association_type.SourceRepository for Akeneo
+type SourceAssociationTypeRepository struct {
+	client akeneo.API
+}
+
+// NewSourceAssociationTypeRepository creates a new source association type repository
+func NewSourceAssociationTypeRepository(client akeneo.API) association_type.SourceRepository {
+	return &SourceAssociationTypeRepository{
+		client: client,
+	}
+}
+
+// FindByCode retrieves an association type by its code
+func (r *SourceAssociationTypeRepository) FindByCode(ctx context.Context, code string) (association_type.AssociationType, error) {
+	associationType, err := r.client.GetAssociationType(ctx, code)
+	if errors.Is(err, akeneo.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %w", association_type.ErrNotFound, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching association type %s: %w", code, err)
+	}
+	return association_type.AssociationType(associationType), nil
+}
+
+// FindCodes retrieves the codes of every association type
+func (r *SourceAssociationTypeRepository) FindCodes(ctx context.Context) ([]string, error) {
+	associationTypes, err := r.client.GetAssociationTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching association types: %w", err)
+	}
+
+	codes := make([]string, 0, len(associationTypes))
+	for _, associationType := range associationTypes {
+		if code, ok := associationType["code"].(string); ok {
+			codes = append(codes, code)
+		}
+	}
+	return codes, nil
+}
+
+// DestAssociationTypeRepository implements association_type.DestRepository for Akeneo
+type DestAssociationTypeRepository struct {
+	client akeneo.API
+}
+
+// NewDestAssociationTypeRepository creates a new destination association type repository
+func NewDestAssociationTypeRepository(client akeneo.API) association_type.DestRepository {
+	return &DestAssociationTypeRepository{
+		client: client,
+	}
+}
+
+// FindByCode retrieves an association type by its code
+func (r *DestAssociationTypeRepository) FindByCode(ctx context.Context, code string) (association_type.AssociationType, error) {
+	associationType, err := r.client.GetAssociationType(ctx, code)
+	if errors.Is(err, akeneo.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %w", association_type.ErrNotFound, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching association type %s: %w", code, err)
+	}
+	return association_type.AssociationType(associationType), nil
+}
+
+// Save creates or updates an association type
+func (r *DestAssociationTypeRepository) Save(ctx context.Context, code string, associationType association_type.AssociationType) error {
+	if err := r.client.PatchAssociationType(ctx, code, akeneo.AssociationType(associationType)); err != nil {
+		return fmt.Errorf("error saving association type %s: %w", code, err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package akeneo
+
+import (
+	"context"
+	"fmt"
+
+	"akeneo-migrator/internal/attribute_group"
+	"akeneo-migrator/internal/platform/client/akeneo"
+)
+
+// SourceAttributeGroupRepository implements attribute_group.SourceRepository for Akeneo
+type SourceAttributeGroupRepository struct {
+	client akeneo.API
+}
+
+// NewSourceAttributeGroupRepository creates a new source attribute group repository
+func NewSourceAttributeGroupRepository(client akeneo.API) attribute_group.SourceRepository {
+	return &SourceAttributeGroupRepository{
+		client: client,
+	}
+}
+
+// FindByCode retrieves an attribute group by its code
+func (r *SourceAttributeGroupRepository) FindByCode(ctx context.Context, code string) (attribute_group.AttributeGroup, error) {
+	group, err := r.client.GetAttributeGroup(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching attribute group %s: %w", code, err)
+	}
+	return attribute_group.AttributeGroup(group), nil
+}
+
+// DestAttributeGroupRepository implements attribute_group.DestRepository for Akeneo
+type DestAttributeGroupRepository struct {
+	client akeneo.API
+}
+
+// NewDestAttributeGroupRepository creates a new destination attribute group repository
+func NewDestAttributeGroupRepository(client akeneo.API) attribute_group.DestRepository {
+	return &DestAttributeGroupRepository{
+		client: client,
+	}
+}
+
+// Save creates or updates an attribute group
+func (r *DestAttributeGroupRepository) Save(ctx context.Context, code string, group attribute_group.AttributeGroup) error {
+	if err := r.client.PatchAttributeGroup(ctx, code, akeneo.AttributeGroup(group)); err != nil {
+		return fmt.Errorf("error saving attribute group %s: %w", code, err)
+	}
+	return nil
+}
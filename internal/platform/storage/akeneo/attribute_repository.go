@@ -10,11 +10,11 @@ import (
 
 // SourceAttributeRepository implements attribute.SourceRepository for Akeneo
 type SourceAttributeRepository struct {
-	client *akeneo.Client
+	client akeneo.API
 }
 
 // NewSourceAttributeRepository creates a new source attribute repository
-func NewSourceAttributeRepository(client *akeneo.Client) attribute.SourceRepository {
+func NewSourceAttributeRepository(client akeneo.API) attribute.SourceRepository {
 	return &SourceAttributeRepository{
 		client: client,
 	}
@@ -22,7 +22,7 @@ func NewSourceAttributeRepository(client *akeneo.Client) attribute.SourceReposit
 
 // FindByCode retrieves an attribute by its code
 func (r *SourceAttributeRepository) FindByCode(ctx context.Context, code string) (attribute.Attribute, error) {
-	attr, err := r.client.GetAttribute(code)
+	attr, err := r.client.GetAttribute(ctx, code)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching attribute %s: %w", code, err)
 	}
@@ -31,7 +31,7 @@ func (r *SourceAttributeRepository) FindByCode(ctx context.Context, code string)
 
 // GetOptions retrieves all options for an attribute
 func (r *SourceAttributeRepository) GetOptions(ctx context.Context, attributeCode string) ([]attribute.AttributeOption, error) {
-	options, err := r.client.GetAttributeOptions(attributeCode)
+	options, err := r.client.GetAttributeOptions(ctx, attributeCode)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching options for attribute %s: %w", attributeCode, err)
 	}
@@ -45,13 +45,28 @@ func (r *SourceAttributeRepository) GetOptions(ctx context.Context, attributeCod
 	return result, nil
 }
 
+// FindPage retrieves one page of attributes
+func (r *SourceAttributeRepository) FindPage(ctx context.Context, page, limit int) ([]attribute.Attribute, bool, error) {
+	attributes, hasNext, err := r.client.GetAttributes(ctx, page, limit)
+	if err != nil {
+		return nil, false, fmt.Errorf("error fetching page %d of attributes: %w", page, err)
+	}
+
+	result := make([]attribute.Attribute, len(attributes))
+	for i, attr := range attributes {
+		result[i] = attribute.Attribute(attr)
+	}
+
+	return result, hasNext, nil
+}
+
 // DestAttributeRepository implements attribute.DestRepository for Akeneo
 type DestAttributeRepository struct {
-	client *akeneo.Client
+	client akeneo.API
 }
 
 // NewDestAttributeRepository creates a new destination attribute repository
-func NewDestAttributeRepository(client *akeneo.Client) attribute.DestRepository {
+func NewDestAttributeRepository(client akeneo.API) attribute.DestRepository {
 	return &DestAttributeRepository{
 		client: client,
 	}
@@ -59,16 +74,51 @@ func NewDestAttributeRepository(client *akeneo.Client) attribute.DestRepository
 
 // Save creates or updates an attribute
 func (r *DestAttributeRepository) Save(ctx context.Context, code string, attr attribute.Attribute) error {
-	if err := r.client.PatchAttribute(code, akeneo.Attribute(attr)); err != nil {
+	if err := r.client.PatchAttribute(ctx, code, akeneo.Attribute(attr)); err != nil {
 		return fmt.Errorf("error saving attribute %s: %w", code, err)
 	}
 	return nil
 }
 
+// GetOptions retrieves all options for an attribute
+func (r *DestAttributeRepository) GetOptions(ctx context.Context, attributeCode string) ([]attribute.AttributeOption, error) {
+	options, err := r.client.GetAttributeOptions(ctx, attributeCode)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching options for attribute %s: %w", attributeCode, err)
+	}
+
+	result := make([]attribute.AttributeOption, len(options))
+	for i, opt := range options {
+		result[i] = attribute.AttributeOption(opt)
+	}
+
+	return result, nil
+}
+
 // SaveOption creates or updates an attribute option
 func (r *DestAttributeRepository) SaveOption(ctx context.Context, attributeCode, optionCode string, option attribute.AttributeOption) error {
-	if err := r.client.PatchAttributeOption(attributeCode, optionCode, akeneo.AttributeOption(option)); err != nil {
+	if err := r.client.PatchAttributeOption(ctx, attributeCode, optionCode, akeneo.AttributeOption(option)); err != nil {
 		return fmt.Errorf("error saving option %s for attribute %s: %w", optionCode, attributeCode, err)
 	}
 	return nil
 }
+
+// FindCodes retrieves the codes of every attribute, walking all pages
+func (r *DestAttributeRepository) FindCodes(ctx context.Context) (map[string]bool, error) {
+	codes := make(map[string]bool)
+	for page := 1; ; page++ {
+		attributes, hasNext, err := r.client.GetAttributes(ctx, page, codesPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching page %d of attributes: %w", page, err)
+		}
+
+		for _, attr := range attributes {
+			if code, ok := attr["code"].(string); ok {
+				codes[code] = true
+			}
+		}
+		if !hasNext {
+			return codes, nil
+		}
+	}
+}
@@ -2,6 +2,7 @@ package akeneo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"akeneo-migrator/internal/category"
@@ -10,11 +11,11 @@ import (
 
 // SourceCategoryRepository implements category.SourceRepository for Akeneo
 type SourceCategoryRepository struct {
-	client *akeneo.Client
+	client akeneo.API
 }
 
 // NewSourceCategoryRepository creates a new source category repository
-func NewSourceCategoryRepository(client *akeneo.Client) category.SourceRepository {
+func NewSourceCategoryRepository(client akeneo.API) category.SourceRepository {
 	return &SourceCategoryRepository{
 		client: client,
 	}
@@ -22,29 +23,99 @@ func NewSourceCategoryRepository(client *akeneo.Client) category.SourceRepositor
 
 // FindByCode retrieves a category by its code
 func (r *SourceCategoryRepository) FindByCode(ctx context.Context, code string) (category.Category, error) {
-	cat, err := r.client.GetCategory(code)
+	cat, err := r.client.GetCategory(ctx, code)
+	if errors.Is(err, akeneo.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %w", category.ErrNotFound, err)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("error fetching category %s: %w", code, err)
 	}
 	return category.Category(cat), nil
 }
 
+// FindChildren retrieves the direct children of a category
+func (r *SourceCategoryRepository) FindChildren(ctx context.Context, parentCode string) ([]category.Category, error) {
+	children, err := r.client.GetCategoriesByParent(ctx, parentCode)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching children of category %s: %w", parentCode, err)
+	}
+
+	result := make([]category.Category, len(children))
+	for i, child := range children {
+		result[i] = category.Category(child)
+	}
+
+	return result, nil
+}
+
+// FindRootCodes retrieves the codes of the roots of every category tree
+func (r *SourceCategoryRepository) FindRootCodes(ctx context.Context) ([]string, error) {
+	roots, err := r.client.GetRootCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching root categories: %w", err)
+	}
+
+	codes := make([]string, 0, len(roots))
+	for _, root := range roots {
+		if code, ok := root["code"].(string); ok {
+			codes = append(codes, code)
+		}
+	}
+	return codes, nil
+}
+
 // DestCategoryRepository implements category.DestRepository for Akeneo
 type DestCategoryRepository struct {
-	client *akeneo.Client
+	client akeneo.API
 }
 
 // NewDestCategoryRepository creates a new destination category repository
-func NewDestCategoryRepository(client *akeneo.Client) category.DestRepository {
+func NewDestCategoryRepository(client akeneo.API) category.DestRepository {
 	return &DestCategoryRepository{
 		client: client,
 	}
 }
 
+// FindByCode retrieves a category by its code
+func (r *DestCategoryRepository) FindByCode(ctx context.Context, code string) (category.Category, error) {
+	cat, err := r.client.GetCategory(ctx, code)
+	if errors.Is(err, akeneo.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %w", category.ErrNotFound, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching category %s: %w", code, err)
+	}
+	return category.Category(cat), nil
+}
+
 // Save creates or updates a category
 func (r *DestCategoryRepository) Save(ctx context.Context, code string, cat category.Category) error {
-	if err := r.client.PatchCategory(code, akeneo.Category(cat)); err != nil {
+	if err := r.client.PatchCategory(ctx, code, akeneo.Category(cat)); err != nil {
 		return fmt.Errorf("error saving category %s: %w", code, err)
 	}
 	return nil
 }
+
+// FindChildren retrieves the direct children of a category
+func (r *DestCategoryRepository) FindChildren(ctx context.Context, parentCode string) ([]category.Category, error) {
+	children, err := r.client.GetCategoriesByParent(ctx, parentCode)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching children of category %s: %w", parentCode, err)
+	}
+
+	result := make([]category.Category, len(children))
+	for i, child := range children {
+		result[i] = category.Category(child)
+	}
+
+	return result, nil
+}
+
+// FindProductIdentifiers retrieves the products classified in a category or its children
+func (r *DestCategoryRepository) FindProductIdentifiers(ctx context.Context, code string) ([]string, error) {
+	identifiers, err := r.client.GetProductIdentifiersByCategory(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching products of category %s: %w", code, err)
+	}
+	return identifiers, nil
+}
@@ -0,0 +1,102 @@
+package akeneo
+
+import (
+	"context"
+	"fmt"
+
+	"akeneo-migrator/internal/channel"
+	"akeneo-migrator/internal/platform/client/akeneo"
+)
+
+// SourceChannelRepository implements channel.SourceRepository for Akeneo
+type SourceChannelRepository struct {
+	client akeneo.API
+}
+
+// NewSourceChannelRepository creates a new source channel repository
+func NewSourceChannelRepository(client akeneo.API) channel.SourceRepository {
+	return &SourceChannelRepository{
+		client: client,
+	}
+}
+
+// FindByCode retrieves a channel by its code
+func (r *SourceChannelRepository) FindByCode(ctx context.Context, code string) (channel.Channel, error) {
+	ch, err := r.client.GetChannel(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching channel %s: %w", code, err)
+	}
+	return channel.Channel(ch), nil
+}
+
+// FindCodes retrieves the codes of every channel
+func (r *SourceChannelRepository) FindCodes(ctx context.Context) ([]string, error) {
+	channels, err := r.client.GetChannels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching channels: %w", err)
+	}
+
+	codes := make([]string, 0, len(channels))
+	for _, ch := range channels {
+		if code, ok := ch["code"].(string); ok {
+			codes = append(codes, code)
+		}
+	}
+	return codes, nil
+}
+
+// DestChannelRepository implements channel.DestRepository for Akeneo
+type DestChannelRepository struct {
+	client akeneo.API
+}
+
+// NewDestChannelRepository creates a new destination channel repository
+func NewDestChannelRepository(client akeneo.API) channel.DestRepository {
+	return &DestChannelRepository{
+		client: client,
+	}
+}
+
+// Save creates or updates a channel
+func (r *DestChannelRepository) Save(ctx context.Context, code string, ch channel.Channel) error {
+	if err := r.client.PatchChannel(ctx, code, akeneo.Channel(ch)); err != nil {
+		return fmt.Errorf("error saving channel %s: %w", code, err)
+	}
+	return nil
+}
+
+// FindLocales retrieves all locale codes with their activation status
+func (r *DestChannelRepository) FindLocales(ctx context.Context) (map[string]bool, error) {
+	locales, err := r.client.GetLocales(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching locales: %w", err)
+	}
+
+	result := make(map[string]bool, len(locales))
+	for _, locale := range locales {
+		if code, ok := locale["code"].(string); ok {
+			enabled, _ := locale["enabled"].(bool)
+			result[code] = enabled
+		}
+	}
+
+	return result, nil
+}
+
+// FindCurrencies retrieves all currency codes with their activation status
+func (r *DestChannelRepository) FindCurrencies(ctx context.Context) (map[string]bool, error) {
+	currencies, err := r.client.GetCurrencies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching currencies: %w", err)
+	}
+
+	result := make(map[string]bool, len(currencies))
+	for _, currency := range currencies {
+		if code, ok := currency["code"].(string); ok {
+			enabled, _ := currency["enabled"].(bool)
+			result[code] = enabled
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,78 @@
+package akeneo
+
+import (
+	"context"
+	"fmt"
+
+	"akeneo-migrator/internal/platform/client/akeneo"
+	"akeneo-migrator/internal/stats"
+)
+
+// recordBatchSize is the number of records fetched per page when they are counted
+const recordBatchSize = 100
+
+// countResources are the list endpoints reporting the count of each kind
+var countResources = map[stats.Kind]string{
+	stats.KindProducts:      "products",
+	stats.KindProductModels: "product-models",
+	stats.KindFamilies:      "families",
+	stats.KindAttributes:    "attributes",
+	stats.KindCategories:    "categories",
+}
+
+// CountRepository implements stats.Repository for Akeneo
+type CountRepository struct {
+	client akeneo.API
+}
+
+// NewCountRepository creates a new count repository
+func NewCountRepository(client akeneo.API) stats.Repository {
+	return &CountRepository{
+		client: client,
+	}
+}
+
+// Count returns the number of objects of a kind. Records do not report their count, so the records
+// of every reference entity are listed and counted.
+func (r *CountRepository) Count(ctx context.Context, kind stats.Kind) (int, error) {
+	if kind == stats.KindReferenceRecords {
+		return r.countRecords(ctx)
+	}
+
+	resource, ok := countResources[kind]
+	if !ok {
+		return 0, fmt.Errorf("unknown kind %q", kind)
+	}
+
+	count, err := r.client.CountItems(ctx, resource)
+	if err != nil {
+		return 0, fmt.Errorf("error counting %s: %w", kind, err)
+	}
+	return count, nil
+}
+
+// countRecords counts the records of every reference entity
+func (r *CountRepository) countRecords(ctx context.Context) (int, error) {
+	entities, err := r.client.GetReferenceEntities(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error fetching reference entities: %w", err)
+	}
+
+	count := 0
+	for _, entity := range entities {
+		code, ok := entity["code"].(string)
+		if !ok {
+			continue
+		}
+
+		err := r.client.StreamReferenceEntityRecords(ctx, code, recordBatchSize, func(records []akeneo.ReferenceEntityRecord) error {
+			count += len(records)
+			return nil
+		})
+		if err != nil {
+			return 0, fmt.Errorf("error counting records of reference entity %s: %w", code, err)
+		}
+	}
+
+	return count, nil
+}
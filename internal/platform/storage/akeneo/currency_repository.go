@@ -0,0 +1,47 @@
+package akeneo
+
+import (
+	"context"
+	"fmt"
+
+	"akeneo-migrator/internal/currency"
+	"akeneo-migrator/internal/platform/client/akeneo"
+)
+
+// CurrencyRepository implements currency.SourceRepository and currency.DestRepository for Akeneo.
+// Currencies are read-only in the API, so both sides share the same implementation.
+type CurrencyRepository struct {
+	client akeneo.API
+}
+
+// NewSourceCurrencyRepository creates a new source currency repository
+func NewSourceCurrencyRepository(client akeneo.API) currency.SourceRepository {
+	return &CurrencyRepository{
+		client: client,
+	}
+}
+
+// NewDestCurrencyRepository creates a new destination currency repository
+func NewDestCurrencyRepository(client akeneo.API) currency.DestRepository {
+	return &CurrencyRepository{
+		client: client,
+	}
+}
+
+// FindAll retrieves all currency codes with their activation status
+func (r *CurrencyRepository) FindAll(ctx context.Context) (map[string]bool, error) {
+	currencies, err := r.client.GetCurrencies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching currencies: %w", err)
+	}
+
+	result := make(map[string]bool, len(currencies))
+	for _, cur := range currencies {
+		if code, ok := cur["code"].(string); ok {
+			enabled, _ := cur["enabled"].(bool)
+			result[code] = enabled
+		}
+	}
+
+	return result, nil
+}
@@ -2,6 +2,7 @@ package akeneo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"akeneo-migrator/internal/family"
@@ -10,11 +11,11 @@ import (
 
 // SourceFamilyRepository implements family.SourceRepository for Akeneo
 type SourceFamilyRepository struct {
-	client *akeneo.Client
+	client akeneo.API
 }
 
 // NewSourceFamilyRepository creates a new source family repository
-func NewSourceFamilyRepository(client *akeneo.Client) family.SourceRepository {
+func NewSourceFamilyRepository(client akeneo.API) family.SourceRepository {
 	return &SourceFamilyRepository{
 		client: client,
 	}
@@ -22,7 +23,10 @@ func NewSourceFamilyRepository(client *akeneo.Client) family.SourceRepository {
 
 // FindByCode retrieves a family by its code
 func (r *SourceFamilyRepository) FindByCode(ctx context.Context, code string) (family.Family, error) {
-	fam, err := r.client.GetFamily(code)
+	fam, err := r.client.GetFamily(ctx, code)
+	if errors.Is(err, akeneo.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %w", family.ErrNotFound, err)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("error fetching family %s: %w", code, err)
 	}
@@ -31,7 +35,10 @@ func (r *SourceFamilyRepository) FindByCode(ctx context.Context, code string) (f
 
 // GetVariants retrieves all variants for a family
 func (r *SourceFamilyRepository) GetVariants(ctx context.Context, familyCode string) ([]family.FamilyVariant, error) {
-	variants, err := r.client.GetFamilyVariants(familyCode)
+	variants, err := r.client.GetFamilyVariants(ctx, familyCode)
+	if errors.Is(err, akeneo.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %w", family.ErrNotFound, err)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("error fetching variants for family %s: %w", familyCode, err)
 	}
@@ -45,21 +52,66 @@ func (r *SourceFamilyRepository) GetVariants(ctx context.Context, familyCode str
 	return result, nil
 }
 
+// FindPage retrieves one page of families
+func (r *SourceFamilyRepository) FindPage(ctx context.Context, page, limit int) ([]family.Family, bool, error) {
+	families, hasNext, err := r.client.GetFamilies(ctx, page, limit)
+	if err != nil {
+		return nil, false, fmt.Errorf("error fetching page %d of families: %w", page, err)
+	}
+
+	result := make([]family.Family, len(families))
+	for i, fam := range families {
+		result[i] = family.Family(fam)
+	}
+
+	return result, hasNext, nil
+}
+
 // DestFamilyRepository implements family.DestRepository for Akeneo
 type DestFamilyRepository struct {
-	client *akeneo.Client
+	client akeneo.API
 }
 
 // NewDestFamilyRepository creates a new destination family repository
-func NewDestFamilyRepository(client *akeneo.Client) family.DestRepository {
+func NewDestFamilyRepository(client akeneo.API) family.DestRepository {
 	return &DestFamilyRepository{
 		client: client,
 	}
 }
 
+// FindByCode retrieves a family by its code
+func (r *DestFamilyRepository) FindByCode(ctx context.Context, code string) (family.Family, error) {
+	fam, err := r.client.GetFamily(ctx, code)
+	if errors.Is(err, akeneo.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %w", family.ErrNotFound, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching family %s: %w", code, err)
+	}
+	return family.Family(fam), nil
+}
+
+// GetVariants retrieves all variants for a family
+func (r *DestFamilyRepository) GetVariants(ctx context.Context, familyCode string) ([]family.FamilyVariant, error) {
+	variants, err := r.client.GetFamilyVariants(ctx, familyCode)
+	if errors.Is(err, akeneo.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %w", family.ErrNotFound, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching variants for family %s: %w", familyCode, err)
+	}
+
+	result := make([]family.FamilyVariant, len(variants))
+	for i, variant := range variants {
+		result[i] = family.FamilyVariant(variant)
+	}
+
+	return result, nil
+}
+
 // Save creates or updates a family
 func (r *DestFamilyRepository) Save(ctx context.Context, code string, fam family.Family) error {
-	if err := r.client.PatchFamily(code, akeneo.Family(fam)); err != nil {
+	if err := r.client.PatchFamily(ctx, code, akeneo.Family(fam)); err != nil {
 		return fmt.Errorf("error saving family %s: %w", code, err)
 	}
 	return nil
@@ -67,7 +119,7 @@ func (r *DestFamilyRepository) Save(ctx context.Context, code string, fam family
 
 // SaveVariant creates or updates a family variant
 func (r *DestFamilyRepository) SaveVariant(ctx context.Context, familyCode, variantCode string, variant family.FamilyVariant) error {
-	if err := r.client.PatchFamilyVariant(familyCode, variantCode, akeneo.FamilyVariant(variant)); err != nil {
+	if err := r.client.PatchFamilyVariant(ctx, familyCode, variantCode, akeneo.FamilyVariant(variant)); err != nil {
 		return fmt.Errorf("error saving variant %s for family %s: %w", variantCode, familyCode, err)
 	}
 	return nil
@@ -0,0 +1,71 @@
+package akeneo
+
+import (
+	"context"
+	"fmt"
+
+	"akeneo-migrator/internal/measurement_family"
+	"akeneo-migrator/internal/platform/client/akeneo"
+)
+
+// SourceMeasurementFamilyRepository implements measurement_family.SourceRepository for Akeneo
+type SourceMeasurementFamilyRepository struct {
+	client akeneo.API
+}
+
+// NewSourceMeasurementFamilyRepository creates a new source measurement family repository
+func NewSourceMeasurementFamilyRepository(client akeneo.API) measurement_family.SourceRepository {
+	return &SourceMeasurementFamilyRepository{
+		client: client,
+	}
+}
+
+// FindAll retrieves all measurement families
+func (r *SourceMeasurementFamilyRepository) FindAll(ctx context.Context) ([]measurement_family.MeasurementFamily, error) {
+	return findAllMeasurementFamilies(ctx, r.client)
+}
+
+// DestMeasurementFamilyRepository implements measurement_family.DestRepository for Akeneo
+type DestMeasurementFamilyRepository struct {
+	client akeneo.API
+}
+
+// NewDestMeasurementFamilyRepository creates a new destination measurement family repository
+func NewDestMeasurementFamilyRepository(client akeneo.API) measurement_family.DestRepository {
+	return &DestMeasurementFamilyRepository{
+		client: client,
+	}
+}
+
+// FindAll retrieves all measurement families
+func (r *DestMeasurementFamilyRepository) FindAll(ctx context.Context) ([]measurement_family.MeasurementFamily, error) {
+	return findAllMeasurementFamilies(ctx, r.client)
+}
+
+// SaveAll creates or updates several measurement families in one call
+func (r *DestMeasurementFamilyRepository) SaveAll(ctx context.Context, families []measurement_family.MeasurementFamily) (map[string]error, error) {
+	items := make([]akeneo.MeasurementFamily, len(families))
+	for i, family := range families {
+		items[i] = akeneo.MeasurementFamily(family)
+	}
+
+	failed, err := r.client.PatchMeasurementFamilies(ctx, items)
+	if err != nil {
+		return nil, fmt.Errorf("error saving measurement families: %w", err)
+	}
+	return failed, nil
+}
+
+// findAllMeasurementFamilies retrieves and converts the measurement families of an instance
+func findAllMeasurementFamilies(ctx context.Context, client akeneo.API) ([]measurement_family.MeasurementFamily, error) {
+	families, err := client.GetMeasurementFamilies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching measurement families: %w", err)
+	}
+
+	result := make([]measurement_family.MeasurementFamily, len(families))
+	for i, family := range families {
+		result[i] = measurement_family.MeasurementFamily(family)
+	}
+	return result, nil
+}
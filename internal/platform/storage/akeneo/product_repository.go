@@ -2,7 +2,9 @@ package akeneo
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 
 	"akeneo-migrator/internal/platform/client/akeneo"
 	"akeneo-migrator/internal/product"
@@ -10,11 +12,11 @@ import (
 
 // SourceProductRepository implements the read-only repository for the source
 type SourceProductRepository struct {
-	client *akeneo.Client
+	client akeneo.API
 }
 
 // NewSourceProductRepository creates a new instance of the source repository
-func NewSourceProductRepository(client *akeneo.Client) *SourceProductRepository {
+func NewSourceProductRepository(client akeneo.API) *SourceProductRepository {
 	return &SourceProductRepository{
 		client: client,
 	}
@@ -22,7 +24,10 @@ func NewSourceProductRepository(client *akeneo.Client) *SourceProductRepository
 
 // FindByIdentifier retrieves a product by its identifier
 func (r *SourceProductRepository) FindByIdentifier(ctx context.Context, identifier string) (product.Product, error) {
-	productData, err := r.client.GetProduct(identifier)
+	productData, err := r.client.GetProduct(ctx, identifier)
+	if errors.Is(err, akeneo.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %w", product.ErrNotFound, err)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -32,7 +37,10 @@ func (r *SourceProductRepository) FindByIdentifier(ctx context.Context, identifi
 
 // FindModelByCode retrieves a product model by its code
 func (r *SourceProductRepository) FindModelByCode(ctx context.Context, code string) (product.ProductModel, error) {
-	model, err := r.client.GetProductModel(code)
+	model, err := r.client.GetProductModel(ctx, code)
+	if errors.Is(err, akeneo.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %w", product.ErrNotFound, err)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -42,7 +50,7 @@ func (r *SourceProductRepository) FindModelByCode(ctx context.Context, code stri
 
 // FindProductsByParent retrieves all products with a specific parent
 func (r *SourceProductRepository) FindProductsByParent(ctx context.Context, parentCode string) ([]product.Product, error) {
-	products, err := r.client.GetProductsByParent(parentCode)
+	products, err := r.client.GetProductsByParent(ctx, parentCode)
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +65,7 @@ func (r *SourceProductRepository) FindProductsByParent(ctx context.Context, pare
 
 // FindModelsByParent retrieves all product models with a specific parent
 func (r *SourceProductRepository) FindModelsByParent(ctx context.Context, parentCode string) ([]product.ProductModel, error) {
-	models, err := r.client.GetProductModelsByParent(parentCode)
+	models, err := r.client.GetProductModelsByParent(ctx, parentCode)
 	if err != nil {
 		return nil, err
 	}
@@ -70,13 +78,27 @@ func (r *SourceProductRepository) FindModelsByParent(ctx context.Context, parent
 	return result, nil
 }
 
+// DownloadMediaFile retrieves the content of a media file
+func (r *SourceProductRepository) DownloadMediaFile(ctx context.Context, code string) (product.MediaFile, error) {
+	content, err := r.client.DownloadMediaFile(ctx, code)
+	if err != nil {
+		return product.MediaFile{}, fmt.Errorf("error downloading media file %s: %w", code, err)
+	}
+
+	return product.MediaFile{Code: code, Filename: mediaFilename(code), Content: content}, nil
+}
+
 // DestProductRepository implements the read/write repository for the destination
 type DestProductRepository struct {
-	client *akeneo.Client
+	client akeneo.API
+
+	// identifierAttribute is fetched once, when products are first written by UUID
+	mu                  sync.Mutex
+	identifierAttribute string
 }
 
 // NewDestProductRepository creates a new instance of the destination repository
-func NewDestProductRepository(client *akeneo.Client) *DestProductRepository {
+func NewDestProductRepository(client akeneo.API) *DestProductRepository {
 	return &DestProductRepository{
 		client: client,
 	}
@@ -84,7 +106,10 @@ func NewDestProductRepository(client *akeneo.Client) *DestProductRepository {
 
 // FindByIdentifier retrieves a product by its identifier
 func (r *DestProductRepository) FindByIdentifier(ctx context.Context, identifier string) (product.Product, error) {
-	productData, err := r.client.GetProduct(identifier)
+	productData, err := r.client.GetProduct(ctx, identifier)
+	if errors.Is(err, akeneo.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %w", product.ErrNotFound, err)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -96,12 +121,15 @@ func (r *DestProductRepository) FindByIdentifier(ctx context.Context, identifier
 func (r *DestProductRepository) Save(ctx context.Context, identifier string, productData product.Product) error {
 	// Convert from product.Product to akeneo.Product
 	akeneoProduct := akeneo.Product(productData)
-	return r.client.PatchProduct(identifier, akeneoProduct)
+	return r.client.PatchProduct(ctx, identifier, akeneoProduct)
 }
 
 // FindModelByCode retrieves a product model by its code
 func (r *DestProductRepository) FindModelByCode(ctx context.Context, code string) (product.ProductModel, error) {
-	model, err := r.client.GetProductModel(code)
+	model, err := r.client.GetProductModel(ctx, code)
+	if errors.Is(err, akeneo.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %w", product.ErrNotFound, err)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -113,12 +141,30 @@ func (r *DestProductRepository) FindModelByCode(ctx context.Context, code string
 func (r *DestProductRepository) SaveModel(ctx context.Context, code string, model product.ProductModel) error {
 	// Convert from product.ProductModel to akeneo.ProductModel
 	akeneoModel := akeneo.ProductModel(model)
-	return r.client.PatchProductModel(code, akeneoModel)
+	return r.client.PatchProductModel(ctx, code, akeneoModel)
+}
+
+// SaveAll creates or updates several products in batches
+func (r *DestProductRepository) SaveAll(ctx context.Context, products []product.Product) (map[string]error, error) {
+	akeneoProducts := make([]akeneo.Product, len(products))
+	for i, p := range products {
+		akeneoProducts[i] = akeneo.Product(p)
+	}
+	return r.client.PatchProducts(ctx, akeneoProducts)
+}
+
+// SaveModels creates or updates several product models in batches
+func (r *DestProductRepository) SaveModels(ctx context.Context, models []product.ProductModel) (map[string]error, error) {
+	akeneoModels := make([]akeneo.ProductModel, len(models))
+	for i, m := range models {
+		akeneoModels[i] = akeneo.ProductModel(m)
+	}
+	return r.client.PatchProductModels(ctx, akeneoModels)
 }
 
 // FindProductsByParent retrieves all products with a specific parent
 func (r *DestProductRepository) FindProductsByParent(ctx context.Context, parentCode string) ([]product.Product, error) {
-	products, err := r.client.GetProductsByParent(parentCode)
+	products, err := r.client.GetProductsByParent(ctx, parentCode)
 	if err != nil {
 		return nil, err
 	}
@@ -133,7 +179,7 @@ func (r *DestProductRepository) FindProductsByParent(ctx context.Context, parent
 
 // FindModelsByParent retrieves all product models with a specific parent
 func (r *DestProductRepository) FindModelsByParent(ctx context.Context, parentCode string) ([]product.ProductModel, error) {
-	models, err := r.client.GetProductModelsByParent(parentCode)
+	models, err := r.client.GetProductModelsByParent(ctx, parentCode)
 	if err != nil {
 		return nil, err
 	}
@@ -148,7 +194,7 @@ func (r *DestProductRepository) FindModelsByParent(ctx context.Context, parentCo
 
 // FindProductsUpdatedSince retrieves all products updated since a specific date
 func (r *SourceProductRepository) FindProductsUpdatedSince(ctx context.Context, updatedSince string) ([]product.Product, error) {
-	products, err := r.client.GetProductsUpdatedSince(updatedSince)
+	products, err := r.client.GetProductsUpdatedSince(ctx, updatedSince)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching updated products: %w", err)
 	}
@@ -163,7 +209,7 @@ func (r *SourceProductRepository) FindProductsUpdatedSince(ctx context.Context,
 
 // FindModelsUpdatedSince retrieves all product models updated since a specific date
 func (r *SourceProductRepository) FindModelsUpdatedSince(ctx context.Context, updatedSince string) ([]product.ProductModel, error) {
-	models, err := r.client.GetProductModelsUpdatedSince(updatedSince)
+	models, err := r.client.GetProductModelsUpdatedSince(ctx, updatedSince)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching updated product models: %w", err)
 	}
@@ -177,8 +223,8 @@ func (r *SourceProductRepository) FindModelsUpdatedSince(ctx context.Context, up
 }
 
 // StreamProductsUpdatedSince processes products updated since a specific date in batches
-func (r *SourceProductRepository) StreamProductsUpdatedSince(ctx context.Context, updatedSince string, batchSize int, callback func([]product.Product) error) error {
-	return r.client.StreamProductsUpdatedSince(updatedSince, batchSize, func(products []akeneo.Product) error {
+func (r *SourceProductRepository) StreamProductsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]product.Product) error) error {
+	return r.client.StreamProductsUpdatedSince(ctx, updatedSince, updatedUntil, batchSize, func(products []akeneo.Product) error {
 		batch := make([]product.Product, len(products))
 		for i, p := range products {
 			batch[i] = product.Product(p)
@@ -187,9 +233,40 @@ func (r *SourceProductRepository) StreamProductsUpdatedSince(ctx context.Context
 	})
 }
 
+// StreamProductsBySearch processes the products matching a search filter in batches
+func (r *SourceProductRepository) StreamProductsBySearch(ctx context.Context, search string, batchSize int, callback func([]product.Product) error) error {
+	return r.client.StreamProductsBySearch(ctx, search, batchSize, func(products []akeneo.Product) error {
+		batch := make([]product.Product, len(products))
+		for i, p := range products {
+			batch[i] = product.Product(p)
+		}
+		return callback(batch)
+	})
+}
+
+// CountUpdatedSince returns the number of products and product models updated since a specific date
+func (r *SourceProductRepository) CountUpdatedSince(ctx context.Context, updatedSince, updatedUntil string) (int, error) {
+	products, err := r.client.CountUpdatedSince(ctx, "products", updatedSince, updatedUntil)
+	if err != nil {
+		return 0, err
+	}
+
+	models, err := r.client.CountUpdatedSince(ctx, "product-models", updatedSince, updatedUntil)
+	if err != nil {
+		return 0, err
+	}
+
+	return products + models, nil
+}
+
+// CountProductsBySearch returns the number of products matching a product query search filter
+func (r *SourceProductRepository) CountProductsBySearch(ctx context.Context, search string) (int, error) {
+	return r.client.CountMatching(ctx, "products", search)
+}
+
 // StreamModelsUpdatedSince processes product models updated since a specific date in batches
-func (r *SourceProductRepository) StreamModelsUpdatedSince(ctx context.Context, updatedSince string, batchSize int, callback func([]product.ProductModel) error) error {
-	return r.client.StreamProductModelsUpdatedSince(updatedSince, batchSize, func(models []akeneo.ProductModel) error {
+func (r *SourceProductRepository) StreamModelsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]product.ProductModel) error) error {
+	return r.client.StreamProductModelsUpdatedSince(ctx, updatedSince, updatedUntil, batchSize, func(models []akeneo.ProductModel) error {
 		batch := make([]product.ProductModel, len(models))
 		for i, m := range models {
 			batch[i] = product.ProductModel(m)
@@ -197,3 +274,92 @@ func (r *SourceProductRepository) StreamModelsUpdatedSince(ctx context.Context,
 		return callback(batch)
 	})
 }
+
+// UploadMediaFile stores a media file as the value of an existing product or product model
+func (r *DestProductRepository) UploadMediaFile(ctx context.Context, file product.MediaFile, target product.MediaTarget) (string, error) {
+	return r.client.UploadMediaFile(ctx, akeneo.MediaFileTarget(target), file.Filename, file.Content)
+}
+
+// FindUUIDs returns the UUIDs of the products with the given identifiers, indexed by identifier
+func (r *DestProductRepository) FindUUIDs(ctx context.Context, identifiers []string) (map[string]string, error) {
+	attribute, err := r.findIdentifierAttribute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	uuids, err := r.client.GetProductUUIDs(ctx, attribute, identifiers)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching product UUIDs: %w", err)
+	}
+
+	return uuids, nil
+}
+
+// SaveByUUID creates or updates a product by its UUID
+func (r *DestProductRepository) SaveByUUID(ctx context.Context, uuid string, productData product.Product) error {
+	attribute, err := r.findIdentifierAttribute(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.client.PatchProductByUUID(ctx, uuid, uuidProduct(productData, attribute))
+}
+
+// SaveAllByUUID creates or updates several products, each carrying its "uuid", in batches
+func (r *DestProductRepository) SaveAllByUUID(ctx context.Context, products []product.Product) (map[string]error, error) {
+	attribute, err := r.findIdentifierAttribute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	akeneoProducts := make([]akeneo.Product, len(products))
+	for i, p := range products {
+		akeneoProducts[i] = uuidProduct(p, attribute)
+	}
+	return r.client.PatchProductsByUUID(ctx, akeneoProducts)
+}
+
+// findIdentifierAttribute returns the code of the identifier attribute of the destination
+func (r *DestProductRepository) findIdentifierAttribute(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.identifierAttribute != "" {
+		return r.identifierAttribute, nil
+	}
+
+	attribute, err := r.client.GetIdentifierAttribute(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error fetching identifier attribute: %w", err)
+	}
+	r.identifierAttribute = attribute
+
+	return attribute, nil
+}
+
+// uuidProduct converts a product to the UUID format, where the identifier is a value of the identifier attribute
+func uuidProduct(productData product.Product, identifierAttribute string) akeneo.Product {
+	converted := make(akeneo.Product, len(productData))
+	for key, value := range productData {
+		converted[key] = value
+	}
+	delete(converted, "identifier")
+
+	identifier, _ := productData["identifier"].(string)
+	if identifier == "" {
+		return converted
+	}
+
+	values := make(map[string]interface{})
+	if sourceValues, ok := productData["values"].(map[string]interface{}); ok {
+		for attribute, value := range sourceValues {
+			values[attribute] = value
+		}
+	}
+	values[identifierAttribute] = []interface{}{
+		map[string]interface{}{"locale": nil, "scope": nil, "data": identifier},
+	}
+	converted["values"] = values
+
+	return converted
+}
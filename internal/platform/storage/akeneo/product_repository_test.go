@@ -0,0 +1,91 @@
+package akeneo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"akeneo-migrator/internal/platform/client/akeneo"
+	"akeneo-migrator/internal/platform/client/akeneo/akeneotest"
+	storage "akeneo-migrator/internal/platform/storage/akeneo"
+	"akeneo-migrator/internal/product"
+)
+
+func TestDestProductRepository_SaveAllSendsProductsInOneCall(t *testing.T) {
+	var sent []akeneo.Product
+	client := &akeneotest.MockAPI{
+		PatchProductsFunc: func(ctx context.Context, products []akeneo.Product) (map[string]error, error) {
+			sent = append(sent, products...)
+			return map[string]error{"B": errors.New("rejected")}, nil
+		},
+	}
+	repo := storage.NewDestProductRepository(client)
+
+	failed, err := repo.SaveAll(context.Background(), []product.Product{
+		{"identifier": "A"},
+		{"identifier": "B"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sent) != 2 {
+		t.Errorf("Expected 2 products sent, got %d", len(sent))
+	}
+	if len(failed) != 1 || failed["B"] == nil {
+		t.Errorf("Expected only B to fail, got %v", failed)
+	}
+}
+
+func TestSourceProductRepository_WrapsUpdatedSinceErrors(t *testing.T) {
+	client := &akeneotest.MockAPI{
+		GetProductsUpdatedSinceFunc: func(ctx context.Context, updatedSince string) ([]akeneo.Product, error) {
+			return nil, &akeneo.RateLimitError{}
+		},
+	}
+	repo := storage.NewSourceProductRepository(client)
+
+	_, err := repo.FindProductsUpdatedSince(context.Background(), "2024-01-01 00:00:00")
+
+	var rateLimit *akeneo.RateLimitError
+	if !errors.As(err, &rateLimit) {
+		t.Errorf("Expected a RateLimitError, got %v", err)
+	}
+}
+
+func TestDestProductRepository_SaveByUUIDMovesIdentifierToValues(t *testing.T) {
+	attributeLookups := 0
+	var sent akeneo.Product
+	client := &akeneotest.MockAPI{
+		GetIdentifierAttributeFunc: func(ctx context.Context) (string, error) {
+			attributeLookups++
+			return "sku", nil
+		},
+		PatchProductByUUIDFunc: func(ctx context.Context, uuid string, productData akeneo.Product) error {
+			sent = productData
+			return nil
+		},
+	}
+	repo := storage.NewDestProductRepository(client)
+
+	for i := 0; i < 2; i++ {
+		err := repo.SaveByUUID(context.Background(), "aaaa", product.Product{
+			"identifier": "SKU-1",
+			"values":     map[string]interface{}{"name": []interface{}{}},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if _, ok := sent["identifier"]; ok {
+		t.Error("Expected the identifier field to be removed")
+	}
+	values := sent["values"].(map[string]interface{})
+	sku, _ := values["sku"].([]interface{})
+	if len(sku) != 1 || sku[0].(map[string]interface{})["data"] != "SKU-1" || values["name"] == nil {
+		t.Errorf("Expected the identifier as a sku value next to the other values, got %v", values)
+	}
+	if attributeLookups != 1 {
+		t.Errorf("Expected the identifier attribute to be fetched once, got %d", attributeLookups)
+	}
+}
@@ -0,0 +1,48 @@
+package akeneo
+
+import (
+	"context"
+	"fmt"
+
+	"akeneo-migrator/internal/platform/client/akeneo"
+	"akeneo-migrator/internal/product"
+)
+
+// PublishedProductRepository implements product.PublishedRepository for Akeneo.
+// Published products are read-only in the API, so source and destination share the same implementation.
+type PublishedProductRepository struct {
+	client akeneo.API
+}
+
+// NewPublishedProductRepository creates a new published product repository
+func NewPublishedProductRepository(client akeneo.API) product.PublishedRepository {
+	return &PublishedProductRepository{
+		client: client,
+	}
+}
+
+// StreamPublished processes the published products in batches
+func (r *PublishedProductRepository) StreamPublished(ctx context.Context, batchSize int, callback func([]product.Product) error) error {
+	err := r.client.StreamPublishedProducts(ctx, batchSize, func(published []akeneo.PublishedProduct) error {
+		products := make([]product.Product, len(published))
+		for i, p := range published {
+			products[i] = product.Product(p)
+		}
+		return callback(products)
+	})
+	if err != nil {
+		return fmt.Errorf("error fetching published products: %w", err)
+	}
+
+	return nil
+}
+
+// FindPublished retrieves the published version of a product
+func (r *PublishedProductRepository) FindPublished(ctx context.Context, identifier string) (product.Product, error) {
+	published, err := r.client.GetPublishedProduct(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return product.Product(published), nil
+}
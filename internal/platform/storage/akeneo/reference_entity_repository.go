@@ -2,26 +2,42 @@ package akeneo
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
 
 	"akeneo-migrator/internal/platform/client/akeneo"
 	"akeneo-migrator/internal/reference_entity"
 )
 
+// codesPageSize is the page size used to list the codes of destination attributes, records and assets,
+// the largest page Akeneo serves
+const codesPageSize = 100
+
 // SourceReferenceEntityRepository implements the read-only repository for the source
 type SourceReferenceEntityRepository struct {
-	client *akeneo.Client
+	client akeneo.API
 }
 
 // NewSourceReferenceEntityRepository creates a new instance of the source repository
-func NewSourceReferenceEntityRepository(client *akeneo.Client) *SourceReferenceEntityRepository {
+func NewSourceReferenceEntityRepository(client akeneo.API) *SourceReferenceEntityRepository {
 	return &SourceReferenceEntityRepository{
 		client: client,
 	}
 }
 
+// FindEntities retrieves all Reference Entity definitions
+func (r *SourceReferenceEntityRepository) FindEntities(ctx context.Context) ([]reference_entity.Entity, error) {
+	return findEntities(ctx, r.client)
+}
+
 // FindEntity retrieves a Reference Entity definition
 func (r *SourceReferenceEntityRepository) FindEntity(ctx context.Context, entityCode string) (reference_entity.Entity, error) {
-	entity, err := r.client.GetReferenceEntity(entityCode)
+	entity, err := r.client.GetReferenceEntity(ctx, entityCode)
+	if errors.Is(err, akeneo.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %w", reference_entity.ErrNotFound, err)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -31,7 +47,7 @@ func (r *SourceReferenceEntityRepository) FindEntity(ctx context.Context, entity
 
 // FindAttributes retrieves all attributes from a Reference Entity
 func (r *SourceReferenceEntityRepository) FindAttributes(ctx context.Context, entityCode string) ([]reference_entity.Attribute, error) {
-	attributes, err := r.client.GetReferenceEntityAttributes(entityCode)
+	attributes, err := r.client.GetReferenceEntityAttributes(ctx, entityCode)
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +63,7 @@ func (r *SourceReferenceEntityRepository) FindAttributes(ctx context.Context, en
 
 // FindAll retrieves all records from a Reference Entity
 func (r *SourceReferenceEntityRepository) FindAll(ctx context.Context, entityName string) ([]reference_entity.Record, error) {
-	records, err := r.client.GetReferenceEntityRecords(entityName)
+	records, err := r.client.GetReferenceEntityRecords(ctx, entityName)
 	if err != nil {
 		return nil, err
 	}
@@ -61,21 +77,60 @@ func (r *SourceReferenceEntityRepository) FindAll(ctx context.Context, entityNam
 	return result, nil
 }
 
+// StreamRecords processes the records of a Reference Entity in batches, as they are fetched
+func (r *SourceReferenceEntityRepository) StreamRecords(ctx context.Context, entityName string, batchSize int, callback func([]reference_entity.Record) error) error {
+	return r.client.StreamReferenceEntityRecords(ctx, entityName, batchSize, func(records []akeneo.ReferenceEntityRecord) error {
+		batch := make([]reference_entity.Record, len(records))
+		for i, record := range records {
+			batch[i] = reference_entity.Record(record)
+		}
+		return callback(batch)
+	})
+}
+
+// FindRecord retrieves a single record from a Reference Entity
+func (r *SourceReferenceEntityRepository) FindRecord(ctx context.Context, entityName string, code string) (reference_entity.Record, error) {
+	record, err := r.client.GetReferenceEntityRecord(ctx, entityName, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return reference_entity.Record(record), nil
+}
+
+// DownloadMediaFile retrieves the content of a media file
+func (r *SourceReferenceEntityRepository) DownloadMediaFile(ctx context.Context, code string) (reference_entity.MediaFile, error) {
+	content, err := r.client.DownloadReferenceEntityMediaFile(ctx, code)
+	if err != nil {
+		return reference_entity.MediaFile{}, err
+	}
+
+	return reference_entity.MediaFile{Code: code, Filename: mediaFilename(code), Content: content}, nil
+}
+
 // DestReferenceEntityRepository implements the read/write repository for the destination
 type DestReferenceEntityRepository struct {
-	client *akeneo.Client
+	client akeneo.API
 }
 
 // NewDestReferenceEntityRepository creates a new instance of the destination repository
-func NewDestReferenceEntityRepository(client *akeneo.Client) *DestReferenceEntityRepository {
+func NewDestReferenceEntityRepository(client akeneo.API) *DestReferenceEntityRepository {
 	return &DestReferenceEntityRepository{
 		client: client,
 	}
 }
 
+// FindEntities retrieves all Reference Entity definitions
+func (r *DestReferenceEntityRepository) FindEntities(ctx context.Context) ([]reference_entity.Entity, error) {
+	return findEntities(ctx, r.client)
+}
+
 // FindEntity retrieves a Reference Entity definition
 func (r *DestReferenceEntityRepository) FindEntity(ctx context.Context, entityCode string) (reference_entity.Entity, error) {
-	entity, err := r.client.GetReferenceEntity(entityCode)
+	entity, err := r.client.GetReferenceEntity(ctx, entityCode)
+	if errors.Is(err, akeneo.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %w", reference_entity.ErrNotFound, err)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -87,12 +142,12 @@ func (r *DestReferenceEntityRepository) FindEntity(ctx context.Context, entityCo
 func (r *DestReferenceEntityRepository) SaveEntity(ctx context.Context, entityCode string, entity reference_entity.Entity) error {
 	// Convert from reference_entity.Entity to akeneo.ReferenceEntity
 	akeneoEntity := akeneo.ReferenceEntity(entity)
-	return r.client.PatchReferenceEntity(entityCode, akeneoEntity)
+	return r.client.PatchReferenceEntity(ctx, entityCode, akeneoEntity)
 }
 
 // FindAttributes retrieves all attributes from a Reference Entity
 func (r *DestReferenceEntityRepository) FindAttributes(ctx context.Context, entityCode string) ([]reference_entity.Attribute, error) {
-	attributes, err := r.client.GetReferenceEntityAttributes(entityCode)
+	attributes, err := r.client.GetReferenceEntityAttributes(ctx, entityCode)
 	if err != nil {
 		return nil, err
 	}
@@ -110,12 +165,12 @@ func (r *DestReferenceEntityRepository) FindAttributes(ctx context.Context, enti
 func (r *DestReferenceEntityRepository) SaveAttribute(ctx context.Context, entityCode string, attributeCode string, attribute reference_entity.Attribute) error {
 	// Convert from reference_entity.Attribute to akeneo.ReferenceEntityAttribute
 	akeneoAttribute := akeneo.ReferenceEntityAttribute(attribute)
-	return r.client.PatchReferenceEntityAttribute(entityCode, attributeCode, akeneoAttribute)
+	return r.client.PatchReferenceEntityAttribute(ctx, entityCode, attributeCode, akeneoAttribute)
 }
 
 // FindAll retrieves all records from a Reference Entity
 func (r *DestReferenceEntityRepository) FindAll(ctx context.Context, entityName string) ([]reference_entity.Record, error) {
-	records, err := r.client.GetReferenceEntityRecords(entityName)
+	records, err := r.client.GetReferenceEntityRecords(ctx, entityName)
 	if err != nil {
 		return nil, err
 	}
@@ -129,9 +184,81 @@ func (r *DestReferenceEntityRepository) FindAll(ctx context.Context, entityName
 	return result, nil
 }
 
+// FindRecord retrieves a single record from a Reference Entity
+func (r *DestReferenceEntityRepository) FindRecord(ctx context.Context, entityName string, code string) (reference_entity.Record, error) {
+	record, err := r.client.GetReferenceEntityRecord(ctx, entityName, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return reference_entity.Record(record), nil
+}
+
 // Save creates or updates a record in a Reference Entity
 func (r *DestReferenceEntityRepository) Save(ctx context.Context, entityName string, code string, record reference_entity.Record) error {
 	// Convert from reference_entity.Record to akeneo.ReferenceEntityRecord
 	akeneoRecord := akeneo.ReferenceEntityRecord(record)
-	return r.client.PatchReferenceEntityRecord(entityName, code, akeneoRecord)
+	return r.client.PatchReferenceEntityRecord(ctx, entityName, code, akeneoRecord)
+}
+
+// SaveAll creates or updates several records of a Reference Entity in batches
+func (r *DestReferenceEntityRepository) SaveAll(ctx context.Context, entityName string, records []reference_entity.Record) (map[string]error, error) {
+	akeneoRecords := make([]akeneo.ReferenceEntityRecord, len(records))
+	for i, record := range records {
+		akeneoRecords[i] = akeneo.ReferenceEntityRecord(record)
+	}
+	return r.client.PatchReferenceEntityRecords(ctx, entityName, akeneoRecords)
+}
+
+// FindCodes retrieves the codes of all records of a Reference Entity, page by page
+func (r *DestReferenceEntityRepository) FindCodes(ctx context.Context, entityName string) ([]string, error) {
+	var codes []string
+	err := r.client.StreamReferenceEntityRecords(ctx, entityName, codesPageSize, func(records []akeneo.ReferenceEntityRecord) error {
+		for _, record := range records {
+			if code, ok := record["code"].(string); ok {
+				codes = append(codes, code)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Delete deletes a record of a Reference Entity
+func (r *DestReferenceEntityRepository) Delete(ctx context.Context, entityName string, code string) error {
+	return r.client.DeleteReferenceEntityRecord(ctx, entityName, code)
+}
+
+// UploadMediaFile stores a media file and returns the code assigned to it
+func (r *DestReferenceEntityRepository) UploadMediaFile(ctx context.Context, file reference_entity.MediaFile) (string, error) {
+	return r.client.UploadReferenceEntityMediaFile(ctx, file.Filename, file.Content)
+}
+
+// findEntities retrieves all Reference Entity definitions of an instance
+func findEntities(ctx context.Context, client akeneo.API) ([]reference_entity.Entity, error) {
+	entities, err := client.GetReferenceEntities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]reference_entity.Entity, len(entities))
+	for i, entity := range entities {
+		result[i] = reference_entity.Entity(entity)
+	}
+
+	return result, nil
+}
+
+// mediaFilename returns the original name of a media file.
+// Media file codes end with it, e.g. "1/2/3/4/1234abcd_logo.png"
+func mediaFilename(code string) string {
+	filename := path.Base(code)
+	if _, original, found := strings.Cut(filename, "_"); found && original != "" {
+		filename = original
+	}
+	return filename
 }
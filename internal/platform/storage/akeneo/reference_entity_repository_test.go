@@ -0,0 +1,93 @@
+package akeneo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"akeneo-migrator/internal/platform/client/akeneo"
+	"akeneo-migrator/internal/platform/client/akeneo/akeneotest"
+	storage "akeneo-migrator/internal/platform/storage/akeneo"
+	"akeneo-migrator/internal/reference_entity"
+)
+
+func TestSourceReferenceEntityRepository_DownloadMediaFileUsesOriginalFilename(t *testing.T) {
+	client := &akeneotest.MockAPI{
+		DownloadReferenceEntityMediaFileFunc: func(ctx context.Context, code string) ([]byte, error) {
+			return []byte("png"), nil
+		},
+	}
+	repo := storage.NewSourceReferenceEntityRepository(client)
+
+	file, err := repo.DownloadMediaFile(context.Background(), "1/2/3/4/1234abcd_logo.png")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if file.Filename != "logo.png" {
+		t.Errorf("Expected filename 'logo.png', got '%s'", file.Filename)
+	}
+	if file.Code != "1/2/3/4/1234abcd_logo.png" {
+		t.Errorf("Expected the code to be kept, got '%s'", file.Code)
+	}
+}
+
+func TestSourceReferenceEntityRepository_StreamRecordsConvertsBatches(t *testing.T) {
+	client := &akeneotest.MockAPI{
+		StreamReferenceEntityRecordsFunc: func(ctx context.Context, entityName string, batchSize int, callback func([]akeneo.ReferenceEntityRecord) error) error {
+			if entityName != "brands" || batchSize != 2 {
+				t.Errorf("Unexpected stream of '%s' by %d", entityName, batchSize)
+			}
+			if err := callback([]akeneo.ReferenceEntityRecord{{"code": "a"}, {"code": "b"}}); err != nil {
+				return err
+			}
+			return callback([]akeneo.ReferenceEntityRecord{{"code": "c"}})
+		},
+	}
+	repo := storage.NewSourceReferenceEntityRepository(client)
+
+	var codes []string
+	err := repo.StreamRecords(context.Background(), "brands", 2, func(records []reference_entity.Record) error {
+		for _, record := range records {
+			codes = append(codes, record["code"].(string))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(codes) != 3 || codes[0] != "a" || codes[2] != "c" {
+		t.Errorf("Expected records a, b and c, got %v", codes)
+	}
+}
+
+func TestDestReferenceEntityRepository_ReturnsClientErrors(t *testing.T) {
+	repo := storage.NewDestReferenceEntityRepository(&akeneotest.MockAPI{
+		GetReferenceEntityRecordFunc: func(ctx context.Context, entityName, code string) (akeneo.ReferenceEntityRecord, error) {
+			return nil, akeneo.ErrNotFound
+		},
+	})
+
+	_, err := repo.FindRecord(context.Background(), "brands", "missing")
+	if !errors.Is(err, akeneo.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	// Operations the test did not set fail instead of returning empty data
+	if _, err := repo.FindAll(context.Background(), "brands"); err == nil {
+		t.Error("Expected an error from an operation that is not configured")
+	}
+}
+
+func TestDestReferenceEntityRepository_FindEntityReportsNotFound(t *testing.T) {
+	repo := storage.NewDestReferenceEntityRepository(&akeneotest.MockAPI{
+		GetReferenceEntityFunc: func(ctx context.Context, entityCode string) (akeneo.ReferenceEntity, error) {
+			return nil, fmt.Errorf("reference entity '%s' %w", entityCode, akeneo.ErrNotFound)
+		},
+	})
+
+	_, err := repo.FindEntity(context.Background(), "brands")
+	if !errors.Is(err, reference_entity.ErrNotFound) {
+		t.Errorf("Expected reference_entity.ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package akeneo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"akeneo-migrator/internal/platform/client/akeneo"
+	"akeneo-migrator/internal/product"
+)
+
+// StructureRepository implements product.StructureRepository for Akeneo
+type StructureRepository struct {
+	client akeneo.API
+}
+
+// NewStructureRepository creates a new structure repository
+func NewStructureRepository(client akeneo.API) product.StructureRepository {
+	return &StructureRepository{
+		client: client,
+	}
+}
+
+// FindFamilyCodes retrieves the codes of every family, walking all pages
+func (r *StructureRepository) FindFamilyCodes(ctx context.Context) (map[string]bool, error) {
+	codes := make(map[string]bool)
+	for page := 1; ; page++ {
+		families, hasNext, err := r.client.GetFamilies(ctx, page, codesPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching page %d of families: %w", page, err)
+		}
+
+		for _, fam := range families {
+			if code, ok := fam["code"].(string); ok {
+				codes[code] = true
+			}
+		}
+		if !hasNext {
+			return codes, nil
+		}
+	}
+}
+
+// FindFamilyVariantCodes retrieves the codes of the variants of a family
+func (r *StructureRepository) FindFamilyVariantCodes(ctx context.Context, familyCode string) (map[string]bool, error) {
+	variants, err := r.client.GetFamilyVariants(ctx, familyCode)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching variants of family %s: %w", familyCode, err)
+	}
+
+	codes := make(map[string]bool, len(variants))
+	for _, variant := range variants {
+		if code, ok := variant["code"].(string); ok {
+			codes[code] = true
+		}
+	}
+	return codes, nil
+}
+
+// FindAttributeTypes retrieves the type of every attribute, walking all pages
+func (r *StructureRepository) FindAttributeTypes(ctx context.Context) (map[string]string, error) {
+	definitions, err := r.FindAttributeDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	types := make(map[string]string, len(definitions))
+	for code, definition := range definitions {
+		types[code] = definition.Type
+	}
+	return types, nil
+}
+
+// FindAttributeDefinitions retrieves the type, localizable and scopable flags of every attribute, walking all pages
+func (r *StructureRepository) FindAttributeDefinitions(ctx context.Context) (map[string]product.AttributeDefinition, error) {
+	definitions := make(map[string]product.AttributeDefinition)
+	for page := 1; ; page++ {
+		attributes, hasNext, err := r.client.GetAttributes(ctx, page, codesPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching page %d of attributes: %w", page, err)
+		}
+
+		for _, attr := range attributes {
+			if code, ok := attr["code"].(string); ok {
+				attrType, _ := attr["type"].(string)
+				localizable, _ := attr["localizable"].(bool)
+				scopable, _ := attr["scopable"].(bool)
+				definitions[code] = product.AttributeDefinition{Type: attrType, Localizable: localizable, Scopable: scopable}
+			}
+		}
+		if !hasNext {
+			return definitions, nil
+		}
+	}
+}
+
+// FindOptionCodes retrieves the codes of the options of a select attribute
+func (r *StructureRepository) FindOptionCodes(ctx context.Context, attributeCode string) (map[string]bool, error) {
+	options, err := r.client.GetAttributeOptions(ctx, attributeCode)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching options for attribute %s: %w", attributeCode, err)
+	}
+
+	codes := make(map[string]bool, len(options))
+	for _, option := range options {
+		if code, ok := option["code"].(string); ok {
+			codes[code] = true
+		}
+	}
+	return codes, nil
+}
+
+// FindFamilyAttributes retrieves the codes of the attributes of a family
+func (r *StructureRepository) FindFamilyAttributes(ctx context.Context, familyCode string) ([]string, error) {
+	fam, err := r.client.GetFamily(ctx, familyCode)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching family %s: %w", familyCode, err)
+	}
+
+	list, _ := fam["attributes"].([]interface{})
+	codes := make([]string, 0, len(list))
+	for _, attr := range list {
+		if code, ok := attr.(string); ok {
+			codes = append(codes, code)
+		}
+	}
+	return codes, nil
+}
+
+// FindCategoryParent retrieves the parent of a category; a category that is not found is reported
+// as missing rather than as an error
+func (r *StructureRepository) FindCategoryParent(ctx context.Context, code string) (string, bool, error) {
+	cat, err := r.client.GetCategory(ctx, code)
+	if errors.Is(err, akeneo.ErrNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("error fetching category %s: %w", code, err)
+	}
+
+	parent, _ := cat["parent"].(string)
+	return parent, true, nil
+}
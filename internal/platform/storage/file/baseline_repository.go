@@ -0,0 +1,75 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"akeneo-migrator/kit/conflict"
+)
+
+// BaselineRepository implements conflict.Baselines with one JSON file per item in a directory,
+// grouped by kind
+type BaselineRepository struct {
+	dir string
+}
+
+// baseline is the content of a baseline file
+type baseline struct {
+	Kind    string `json:"kind"`
+	Code    string `json:"code"`
+	Updated string `json:"updated"`
+}
+
+// NewBaselineRepository creates a new baseline repository storing its files in dir
+func NewBaselineRepository(dir string) conflict.Baselines {
+	return &BaselineRepository{
+		dir: dir,
+	}
+}
+
+// Find returns the update date recorded for an item
+func (r *BaselineRepository) Find(ctx context.Context, kind, code string) (string, bool, error) {
+	data, err := os.ReadFile(r.path(kind, code))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("error reading baseline of %s %s: %w", kind, code, err)
+	}
+
+	var b baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return "", false, fmt.Errorf("error decoding baseline of %s %s: %w", kind, code, err)
+	}
+
+	return b.Updated, true, nil
+}
+
+// Save records the update date of an item
+func (r *BaselineRepository) Save(ctx context.Context, kind, code, updated string) error {
+	path := r.path(kind, code)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error creating baseline directory: %w", err)
+	}
+
+	data, err := json.Marshal(baseline{Kind: kind, Code: code, Updated: updated})
+	if err != nil {
+		return fmt.Errorf("error encoding baseline of %s %s: %w", kind, code, err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("error writing baseline of %s %s: %w", kind, code, err)
+	}
+
+	return nil
+}
+
+// path returns the file of an item; codes are escaped as they may contain slashes
+func (r *BaselineRepository) path(kind, code string) string {
+	return filepath.Join(r.dir, kind, url.PathEscape(code)+".json")
+}
@@ -0,0 +1,65 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"akeneo-migrator/internal/migration"
+)
+
+// CheckpointRepository implements migration.CheckpointRepository with a JSON file
+type CheckpointRepository struct {
+	path string
+}
+
+// NewCheckpointRepository creates a new checkpoint repository storing the checkpoint in path
+func NewCheckpointRepository(path string) migration.CheckpointRepository {
+	return &CheckpointRepository{
+		path: path,
+	}
+}
+
+// Find retrieves the checkpoint
+func (r *CheckpointRepository) Find(ctx context.Context) (migration.Checkpoint, error) {
+	data, err := os.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return migration.Checkpoint{}, migration.ErrNotFound
+	}
+	if err != nil {
+		return migration.Checkpoint{}, fmt.Errorf("error reading checkpoint %s: %w", r.path, err)
+	}
+
+	var checkpoint migration.Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return migration.Checkpoint{}, fmt.Errorf("error decoding checkpoint %s: %w", r.path, err)
+	}
+
+	return checkpoint, nil
+}
+
+// Save creates or replaces the checkpoint. The file is replaced atomically, so an interrupted
+// write never leaves a truncated checkpoint.
+func (r *CheckpointRepository) Save(ctx context.Context, checkpoint migration.Checkpoint) error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("error creating checkpoint directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding checkpoint: %w", err)
+	}
+
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("error writing checkpoint %s: %w", r.path, err)
+	}
+	if err := os.Rename(tmp, r.path); err != nil {
+		return fmt.Errorf("error writing checkpoint %s: %w", r.path, err)
+	}
+
+	return nil
+}
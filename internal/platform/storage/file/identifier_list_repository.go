@@ -0,0 +1,155 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"akeneo-migrator/internal/product"
+)
+
+// IdentifierListRepository implements product.IdentifierListRepository with files whose format
+// is chosen by their extension:
+//   - .json: an array of identifiers, or of objects with an "identifier" field
+//   - .csv: the identifier column, named identifier, sku or code in the header, or the first column
+//     when there is no such header; "," or ";" separated
+//   - anything else: one identifier per line, blank lines and lines starting with # being skipped
+type IdentifierListRepository struct{}
+
+// NewIdentifierListRepository creates a new identifier list repository
+func NewIdentifierListRepository() product.IdentifierListRepository {
+	return &IdentifierListRepository{}
+}
+
+// Read returns the identifiers listed in a file, in order and without duplicates
+func (r *IdentifierListRepository) Read(ctx context.Context, path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("identifier list %s not found", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading identifier list %s: %w", path, err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	var identifiers []string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		identifiers, err = parseJSONIdentifiers(data)
+	case ".csv":
+		identifiers, err = parseCSVIdentifiers(data)
+	default:
+		identifiers = parseTextIdentifiers(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error decoding identifier list %s: %w", path, err)
+	}
+
+	return unique(identifiers), nil
+}
+
+// parseJSONIdentifiers reads an array of identifiers or of objects with an "identifier" field
+func parseJSONIdentifiers(data []byte) ([]string, error) {
+	var entries []json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("expected an array of identifiers: %w", err)
+	}
+
+	identifiers := make([]string, 0, len(entries))
+	for i, entry := range entries {
+		var identifier string
+		if err := json.Unmarshal(entry, &identifier); err != nil {
+			var item struct {
+				Identifier string `json:"identifier"`
+			}
+			if err := json.Unmarshal(entry, &item); err != nil || item.Identifier == "" {
+				return nil, fmt.Errorf("entry %d is neither an identifier nor an object with an identifier", i+1)
+			}
+			identifier = item.Identifier
+		}
+		identifiers = append(identifiers, identifier)
+	}
+
+	return identifiers, nil
+}
+
+// identifierColumns are the header names recognized as the identifier column of a CSV file, sku
+// being the identifier attribute of most catalogs
+var identifierColumns = map[string]bool{"identifier": true, "sku": true, "code": true}
+
+// parseCSVIdentifiers reads the identifier column of a CSV file, or its first column when the first
+// line has no identifier column header. Akeneo exports use ";", so it is used when the first line
+// contains one.
+func parseCSVIdentifiers(data []byte) ([]string, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	header, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Contains(header, []byte(";")) {
+		reader.Comma = ';'
+	}
+
+	var identifiers []string
+	column := 0
+	for line := 0; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if line == 0 {
+			found := false
+			for i, name := range record {
+				if identifierColumns[strings.ToLower(strings.TrimSpace(name))] {
+					column, found = i, true
+					break
+				}
+			}
+			if found {
+				continue
+			}
+		}
+		if column < len(record) {
+			identifiers = append(identifiers, record[column])
+		}
+	}
+
+	return identifiers, nil
+}
+
+// parseTextIdentifiers reads one identifier per line, skipping blank lines and # comments
+func parseTextIdentifiers(data []byte) []string {
+	var identifiers []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		identifiers = append(identifiers, line)
+	}
+	return identifiers
+}
+
+// unique trims the identifiers and removes the blank and repeated ones, keeping the first occurrence
+func unique(identifiers []string) []string {
+	seen := make(map[string]bool, len(identifiers))
+	result := make([]string, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		identifier = strings.TrimSpace(identifier)
+		if identifier == "" || seen[identifier] {
+			continue
+		}
+		seen[identifier] = true
+		result = append(result, identifier)
+	}
+	return result
+}
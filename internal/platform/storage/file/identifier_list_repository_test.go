@@ -0,0 +1,57 @@
+package file_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"akeneo-migrator/internal/platform/storage/file"
+)
+
+func TestIdentifierListRepository_ReadsEveryFormat(t *testing.T) {
+	dir := t.TempDir()
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"list.txt", "# hierarchies to migrate\nSKU-1\n\n  SKU-2  \nSKU-1\nMODEL-3\n"},
+		{"list.json", `["SKU-1", {"identifier": "SKU-2"}, "SKU-2", "MODEL-3"]`},
+		{"export.csv", "\xef\xbb\xbfenabled;sku;family\n1;SKU-1;shoes\n0;SKU-2;shoes\n1;MODEL-3;shoes\n"},
+		{"plain.CSV", "SKU-1,first\nSKU-2,second\nMODEL-3,third\n"},
+	}
+
+	repo := file.NewIdentifierListRepository()
+	for _, f := range files {
+		path := filepath.Join(dir, f.name)
+		if err := os.WriteFile(path, []byte(f.content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		identifiers, err := repo.Read(context.Background(), path)
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", f.name, err)
+			continue
+		}
+		if got := strings.Join(identifiers, ","); got != "SKU-1,SKU-2,MODEL-3" {
+			t.Errorf("%s: expected SKU-1,SKU-2,MODEL-3, got %s", f.name, got)
+		}
+	}
+}
+
+func TestIdentifierListRepository_Errors(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "list.json")
+	if err := os.WriteFile(invalid, []byte(`[{"code": "SKU-1"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := file.NewIdentifierListRepository()
+	if _, err := repo.Read(context.Background(), invalid); err == nil {
+		t.Error("Expected an error for objects without identifier")
+	}
+	if _, err := repo.Read(context.Background(), filepath.Join(dir, "missing.txt")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
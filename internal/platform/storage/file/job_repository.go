@@ -0,0 +1,93 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"akeneo-migrator/internal/job"
+)
+
+// JobRepository implements job.Repository with one JSON file per job in a directory
+type JobRepository struct {
+	dir string
+}
+
+// NewJobRepository creates a new job repository storing its files in dir
+func NewJobRepository(dir string) job.Repository {
+	return &JobRepository{
+		dir: dir,
+	}
+}
+
+// Save creates or updates a job
+func (r *JobRepository) Save(ctx context.Context, j job.Job) error {
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return fmt.Errorf("error creating job directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding job %s: %w", j.ID, err)
+	}
+
+	if err := os.WriteFile(r.path(j.ID), data, 0o644); err != nil {
+		return fmt.Errorf("error writing job %s: %w", j.ID, err)
+	}
+
+	return nil
+}
+
+// FindByID retrieves a job by its ID
+func (r *JobRepository) FindByID(ctx context.Context, id string) (job.Job, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return job.Job{}, job.ErrNotFound
+	}
+
+	data, err := os.ReadFile(r.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return job.Job{}, job.ErrNotFound
+	}
+	if err != nil {
+		return job.Job{}, fmt.Errorf("error reading job %s: %w", id, err)
+	}
+
+	var j job.Job
+	if err := json.Unmarshal(data, &j); err != nil {
+		return job.Job{}, fmt.Errorf("error decoding job %s: %w", id, err)
+	}
+
+	return j, nil
+}
+
+// FindAll retrieves all jobs, oldest first
+func (r *JobRepository) FindAll(ctx context.Context) ([]job.Job, error) {
+	files, err := filepath.Glob(filepath.Join(r.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("error listing jobs: %w", err)
+	}
+
+	jobs := make([]job.Job, 0, len(files))
+	for _, file := range files {
+		j, err := r.FindByID(ctx, strings.TrimSuffix(filepath.Base(file), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+
+	sort.SliceStable(jobs, func(i, k int) bool {
+		return jobs[i].CreatedAt.Before(jobs[k].CreatedAt)
+	})
+
+	return jobs, nil
+}
+
+func (r *JobRepository) path(id string) string {
+	return filepath.Join(r.dir, id+".json")
+}
@@ -0,0 +1,59 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"akeneo-migrator/internal/job"
+)
+
+// ManifestRepository implements job.ManifestRepository with the same JSON format as the job files
+type ManifestRepository struct{}
+
+// NewManifestRepository creates a new manifest repository
+func NewManifestRepository() job.ManifestRepository {
+	return &ManifestRepository{}
+}
+
+// Write saves a job to path, creating its directory when needed
+func (r *ManifestRepository) Write(ctx context.Context, path string, j job.Job) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error creating manifest directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding manifest %s: %w", path, err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("error writing manifest %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Read retrieves the job saved in path
+func (r *ManifestRepository) Read(ctx context.Context, path string) (job.Job, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return job.Job{}, job.ErrNotFound
+	}
+	if err != nil {
+		return job.Job{}, fmt.Errorf("error reading manifest %s: %w", path, err)
+	}
+
+	var j job.Job
+	if err := json.Unmarshal(data, &j); err != nil {
+		return job.Job{}, fmt.Errorf("error decoding manifest %s: %w", path, err)
+	}
+	if j.ID == "" {
+		return job.Job{}, fmt.Errorf("manifest %s has no job ID", path)
+	}
+
+	return j, nil
+}
@@ -0,0 +1,58 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"akeneo-migrator/internal/plan"
+)
+
+// PlanRepository implements plan.Repository with one JSON file per plan
+type PlanRepository struct{}
+
+// NewPlanRepository creates a new plan repository
+func NewPlanRepository() plan.Repository {
+	return &PlanRepository{}
+}
+
+// Save writes a plan to path, creating its directory when needed
+func (r *PlanRepository) Save(ctx context.Context, path string, p plan.Plan) error {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("error creating plan directory: %w", err)
+		}
+	}
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding plan %s: %w", p.ID, err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("error writing plan %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Find reads the plan stored in path
+func (r *PlanRepository) Find(ctx context.Context, path string) (plan.Plan, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return plan.Plan{}, plan.ErrNotFound
+	}
+	if err != nil {
+		return plan.Plan{}, fmt.Errorf("error reading plan %s: %w", path, err)
+	}
+
+	var p plan.Plan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return plan.Plan{}, fmt.Errorf("error decoding plan %s: %w", path, err)
+	}
+
+	return p, nil
+}
@@ -0,0 +1,100 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"akeneo-migrator/internal/product"
+)
+
+// lastRun is the record of the last successful run of a scope
+type lastRun struct {
+	Until       time.Time `json:"until"`
+	CompletedAt time.Time `json:"completedAt"`
+}
+
+// RunRepository implements product.RunRepository with a JSON file holding every scope
+type RunRepository struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewRunRepository creates a new run repository storing the last runs in path
+func NewRunRepository(path string) product.RunRepository {
+	return &RunRepository{
+		path: path,
+	}
+}
+
+// FindLastRun returns the end of the window of the last successful run of a scope
+func (r *RunRepository) FindLastRun(ctx context.Context, scope string) (time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	runs, err := r.read()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	run, exists := runs[scope]
+	if !exists {
+		return time.Time{}, product.ErrNoLastRun
+	}
+	return run.Until, nil
+}
+
+// SaveLastRun records the end of the window of a successful run of a scope, keeping the other scopes.
+// The file is replaced atomically, so an interrupted write never loses the previous runs.
+func (r *RunRepository) SaveLastRun(ctx context.Context, scope string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	runs, err := r.read()
+	if err != nil {
+		return err
+	}
+	runs[scope] = lastRun{Until: until.UTC(), CompletedAt: time.Now().UTC()}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("error creating runs directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(runs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding runs: %w", err)
+	}
+
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("error writing runs %s: %w", r.path, err)
+	}
+	if err := os.Rename(tmp, r.path); err != nil {
+		return fmt.Errorf("error writing runs %s: %w", r.path, err)
+	}
+
+	return nil
+}
+
+// read returns the runs stored in the file, none when it does not exist yet
+func (r *RunRepository) read() (map[string]lastRun, error) {
+	runs := make(map[string]lastRun)
+
+	data, err := os.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return runs, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading runs %s: %w", r.path, err)
+	}
+
+	if err := json.Unmarshal(data, &runs); err != nil {
+		return nil, fmt.Errorf("error decoding runs %s: %w", r.path, err)
+	}
+	return runs, nil
+}
@@ -81,6 +81,44 @@ func (s *Server) handleGetCommands(w http.ResponseWriter, r *http.Request) {
 				{"name": "debug", "type": "checkbox", "label": "Debug mode"},
 			},
 		},
+		{
+			"id":          "sync-reference-entity-record",
+			"name":        "Sync Reference Entity Record",
+			"description": "Synchronize a single record of a Reference Entity with its media files",
+			"command":     "sync-reference-entity-record",
+			"args": []map[string]interface{}{
+				{"name": "entity", "type": "text", "placeholder": "brands", "required": true},
+				{"name": "code", "type": "text", "placeholder": "acme", "required": true},
+			},
+			"flags": []map[string]interface{}{
+				{"name": "debug", "type": "checkbox", "label": "Debug mode"},
+			},
+		},
+		{
+			"id":          "sync-asset-family",
+			"name":        "Sync Asset Family",
+			"description": "Synchronize an asset family with its attributes and assets",
+			"command":     "sync-asset-family",
+			"args": []map[string]interface{}{
+				{"name": "family-code", "type": "text", "placeholder": "packshots", "required": true},
+			},
+			"flags": []map[string]interface{}{
+				{"name": "debug", "type": "checkbox", "label": "Debug mode"},
+			},
+		},
+		{
+			"id":          "sync-asset",
+			"name":        "Sync Asset",
+			"description": "Synchronize a single asset with its media files",
+			"command":     "sync-asset",
+			"args": []map[string]interface{}{
+				{"name": "family-code", "type": "text", "placeholder": "packshots", "required": true},
+				{"name": "code", "type": "text", "placeholder": "shoe_front", "required": true},
+			},
+			"flags": []map[string]interface{}{
+				{"name": "debug", "type": "checkbox", "label": "Debug mode"},
+			},
+		},
 		{
 			"id":          "sync-product",
 			"name":        "Sync Product Hierarchy",
@@ -90,6 +128,21 @@ func (s *Server) handleGetCommands(w http.ResponseWriter, r *http.Request) {
 				{"name": "identifier", "type": "text", "placeholder": "COMMON-001", "required": true},
 			},
 			"flags": []map[string]interface{}{
+				{"name": "values-only", "type": "checkbox", "label": "Values only (existing items)"},
+				{"name": "debug", "type": "checkbox", "label": "Debug mode"},
+			},
+		},
+		{
+			"id":          "sync-product-model",
+			"name":        "Sync Product Model",
+			"description": "Synchronize a single product model without its siblings or variants",
+			"command":     "sync-product-model",
+			"args": []map[string]interface{}{
+				{"name": "code", "type": "text", "placeholder": "MODEL-001", "required": true},
+			},
+			"flags": []map[string]interface{}{
+				{"name": "with-parents", "type": "checkbox", "label": "With parent models"},
+				{"name": "values-only", "type": "checkbox", "label": "Values only (existing items)"},
 				{"name": "debug", "type": "checkbox", "label": "Debug mode"},
 			},
 		},
@@ -101,6 +154,52 @@ func (s *Server) handleGetCommands(w http.ResponseWriter, r *http.Request) {
 			"args": []map[string]interface{}{
 				{"name": "code", "type": "text", "placeholder": "sku", "required": true},
 			},
+			"flags": []map[string]interface{}{
+				{"name": "with-group", "type": "checkbox", "label": "Sync its attribute group first"},
+				{"name": "debug", "type": "checkbox", "label": "Debug mode"},
+			},
+		},
+		{
+			"id":          "sync-all-attributes",
+			"name":        "Sync All Attributes",
+			"description": "Synchronize every attribute of the source with its options and group",
+			"command":     "sync-all-attributes",
+			"args":        []map[string]interface{}{},
+			"flags": []map[string]interface{}{
+				{"name": "debug", "type": "checkbox", "label": "Debug mode"},
+			},
+		},
+		{
+			"id":          "sync-attribute-group",
+			"name":        "Sync Attribute Group",
+			"description": "Synchronize a single attribute group",
+			"command":     "sync-attribute-group",
+			"args": []map[string]interface{}{
+				{"name": "code", "type": "text", "placeholder": "marketing", "required": true},
+			},
+			"flags": []map[string]interface{}{
+				{"name": "with-attributes", "type": "checkbox", "label": "With attributes (already in destination)"},
+				{"name": "debug", "type": "checkbox", "label": "Debug mode"},
+			},
+		},
+		{
+			"id":          "sync-association-type",
+			"name":        "Sync Association Type",
+			"description": "Synchronize an association type by its code",
+			"command":     "sync-association-type",
+			"args": []map[string]interface{}{
+				{"name": "code", "type": "text", "placeholder": "X_SELL", "required": true},
+			},
+			"flags": []map[string]interface{}{
+				{"name": "debug", "type": "checkbox", "label": "Debug mode"},
+			},
+		},
+		{
+			"id":          "sync-all-association-types",
+			"name":        "Sync All Association Types",
+			"description": "Synchronize every association type of the source",
+			"command":     "sync-all-association-types",
+			"args":        []map[string]interface{}{},
 			"flags": []map[string]interface{}{
 				{"name": "debug", "type": "checkbox", "label": "Debug mode"},
 			},
@@ -117,6 +216,18 @@ func (s *Server) handleGetCommands(w http.ResponseWriter, r *http.Request) {
 				{"name": "debug", "type": "checkbox", "label": "Debug mode"},
 			},
 		},
+		{
+			"id":          "sync-category-tree",
+			"name":        "Sync Category Tree",
+			"description": "Synchronize a category and all its descendants, parents first",
+			"command":     "sync-category-tree",
+			"args": []map[string]interface{}{
+				{"name": "root", "type": "text", "placeholder": "master", "required": true},
+			},
+			"flags": []map[string]interface{}{
+				{"name": "debug", "type": "checkbox", "label": "Debug mode"},
+			},
+		},
 		{
 			"id":          "sync-family",
 			"name":        "Sync Family",
@@ -126,6 +237,73 @@ func (s *Server) handleGetCommands(w http.ResponseWriter, r *http.Request) {
 				{"name": "code", "type": "text", "placeholder": "clothing", "required": true},
 			},
 			"flags": []map[string]interface{}{
+				{"name": "variants-only", "type": "checkbox", "label": "Variants only"},
+				{"name": "debug", "type": "checkbox", "label": "Debug mode"},
+			},
+		},
+		{
+			"id":          "sync-all-families",
+			"name":        "Sync All Families",
+			"description": "Synchronize every family of the source with its variants",
+			"command":     "sync-all-families",
+			"args":        []map[string]interface{}{},
+			"flags": []map[string]interface{}{
+				{"name": "debug", "type": "checkbox", "label": "Debug mode"},
+			},
+		},
+		{
+			"id":          "sync-channel",
+			"name":        "Sync Channel",
+			"description": "Synchronize a single channel, checking its locales and currencies",
+			"command":     "sync-channel",
+			"args": []map[string]interface{}{
+				{"name": "code", "type": "text", "placeholder": "ecommerce", "required": true},
+			},
+			"flags": []map[string]interface{}{
+				{"name": "auto-deps", "type": "checkbox", "label": "Sync missing category tree and activate locales"},
+			},
+		},
+		{
+			"id":          "sync-currencies",
+			"name":        "Sync Currencies",
+			"description": "Check that the currencies enabled in source are enabled in destination",
+			"command":     "sync-currencies",
+			"args":        []map[string]interface{}{},
+			"flags": []map[string]interface{}{
+				{"name": "debug", "type": "checkbox", "label": "Debug mode"},
+			},
+		},
+		{
+			"id":          "sync-measurement-families",
+			"name":        "Sync Measurement Families",
+			"description": "Create missing measurement families and units in destination",
+			"command":     "sync-measurement-families",
+			"args":        []map[string]interface{}{},
+			"flags": []map[string]interface{}{
+				{"name": "debug", "type": "checkbox", "label": "Debug mode"},
+			},
+		},
+		{
+			"id":          "sync-structure",
+			"name":        "Sync Structure",
+			"description": "Migrate the whole catalog structure in dependency order",
+			"command":     "sync-structure",
+			"args":        []map[string]interface{}{},
+			"flags": []map[string]interface{}{
+				{"name": "auto-deps", "type": "checkbox", "label": "Activate locales used by channels"},
+				{"name": "debug", "type": "checkbox", "label": "Debug mode"},
+			},
+		},
+		{
+			"id":          "migrate",
+			"name":        "Migrate Everything",
+			"description": "Migrate the structure, assets and products in dependency order, with checkpoints",
+			"command":     "migrate",
+			"args":        []map[string]interface{}{},
+			"flags": []map[string]interface{}{
+				{"name": "resume", "type": "checkbox", "label": "Resume the previous migration"},
+				{"name": "auto-deps", "type": "checkbox", "label": "Activate locales used by channels"},
+				{"name": "values-only", "type": "checkbox", "label": "Values only (existing items)"},
 				{"name": "debug", "type": "checkbox", "label": "Debug mode"},
 			},
 		},
@@ -137,10 +315,63 @@ func (s *Server) handleGetCommands(w http.ResponseWriter, r *http.Request) {
 			"args": []map[string]interface{}{
 				{"name": "date", "type": "datetime-local", "placeholder": "2024-01-01T00:00:00", "required": true},
 			},
+			"flags": []map[string]interface{}{
+				{"name": "values-only", "type": "checkbox", "label": "Values only (existing items)"},
+				{"name": "debug", "type": "checkbox", "label": "Debug mode"},
+			},
+		},
+		{
+			"id":          "sync-published-products",
+			"name":        "Sync Published Products",
+			"description": "Synchronize the working copies of published products and list the ones to publish in destination (Enterprise Edition)",
+			"command":     "sync-published-products",
+			"args":        []map[string]interface{}{},
+			"flags": []map[string]interface{}{
+				{"name": "values-only", "type": "checkbox", "label": "Values only (existing items)"},
+				{"name": "debug", "type": "checkbox", "label": "Debug mode"},
+			},
+		},
+		{
+			"id":          "retry-failed",
+			"name":        "Retry Failed Items",
+			"description": "Reprocess the items that failed during a previous sync (latest pending job by default)",
+			"command":     "retry-failed",
+			"args": []map[string]interface{}{
+				{"name": "job-id", "type": "text", "placeholder": "20240115-103000-a1b2", "required": false},
+			},
+			"flags": []map[string]interface{}{
+				{"name": "list", "type": "checkbox", "label": "List queued jobs"},
+			},
+		},
+		{
+			"id":          "verify",
+			"name":        "Verify",
+			"description": "Compare checksums between source and destination without writing (scope: entity, family or category)",
+			"command":     "verify",
+			"args": []map[string]interface{}{
+				{"name": "scope", "type": "text", "placeholder": "family", "required": true},
+				{"name": "code", "type": "text", "placeholder": "clothing", "required": true},
+			},
 			"flags": []map[string]interface{}{
 				{"name": "debug", "type": "checkbox", "label": "Debug mode"},
 			},
 		},
+		{
+			"id":          "list-reference-entities",
+			"name":        "List Reference Entities",
+			"description": "List the Reference Entities of source and destination with their number of labels",
+			"command":     "list-reference-entities",
+			"args":        []map[string]interface{}{},
+			"flags":       []map[string]interface{}{},
+		},
+		{
+			"id":          "stats",
+			"name":        "Compare Counts",
+			"description": "Count the products, models, families, attributes, categories and records of both instances side by side",
+			"command":     "stats",
+			"args":        []map[string]interface{}{},
+			"flags":       []map[string]interface{}{},
+		},
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -53,6 +53,8 @@ function handleWebSocketMessage(message) {
             updateExecuteButton();
             if (message.exitCode === 0) {
                 addOutput('\n✅ Command completed successfully', 'success');
+            } else if (message.exitCode === 2) {
+                addOutput('\n⚠️ Command completed with failed items', 'error');
             } else {
                 addOutput(`\n❌ Command failed with exit code ${message.exitCode}`, 'error');
             }
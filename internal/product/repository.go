@@ -1,6 +1,16 @@
 package product
 
-import "context"
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when a product or product model does not exist in the instance
+var ErrNotFound = errors.New("product not found")
+
+// ErrNoLastRun is returned when an incremental sync scope never completed successfully
+var ErrNoLastRun = errors.New("no successful run recorded")
 
 // Product represents a product
 type Product map[string]interface{}
@@ -8,6 +18,23 @@ type Product map[string]interface{}
 // ProductModel represents a product model
 type ProductModel map[string]interface{}
 
+// MediaFile represents a file referenced by an image or file value
+type MediaFile struct {
+	Code     string
+	Filename string
+	Content  []byte
+}
+
+// MediaTarget is the value of a product or product model a media file is uploaded to
+type MediaTarget struct {
+	// Identifier is the product receiving the file; ModelCode is used when Identifier is empty
+	Identifier string
+	ModelCode  string
+	Attribute  string
+	Locale     string
+	Scope      string
+}
+
 // SourceRepository defines read-only operations for the source
 type SourceRepository interface {
 	// FindByIdentifier retrieves a product by its identifier
@@ -29,12 +56,26 @@ type SourceRepository interface {
 	FindModelsUpdatedSince(ctx context.Context, updatedSince string) ([]ProductModel, error)
 
 	// StreamProductsUpdatedSince processes products updated since a specific date in batches
-	// The callback is called for each batch of products
-	StreamProductsUpdatedSince(ctx context.Context, updatedSince string, batchSize int, callback func([]Product) error) error
+	// An empty updatedUntil leaves the window open. The callback is called for each batch of products
+	StreamProductsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]Product) error) error
 
 	// StreamModelsUpdatedSince processes product models updated since a specific date in batches
-	// The callback is called for each batch of models
-	StreamModelsUpdatedSince(ctx context.Context, updatedSince string, batchSize int, callback func([]ProductModel) error) error
+	// An empty updatedUntil leaves the window open. The callback is called for each batch of models
+	StreamModelsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]ProductModel) error) error
+
+	// StreamProductsBySearch processes the products matching a product query search filter in batches.
+	// The filter is the JSON search syntax of the Akeneo API. The callback is called for each batch of products
+	StreamProductsBySearch(ctx context.Context, search string, batchSize int, callback func([]Product) error) error
+
+	// CountUpdatedSince returns the number of products and product models updated since a specific date.
+	// An empty updatedUntil leaves the window open
+	CountUpdatedSince(ctx context.Context, updatedSince, updatedUntil string) (int, error)
+
+	// CountProductsBySearch returns the number of products matching a product query search filter
+	CountProductsBySearch(ctx context.Context, search string) (int, error)
+
+	// DownloadMediaFile retrieves the content of a media file
+	DownloadMediaFile(ctx context.Context, code string) (MediaFile, error)
 }
 
 // DestRepository defines read and write operations for the destination
@@ -51,9 +92,97 @@ type DestRepository interface {
 	// SaveModel creates or updates a product model
 	SaveModel(ctx context.Context, code string, model ProductModel) error
 
+	// SaveAll creates or updates several products in batches.
+	// It returns the errors of the products that were not written, indexed by identifier
+	SaveAll(ctx context.Context, products []Product) (map[string]error, error)
+
+	// SaveModels creates or updates several product models in batches.
+	// It returns the errors of the models that were not written, indexed by code
+	SaveModels(ctx context.Context, models []ProductModel) (map[string]error, error)
+
 	// FindProductsByParent retrieves all products with a specific parent
 	FindProductsByParent(ctx context.Context, parentCode string) ([]Product, error)
 
 	// FindModelsByParent retrieves all product models with a specific parent
 	FindModelsByParent(ctx context.Context, parentCode string) ([]ProductModel, error)
+
+	// UploadMediaFile stores a media file as the value of an existing product or product model
+	// and returns the code assigned to it
+	UploadMediaFile(ctx context.Context, file MediaFile, target MediaTarget) (string, error)
+}
+
+// UUIDRepository writes products through the UUID endpoints of destinations that key products by UUID
+// (Akeneo 7 and SaaS)
+type UUIDRepository interface {
+	// FindUUIDs returns the UUIDs of the products with the given identifiers, indexed by identifier.
+	// Identifiers of products that do not exist are left out
+	FindUUIDs(ctx context.Context, identifiers []string) (map[string]string, error)
+
+	// SaveByUUID creates or updates a product by its UUID
+	SaveByUUID(ctx context.Context, uuid string, product Product) error
+
+	// SaveAllByUUID creates or updates several products, each carrying its "uuid", in batches.
+	// It returns the errors of the products that were not written, indexed by UUID
+	SaveAllByUUID(ctx context.Context, products []Product) (map[string]error, error)
+}
+
+// PublishedRepository reads the published products of an Enterprise Edition instance.
+// The API cannot publish products, so there is no write operation
+type PublishedRepository interface {
+	// StreamPublished processes the published products in batches
+	StreamPublished(ctx context.Context, batchSize int, callback func([]Product) error) error
+
+	// FindPublished retrieves the published version of a product
+	FindPublished(ctx context.Context, identifier string) (Product, error)
+}
+
+// IdentifierListRepository reads lists of product identifiers prepared outside of Akeneo
+type IdentifierListRepository interface {
+	// Read returns the identifiers listed in a file, in order and without duplicates
+	Read(ctx context.Context, path string) ([]string, error)
+}
+
+// AttributeDefinition is the part of an attribute deciding the shape of its values
+type AttributeDefinition struct {
+	Type        string
+	Localizable bool
+	Scopable    bool
+}
+
+// StructureRepository reads the catalog structure products and product models rely on, to check
+// that it exists in destination before they are written
+type StructureRepository interface {
+	// FindFamilyCodes retrieves the codes of every family
+	FindFamilyCodes(ctx context.Context) (map[string]bool, error)
+
+	// FindFamilyVariantCodes retrieves the codes of the variants of a family
+	FindFamilyVariantCodes(ctx context.Context, familyCode string) (map[string]bool, error)
+
+	// FindAttributeTypes retrieves the type of every attribute, indexed by code
+	FindAttributeTypes(ctx context.Context) (map[string]string, error)
+
+	// FindAttributeDefinitions retrieves the type and the localizable and scopable flags of every
+	// attribute, indexed by code
+	FindAttributeDefinitions(ctx context.Context) (map[string]AttributeDefinition, error)
+
+	// FindOptionCodes retrieves the codes of the options of a select attribute
+	FindOptionCodes(ctx context.Context, attributeCode string) (map[string]bool, error)
+
+	// FindFamilyAttributes retrieves the codes of the attributes of a family
+	FindFamilyAttributes(ctx context.Context, familyCode string) ([]string, error)
+
+	// FindCategoryParent retrieves the parent of a category, empty for a root, and whether the
+	// category exists
+	FindCategoryParent(ctx context.Context, code string) (string, bool, error)
+}
+
+// RunRepository keeps the end of the window of the last successful run of each incremental sync scope,
+// so the next run starts where it finished
+type RunRepository interface {
+	// FindLastRun returns the end of the window of the last successful run of a scope,
+	// ErrNoLastRun when there is none
+	FindLastRun(ctx context.Context, scope string) (time.Time, error)
+
+	// SaveLastRun records the end of the window of a successful run of a scope
+	SaveLastRun(ctx context.Context, scope string, until time.Time) error
 }
@@ -0,0 +1,177 @@
+package syncing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"akeneo-migrator/kit/logger"
+)
+
+// DefaultAssociationDepth is the number of levels of association targets synced through the targets
+// synced before them, when WithAssociationTargets is given no depth
+const DefaultAssociationDepth = 2
+
+// ParseAssociationPolicy validates a missing association target policy. An empty policy, the
+// default, and "fail" send the links as they are and let destination reject the item.
+func ParseAssociationPolicy(name string) (TargetPolicy, error) {
+	switch TargetPolicy(name) {
+	case "", "fail":
+		return "", nil
+	case TargetDrop, TargetSync:
+		return TargetPolicy(name), nil
+	default:
+		return "", fmt.Errorf("unknown missing association policy '%s' (expected fail, drop or sync)", name)
+	}
+}
+
+// WithAssociationTargets checks that the products and models linked by the associations of an item exist
+// in destination before it is written. Links to missing targets are dropped and reported. With the sync
+// policy, the hierarchies of missing targets are synced first, and so are the targets they link to, up to
+// depth levels away from the item being synced; deeper targets are dropped.
+func WithAssociationTargets(policy TargetPolicy, depth int) Option {
+	return func(s *Service) {
+		if depth <= 0 {
+			depth = DefaultAssociationDepth
+		}
+		s.associationPolicy = policy
+		s.associationDepth = depth
+	}
+}
+
+// MissingTarget is a product or product model linked by an association that does not exist in destination
+type MissingTarget struct {
+	// Item is the product or model linking to the target, e.g. "product SKU-1"
+	Item        string
+	Association string
+	Kind        string
+	Code        string
+}
+
+func (t MissingTarget) String() string {
+	return fmt.Sprintf("%s → %s %s (%s)", t.Item, t.Kind, t.Code, t.Association)
+}
+
+// linkDepthKey holds the number of association targets synced through each other to reach the current sync
+type linkDepthKey struct{}
+
+// withLinkDepth returns a context recording the depth of the association target being synced
+func withLinkDepth(ctx context.Context, depth int) context.Context {
+	return context.WithValue(ctx, linkDepthKey{}, depth)
+}
+
+// linkDepth returns the depth of the association target being synced, 0 outside of such a sync
+func linkDepth(ctx context.Context) int {
+	depth, _ := ctx.Value(linkDepthKey{}).(int)
+	return depth
+}
+
+// checkAssociationTargets returns a copy of an item without the association links whose target does not
+// exist in destination, adding the dropped links to the result. With the sync policy, missing targets
+// are synced first while the depth allows it, and only the ones that could not be synced are removed.
+func (s *Service) checkAssociationTargets(ctx context.Context, name string, item map[string]interface{}, result *SyncResult, opts SyncOptions) (map[string]interface{}, error) {
+	associations, ok := item["associations"].(map[string]interface{})
+	if s.associationPolicy == "" || !ok || len(associations) == 0 {
+		return item, nil
+	}
+
+	sync := s.associationPolicy == TargetSync && linkDepth(ctx) < s.associationDepth
+
+	missing := make(map[linkTarget]bool)
+	for _, target := range associationTargets(associations) {
+		found, err := s.ensureTarget(ctx, target, sync, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error checking association targets of %s: %w", name, err)
+		}
+		if !found {
+			missing[target] = true
+		}
+	}
+	if len(missing) == 0 {
+		return item, nil
+	}
+
+	types := make([]string, 0, len(associations))
+	for associationType := range associations {
+		types = append(types, associationType)
+	}
+	sort.Strings(types)
+
+	dropped := make([]string, 0, len(missing))
+	for target := range missing {
+		dropped = append(dropped, target.String())
+	}
+	sort.Strings(dropped)
+	s.logger.Warn("   ⚠️  Dropped associations to items missing in destination", logger.F("item", name), logger.F("targets", strings.Join(dropped, ",")))
+
+	kept := make(map[string]interface{}, len(associations))
+	for _, associationType := range types {
+		linksByKind, ok := associations[associationType].(map[string]interface{})
+		if !ok {
+			kept[associationType] = associations[associationType]
+			continue
+		}
+
+		fields := make([]string, 0, len(linksByKind))
+		for field := range linksByKind {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		links := make(map[string]interface{}, len(linksByKind))
+		for _, field := range fields {
+			list := linksByKind[field]
+			kind, linked := linkKinds[field]
+			codes, ok := list.([]interface{})
+			if !linked || !ok {
+				links[field] = list
+				continue
+			}
+
+			remaining := make([]interface{}, 0, len(codes))
+			for _, raw := range codes {
+				code, _ := raw.(string)
+				if missing[linkTarget{Kind: kind, Code: code}] {
+					result.MissingTargets = append(result.MissingTargets, MissingTarget{Item: name, Association: associationType, Kind: kind, Code: code})
+					continue
+				}
+				remaining = append(remaining, raw)
+			}
+			links[field] = remaining
+		}
+		kept[associationType] = links
+	}
+
+	copied := make(map[string]interface{}, len(item))
+	for key, value := range item {
+		copied[key] = value
+	}
+	copied["associations"] = kept
+
+	return copied, nil
+}
+
+// associationTargets returns the distinct products and models linked by associations, sorted
+func associationTargets(associations map[string]interface{}) []linkTarget {
+	seen := make(map[linkTarget]bool)
+	var targets []linkTarget
+	for _, links := range associations {
+		linksByKind, _ := links.(map[string]interface{})
+		for field, kind := range linkKinds {
+			codes, _ := linksByKind[field].([]interface{})
+			for _, raw := range codes {
+				code, _ := raw.(string)
+				target := linkTarget{Kind: kind, Code: code}
+				if code == "" || seen[target] {
+					continue
+				}
+				seen[target] = true
+				targets = append(targets, target)
+			}
+		}
+	}
+
+	sortTargets(targets)
+	return targets
+}
@@ -1,12 +1,19 @@
 package syncing
 
-import "akeneo-migrator/kit/bus"
+import (
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/conflict"
+	"akeneo-migrator/kit/retry"
+)
 
 const SyncProductCommandType bus.Type = "product.sync"
 
 // SyncProductCommand represents a command to sync a product hierarchy
 type SyncProductCommand struct {
 	Identifier string
+	ValuesOnly bool
+	// OnConflict overrides the strategy applied to items edited in destination since their last sync
+	OnConflict conflict.Strategy
 	Debug      bool
 }
 
@@ -14,3 +21,8 @@ type SyncProductCommand struct {
 func (c SyncProductCommand) Type() bus.Type {
 	return SyncProductCommandType
 }
+
+// RetryItem returns the item targeted by the command
+func (c SyncProductCommand) RetryItem() retry.Failure {
+	return retry.Failure{Kind: KindProduct, Code: c.Identifier}
+}
@@ -25,7 +25,7 @@ func (h *CommandHandler) Handle(ctx context.Context, msg bus.Message) (bus.Respo
 		return bus.Response{}, nil
 	}
 
-	result, err := h.service.Sync(ctx, cmd.Identifier)
+	result, err := h.service.Sync(ctx, cmd.Identifier, SyncOptions{ValuesOnly: cmd.ValuesOnly, Conflicts: cmd.OnConflict})
 	if err != nil {
 		return bus.Response{Error: err}, err
 	}
@@ -0,0 +1,100 @@
+package syncing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"akeneo-migrator/internal/product"
+
+	"akeneo-migrator/kit/conflict"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/logger"
+)
+
+// WithConflictDetection compares products and models to their last sync before writing them. Items edited
+// in destination since then are resolved with the strategy of the run, or strategy when the run sets none.
+func WithConflictDetection(detector *conflict.Detector, strategy conflict.Strategy) Option {
+	return func(s *Service) {
+		s.conflicts = detector
+		s.conflictStrategy = strategy
+	}
+}
+
+// strategyOf returns the conflict strategy of a run, None when conflicts are not detected
+func (s *Service) strategyOf(opts SyncOptions) conflict.Strategy {
+	if s.conflicts == nil {
+		return conflict.None
+	}
+	if opts.Conflicts != conflict.None {
+		return opts.Conflicts
+	}
+	return s.conflictStrategy
+}
+
+// resolveConflict checks whether an item was edited in destination since its last sync and reports whether
+// the source item must be written. Conflicts are added to the result; the Abort strategy returns an error
+// wrapping conflict.ErrConflict.
+func (s *Service) resolveConflict(ctx context.Context, kind, code string, opts SyncOptions, result *SyncResult) (bool, error) {
+	strategy := s.strategyOf(opts)
+	if strategy == conflict.None {
+		return true, nil
+	}
+
+	updated, err := s.destUpdated(ctx, kind, code)
+	if err != nil {
+		return false, err
+	}
+
+	c, write, err := s.conflicts.Check(ctx, strategy, kind, code, updated)
+	if c == nil || err != nil {
+		return write, err
+	}
+
+	result.Conflicts = append(result.Conflicts, *c)
+	if c.Overwritten {
+		s.logger.Warn("   ⚠️  Overwriting item edited in destination since the last sync", logger.F("kind", kind), logger.F("code", code))
+	} else {
+		s.logger.Warn("   ⏭️  Kept item edited in destination since the last sync", logger.F("kind", kind), logger.F("code", code))
+	}
+
+	return write, nil
+}
+
+// recordSynced saves the destination update date of an item that was just written, so the next
+// sync can tell whether it was edited in between. Dry runs write nothing and record nothing.
+func (s *Service) recordSynced(ctx context.Context, kind, code string, opts SyncOptions) {
+	if s.strategyOf(opts) == conflict.None || dryrun.Enabled(ctx) {
+		return
+	}
+
+	updated, err := s.destUpdated(ctx, kind, code)
+	if err != nil {
+		s.logger.Warn("   ⚠️  Could not record the sync", logger.F("kind", kind), logger.F("code", code), logger.Err(err))
+		return
+	}
+
+	if err := s.conflicts.Record(ctx, kind, code, updated); err != nil {
+		s.logger.Warn("   ⚠️  Could not record the sync", logger.F("kind", kind), logger.F("code", code), logger.Err(err))
+	}
+}
+
+// destUpdated returns the update date of an item in destination, empty when it does not exist
+func (s *Service) destUpdated(ctx context.Context, kind, code string) (string, error) {
+	var item map[string]interface{}
+	var err error
+	if kind == KindProductModel {
+		item, err = s.destRepo.FindModelByCode(ctx, code)
+	} else {
+		item, err = s.destRepo.FindByIdentifier(ctx, code)
+	}
+	if errors.Is(err, product.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error fetching %s %s from destination: %w", kind, code, err)
+	}
+
+	updated, _ := item["updated"].(string)
+	return updated, nil
+}
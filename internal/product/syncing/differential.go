@@ -0,0 +1,131 @@
+package syncing
+
+import "reflect"
+
+// WithChangedOnly sends only what differs from destination for the products and models that already
+// exist there: the values whose data changed, per attribute, locale and scope, and the other fields
+// whose content changed. Items without any change are not written at all, so destination records no
+// new version for them.
+func WithChangedOnly() Option {
+	return func(s *Service) {
+		s.changedOnly = true
+	}
+}
+
+// readOnlyFields are the fields Akeneo computes itself, which always differ between instances and
+// are never written
+var readOnlyFields = map[string]bool{
+	"_links":         true,
+	"uuid":           true,
+	"created":        true,
+	"updated":        true,
+	"metadata":       true,
+	"completenesses": true,
+	"quality_scores": true,
+}
+
+// changesOf returns the part of a payload that differs from the destination item. The key field
+// identifying the item is always kept, read-only fields are left out.
+func changesOf(payload, dest map[string]interface{}, key string) map[string]interface{} {
+	changes := map[string]interface{}{key: payload[key]}
+	for field, value := range payload {
+		switch {
+		case field == key || readOnlyFields[field]:
+		case field == "values":
+			if values := changedValues(value, dest[field]); len(values) > 0 {
+				changes[field] = values
+			}
+		default:
+			if !sameContent(value, dest[field]) {
+				changes[field] = value
+			}
+		}
+	}
+	return changes
+}
+
+// unchanged tells whether a payload built with changed-only updates has nothing left to write
+func (s *Service) unchanged(payload map[string]interface{}, pending []pendingMedia) bool {
+	return s.changedOnly && len(payload) == 1 && len(pending) == 0
+}
+
+// changedValues returns the values of a payload whose data differs from the value of destination with
+// the same locale and scope, or that destination does not have
+func changedValues(source, dest interface{}) map[string]interface{} {
+	sourceValues, _ := source.(map[string]interface{})
+	destValues, _ := dest.(map[string]interface{})
+
+	changed := make(map[string]interface{})
+	for attribute, raw := range sourceValues {
+		entries, _ := raw.([]interface{})
+		destEntries, _ := destValues[attribute].([]interface{})
+
+		var kept []interface{}
+		for _, entry := range entries {
+			if !hasSameValue(entry, destEntries) {
+				kept = append(kept, entry)
+			}
+		}
+		if len(kept) > 0 {
+			changed[attribute] = kept
+		}
+	}
+	return changed
+}
+
+// hasSameValue tells whether destination has a value with the locale, scope and data of a source value
+func hasSameValue(entry interface{}, destEntries []interface{}) bool {
+	value, _ := entry.(map[string]interface{})
+	for _, raw := range destEntries {
+		destValue, _ := raw.(map[string]interface{})
+		if destValue["locale"] != value["locale"] || destValue["scope"] != value["scope"] {
+			continue
+		}
+		return sameContent(value["data"], destValue["data"])
+	}
+	return false
+}
+
+// sameContent compares two JSON values, lists being compared regardless of their order since Akeneo
+// does not keep the order of categories, options, prices or associations
+func sameContent(a, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+
+	if mapA, ok := a.(map[string]interface{}); ok {
+		mapB, ok := b.(map[string]interface{})
+		if !ok || len(mapA) != len(mapB) {
+			return false
+		}
+		for key, valueA := range mapA {
+			valueB, exists := mapB[key]
+			if !exists || !sameContent(valueA, valueB) {
+				return false
+			}
+		}
+		return true
+	}
+
+	listA, okA := a.([]interface{})
+	listB, okB := b.([]interface{})
+	if !okA || !okB || len(listA) != len(listB) {
+		return false
+	}
+
+	matched := make([]bool, len(listB))
+	for _, itemA := range listA {
+		found := false
+		for i, itemB := range listB {
+			if !matched[i] && sameContent(itemA, itemB) {
+				matched[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
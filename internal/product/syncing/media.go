@@ -0,0 +1,188 @@
+package syncing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"akeneo-migrator/internal/product"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/logger"
+)
+
+// Defaults of the media transfers, which are the calls most exposed to timeouts and dropped connections
+const (
+	// DefaultMediaRetries is the number of times a media file failing to download or upload is tried again
+	DefaultMediaRetries = 2
+	// DefaultMediaRetryDelay is the wait before the first retry, growing with each one
+	DefaultMediaRetryDelay = time.Second
+)
+
+// pendingMedia is a media value left out of a payload because its file does not exist yet in destination
+type pendingMedia struct {
+	Attribute string
+	Locale    string
+	Scope     string
+	FileCode  string
+}
+
+// mediaCache remembers the media files already copied, indexed by source file code
+type mediaCache struct {
+	mu    sync.Mutex
+	codes map[string]string
+}
+
+// get returns the destination code of a source media file
+func (c *mediaCache) get(sourceCode string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	destCode, ok := c.codes[sourceCode]
+	return destCode, ok
+}
+
+// set records the destination code of a source media file
+func (c *mediaCache) set(sourceCode, destCode string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.codes == nil {
+		c.codes = make(map[string]string)
+	}
+	c.codes[sourceCode] = destCode
+}
+
+// extractMedia returns a copy of an item where image and file values reference the destination file
+// when it was already copied. The other media values are removed and returned as pending:
+// Akeneo only accepts a new file as the value of an existing product or model.
+// Without media, every media value is removed and the destination keeps its own files.
+func (s *Service) extractMedia(item map[string]interface{}) (map[string]interface{}, []pendingMedia) {
+	values, ok := item["values"].(map[string]interface{})
+	if !ok {
+		return item, nil
+	}
+
+	var pending []pendingMedia
+	rewritten := make(map[string]interface{}, len(values))
+	changed := false
+	for attribute, value := range values {
+		entries, ok := value.([]interface{})
+		if !ok {
+			rewritten[attribute] = value
+			continue
+		}
+
+		kept := make([]interface{}, 0, len(entries))
+		for _, raw := range entries {
+			entry, ok := raw.(map[string]interface{})
+			fileCode, _ := entry["data"].(string)
+			if !ok || fileCode == "" || !isMediaValue(entry) {
+				kept = append(kept, raw)
+				continue
+			}
+
+			changed = true
+			if s.skipMedia {
+				continue
+			}
+			if destCode, copied := s.mediaFiles.get(fileCode); copied {
+				kept = append(kept, map[string]interface{}{
+					"locale": entry["locale"],
+					"scope":  entry["scope"],
+					"data":   destCode,
+				})
+				continue
+			}
+
+			locale, _ := entry["locale"].(string)
+			scope, _ := entry["scope"].(string)
+			pending = append(pending, pendingMedia{Attribute: attribute, Locale: locale, Scope: scope, FileCode: fileCode})
+		}
+
+		if len(kept) > 0 {
+			rewritten[attribute] = kept
+		}
+	}
+
+	if !changed {
+		return item, nil
+	}
+
+	result := make(map[string]interface{}, len(item))
+	for key, value := range item {
+		result[key] = value
+	}
+	result["values"] = rewritten
+
+	return result, pending
+}
+
+// isMediaValue tells whether a value entry references a media file, which Akeneo exposes with a download link
+func isMediaValue(entry map[string]interface{}) bool {
+	links, _ := entry["_links"].(map[string]interface{})
+	download, _ := links["download"].(map[string]interface{})
+	_, ok := download["href"]
+	return ok
+}
+
+// copyMedia downloads the pending media files of a product or model from source
+// and uploads them as its values in destination. Dry runs only record the uploads.
+func (s *Service) copyMedia(ctx context.Context, target product.MediaTarget, pending []pendingMedia) error {
+	owner := target.Identifier
+	if owner == "" {
+		owner = target.ModelCode
+	}
+
+	for _, media := range pending {
+		if dryrun.Record(ctx, dryrun.Write{Kind: KindMediaFile, Scope: owner, Code: media.FileCode}) {
+			continue
+		}
+
+		target.Attribute, target.Locale, target.Scope = media.Attribute, media.Locale, media.Scope
+		destCode, err := s.transferMedia(ctx, media, target)
+		if err != nil {
+			return err
+		}
+
+		s.mediaFiles.set(media.FileCode, destCode)
+	}
+
+	return nil
+}
+
+// transferMedia copies a media file, trying again with a growing delay when the download or the
+// upload fails. Throttled calls are already replayed by the client.
+func (s *Service) transferMedia(ctx context.Context, media pendingMedia, target product.MediaTarget) (string, error) {
+	for attempt := 1; ; attempt++ {
+		destCode, err := s.transferMediaOnce(ctx, media, target)
+		if err == nil || attempt > s.mediaRetries || ctx.Err() != nil {
+			return destCode, err
+		}
+
+		s.logger.Warn("   ⏳ Retrying media file",
+			logger.F("file", media.FileCode),
+			logger.F("attempt", fmt.Sprintf("%d/%d", attempt, s.mediaRetries)),
+			logger.Err(err),
+		)
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(s.mediaRetryDelay * time.Duration(attempt)):
+		}
+	}
+}
+
+// transferMediaOnce downloads a media file from source and uploads it to destination
+func (s *Service) transferMediaOnce(ctx context.Context, media pendingMedia, target product.MediaTarget) (string, error) {
+	file, err := s.sourceRepo.DownloadMediaFile(ctx, media.FileCode)
+	if err != nil {
+		return "", fmt.Errorf("error downloading media file %s of attribute %s: %w", media.FileCode, media.Attribute, err)
+	}
+
+	destCode, err := s.destRepo.UploadMediaFile(ctx, file, target)
+	if err != nil {
+		return "", fmt.Errorf("error uploading media file %s of attribute %s: %w", media.FileCode, media.Attribute, err)
+	}
+	return destCode, nil
+}
@@ -0,0 +1,206 @@
+package syncing
+
+import (
+	"fmt"
+	"sort"
+)
+
+// FieldStrategy defines how a top-level product field is written when the item already exists in destination
+type FieldStrategy string
+
+const (
+	// FieldOverwrite makes the destination field identical to the source field
+	FieldOverwrite FieldStrategy = "overwrite"
+	// FieldMerge combines source and destination content
+	FieldMerge FieldStrategy = "merge"
+	// FieldKeep leaves the destination field untouched
+	FieldKeep FieldStrategy = "keep"
+)
+
+// MergeableFields are the top-level fields that accept a strategy
+var MergeableFields = []string{"values", "categories", "associations", "quantified_associations", "enabled"}
+
+// ParseFieldStrategies validates a field → strategy configuration
+func ParseFieldStrategies(config map[string]string) (map[string]FieldStrategy, error) {
+	allowed := make(map[string]bool, len(MergeableFields))
+	for _, field := range MergeableFields {
+		allowed[field] = true
+	}
+
+	strategies := make(map[string]FieldStrategy, len(config))
+	for field, name := range config {
+		if !allowed[field] {
+			return nil, fmt.Errorf("unknown product field '%s' (expected one of %v)", field, MergeableFields)
+		}
+
+		switch FieldStrategy(name) {
+		case FieldOverwrite, FieldMerge, FieldKeep:
+			strategies[field] = FieldStrategy(name)
+		default:
+			return nil, fmt.Errorf("invalid strategy '%s' for product field '%s' (expected overwrite, merge or keep)", name, field)
+		}
+	}
+
+	return strategies, nil
+}
+
+// applyFieldStrategies builds the payload for an item that already exists in destination.
+//
+//   - values: overwrite also clears destination values missing in source; merge relies on
+//     Akeneo merging values per attribute, locale and channel
+//   - categories: overwrite replaces the list; merge sends the union of both lists
+//   - associations: overwrite also empties association types missing in source; merge sends
+//     the union of both lists for every association type
+//   - quantified_associations: same as associations, links being identified by their target
+//     and keeping the source quantity
+//   - enabled: overwrite and merge both send the source flag
+func applyFieldStrategies(source, dest map[string]interface{}, strategies map[string]FieldStrategy) map[string]interface{} {
+	result := make(map[string]interface{}, len(source))
+	for key, value := range source {
+		result[key] = value
+	}
+
+	for field, strategy := range strategies {
+		if strategy == FieldKeep {
+			delete(result, field)
+			continue
+		}
+
+		if _, inSource := source[field]; !inSource {
+			continue
+		}
+
+		switch field {
+		case "values":
+			if strategy == FieldOverwrite {
+				result[field] = overwriteValues(source[field], dest[field])
+			}
+		case "categories":
+			if strategy == FieldMerge {
+				result[field] = unionList(source[field], dest[field])
+			}
+		case "associations":
+			result[field] = mergeAssociations(source[field], dest[field], strategy)
+		case "quantified_associations":
+			result[field] = mergeQuantifiedAssociations(source[field], dest[field], strategy)
+		}
+	}
+
+	return result
+}
+
+// overwriteValues adds null values for every destination value that does not exist in source
+func overwriteValues(source, dest interface{}) interface{} {
+	sourceValues, ok := source.(map[string]interface{})
+	if !ok {
+		return source
+	}
+
+	result := make(map[string]interface{}, len(sourceValues))
+	for attributeCode, entries := range sourceValues {
+		result[attributeCode] = entries
+	}
+
+	destValues, _ := dest.(map[string]interface{})
+	for attributeCode, destEntries := range destValues {
+		sourceKeys := make(map[string]bool)
+		sourceEntries, _ := sourceValues[attributeCode].([]interface{})
+		for _, entry := range sourceEntries {
+			if value, ok := entry.(map[string]interface{}); ok {
+				sourceKeys[valueKey(value)] = true
+			}
+		}
+
+		merged := append([]interface{}{}, sourceEntries...)
+		entries, _ := destEntries.([]interface{})
+		for _, entry := range entries {
+			value, ok := entry.(map[string]interface{})
+			if !ok || sourceKeys[valueKey(value)] {
+				continue
+			}
+			merged = append(merged, map[string]interface{}{
+				"locale": value["locale"],
+				"scope":  value["scope"],
+				"data":   nil,
+			})
+		}
+
+		if len(merged) > 0 {
+			result[attributeCode] = merged
+		}
+	}
+
+	return result
+}
+
+// mergeAssociations applies a strategy to the associations of an item
+func mergeAssociations(source, dest interface{}, strategy FieldStrategy) interface{} {
+	sourceAssociations, ok := source.(map[string]interface{})
+	if !ok {
+		return source
+	}
+	destAssociations, _ := dest.(map[string]interface{})
+
+	result := make(map[string]interface{}, len(sourceAssociations))
+	for associationType, links := range sourceAssociations {
+		result[associationType] = links
+	}
+
+	for associationType, destLinks := range destAssociations {
+		destLinksByKind, _ := destLinks.(map[string]interface{})
+		sourceLinksByKind, _ := sourceAssociations[associationType].(map[string]interface{})
+
+		links := make(map[string]interface{})
+		for kind, destList := range destLinksByKind {
+			switch strategy {
+			case FieldMerge:
+				links[kind] = unionList(sourceLinksByKind[kind], destList)
+			case FieldOverwrite:
+				if sourceList, exists := sourceLinksByKind[kind]; exists {
+					links[kind] = sourceList
+				} else {
+					links[kind] = []interface{}{}
+				}
+			}
+		}
+		for kind, sourceList := range sourceLinksByKind {
+			if _, exists := links[kind]; !exists {
+				links[kind] = sourceList
+			}
+		}
+
+		result[associationType] = links
+	}
+
+	return result
+}
+
+// unionList returns the sorted union of two lists of codes
+func unionList(source, dest interface{}) []interface{} {
+	seen := make(map[string]bool)
+	codes := []string{}
+
+	for _, list := range []interface{}{source, dest} {
+		items, _ := list.([]interface{})
+		for _, item := range items {
+			code, ok := item.(string)
+			if !ok || seen[code] {
+				continue
+			}
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+
+	sort.Strings(codes)
+	result := make([]interface{}, len(codes))
+	for i, code := range codes {
+		result[i] = code
+	}
+	return result
+}
+
+// valueKey identifies a value by its locale and channel
+func valueKey(value map[string]interface{}) string {
+	return fmt.Sprintf("%v|%v", value["locale"], value["scope"])
+}
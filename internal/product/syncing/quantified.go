@@ -0,0 +1,452 @@
+package syncing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"akeneo-migrator/internal/product"
+	"akeneo-migrator/kit/logger"
+)
+
+// TargetPolicy defines what happens to the quantified association targets missing in destination
+type TargetPolicy string
+
+const (
+	// TargetDrop removes the links to missing targets before the item is written
+	TargetDrop TargetPolicy = "drop"
+	// TargetSync syncs the hierarchy of missing targets before the item linking to them
+	TargetSync TargetPolicy = "sync"
+)
+
+// ParseTargetPolicy validates a missing target policy, "drop" being the default
+func ParseTargetPolicy(name string) (TargetPolicy, error) {
+	switch TargetPolicy(name) {
+	case "":
+		return TargetDrop, nil
+	case TargetDrop, TargetSync:
+		return TargetPolicy(name), nil
+	default:
+		return "", fmt.Errorf("unknown missing target policy '%s' (expected drop or sync)", name)
+	}
+}
+
+// WithQuantifiedTargets checks that the products and models linked by quantified associations exist in
+// destination before an item is written. Without it, items linked to missing targets are rejected.
+func WithQuantifiedTargets(policy TargetPolicy) Option {
+	return func(s *Service) {
+		s.targetPolicy = policy
+	}
+}
+
+// linkTarget is a product or product model linked by an association or a quantified association
+type linkTarget struct {
+	Kind string
+	Code string
+}
+
+func (t linkTarget) String() string {
+	return t.Kind + " " + t.Code
+}
+
+// targetCache remembers the targets known to exist in destination and the ones being synced
+type targetCache struct {
+	mu      sync.Mutex
+	found   map[linkTarget]bool
+	syncing map[linkTarget]bool
+}
+
+// exists tells whether a target is known to exist in destination
+func (c *targetCache) exists(target linkTarget) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.found[target]
+}
+
+// setFound records a target that exists in destination
+func (c *targetCache) setFound(target linkTarget) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.found == nil {
+		c.found = make(map[linkTarget]bool)
+	}
+	c.found[target] = true
+}
+
+// startSync marks a target as being synced. It returns false when the target is already being synced,
+// which happens when associations link items in a cycle.
+func (c *targetCache) startSync(target linkTarget) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.syncing[target] {
+		return false
+	}
+	if c.syncing == nil {
+		c.syncing = make(map[linkTarget]bool)
+	}
+	c.syncing[target] = true
+	return true
+}
+
+// endSync clears the syncing mark of a target
+func (c *targetCache) endSync(target linkTarget) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.syncing, target)
+}
+
+// linkKinds maps the link lists of an association to the kind of their targets
+var linkKinds = map[string]string{
+	"products":       KindProduct,
+	"product_models": KindProductModel,
+}
+
+// checkQuantifiedTargets returns a copy of an item without the quantified association links
+// whose target does not exist in destination. With the sync policy, missing targets are synced first
+// and only the ones that could not be synced are removed.
+func (s *Service) checkQuantifiedTargets(ctx context.Context, name string, item map[string]interface{}, opts SyncOptions) (map[string]interface{}, error) {
+	associations, ok := item["quantified_associations"].(map[string]interface{})
+	if s.targetPolicy == "" || !ok || len(associations) == 0 {
+		return item, nil
+	}
+
+	missing := make(map[linkTarget]bool)
+	for _, target := range quantifiedTargets(associations) {
+		found, err := s.ensureTarget(ctx, target, s.targetPolicy == TargetSync, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error checking quantified association targets of %s: %w", name, err)
+		}
+		if !found {
+			missing[target] = true
+		}
+	}
+	if len(missing) == 0 {
+		return item, nil
+	}
+
+	dropped := make([]string, 0, len(missing))
+	for target := range missing {
+		dropped = append(dropped, target.String())
+	}
+	sort.Strings(dropped)
+	s.logger.Warn("   ⚠️  Dropped quantified associations to items missing in destination", logger.F("item", name), logger.F("targets", strings.Join(dropped, ",")))
+
+	result := make(map[string]interface{}, len(item))
+	for key, value := range item {
+		result[key] = value
+	}
+	result["quantified_associations"] = withoutTargets(associations, missing)
+
+	return result, nil
+}
+
+// ensureTarget tells whether a target exists in destination, syncing its hierarchy first when sync is set
+func (s *Service) ensureTarget(ctx context.Context, target linkTarget, sync bool, opts SyncOptions) (bool, error) {
+	if s.targets.exists(target) {
+		return true, nil
+	}
+
+	exists, err := s.targetExistsInDest(ctx, target)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		s.targets.setFound(target)
+		return true, nil
+	}
+
+	if !sync || !s.targets.startSync(target) {
+		return false, nil
+	}
+	defer s.targets.endSync(target)
+
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	// A target missing in source cannot be synced and is dropped; other lookup errors fail the item
+	root, err := s.targetRoot(ctx, target)
+	if err != nil && !errors.Is(err, product.ErrNotFound) {
+		return false, fmt.Errorf("error fetching the hierarchy of %s from source: %w", target, err)
+	}
+	if err != nil {
+		s.logger.Warn("   ⚠️  Cannot sync association target", logger.F("target", target), logger.Err(err))
+		return false, nil
+	}
+
+	s.logger.Info("   🔗 Syncing association target", logger.F("target", target), logger.F("hierarchy", root))
+	if _, err := s.Sync(withLinkDepth(ctx, linkDepth(ctx)+1), root, opts); err != nil {
+		s.logger.Warn("   ⚠️  Cannot sync association target", logger.F("target", target), logger.Err(err))
+		return false, nil
+	}
+
+	// Part of the hierarchy may have failed: the target is only linked when it was written
+	exists, err = s.targetExistsInDest(ctx, target)
+	if err != nil || !exists {
+		return false, err
+	}
+	s.targets.setFound(target)
+	return true, nil
+}
+
+// targetExistsInDest looks a target up in destination. Only a target reported as not found is missing:
+// other lookup errors are returned, so a link is never dropped because destination could not be read.
+func (s *Service) targetExistsInDest(ctx context.Context, target linkTarget) (bool, error) {
+	var err error
+	if target.Kind == KindProductModel {
+		_, err = s.destRepo.FindModelByCode(ctx, target.Code)
+	} else {
+		_, err = s.destRepo.FindByIdentifier(ctx, target.Code)
+	}
+
+	if errors.Is(err, product.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error fetching %s from destination: %w", target, err)
+	}
+	return true, nil
+}
+
+// targetRoot returns the code of the root of the source hierarchy a target belongs to
+func (s *Service) targetRoot(ctx context.Context, target linkTarget) (string, error) {
+	code := target.Code
+	var parent string
+	if target.Kind == KindProduct {
+		prod, err := s.sourceRepo.FindByIdentifier(ctx, code)
+		if err != nil {
+			return "", err
+		}
+		parent, _ = prod["parent"].(string)
+	} else {
+		parent = code
+	}
+
+	// Hierarchies can be of any depth; a parent seen twice only happens with malformed data
+	seen := make(map[string]bool)
+	for parent != "" && !seen[parent] {
+		seen[parent] = true
+		code = parent
+		model, err := s.sourceRepo.FindModelByCode(ctx, code)
+		if err != nil {
+			return "", err
+		}
+		parent, _ = model["parent"].(string)
+	}
+
+	return code, nil
+}
+
+// quantifiedTargets returns the distinct products and models linked by quantified associations, sorted
+func quantifiedTargets(associations map[string]interface{}) []linkTarget {
+	seen := make(map[linkTarget]bool)
+	var targets []linkTarget
+	for _, links := range associations {
+		linksByKind, _ := links.(map[string]interface{})
+		for field, kind := range linkKinds {
+			entries, _ := linksByKind[field].([]interface{})
+			for _, raw := range entries {
+				entry, _ := raw.(map[string]interface{})
+				code, _ := entry["identifier"].(string)
+				target := linkTarget{Kind: kind, Code: code}
+				if code == "" || seen[target] {
+					continue
+				}
+				seen[target] = true
+				targets = append(targets, target)
+			}
+		}
+	}
+
+	sortTargets(targets)
+	return targets
+}
+
+// sortTargets sorts targets by kind, then by code
+func sortTargets(targets []linkTarget) {
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Kind != targets[j].Kind {
+			return targets[i].Kind < targets[j].Kind
+		}
+		return targets[i].Code < targets[j].Code
+	})
+}
+
+// withoutTargets returns a copy of quantified associations without the links to the given targets
+func withoutTargets(associations map[string]interface{}, missing map[linkTarget]bool) map[string]interface{} {
+	result := make(map[string]interface{}, len(associations))
+	for associationType, links := range associations {
+		linksByKind, ok := links.(map[string]interface{})
+		if !ok {
+			result[associationType] = links
+			continue
+		}
+
+		kept := make(map[string]interface{}, len(linksByKind))
+		for field, list := range linksByKind {
+			kind, quantified := linkKinds[field]
+			entries, ok := list.([]interface{})
+			if !quantified || !ok {
+				kept[field] = list
+				continue
+			}
+
+			remaining := make([]interface{}, 0, len(entries))
+			for _, raw := range entries {
+				entry, _ := raw.(map[string]interface{})
+				code, _ := entry["identifier"].(string)
+				if missing[linkTarget{Kind: kind, Code: code}] {
+					continue
+				}
+				remaining = append(remaining, raw)
+			}
+			kept[field] = remaining
+		}
+		result[associationType] = kept
+	}
+
+	return result
+}
+
+// mergeQuantifiedAssociations applies a strategy to the quantified associations of an item.
+// Links are identified by their target; the source quantity wins when both sides link the same target.
+func mergeQuantifiedAssociations(source, dest interface{}, strategy FieldStrategy) interface{} {
+	sourceAssociations, ok := source.(map[string]interface{})
+	if !ok {
+		return source
+	}
+	destAssociations, _ := dest.(map[string]interface{})
+
+	result := make(map[string]interface{}, len(sourceAssociations))
+	for associationType, links := range sourceAssociations {
+		result[associationType] = links
+	}
+
+	for associationType, destLinks := range destAssociations {
+		destLinksByKind, _ := destLinks.(map[string]interface{})
+		sourceLinksByKind, _ := sourceAssociations[associationType].(map[string]interface{})
+
+		links := make(map[string]interface{})
+		for kind, destList := range destLinksByKind {
+			switch strategy {
+			case FieldMerge:
+				links[kind] = unionQuantifiedList(sourceLinksByKind[kind], destList)
+			case FieldOverwrite:
+				if sourceList, exists := sourceLinksByKind[kind]; exists {
+					links[kind] = sourceList
+				} else {
+					links[kind] = []interface{}{}
+				}
+			}
+		}
+		for kind, sourceList := range sourceLinksByKind {
+			if _, exists := links[kind]; !exists {
+				links[kind] = sourceList
+			}
+		}
+
+		result[associationType] = links
+	}
+
+	return result
+}
+
+// unionQuantifiedList returns the union of two lists of quantified links sorted by identifier,
+// keeping the source link when both lists contain the same identifier
+func unionQuantifiedList(source, dest interface{}) []interface{} {
+	byIdentifier := make(map[string]interface{})
+	identifiers := []string{}
+
+	for _, list := range []interface{}{source, dest} {
+		entries, _ := list.([]interface{})
+		for _, raw := range entries {
+			entry, _ := raw.(map[string]interface{})
+			identifier, ok := entry["identifier"].(string)
+			if !ok {
+				continue
+			}
+			if _, exists := byIdentifier[identifier]; exists {
+				continue
+			}
+			byIdentifier[identifier] = raw
+			identifiers = append(identifiers, identifier)
+		}
+	}
+
+	sort.Strings(identifiers)
+	result := make([]interface{}, len(identifiers))
+	for i, identifier := range identifiers {
+		result[i] = byIdentifier[identifier]
+	}
+	return result
+}
+
+// withQuantifiedUUIDs returns a copy of a product whose quantified associations link products by their
+// destination UUID, as expected by the UUID endpoints. Product models keep being linked by code.
+func withQuantifiedUUIDs(prod map[string]interface{}, uuids map[string]string) map[string]interface{} {
+	associations, ok := prod["quantified_associations"].(map[string]interface{})
+	if !ok || len(associations) == 0 {
+		return prod
+	}
+
+	converted := make(map[string]interface{}, len(associations))
+	for associationType, links := range associations {
+		linksByKind, ok := links.(map[string]interface{})
+		if !ok {
+			converted[associationType] = links
+			continue
+		}
+
+		copied := make(map[string]interface{}, len(linksByKind))
+		for field, list := range linksByKind {
+			copied[field] = list
+		}
+
+		entries, _ := linksByKind["products"].([]interface{})
+		products := make([]interface{}, len(entries))
+		for i, raw := range entries {
+			products[i] = raw
+
+			entry, _ := raw.(map[string]interface{})
+			identifier, _ := entry["identifier"].(string)
+			uuid, ok := uuids[identifier]
+			if !ok {
+				continue
+			}
+			products[i] = map[string]interface{}{"uuid": uuid, "quantity": entry["quantity"]}
+		}
+		if entries != nil {
+			copied["products"] = products
+		}
+
+		converted[associationType] = copied
+	}
+
+	result := make(map[string]interface{}, len(prod))
+	for key, value := range prod {
+		result[key] = value
+	}
+	result["quantified_associations"] = converted
+
+	return result
+}
+
+// quantifiedProductIdentifiers returns the identifiers of the products linked by the quantified associations of an item
+func quantifiedProductIdentifiers(item map[string]interface{}) []string {
+	associations, _ := item["quantified_associations"].(map[string]interface{})
+
+	var identifiers []string
+	for _, target := range quantifiedTargets(associations) {
+		if target.Kind == KindProduct {
+			identifiers = append(identifiers, target.Code)
+		}
+	}
+	return identifiers
+}
@@ -0,0 +1,107 @@
+package syncing
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"akeneo-migrator/kit/logger"
+)
+
+// OptionCreator creates the attribute options missing in destination
+type OptionCreator interface {
+	// CreateOption copies an option of an attribute from source to destination, with its labels
+	CreateOption(ctx context.Context, attributeCode, optionCode string) error
+}
+
+// WithMissingOptions creates the attribute options destination reports missing when it rejects a product
+// or model, copying them from source, then writes the item again. Without it, such items fail.
+func WithMissingOptions(creator OptionCreator) Option {
+	return func(s *Service) {
+		s.optionCreator = creator
+	}
+}
+
+// maxOptionRecoveries is the number of times an item is written again after creating options, since
+// destination may report the missing options of an item a few at a time
+const maxOptionRecoveries = 3
+
+// missingOptionPatterns match the messages of the values rejected because an option does not exist,
+// capturing the attribute and the option codes, separated by commas for multi selects
+var missingOptionPatterns = []*regexp.Regexp{
+	// Property "color" expects a valid code. The option "navy" does not exist
+	regexp.MustCompile(`Property "(?P<attribute>[^"]+)" expects a valid code\. The option "(?P<options>[^"]+)" does not exist`),
+	// Property "sizes" expects valid codes. The following options do not exist: "xl,xxl"
+	regexp.MustCompile(`Property "(?P<attribute>[^"]+)" expects valid codes\. The following options do not exist: "(?P<options>[^"]+)"`),
+	// The option "navy" does not exist for attribute "color"
+	regexp.MustCompile(`[Oo]ption "(?P<options>[^"]+)" does not exist for (?:the )?attribute "(?P<attribute>[^"]+)"`),
+}
+
+// missingOption is an attribute option destination reported missing
+type missingOption struct {
+	Attribute string
+	Code      string
+}
+
+func (o missingOption) String() string {
+	return o.Attribute + "." + o.Code
+}
+
+// missingOptions returns the attribute options an error reports missing in destination, sorted
+func missingOptions(err error) []missingOption {
+	message := err.Error()
+	seen := make(map[missingOption]bool)
+	var options []missingOption
+	for _, pattern := range missingOptionPatterns {
+		for _, match := range pattern.FindAllStringSubmatch(message, -1) {
+			attribute := match[pattern.SubexpIndex("attribute")]
+			for _, code := range strings.Split(match[pattern.SubexpIndex("options")], ",") {
+				option := missingOption{Attribute: attribute, Code: strings.TrimSpace(code)}
+				if option.Code == "" || seen[option] {
+					continue
+				}
+				seen[option] = true
+				options = append(options, option)
+			}
+		}
+	}
+
+	sort.Slice(options, func(i, j int) bool {
+		return options[i].String() < options[j].String()
+	})
+	return options
+}
+
+// recoverOptions creates the options a write error reports missing and writes the item again, until it
+// is accepted or no new option is reported. It returns the error of the last write, or err when nothing
+// could be recovered.
+func (s *Service) recoverOptions(ctx context.Context, name string, err error, write func() error) error {
+	if s.optionCreator == nil || err == nil {
+		return err
+	}
+
+	created := make(map[missingOption]bool)
+	for attempt := 0; attempt < maxOptionRecoveries && err != nil; attempt++ {
+		var options []string
+		for _, option := range missingOptions(err) {
+			if created[option] {
+				continue
+			}
+			if createErr := s.optionCreator.CreateOption(ctx, option.Attribute, option.Code); createErr != nil {
+				return fmt.Errorf("%w (creating missing option %s: %v)", err, option, createErr)
+			}
+			created[option] = true
+			options = append(options, option.String())
+		}
+		if len(options) == 0 {
+			return err
+		}
+
+		s.logger.Info("   🏷️  Created missing attribute options", logger.F("item", name), logger.F("options", strings.Join(options, ",")))
+		err = write()
+	}
+
+	return err
+}
@@ -2,23 +2,200 @@ package syncing
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"akeneo-migrator/internal/product"
+	"akeneo-migrator/kit/anonymize"
+	"akeneo-migrator/kit/attributes"
+	"akeneo-migrator/kit/conflict"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/filter"
+	"akeneo-migrator/kit/limit"
+	"akeneo-migrator/kit/locales"
+	"akeneo-migrator/kit/logger"
+	"akeneo-migrator/kit/retry"
+	"akeneo-migrator/kit/transform"
+)
+
+// Kinds of items reported as failures
+const (
+	KindProduct      = "product"
+	KindProductModel = "product_model"
+	// KindMediaFile is a media file uploaded as a value of a product or model, its scope;
+	// only reported in planned writes
+	KindMediaFile = "media_file"
 )
 
 // Service handles the synchronization logic for Products
 type Service struct {
-	sourceRepo product.SourceRepository
-	destRepo   product.DestRepository
+	sourceRepo       product.SourceRepository
+	destRepo         product.DestRepository
+	fieldStrategies  map[string]FieldStrategy
+	anonymizer       *anonymize.Anonymizer
+	valueFilter      *filter.Filter
+	transformer      *transform.Transformer
+	localeChecker    *locales.Checker
+	attributeChecker *attributes.Checker
+	associations     AssociationTypeEnsurer
+	optionCreator    OptionCreator
+	variantChecker   VariantChecker
+	variants         variantCache
+	mediaFiles       mediaCache
+	skipMedia        bool
+	changedOnly      bool
+	mediaRetries     int
+	mediaRetryDelay  time.Duration
+	uuidRepo         product.UUIDRepository
+	uuids            uuidCache
+	targetPolicy     TargetPolicy
+	targets          targetCache
+	// associationPolicy is empty when the targets of associations are not checked
+	associationPolicy TargetPolicy
+	associationDepth  int
+	// conflicts detects the items edited in destination since their last sync, conflictStrategy being the default strategy
+	conflicts        *conflict.Detector
+	conflictStrategy conflict.Strategy
+	workers          int
+	logger           *logger.Logger
+}
+
+// AssociationTypeEnsurer creates the association types missing in destination
+type AssociationTypeEnsurer interface {
+	// EnsureExists creates the given association types when they do not exist and returns the created ones
+	EnsureExists(ctx context.Context, codes []string) ([]string, error)
+}
+
+// Option configures the synchronization service
+type Option func(*Service)
+
+// WithFieldStrategies sets the strategy applied to each top-level field of items that already exist in destination
+func WithFieldStrategies(strategies map[string]FieldStrategy) Option {
+	return func(s *Service) {
+		s.fieldStrategies = strategies
+	}
+}
+
+// WithAnonymizer anonymizes product and model values before they are written to destination
+func WithAnonymizer(anonymizer *anonymize.Anonymizer) Option {
+	return func(s *Service) {
+		s.anonymizer = anonymizer
+	}
+}
+
+// WithValueFilter removes the product and model values excluded by the filter before they are written to destination
+func WithValueFilter(valueFilter *filter.Filter) Option {
+	return func(s *Service) {
+		s.valueFilter = valueFilter
+	}
+}
+
+// WithTransformer computes product and model values with expressions before they are written to destination
+func WithTransformer(transformer *transform.Transformer) Option {
+	return func(s *Service) {
+		s.transformer = transformer
+	}
+}
+
+// WithLocaleChecker checks the locales of product and model values against the locales enabled in destination
+func WithLocaleChecker(checker *locales.Checker) Option {
+	return func(s *Service) {
+		s.localeChecker = checker
+	}
+}
+
+// WithAttributeChecker strips the product and model values of attributes that do not exist in destination
+func WithAttributeChecker(checker *attributes.Checker) Option {
+	return func(s *Service) {
+		s.attributeChecker = checker
+	}
+}
+
+// WithAssociationTypes creates the association types used by products and models before they are written.
+// Without it, items associated through a type missing in destination are rejected.
+func WithAssociationTypes(ensurer AssociationTypeEnsurer) Option {
+	return func(s *Service) {
+		s.associations = ensurer
+	}
+}
+
+// WithoutMedia leaves image and file values out of the payloads, for syncs that only need text data.
+// Nothing is downloaded or uploaded, and the media values already in destination are kept.
+func WithoutMedia() Option {
+	return func(s *Service) {
+		s.skipMedia = true
+	}
+}
+
+// WithMediaRetries sets how many times a media file failing to download or upload is tried again,
+// waiting delay before the first retry and longer before each next one
+func WithMediaRetries(retries int, delay time.Duration) Option {
+	return func(s *Service) {
+		s.mediaRetries = retries
+		s.mediaRetryDelay = delay
+	}
+}
+
+// WithWorkers sets the number of hierarchies or product batches written to destination at the same
+// time by the bulk syncs composing this service
+func WithWorkers(workers int) Option {
+	return func(s *Service) {
+		s.workers = workers
+	}
+}
+
+// WithLogger sends the messages of the syncs to log instead of the standard output
+func WithLogger(log *logger.Logger) Option {
+	return func(s *Service) {
+		s.logger = log
+	}
 }
 
 // NewService creates a new instance of the synchronization service
-func NewService(sourceRepo product.SourceRepository, destRepo product.DestRepository) *Service {
-	return &Service{
-		sourceRepo: sourceRepo,
-		destRepo:   destRepo,
+func NewService(sourceRepo product.SourceRepository, destRepo product.DestRepository, opts ...Option) *Service {
+	service := &Service{
+		sourceRepo:      sourceRepo,
+		destRepo:        destRepo,
+		fieldStrategies: map[string]FieldStrategy{},
+		mediaRetries:    DefaultMediaRetries,
+		mediaRetryDelay: DefaultMediaRetryDelay,
+		logger:          logger.Default(),
 	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
+
+// Logger returns the logger of the service, used by the bulk syncs composing it
+func (s *Service) Logger() *logger.Logger {
+	return s.logger
+}
+
+// Workers returns the number of writes a bulk sync runs at the same time, 0 when not set. Interactive
+// conflict resolution runs them one at a time, since questions from several workers would be mixed up.
+func (s *Service) Workers(opts SyncOptions) int {
+	if s.strategyOf(opts) == conflict.Interactive {
+		return 1
+	}
+	return s.workers
+}
+
+// SyncOptions contains per-run options of a product sync
+type SyncOptions struct {
+	// ValuesOnly sends only the values of products and models that already exist in destination,
+	// leaving categories, groups, associations and the enabled flag untouched
+	ValuesOnly bool
+	// Conflicts overrides the strategy applied to items edited in destination since their last sync
+	Conflicts conflict.Strategy
+	// Range selects the items listed by the bulk syncs, e.g. the first 50 for a trial run; the sync
+	// of a single hierarchy ignores it
+	Range limit.Range
 }
 
 // SyncResult contains the result of a synchronization operation
@@ -29,28 +206,86 @@ type SyncResult struct {
 	ModelsSynced   int
 	ProductsSynced int
 	TotalSynced    int
+	// Errors are the products and models of the hierarchy that could not be written
+	Errors []SyncError
+	// Conflicts are the items edited in destination since their last sync
+	Conflicts []conflict.Conflict
+	// MissingTargets are the association links dropped because their target is missing in destination
+	MissingTargets []MissingTarget
+	// DroppedAttributes are the values left out because their attribute is missing in destination
+	DroppedAttributes []DroppedAttributes
+	// Unchanged is the number of products and models not written because they match destination,
+	// with changed-only updates
+	Unchanged int
+	// Planned are the writes recorded instead of being sent during a dry run
+	Planned []dryrun.Write
+}
+
+// SyncError represents an item of the hierarchy that could not be synchronized
+type SyncError struct {
+	Kind    string
+	Code    string
+	Message string
+	// FailedModel is set when the item was skipped because a model above it could not be written
+	FailedModel string
+}
+
+// DroppedAttributes lists the attributes whose values were left out of an item because they do not
+// exist in destination
+type DroppedAttributes struct {
+	// Item is the product or model written without the values, e.g. "product SKU-1"
+	Item       string
+	Attributes []string
+}
+
+func (d DroppedAttributes) String() string {
+	return fmt.Sprintf("%s: %s", d.Item, strings.Join(d.Attributes, ", "))
+}
+
+// Failures returns the items of the hierarchy that failed
+func (r *SyncResult) Failures() []retry.Failure {
+	failures := make([]retry.Failure, 0, len(r.Errors))
+	for _, syncErr := range r.Errors {
+		failures = append(failures, retry.Failure{Kind: syncErr.Kind, Code: syncErr.Code, Error: syncErr.Message})
+	}
+	return failures
+}
+
+// Synced returns the number of products and models written
+func (r *SyncResult) Synced() int {
+	return r.TotalSynced
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
 }
 
-// Sync synchronizes a complete product hierarchy (common → models → products)
-func (s *Service) Sync(ctx context.Context, commonIdentifier string) (*SyncResult, error) {
+// Sync synchronizes a complete product hierarchy: a common product and its children, or a root
+// model with its sub-models and variant products at every level
+func (s *Service) Sync(ctx context.Context, commonIdentifier string, opts SyncOptions) (*SyncResult, error) {
 	result := &SyncResult{
 		Identifier: commonIdentifier,
 	}
+	ctx, planned := dryrun.Collect(ctx)
 
 	// 1. Sync the common product/model
-	fmt.Printf("   📦 Syncing common: %s\n", commonIdentifier)
+	s.logger.Info("   📦 Syncing common", logger.F("identifier", commonIdentifier))
 
 	// Try as product first
 	commonProduct, err := s.sourceRepo.FindByIdentifier(ctx, commonIdentifier)
 	if err == nil {
 		// It's a product (simple type)
-		if err := s.destRepo.Save(ctx, commonIdentifier, commonProduct); err != nil {
+		written, err := s.saveProduct(ctx, commonIdentifier, commonProduct, result, opts)
+		if err != nil {
 			return nil, fmt.Errorf("error saving common product: %w", err)
 		}
-		result.ProductsSynced++
+		if written {
+			result.ProductsSynced++
+		}
 
 		// Sync child products
-		if err := s.syncChildProducts(ctx, commonIdentifier, result); err != nil {
+		if err := s.syncChildProducts(ctx, commonIdentifier, result, opts); err != nil {
 			return nil, err
 		}
 	} else {
@@ -60,119 +295,725 @@ func (s *Service) Sync(ctx context.Context, commonIdentifier string) (*SyncResul
 			return nil, fmt.Errorf("common '%s' not found as product or model: %w", commonIdentifier, modelErr)
 		}
 
-		if err := s.destRepo.SaveModel(ctx, commonIdentifier, commonModel); err != nil {
+		written, err := s.saveModel(ctx, commonIdentifier, commonModel, result, opts)
+		if err != nil {
 			return nil, fmt.Errorf("error saving common model: %w", err)
 		}
-		result.ModelsSynced++
-
-		// Sync child models
-		if err := s.syncChildModels(ctx, commonIdentifier, result); err != nil {
-			return nil, err
+		if written {
+			result.ModelsSynced++
 		}
 
-		// Sync all variant products under all models
-		if err := s.syncVariantProducts(ctx, commonIdentifier, result); err != nil {
+		// Sync the sub-models and variant products under it, at any depth
+		if err := s.syncModelTree(ctx, commonIdentifier, result, opts); err != nil {
 			return nil, err
 		}
 	}
 
 	result.TotalSynced = result.ModelsSynced + result.ProductsSynced
+	result.Planned = planned()
 	result.Success = true
 	return result, nil
 }
 
 // syncChildProducts syncs all products that have the given parent
-func (s *Service) syncChildProducts(ctx context.Context, parentCode string, result *SyncResult) error {
+func (s *Service) syncChildProducts(ctx context.Context, parentCode string, result *SyncResult, opts SyncOptions) error {
 	products, err := s.sourceRepo.FindProductsByParent(ctx, parentCode)
 	if err != nil {
 		return fmt.Errorf("error fetching child products: %w", err)
 	}
 
-	fmt.Printf("   👶 Found %d child products\n", len(products))
+	s.logger.Debug("   👶 Found child products", logger.F("parent", parentCode), logger.F("count", len(products)))
+
+	return s.saveProducts(ctx, "product", products, result, opts)
+}
+
+// syncModelTree syncs the product models and variant products under a root model. The whole tree is
+// read first, then written level by level in code order: every model is written before the models
+// and products under it, whatever the depth of the hierarchy, and the items of a level are written
+// together in batches. The descendants of a model that could not be written are skipped, since
+// destination would reject them or create them without their parent.
+func (s *Service) syncModelTree(ctx context.Context, rootCode string, result *SyncResult, opts SyncOptions) error {
+	tree, err := s.readModelTree(ctx, rootCode, result)
+	if err != nil {
+		return err
+	}
+
+	// failed maps the models missing in destination to the model whose failure keeps them out
+	failed := make(map[string]string)
+	for depth, level := range tree.levels {
+		s.logger.Debug("   📋 Writing hierarchy level", logger.F("depth", depth+1), logger.F("models", len(level.models)), logger.F("variants", len(level.products)))
+
+		models := make([]product.ProductModel, 0, len(level.models))
+		for _, model := range level.models {
+			code, _ := model["code"].(string)
+			if cause, blocked := failed[tree.parents[modelKey(code)]]; blocked {
+				failed[code] = cause
+				s.skip(KindProductModel, code, cause, result)
+				continue
+			}
+			models = append(models, model)
+		}
 
+		products := make([]product.Product, 0, len(level.products))
+		for _, prod := range level.products {
+			identifier, _ := prod["identifier"].(string)
+			if cause, blocked := failed[tree.parents[productKey(identifier)]]; blocked {
+				s.skip(KindProduct, identifier, cause, result)
+				continue
+			}
+			products = append(products, prod)
+		}
+
+		written := len(result.Errors)
+		if err := s.saveModels(ctx, models, result, opts); err != nil {
+			return err
+		}
+		for _, syncErr := range result.Errors[written:] {
+			if syncErr.Kind == KindProductModel {
+				failed[syncErr.Code] = syncErr.Code
+			}
+		}
+
+		if err := s.saveProducts(ctx, "variant", products, result, opts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// modelTree is the hierarchy under a root model, as read from source
+type modelTree struct {
+	// levels are the models and products at each depth under the root, sorted by code
+	levels []treeLevel
+	// parents maps the models and products of the tree (see modelKey and productKey) to their parent model
+	parents map[string]string
+}
+
+// treeLevel holds the models and products at one depth of a model tree
+type treeLevel struct {
+	models   []product.ProductModel
+	products []product.Product
+}
+
+// modelKey identifies a model in the parents of a tree, since model codes and product identifiers may collide
+func modelKey(code string) string {
+	return KindProductModel + ":" + code
+}
+
+// productKey identifies a product in the parents of a tree
+func productKey(identifier string) string {
+	return KindProduct + ":" + identifier
+}
+
+// readModelTree reads the models and products under a root model from source, level by level.
+// A sub-model whose children cannot be listed is reported as failed and the rest of the tree goes on.
+func (s *Service) readModelTree(ctx context.Context, rootCode string, result *SyncResult) (*modelTree, error) {
+	tree := &modelTree{parents: make(map[string]string)}
+	visited := map[string]bool{rootCode: true}
+	parents := []string{rootCode}
+	for len(parents) > 0 {
+		var level treeLevel
+		var next []string
+		for _, parentCode := range parents {
+			children, err := s.sourceRepo.FindModelsByParent(ctx, parentCode)
+			if err != nil {
+				if parentCode == rootCode {
+					return nil, fmt.Errorf("error fetching child models: %w", err)
+				}
+				s.logger.Warn("   ⚠️  Error fetching child models", logger.F("model", parentCode), logger.Err(err))
+				result.Errors = append(result.Errors, SyncError{Kind: KindProductModel, Code: parentCode, Message: err.Error()})
+				continue
+			}
+
+			for _, child := range children {
+				code, _ := child["code"].(string)
+				// A model listed twice, e.g. by malformed source data, is only synced once
+				if code == "" || visited[code] {
+					continue
+				}
+				visited[code] = true
+				tree.parents[modelKey(code)] = parentCode
+				level.models = append(level.models, child)
+				next = append(next, code)
+			}
+
+			products, err := s.sourceRepo.FindProductsByParent(ctx, parentCode)
+			if err != nil {
+				s.logger.Warn("   ⚠️  Error fetching variants", logger.F("model", parentCode), logger.Err(err))
+				result.Errors = append(result.Errors, SyncError{Kind: KindProductModel, Code: parentCode, Message: err.Error()})
+				continue
+			}
+			for _, prod := range products {
+				identifier, _ := prod["identifier"].(string)
+				tree.parents[productKey(identifier)] = parentCode
+			}
+			level.products = append(level.products, products...)
+		}
+
+		if len(level.models) == 0 && len(level.products) == 0 {
+			break
+		}
+
+		sort.SliceStable(level.models, func(i, j int) bool {
+			return fmt.Sprint(level.models[i]["code"]) < fmt.Sprint(level.models[j]["code"])
+		})
+		sort.SliceStable(level.products, func(i, j int) bool {
+			return fmt.Sprint(level.products[i]["identifier"]) < fmt.Sprint(level.products[j]["identifier"])
+		})
+		tree.levels = append(tree.levels, level)
+		parents = next
+	}
+
+	return tree, nil
+}
+
+// skip reports an item of a model tree left out because the model above it could not be written
+func (s *Service) skip(kind, code, failedModel string, result *SyncResult) {
+	s.logger.Warn("   ⏭️  Skipped item under a failed model", logger.F("kind", kind), logger.F("code", code), logger.F("failed_model", failedModel))
+	result.Errors = append(result.Errors, SyncError{
+		Kind:        kind,
+		Code:        code,
+		Message:     fmt.Sprintf("not synced because parent model %s failed", failedModel),
+		FailedModel: failedModel,
+	})
+}
+
+// saveProducts writes products to destination in batches, recording the ones that fail.
+// The label names the products in the output ("product" or "variant").
+// An error is only returned when a conflict aborts the sync.
+func (s *Service) saveProducts(ctx context.Context, label string, products []product.Product, result *SyncResult, opts SyncOptions) error {
+	batch := make([]product.Product, 0, len(products))
+	media := make(map[string][]pendingMedia)
 	for _, prod := range products {
 		identifier, _ := prod["identifier"].(string)
 		if identifier == "" {
 			continue
 		}
 
-		if err := s.destRepo.Save(ctx, identifier, prod); err != nil {
-			fmt.Printf("   ⚠️  Error syncing product %s: %v\n", identifier, err)
+		write, err := s.resolveConflict(ctx, KindProduct, identifier, opts, result)
+		if errors.Is(err, conflict.ErrConflict) {
+			return err
+		}
+		if err != nil {
+			s.logger.Warn("   ⚠️  Error syncing "+label, logger.F("identifier", identifier), logger.Err(err))
+			result.Errors = append(result.Errors, SyncError{Kind: KindProduct, Code: identifier, Message: err.Error()})
+			continue
+		}
+		if !write {
+			continue
+		}
+
+		prepared, pending, err := s.prepareProduct(ctx, identifier, prod, result, opts)
+		if err != nil {
+			s.logger.Warn("   ⚠️  Error syncing "+label, logger.F("identifier", identifier), logger.Err(err))
+			result.Errors = append(result.Errors, SyncError{Kind: KindProduct, Code: identifier, Message: err.Error()})
+			continue
+		}
+		if s.unchanged(prepared, pending) {
+			s.logger.Debug("   ⏸️  Unchanged "+label, logger.F("identifier", identifier))
+			result.Unchanged++
 			continue
 		}
+		batch = append(batch, prepared)
+		media[identifier] = pending
+	}
 
-		fmt.Printf("   ✅ Synced product: %s\n", identifier)
-		result.ProductsSynced++
+	if len(batch) == 0 {
+		return nil
 	}
 
-	return nil
-}
+	failed, err := s.writeProducts(ctx, batch)
+	for _, prod := range batch {
+		identifier, _ := prod["identifier"].(string)
 
-// syncChildModels syncs all product models that have the given parent
-func (s *Service) syncChildModels(ctx context.Context, parentCode string, result *SyncResult) error {
-	models, err := s.sourceRepo.FindModelsByParent(ctx, parentCode)
-	if err != nil {
-		return fmt.Errorf("error fetching child models: %w", err)
+		saveErr := err
+		if saveErr == nil {
+			saveErr = s.recoverOptions(ctx, "product "+identifier, failed[identifier], func() error {
+				return s.writeProduct(ctx, identifier, prod)
+			})
+		}
+		if saveErr == nil {
+			saveErr = s.copyMedia(ctx, product.MediaTarget{Identifier: identifier}, media[identifier])
+		}
+		if saveErr != nil {
+			s.logger.Warn("   ⚠️  Error syncing "+label, logger.F("identifier", identifier), logger.Err(saveErr))
+			result.Errors = append(result.Errors, SyncError{Kind: KindProduct, Code: identifier, Message: saveErr.Error()})
+			continue
+		}
+
+		s.recordSynced(ctx, KindProduct, identifier, opts)
+		s.logger.Info("   ✅ Synced "+label, logger.F("identifier", identifier))
+		result.ProductsSynced++
 	}
 
-	fmt.Printf("   📋 Found %d child models\n", len(models))
+	return nil
+}
 
+// saveModels writes product models to destination in batches, recording the ones that fail.
+// An error is only returned when a conflict aborts the sync.
+func (s *Service) saveModels(ctx context.Context, models []product.ProductModel, result *SyncResult, opts SyncOptions) error {
+	batch := make([]product.ProductModel, 0, len(models))
+	media := make(map[string][]pendingMedia)
 	for _, model := range models {
 		code, _ := model["code"].(string)
 		if code == "" {
 			continue
 		}
 
-		if err := s.destRepo.SaveModel(ctx, code, model); err != nil {
-			fmt.Printf("   ⚠️  Error syncing model %s: %v\n", code, err)
+		write, err := s.resolveConflict(ctx, KindProductModel, code, opts, result)
+		if errors.Is(err, conflict.ErrConflict) {
+			return err
+		}
+		if err != nil {
+			s.logger.Warn("   ⚠️  Error syncing model", logger.F("code", code), logger.Err(err))
+			result.Errors = append(result.Errors, SyncError{Kind: KindProductModel, Code: code, Message: err.Error()})
+			continue
+		}
+		if !write {
+			continue
+		}
+
+		prepared, pending, err := s.prepareModel(ctx, code, model, result, opts)
+		if err != nil {
+			s.logger.Warn("   ⚠️  Error syncing model", logger.F("code", code), logger.Err(err))
+			result.Errors = append(result.Errors, SyncError{Kind: KindProductModel, Code: code, Message: err.Error()})
 			continue
 		}
+		if s.unchanged(prepared, pending) {
+			s.logger.Debug("   ⏸️  Unchanged model", logger.F("code", code))
+			result.Unchanged++
+			continue
+		}
+		batch = append(batch, prepared)
+		media[code] = pending
+	}
+
+	if len(batch) == 0 {
+		return nil
+	}
 
-		fmt.Printf("   ✅ Synced model: %s\n", code)
+	failed, err := s.writeModels(ctx, batch)
+	for _, model := range batch {
+		code, _ := model["code"].(string)
+
+		saveErr := err
+		if saveErr == nil {
+			saveErr = s.recoverOptions(ctx, "product model "+code, failed[code], func() error {
+				return s.writeModel(ctx, code, model)
+			})
+		}
+		if saveErr == nil {
+			saveErr = s.copyMedia(ctx, product.MediaTarget{ModelCode: code}, media[code])
+		}
+		if saveErr != nil {
+			s.logger.Warn("   ⚠️  Error syncing model", logger.F("code", code), logger.Err(saveErr))
+			result.Errors = append(result.Errors, SyncError{Kind: KindProductModel, Code: code, Message: saveErr.Error()})
+			continue
+		}
+
+		s.recordSynced(ctx, KindProductModel, code, opts)
+		s.logger.Info("   ✅ Synced model", logger.F("code", code))
 		result.ModelsSynced++
 	}
 
 	return nil
 }
 
-// syncVariantProducts syncs all variant products under all models of a common
-func (s *Service) syncVariantProducts(ctx context.Context, commonCode string, result *SyncResult) error {
-	// Get all models under the common
-	models, err := s.sourceRepo.FindModelsByParent(ctx, commonCode)
+// saveProduct writes a product to destination, applying the sync options and field strategies.
+// It reports whether the product was written, which is not the case when a conflict keeps the destination one.
+func (s *Service) saveProduct(ctx context.Context, identifier string, prod product.Product, result *SyncResult, opts SyncOptions) (bool, error) {
+	write, err := s.resolveConflict(ctx, KindProduct, identifier, opts, result)
+	if err != nil || !write {
+		return false, err
+	}
+
+	prepared, pending, err := s.prepareProduct(ctx, identifier, prod, result, opts)
 	if err != nil {
-		return fmt.Errorf("error fetching models for variants: %w", err)
+		return false, err
+	}
+	if s.unchanged(prepared, pending) {
+		s.logger.Debug("   ⏸️  Unchanged product", logger.F("identifier", identifier))
+		result.Unchanged++
+		return false, nil
 	}
 
-	// For each model, get its variant products
-	for _, model := range models {
-		modelCode, _ := model["code"].(string)
-		if modelCode == "" {
-			continue
-		}
+	err = s.writeProduct(ctx, identifier, prepared)
+	err = s.recoverOptions(ctx, "product "+identifier, err, func() error {
+		return s.writeProduct(ctx, identifier, prepared)
+	})
+	if err != nil {
+		return false, err
+	}
 
-		products, err := s.sourceRepo.FindProductsByParent(ctx, modelCode)
-		if err != nil {
-			fmt.Printf("   ⚠️  Error fetching variants for model %s: %v\n", modelCode, err)
-			continue
-		}
+	if err := s.copyMedia(ctx, product.MediaTarget{Identifier: identifier}, pending); err != nil {
+		return false, err
+	}
+
+	s.recordSynced(ctx, KindProduct, identifier, opts)
+	return true, nil
+}
 
-		fmt.Printf("   🔸 Found %d variants for model %s\n", len(products), modelCode)
+// prepareProduct builds the payload of a product, applying the sync options and field strategies.
+// It also returns the media values to copy once the product is written.
+func (s *Service) prepareProduct(ctx context.Context, identifier string, prod product.Product, result *SyncResult, opts SyncOptions) (product.Product, []pendingMedia, error) {
+	if err := s.checkVariant(ctx, "product "+identifier, prod); err != nil {
+		return nil, nil, err
+	}
 
-		for _, prod := range products {
-			identifier, _ := prod["identifier"].(string)
-			if identifier == "" {
-				continue
+	transformed, err := s.transformer.Apply(prod)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error transforming product %s: %w", identifier, err)
+	}
+	prod = s.filterValues(s.anonymizeValues(transformed))
+
+	prod, err = s.checkLocales(ctx, "product "+identifier, prod)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prod, err = s.checkAttributes(ctx, "product "+identifier, prod, result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prod, err = s.checkQuantifiedTargets(ctx, "product "+identifier, prod, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prod, err = s.checkAssociationTargets(ctx, "product "+identifier, prod, result, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Media values are extracted before merging with destination, whose file codes are already valid
+	prod, pending := s.extractMedia(prod)
+
+	if opts.ValuesOnly || len(s.fieldStrategies) > 0 || s.changedOnly {
+		// An item missing from destination is written with its full payload
+		destProduct, err := s.destRepo.FindByIdentifier(ctx, identifier)
+		if err != nil && !errors.Is(err, product.ErrNotFound) {
+			return nil, nil, fmt.Errorf("error fetching product %s from destination: %w", identifier, err)
+		}
+		if err == nil {
+			destProduct = s.destScope(destProduct)
+			if opts.ValuesOnly {
+				prod = product.Product{
+					"identifier": identifier,
+					"values":     prod["values"],
+				}
+			} else {
+				prod = applyFieldStrategies(prod, destProduct, s.fieldStrategies)
+			}
+			if s.changedOnly {
+				prod = changesOf(prod, destProduct, "identifier")
 			}
+		}
+	}
 
-			if err := s.destRepo.Save(ctx, identifier, prod); err != nil {
-				fmt.Printf("   ⚠️  Error syncing variant %s: %v\n", identifier, err)
-				continue
+	if err := s.ensureAssociationTypes(ctx, "product "+identifier, prod); err != nil {
+		return nil, nil, err
+	}
+
+	return prod, pending, nil
+}
+
+// saveModel writes a product model to destination, applying the sync options and field strategies.
+// It reports whether the model was written, which is not the case when a conflict keeps the destination one.
+func (s *Service) saveModel(ctx context.Context, code string, model product.ProductModel, result *SyncResult, opts SyncOptions) (bool, error) {
+	write, err := s.resolveConflict(ctx, KindProductModel, code, opts, result)
+	if err != nil || !write {
+		return false, err
+	}
+
+	prepared, pending, err := s.prepareModel(ctx, code, model, result, opts)
+	if err != nil {
+		return false, err
+	}
+	if s.unchanged(prepared, pending) {
+		s.logger.Debug("   ⏸️  Unchanged model", logger.F("code", code))
+		result.Unchanged++
+		return false, nil
+	}
+
+	err = s.writeModel(ctx, code, prepared)
+	err = s.recoverOptions(ctx, "product model "+code, err, func() error {
+		return s.writeModel(ctx, code, prepared)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if err := s.copyMedia(ctx, product.MediaTarget{ModelCode: code}, pending); err != nil {
+		return false, err
+	}
+
+	s.recordSynced(ctx, KindProductModel, code, opts)
+	return true, nil
+}
+
+// writeModel writes a product model, or only records the write during a dry run
+func (s *Service) writeModel(ctx context.Context, code string, model product.ProductModel) error {
+	if dryrun.Record(ctx, dryrun.Write{Kind: KindProductModel, Code: code, Data: model}) {
+		return nil
+	}
+	return s.destRepo.SaveModel(ctx, code, model)
+}
+
+// writeModels writes product models in a batch, or only records the writes during a dry run
+func (s *Service) writeModels(ctx context.Context, models []product.ProductModel) (map[string]error, error) {
+	if !dryrun.Enabled(ctx) {
+		return s.destRepo.SaveModels(ctx, models)
+	}
+
+	for _, model := range models {
+		code, _ := model["code"].(string)
+		dryrun.Record(ctx, dryrun.Write{Kind: KindProductModel, Code: code, Data: model})
+	}
+	return nil, nil
+}
+
+// WriteProduct sends a product to destination the way the sync writes it, e.g. when applying a plan
+func (s *Service) WriteProduct(ctx context.Context, identifier string, prod product.Product) error {
+	return s.writeProduct(ctx, identifier, prod)
+}
+
+// WriteModel sends a product model to destination the way the sync writes it, e.g. when applying a plan
+func (s *Service) WriteModel(ctx context.Context, code string, model product.ProductModel) error {
+	return s.writeModel(ctx, code, model)
+}
+
+// prepareModel builds the payload of a product model, applying the sync options and field strategies.
+// It also returns the media values to copy once the product model is written.
+func (s *Service) prepareModel(ctx context.Context, code string, model product.ProductModel, result *SyncResult, opts SyncOptions) (product.ProductModel, []pendingMedia, error) {
+	if err := s.checkVariant(ctx, "product model "+code, model); err != nil {
+		return nil, nil, err
+	}
+
+	transformed, err := s.transformer.Apply(model)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error transforming product model %s: %w", code, err)
+	}
+	model = s.filterValues(s.anonymizeValues(transformed))
+
+	model, err = s.checkLocales(ctx, "product model "+code, model)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	model, err = s.checkAttributes(ctx, "product model "+code, model, result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	model, err = s.checkQuantifiedTargets(ctx, "product model "+code, model, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	model, err = s.checkAssociationTargets(ctx, "product model "+code, model, result, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Media values are extracted before merging with destination, whose file codes are already valid
+	model, pending := s.extractMedia(model)
+
+	if opts.ValuesOnly || len(s.fieldStrategies) > 0 || s.changedOnly {
+		// An item missing from destination is written with its full payload
+		destModel, err := s.destRepo.FindModelByCode(ctx, code)
+		if err != nil && !errors.Is(err, product.ErrNotFound) {
+			return nil, nil, fmt.Errorf("error fetching product model %s from destination: %w", code, err)
+		}
+		if err == nil {
+			destModel = s.destScope(destModel)
+			if opts.ValuesOnly {
+				model = product.ProductModel{
+					"code":   code,
+					"values": model["values"],
+				}
+			} else {
+				model = applyFieldStrategies(model, destModel, s.fieldStrategies)
 			}
+			if s.changedOnly {
+				model = changesOf(model, destModel, "code")
+			}
+		}
+	}
 
-			fmt.Printf("   ✅ Synced variant: %s\n", identifier)
-			result.ProductsSynced++
+	if err := s.ensureAssociationTypes(ctx, "product model "+code, model); err != nil {
+		return nil, nil, err
+	}
+
+	return model, pending, nil
+}
+
+// SaveModel writes a single product model to destination, without its children,
+// applying the same transformations, anonymization, field strategies and conflict detection as Sync.
+// The model is not written, and not counted in the result, when a conflict keeps the destination one.
+func (s *Service) SaveModel(ctx context.Context, code string, model product.ProductModel, opts SyncOptions) (*SyncResult, error) {
+	result := &SyncResult{Identifier: code}
+	written, err := s.saveModel(ctx, code, model, result, opts)
+	if err != nil {
+		return nil, err
+	}
+	if written {
+		result.ModelsSynced++
+	}
+
+	result.TotalSynced = result.ModelsSynced
+	result.Success = true
+	return result, nil
+}
+
+// SaveProducts writes products to destination in batches, without their hierarchy,
+// applying the same transformations, anonymization, field strategies and conflict detection as Sync.
+// Products that could not be written are reported in the result; an error is only returned when
+// a conflict aborts the sync.
+func (s *Service) SaveProducts(ctx context.Context, products []product.Product, opts SyncOptions) (*SyncResult, error) {
+	result := &SyncResult{}
+	ctx, planned := dryrun.Collect(ctx)
+	if err := s.saveProducts(ctx, "product", products, result, opts); err != nil {
+		return nil, err
+	}
+
+	result.TotalSynced = result.ProductsSynced
+	result.Planned = planned()
+	result.Success = len(result.Errors) == 0
+	return result, nil
+}
+
+// anonymizeValues returns a copy of an item with its values anonymized
+func (s *Service) anonymizeValues(item map[string]interface{}) map[string]interface{} {
+	values, ok := item["values"].(map[string]interface{})
+	if !ok || !s.anonymizer.Enabled() {
+		return item
+	}
+
+	anonymized := make(map[string]interface{}, len(item))
+	for key, value := range item {
+		anonymized[key] = value
+	}
+	anonymized["values"] = s.anonymizer.Apply(values)
+
+	return anonymized
+}
+
+// destScope returns a copy of a destination item without the values the sync leaves out on purpose,
+// dropped by anonymization or excluded by the value filter, so the overwrite strategy does not clear them
+func (s *Service) destScope(item map[string]interface{}) map[string]interface{} {
+	values, ok := item["values"].(map[string]interface{})
+	if !ok || (!s.anonymizer.Enabled() && !s.valueFilter.Enabled()) {
+		return item
+	}
+
+	scoped := make(map[string]interface{}, len(item))
+	for key, value := range item {
+		scoped[key] = value
+	}
+	scoped["values"] = s.valueFilter.ApplyDest(s.anonymizer.WithoutDropped(values))
+
+	return scoped
+}
+
+// filterValues returns a copy of an item without the values excluded by the value filter
+func (s *Service) filterValues(item map[string]interface{}) map[string]interface{} {
+	values, ok := item["values"].(map[string]interface{})
+	if !ok || !s.valueFilter.Enabled() {
+		return item
+	}
+
+	filtered := make(map[string]interface{}, len(item))
+	for key, value := range item {
+		filtered[key] = value
+	}
+	filtered["values"] = s.valueFilter.Apply(values)
+
+	return filtered
+}
+
+// checkLocales returns a copy of an item without the values in locales that are not enabled in destination,
+// or an error when the locale policy rejects such values
+func (s *Service) checkLocales(ctx context.Context, name string, item map[string]interface{}) (map[string]interface{}, error) {
+	values, ok := item["values"].(map[string]interface{})
+	if !ok {
+		return item, nil
+	}
+
+	checked, disabled, err := s.localeChecker.Apply(ctx, values)
+	if err != nil {
+		return nil, fmt.Errorf("error checking locales of %s: %w", name, err)
+	}
+	if len(disabled) == 0 {
+		return item, nil
+	}
+
+	s.logger.Warn("   ⚠️  Dropped values in locales not enabled in destination", logger.F("item", name), logger.F("locales", strings.Join(disabled, ",")))
+
+	result := make(map[string]interface{}, len(item))
+	for key, value := range item {
+		result[key] = value
+	}
+	result["values"] = checked
+
+	return result, nil
+}
+
+// checkAttributes returns a copy of an item without the values of attributes that do not exist in destination,
+// adding the dropped attributes to the result
+func (s *Service) checkAttributes(ctx context.Context, name string, item map[string]interface{}, result *SyncResult) (map[string]interface{}, error) {
+	values, ok := item["values"].(map[string]interface{})
+	if !ok {
+		return item, nil
+	}
+
+	checked, missing, err := s.attributeChecker.Apply(ctx, values)
+	if err != nil {
+		return nil, fmt.Errorf("error checking attributes of %s: %w", name, err)
+	}
+	if len(missing) == 0 {
+		return item, nil
+	}
+
+	s.logger.Warn("   ⚠️  Dropped values of attributes missing in destination", logger.F("item", name), logger.F("attributes", strings.Join(missing, ",")))
+	result.DroppedAttributes = append(result.DroppedAttributes, DroppedAttributes{Item: name, Attributes: missing})
+
+	checkedItem := make(map[string]interface{}, len(item))
+	for key, value := range item {
+		checkedItem[key] = value
+	}
+	checkedItem["values"] = checked
+
+	return checkedItem, nil
+}
+
+// ensureAssociationTypes creates the association types used by the associations of an item
+// when they are missing in destination
+func (s *Service) ensureAssociationTypes(ctx context.Context, name string, item map[string]interface{}) error {
+	if s.associations == nil {
+		return nil
+	}
+
+	var codes []string
+	for _, field := range []string{"associations", "quantified_associations"} {
+		associations, _ := item[field].(map[string]interface{})
+		for code := range associations {
+			codes = append(codes, code)
 		}
 	}
+	if len(codes) == 0 {
+		return nil
+	}
+	sort.Strings(codes)
+
+	created, err := s.associations.EnsureExists(ctx, codes)
+	if err != nil {
+		return fmt.Errorf("error creating association types of %s: %w", name, err)
+	}
+	if len(created) > 0 {
+		s.logger.Info("   🔗 Created association types", logger.F("item", name), logger.F("association_types", strings.Join(created, ",")))
+	}
 
 	return nil
 }
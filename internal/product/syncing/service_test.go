@@ -3,10 +3,20 @@ package syncing_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	"akeneo-migrator/internal/product"
 	"akeneo-migrator/internal/product/syncing"
+	"akeneo-migrator/kit/anonymize"
+	"akeneo-migrator/kit/attributes"
+	"akeneo-migrator/kit/conflict"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/filter"
+	"akeneo-migrator/kit/locales"
+	"akeneo-migrator/kit/transform"
 )
 
 // MockSourceRepository is a mock of the source repository for testing
@@ -15,6 +25,7 @@ type MockSourceRepository struct {
 	findModelByCodeFunc      func(ctx context.Context, code string) (product.ProductModel, error)
 	findProductsByParentFunc func(ctx context.Context, parentCode string) ([]product.Product, error)
 	findModelsByParentFunc   func(ctx context.Context, parentCode string) ([]product.ProductModel, error)
+	downloadMediaFileFunc    func(ctx context.Context, code string) (product.MediaFile, error)
 }
 
 func (m *MockSourceRepository) FindByIdentifier(ctx context.Context, identifier string) (product.Product, error) {
@@ -53,22 +64,43 @@ func (m *MockSourceRepository) FindModelsUpdatedSince(ctx context.Context, updat
 	return []product.ProductModel{}, nil
 }
 
-func (m *MockSourceRepository) StreamProductsUpdatedSince(ctx context.Context, updatedSince string, batchSize int, callback func([]product.Product) error) error {
+func (m *MockSourceRepository) StreamProductsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]product.Product) error) error {
 	return nil
 }
 
-func (m *MockSourceRepository) StreamModelsUpdatedSince(ctx context.Context, updatedSince string, batchSize int, callback func([]product.ProductModel) error) error {
+func (m *MockSourceRepository) StreamModelsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]product.ProductModel) error) error {
 	return nil
 }
 
+func (m *MockSourceRepository) StreamProductsBySearch(ctx context.Context, search string, batchSize int, callback func([]product.Product) error) error {
+	return nil
+}
+
+func (m *MockSourceRepository) CountUpdatedSince(ctx context.Context, updatedSince, updatedUntil string) (int, error) {
+	return 0, nil
+}
+
+func (m *MockSourceRepository) CountProductsBySearch(ctx context.Context, search string) (int, error) {
+	return 0, nil
+}
+
+func (m *MockSourceRepository) DownloadMediaFile(ctx context.Context, code string) (product.MediaFile, error) {
+	if m.downloadMediaFileFunc != nil {
+		return m.downloadMediaFileFunc(ctx, code)
+	}
+	return product.MediaFile{Code: code, Filename: code, Content: []byte(code)}, nil
+}
+
 // MockDestRepository is a mock of the destination repository for testing
 type MockDestRepository struct {
 	findByIdentifierFunc     func(ctx context.Context, identifier string) (product.Product, error)
 	saveFunc                 func(ctx context.Context, identifier string, productData product.Product) error
 	findModelByCodeFunc      func(ctx context.Context, code string) (product.ProductModel, error)
 	saveModelFunc            func(ctx context.Context, code string, model product.ProductModel) error
+	saveAllFunc              func(ctx context.Context, products []product.Product) (map[string]error, error)
 	findProductsByParentFunc func(ctx context.Context, parentCode string) ([]product.Product, error)
 	findModelsByParentFunc   func(ctx context.Context, parentCode string) ([]product.ProductModel, error)
+	uploadMediaFileFunc      func(ctx context.Context, file product.MediaFile, target product.MediaTarget) (string, error)
 }
 
 func (m *MockDestRepository) FindByIdentifier(ctx context.Context, identifier string) (product.Product, error) {
@@ -99,6 +131,32 @@ func (m *MockDestRepository) SaveModel(ctx context.Context, code string, model p
 	return nil
 }
 
+func (m *MockDestRepository) SaveAll(ctx context.Context, products []product.Product) (map[string]error, error) {
+	if m.saveAllFunc != nil {
+		return m.saveAllFunc(ctx, products)
+	}
+
+	failed := map[string]error{}
+	for _, productData := range products {
+		identifier, _ := productData["identifier"].(string)
+		if err := m.Save(ctx, identifier, productData); err != nil {
+			failed[identifier] = err
+		}
+	}
+	return failed, nil
+}
+
+func (m *MockDestRepository) SaveModels(ctx context.Context, models []product.ProductModel) (map[string]error, error) {
+	failed := map[string]error{}
+	for _, model := range models {
+		code, _ := model["code"].(string)
+		if err := m.SaveModel(ctx, code, model); err != nil {
+			failed[code] = err
+		}
+	}
+	return failed, nil
+}
+
 func (m *MockDestRepository) FindProductsByParent(ctx context.Context, parentCode string) ([]product.Product, error) {
 	if m.findProductsByParentFunc != nil {
 		return m.findProductsByParentFunc(ctx, parentCode)
@@ -113,6 +171,13 @@ func (m *MockDestRepository) FindModelsByParent(ctx context.Context, parentCode
 	return []product.ProductModel{}, nil
 }
 
+func (m *MockDestRepository) UploadMediaFile(ctx context.Context, file product.MediaFile, target product.MediaTarget) (string, error) {
+	if m.uploadMediaFileFunc != nil {
+		return m.uploadMediaFileFunc(ctx, file, target)
+	}
+	return "dest/" + file.Code, nil
+}
+
 func TestSync_Success(t *testing.T) {
 	// Arrange
 	mockProduct := product.Product{
@@ -139,7 +204,7 @@ func TestSync_Success(t *testing.T) {
 	service := syncing.NewService(sourceRepo, destRepo)
 
 	// Act
-	result, err := service.Sync(context.Background(), "SKU-123")
+	result, err := service.Sync(context.Background(), "SKU-123", syncing.SyncOptions{})
 
 	// Assert
 	if err != nil {
@@ -171,7 +236,7 @@ func TestSync_SourceError(t *testing.T) {
 	service := syncing.NewService(sourceRepo, destRepo)
 
 	// Act
-	_, err := service.Sync(context.Background(), "SKU-123")
+	_, err := service.Sync(context.Background(), "SKU-123", syncing.SyncOptions{})
 
 	// Assert
 	if err == nil {
@@ -201,10 +266,1731 @@ func TestSync_DestError(t *testing.T) {
 	service := syncing.NewService(sourceRepo, destRepo)
 
 	// Act
-	_, err := service.Sync(context.Background(), "SKU-123")
+	_, err := service.Sync(context.Background(), "SKU-123", syncing.SyncOptions{})
 
 	// Assert
 	if err == nil {
 		t.Error("Expected error, got nil")
 	}
 }
+
+func TestSync_ValuesOnlyForExistingProducts(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return product.Product{
+				"identifier": identifier,
+				"enabled":    false,
+				"categories": []interface{}{"shoes"},
+				"values":     map[string]interface{}{"name": []interface{}{}},
+			}, nil
+		},
+		findProductsByParentFunc: func(ctx context.Context, parentCode string) ([]product.Product, error) {
+			return []product.Product{{"identifier": "NEW-001", "enabled": true, "values": map[string]interface{}{}}}, nil
+		},
+	}
+
+	saved := map[string]product.Product{}
+	destRepo := &MockDestRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			if identifier == "NEW-001" {
+				return nil, product.ErrNotFound
+			}
+			return product.Product{"identifier": identifier}, nil
+		},
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			saved[identifier] = productData
+			return nil
+		},
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo)
+	_, err := service.Sync(context.Background(), "COMMON-001", syncing.SyncOptions{ValuesOnly: true})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	existing := saved["COMMON-001"]
+	if len(existing) != 2 || existing["values"] == nil {
+		t.Errorf("Expected only identifier and values for existing product, got %v", existing)
+	}
+
+	if _, hasEnabled := saved["NEW-001"]["enabled"]; !hasEnabled {
+		t.Errorf("Expected full payload for new product, got %v", saved["NEW-001"])
+	}
+}
+
+func TestSync_ValuesOnlyFailsItemWhenDestinationCannotBeRead(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findProductsByParentFunc: func(ctx context.Context, parentCode string) ([]product.Product, error) {
+			return []product.Product{{"identifier": "SKU-1", "enabled": true, "values": map[string]interface{}{}}}, nil
+		},
+	}
+
+	saved := map[string]product.Product{}
+	destRepo := &MockDestRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			if identifier == "SKU-1" {
+				return nil, errors.New("connection reset by peer")
+			}
+			return product.Product{"identifier": identifier}, nil
+		},
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			saved[identifier] = productData
+			return nil
+		},
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background(), "COMMON-001", syncing.SyncOptions{ValuesOnly: true})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Writing the full payload would overwrite destination fields values-only must keep
+	if _, written := saved["SKU-1"]; written {
+		t.Errorf("Expected SKU-1 not to be written, got %v", saved["SKU-1"])
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Code != "SKU-1" {
+		t.Errorf("Expected SKU-1 to be reported, got %v", result.Errors)
+	}
+}
+
+func TestSync_FieldStrategiesForExistingProducts(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return product.Product{
+				"identifier": identifier,
+				"enabled":    false,
+				"categories": []interface{}{"shoes"},
+				"values": map[string]interface{}{
+					"name": []interface{}{map[string]interface{}{"locale": "en_US", "scope": nil, "data": "Boot"}},
+				},
+			}, nil
+		},
+	}
+
+	var saved product.Product
+	destRepo := &MockDestRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return product.Product{
+				"identifier": identifier,
+				"enabled":    true,
+				"categories": []interface{}{"sale"},
+				"values": map[string]interface{}{
+					"name":  []interface{}{map[string]interface{}{"locale": "fr_FR", "scope": nil, "data": "Botte"}},
+					"promo": []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "yes"}},
+				},
+			}, nil
+		},
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			saved = productData
+			return nil
+		},
+	}
+
+	strategies, err := syncing.ParseFieldStrategies(map[string]string{
+		"values":     "overwrite",
+		"categories": "merge",
+		"enabled":    "keep",
+	})
+	if err != nil {
+		t.Fatalf("Expected valid strategies, got %v", err)
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithFieldStrategies(strategies))
+	if _, err := service.Sync(context.Background(), "COMMON-001", syncing.SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, hasEnabled := saved["enabled"]; hasEnabled {
+		t.Errorf("Expected enabled to be kept in destination, got %v", saved["enabled"])
+	}
+
+	categories := saved["categories"].([]interface{})
+	if len(categories) != 2 {
+		t.Errorf("Expected merged categories, got %v", categories)
+	}
+
+	values := saved["values"].(map[string]interface{})
+	if len(values["name"].([]interface{})) != 2 {
+		t.Errorf("Expected source name plus cleared fr_FR name, got %v", values["name"])
+	}
+	promo := values["promo"].([]interface{})[0].(map[string]interface{})
+	if promo["data"] != nil {
+		t.Errorf("Expected destination-only value to be cleared, got %v", promo)
+	}
+}
+
+func TestSync_OverwriteKeepsValuesLeftOutOnPurpose(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return product.Product{
+				"identifier": identifier,
+				"values": map[string]interface{}{
+					"name":     []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "Boot"}},
+					"erp_code": []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "E-2"}},
+				},
+			}, nil
+		},
+	}
+
+	var saved product.Product
+	destRepo := &MockDestRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return product.Product{
+				"identifier": identifier,
+				"values": map[string]interface{}{
+					"name":     []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "Old boot"}},
+					"erp_code": []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "E-1"}},
+					"promo":    []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "yes"}},
+				},
+			}, nil
+		},
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			saved = productData
+			return nil
+		},
+	}
+
+	valueFilter, err := filter.New(filter.Rules{ExcludedAttributes: []string{"erp_code"}})
+	if err != nil {
+		t.Fatalf("Expected valid rules, got %v", err)
+	}
+	strategies, err := syncing.ParseFieldStrategies(map[string]string{"values": "overwrite"})
+	if err != nil {
+		t.Fatalf("Expected valid strategies, got %v", err)
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithFieldStrategies(strategies), syncing.WithValueFilter(valueFilter))
+	if _, err := service.Sync(context.Background(), "COMMON-001", syncing.SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	values := saved["values"].(map[string]interface{})
+	if _, sent := values["erp_code"]; sent {
+		t.Errorf("Expected the excluded attribute to be left as it is in destination, got %v", values["erp_code"])
+	}
+	promo, _ := values["promo"].([]interface{})
+	if len(promo) != 1 || promo[0].(map[string]interface{})["data"] != nil {
+		t.Errorf("Expected the destination-only value to be cleared, got %v", values["promo"])
+	}
+}
+
+func TestParseFieldStrategies_Invalid(t *testing.T) {
+	if _, err := syncing.ParseFieldStrategies(map[string]string{"family": "keep"}); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+
+	if _, err := syncing.ParseFieldStrategies(map[string]string{"values": "replace"}); err == nil {
+		t.Error("Expected error for unknown strategy")
+	}
+}
+
+func TestSync_AnonymizesValues(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return product.Product{
+				"identifier": identifier,
+				"values": map[string]interface{}{
+					"supplier_cost": []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "12.50"}},
+				},
+			}, nil
+		},
+	}
+
+	var saved product.Product
+	destRepo := &MockDestRepository{
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			saved = productData
+			return nil
+		},
+	}
+
+	anonymizer, err := anonymize.New([]anonymize.Rule{{Attribute: "supplier_cost", Action: anonymize.Drop}}, "")
+	if err != nil {
+		t.Fatalf("Expected valid rules, got %v", err)
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithAnonymizer(anonymizer))
+	if _, err := service.Sync(context.Background(), "COMMON-001", syncing.SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, exists := saved["values"].(map[string]interface{})["supplier_cost"]; exists {
+		t.Errorf("Expected supplier_cost to be dropped, got %v", saved["values"])
+	}
+}
+
+func TestSync_TransformsValues(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return product.Product{
+				"identifier": identifier,
+				"values": map[string]interface{}{
+					"name": []interface{}{map[string]interface{}{"locale": "en_US", "scope": nil, "data": "Boot"}},
+				},
+			}, nil
+		},
+	}
+
+	var saved product.Product
+	destRepo := &MockDestRepository{
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			saved = productData
+			return nil
+		},
+	}
+
+	transformer, err := transform.New([]transform.Rule{{Attribute: "name", Expression: "upper(value)"}})
+	if err != nil {
+		t.Fatalf("Expected valid rules, got %v", err)
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithTransformer(transformer))
+	if _, err := service.Sync(context.Background(), "COMMON-001", syncing.SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	name := saved["values"].(map[string]interface{})["name"].([]interface{})[0].(map[string]interface{})
+	if name["data"] != "BOOT" {
+		t.Errorf("Expected transformed name, got %v", name["data"])
+	}
+}
+
+func TestSync_DropsValuesInDisabledLocales(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return product.Product{
+				"identifier": identifier,
+				"values": map[string]interface{}{
+					"name": []interface{}{
+						map[string]interface{}{"locale": "en_US", "scope": nil, "data": "Boot"},
+						map[string]interface{}{"locale": "de_DE", "scope": nil, "data": "Stiefel"},
+					},
+				},
+			}, nil
+		},
+	}
+
+	var saved product.Product
+	destRepo := &MockDestRepository{
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			saved = productData
+			return nil
+		},
+	}
+
+	destLocales := func(ctx context.Context) (map[string]bool, error) {
+		return map[string]bool{"en_US": true, "de_DE": false}, nil
+	}
+
+	// Fail: the product is rejected before it is sent
+	failing := syncing.NewService(sourceRepo, destRepo, syncing.WithLocaleChecker(locales.NewChecker(destLocales, locales.Fail)))
+	if _, err := failing.Sync(context.Background(), "COMMON-001", syncing.SyncOptions{}); err == nil || !strings.Contains(err.Error(), "de_DE") {
+		t.Errorf("Expected an error listing de_DE, got %v", err)
+	}
+	if saved != nil {
+		t.Error("Expected nothing to be written")
+	}
+
+	// Drop: the product is written without the de_DE value
+	dropping := syncing.NewService(sourceRepo, destRepo, syncing.WithLocaleChecker(locales.NewChecker(destLocales, locales.Drop)))
+	if _, err := dropping.Sync(context.Background(), "COMMON-001", syncing.SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	names := saved["values"].(map[string]interface{})["name"].([]interface{})
+	if len(names) != 1 || names[0].(map[string]interface{})["locale"] != "en_US" {
+		t.Errorf("Expected only the en_US name to be sent, got %v", names)
+	}
+}
+
+func TestSync_StripsAttributesMissingInDestination(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return product.Product{
+				"identifier": identifier,
+				"values": map[string]interface{}{
+					"name":       []interface{}{map[string]interface{}{"locale": "en_US", "scope": nil, "data": "Boot"}},
+					"legacy_ref": []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "X-1"}},
+				},
+			}, nil
+		},
+	}
+
+	var saved product.Product
+	destRepo := &MockDestRepository{
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			saved = productData
+			return nil
+		},
+	}
+
+	destAttributes := func(ctx context.Context) (map[string]bool, error) {
+		return map[string]bool{"name": true}, nil
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithAttributeChecker(attributes.NewChecker(destAttributes)))
+	result, err := service.Sync(context.Background(), "COMMON-001", syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	values := saved["values"].(map[string]interface{})
+	if _, exists := values["legacy_ref"]; exists || values["name"] == nil {
+		t.Errorf("Expected only the name to be sent, got %v", values)
+	}
+
+	expected := []syncing.DroppedAttributes{{Item: "product COMMON-001", Attributes: []string{"legacy_ref"}}}
+	if !reflect.DeepEqual(result.DroppedAttributes, expected) {
+		t.Errorf("Expected %v, got %v", expected, result.DroppedAttributes)
+	}
+}
+
+func TestSync_FiltersValuesByLocale(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return product.Product{
+				"identifier": identifier,
+				"values": map[string]interface{}{
+					"name": []interface{}{
+						map[string]interface{}{"locale": "en_US", "scope": nil, "data": "Boot"},
+						map[string]interface{}{"locale": "de_DE", "scope": nil, "data": "Stiefel"},
+					},
+					"weight": []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "12"}},
+				},
+			}, nil
+		},
+	}
+
+	var saved product.Product
+	destRepo := &MockDestRepository{
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			saved = productData
+			return nil
+		},
+	}
+
+	destLocales := func(ctx context.Context) (map[string]bool, error) {
+		return map[string]bool{"en_US": true}, nil
+	}
+
+	valueFilter, err := filter.New(filter.Rules{Locales: []string{"en_US"}})
+	if err != nil {
+		t.Fatalf("Expected valid rules, got %v", err)
+	}
+
+	// Filtered locales never reach the locale check, so the Fail policy accepts the product
+	service := syncing.NewService(sourceRepo, destRepo,
+		syncing.WithValueFilter(valueFilter),
+		syncing.WithLocaleChecker(locales.NewChecker(destLocales, locales.Fail)),
+	)
+	if _, err := service.Sync(context.Background(), "COMMON-001", syncing.SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	values := saved["values"].(map[string]interface{})
+	if names := values["name"].([]interface{}); len(names) != 1 || names[0].(map[string]interface{})["locale"] != "en_US" {
+		t.Errorf("Expected only the en_US name to be sent, got %v", names)
+	}
+	if _, exists := values["weight"]; !exists {
+		t.Error("Expected non-localizable values to be sent")
+	}
+}
+
+func TestSaveModel_FiltersValuesByChannel(t *testing.T) {
+	var saved product.ProductModel
+	destRepo := &MockDestRepository{
+		saveModelFunc: func(ctx context.Context, code string, model product.ProductModel) error {
+			saved = model
+			return nil
+		},
+	}
+
+	valueFilter, err := filter.New(filter.Rules{Channels: []string{"ecommerce=web"}})
+	if err != nil {
+		t.Fatalf("Expected valid rules, got %v", err)
+	}
+
+	service := syncing.NewService(&MockSourceRepository{}, destRepo, syncing.WithValueFilter(valueFilter))
+	model := product.ProductModel{
+		"code": "MODEL-001",
+		"values": map[string]interface{}{
+			"description": []interface{}{
+				map[string]interface{}{"locale": "en_US", "scope": "ecommerce", "data": "Boot"},
+				map[string]interface{}{"locale": "en_US", "scope": "print", "data": "Boot"},
+			},
+		},
+	}
+	if _, err := service.SaveModel(context.Background(), "MODEL-001", model, syncing.SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	descriptions := saved["values"].(map[string]interface{})["description"].([]interface{})
+	if len(descriptions) != 1 || descriptions[0].(map[string]interface{})["scope"] != "web" {
+		t.Errorf("Expected only the ecommerce description to be sent as web, got %v", descriptions)
+	}
+}
+
+// mockAssociationTypes records the association types the service asked for
+type mockAssociationTypes struct {
+	requested []string
+	err       error
+}
+
+func (m *mockAssociationTypes) EnsureExists(ctx context.Context, codes []string) ([]string, error) {
+	m.requested = append(m.requested, codes...)
+	return codes, m.err
+}
+
+func TestSync_EnsuresAssociationTypes(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return product.Product{
+				"identifier": identifier,
+				"associations": map[string]interface{}{
+					"X_SELL": map[string]interface{}{"products": []interface{}{"SKU-2"}},
+				},
+				"quantified_associations": map[string]interface{}{
+					"PACK": map[string]interface{}{"products": []interface{}{}},
+				},
+			}, nil
+		},
+	}
+
+	saves := 0
+	destRepo := &MockDestRepository{
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			saves++
+			return nil
+		},
+	}
+
+	associationTypes := &mockAssociationTypes{}
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithAssociationTypes(associationTypes))
+	if _, err := service.Sync(context.Background(), "COMMON-001", syncing.SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(associationTypes.requested) != 2 || associationTypes.requested[0] != "PACK" || associationTypes.requested[1] != "X_SELL" {
+		t.Errorf("Expected PACK and X_SELL to be ensured, got %v", associationTypes.requested)
+	}
+
+	// The product is not written when an association type cannot be created
+	associationTypes.err = errors.New("not found in source")
+	if _, err := service.Sync(context.Background(), "COMMON-001", syncing.SyncOptions{}); err == nil {
+		t.Error("Expected an error when the association type cannot be created")
+	}
+	if saves != 1 {
+		t.Errorf("Expected 1 save, got %d", saves)
+	}
+}
+
+// mockOptionCreator creates the options of a fixed source catalog
+type mockOptionCreator struct {
+	source  map[string]bool
+	created map[string]bool
+}
+
+func (m *mockOptionCreator) CreateOption(ctx context.Context, attributeCode, optionCode string) error {
+	if !m.source[attributeCode+"."+optionCode] {
+		return fmt.Errorf("option %s of attribute %s does not exist in source", optionCode, attributeCode)
+	}
+	m.created[attributeCode+"."+optionCode] = true
+	return nil
+}
+
+func TestSync_CreatesMissingOptions(t *testing.T) {
+	selectValue := func(data interface{}) []interface{} {
+		return []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": data}}
+	}
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return product.Product{"identifier": identifier, "values": map[string]interface{}{"color": selectValue("navy")}}, nil
+		},
+		findProductsByParentFunc: func(ctx context.Context, parentCode string) ([]product.Product, error) {
+			return []product.Product{
+				{"identifier": "SKU-1-A", "values": map[string]interface{}{"sizes": selectValue([]interface{}{"xl", "xxl"})}},
+				{"identifier": "SKU-1-B", "values": map[string]interface{}{"color": selectValue("teal")}},
+			}, nil
+		},
+	}
+
+	options := &mockOptionCreator{
+		source:  map[string]bool{"color.navy": true, "sizes.xl": true, "sizes.xxl": true},
+		created: map[string]bool{},
+	}
+	writes := map[string]int{}
+	destRepo := &MockDestRepository{
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			writes[identifier]++
+			values, _ := productData["values"].(map[string]interface{})
+			switch {
+			case values["color"] != nil && identifier == "SKU-1" && !options.created["color.navy"]:
+				return errors.New(`validation error in product SKU-1: Property "color" expects a valid code. The option "navy" does not exist. Check the expected format on the API documentation.`)
+			case values["color"] != nil && identifier == "SKU-1-B":
+				return errors.New(`validation error in product SKU-1-B: Property "color" expects a valid code. The option "teal" does not exist.`)
+			case values["sizes"] != nil && !options.created["sizes.xxl"]:
+				return errors.New(`validation error in product SKU-1-A: Property "sizes" expects valid codes. The following options do not exist: "xl,xxl".`)
+			}
+			return nil
+		},
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithMissingOptions(options))
+	result, err := service.Sync(context.Background(), "SKU-1", syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(options.created, map[string]bool{"color.navy": true, "sizes.xl": true, "sizes.xxl": true}) {
+		t.Errorf("Expected the options missing in destination to be created, got %v", options.created)
+	}
+	if writes["SKU-1"] != 2 || writes["SKU-1-A"] != 2 {
+		t.Errorf("Expected the rejected products to be written again once, got %v", writes)
+	}
+	if result.ProductsSynced != 2 {
+		t.Errorf("Expected 2 products synced, got %d", result.ProductsSynced)
+	}
+
+	// An option missing in source cannot be created, and the product fails with the destination error
+	if len(result.Errors) != 1 || result.Errors[0].Code != "SKU-1-B" || !strings.Contains(result.Errors[0].Message, `The option "teal" does not exist`) {
+		t.Errorf("Expected SKU-1-B to be reported, got %v", result.Errors)
+	}
+	if writes["SKU-1-B"] != 1 {
+		t.Errorf("Expected SKU-1-B to be written once, got %d", writes["SKU-1-B"])
+	}
+}
+
+func TestSync_SavesChildrenInBatches(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return nil, errors.New("not a product")
+		},
+		findModelsByParentFunc: func(ctx context.Context, parentCode string) ([]product.ProductModel, error) {
+			if parentCode != "COMMON-001" {
+				return []product.ProductModel{}, nil
+			}
+			return []product.ProductModel{{"code": "MODEL-001"}, {"code": "MODEL-002"}}, nil
+		},
+		findProductsByParentFunc: func(ctx context.Context, parentCode string) ([]product.Product, error) {
+			if parentCode == "COMMON-001" {
+				return []product.Product{}, nil
+			}
+			return []product.Product{{"identifier": parentCode + "-S"}, {"identifier": parentCode + "-M"}}, nil
+		},
+	}
+
+	var batches [][]product.Product
+	destRepo := &MockDestRepository{
+		saveAllFunc: func(ctx context.Context, products []product.Product) (map[string]error, error) {
+			batches = append(batches, products)
+			return map[string]error{"MODEL-002-M": errors.New("validation error")}, nil
+		},
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background(), "COMMON-001", syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(batches) != 1 || len(batches[0]) != 4 {
+		t.Fatalf("Expected the 4 variants to be saved in one batch, got %v", batches)
+	}
+
+	if result.ProductsSynced != 3 || result.ModelsSynced != 3 {
+		t.Errorf("Expected 3 products and 3 models synced, got %d and %d", result.ProductsSynced, result.ModelsSynced)
+	}
+
+	if len(result.Errors) != 1 || result.Errors[0].Code != "MODEL-002-M" || result.Errors[0].Kind != syncing.KindProduct {
+		t.Errorf("Expected rejected variant to be reported, got %v", result.Errors)
+	}
+}
+
+func TestSync_WalksDeepModelTrees(t *testing.T) {
+	// Models and products hang at every level, deeper than the usual root → sub-model → variant
+	modelsByParent := map[string][]product.ProductModel{
+		"ROOT":    {{"code": "SUB-1"}, {"code": "SUB-2"}},
+		"SUB-1":   {{"code": "SUB-1-A"}},
+		"SUB-1-A": {{"code": "SUB-1-A-X"}, {"code": "ROOT"}},
+	}
+	productsByParent := map[string][]product.Product{
+		"ROOT":      {{"identifier": "ROOT-P"}},
+		"SUB-2":     {{"identifier": "SUB-2-P"}},
+		"SUB-1-A-X": {{"identifier": "SUB-1-A-X-P"}},
+	}
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return nil, errors.New("not a product")
+		},
+		findModelByCodeFunc: func(ctx context.Context, code string) (product.ProductModel, error) {
+			return product.ProductModel{"code": code}, nil
+		},
+		findModelsByParentFunc: func(ctx context.Context, parentCode string) ([]product.ProductModel, error) {
+			return modelsByParent[parentCode], nil
+		},
+		findProductsByParentFunc: func(ctx context.Context, parentCode string) ([]product.Product, error) {
+			return productsByParent[parentCode], nil
+		},
+	}
+
+	var order []string
+	destRepo := &MockDestRepository{
+		saveModelFunc: func(ctx context.Context, code string, model product.ProductModel) error {
+			order = append(order, code)
+			return nil
+		},
+		saveAllFunc: func(ctx context.Context, products []product.Product) (map[string]error, error) {
+			for _, prod := range products {
+				order = append(order, prod["identifier"].(string))
+			}
+			return nil, nil
+		},
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background(), "ROOT", syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Level by level, the model listed again under its own descendant being skipped
+	expected := "ROOT,SUB-1,SUB-2,ROOT-P,SUB-1-A,SUB-2-P,SUB-1-A-X,SUB-1-A-X-P"
+	if strings.Join(order, ",") != expected {
+		t.Errorf("Expected %s to be written, got %v", expected, order)
+	}
+	if result.ModelsSynced != 5 || result.ProductsSynced != 3 {
+		t.Errorf("Expected 5 models and 3 products synced, got %d and %d", result.ModelsSynced, result.ProductsSynced)
+	}
+}
+
+func TestSync_SkipsDescendantsOfFailedModels(t *testing.T) {
+	modelsByParent := map[string][]product.ProductModel{
+		"ROOT":  {{"code": "SUB-2"}, {"code": "SUB-1"}},
+		"SUB-1": {{"code": "SUB-1-A"}},
+	}
+	productsByParent := map[string][]product.Product{
+		"SUB-1":   {{"identifier": "SUB-1-P"}},
+		"SUB-1-A": {{"identifier": "SUB-1-A-P"}},
+		"SUB-2":   {{"identifier": "SUB-2-P"}},
+	}
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return nil, errors.New("not a product")
+		},
+		findModelByCodeFunc: func(ctx context.Context, code string) (product.ProductModel, error) {
+			return product.ProductModel{"code": code}, nil
+		},
+		findModelsByParentFunc: func(ctx context.Context, parentCode string) ([]product.ProductModel, error) {
+			return modelsByParent[parentCode], nil
+		},
+		findProductsByParentFunc: func(ctx context.Context, parentCode string) ([]product.Product, error) {
+			return productsByParent[parentCode], nil
+		},
+	}
+
+	var written []string
+	destRepo := &MockDestRepository{
+		saveModelFunc: func(ctx context.Context, code string, model product.ProductModel) error {
+			if code == "SUB-1" {
+				return errors.New("validation error")
+			}
+			written = append(written, code)
+			return nil
+		},
+		saveAllFunc: func(ctx context.Context, products []product.Product) (map[string]error, error) {
+			for _, prod := range products {
+				written = append(written, prod["identifier"].(string))
+			}
+			return nil, nil
+		},
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background(), "ROOT", syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Siblings are written in code order, nothing under SUB-1 is sent
+	if strings.Join(written, ",") != "ROOT,SUB-2,SUB-2-P" {
+		t.Errorf("Expected ROOT, SUB-2, SUB-2-P to be written, got %v", written)
+	}
+
+	var skipped []string
+	for _, syncErr := range result.Errors {
+		if syncErr.FailedModel != "" {
+			skipped = append(skipped, syncErr.Kind+" "+syncErr.Code+" under "+syncErr.FailedModel)
+		}
+	}
+	expected := []string{"product_model SUB-1-A under SUB-1", "product SUB-1-P under SUB-1", "product SUB-1-A-P under SUB-1"}
+	if !reflect.DeepEqual(skipped, expected) {
+		t.Errorf("Expected %v, got %v", expected, skipped)
+	}
+	if len(result.Errors) != 4 {
+		t.Errorf("Expected the failed model and its 3 descendants to be reported, got %v", result.Errors)
+	}
+}
+
+func TestSync_DryRunRecordsWrites(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return nil, errors.New("not a product")
+		},
+		findModelsByParentFunc: func(ctx context.Context, parentCode string) ([]product.ProductModel, error) {
+			if parentCode != "COMMON-001" {
+				return []product.ProductModel{}, nil
+			}
+			return []product.ProductModel{{"code": "MODEL-001"}}, nil
+		},
+		findProductsByParentFunc: func(ctx context.Context, parentCode string) ([]product.Product, error) {
+			if parentCode == "COMMON-001" {
+				return []product.Product{}, nil
+			}
+			return []product.Product{{"identifier": parentCode + "-S", "values": map[string]interface{}{"picture": mediaValue("a/b/shoe.jpg")}}}, nil
+		},
+	}
+
+	writes := 0
+	destRepo := &MockDestRepository{
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			writes++
+			return nil
+		},
+		saveModelFunc: func(ctx context.Context, code string, model product.ProductModel) error {
+			writes++
+			return nil
+		},
+		uploadMediaFileFunc: func(ctx context.Context, file product.MediaFile, target product.MediaTarget) (string, error) {
+			writes++
+			return "", nil
+		},
+	}
+
+	result, err := syncing.NewService(sourceRepo, destRepo).Sync(dryrun.With(context.Background()), "COMMON-001", syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if writes != 0 {
+		t.Errorf("Expected nothing written to destination, got %d writes", writes)
+	}
+
+	var planned []string
+	for _, write := range result.PlannedWrites() {
+		planned = append(planned, write.Kind+" "+write.Scope+"/"+write.Code)
+	}
+	expected := []string{
+		"product_model /COMMON-001",
+		"product_model /MODEL-001",
+		"product /MODEL-001-S",
+		"media_file MODEL-001-S/a/b/shoe.jpg",
+	}
+	if strings.Join(planned, ", ") != strings.Join(expected, ", ") {
+		t.Errorf("Expected planned writes %v, got %v", expected, planned)
+	}
+	if result.TotalSynced != 3 {
+		t.Errorf("Expected the planned items to be counted, got %d", result.TotalSynced)
+	}
+}
+
+// mediaValue returns an image value as exposed by the API, with its download link
+func mediaValue(code string) []interface{} {
+	return []interface{}{map[string]interface{}{
+		"locale": nil,
+		"scope":  nil,
+		"data":   code,
+		"_links": map[string]interface{}{
+			"download": map[string]interface{}{"href": "http://source/api/rest/v1/media-files/" + code + "/download"},
+		},
+	}}
+}
+
+func TestSync_CopiesMediaFiles(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return product.Product{
+				"identifier": identifier,
+				"values": map[string]interface{}{
+					"picture": mediaValue("a/b/1234_shoe.jpg"),
+					"name":    []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "Shoe"}},
+				},
+			}, nil
+		},
+	}
+
+	var saved []product.Product
+	var targets []product.MediaTarget
+	destRepo := &MockDestRepository{
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			saved = append(saved, productData)
+			return nil
+		},
+		uploadMediaFileFunc: func(ctx context.Context, file product.MediaFile, target product.MediaTarget) (string, error) {
+			targets = append(targets, target)
+			return "c/d/5678_shoe.jpg", nil
+		},
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo)
+	if _, err := service.Sync(context.Background(), "SKU-1", syncing.SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The file does not exist yet in destination: the product is written without it, then the file is uploaded
+	values := saved[0]["values"].(map[string]interface{})
+	if _, ok := values["picture"]; ok {
+		t.Errorf("Expected the media value to be left out of the payload, got %v", values["picture"])
+	}
+	if _, ok := values["name"]; !ok {
+		t.Error("Expected other values to be kept")
+	}
+	if len(targets) != 1 || targets[0].Identifier != "SKU-1" || targets[0].Attribute != "picture" {
+		t.Fatalf("Expected the file to be uploaded to SKU-1 picture, got %v", targets)
+	}
+
+	// A file already copied is referenced by its destination code
+	if _, err := service.Sync(context.Background(), "SKU-2", syncing.SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	picture := saved[1]["values"].(map[string]interface{})["picture"].([]interface{})
+	if data := picture[0].(map[string]interface{})["data"]; data != "c/d/5678_shoe.jpg" {
+		t.Errorf("Expected the destination file code, got %v", data)
+	}
+	if len(targets) != 1 {
+		t.Errorf("Expected the file to be uploaded once, got %d uploads", len(targets))
+	}
+}
+
+func TestSync_ReportsMediaFileErrors(t *testing.T) {
+	downloads := 0
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return nil, errors.New("not a product")
+		},
+		findModelsByParentFunc: func(ctx context.Context, parentCode string) ([]product.ProductModel, error) {
+			if parentCode != "COMMON-001" {
+				return nil, nil
+			}
+			return []product.ProductModel{{"code": "MODEL-001", "values": map[string]interface{}{"picture": mediaValue("missing.jpg")}}}, nil
+		},
+		downloadMediaFileFunc: func(ctx context.Context, code string) (product.MediaFile, error) {
+			downloads++
+			return product.MediaFile{}, errors.New("not found")
+		},
+	}
+
+	service := syncing.NewService(sourceRepo, &MockDestRepository{}, syncing.WithMediaRetries(2, 0))
+	result, err := service.Sync(context.Background(), "COMMON-001", syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.Errors) != 1 || result.Errors[0].Code != "MODEL-001" || result.Errors[0].Kind != syncing.KindProductModel {
+		t.Errorf("Expected the model to be reported, got %v", result.Errors)
+	}
+	if downloads != 3 {
+		t.Errorf("Expected the download to be tried 3 times, got %d", downloads)
+	}
+}
+
+func TestSync_RetriesFailedMediaTransfers(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return product.Product{"identifier": identifier, "values": map[string]interface{}{"picture": mediaValue("a/b/1234_shoe.jpg")}}, nil
+		},
+	}
+
+	uploads := 0
+	destRepo := &MockDestRepository{
+		uploadMediaFileFunc: func(ctx context.Context, file product.MediaFile, target product.MediaTarget) (string, error) {
+			uploads++
+			if uploads == 1 {
+				return "", errors.New("connection reset by peer")
+			}
+			return "c/d/5678_shoe.jpg", nil
+		},
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithMediaRetries(1, 0))
+	result, err := service.Sync(context.Background(), "SKU-1", syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.Errors) != 0 || result.ProductsSynced != 1 || uploads != 2 {
+		t.Errorf("Expected the product to be synced on the second upload, got %v after %d uploads", result.Errors, uploads)
+	}
+}
+
+func TestSync_SkipsMediaValues(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return product.Product{
+				"identifier": identifier,
+				"values": map[string]interface{}{
+					"picture": mediaValue("a/b/1234_shoe.jpg"),
+					"name":    []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "Shoe"}},
+				},
+			}, nil
+		},
+		downloadMediaFileFunc: func(ctx context.Context, code string) (product.MediaFile, error) {
+			t.Errorf("Expected no download, got %s", code)
+			return product.MediaFile{}, nil
+		},
+	}
+
+	var saved product.Product
+	destRepo := &MockDestRepository{
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			saved = productData
+			return nil
+		},
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithoutMedia())
+	if _, err := service.Sync(context.Background(), "SKU-1", syncing.SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	values := saved["values"].(map[string]interface{})
+	if _, ok := values["picture"]; ok {
+		t.Errorf("Expected the media value to be left out, got %v", values["picture"])
+	}
+	if _, ok := values["name"]; !ok {
+		t.Error("Expected other values to be kept")
+	}
+}
+
+// mockUUIDRepository records the products written by UUID
+type mockUUIDRepository struct {
+	existing map[string]string
+	lookups  [][]string
+	saved    []product.Product
+	rejected string
+}
+
+func (m *mockUUIDRepository) FindUUIDs(ctx context.Context, identifiers []string) (map[string]string, error) {
+	m.lookups = append(m.lookups, identifiers)
+	found := map[string]string{}
+	for _, identifier := range identifiers {
+		if uuid, ok := m.existing[identifier]; ok {
+			found[identifier] = uuid
+		}
+	}
+	return found, nil
+}
+
+func (m *mockUUIDRepository) SaveByUUID(ctx context.Context, uuid string, productData product.Product) error {
+	m.saved = append(m.saved, productData)
+	return nil
+}
+
+func (m *mockUUIDRepository) SaveAllByUUID(ctx context.Context, products []product.Product) (map[string]error, error) {
+	m.saved = append(m.saved, products...)
+	failed := map[string]error{}
+	for _, productData := range products {
+		if productData["identifier"] == m.rejected {
+			failed[productData["uuid"].(string)] = errors.New("validation error")
+		}
+	}
+	return failed, nil
+}
+
+func TestSync_WritesProductsByUUID(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findProductsByParentFunc: func(ctx context.Context, parentCode string) ([]product.Product, error) {
+			return []product.Product{{"identifier": "SKU-1-S"}, {"identifier": "SKU-1-M"}}, nil
+		},
+	}
+	destRepo := &MockDestRepository{
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			t.Errorf("Expected products to be written by UUID, got %s by identifier", identifier)
+			return nil
+		},
+	}
+	uuids := &mockUUIDRepository{
+		existing: map[string]string{"SKU-1": "11111111-1111-4111-8111-111111111111"},
+		rejected: "SKU-1-M",
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithProductUUIDs(uuids))
+	result, err := service.Sync(context.Background(), "SKU-1", syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(uuids.saved) != 3 || uuids.saved[0]["uuid"] != "11111111-1111-4111-8111-111111111111" {
+		t.Fatalf("Expected the common product to keep its destination UUID, got %v", uuids.saved)
+	}
+
+	// Products missing in destination get a new UUID
+	newUUID, _ := uuids.saved[1]["uuid"].(string)
+	if len(newUUID) != 36 || newUUID == uuids.saved[2]["uuid"] {
+		t.Errorf("Expected distinct generated UUIDs, got %v and %v", newUUID, uuids.saved[2]["uuid"])
+	}
+
+	// Failures are reported by identifier
+	if result.ProductsSynced != 2 || len(result.Errors) != 1 || result.Errors[0].Code != "SKU-1-M" {
+		t.Errorf("Expected SKU-1-M to be reported, got %+v", result)
+	}
+
+	// Resolved identifiers are not looked up again
+	if _, err := service.Sync(context.Background(), "SKU-1", syncing.SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(uuids.lookups) != 2 {
+		t.Errorf("Expected 2 lookups, got %v", uuids.lookups)
+	}
+	if uuids.saved[4]["uuid"] != newUUID {
+		t.Errorf("Expected the generated UUID to be reused, got %v", uuids.saved[4]["uuid"])
+	}
+}
+
+// packProduct returns a product linked to other products and models by a quantified association
+func packProduct(identifier string, products, models []string) product.Product {
+	productLinks := make([]interface{}, len(products))
+	for i, code := range products {
+		productLinks[i] = map[string]interface{}{"identifier": code, "quantity": float64(i + 1)}
+	}
+	modelLinks := make([]interface{}, len(models))
+	for i, code := range models {
+		modelLinks[i] = map[string]interface{}{"identifier": code, "quantity": float64(1)}
+	}
+
+	return product.Product{
+		"identifier": identifier,
+		"quantified_associations": map[string]interface{}{
+			"PACK": map[string]interface{}{"products": productLinks, "product_models": modelLinks},
+		},
+	}
+}
+
+// packLinks returns the identifiers linked by the PACK quantified association of a payload
+func packLinks(payload product.Product, kind string) []string {
+	associations := payload["quantified_associations"].(map[string]interface{})
+	entries, _ := associations["PACK"].(map[string]interface{})[kind].([]interface{})
+
+	identifiers := make([]string, len(entries))
+	for i, entry := range entries {
+		identifiers[i], _ = entry.(map[string]interface{})["identifier"].(string)
+	}
+	return identifiers
+}
+
+func TestSync_DropsMissingQuantifiedTargets(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return packProduct(identifier, []string{"SKU-2", "SKU-3"}, []string{"MODEL-1"}), nil
+		},
+	}
+
+	lookups := 0
+	saved := map[string]product.Product{}
+	destRepo := &MockDestRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			lookups++
+			if identifier == "SKU-3" {
+				return nil, product.ErrNotFound
+			}
+			return product.Product{"identifier": identifier}, nil
+		},
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			saved[identifier] = productData
+			return nil
+		},
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithQuantifiedTargets(syncing.TargetDrop))
+	for i := 0; i < 2; i++ {
+		if _, err := service.Sync(context.Background(), "SKU-1", syncing.SyncOptions{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if links := packLinks(saved["SKU-1"], "products"); len(links) != 1 || links[0] != "SKU-2" {
+		t.Errorf("Expected only SKU-2 to stay linked, got %v", links)
+	}
+	if links := packLinks(saved["SKU-1"], "product_models"); len(links) != 1 || links[0] != "MODEL-1" {
+		t.Errorf("Expected MODEL-1 to stay linked, got %v", links)
+	}
+
+	// Existing targets are looked up once, missing ones on every sync
+	if lookups != 3 {
+		t.Errorf("Expected 3 lookups, got %d", lookups)
+	}
+}
+
+func TestSync_FailsWhenQuantifiedTargetLookupFails(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return packProduct(identifier, []string{"SKU-2"}, nil), nil
+		},
+	}
+
+	saved := false
+	destRepo := &MockDestRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			if identifier == "SKU-2" {
+				return nil, errors.New("connection reset")
+			}
+			return product.Product{"identifier": identifier}, nil
+		},
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			saved = true
+			return nil
+		},
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithQuantifiedTargets(syncing.TargetDrop))
+	if _, err := service.Sync(context.Background(), "SKU-1", syncing.SyncOptions{}); err == nil || !strings.Contains(err.Error(), "connection reset") {
+		t.Errorf("Expected the lookup error to fail the product, got %v", err)
+	}
+	if saved {
+		t.Error("Expected the product not to be written")
+	}
+}
+
+func TestSync_SyncsMissingQuantifiedTargets(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			switch identifier {
+			case "SKU-1":
+				return packProduct(identifier, []string{"SKU-2"}, nil), nil
+			case "SKU-2":
+				// The target links back to the product being synced
+				return packProduct(identifier, []string{"SKU-1"}, nil), nil
+			}
+			return nil, errors.New("not found")
+		},
+	}
+
+	var order []string
+	saved := map[string]product.Product{}
+	destRepo := &MockDestRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			if _, ok := saved[identifier]; !ok {
+				return nil, product.ErrNotFound
+			}
+			return saved[identifier], nil
+		},
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			order = append(order, identifier)
+			saved[identifier] = productData
+			return nil
+		},
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithQuantifiedTargets(syncing.TargetSync))
+	if _, err := service.Sync(context.Background(), "SKU-1", syncing.SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The cycle is broken by writing SKU-1 without its link first, then SKU-2, then SKU-1 again
+	if strings.Join(order, ",") != "SKU-1,SKU-2,SKU-1" {
+		t.Errorf("Expected SKU-1, SKU-2, SKU-1 to be written, got %v", order)
+	}
+	if links := packLinks(saved["SKU-1"], "products"); len(links) != 1 || links[0] != "SKU-2" {
+		t.Errorf("Expected SKU-1 to link SKU-2, got %v", links)
+	}
+	if links := packLinks(saved["SKU-2"], "products"); len(links) != 1 || links[0] != "SKU-1" {
+		t.Errorf("Expected SKU-2 to link SKU-1, got %v", links)
+	}
+}
+
+func TestSync_MergesQuantifiedAssociations(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return packProduct(identifier, []string{"SKU-2", "SKU-3"}, nil), nil
+		},
+	}
+
+	var saved product.Product
+	destRepo := &MockDestRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return packProduct(identifier, []string{"SKU-4", "SKU-3"}, nil), nil
+		},
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			saved = productData
+			return nil
+		},
+	}
+
+	strategies, err := syncing.ParseFieldStrategies(map[string]string{"quantified_associations": "merge"})
+	if err != nil {
+		t.Fatalf("Expected valid strategies, got %v", err)
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithFieldStrategies(strategies))
+	if _, err := service.Sync(context.Background(), "SKU-1", syncing.SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	entries := saved["quantified_associations"].(map[string]interface{})["PACK"].(map[string]interface{})["products"].([]interface{})
+	if links := packLinks(saved, "products"); strings.Join(links, ",") != "SKU-2,SKU-3,SKU-4" {
+		t.Fatalf("Expected the union of both links, got %v", links)
+	}
+	// SKU-3 has quantity 2 in source and 1 in destination
+	if quantity := entries[1].(map[string]interface{})["quantity"]; quantity != float64(2) {
+		t.Errorf("Expected the source quantity to win, got %v", quantity)
+	}
+}
+
+func TestSync_LinksQuantifiedProductsByUUID(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return packProduct(identifier, []string{"SKU-2"}, []string{"MODEL-1"}), nil
+		},
+	}
+	uuids := &mockUUIDRepository{
+		existing: map[string]string{"SKU-2": "22222222-2222-4222-8222-222222222222"},
+	}
+
+	service := syncing.NewService(sourceRepo, &MockDestRepository{}, syncing.WithProductUUIDs(uuids))
+	if _, err := service.Sync(context.Background(), "SKU-1", syncing.SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	pack := uuids.saved[0]["quantified_associations"].(map[string]interface{})["PACK"].(map[string]interface{})
+	link := pack["products"].([]interface{})[0].(map[string]interface{})
+	if link["uuid"] != "22222222-2222-4222-8222-222222222222" || link["quantity"] != float64(1) {
+		t.Errorf("Expected SKU-2 to be linked by its destination UUID, got %v", link)
+	}
+	if model := pack["product_models"].([]interface{})[0].(map[string]interface{}); model["identifier"] != "MODEL-1" {
+		t.Errorf("Expected MODEL-1 to be linked by code, got %v", model)
+	}
+}
+
+// memoryBaselines stores the last sync of each item in memory
+type memoryBaselines map[string]string
+
+func (b memoryBaselines) Find(ctx context.Context, kind, code string) (string, bool, error) {
+	updated, ok := b[kind+"/"+code]
+	return updated, ok, nil
+}
+
+func (b memoryBaselines) Save(ctx context.Context, kind, code, updated string) error {
+	b[kind+"/"+code] = updated
+	return nil
+}
+
+// conflictingDest is a destination where SKU-2 was edited since the last sync recorded in baselines
+func conflictingDest(saved map[string]bool) (*MockDestRepository, memoryBaselines) {
+	updated := map[string]string{"SKU-1": "2024-01-01T10:00:00+00:00", "SKU-2": "2024-03-01T10:00:00+00:00"}
+	baselines := memoryBaselines{"product/SKU-1": updated["SKU-1"], "product/SKU-2": "2024-01-01T10:00:00+00:00"}
+
+	dest := &MockDestRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return product.Product{"identifier": identifier, "updated": updated[identifier]}, nil
+		},
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			saved[identifier] = true
+			updated[identifier] = "2024-04-01T10:00:00+00:00"
+			return nil
+		},
+	}
+	return dest, baselines
+}
+
+func conflictingSource() *MockSourceRepository {
+	return &MockSourceRepository{
+		findProductsByParentFunc: func(ctx context.Context, parentCode string) ([]product.Product, error) {
+			return []product.Product{{"identifier": "SKU-1"}, {"identifier": "SKU-2"}}, nil
+		},
+	}
+}
+
+func TestSync_DestWinsKeepsEditedProducts(t *testing.T) {
+	saved := map[string]bool{}
+	destRepo, baselines := conflictingDest(saved)
+
+	service := syncing.NewService(conflictingSource(), destRepo,
+		syncing.WithConflictDetection(conflict.NewDetector(baselines, nil), conflict.DestWins))
+	result, err := service.SaveProducts(context.Background(), []product.Product{{"identifier": "SKU-1"}, {"identifier": "SKU-2"}}, syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !saved["SKU-1"] || saved["SKU-2"] {
+		t.Errorf("Expected only the unchanged product to be written, got %v", saved)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0].Code != "SKU-2" || result.Conflicts[0].Overwritten {
+		t.Errorf("Expected SKU-2 to be reported as kept, got %+v", result.Conflicts)
+	}
+	if baselines["product/SKU-1"] != "2024-04-01T10:00:00+00:00" {
+		t.Errorf("Expected the update date after the write to be recorded, got %v", baselines)
+	}
+}
+
+func TestSync_RunStrategyOverridesDefault(t *testing.T) {
+	saved := map[string]bool{}
+	destRepo, baselines := conflictingDest(saved)
+
+	service := syncing.NewService(conflictingSource(), destRepo,
+		syncing.WithConflictDetection(conflict.NewDetector(baselines, nil), conflict.DestWins))
+	result, err := service.SaveProducts(context.Background(), []product.Product{{"identifier": "SKU-2"}}, syncing.SyncOptions{Conflicts: conflict.SourceWins})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !saved["SKU-2"] || len(result.Conflicts) != 1 || !result.Conflicts[0].Overwritten {
+		t.Errorf("Expected SKU-2 to be overwritten and reported, got %v %+v", saved, result.Conflicts)
+	}
+}
+
+func TestSync_AbortStopsBeforeWriting(t *testing.T) {
+	saved := map[string]bool{}
+	destRepo, baselines := conflictingDest(saved)
+
+	service := syncing.NewService(conflictingSource(), destRepo,
+		syncing.WithConflictDetection(conflict.NewDetector(baselines, nil), conflict.Abort))
+	_, err := service.Sync(context.Background(), "COMMON-001", syncing.SyncOptions{})
+	if !errors.Is(err, conflict.ErrConflict) {
+		t.Fatalf("Expected the conflict to abort the sync, got %v", err)
+	}
+
+	if len(saved) != 1 || !saved["COMMON-001"] {
+		t.Errorf("Expected no child product to be written, got %v", saved)
+	}
+}
+
+func TestSync_ConflictCheckFailsItemWhenDestinationCannotBeRead(t *testing.T) {
+	saved := map[string]bool{}
+	destRepo, baselines := conflictingDest(saved)
+	destRepo.findByIdentifierFunc = func(ctx context.Context, identifier string) (product.Product, error) {
+		if identifier == "SKU-2" {
+			return nil, errors.New("connection reset by peer")
+		}
+		return product.Product{"identifier": identifier, "updated": "2024-01-01T10:00:00+00:00"}, nil
+	}
+
+	service := syncing.NewService(conflictingSource(), destRepo,
+		syncing.WithConflictDetection(conflict.NewDetector(baselines, nil), conflict.DestWins))
+	result, err := service.SaveProducts(context.Background(), []product.Product{{"identifier": "SKU-1"}, {"identifier": "SKU-2"}}, syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// An unreadable destination must not pass for a missing item, which would be overwritten
+	if !saved["SKU-1"] || saved["SKU-2"] {
+		t.Errorf("Expected only SKU-1 to be written, got %v", saved)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Code != "SKU-2" {
+		t.Errorf("Expected SKU-2 to be reported, got %v", result.Errors)
+	}
+}
+
+func TestParseTargetPolicy(t *testing.T) {
+	if policy, err := syncing.ParseTargetPolicy(""); err != nil || policy != syncing.TargetDrop {
+		t.Errorf("Expected drop by default, got %q (%v)", policy, err)
+	}
+	if _, err := syncing.ParseTargetPolicy("enqueue"); err == nil {
+		t.Error("Expected error for unknown policy")
+	}
+}
+
+// crossSellProduct returns a product linking products and models with a X_SELL association
+func crossSellProduct(identifier string, products, models []string) product.Product {
+	productLinks := make([]interface{}, len(products))
+	for i, code := range products {
+		productLinks[i] = code
+	}
+	modelLinks := make([]interface{}, len(models))
+	for i, code := range models {
+		modelLinks[i] = code
+	}
+
+	return product.Product{
+		"identifier": identifier,
+		"associations": map[string]interface{}{
+			"X_SELL": map[string]interface{}{"products": productLinks, "product_models": modelLinks, "groups": []interface{}{"promo"}},
+		},
+	}
+}
+
+// crossSellLinks returns the codes linked by the X_SELL association of a payload
+func crossSellLinks(payload product.Product, kind string) []interface{} {
+	associations := payload["associations"].(map[string]interface{})
+	links, _ := associations["X_SELL"].(map[string]interface{})[kind].([]interface{})
+	return links
+}
+
+func TestSync_DropsMissingAssociationTargets(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return crossSellProduct(identifier, []string{"SKU-2", "SKU-3"}, []string{"MODEL-1"}), nil
+		},
+	}
+
+	var saved product.Product
+	destRepo := &MockDestRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			if identifier == "SKU-3" {
+				return nil, product.ErrNotFound
+			}
+			return product.Product{"identifier": identifier}, nil
+		},
+		findModelByCodeFunc: func(ctx context.Context, code string) (product.ProductModel, error) {
+			return nil, product.ErrNotFound
+		},
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			saved = productData
+			return nil
+		},
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithAssociationTargets(syncing.TargetDrop, 0))
+	result, err := service.Sync(context.Background(), "SKU-1", syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if links := crossSellLinks(saved, "products"); !reflect.DeepEqual(links, []interface{}{"SKU-2"}) {
+		t.Errorf("Expected only SKU-2 to stay linked, got %v", links)
+	}
+	if links := crossSellLinks(saved, "product_models"); len(links) != 0 {
+		t.Errorf("Expected MODEL-1 to be dropped, got %v", links)
+	}
+	if links := crossSellLinks(saved, "groups"); !reflect.DeepEqual(links, []interface{}{"promo"}) {
+		t.Errorf("Expected groups to be kept, got %v", links)
+	}
+
+	expected := []syncing.MissingTarget{
+		{Item: "product SKU-1", Association: "X_SELL", Kind: syncing.KindProductModel, Code: "MODEL-1"},
+		{Item: "product SKU-1", Association: "X_SELL", Kind: syncing.KindProduct, Code: "SKU-3"},
+	}
+	if !reflect.DeepEqual(result.MissingTargets, expected) {
+		t.Errorf("Expected %v, got %v", expected, result.MissingTargets)
+	}
+}
+
+func TestSync_FailsWhenAssociationTargetLookupFails(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       syncing.TargetPolicy
+		destLookup   error
+		sourceLookup error
+	}{
+		{name: "destination lookup", policy: syncing.TargetDrop, destLookup: errors.New("connection reset")},
+		{name: "source hierarchy", policy: syncing.TargetSync, destLookup: product.ErrNotFound, sourceLookup: errors.New("connection reset")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sourceRepo := &MockSourceRepository{
+				findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+					if identifier == "SKU-2" && tt.sourceLookup != nil {
+						return nil, tt.sourceLookup
+					}
+					return crossSellProduct(identifier, []string{"SKU-2"}, nil), nil
+				},
+			}
+
+			saved := false
+			destRepo := &MockDestRepository{
+				findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+					if identifier == "SKU-2" {
+						return nil, tt.destLookup
+					}
+					return product.Product{"identifier": identifier}, nil
+				},
+				saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+					saved = true
+					return nil
+				},
+			}
+
+			service := syncing.NewService(sourceRepo, destRepo, syncing.WithAssociationTargets(tt.policy, 1))
+			if _, err := service.Sync(context.Background(), "SKU-1", syncing.SyncOptions{}); err == nil || !strings.Contains(err.Error(), "connection reset") {
+				t.Errorf("Expected the lookup error to fail the product, got %v", err)
+			}
+			if saved {
+				t.Error("Expected the product not to be written")
+			}
+		})
+	}
+}
+
+func TestSync_SyncsAssociationTargetsUpToDepth(t *testing.T) {
+	// Each product of the chain links the next one
+	chain := map[string]string{"SKU-1": "SKU-2", "SKU-2": "SKU-3", "SKU-3": "SKU-4", "SKU-4": "SKU-5"}
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return crossSellProduct(identifier, []string{chain[identifier]}, nil), nil
+		},
+	}
+
+	var order []string
+	saved := map[string]product.Product{}
+	destRepo := &MockDestRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			if _, ok := saved[identifier]; !ok {
+				return nil, product.ErrNotFound
+			}
+			return saved[identifier], nil
+		},
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			order = append(order, identifier)
+			saved[identifier] = productData
+			return nil
+		},
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithAssociationTargets(syncing.TargetSync, 2))
+	result, err := service.Sync(context.Background(), "SKU-1", syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Targets are written before the items linking them, two levels deep
+	if strings.Join(order, ",") != "SKU-3,SKU-2,SKU-1" {
+		t.Errorf("Expected SKU-3, SKU-2, SKU-1 to be written, got %v", order)
+	}
+	if links := crossSellLinks(saved["SKU-1"], "products"); !reflect.DeepEqual(links, []interface{}{"SKU-2"}) {
+		t.Errorf("Expected SKU-1 to link SKU-2, got %v", links)
+	}
+	if links := crossSellLinks(saved["SKU-3"], "products"); len(links) != 0 {
+		t.Errorf("Expected SKU-3 to be written without its link beyond the depth, got %v", links)
+	}
+	if len(result.MissingTargets) != 0 {
+		t.Errorf("Expected the targets of SKU-1 to be synced, got %v", result.MissingTargets)
+	}
+}
+
+func TestParseAssociationPolicy(t *testing.T) {
+	if policy, err := syncing.ParseAssociationPolicy("fail"); err != nil || policy != "" {
+		t.Errorf("Expected fail to disable the check, got %q (%v)", policy, err)
+	}
+	if policy, err := syncing.ParseAssociationPolicy("sync"); err != nil || policy != syncing.TargetSync {
+		t.Errorf("Expected sync, got %q (%v)", policy, err)
+	}
+	if _, err := syncing.ParseAssociationPolicy("enqueue"); err == nil {
+		t.Error("Expected error for unknown policy")
+	}
+}
+
+func TestSync_ChangedOnlySendsDifferences(t *testing.T) {
+	localized := func(enUS, frFR string) []interface{} {
+		return []interface{}{
+			map[string]interface{}{"locale": "en_US", "scope": nil, "data": enUS},
+			map[string]interface{}{"locale": "fr_FR", "scope": nil, "data": frFR},
+		}
+	}
+	sourceRepo := &MockSourceRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return product.Product{
+				"identifier": identifier,
+				"enabled":    true,
+				"categories": []interface{}{"summer", "shoes"},
+				"updated":    "2024-05-01T10:00:00+00:00",
+				"values": map[string]interface{}{
+					"name":  localized("Shoe", "Chaussure"),
+					"sizes": []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": []interface{}{"s", "m"}}},
+				},
+			}, nil
+		},
+		findProductsByParentFunc: func(ctx context.Context, parentCode string) ([]product.Product, error) {
+			return []product.Product{}, nil
+		},
+	}
+
+	dest := product.Product{
+		"identifier": "SKU-1",
+		"enabled":    true,
+		"categories": []interface{}{"shoes", "summer"},
+		"updated":    "2024-06-01T10:00:00+00:00",
+		"values": map[string]interface{}{
+			"name":  localized("Shoe", "Soulier"),
+			"sizes": []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": []interface{}{"m", "s"}}},
+		},
+	}
+	var saved []product.Product
+	destRepo := &MockDestRepository{
+		findByIdentifierFunc: func(ctx context.Context, identifier string) (product.Product, error) {
+			return dest, nil
+		},
+		saveFunc: func(ctx context.Context, identifier string, productData product.Product) error {
+			saved = append(saved, productData)
+			return nil
+		},
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithChangedOnly())
+	if _, err := service.Sync(context.Background(), "SKU-1", syncing.SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Lists in another order are the same, only the French name changed
+	expected := product.Product{
+		"identifier": "SKU-1",
+		"values": map[string]interface{}{
+			"name": []interface{}{map[string]interface{}{"locale": "fr_FR", "scope": nil, "data": "Chaussure"}},
+		},
+	}
+	if len(saved) != 1 || !reflect.DeepEqual(saved[0], expected) {
+		t.Fatalf("Expected %v to be sent, got %v", expected, saved)
+	}
+
+	// Once destination matches, nothing is written
+	dest["values"].(map[string]interface{})["name"] = localized("Shoe", "Chaussure")
+	result, err := service.Sync(context.Background(), "SKU-1", syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(saved) != 1 || result.Unchanged != 1 || result.ProductsSynced != 0 {
+		t.Errorf("Expected the unchanged product not to be written, got %d writes and %+v", len(saved), result)
+	}
+}
+
+// variantChecker reports the family variants listed in conflicts as changed in destination
+type variantChecker struct {
+	conflicts map[string]string
+	calls     int
+}
+
+func (c *variantChecker) CheckVariant(ctx context.Context, familyCode, variantCode string) (string, error) {
+	c.calls++
+	return c.conflicts[familyCode+"/"+variantCode], nil
+}
+
+func TestSaveProducts_FailsItemsOfChangedFamilyVariants(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findModelByCodeFunc: func(ctx context.Context, code string) (product.ProductModel, error) {
+			return product.ProductModel{"code": code, "family": "shoes", "family_variant": "by_size"}, nil
+		},
+	}
+
+	var written []string
+	destRepo := &MockDestRepository{
+		saveAllFunc: func(ctx context.Context, products []product.Product) (map[string]error, error) {
+			for _, prod := range products {
+				written = append(written, prod["identifier"].(string))
+			}
+			return nil, nil
+		},
+	}
+
+	checker := &variantChecker{conflicts: map[string]string{"shoes/by_size": "axes of level 1 differ"}}
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithVariantChecker(checker))
+	result, err := service.SaveProducts(context.Background(), []product.Product{
+		{"identifier": "SKU-1-S", "family": "shoes", "parent": "SKU-1"},
+		{"identifier": "SKU-1-M", "family": "shoes", "parent": "SKU-1"},
+		{"identifier": "SIMPLE", "family": "shoes"},
+	}, syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if strings.Join(written, ",") != "SIMPLE" {
+		t.Errorf("Expected only the simple product to be written, got %v", written)
+	}
+	if len(result.Errors) != 2 || !strings.Contains(result.Errors[0].Message, "axes of level 1 differ") {
+		t.Errorf("Expected both variant products to be reported, got %v", result.Errors)
+	}
+	if checker.calls != 1 {
+		t.Errorf("Expected the variant to be checked once, got %d checks", checker.calls)
+	}
+}
@@ -0,0 +1,158 @@
+package syncing
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sync"
+
+	"akeneo-migrator/internal/product"
+	"akeneo-migrator/kit/dryrun"
+)
+
+// uuidCache remembers the destination UUID of each product identifier
+type uuidCache struct {
+	mu    sync.Mutex
+	uuids map[string]string
+}
+
+// WithProductUUIDs writes products through the UUID endpoints of the destination, for Akeneo 7 and SaaS.
+// Source identifiers are mapped to destination UUIDs; products missing in destination get a new UUID.
+func WithProductUUIDs(repo product.UUIDRepository) Option {
+	return func(s *Service) {
+		s.uuidRepo = repo
+	}
+}
+
+// resolveUUIDs returns the destination UUIDs of the given identifiers, generating one for the
+// products that do not exist yet. Identifiers already resolved are not looked up again.
+func (s *Service) resolveUUIDs(ctx context.Context, identifiers []string) (map[string]string, error) {
+	s.uuids.mu.Lock()
+	defer s.uuids.mu.Unlock()
+
+	if s.uuids.uuids == nil {
+		s.uuids.uuids = make(map[string]string)
+	}
+
+	var unknown []string
+	seen := make(map[string]bool, len(identifiers))
+	for _, identifier := range identifiers {
+		if _, ok := s.uuids.uuids[identifier]; !ok && !seen[identifier] {
+			seen[identifier] = true
+			unknown = append(unknown, identifier)
+		}
+	}
+
+	if len(unknown) > 0 {
+		found, err := s.uuidRepo.FindUUIDs(ctx, unknown)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, identifier := range unknown {
+			uuid, ok := found[identifier]
+			if !ok {
+				if uuid, err = newUUID(); err != nil {
+					return nil, err
+				}
+			}
+			s.uuids.uuids[identifier] = uuid
+		}
+	}
+
+	resolved := make(map[string]string, len(identifiers))
+	for _, identifier := range identifiers {
+		resolved[identifier] = s.uuids.uuids[identifier]
+	}
+
+	return resolved, nil
+}
+
+// writeProduct writes a product by identifier, or by UUID when the destination keys products by UUID.
+// Dry runs only record the write.
+func (s *Service) writeProduct(ctx context.Context, identifier string, prod product.Product) error {
+	if dryrun.Record(ctx, dryrun.Write{Kind: KindProduct, Code: identifier, Data: prod}) {
+		return nil
+	}
+	if s.uuidRepo == nil {
+		return s.destRepo.Save(ctx, identifier, prod)
+	}
+
+	// Products linked by quantified associations are referenced by UUID as well
+	uuids, err := s.resolveUUIDs(ctx, append([]string{identifier}, quantifiedProductIdentifiers(prod)...))
+	if err != nil {
+		return fmt.Errorf("error resolving UUID of product %s: %w", identifier, err)
+	}
+
+	return s.uuidRepo.SaveByUUID(ctx, uuids[identifier], withUUID(withQuantifiedUUIDs(prod, uuids), uuids[identifier]))
+}
+
+// writeProducts writes several products by identifier, or by UUID when the destination keys products by UUID.
+// The errors are indexed by identifier in both cases. Dry runs only record the writes.
+func (s *Service) writeProducts(ctx context.Context, products []product.Product) (map[string]error, error) {
+	if dryrun.Enabled(ctx) {
+		for _, prod := range products {
+			identifier, _ := prod["identifier"].(string)
+			dryrun.Record(ctx, dryrun.Write{Kind: KindProduct, Code: identifier, Data: prod})
+		}
+		return nil, nil
+	}
+	if s.uuidRepo == nil {
+		return s.destRepo.SaveAll(ctx, products)
+	}
+
+	identifiers := make([]string, len(products))
+	var linked []string
+	for i, prod := range products {
+		identifiers[i], _ = prod["identifier"].(string)
+		linked = append(linked, quantifiedProductIdentifiers(prod)...)
+	}
+
+	// Products linked by quantified associations are referenced by UUID as well
+	uuids, err := s.resolveUUIDs(ctx, append(append([]string{}, identifiers...), linked...))
+	if err != nil {
+		return nil, fmt.Errorf("error resolving product UUIDs: %w", err)
+	}
+
+	batch := make([]product.Product, len(products))
+	for i, prod := range products {
+		batch[i] = withUUID(withQuantifiedUUIDs(prod, uuids), uuids[identifiers[i]])
+	}
+
+	failedByUUID, err := s.uuidRepo.SaveAllByUUID(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	failed := make(map[string]error, len(failedByUUID))
+	for _, identifier := range identifiers {
+		if saveErr, ok := failedByUUID[uuids[identifier]]; ok {
+			failed[identifier] = saveErr
+		}
+	}
+
+	return failed, nil
+}
+
+// withUUID returns a copy of a product carrying the given UUID
+func withUUID(prod product.Product, uuid string) product.Product {
+	result := make(product.Product, len(prod)+1)
+	for key, value := range prod {
+		result[key] = value
+	}
+	result["uuid"] = uuid
+
+	return result
+}
+
+// newUUID generates a random (version 4) UUID
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("error generating UUID: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
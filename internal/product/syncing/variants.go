@@ -0,0 +1,129 @@
+package syncing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// VariantChecker compares the family variants of products and models between source and destination
+type VariantChecker interface {
+	// CheckVariant returns why the axes or levels of a family variant differ in destination, empty when they match
+	CheckVariant(ctx context.Context, familyCode, variantCode string) (string, error)
+}
+
+// WithVariantChecker checks the family variant of every product model and variant product before it is written.
+// Akeneo rejects or mangles items whose variant has other axes or levels in destination, so they fail instead.
+func WithVariantChecker(checker VariantChecker) Option {
+	return func(s *Service) {
+		s.variantChecker = checker
+	}
+}
+
+// familyVariant identifies a family variant
+type familyVariant struct {
+	Family string
+	Code   string
+}
+
+// variantCache remembers the family variant of each parent model and the conflicts found for each variant.
+// Lookups that fail are not remembered, so they are tried again for the next item.
+type variantCache struct {
+	mu        sync.Mutex
+	parents   map[string]familyVariant
+	conflicts map[familyVariant]string
+}
+
+func (c *variantCache) parent(code string) (familyVariant, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	variant, ok := c.parents[code]
+	return variant, ok
+}
+
+func (c *variantCache) setParent(code string, variant familyVariant) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.parents == nil {
+		c.parents = make(map[string]familyVariant)
+	}
+	c.parents[code] = variant
+}
+
+func (c *variantCache) conflict(variant familyVariant) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	reason, ok := c.conflicts[variant]
+	return reason, ok
+}
+
+func (c *variantCache) setConflict(variant familyVariant, reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conflicts == nil {
+		c.conflicts = make(map[familyVariant]string)
+	}
+	c.conflicts[variant] = reason
+}
+
+// checkVariant returns an error when the family variant of an item has other axes or levels in destination.
+// Product models name their variant; variant products use the one of their parent model in source.
+func (s *Service) checkVariant(ctx context.Context, name string, item map[string]interface{}) error {
+	if s.variantChecker == nil {
+		return nil
+	}
+
+	variant, err := s.variantOf(ctx, item)
+	if err != nil {
+		return fmt.Errorf("error checking the family variant of %s: %w", name, err)
+	}
+	if variant.Code == "" {
+		return nil
+	}
+
+	reason, known := s.variants.conflict(variant)
+	if !known {
+		reason, err = s.variantChecker.CheckVariant(ctx, variant.Family, variant.Code)
+		if err != nil {
+			return fmt.Errorf("error checking the family variant of %s: %w", name, err)
+		}
+		s.variants.setConflict(variant, reason)
+	}
+	if reason != "" {
+		return fmt.Errorf("family variant %s of %s differs in destination: %s", variant.Code, name, reason)
+	}
+
+	return nil
+}
+
+// variantOf returns the family variant of an item, empty for simple products
+func (s *Service) variantOf(ctx context.Context, item map[string]interface{}) (familyVariant, error) {
+	familyCode, _ := item["family"].(string)
+	if variantCode, _ := item["family_variant"].(string); variantCode != "" {
+		return familyVariant{Family: familyCode, Code: variantCode}, nil
+	}
+
+	parent, _ := item["parent"].(string)
+	if parent == "" {
+		return familyVariant{}, nil
+	}
+	if variant, ok := s.variants.parent(parent); ok {
+		return variant, nil
+	}
+
+	model, err := s.sourceRepo.FindModelByCode(ctx, parent)
+	if err != nil {
+		return familyVariant{}, fmt.Errorf("error fetching parent model %s from source: %w", parent, err)
+	}
+	variant, err := s.variantOf(ctx, model)
+	if err != nil {
+		return familyVariant{}, err
+	}
+	s.variants.setParent(parent, variant)
+
+	return variant, nil
+}
@@ -0,0 +1,19 @@
+package syncing_dependencies
+
+import "akeneo-migrator/kit/bus"
+
+const SyncDependenciesCommandType bus.Type = "product.sync_dependencies"
+
+// SyncDependenciesCommand represents a command to sync the structure missing in destination for product hierarchies
+type SyncDependenciesCommand struct {
+	// Identifiers are the commons of the hierarchies, as given to sync-product
+	Identifiers []string
+	// Path is a list of identifiers in any format sync-products-from-file reads, used instead
+	Path  string
+	Debug bool
+}
+
+// Type returns the command type
+func (c SyncDependenciesCommand) Type() bus.Type {
+	return SyncDependenciesCommandType
+}
@@ -0,0 +1,257 @@
+package syncing_dependencies
+
+import (
+	"context"
+	"fmt"
+
+	"akeneo-migrator/internal/product"
+	"akeneo-migrator/internal/product/validating"
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/retry"
+	"akeneo-migrator/kit/session"
+)
+
+// Builder creates the command syncing a prerequisite missing in destination
+type Builder func(missing validating.Missing, opts SyncOptions) bus.Message
+
+// Service syncs the catalog structure product hierarchies rely on when the destination lacks it,
+// so a product sync does not need the structure to be migrated first
+type Service struct {
+	validator       *validating.Service
+	sourceStructure product.StructureRepository
+	destStructure   product.StructureRepository
+	dispatcher      bus.Bus
+	builders        map[string]Builder
+}
+
+// Option configures the dependencies service
+type Option func(*Service)
+
+// WithBuilder registers how prerequisites of a kind (validating.KindAttribute, KindCategory,
+// KindFamily or KindFamilyVariant) are synced. Kinds without a builder are reported as skipped.
+func WithBuilder(kind string, builder Builder) Option {
+	return func(s *Service) {
+		s.builders[kind] = builder
+	}
+}
+
+// NewService creates a new instance of the dependencies service
+func NewService(
+	validator *validating.Service,
+	sourceStructure product.StructureRepository,
+	destStructure product.StructureRepository,
+	dispatcher bus.Bus,
+	opts ...Option,
+) *Service {
+	service := &Service{
+		validator:       validator,
+		sourceStructure: sourceStructure,
+		destStructure:   destStructure,
+		dispatcher:      dispatcher,
+		builders:        make(map[string]Builder),
+	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
+
+// SyncOptions contains the options of a dependencies sync
+type SyncOptions struct {
+	Identifiers []string
+	// Path is read instead of Identifiers when set
+	Path  string
+	Debug bool
+}
+
+// Dependency is a prerequisite synced before the products
+type Dependency struct {
+	validating.Missing
+	Synced int
+	Error  string
+	// Skipped is set when no builder is registered for the kind of the prerequisite
+	Skipped bool
+}
+
+// SyncResult contains the result of a dependencies sync
+type SyncResult struct {
+	// Validation is what the hierarchies were missing in destination before the sync
+	Validation   *validating.ValidationResult
+	Dependencies []Dependency
+	failures     []retry.Failure
+	// Planned are the writes recorded instead of being sent during a dry run
+	Planned []dryrun.Write
+}
+
+// Synced returns the number of items written by the dependency syncs
+func (r *SyncResult) Synced() int {
+	synced := 0
+	for _, dependency := range r.Dependencies {
+		synced += dependency.Synced
+	}
+	return synced
+}
+
+// Failures returns the prerequisites that could not be synchronized
+func (r *SyncResult) Failures() []retry.Failure {
+	return r.failures
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// Sync validates the hierarchies and syncs what they miss in destination, in the order the
+// prerequisites depend on each other. A prerequisite failing does not stop the others; the
+// products relying on it will fail on their own.
+func (s *Service) Sync(ctx context.Context, opts SyncOptions) (*SyncResult, error) {
+	var validation *validating.ValidationResult
+	var err error
+	if opts.Path != "" {
+		validation, err = s.validator.ValidateFile(ctx, opts.Path)
+	} else {
+		validation, err = s.validator.Validate(ctx, opts.Identifiers)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SyncResult{Validation: validation}
+	plan, err := s.plan(ctx, validation.Missing)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, planned := dryrun.Collect(ctx)
+
+	// Failures are collected by the dependencies sync itself instead of being queued by each command
+	syncCtx := retry.WithoutRecording(ctx)
+
+	for _, missing := range plan {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		dependency := Dependency{Missing: missing}
+		builder, ok := s.builders[missing.Kind]
+		if !ok {
+			dependency.Skipped = true
+			result.Dependencies = append(result.Dependencies, dependency)
+			continue
+		}
+
+		msg := builder(missing, opts)
+		response, dispatchErr := s.dispatcher.Dispatch(syncCtx, msg)
+		if counter, ok := response.Data.(session.Counter); ok {
+			dependency.Synced = counter.Synced()
+		}
+
+		failures := retry.Collect(msg, response, dispatchErr)
+		result.failures = append(result.failures, failures...)
+		switch {
+		case len(failures) > 0:
+			dependency.Error = failures[0].Error
+		case dispatchErr != nil:
+			dependency.Error = dispatchErr.Error()
+		}
+		result.Dependencies = append(result.Dependencies, dependency)
+	}
+
+	result.Planned = planned()
+	return result, nil
+}
+
+// plan orders the prerequisites to sync: attributes first, with their options and including the
+// attributes of the missing families, then categories root first, then families with their variants
+func (s *Service) plan(ctx context.Context, missing []validating.Missing) ([]validating.Missing, error) {
+	var attributes, categories, families []validating.Missing
+	seen := make(map[string]bool)
+	add := func(list *[]validating.Missing, item validating.Missing) {
+		if key := item.String(); !seen[key] {
+			seen[key] = true
+			*list = append(*list, item)
+		}
+	}
+
+	// Variants come with the sync of their family when it is missing as well
+	missingFamilies := make(map[string]bool)
+	for _, item := range missing {
+		if item.Kind == validating.KindFamily {
+			missingFamilies[item.Code] = true
+		}
+	}
+
+	var destAttributes map[string]string
+	for _, item := range missing {
+		switch item.Kind {
+		case validating.KindAttribute:
+			add(&attributes, item)
+		case validating.KindAttributeOption:
+			add(&attributes, validating.Missing{Kind: validating.KindAttribute, Code: item.Scope})
+		case validating.KindCategory:
+			chain, err := s.categoryChain(ctx, item.Code)
+			if err != nil {
+				return nil, err
+			}
+			for _, code := range chain {
+				add(&categories, validating.Missing{Kind: validating.KindCategory, Code: code})
+			}
+			add(&categories, item)
+		case validating.KindFamily:
+			if destAttributes == nil {
+				types, err := s.destStructure.FindAttributeTypes(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("error fetching destination attributes: %w", err)
+				}
+				destAttributes = types
+			}
+
+			codes, err := s.sourceStructure.FindFamilyAttributes(ctx, item.Code)
+			if err != nil {
+				return nil, fmt.Errorf("error fetching source attributes of family %s: %w", item.Code, err)
+			}
+			for _, code := range codes {
+				if _, exists := destAttributes[code]; !exists {
+					add(&attributes, validating.Missing{Kind: validating.KindAttribute, Code: code})
+				}
+			}
+			add(&families, item)
+		case validating.KindFamilyVariant:
+			if !missingFamilies[item.Scope] {
+				add(&families, item)
+			}
+		}
+	}
+
+	return append(append(attributes, categories...), families...), nil
+}
+
+// categoryChain returns the ancestors of a category that are missing in destination, root first,
+// since a category can only be created under an existing parent
+func (s *Service) categoryChain(ctx context.Context, code string) ([]string, error) {
+	var chain []string
+	for current := code; ; {
+		parent, exists, err := s.sourceStructure.FindCategoryParent(ctx, current)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching source category %s: %w", current, err)
+		}
+		if !exists || parent == "" {
+			return chain, nil
+		}
+
+		_, parentExists, err := s.destStructure.FindCategoryParent(ctx, parent)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching destination category %s: %w", parent, err)
+		}
+		if parentExists {
+			return chain, nil
+		}
+
+		chain = append([]string{parent}, chain...)
+		current = parent
+	}
+}
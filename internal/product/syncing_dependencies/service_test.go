@@ -0,0 +1,303 @@
+package syncing_dependencies_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"akeneo-migrator/internal/product"
+	"akeneo-migrator/internal/product/syncing_dependencies"
+	"akeneo-migrator/internal/product/validating"
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/retry"
+)
+
+// MockSourceRepository is a mock of the source repository serving a fixed catalog
+type MockSourceRepository struct {
+	products         map[string]product.Product
+	models           map[string]product.ProductModel
+	productsByParent map[string][]product.Product
+	modelsByParent   map[string][]product.ProductModel
+}
+
+func (m *MockSourceRepository) FindByIdentifier(ctx context.Context, identifier string) (product.Product, error) {
+	if prod, ok := m.products[identifier]; ok {
+		return prod, nil
+	}
+	return nil, errors.New("product not found")
+}
+
+func (m *MockSourceRepository) FindModelByCode(ctx context.Context, code string) (product.ProductModel, error) {
+	if model, ok := m.models[code]; ok {
+		return model, nil
+	}
+	return nil, errors.New("model not found")
+}
+
+func (m *MockSourceRepository) FindProductsByParent(ctx context.Context, parentCode string) ([]product.Product, error) {
+	return m.productsByParent[parentCode], nil
+}
+
+func (m *MockSourceRepository) FindModelsByParent(ctx context.Context, parentCode string) ([]product.ProductModel, error) {
+	return m.modelsByParent[parentCode], nil
+}
+
+func (m *MockSourceRepository) FindProductsUpdatedSince(ctx context.Context, updatedSince string) ([]product.Product, error) {
+	return nil, nil
+}
+
+func (m *MockSourceRepository) FindModelsUpdatedSince(ctx context.Context, updatedSince string) ([]product.ProductModel, error) {
+	return nil, nil
+}
+
+func (m *MockSourceRepository) StreamProductsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]product.Product) error) error {
+	return nil
+}
+
+func (m *MockSourceRepository) StreamModelsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]product.ProductModel) error) error {
+	return nil
+}
+
+func (m *MockSourceRepository) StreamProductsBySearch(ctx context.Context, search string, batchSize int, callback func([]product.Product) error) error {
+	return nil
+}
+
+func (m *MockSourceRepository) CountUpdatedSince(ctx context.Context, updatedSince, updatedUntil string) (int, error) {
+	return 0, nil
+}
+
+func (m *MockSourceRepository) CountProductsBySearch(ctx context.Context, search string) (int, error) {
+	return 0, nil
+}
+
+func (m *MockSourceRepository) DownloadMediaFile(ctx context.Context, code string) (product.MediaFile, error) {
+	return product.MediaFile{}, errors.New("unexpected media download")
+}
+
+// MockIdentifierListRepository is a mock of the identifier list repository
+type MockIdentifierListRepository struct {
+	lists map[string][]string
+}
+
+func (m *MockIdentifierListRepository) Read(ctx context.Context, path string) ([]string, error) {
+	if list, ok := m.lists[path]; ok {
+		return list, nil
+	}
+	return nil, errors.New("file not found")
+}
+
+// MockStructureRepository is a mock of the structure of an instance
+type MockStructureRepository struct {
+	families       map[string]bool
+	variants       map[string]map[string]bool
+	attributeTypes map[string]string
+	options        map[string]map[string]bool
+	categories     map[string]string
+	familyAttrs    map[string][]string
+}
+
+func (m *MockStructureRepository) FindFamilyCodes(ctx context.Context) (map[string]bool, error) {
+	return m.families, nil
+}
+
+func (m *MockStructureRepository) FindFamilyVariantCodes(ctx context.Context, familyCode string) (map[string]bool, error) {
+	return m.variants[familyCode], nil
+}
+
+func (m *MockStructureRepository) FindAttributeTypes(ctx context.Context) (map[string]string, error) {
+	return m.attributeTypes, nil
+}
+
+func (m *MockStructureRepository) FindAttributeDefinitions(ctx context.Context) (map[string]product.AttributeDefinition, error) {
+	definitions := make(map[string]product.AttributeDefinition, len(m.attributeTypes))
+	for code, attrType := range m.attributeTypes {
+		definitions[code] = product.AttributeDefinition{Type: attrType}
+	}
+	return definitions, nil
+}
+
+func (m *MockStructureRepository) FindOptionCodes(ctx context.Context, attributeCode string) (map[string]bool, error) {
+	return m.options[attributeCode], nil
+}
+
+func (m *MockStructureRepository) FindFamilyAttributes(ctx context.Context, familyCode string) ([]string, error) {
+	return m.familyAttrs[familyCode], nil
+}
+
+func (m *MockStructureRepository) FindCategoryParent(ctx context.Context, code string) (string, bool, error) {
+	parent, exists := m.categories[code]
+	return parent, exists, nil
+}
+
+func values(data map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(data))
+	for attribute, value := range data {
+		result[attribute] = []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": value}}
+	}
+	return result
+}
+
+// syncCommand is a fake command syncing one prerequisite
+type syncCommand struct {
+	Name string
+}
+
+func (c syncCommand) Type() bus.Type {
+	return "fake.sync"
+}
+
+// syncResult reports the items written and the ones that failed
+type syncResult struct {
+	synced   int
+	failures []retry.Failure
+}
+
+func (r syncResult) Synced() int {
+	return r.synced
+}
+
+func (r syncResult) Failures() []retry.Failure {
+	return r.failures
+}
+
+// MockBus records the dispatched commands
+type MockBus struct {
+	dispatched []string
+	failing    map[string]bool
+}
+
+func (m *MockBus) Dispatch(ctx context.Context, msg bus.Message) (bus.Response, error) {
+	name := msg.(syncCommand).Name
+	m.dispatched = append(m.dispatched, name)
+	if m.failing[name] {
+		return bus.Response{Data: syncResult{failures: []retry.Failure{{Kind: "fake", Code: name, Error: "boom"}}}}, nil
+	}
+	return bus.Response{Data: syncResult{synced: 1}}, nil
+}
+
+func (m *MockBus) Register(msgType bus.Type, handler bus.Handler) {}
+
+// builders creates one fake command per prerequisite, named after it
+func builders() []syncing_dependencies.Option {
+	build := func(missing validating.Missing, opts syncing_dependencies.SyncOptions) bus.Message {
+		return syncCommand{Name: missing.String()}
+	}
+	return []syncing_dependencies.Option{
+		syncing_dependencies.WithBuilder(validating.KindAttribute, build),
+		syncing_dependencies.WithBuilder(validating.KindCategory, build),
+		syncing_dependencies.WithBuilder(validating.KindFamily, build),
+		syncing_dependencies.WithBuilder(validating.KindFamilyVariant, build),
+	}
+}
+
+func newInstances() (*MockSourceRepository, *MockStructureRepository, *MockStructureRepository) {
+	source := &MockSourceRepository{
+		products: map[string]product.Product{
+			"SKU-1": {
+				"identifier": "SKU-1",
+				"family":     "shoes",
+				"categories": []interface{}{"sandals"},
+				"values":     values(map[string]interface{}{"name": "Shoe", "color": "navy"}),
+			},
+		},
+		models: map[string]product.ProductModel{
+			"MODEL-1": {"code": "MODEL-1", "family": "boots", "family_variant": "boots_by_size", "values": values(map[string]interface{}{})},
+		},
+	}
+	sourceStructure := &MockStructureRepository{
+		categories:  map[string]string{"master": "", "summer": "master", "sandals": "summer"},
+		familyAttrs: map[string][]string{"shoes": {"sku", "name", "heel_height"}},
+	}
+	destStructure := &MockStructureRepository{
+		families: map[string]bool{"boots": true},
+		variants: map[string]map[string]bool{"boots": {}},
+		attributeTypes: map[string]string{
+			"sku":   "pim_catalog_identifier",
+			"name":  "pim_catalog_text",
+			"color": "pim_catalog_simpleselect",
+		},
+		options:    map[string]map[string]bool{"color": {"red": true}},
+		categories: map[string]string{"master": ""},
+	}
+	return source, sourceStructure, destStructure
+}
+
+func TestSync_SyncsMissingPrerequisitesInDependencyOrder(t *testing.T) {
+	source, sourceStructure, destStructure := newInstances()
+	validator := validating.NewService(&MockIdentifierListRepository{}, source, destStructure)
+	dispatcher := &MockBus{}
+	service := syncing_dependencies.NewService(validator, sourceStructure, destStructure, dispatcher, builders()...)
+
+	result, err := service.Sync(context.Background(), syncing_dependencies.SyncOptions{Identifiers: []string{"SKU-1", "MODEL-1"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The option comes with its attribute, the family with the attributes it lacks, and the
+	// category with its missing parent
+	expected := []string{
+		"attribute color",
+		"attribute heel_height",
+		"category summer",
+		"category sandals",
+		"family shoes",
+		"family_variant boots.boots_by_size",
+	}
+	if !reflect.DeepEqual(dispatcher.dispatched, expected) {
+		t.Errorf("Expected %v, got %v", expected, dispatcher.dispatched)
+	}
+	if result.Synced() != len(expected) || len(result.Failures()) != 0 {
+		t.Errorf("Expected %d synced without failures, got %d and %v", len(expected), result.Synced(), result.Failures())
+	}
+}
+
+func TestSync_ReportsFailedAndSkippedPrerequisites(t *testing.T) {
+	source, sourceStructure, destStructure := newInstances()
+	validator := validating.NewService(&MockIdentifierListRepository{}, source, destStructure)
+	dispatcher := &MockBus{failing: map[string]bool{"family shoes": true}}
+	build := func(missing validating.Missing, opts syncing_dependencies.SyncOptions) bus.Message {
+		return syncCommand{Name: missing.String()}
+	}
+	service := syncing_dependencies.NewService(validator, sourceStructure, destStructure, dispatcher,
+		syncing_dependencies.WithBuilder(validating.KindFamily, build),
+	)
+
+	result, err := service.Sync(context.Background(), syncing_dependencies.SyncOptions{Identifiers: []string{"SKU-1"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(dispatcher.dispatched, []string{"family shoes"}) {
+		t.Errorf("Expected only the family to be dispatched, got %v", dispatcher.dispatched)
+	}
+
+	var failed, skipped int
+	for _, dependency := range result.Dependencies {
+		switch {
+		case dependency.Error != "":
+			failed++
+		case dependency.Skipped:
+			skipped++
+		}
+	}
+	if failed != 1 || skipped != 4 || len(result.Failures()) != 1 {
+		t.Errorf("Expected 1 failed and 4 skipped dependencies, got %d and %d: %+v", failed, skipped, result.Dependencies)
+	}
+}
+
+func TestSync_DispatchesNothingForUnreadableHierarchies(t *testing.T) {
+	source, sourceStructure, destStructure := newInstances()
+	validator := validating.NewService(&MockIdentifierListRepository{}, source, destStructure)
+	dispatcher := &MockBus{}
+	service := syncing_dependencies.NewService(validator, sourceStructure, destStructure, dispatcher, builders()...)
+
+	result, err := service.Sync(context.Background(), syncing_dependencies.SyncOptions{Identifiers: []string{"UNKNOWN"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(dispatcher.dispatched) != 0 || len(result.Validation.Errors) != 1 {
+		t.Errorf("Expected nothing dispatched and the unknown hierarchy reported, got %v and %v", dispatcher.dispatched, result.Validation.Errors)
+	}
+}
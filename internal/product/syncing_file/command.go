@@ -0,0 +1,30 @@
+package syncing_file
+
+import (
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/conflict"
+	"akeneo-migrator/kit/limit"
+)
+
+const SyncProductsFromFileCommandType bus.Type = "product.sync_file"
+
+// SyncProductsFromFileCommand represents a command to sync the product hierarchies listed in a file
+type SyncProductsFromFileCommand struct {
+	// Path is a CSV, JSON or plain text file listing the identifiers
+	Path string
+	// Workers is the number of hierarchies synchronized at the same time
+	Workers    int
+	ValuesOnly bool
+	// OnConflict overrides the strategy applied to items edited in destination since their last sync
+	OnConflict conflict.Strategy
+	// Progress is optional
+	Progress ProgressFunc
+	// Range selects the listed identifiers synced, e.g. the first 50 for a trial run
+	Range limit.Range
+	Debug bool
+}
+
+// Type returns the command type
+func (c SyncProductsFromFileCommand) Type() bus.Type {
+	return SyncProductsFromFileCommandType
+}
@@ -0,0 +1,221 @@
+package syncing_file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"akeneo-migrator/internal/product"
+	"akeneo-migrator/internal/product/syncing"
+	"akeneo-migrator/kit/conflict"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/limit"
+	"akeneo-migrator/kit/progress"
+	"akeneo-migrator/kit/retry"
+)
+
+// DefaultWorkers is the number of hierarchies synchronized at the same time when none is given
+const DefaultWorkers = 4
+
+// HierarchyResult is the outcome of the hierarchy of one listed identifier
+type HierarchyResult struct {
+	Identifier string
+	// Synced is the number of products and models written, Failed the number that could not be
+	Synced int
+	Failed int
+	// Error is set when the hierarchy could not be synced at all, e.g. when the identifier does not exist
+	Error string
+}
+
+// ProgressFunc is called each time a hierarchy is done, with the number of hierarchies done so far
+type ProgressFunc func(done, total int, hierarchy HierarchyResult)
+
+// SyncOptions contains per-run options of a sync from a file
+type SyncOptions struct {
+	// ValuesOnly and Conflicts are applied to every hierarchy, as in syncing.SyncOptions
+	ValuesOnly bool
+	Conflicts  conflict.Strategy
+	// Workers is the number of hierarchies synchronized at the same time, DefaultWorkers when not positive
+	Workers int
+	// Progress is optional
+	Progress ProgressFunc
+	// Range selects the listed identifiers synced, in the order of the file
+	Range limit.Range
+}
+
+// Service handles the synchronization of the product hierarchies listed in a file
+type Service struct {
+	listRepo       product.IdentifierListRepository
+	syncingService *syncing.Service
+}
+
+// NewService creates a new instance of the file sync service
+// Options are passed to the composed hierarchy sync service
+func NewService(listRepo product.IdentifierListRepository, sourceRepo product.SourceRepository, destRepo product.DestRepository, opts ...syncing.Option) *Service {
+	return &Service{
+		listRepo:       listRepo,
+		syncingService: syncing.NewService(sourceRepo, destRepo, opts...),
+	}
+}
+
+// SyncResult contains the result of syncing the hierarchies listed in a file
+type SyncResult struct {
+	Path string
+	// Hierarchies are the results of the listed identifiers, in the order of the file
+	Hierarchies    []HierarchyResult
+	ModelsSynced   int
+	ProductsSynced int
+	TotalSynced    int
+	Success        bool
+	// FailedItems are the products and models that could not be written, and the listed
+	// identifiers whose hierarchy could not be synced at all
+	FailedItems []retry.Failure
+	// Conflicts are the items edited in destination since their last sync
+	Conflicts []conflict.Conflict
+	// MissingTargets are the association links dropped because their target is missing in destination
+	MissingTargets []syncing.MissingTarget
+	// DroppedAttributes are the values left out because their attribute is missing in destination
+	DroppedAttributes []syncing.DroppedAttributes
+	// Unchanged is the number of products and models matching destination, not written with changed-only updates
+	Unchanged int
+	// Planned are the writes recorded instead of being sent during a dry run
+	Planned []dryrun.Write
+}
+
+// Failures returns the items that could not be written
+func (r *SyncResult) Failures() []retry.Failure {
+	return r.FailedItems
+}
+
+// Synced returns the number of products and models written
+func (r *SyncResult) Synced() int {
+	return r.TotalSynced
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// Sync synchronizes the hierarchy of every identifier listed in a file, several at a time.
+// A listed identifier is the root of its hierarchy, like the identifier given to sync-product:
+// a product with its children or a product model with its models and variants.
+// The sync stops early only when the Abort conflict strategy finds a conflict.
+func (s *Service) Sync(ctx context.Context, path string, opts SyncOptions) (*SyncResult, error) {
+	if err := opts.Range.Validate(); err != nil {
+		return nil, err
+	}
+
+	identifiers, err := s.listRepo.Read(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(identifiers) == 0 {
+		return nil, fmt.Errorf("no identifier found in %s", path)
+	}
+
+	identifiers = limit.Slice(opts.Range, identifiers)
+	if len(identifiers) == 0 {
+		return nil, fmt.Errorf("no identifier of %s in the %s", path, opts.Range)
+	}
+
+	workers := opts.Workers
+	if workers < 1 {
+		workers = DefaultWorkers
+	}
+	if workers > len(identifiers) {
+		workers = len(identifiers)
+	}
+
+	hierarchyOpts := syncing.SyncOptions{ValuesOnly: opts.ValuesOnly, Conflicts: opts.Conflicts}
+	ctx, planned := dryrun.Collect(ctx)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	progress.Expect(ctx, len(identifiers))
+
+	results := make([]*syncing.SyncResult, len(identifiers))
+	hierarchies := make([]HierarchyResult, len(identifiers))
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var aborted error
+	done := 0
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range queue {
+				hierarchy := HierarchyResult{Identifier: identifiers[i]}
+				result, err := s.syncingService.Sync(ctx, identifiers[i], hierarchyOpts)
+
+				mu.Lock()
+				switch {
+				case errors.Is(err, conflict.ErrConflict):
+					if aborted == nil {
+						aborted = fmt.Errorf("hierarchy %s: %w", identifiers[i], err)
+					}
+					cancel()
+					hierarchy.Error = err.Error()
+				case err != nil:
+					hierarchy.Error = err.Error()
+				default:
+					results[i] = result
+					hierarchy.Synced = result.TotalSynced
+					hierarchy.Failed = len(result.Errors)
+				}
+				hierarchies[i] = hierarchy
+				done++
+				if opts.Progress != nil {
+					opts.Progress(done, len(identifiers), hierarchy)
+				}
+				mu.Unlock()
+				progress.Done(ctx, 1)
+			}
+		}()
+	}
+
+	for i := range identifiers {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case queue <- i:
+		case <-ctx.Done():
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if aborted != nil {
+		return nil, aborted
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	syncResult := &SyncResult{Path: path, Hierarchies: hierarchies}
+	for i, hierarchy := range hierarchies {
+		result := results[i]
+		if result == nil {
+			syncResult.FailedItems = append(syncResult.FailedItems, retry.Failure{Kind: syncing.KindProduct, Code: hierarchy.Identifier, Error: hierarchy.Error})
+			continue
+		}
+
+		syncResult.ModelsSynced += result.ModelsSynced
+		syncResult.ProductsSynced += result.ProductsSynced
+		syncResult.FailedItems = append(syncResult.FailedItems, result.Failures()...)
+		syncResult.Conflicts = append(syncResult.Conflicts, result.Conflicts...)
+		syncResult.MissingTargets = append(syncResult.MissingTargets, result.MissingTargets...)
+		syncResult.DroppedAttributes = append(syncResult.DroppedAttributes, result.DroppedAttributes...)
+		syncResult.Unchanged += result.Unchanged
+	}
+
+	syncResult.TotalSynced = syncResult.ModelsSynced + syncResult.ProductsSynced
+	syncResult.Planned = planned()
+	syncResult.Success = len(syncResult.FailedItems) == 0
+	return syncResult, nil
+}
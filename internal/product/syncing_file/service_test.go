@@ -0,0 +1,232 @@
+package syncing_file
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"akeneo-migrator/internal/product"
+	"akeneo-migrator/kit/limit"
+)
+
+// mockListRepository returns a fixed list of identifiers
+type mockListRepository struct {
+	identifiers []string
+}
+
+func (m *mockListRepository) Read(ctx context.Context, path string) ([]string, error) {
+	return m.identifiers, nil
+}
+
+// mockSourceRepository serves a simple product SKU-1 with a child product, and a product model
+// MODEL-1 with a sub-model holding a variant
+type mockSourceRepository struct{}
+
+func (m *mockSourceRepository) FindByIdentifier(ctx context.Context, identifier string) (product.Product, error) {
+	if identifier == "SKU-1" {
+		return product.Product{"identifier": "SKU-1"}, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockSourceRepository) FindModelByCode(ctx context.Context, code string) (product.ProductModel, error) {
+	if code == "MODEL-1" {
+		return product.ProductModel{"code": "MODEL-1"}, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockSourceRepository) FindProductsByParent(ctx context.Context, parentCode string) ([]product.Product, error) {
+	switch parentCode {
+	case "SKU-1":
+		return []product.Product{{"identifier": "SKU-1-CHILD"}}, nil
+	case "SUBMODEL-1":
+		return []product.Product{{"identifier": "VARIANT-1"}}, nil
+	}
+	return nil, nil
+}
+
+func (m *mockSourceRepository) FindModelsByParent(ctx context.Context, parentCode string) ([]product.ProductModel, error) {
+	if parentCode == "MODEL-1" {
+		return []product.ProductModel{{"code": "SUBMODEL-1", "parent": "MODEL-1"}}, nil
+	}
+	return nil, nil
+}
+
+func (m *mockSourceRepository) FindProductsUpdatedSince(ctx context.Context, updatedSince string) ([]product.Product, error) {
+	return nil, nil
+}
+
+func (m *mockSourceRepository) FindModelsUpdatedSince(ctx context.Context, updatedSince string) ([]product.ProductModel, error) {
+	return nil, nil
+}
+
+func (m *mockSourceRepository) StreamProductsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]product.Product) error) error {
+	return nil
+}
+
+func (m *mockSourceRepository) StreamModelsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]product.ProductModel) error) error {
+	return nil
+}
+
+func (m *mockSourceRepository) StreamProductsBySearch(ctx context.Context, search string, batchSize int, callback func([]product.Product) error) error {
+	return nil
+}
+
+func (m *mockSourceRepository) CountUpdatedSince(ctx context.Context, updatedSince, updatedUntil string) (int, error) {
+	return 0, nil
+}
+
+func (m *mockSourceRepository) CountProductsBySearch(ctx context.Context, search string) (int, error) {
+	return 0, nil
+}
+
+func (m *mockSourceRepository) DownloadMediaFile(ctx context.Context, code string) (product.MediaFile, error) {
+	return product.MediaFile{}, errors.New("unexpected media download")
+}
+
+// mockDestRepository records the items written; it is called by several workers at once
+type mockDestRepository struct {
+	mu       sync.Mutex
+	saved    []string
+	rejected string
+}
+
+func (m *mockDestRepository) record(code string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if code == m.rejected {
+		return errors.New("validation error")
+	}
+	m.saved = append(m.saved, code)
+	return nil
+}
+
+func (m *mockDestRepository) FindByIdentifier(ctx context.Context, identifier string) (product.Product, error) {
+	return nil, errors.New("not found")
+}
+
+func (m *mockDestRepository) Save(ctx context.Context, identifier string, productData product.Product) error {
+	return m.record(identifier)
+}
+
+func (m *mockDestRepository) FindModelByCode(ctx context.Context, code string) (product.ProductModel, error) {
+	return nil, errors.New("not found")
+}
+
+func (m *mockDestRepository) SaveModel(ctx context.Context, code string, model product.ProductModel) error {
+	return m.record(code)
+}
+
+func (m *mockDestRepository) SaveAll(ctx context.Context, products []product.Product) (map[string]error, error) {
+	failed := map[string]error{}
+	for _, prod := range products {
+		identifier, _ := prod["identifier"].(string)
+		if err := m.record(identifier); err != nil {
+			failed[identifier] = err
+		}
+	}
+	return failed, nil
+}
+
+func (m *mockDestRepository) SaveModels(ctx context.Context, models []product.ProductModel) (map[string]error, error) {
+	failed := map[string]error{}
+	for _, model := range models {
+		code, _ := model["code"].(string)
+		if err := m.record(code); err != nil {
+			failed[code] = err
+		}
+	}
+	return failed, nil
+}
+
+func (m *mockDestRepository) FindProductsByParent(ctx context.Context, parentCode string) ([]product.Product, error) {
+	return nil, nil
+}
+
+func (m *mockDestRepository) FindModelsByParent(ctx context.Context, parentCode string) ([]product.ProductModel, error) {
+	return nil, nil
+}
+
+func (m *mockDestRepository) UploadMediaFile(ctx context.Context, file product.MediaFile, target product.MediaTarget) (string, error) {
+	return "", errors.New("unexpected media upload")
+}
+
+func TestSync_SyncsListedHierarchies(t *testing.T) {
+	listRepo := &mockListRepository{identifiers: []string{"SKU-1", "MISSING", "MODEL-1"}}
+	destRepo := &mockDestRepository{rejected: "VARIANT-1"}
+
+	var progress []int
+	service := NewService(listRepo, &mockSourceRepository{}, destRepo)
+	result, err := service.Sync(context.Background(), "list.txt", SyncOptions{
+		Workers: 2,
+		Progress: func(done, total int, hierarchy HierarchyResult) {
+			if total != 3 {
+				t.Errorf("Expected 3 hierarchies in total, got %d", total)
+			}
+			progress = append(progress, done)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	sort.Strings(destRepo.saved)
+	if got := strings.Join(destRepo.saved, ","); got != "MODEL-1,SKU-1,SKU-1-CHILD,SUBMODEL-1" {
+		t.Errorf("Expected MODEL-1, SUBMODEL-1, SKU-1 and SKU-1-CHILD to be written, got %s", got)
+	}
+	if result.TotalSynced != 4 || result.ModelsSynced != 2 || result.ProductsSynced != 2 {
+		t.Errorf("Expected 2 models and 2 products synced, got %+v", result)
+	}
+	if len(progress) != 3 || progress[2] != 3 {
+		t.Errorf("Expected progress after each hierarchy, got %v", progress)
+	}
+
+	hierarchies := result.Hierarchies
+	if len(hierarchies) != 3 || hierarchies[0].Identifier != "SKU-1" || hierarchies[1].Identifier != "MISSING" || hierarchies[2].Identifier != "MODEL-1" {
+		t.Fatalf("Expected the hierarchies in the order of the file, got %+v", hierarchies)
+	}
+	if hierarchies[0].Synced != 2 || hierarchies[1].Error == "" || hierarchies[2].Synced != 2 || hierarchies[2].Failed != 1 {
+		t.Errorf("Unexpected hierarchy results %+v", hierarchies)
+	}
+
+	failures := result.Failures()
+	if len(failures) != 2 || failures[0].Code != "MISSING" || failures[1].Code != "VARIANT-1" || result.Success {
+		t.Errorf("Expected MISSING and VARIANT-1 to fail, got %v", failures)
+	}
+}
+
+func TestSync_EmptyList(t *testing.T) {
+	service := NewService(&mockListRepository{}, &mockSourceRepository{}, &mockDestRepository{})
+
+	if _, err := service.Sync(context.Background(), "empty.txt", SyncOptions{}); err == nil {
+		t.Error("Expected an error for a file without identifiers")
+	}
+}
+
+func TestSync_OnlyListedHierarchiesInRange(t *testing.T) {
+	listRepo := &mockListRepository{identifiers: []string{"MISSING", "SKU-1", "MODEL-1"}}
+	destRepo := &mockDestRepository{}
+
+	service := NewService(listRepo, &mockSourceRepository{}, destRepo)
+	result, err := service.Sync(context.Background(), "list.txt", SyncOptions{Range: limit.Range{Offset: 1, Limit: 1}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	sort.Strings(destRepo.saved)
+	if got := strings.Join(destRepo.saved, ","); got != "SKU-1,SKU-1-CHILD" {
+		t.Errorf("Expected only the hierarchy of SKU-1 to be written, got %s", got)
+	}
+	if len(result.Hierarchies) != 1 || result.Hierarchies[0].Identifier != "SKU-1" {
+		t.Errorf("Expected only SKU-1 in the result, got %+v", result.Hierarchies)
+	}
+
+	if _, err := service.Sync(context.Background(), "list.txt", SyncOptions{Range: limit.Range{Offset: 3}}); err == nil {
+		t.Error("Expected an error for a range past the end of the file")
+	}
+}
@@ -0,0 +1,31 @@
+package syncing_model
+
+import (
+	"akeneo-migrator/internal/product/syncing"
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/conflict"
+	"akeneo-migrator/kit/retry"
+)
+
+const SyncProductModelCommandType bus.Type = "product.sync_model"
+
+// SyncProductModelCommand represents a command to sync a single product model,
+// optionally with its ancestors
+type SyncProductModelCommand struct {
+	Code        string
+	WithParents bool
+	ValuesOnly  bool
+	// OnConflict overrides the strategy applied to items edited in destination since their last sync
+	OnConflict conflict.Strategy
+	Debug      bool
+}
+
+// Type returns the command type
+func (c SyncProductModelCommand) Type() bus.Type {
+	return SyncProductModelCommandType
+}
+
+// RetryItem returns the item targeted by the command
+func (c SyncProductModelCommand) RetryItem() retry.Failure {
+	return retry.Failure{Kind: syncing.KindProductModel, Code: c.Code}
+}
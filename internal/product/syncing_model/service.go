@@ -0,0 +1,138 @@
+package syncing_model
+
+import (
+	"context"
+	"fmt"
+
+	"akeneo-migrator/internal/product"
+	"akeneo-migrator/internal/product/syncing"
+	"akeneo-migrator/kit/conflict"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/logger"
+)
+
+// Service handles the synchronization of a single product model
+type Service struct {
+	sourceRepo     product.SourceRepository
+	syncingService *syncing.Service
+}
+
+// NewService creates a new instance of the product model sync service
+// Options are passed to the composed hierarchy sync service
+func NewService(sourceRepo product.SourceRepository, destRepo product.DestRepository, opts ...syncing.Option) *Service {
+	return &Service{
+		sourceRepo:     sourceRepo,
+		syncingService: syncing.NewService(sourceRepo, destRepo, opts...),
+	}
+}
+
+// SyncResult contains the result of syncing a product model
+type SyncResult struct {
+	Code string
+	// Parents are the ancestors synced before the model, root first
+	Parents      []string
+	ModelsSynced int
+	// Conflicts are the models edited in destination since their last sync
+	Conflicts []conflict.Conflict
+	// MissingTargets are the association links dropped because their target is missing in destination
+	MissingTargets []syncing.MissingTarget
+	// DroppedAttributes are the values left out because their attribute is missing in destination
+	DroppedAttributes []syncing.DroppedAttributes
+	// Unchanged is the number of products and models matching destination, not written with changed-only updates
+	Unchanged int
+	// Planned are the writes recorded instead of being sent during a dry run
+	Planned []dryrun.Write
+}
+
+// Synced returns the number of models written
+func (r *SyncResult) Synced() int {
+	return r.ModelsSynced
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// Sync synchronizes exactly one product model. Sibling models and variant products are not touched.
+// With withParents, its ancestor chain is synced first, from the root down, so the parents exist
+// in destination before their children are written.
+func (s *Service) Sync(ctx context.Context, code string, withParents bool, opts syncing.SyncOptions) (*SyncResult, error) {
+	result := &SyncResult{
+		Code:    code,
+		Parents: make([]string, 0),
+	}
+	ctx, planned := dryrun.Collect(ctx)
+
+	// 1. Get the model from source
+	model, err := s.sourceRepo.FindModelByCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("product model '%s' not found in source: %w", code, err)
+	}
+
+	// 2. Sync its ancestors, root first
+	if withParents {
+		ancestors, err := s.findAncestors(ctx, model)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := len(ancestors) - 1; i >= 0; i-- {
+			parentCode, _ := ancestors[i]["code"].(string)
+			s.syncingService.Logger().Info("   📋 Syncing parent model", logger.F("code", parentCode))
+
+			saved, err := s.syncingService.SaveModel(ctx, parentCode, ancestors[i], opts)
+			if err != nil {
+				return nil, fmt.Errorf("error saving parent model %s: %w", parentCode, err)
+			}
+
+			result.Parents = append(result.Parents, parentCode)
+			result.ModelsSynced += saved.ModelsSynced
+			result.Conflicts = append(result.Conflicts, saved.Conflicts...)
+			result.MissingTargets = append(result.MissingTargets, saved.MissingTargets...)
+			result.DroppedAttributes = append(result.DroppedAttributes, saved.DroppedAttributes...)
+			result.Unchanged += saved.Unchanged
+		}
+	}
+
+	// 3. Sync the model itself
+	s.syncingService.Logger().Info("   📋 Syncing model", logger.F("code", code))
+	saved, err := s.syncingService.SaveModel(ctx, code, model, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error saving model %s: %w", code, err)
+	}
+	result.ModelsSynced += saved.ModelsSynced
+	result.Conflicts = append(result.Conflicts, saved.Conflicts...)
+	result.MissingTargets = append(result.MissingTargets, saved.MissingTargets...)
+	result.DroppedAttributes = append(result.DroppedAttributes, saved.DroppedAttributes...)
+	result.Unchanged += saved.Unchanged
+
+	result.Planned = planned()
+	return result, nil
+}
+
+// findAncestors returns the ancestor chain of a model, closest parent first
+func (s *Service) findAncestors(ctx context.Context, model product.ProductModel) ([]product.ProductModel, error) {
+	ancestors := make([]product.ProductModel, 0)
+	visited := make(map[string]bool)
+
+	for {
+		parent, _ := model["parent"].(string)
+		if parent == "" || parent == "null" {
+			return ancestors, nil
+		}
+
+		if visited[parent] {
+			return nil, fmt.Errorf("circular parent chain at model %s", parent)
+		}
+		visited[parent] = true
+
+		parentModel, err := s.sourceRepo.FindModelByCode(ctx, parent)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching parent model %s: %w", parent, err)
+		}
+
+		ancestors = append(ancestors, parentModel)
+		model = parentModel
+	}
+}
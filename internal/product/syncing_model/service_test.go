@@ -0,0 +1,178 @@
+package syncing_model_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"akeneo-migrator/internal/product"
+	"akeneo-migrator/internal/product/syncing"
+	"akeneo-migrator/internal/product/syncing_model"
+)
+
+// MockSourceRepository is a mock of the source repository serving a fixed set of models
+type MockSourceRepository struct {
+	models map[string]product.ProductModel
+}
+
+func (m *MockSourceRepository) FindByIdentifier(ctx context.Context, identifier string) (product.Product, error) {
+	return nil, errors.New("unexpected product lookup")
+}
+
+func (m *MockSourceRepository) FindModelByCode(ctx context.Context, code string) (product.ProductModel, error) {
+	model, ok := m.models[code]
+	if !ok {
+		return nil, errors.New("model not found")
+	}
+	return model, nil
+}
+
+func (m *MockSourceRepository) FindProductsByParent(ctx context.Context, parentCode string) ([]product.Product, error) {
+	return nil, errors.New("unexpected children lookup")
+}
+
+func (m *MockSourceRepository) FindModelsByParent(ctx context.Context, parentCode string) ([]product.ProductModel, error) {
+	return nil, errors.New("unexpected children lookup")
+}
+
+func (m *MockSourceRepository) FindProductsUpdatedSince(ctx context.Context, updatedSince string) ([]product.Product, error) {
+	return nil, nil
+}
+
+func (m *MockSourceRepository) FindModelsUpdatedSince(ctx context.Context, updatedSince string) ([]product.ProductModel, error) {
+	return nil, nil
+}
+
+func (m *MockSourceRepository) StreamProductsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]product.Product) error) error {
+	return nil
+}
+
+func (m *MockSourceRepository) StreamModelsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]product.ProductModel) error) error {
+	return nil
+}
+
+func (m *MockSourceRepository) StreamProductsBySearch(ctx context.Context, search string, batchSize int, callback func([]product.Product) error) error {
+	return nil
+}
+
+func (m *MockSourceRepository) CountUpdatedSince(ctx context.Context, updatedSince, updatedUntil string) (int, error) {
+	return 0, nil
+}
+
+func (m *MockSourceRepository) CountProductsBySearch(ctx context.Context, search string) (int, error) {
+	return 0, nil
+}
+
+func (m *MockSourceRepository) DownloadMediaFile(ctx context.Context, code string) (product.MediaFile, error) {
+	return product.MediaFile{}, errors.New("unexpected media download")
+}
+
+// MockDestRepository is a mock of the destination repository recording saved models
+type MockDestRepository struct {
+	savedModels []string
+}
+
+func (m *MockDestRepository) FindByIdentifier(ctx context.Context, identifier string) (product.Product, error) {
+	return nil, errors.New("not found")
+}
+
+func (m *MockDestRepository) Save(ctx context.Context, identifier string, productData product.Product) error {
+	return errors.New("unexpected product save")
+}
+
+func (m *MockDestRepository) FindModelByCode(ctx context.Context, code string) (product.ProductModel, error) {
+	return nil, errors.New("not found")
+}
+
+func (m *MockDestRepository) SaveModel(ctx context.Context, code string, model product.ProductModel) error {
+	m.savedModels = append(m.savedModels, code)
+	return nil
+}
+
+func (m *MockDestRepository) SaveAll(ctx context.Context, products []product.Product) (map[string]error, error) {
+	return nil, errors.New("unexpected product save")
+}
+
+func (m *MockDestRepository) SaveModels(ctx context.Context, models []product.ProductModel) (map[string]error, error) {
+	return nil, errors.New("unexpected model batch save")
+}
+
+func (m *MockDestRepository) FindProductsByParent(ctx context.Context, parentCode string) ([]product.Product, error) {
+	return nil, nil
+}
+
+func (m *MockDestRepository) FindModelsByParent(ctx context.Context, parentCode string) ([]product.ProductModel, error) {
+	return nil, nil
+}
+
+func (m *MockDestRepository) UploadMediaFile(ctx context.Context, file product.MediaFile, target product.MediaTarget) (string, error) {
+	return "", errors.New("unexpected media upload")
+}
+
+func newSourceRepository() *MockSourceRepository {
+	return &MockSourceRepository{models: map[string]product.ProductModel{
+		"COMMON-001":     {"code": "COMMON-001", "parent": nil},
+		"MODEL-001":      {"code": "MODEL-001", "parent": "COMMON-001"},
+		"MODEL-001-BLUE": {"code": "MODEL-001-BLUE", "parent": "MODEL-001"},
+	}}
+}
+
+func TestSync_OnlyTheModel(t *testing.T) {
+	destRepo := &MockDestRepository{}
+	service := syncing_model.NewService(newSourceRepository(), destRepo)
+
+	result, err := service.Sync(context.Background(), "MODEL-001-BLUE", false, syncing.SyncOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(destRepo.savedModels) != 1 || destRepo.savedModels[0] != "MODEL-001-BLUE" {
+		t.Errorf("Expected only the model to be saved, got %v", destRepo.savedModels)
+	}
+
+	if result.ModelsSynced != 1 || len(result.Parents) != 0 {
+		t.Errorf("Expected 1 model and no parents, got %+v", result)
+	}
+}
+
+func TestSync_WithParentsRootFirst(t *testing.T) {
+	destRepo := &MockDestRepository{}
+	service := syncing_model.NewService(newSourceRepository(), destRepo)
+
+	result, err := service.Sync(context.Background(), "MODEL-001-BLUE", true, syncing.SyncOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{"COMMON-001", "MODEL-001", "MODEL-001-BLUE"}
+	if len(destRepo.savedModels) != len(expected) {
+		t.Fatalf("Expected %v to be saved, got %v", expected, destRepo.savedModels)
+	}
+	for i, code := range expected {
+		if destRepo.savedModels[i] != code {
+			t.Errorf("Expected %s at position %d, got %s", code, i, destRepo.savedModels[i])
+		}
+	}
+
+	if result.ModelsSynced != 3 || len(result.Parents) != 2 {
+		t.Errorf("Expected 3 models including 2 parents, got %+v", result)
+	}
+}
+
+func TestSync_MissingParent(t *testing.T) {
+	sourceRepo := &MockSourceRepository{models: map[string]product.ProductModel{
+		"MODEL-001": {"code": "MODEL-001", "parent": "UNKNOWN"},
+	}}
+	destRepo := &MockDestRepository{}
+	service := syncing_model.NewService(sourceRepo, destRepo)
+
+	if _, err := service.Sync(context.Background(), "MODEL-001", true, syncing.SyncOptions{}); err == nil {
+		t.Error("Expected error when a parent is missing in source")
+	}
+
+	if len(destRepo.savedModels) != 0 {
+		t.Errorf("Expected nothing to be saved, got %v", destRepo.savedModels)
+	}
+}
@@ -0,0 +1,21 @@
+package syncing_published
+
+import (
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/conflict"
+)
+
+const SyncPublishedProductsCommandType bus.Type = "product.sync_published"
+
+// SyncPublishedProductsCommand represents a command to sync the published products of the source
+type SyncPublishedProductsCommand struct {
+	ValuesOnly bool
+	// OnConflict overrides the strategy applied to items edited in destination since their last sync
+	OnConflict conflict.Strategy
+	Debug      bool
+}
+
+// Type returns the command type
+func (c SyncPublishedProductsCommand) Type() bus.Type {
+	return SyncPublishedProductsCommandType
+}
@@ -0,0 +1,171 @@
+package syncing_published
+
+import (
+	"context"
+
+	"akeneo-migrator/internal/product"
+	"akeneo-migrator/internal/product/syncing"
+	"akeneo-migrator/kit/checksum"
+	"akeneo-migrator/kit/conflict"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/retry"
+)
+
+// BatchSize is the number of published products fetched from source and written to destination at a time
+const BatchSize = 100
+
+// Service handles the synchronization of published products (Enterprise Edition)
+type Service struct {
+	sourceRepo      product.SourceRepository
+	sourcePublished product.PublishedRepository
+	destPublished   product.PublishedRepository
+	syncingService  *syncing.Service
+}
+
+// NewService creates a new instance of the published products sync service
+// Options are passed to the composed hierarchy sync service
+func NewService(sourceRepo product.SourceRepository, destRepo product.DestRepository, sourcePublished, destPublished product.PublishedRepository, opts ...syncing.Option) *Service {
+	return &Service{
+		sourceRepo:      sourceRepo,
+		sourcePublished: sourcePublished,
+		destPublished:   destPublished,
+		syncingService:  syncing.NewService(sourceRepo, destRepo, opts...),
+	}
+}
+
+// Publication is a product to publish in destination once its working copy is synced
+type Publication struct {
+	Identifier string
+	// FirstPublication is true when the product has never been published in destination
+	FirstPublication bool
+}
+
+// SyncResult contains the result of syncing published products
+type SyncResult struct {
+	Published      int
+	ProductsSynced int
+	// UpToDate counts the products whose published version in destination already matches the source
+	UpToDate int
+	// ToPublish are the products to publish (again) in destination
+	ToPublish []Publication
+	Success   bool
+	// FailedItems are the products that could not be written
+	FailedItems []retry.Failure
+	// Conflicts are the products edited in destination since their last sync
+	Conflicts []conflict.Conflict
+	// MissingTargets are the association links dropped because their target is missing in destination
+	MissingTargets []syncing.MissingTarget
+	// DroppedAttributes are the values left out because their attribute is missing in destination
+	DroppedAttributes []syncing.DroppedAttributes
+	// Unchanged is the number of products and models matching destination, not written with changed-only updates
+	Unchanged int
+	// Planned are the writes recorded instead of being sent during a dry run
+	Planned []dryrun.Write
+}
+
+// Failures returns the products that could not be written
+func (r *SyncResult) Failures() []retry.Failure {
+	return r.FailedItems
+}
+
+// Synced returns the number of products written
+func (r *SyncResult) Synced() int {
+	return r.ProductsSynced
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// Sync synchronizes the working copy of every product published in source, then compares the
+// published versions to list the products to publish in destination.
+// The API cannot publish products, so the last step only reports them.
+func (s *Service) Sync(ctx context.Context, opts syncing.SyncOptions) (*SyncResult, error) {
+	result := &SyncResult{}
+	ctx, planned := dryrun.Collect(ctx)
+
+	err := s.sourcePublished.StreamPublished(ctx, BatchSize, func(published []product.Product) error {
+		result.Published += len(published)
+
+		// 1. Sync the working copies of the batch
+		failed := make(map[string]bool)
+		workingCopies := make([]product.Product, 0, len(published))
+		for _, item := range published {
+			identifier, _ := item["identifier"].(string)
+			if identifier == "" {
+				continue
+			}
+
+			workingCopy, err := s.sourceRepo.FindByIdentifier(ctx, identifier)
+			if err != nil {
+				failed[identifier] = true
+				result.FailedItems = append(result.FailedItems, retry.Failure{Kind: syncing.KindProduct, Code: identifier, Error: err.Error()})
+				continue
+			}
+			workingCopies = append(workingCopies, workingCopy)
+		}
+
+		batchResult, err := s.syncingService.SaveProducts(ctx, workingCopies, opts)
+		if err != nil {
+			return err
+		}
+		result.ProductsSynced += batchResult.ProductsSynced
+		result.Conflicts = append(result.Conflicts, batchResult.Conflicts...)
+		result.MissingTargets = append(result.MissingTargets, batchResult.MissingTargets...)
+		result.DroppedAttributes = append(result.DroppedAttributes, batchResult.DroppedAttributes...)
+		result.Unchanged += batchResult.Unchanged
+		result.FailedItems = append(result.FailedItems, batchResult.Failures()...)
+		for _, syncErr := range batchResult.Errors {
+			failed[syncErr.Code] = true
+		}
+
+		// 2. Compare the published versions of the products written
+		for _, item := range published {
+			identifier, _ := item["identifier"].(string)
+			if identifier == "" || failed[identifier] {
+				continue
+			}
+
+			if err := s.checkPublication(ctx, identifier, item, result); err != nil {
+				result.FailedItems = append(result.FailedItems, retry.Failure{Kind: syncing.KindProduct, Code: identifier, Error: err.Error()})
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Planned = planned()
+	result.Success = len(result.FailedItems) == 0
+	return result, nil
+}
+
+// checkPublication records whether a product must be published in destination
+func (s *Service) checkPublication(ctx context.Context, identifier string, sourcePublished product.Product, result *SyncResult) error {
+	// A product without published version in destination has never been published there
+	destPublished, err := s.destPublished.FindPublished(ctx, identifier)
+	if err != nil {
+		result.ToPublish = append(result.ToPublish, Publication{Identifier: identifier, FirstPublication: true})
+		return nil
+	}
+
+	sourceSum, err := checksum.Compute(sourcePublished)
+	if err != nil {
+		return err
+	}
+	destSum, err := checksum.Compute(destPublished)
+	if err != nil {
+		return err
+	}
+
+	if sourceSum == destSum {
+		result.UpToDate++
+		return nil
+	}
+
+	result.ToPublish = append(result.ToPublish, Publication{Identifier: identifier})
+	return nil
+}
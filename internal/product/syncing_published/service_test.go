@@ -0,0 +1,192 @@
+package syncing_published
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"akeneo-migrator/internal/product"
+	"akeneo-migrator/internal/product/syncing"
+)
+
+// mockSourceRepository serves working copies of products
+type mockSourceRepository struct {
+	products map[string]product.Product
+}
+
+func (m *mockSourceRepository) FindByIdentifier(ctx context.Context, identifier string) (product.Product, error) {
+	if prod, ok := m.products[identifier]; ok {
+		return prod, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockSourceRepository) FindModelByCode(ctx context.Context, code string) (product.ProductModel, error) {
+	return nil, errors.New("not found")
+}
+
+func (m *mockSourceRepository) FindProductsByParent(ctx context.Context, parentCode string) ([]product.Product, error) {
+	return nil, nil
+}
+
+func (m *mockSourceRepository) FindModelsByParent(ctx context.Context, parentCode string) ([]product.ProductModel, error) {
+	return nil, nil
+}
+
+func (m *mockSourceRepository) FindProductsUpdatedSince(ctx context.Context, updatedSince string) ([]product.Product, error) {
+	return nil, nil
+}
+
+func (m *mockSourceRepository) FindModelsUpdatedSince(ctx context.Context, updatedSince string) ([]product.ProductModel, error) {
+	return nil, nil
+}
+
+func (m *mockSourceRepository) StreamProductsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]product.Product) error) error {
+	return nil
+}
+
+func (m *mockSourceRepository) StreamModelsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]product.ProductModel) error) error {
+	return nil
+}
+
+func (m *mockSourceRepository) StreamProductsBySearch(ctx context.Context, search string, batchSize int, callback func([]product.Product) error) error {
+	return nil
+}
+
+func (m *mockSourceRepository) CountUpdatedSince(ctx context.Context, updatedSince, updatedUntil string) (int, error) {
+	return 0, nil
+}
+
+func (m *mockSourceRepository) CountProductsBySearch(ctx context.Context, search string) (int, error) {
+	return 0, nil
+}
+
+func (m *mockSourceRepository) DownloadMediaFile(ctx context.Context, code string) (product.MediaFile, error) {
+	return product.MediaFile{}, errors.New("unexpected media download")
+}
+
+// mockDestRepository records the products written in batches
+type mockDestRepository struct {
+	saved    []string
+	rejected string
+}
+
+func (m *mockDestRepository) FindByIdentifier(ctx context.Context, identifier string) (product.Product, error) {
+	return nil, errors.New("not found")
+}
+
+func (m *mockDestRepository) Save(ctx context.Context, identifier string, productData product.Product) error {
+	return errors.New("unexpected single save")
+}
+
+func (m *mockDestRepository) FindModelByCode(ctx context.Context, code string) (product.ProductModel, error) {
+	return nil, errors.New("not found")
+}
+
+func (m *mockDestRepository) SaveModel(ctx context.Context, code string, model product.ProductModel) error {
+	return errors.New("unexpected model save")
+}
+
+func (m *mockDestRepository) SaveAll(ctx context.Context, products []product.Product) (map[string]error, error) {
+	failed := map[string]error{}
+	for _, prod := range products {
+		identifier, _ := prod["identifier"].(string)
+		if identifier == m.rejected {
+			failed[identifier] = errors.New("validation error")
+			continue
+		}
+		m.saved = append(m.saved, identifier)
+	}
+	return failed, nil
+}
+
+func (m *mockDestRepository) SaveModels(ctx context.Context, models []product.ProductModel) (map[string]error, error) {
+	return nil, errors.New("unexpected model save")
+}
+
+func (m *mockDestRepository) FindProductsByParent(ctx context.Context, parentCode string) ([]product.Product, error) {
+	return nil, nil
+}
+
+func (m *mockDestRepository) FindModelsByParent(ctx context.Context, parentCode string) ([]product.ProductModel, error) {
+	return nil, nil
+}
+
+func (m *mockDestRepository) UploadMediaFile(ctx context.Context, file product.MediaFile, target product.MediaTarget) (string, error) {
+	return "", errors.New("unexpected media upload")
+}
+
+// mockPublishedRepository serves published products
+type mockPublishedRepository struct {
+	published map[string]product.Product
+	order     []string
+}
+
+func (m *mockPublishedRepository) StreamPublished(ctx context.Context, batchSize int, callback func([]product.Product) error) error {
+	batch := make([]product.Product, 0, len(m.order))
+	for _, identifier := range m.order {
+		batch = append(batch, m.published[identifier])
+	}
+	return callback(batch)
+}
+
+func (m *mockPublishedRepository) FindPublished(ctx context.Context, identifier string) (product.Product, error) {
+	if published, ok := m.published[identifier]; ok {
+		return published, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func TestSync_SyncsWorkingCopiesAndListsProductsToPublish(t *testing.T) {
+	published := func(identifier, name string) product.Product {
+		return product.Product{"identifier": identifier, "values": map[string]interface{}{
+			"name": []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": name}},
+		}}
+	}
+
+	sourceRepo := &mockSourceRepository{products: map[string]product.Product{
+		"NEW":      {"identifier": "NEW"},
+		"OUTDATED": {"identifier": "OUTDATED"},
+		"CURRENT":  {"identifier": "CURRENT"},
+		"REJECTED": {"identifier": "REJECTED"},
+	}}
+	destRepo := &mockDestRepository{rejected: "REJECTED"}
+	sourcePublished := &mockPublishedRepository{
+		order: []string{"NEW", "OUTDATED", "CURRENT", "REJECTED", "DELETED"},
+		published: map[string]product.Product{
+			"NEW":      published("NEW", "New"),
+			"OUTDATED": published("OUTDATED", "Version 2"),
+			"CURRENT":  published("CURRENT", "Current"),
+			"REJECTED": published("REJECTED", "Rejected"),
+			"DELETED":  published("DELETED", "Deleted"),
+		},
+	}
+	destPublished := &mockPublishedRepository{published: map[string]product.Product{
+		"OUTDATED": published("OUTDATED", "Version 1"),
+		"CURRENT":  published("CURRENT", "Current"),
+	}}
+
+	service := NewService(sourceRepo, destRepo, sourcePublished, destPublished)
+	result, err := service.Sync(context.Background(), syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Published != 5 || result.ProductsSynced != 3 || len(destRepo.saved) != 3 {
+		t.Errorf("Expected 3 of 5 working copies synced, got %+v", result)
+	}
+
+	if result.UpToDate != 1 {
+		t.Errorf("Expected CURRENT to be up to date, got %d", result.UpToDate)
+	}
+
+	expected := []Publication{{Identifier: "NEW", FirstPublication: true}, {Identifier: "OUTDATED"}}
+	if len(result.ToPublish) != len(expected) || result.ToPublish[0] != expected[0] || result.ToPublish[1] != expected[1] {
+		t.Errorf("Expected %v to publish, got %v", expected, result.ToPublish)
+	}
+
+	// The rejected product and the one without working copy are reported, not listed for publication
+	if len(result.Failures()) != 2 || result.Success {
+		t.Errorf("Expected 2 failures, got %v", result.Failures())
+	}
+}
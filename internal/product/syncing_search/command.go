@@ -0,0 +1,26 @@
+package syncing_search
+
+import (
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/conflict"
+	"akeneo-migrator/kit/limit"
+)
+
+const SyncProductsBySearchCommandType bus.Type = "product.sync_search"
+
+// SyncProductsBySearchCommand represents a command to sync the products matching a search filter
+type SyncProductsBySearchCommand struct {
+	// Search is the product query filter, in the JSON search syntax of the Akeneo API
+	Search     string
+	ValuesOnly bool
+	// OnConflict overrides the strategy applied to items edited in destination since their last sync
+	OnConflict conflict.Strategy
+	// Range selects the matching products synced, e.g. the first 50 for a trial run
+	Range limit.Range
+	Debug bool
+}
+
+// Type returns the command type
+func (c SyncProductsBySearchCommand) Type() bus.Type {
+	return SyncProductsBySearchCommandType
+}
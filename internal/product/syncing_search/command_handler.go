@@ -0,0 +1,35 @@
+package syncing_search
+
+import (
+	"context"
+
+	"akeneo-migrator/internal/product/syncing"
+	"akeneo-migrator/kit/bus"
+)
+
+// CommandHandler handles SyncProductsBySearchCommand
+type CommandHandler struct {
+	service *Service
+}
+
+// NewCommandHandler creates a new command handler
+func NewCommandHandler(service *Service) *CommandHandler {
+	return &CommandHandler{
+		service: service,
+	}
+}
+
+// Handle executes the sync command
+func (h *CommandHandler) Handle(ctx context.Context, msg bus.Message) (bus.Response, error) {
+	cmd, ok := msg.(SyncProductsBySearchCommand)
+	if !ok {
+		return bus.Response{}, nil
+	}
+
+	result, err := h.service.Sync(ctx, cmd.Search, syncing.SyncOptions{ValuesOnly: cmd.ValuesOnly, Conflicts: cmd.OnConflict, Range: cmd.Range})
+	if err != nil {
+		return bus.Response{Error: err}, err
+	}
+
+	return bus.Response{Data: result}, nil
+}
@@ -0,0 +1,178 @@
+package syncing_search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"akeneo-migrator/internal/product"
+	"akeneo-migrator/internal/product/syncing"
+	"akeneo-migrator/kit/conflict"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/limit"
+	"akeneo-migrator/kit/logger"
+	"akeneo-migrator/kit/progress"
+	"akeneo-migrator/kit/retry"
+	"akeneo-migrator/kit/workers"
+)
+
+// BatchSize is the number of matching products fetched from source and written to destination at a time
+const BatchSize = 100
+
+// Service handles the synchronization of the products matching a search filter
+type Service struct {
+	sourceRepo     product.SourceRepository
+	syncingService *syncing.Service
+}
+
+// NewService creates a new instance of the search sync service
+// Options are passed to the composed hierarchy sync service
+func NewService(sourceRepo product.SourceRepository, destRepo product.DestRepository, opts ...syncing.Option) *Service {
+	return &Service{
+		sourceRepo:     sourceRepo,
+		syncingService: syncing.NewService(sourceRepo, destRepo, opts...),
+	}
+}
+
+// SyncResult contains the result of syncing the products matching a search filter
+type SyncResult struct {
+	Search         string
+	Matched        int
+	ProductsSynced int
+	Success        bool
+	// FailedItems are the products that could not be written
+	FailedItems []retry.Failure
+	// Conflicts are the products edited in destination since their last sync
+	Conflicts []conflict.Conflict
+	// MissingTargets are the association links dropped because their target is missing in destination
+	MissingTargets []syncing.MissingTarget
+	// DroppedAttributes are the values left out because their attribute is missing in destination
+	DroppedAttributes []syncing.DroppedAttributes
+	// Unchanged is the number of products and models matching destination, not written with changed-only updates
+	Unchanged int
+	// Planned are the writes recorded instead of being sent during a dry run
+	Planned []dryrun.Write
+}
+
+// Failures returns the products that could not be written
+func (r *SyncResult) Failures() []retry.Failure {
+	return r.FailedItems
+}
+
+// Synced returns the number of products written
+func (r *SyncResult) Synced() int {
+	return r.ProductsSynced
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// Sync synchronizes the products of the source matching a search filter, in the JSON search syntax
+// of the Akeneo API. Only the matching products are written, without their hierarchy: the parent
+// models of variant products must already exist in destination. Batches are written on a worker
+// pool; an error writing a batch, e.g. a conflict aborting the sync, stops the stream. The range of
+// the options selects the matching products written; the stream stops after its last one.
+func (s *Service) Sync(ctx context.Context, search string, opts syncing.SyncOptions) (*SyncResult, error) {
+	if err := ValidateSearch(search); err != nil {
+		return nil, err
+	}
+	if err := opts.Range.Validate(); err != nil {
+		return nil, err
+	}
+
+	result := &SyncResult{Search: search}
+	ctx, planned := dryrun.Collect(ctx)
+
+	// The count only sizes the progress, the sync goes on without it
+	if total, err := s.sourceRepo.CountProductsBySearch(ctx, search); err == nil {
+		progress.Expect(ctx, opts.Range.Count(total))
+	}
+
+	pool := workers.New(s.syncingService.Workers(opts))
+	cursor := opts.Range.Start()
+	var saveErr error
+	err := s.sourceRepo.StreamProductsBySearch(ctx, search, BatchSize, func(products []product.Product) error {
+		products, reached := limit.Take(cursor, products)
+		if len(products) == 0 {
+			return reached
+		}
+
+		result.Matched += len(products)
+		s.syncingService.Logger().Info("   🔄 Syncing matching products", logger.F("count", len(products)), logger.F("matched", result.Matched))
+
+		pool.Go(func() {
+			batchResult, err := s.syncingService.SaveProducts(ctx, products, opts)
+			pool.Merge(func() {
+				if err != nil {
+					if saveErr == nil {
+						saveErr = err
+					}
+					return
+				}
+				result.ProductsSynced += batchResult.ProductsSynced
+				result.Conflicts = append(result.Conflicts, batchResult.Conflicts...)
+				result.MissingTargets = append(result.MissingTargets, batchResult.MissingTargets...)
+				result.DroppedAttributes = append(result.DroppedAttributes, batchResult.DroppedAttributes...)
+				result.Unchanged += batchResult.Unchanged
+				result.FailedItems = append(result.FailedItems, batchResult.Failures()...)
+			})
+			progress.Done(ctx, len(products))
+		})
+
+		stop := reached
+		pool.Merge(func() {
+			if saveErr != nil {
+				stop = saveErr
+			}
+		})
+		return stop
+	})
+	pool.Wait()
+	if err == nil || errors.Is(err, limit.ErrReached) {
+		err = saveErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching products matching the search: %w", err)
+	}
+
+	result.Planned = planned()
+	result.Success = len(result.FailedItems) == 0
+	return result, nil
+}
+
+// ValidateSearch checks that a search filter follows the syntax of the Akeneo API before anything is
+// fetched: an object of properties, each with a list of conditions naming their operator, e.g.
+// {"enabled":[{"operator":"=","value":true}]}. An empty filter is rejected, since it would select
+// the whole catalog.
+func ValidateSearch(search string) error {
+	var filters map[string][]map[string]interface{}
+	if err := json.Unmarshal([]byte(search), &filters); err != nil {
+		return fmt.Errorf("invalid search filter, expected {\"property\":[{\"operator\":...,\"value\":...}]}: %w", err)
+	}
+	if len(filters) == 0 {
+		return fmt.Errorf("the search filter has no condition")
+	}
+
+	properties := make([]string, 0, len(filters))
+	for property := range filters {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+
+	for _, property := range properties {
+		if len(filters[property]) == 0 {
+			return fmt.Errorf("invalid search filter: %s has no condition", property)
+		}
+		for _, condition := range filters[property] {
+			if operator, _ := condition["operator"].(string); operator == "" {
+				return fmt.Errorf("invalid search filter: a condition on %s has no operator", property)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package syncing_search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"akeneo-migrator/internal/product"
+	"akeneo-migrator/internal/product/syncing"
+	"akeneo-migrator/kit/limit"
+	"akeneo-migrator/kit/progress"
+)
+
+// mockSourceRepository serves the products matching a search in batches
+type mockSourceRepository struct {
+	batches  [][]product.Product
+	searches []string
+	// streamed is the number of batches passed to the stream callback
+	streamed int
+}
+
+func (m *mockSourceRepository) FindByIdentifier(ctx context.Context, identifier string) (product.Product, error) {
+	return nil, errors.New("not found")
+}
+
+func (m *mockSourceRepository) FindModelByCode(ctx context.Context, code string) (product.ProductModel, error) {
+	return nil, errors.New("not found")
+}
+
+func (m *mockSourceRepository) FindProductsByParent(ctx context.Context, parentCode string) ([]product.Product, error) {
+	return nil, nil
+}
+
+func (m *mockSourceRepository) FindModelsByParent(ctx context.Context, parentCode string) ([]product.ProductModel, error) {
+	return nil, nil
+}
+
+func (m *mockSourceRepository) FindProductsUpdatedSince(ctx context.Context, updatedSince string) ([]product.Product, error) {
+	return nil, nil
+}
+
+func (m *mockSourceRepository) FindModelsUpdatedSince(ctx context.Context, updatedSince string) ([]product.ProductModel, error) {
+	return nil, nil
+}
+
+func (m *mockSourceRepository) StreamProductsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]product.Product) error) error {
+	return nil
+}
+
+func (m *mockSourceRepository) StreamModelsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]product.ProductModel) error) error {
+	return nil
+}
+
+func (m *mockSourceRepository) StreamProductsBySearch(ctx context.Context, search string, batchSize int, callback func([]product.Product) error) error {
+	m.searches = append(m.searches, search)
+	for _, batch := range m.batches {
+		m.streamed++
+		if err := callback(batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockSourceRepository) CountUpdatedSince(ctx context.Context, updatedSince, updatedUntil string) (int, error) {
+	return 0, nil
+}
+
+func (m *mockSourceRepository) CountProductsBySearch(ctx context.Context, search string) (int, error) {
+	count := 0
+	for _, batch := range m.batches {
+		count += len(batch)
+	}
+	return count, nil
+}
+
+func (m *mockSourceRepository) DownloadMediaFile(ctx context.Context, code string) (product.MediaFile, error) {
+	return product.MediaFile{}, errors.New("unexpected media download")
+}
+
+// mockDestRepository records the products written in batches, possibly by several workers
+type mockDestRepository struct {
+	mu       sync.Mutex
+	saved    []string
+	rejected string
+}
+
+func (m *mockDestRepository) FindByIdentifier(ctx context.Context, identifier string) (product.Product, error) {
+	return nil, errors.New("not found")
+}
+
+func (m *mockDestRepository) Save(ctx context.Context, identifier string, productData product.Product) error {
+	return errors.New("unexpected single save")
+}
+
+func (m *mockDestRepository) FindModelByCode(ctx context.Context, code string) (product.ProductModel, error) {
+	return nil, errors.New("not found")
+}
+
+func (m *mockDestRepository) SaveModel(ctx context.Context, code string, model product.ProductModel) error {
+	return errors.New("unexpected model save")
+}
+
+func (m *mockDestRepository) SaveAll(ctx context.Context, products []product.Product) (map[string]error, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	failed := map[string]error{}
+	for _, prod := range products {
+		identifier, _ := prod["identifier"].(string)
+		if identifier == m.rejected {
+			failed[identifier] = errors.New("validation error")
+			continue
+		}
+		m.saved = append(m.saved, identifier)
+	}
+	return failed, nil
+}
+
+func (m *mockDestRepository) SaveModels(ctx context.Context, models []product.ProductModel) (map[string]error, error) {
+	return nil, errors.New("unexpected model save")
+}
+
+func (m *mockDestRepository) FindProductsByParent(ctx context.Context, parentCode string) ([]product.Product, error) {
+	return nil, nil
+}
+
+func (m *mockDestRepository) FindModelsByParent(ctx context.Context, parentCode string) ([]product.ProductModel, error) {
+	return nil, nil
+}
+
+func (m *mockDestRepository) UploadMediaFile(ctx context.Context, file product.MediaFile, target product.MediaTarget) (string, error) {
+	return "", errors.New("unexpected media upload")
+}
+
+func TestSync_WritesMatchingProducts(t *testing.T) {
+	search := `{"enabled":[{"operator":"=","value":true}],"family":[{"operator":"IN","value":["shoes"]}]}`
+	sourceRepo := &mockSourceRepository{batches: [][]product.Product{
+		{{"identifier": "SKU-1"}, {"identifier": "SKU-2"}},
+		{{"identifier": "SKU-3"}},
+	}}
+	destRepo := &mockDestRepository{rejected: "SKU-2"}
+
+	service := NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background(), search, syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(sourceRepo.searches) != 1 || sourceRepo.searches[0] != search {
+		t.Errorf("Expected the search to be sent as is, got %v", sourceRepo.searches)
+	}
+	if result.Matched != 3 || result.ProductsSynced != 2 || len(destRepo.saved) != 2 {
+		t.Errorf("Expected 2 of 3 matching products synced, got %+v", result)
+	}
+	if len(result.Failures()) != 1 || result.Failures()[0].Code != "SKU-2" || result.Success {
+		t.Errorf("Expected SKU-2 to fail, got %v", result.Failures())
+	}
+}
+
+func TestSync_WritesBatchesConcurrentlyWithWorkers(t *testing.T) {
+	sourceRepo := &mockSourceRepository{}
+	for i := 0; i < 6; i++ {
+		sourceRepo.batches = append(sourceRepo.batches, []product.Product{
+			{"identifier": fmt.Sprintf("SKU-%d-a", i)},
+			{"identifier": fmt.Sprintf("SKU-%d-b", i)},
+		})
+	}
+	destRepo := &mockDestRepository{rejected: "SKU-4-b"}
+
+	service := NewService(sourceRepo, destRepo, syncing.WithWorkers(3))
+	result, err := service.Sync(context.Background(), `{"enabled":[{"operator":"=","value":true}]}`, syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Matched != 12 || result.ProductsSynced != 11 || len(destRepo.saved) != 11 {
+		t.Errorf("Expected the outcome of every batch to be merged, got %+v", result)
+	}
+	if len(result.Failures()) != 1 || result.Failures()[0].Code != "SKU-4-b" {
+		t.Errorf("Expected SKU-4-b to fail, got %v", result.Failures())
+	}
+}
+
+// recordingReporter records the progress reported by a sync
+type recordingReporter struct {
+	mu       sync.Mutex
+	expected int
+	done     int
+}
+
+func (r *recordingReporter) Expect(items int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.expected += items
+}
+
+func (r *recordingReporter) Done(items int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.done += items
+}
+
+func TestSync_ReportsProgress(t *testing.T) {
+	sourceRepo := &mockSourceRepository{batches: [][]product.Product{
+		{{"identifier": "SKU-1"}, {"identifier": "SKU-2"}},
+		{{"identifier": "SKU-3"}},
+	}}
+	reporter := &recordingReporter{}
+	ctx := progress.With(context.Background(), reporter)
+
+	service := NewService(sourceRepo, &mockDestRepository{rejected: "SKU-2"})
+	if _, err := service.Sync(ctx, `{"enabled":[{"operator":"=","value":true}]}`, syncing.SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if reporter.expected != 3 || reporter.done != 3 {
+		t.Errorf("Expected 3 of 3 products reported, failed ones included, got %d of %d", reporter.done, reporter.expected)
+	}
+}
+
+func TestSync_OnlyProductsInRange(t *testing.T) {
+	sourceRepo := &mockSourceRepository{}
+	for i := 0; i < 4; i++ {
+		sourceRepo.batches = append(sourceRepo.batches, []product.Product{
+			{"identifier": fmt.Sprintf("SKU-%d-a", i)},
+			{"identifier": fmt.Sprintf("SKU-%d-b", i)},
+		})
+	}
+	destRepo := &mockDestRepository{}
+	reporter := &recordingReporter{}
+	ctx := progress.With(context.Background(), reporter)
+
+	opts := syncing.SyncOptions{Range: limit.Range{Offset: 1, Limit: 4}}
+	result, err := NewService(sourceRepo, destRepo).Sync(ctx, `{"enabled":[{"operator":"=","value":true}]}`, opts)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Matched != 4 || fmt.Sprint(destRepo.saved) != "[SKU-0-b SKU-1-a SKU-1-b SKU-2-a]" {
+		t.Errorf("Expected the 2nd to 5th products written, got %v", destRepo.saved)
+	}
+	if sourceRepo.streamed != 3 {
+		t.Errorf("Expected the stream to stop after the last product of the range, got %d batches", sourceRepo.streamed)
+	}
+	if reporter.expected != 4 {
+		t.Errorf("Expected the progress to expect the 4 products of the range, got %d", reporter.expected)
+	}
+}
+
+func TestSync_RejectsInvalidSearch(t *testing.T) {
+	tests := []struct {
+		name   string
+		search string
+	}{
+		{"not json", `enabled=true`},
+		{"no condition", `{}`},
+		{"empty conditions", `{"enabled":[]}`},
+		{"missing operator", `{"enabled":[{"value":true}]}`},
+		{"condition not in a list", `{"enabled":{"operator":"=","value":true}}`},
+	}
+
+	for _, tt := range tests {
+		sourceRepo := &mockSourceRepository{}
+		service := NewService(sourceRepo, &mockDestRepository{})
+
+		if _, err := service.Sync(context.Background(), tt.search, syncing.SyncOptions{}); err == nil {
+			t.Errorf("%s: expected the search to be rejected", tt.name)
+		}
+		if len(sourceRepo.searches) != 0 {
+			t.Errorf("%s: expected nothing to be fetched", tt.name)
+		}
+	}
+}
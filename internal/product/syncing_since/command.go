@@ -1,13 +1,27 @@
 package syncing_since
 
-import "akeneo-migrator/kit/bus"
+import (
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/conflict"
+	"akeneo-migrator/kit/limit"
+)
 
 const SyncProductsSinceCommandType bus.Type = "product.sync_updated"
 
-// SyncProductsSinceCommand represents a command to sync products updated since a date
+// SyncProductsSinceCommand represents a command to sync products updated since a date,
+// optionally up to an end date
 type SyncProductsSinceCommand struct {
 	UpdatedSince string
-	Debug        bool
+	UpdatedUntil string
+	// SinceLastRun starts at the end of the last successful run and ends now; UpdatedSince is then
+	// only used for the first run
+	SinceLastRun bool
+	ValuesOnly   bool
+	// OnConflict overrides the strategy applied to items edited in destination since their last sync
+	OnConflict conflict.Strategy
+	// Range selects the updated items whose hierarchies are synced, e.g. the first 50 for a trial run
+	Range limit.Range
+	Debug bool
 }
 
 // Type returns the command type
@@ -3,6 +3,7 @@ package syncing_since
 import (
 	"context"
 
+	"akeneo-migrator/internal/product/syncing"
 	"akeneo-migrator/kit/bus"
 )
 
@@ -25,7 +26,15 @@ func (h *CommandHandler) Handle(ctx context.Context, msg bus.Message) (bus.Respo
 		return bus.Response{}, nil
 	}
 
-	result, err := h.service.Sync(ctx, cmd.UpdatedSince)
+	opts := syncing.SyncOptions{ValuesOnly: cmd.ValuesOnly, Conflicts: cmd.OnConflict, Range: cmd.Range}
+
+	var result *SyncResult
+	var err error
+	if cmd.SinceLastRun {
+		result, err = h.service.SyncSinceLastRun(ctx, cmd.UpdatedSince, opts)
+	} else {
+		result, err = h.service.Sync(ctx, cmd.UpdatedSince, cmd.UpdatedUntil, opts)
+	}
 	if err != nil {
 		return bus.Response{Error: err}, err
 	}
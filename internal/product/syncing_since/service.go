@@ -2,137 +2,225 @@ package syncing_since
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"akeneo-migrator/internal/product"
 	"akeneo-migrator/internal/product/syncing"
+	"akeneo-migrator/kit/conflict"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/limit"
+	"akeneo-migrator/kit/logger"
+	"akeneo-migrator/kit/progress"
+	"akeneo-migrator/kit/retry"
+	"akeneo-migrator/kit/workers"
 )
 
+// RunScope is the scope under which the end of the last successful sync of updated products is stored
+const RunScope = "updated-products"
+
 // Service handles the synchronization of updated products
 type Service struct {
 	sourceRepo     product.SourceRepository
 	destRepo       product.DestRepository
+	runs           product.RunRepository
 	syncingService *syncing.Service
+	now            func() time.Time
 }
 
 // NewService creates a new instance of the sync since service
-func NewService(sourceRepo product.SourceRepository, destRepo product.DestRepository) *Service {
+// The runs repository keeps where the last successful run finished, for SyncSinceLastRun.
+// Options are passed to the composed hierarchy sync service
+func NewService(sourceRepo product.SourceRepository, destRepo product.DestRepository, runs product.RunRepository, opts ...syncing.Option) *Service {
 	return &Service{
 		sourceRepo:     sourceRepo,
 		destRepo:       destRepo,
-		syncingService: syncing.NewService(sourceRepo, destRepo),
+		runs:           runs,
+		syncingService: syncing.NewService(sourceRepo, destRepo, opts...),
+		now:            time.Now,
 	}
 }
 
 // SyncResult contains the result of syncing updated products
 type SyncResult struct {
 	UpdatedSince   string
+	UpdatedUntil   string
 	ProductsSynced int
 	ModelsSynced   int
 	TotalSynced    int
 	Errors         []string
 	Success        bool
+	// FailedItems are the products and models that could not be written
+	FailedItems []retry.Failure
+	// Conflicts are the products and models edited in destination since their last sync
+	Conflicts []conflict.Conflict
+	// MissingTargets are the association links dropped because their target is missing in destination
+	MissingTargets []syncing.MissingTarget
+	// DroppedAttributes are the values left out because their attribute is missing in destination
+	DroppedAttributes []syncing.DroppedAttributes
+	// Unchanged is the number of products and models matching destination, not written with changed-only updates
+	Unchanged int
+	// Recorded is set when the end of the window was saved as the start of the next run since the last one
+	Recorded bool
+	// Planned are the writes recorded instead of being sent during a dry run
+	Planned []dryrun.Write
+}
+
+// Failures returns the products and models that could not be written
+func (r *SyncResult) Failures() []retry.Failure {
+	return r.FailedItems
+}
+
+// Synced returns the number of products and models written
+func (r *SyncResult) Synced() int {
+	return r.TotalSynced
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
 }
 
-// Sync synchronizes all products and models updated since a specific date
+// Sync synchronizes all products and models updated since a specific date.
+// A non-empty updatedUntil restricts the sync to items updated after updatedSince and up to
+// updatedUntil included, so the window of a previous run can be replayed precisely.
 // Memory-efficient: Processes products/models in batches using streaming
-// Logic: For each updated product/model, finds its root and syncs the entire hierarchy
-func (s *Service) Sync(ctx context.Context, updatedSince string) (*SyncResult, error) {
+// Logic: For each updated product/model, finds its root and syncs the entire hierarchy.
+// Hierarchies are synced on a worker pool; a conflict aborting the sync stops the streams.
+// The range of the options selects the updated items, models first, whose hierarchies are synced.
+func (s *Service) Sync(ctx context.Context, updatedSince, updatedUntil string, opts syncing.SyncOptions) (*SyncResult, error) {
+	if err := ValidateWindow(updatedSince, updatedUntil); err != nil {
+		return nil, err
+	}
+	if err := opts.Range.Validate(); err != nil {
+		return nil, err
+	}
+
 	result := &SyncResult{
 		UpdatedSince: updatedSince,
+		UpdatedUntil: updatedUntil,
 		Success:      true,
 	}
+	ctx, planned := dryrun.Collect(ctx)
+	log := s.syncingService.Logger()
 
-	fmt.Printf("📅 Syncing products updated since: %s (streaming mode)\n", updatedSince)
+	if updatedUntil != "" {
+		log.Info("📅 Syncing products updated in a window (streaming mode)", logger.F("since", updatedSince), logger.F("until", updatedUntil))
+	} else {
+		log.Info("📅 Syncing products updated since a date (streaming mode)", logger.F("since", updatedSince))
+	}
+
+	// The count only sizes the progress, the sync goes on without it
+	if total, err := s.sourceRepo.CountUpdatedSince(ctx, updatedSince, updatedUntil); err == nil {
+		progress.Expect(ctx, opts.Range.Count(total))
+	}
 
 	// Track synced hierarchies to avoid duplicates
 	syncedHierarchies := make(map[string]bool)
+	roots := newRootResolver(s.sourceRepo)
+	hierarchies := &hierarchySyncs{
+		service: s.syncingService,
+		pool:    workers.New(s.syncingService.Workers(opts)),
+		opts:    opts,
+		result:  result,
+	}
 
 	batchSize := 100 // Process 100 items at a time
+	cursor := opts.Range.Start()
 	modelsProcessed := 0
 	productsProcessed := 0
 
 	// 1. Stream and process product models in batches
-	fmt.Println("   📦 Processing product models...")
-	err := s.sourceRepo.StreamModelsUpdatedSince(ctx, updatedSince, batchSize, func(models []product.ProductModel) error {
+	log.Info("   📦 Processing product models...")
+	err := s.sourceRepo.StreamModelsUpdatedSince(ctx, updatedSince, updatedUntil, batchSize, func(models []product.ProductModel) error {
+		models, reached := limit.Take(cursor, models)
 		for _, model := range models {
 			code, ok := model["code"].(string)
 			if !ok {
-				result.Errors = append(result.Errors, "could not extract model code")
+				hierarchies.pool.Merge(func() { result.Errors = append(result.Errors, "could not extract model code") })
 				continue
 			}
 
 			// Find the root of this model's hierarchy
-			root := s.findModelRoot(ctx, model)
+			root := roots.modelRoot(ctx, model)
 
 			// Skip if already synced
 			if syncedHierarchies[root] {
 				continue
 			}
 
-			fmt.Printf("   🔄 Syncing hierarchy from root: %s (triggered by model: %s)\n", root, code)
-
-			hierarchyResult, syncErr := s.syncingService.Sync(ctx, root)
-			if syncErr != nil {
-				result.Errors = append(result.Errors, fmt.Sprintf("error syncing root %s: %v", root, syncErr))
-				continue
-			}
+			log.Info("   🔄 Syncing hierarchy", logger.F("root", root), logger.F("model", code))
 
 			syncedHierarchies[root] = true
-			result.ModelsSynced += hierarchyResult.ModelsSynced
-			result.ProductsSynced += hierarchyResult.ProductsSynced
-			modelsProcessed++
+			hierarchies.sync(ctx, root, syncing.KindProductModel, &modelsProcessed)
+			if err := hierarchies.aborted(); err != nil {
+				return err
+			}
 		}
-		return nil
+		// Items are processed once their hierarchy is dispatched or already synced
+		progress.Done(ctx, len(models))
+		return reached
 	})
+	hierarchies.pool.Wait()
+	if err == nil || errors.Is(err, limit.ErrReached) {
+		err = hierarchies.aborted()
+	}
 
 	if err != nil {
 		return nil, fmt.Errorf("error streaming updated models: %w", err)
 	}
 
-	fmt.Printf("   ✅ Processed %d models (found their roots)\n", modelsProcessed)
+	log.Info("   ✅ Processed models (found their roots)", logger.F("count", modelsProcessed))
 
 	// 2. Stream and process products in batches
-	fmt.Println("   📦 Processing products...")
-	err = s.sourceRepo.StreamProductsUpdatedSince(ctx, updatedSince, batchSize, func(products []product.Product) error {
+	log.Info("   📦 Processing products...")
+	// Once the models fill the range, the first page of products is fetched and the stream stops
+	err = s.sourceRepo.StreamProductsUpdatedSince(ctx, updatedSince, updatedUntil, batchSize, func(products []product.Product) error {
+		products, reached := limit.Take(cursor, products)
 		for _, prod := range products {
 			identifier, ok := prod["identifier"].(string)
 			if !ok {
-				result.Errors = append(result.Errors, "could not extract product identifier")
+				hierarchies.pool.Merge(func() { result.Errors = append(result.Errors, "could not extract product identifier") })
 				continue
 			}
 
 			// Find the root of this product's hierarchy
-			root := s.findProductRoot(ctx, prod)
+			root := roots.productRoot(ctx, prod)
 
 			// Skip if already synced
 			if syncedHierarchies[root] {
 				continue
 			}
 
-			fmt.Printf("   🔄 Syncing hierarchy from root: %s (triggered by product: %s)\n", root, identifier)
+			log.Info("   🔄 Syncing hierarchy", logger.F("root", root), logger.F("product", identifier))
 
-			hierarchyResult, syncErr := s.syncingService.Sync(ctx, root)
-			if syncErr != nil {
-				result.Errors = append(result.Errors, fmt.Sprintf("error syncing root %s: %v", root, syncErr))
-				continue
+			kind := syncing.KindProduct
+			if root != identifier {
+				kind = syncing.KindProductModel
 			}
-
 			syncedHierarchies[root] = true
-			result.ModelsSynced += hierarchyResult.ModelsSynced
-			result.ProductsSynced += hierarchyResult.ProductsSynced
-			productsProcessed++
+			hierarchies.sync(ctx, root, kind, &productsProcessed)
+			if err := hierarchies.aborted(); err != nil {
+				return err
+			}
 		}
-		return nil
+		progress.Done(ctx, len(products))
+		return reached
 	})
+	hierarchies.pool.Wait()
+	if err == nil || errors.Is(err, limit.ErrReached) {
+		err = hierarchies.aborted()
+	}
 
 	if err != nil {
 		return nil, fmt.Errorf("error streaming updated products: %w", err)
 	}
 
-	fmt.Printf("   ✅ Processed %d products (found their roots)\n", productsProcessed)
+	log.Info("   ✅ Processed products (found their roots)", logger.F("count", productsProcessed))
 
 	result.TotalSynced = result.ModelsSynced + result.ProductsSynced
+	result.Planned = planned()
 
 	if len(result.Errors) > 0 {
 		result.Success = false
@@ -141,51 +229,229 @@ func (s *Service) Sync(ctx context.Context, updatedSince string) (*SyncResult, e
 	return result, nil
 }
 
-// findModelRoot navigates up the hierarchy to find the root model
-func (s *Service) findModelRoot(ctx context.Context, model product.ProductModel) string {
-	code, _ := model["code"].(string)
-	parent, hasParent := model["parent"].(string)
+// SyncSinceLastRun synchronizes the items updated since the end of the last successful run, up to now.
+// The first run, without a recorded one, starts at firstSince. Windows of consecutive runs share their
+// bound, so no update is missed or synced twice. The end of the window is only recorded when every
+// item was written and the whole window was synced, outside of dry runs; otherwise the next run
+// starts from the same date again.
+func (s *Service) SyncSinceLastRun(ctx context.Context, firstSince string, opts syncing.SyncOptions) (*SyncResult, error) {
+	since := firstSince
+	last, err := s.runs.FindLastRun(ctx, RunScope)
+	switch {
+	case err == nil:
+		since = last.UTC().Format(time.RFC3339)
+	case !errors.Is(err, product.ErrNoLastRun):
+		return nil, fmt.Errorf("error reading the last run: %w", err)
+	case since == "":
+		return nil, errors.New("no successful run recorded yet: pass the start date of the first run")
+	}
 
-	// If no parent, this is the root
-	if !hasParent || parent == "" || parent == "null" {
-		return code
+	// Akeneo dates have no fraction of second, and the window includes its end
+	until := s.now().UTC().Truncate(time.Second)
+	if start, err := parseDate(since); err == nil && !until.After(start) {
+		return &SyncResult{UpdatedSince: since, UpdatedUntil: since, Success: true}, nil
 	}
 
-	// Navigate up to find the root
-	parentModel, err := s.sourceRepo.FindModelByCode(ctx, parent)
+	result, err := s.Sync(ctx, since, until.Format(time.RFC3339), opts)
 	if err != nil {
-		// If we can't find the parent, treat current as root
+		return nil, err
+	}
+
+	if !result.Success || len(result.FailedItems) > 0 || !opts.Range.IsZero() || dryrun.Enabled(ctx) {
+		return result, nil
+	}
+	if err := s.runs.SaveLastRun(ctx, RunScope, until); err != nil {
+		return result, fmt.Errorf("error recording the run: %w", err)
+	}
+	result.Recorded = true
+
+	return result, nil
+}
+
+// hierarchySyncs syncs hierarchies on a worker pool and merges their outcome into the result
+type hierarchySyncs struct {
+	service *syncing.Service
+	pool    *workers.Pool
+	opts    syncing.SyncOptions
+	result  *SyncResult
+	// err is the conflict that aborted the sync
+	err error
+}
+
+// sync syncs the hierarchy of a root, counting it in processed once synced. A root that cannot be
+// synced is reported as a failure of the given kind.
+func (h *hierarchySyncs) sync(ctx context.Context, root, kind string, processed *int) {
+	h.pool.Go(func() {
+		hierarchyResult, syncErr := h.service.Sync(ctx, root, h.opts)
+		h.pool.Merge(func() {
+			result := h.result
+			if errors.Is(syncErr, conflict.ErrConflict) {
+				if h.err == nil {
+					h.err = syncErr
+				}
+				return
+			}
+			if syncErr != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("error syncing root %s: %v", root, syncErr))
+				result.FailedItems = append(result.FailedItems, retry.Failure{Kind: kind, Code: root, Error: syncErr.Error()})
+				return
+			}
+
+			result.ModelsSynced += hierarchyResult.ModelsSynced
+			result.ProductsSynced += hierarchyResult.ProductsSynced
+			result.FailedItems = append(result.FailedItems, hierarchyResult.Failures()...)
+			result.Conflicts = append(result.Conflicts, hierarchyResult.Conflicts...)
+			result.MissingTargets = append(result.MissingTargets, hierarchyResult.MissingTargets...)
+			result.DroppedAttributes = append(result.DroppedAttributes, hierarchyResult.DroppedAttributes...)
+			result.Unchanged += hierarchyResult.Unchanged
+			*processed++
+		})
+	})
+}
+
+// aborted returns the conflict that aborted the sync, nil while it goes on
+func (h *hierarchySyncs) aborted() error {
+	var err error
+	h.pool.Merge(func() { err = h.err })
+	return err
+}
+
+// rootResolver finds the roots of the hierarchies of updated items. The root of every model and
+// product met on the way is remembered, so each parent chain is fetched from source once per run
+// instead of once per updated item.
+type rootResolver struct {
+	sourceRepo product.SourceRepository
+	// models and products map codes to the root of their hierarchy, "" when not found in source
+	models   map[string]string
+	products map[string]string
+}
+
+// newRootResolver creates a resolver with empty caches, meant to be used for a single run
+func newRootResolver(sourceRepo product.SourceRepository) *rootResolver {
+	return &rootResolver{
+		sourceRepo: sourceRepo,
+		models:     make(map[string]string),
+		products:   make(map[string]string),
+	}
+}
+
+// modelRoot navigates up the hierarchy of a model to find its root
+func (r *rootResolver) modelRoot(ctx context.Context, model product.ProductModel) string {
+	code, _ := model["code"].(string)
+	parent := parentOf(model)
+	if parent == "" {
 		return code
 	}
 
-	// Recursively find the root
-	return s.findModelRoot(ctx, parentModel)
+	// The model stands for its root while its parents are resolved, which stops malformed cycles
+	r.models[code] = code
+	if root := r.rootOfModel(ctx, parent); root != "" {
+		r.models[code] = root
+		return root
+	}
+
+	// If we can't find the parent, treat current as root
+	return code
 }
 
-// findProductRoot navigates up the hierarchy to find the root (model or product)
-func (s *Service) findProductRoot(ctx context.Context, prod product.Product) string {
+// productRoot navigates up the hierarchy of a product to find its root (model or product)
+func (r *rootResolver) productRoot(ctx context.Context, prod product.Product) string {
 	identifier, _ := prod["identifier"].(string)
-	parent, hasParent := prod["parent"].(string)
-
-	// If no parent, this is the root
-	if !hasParent || parent == "" || parent == "null" {
+	parent := parentOf(prod)
+	if parent == "" {
 		return identifier
 	}
 
-	// Try to find parent as a model first
-	parentModel, err := s.sourceRepo.FindModelByCode(ctx, parent)
-	if err == nil {
-		// Found as model, navigate up from there
-		return s.findModelRoot(ctx, parentModel)
+	// The parent is a model first, a product otherwise
+	if root := r.rootOfModel(ctx, parent); root != "" {
+		return root
+	}
+
+	r.products[identifier] = identifier
+	if root := r.rootOfProduct(ctx, parent); root != "" {
+		r.products[identifier] = root
+		return root
 	}
 
-	// Try as product
-	parentProduct, err := s.sourceRepo.FindByIdentifier(ctx, parent)
+	// If we can't find the parent, treat current as root
+	return identifier
+}
+
+// rootOfModel returns the root of the model with the given code, "" when it is not found in source
+func (r *rootResolver) rootOfModel(ctx context.Context, code string) string {
+	if root, known := r.models[code]; known {
+		return root
+	}
+
+	model, err := r.sourceRepo.FindModelByCode(ctx, code)
 	if err != nil {
-		// If we can't find the parent, treat current as root
-		return identifier
+		r.models[code] = ""
+		return ""
+	}
+
+	root := r.modelRoot(ctx, model)
+	r.models[code] = root
+	return root
+}
+
+// rootOfProduct returns the root of the product with the given identifier, "" when it is not found in source
+func (r *rootResolver) rootOfProduct(ctx context.Context, identifier string) string {
+	if root, known := r.products[identifier]; known {
+		return root
 	}
 
-	// Recursively find the root
-	return s.findProductRoot(ctx, parentProduct)
+	prod, err := r.sourceRepo.FindByIdentifier(ctx, identifier)
+	if err != nil {
+		r.products[identifier] = ""
+		return ""
+	}
+
+	root := r.productRoot(ctx, prod)
+	r.products[identifier] = root
+	return root
+}
+
+// parentOf returns the parent code of a product or model, "" for the root of a hierarchy
+func parentOf(item map[string]interface{}) string {
+	parent, _ := item["parent"].(string)
+	if parent == "null" {
+		return ""
+	}
+	return parent
+}
+
+// dateLayouts are the ISO 8601 layouts accepted for the bounds of a window; dates without timezone
+// are UTC, and seconds may be omitted as in the dates sent by the web UI
+var dateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02T15:04"}
+
+// ValidateWindow checks the bounds of a time window before anything is fetched: both must be
+// ISO 8601 dates, and updatedUntil, when set, must come after updatedSince
+func ValidateWindow(updatedSince, updatedUntil string) error {
+	since, err := parseDate(updatedSince)
+	if err != nil {
+		return err
+	}
+	if updatedUntil == "" {
+		return nil
+	}
+
+	until, err := parseDate(updatedUntil)
+	if err != nil {
+		return err
+	}
+	if !until.After(since) {
+		return fmt.Errorf("the end of the window (%s) must come after its start (%s)", updatedUntil, updatedSince)
+	}
+
+	return nil
+}
+
+// parseDate parses an ISO 8601 date
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date format: %s (expected ISO 8601 format like 2024-01-01T00:00:00)", value)
 }
@@ -0,0 +1,215 @@
+package syncing_since
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"akeneo-migrator/internal/product"
+	"akeneo-migrator/internal/product/syncing"
+	"akeneo-migrator/kit/limit"
+)
+
+// MockSourceRepository is a mock of the source repository counting the lookups of hierarchy items
+type MockSourceRepository struct {
+	products map[string]product.Product
+	models   map[string]product.ProductModel
+	lookups  map[string]int
+	// windows are the bounds of the streams of updated models, "since/until"
+	windows []string
+}
+
+func (m *MockSourceRepository) FindByIdentifier(ctx context.Context, identifier string) (product.Product, error) {
+	m.lookups[identifier]++
+	if prod, ok := m.products[identifier]; ok {
+		return prod, nil
+	}
+	return nil, errors.New("product not found")
+}
+
+func (m *MockSourceRepository) FindModelByCode(ctx context.Context, code string) (product.ProductModel, error) {
+	m.lookups[code]++
+	if model, ok := m.models[code]; ok {
+		return model, nil
+	}
+	return nil, errors.New("model not found")
+}
+
+func (m *MockSourceRepository) FindProductsByParent(ctx context.Context, parentCode string) ([]product.Product, error) {
+	return nil, nil
+}
+
+func (m *MockSourceRepository) FindModelsByParent(ctx context.Context, parentCode string) ([]product.ProductModel, error) {
+	return nil, nil
+}
+
+func (m *MockSourceRepository) FindProductsUpdatedSince(ctx context.Context, updatedSince string) ([]product.Product, error) {
+	return nil, nil
+}
+
+func (m *MockSourceRepository) FindModelsUpdatedSince(ctx context.Context, updatedSince string) ([]product.ProductModel, error) {
+	return nil, nil
+}
+
+func (m *MockSourceRepository) StreamProductsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]product.Product) error) error {
+	return nil
+}
+
+func (m *MockSourceRepository) StreamModelsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]product.ProductModel) error) error {
+	m.windows = append(m.windows, updatedSince+"/"+updatedUntil)
+	return nil
+}
+
+func (m *MockSourceRepository) StreamProductsBySearch(ctx context.Context, search string, batchSize int, callback func([]product.Product) error) error {
+	return nil
+}
+
+func (m *MockSourceRepository) CountUpdatedSince(ctx context.Context, updatedSince, updatedUntil string) (int, error) {
+	return 0, nil
+}
+
+func (m *MockSourceRepository) CountProductsBySearch(ctx context.Context, search string) (int, error) {
+	return 0, nil
+}
+
+func (m *MockSourceRepository) DownloadMediaFile(ctx context.Context, code string) (product.MediaFile, error) {
+	return product.MediaFile{}, errors.New("unexpected media download")
+}
+
+func TestValidateWindow(t *testing.T) {
+	valid := []struct {
+		since string
+		until string
+	}{
+		{"2024-01-01T00:00:00", ""},
+		{"2024-01-01T00:00", ""},
+		{"2024-03-01T02:00:00+01:00", "2024-03-01 04:30:00"},
+	}
+	for _, window := range valid {
+		if err := ValidateWindow(window.since, window.until); err != nil {
+			t.Errorf("Expected %s → %s to be valid, got %v", window.since, window.until, err)
+		}
+	}
+
+	invalid := []struct {
+		since string
+		until string
+	}{
+		{"yesterday", ""},
+		{"2024-01-01", ""},
+		{"2024-01-01T00:00:00", "tomorrow"},
+		{"2024-03-01T00:00:00", "2024-01-01T00:00:00"},
+		{"2024-03-01T00:00:00", "2024-03-01T00:00:00"},
+	}
+	for _, window := range invalid {
+		if err := ValidateWindow(window.since, window.until); err == nil {
+			t.Errorf("Expected %s → %s to be rejected", window.since, window.until)
+		}
+	}
+}
+
+func TestRootResolver_ResolvesEachParentOnce(t *testing.T) {
+	source := &MockSourceRepository{
+		models: map[string]product.ProductModel{
+			"ROOT":     {"code": "ROOT", "parent": nil},
+			"ROOT-RED": {"code": "ROOT-RED", "parent": "ROOT"},
+		},
+		products: map[string]product.Product{
+			"KIT": {"identifier": "KIT", "parent": nil},
+		},
+		lookups: make(map[string]int),
+	}
+	roots := newRootResolver(source)
+	ctx := context.Background()
+
+	for _, size := range []string{"S", "M", "L"} {
+		variant := product.Product{"identifier": "ROOT-RED-" + size, "parent": "ROOT-RED"}
+		if root := roots.productRoot(ctx, variant); root != "ROOT" {
+			t.Errorf("Expected ROOT as the root of %s, got %s", variant["identifier"], root)
+		}
+	}
+	if root := roots.modelRoot(ctx, source.models["ROOT-RED"]); root != "ROOT" {
+		t.Errorf("Expected ROOT as the root of ROOT-RED, got %s", root)
+	}
+
+	// Products under a product parent, and parents missing in source
+	if root := roots.productRoot(ctx, product.Product{"identifier": "KIT-PART", "parent": "KIT"}); root != "KIT" {
+		t.Errorf("Expected KIT as the root of KIT-PART, got %s", root)
+	}
+	for i := 0; i < 2; i++ {
+		if root := roots.productRoot(ctx, product.Product{"identifier": "ORPHAN", "parent": "GONE"}); root != "ORPHAN" {
+			t.Errorf("Expected an orphan to be its own root, got %s", root)
+		}
+	}
+
+	expected := map[string]int{"ROOT-RED": 1, "ROOT": 1, "KIT": 2, "GONE": 2}
+	for code, lookups := range expected {
+		if source.lookups[code] != lookups {
+			t.Errorf("Expected %d source lookups of %s, got %d", lookups, code, source.lookups[code])
+		}
+	}
+}
+
+// MockRunRepository keeps the last runs in memory
+type MockRunRepository struct {
+	runs map[string]time.Time
+}
+
+func (m *MockRunRepository) FindLastRun(ctx context.Context, scope string) (time.Time, error) {
+	until, exists := m.runs[scope]
+	if !exists {
+		return time.Time{}, product.ErrNoLastRun
+	}
+	return until, nil
+}
+
+func (m *MockRunRepository) SaveLastRun(ctx context.Context, scope string, until time.Time) error {
+	m.runs[scope] = until
+	return nil
+}
+
+func TestSyncSinceLastRun_StartsWhereTheLastRunFinished(t *testing.T) {
+	source := &MockSourceRepository{lookups: map[string]int{}}
+	runs := &MockRunRepository{runs: map[string]time.Time{}}
+	service := NewService(source, nil, runs)
+	now := time.Date(2024, 3, 1, 10, 0, 0, 500, time.UTC)
+	service.now = func() time.Time { return now }
+
+	if _, err := service.SyncSinceLastRun(context.Background(), "", syncing.SyncOptions{}); err == nil {
+		t.Fatal("Expected an error for a first run without a start date")
+	}
+
+	// The first run starts at the given date, the next ones where the previous one finished
+	result, err := service.SyncSinceLastRun(context.Background(), "2024-01-01T00:00:00", syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !result.Recorded || !runs.runs[RunScope].Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the end of the window to be recorded, got %v", runs.runs)
+	}
+
+	now = now.Add(time.Hour)
+	if _, err := service.SyncSinceLastRun(context.Background(), "2024-01-01T00:00:00", syncing.SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{
+		"2024-01-01T00:00:00/2024-03-01T10:00:00Z",
+		"2024-03-01T10:00:00Z/2024-03-01T11:00:00Z",
+	}
+	if !reflect.DeepEqual(source.windows, expected) {
+		t.Errorf("Expected windows %v, got %v", expected, source.windows)
+	}
+
+	// A run over part of the window does not move the start of the next one
+	now = now.Add(time.Hour)
+	result, err = service.SyncSinceLastRun(context.Background(), "", syncing.SyncOptions{Range: limit.Range{Limit: 10}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Recorded || !runs.runs[RunScope].Equal(time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the limited run not to be recorded, got %v", runs.runs)
+	}
+}
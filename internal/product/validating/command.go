@@ -0,0 +1,18 @@
+package validating
+
+import "akeneo-migrator/kit/bus"
+
+const ValidateProductsCommandType bus.Type = "product.validate"
+
+// ValidateProductsCommand represents a command to check the destination prerequisites of product hierarchies
+type ValidateProductsCommand struct {
+	// Identifiers are the commons of the hierarchies, as given to sync-product
+	Identifiers []string
+	// Path is a list of identifiers in any format sync-products-from-file reads, used instead
+	Path string
+}
+
+// Type returns the command type
+func (c ValidateProductsCommand) Type() bus.Type {
+	return ValidateProductsCommandType
+}
@@ -0,0 +1,40 @@
+package validating
+
+import (
+	"context"
+
+	"akeneo-migrator/kit/bus"
+)
+
+// CommandHandler handles ValidateProductsCommand
+type CommandHandler struct {
+	service *Service
+}
+
+// NewCommandHandler creates a new command handler
+func NewCommandHandler(service *Service) *CommandHandler {
+	return &CommandHandler{
+		service: service,
+	}
+}
+
+// Handle executes the validate command
+func (h *CommandHandler) Handle(ctx context.Context, msg bus.Message) (bus.Response, error) {
+	cmd, ok := msg.(ValidateProductsCommand)
+	if !ok {
+		return bus.Response{}, nil
+	}
+
+	var result *ValidationResult
+	var err error
+	if cmd.Path != "" {
+		result, err = h.service.ValidateFile(ctx, cmd.Path)
+	} else {
+		result, err = h.service.Validate(ctx, cmd.Identifiers)
+	}
+	if err != nil {
+		return bus.Response{Error: err}, err
+	}
+
+	return bus.Response{Data: result}, nil
+}
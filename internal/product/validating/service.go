@@ -0,0 +1,544 @@
+package validating
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"akeneo-migrator/internal/product"
+	"akeneo-migrator/kit/filter"
+)
+
+// Kinds of prerequisites products rely on in destination
+const (
+	KindAttribute       = "attribute"
+	KindAttributeOption = "attribute_option"
+	KindCategory        = "category"
+	KindFamily          = "family"
+	KindFamilyVariant   = "family_variant"
+)
+
+// kindOrder sorts the missing prerequisites in the order they have to be synced
+var kindOrder = map[string]int{
+	KindAttribute:       0,
+	KindAttributeOption: 1,
+	KindCategory:        2,
+	KindFamily:          3,
+	KindFamilyVariant:   4,
+}
+
+// Attribute types whose values are option codes
+const (
+	typeSimpleSelect = "pim_catalog_simpleselect"
+	typeMultiSelect  = "pim_catalog_multiselect"
+)
+
+// maxUsedBy is the number of products and models listed for each missing prerequisite
+const maxUsedBy = 5
+
+// Missing is a prerequisite of the products that does not exist in destination
+type Missing struct {
+	Kind string
+	// Scope is the attribute of an option or the family of a variant, empty otherwise
+	Scope string
+	Code  string
+	// Items is the number of products and models relying on it, UsedBy the first of them
+	Items  int
+	UsedBy []string
+}
+
+// String names the prerequisite for messages, e.g. "attribute_option color.red"
+func (m Missing) String() string {
+	if m.Scope == "" {
+		return m.Kind + " " + m.Code
+	}
+	return m.Kind + " " + m.Scope + "." + m.Code
+}
+
+// Incompatibility is an attribute defined differently in source and destination, so destination rejects
+// the values read from source even though the attribute exists there
+type Incompatibility struct {
+	Attribute string
+	Source    product.AttributeDefinition
+	Dest      product.AttributeDefinition
+	// Items is the number of products and models with values of the attribute, UsedBy the first of them
+	Items  int
+	UsedBy []string
+}
+
+// Differences describes what differs between the two definitions, source first,
+// e.g. "type pim_catalog_textarea → pim_catalog_text"
+func (i Incompatibility) Differences() []string {
+	var differences []string
+	if i.Source.Type != i.Dest.Type {
+		differences = append(differences, fmt.Sprintf("type %s → %s", i.Source.Type, i.Dest.Type))
+	}
+	if i.Source.Localizable != i.Dest.Localizable {
+		differences = append(differences, fmt.Sprintf("localizable %t → %t", i.Source.Localizable, i.Dest.Localizable))
+	}
+	if i.Source.Scopable != i.Dest.Scopable {
+		differences = append(differences, fmt.Sprintf("scopable %t → %t", i.Source.Scopable, i.Dest.Scopable))
+	}
+	return differences
+}
+
+// Fix tells how to solve the incompatibility. Akeneo cannot change the type or flags of an existing
+// attribute, so it has to be recreated in destination, or its values left out of the sync.
+func (i Incompatibility) Fix() string {
+	return fmt.Sprintf("recreate %s in destination as in source, or leave its values out with --exclude-attributes %s", i.Attribute, i.Attribute)
+}
+
+// ValidationResult lists the prerequisites missing in destination for a set of product hierarchies
+type ValidationResult struct {
+	Identifiers []string
+	// Products and Models are the number of items checked
+	Products int
+	Models   int
+	Missing  []Missing
+	// Incompatible are the attributes defined differently in source and destination, sorted by code
+	Incompatible []Incompatibility
+	// Errors are the hierarchies that could not be read from source
+	Errors []string
+}
+
+// Valid tells whether every hierarchy was read and nothing is missing or incompatible in destination
+func (r *ValidationResult) Valid() bool {
+	return len(r.Missing) == 0 && len(r.Incompatible) == 0 && len(r.Errors) == 0
+}
+
+// SyncFirst returns the commands creating the missing prerequisites, attributes first since
+// families need them. Attributes are synced with their options and families with their variants.
+// Categories are synced alone, so their parents have to exist in destination.
+func (r *ValidationResult) SyncFirst() []string {
+	var attributes, categories, families []string
+	seen := make(map[string]bool)
+	add := func(list *[]string, command string) {
+		if !seen[command] {
+			seen[command] = true
+			*list = append(*list, command)
+		}
+	}
+
+	for _, missing := range r.Missing {
+		switch missing.Kind {
+		case KindAttribute:
+			add(&attributes, "sync-attribute "+missing.Code)
+		case KindAttributeOption:
+			add(&attributes, "sync-attribute "+missing.Scope)
+		case KindCategory:
+			add(&categories, "sync-category "+missing.Code)
+		case KindFamily:
+			add(&families, "sync-family "+missing.Code)
+		case KindFamilyVariant:
+			if !seen["sync-family "+missing.Scope] {
+				add(&families, "sync-family "+missing.Scope+" --variants-only")
+			}
+		}
+	}
+
+	return append(append(attributes, categories...), families...)
+}
+
+// Service checks that the catalog structure product hierarchies rely on exists in destination,
+// so a sync can be stopped before any write instead of failing item by item
+type Service struct {
+	listRepo        product.IdentifierListRepository
+	sourceRepo      product.SourceRepository
+	destStructure   product.StructureRepository
+	sourceStructure product.StructureRepository
+	valueFilter     *filter.Filter
+}
+
+// Option configures the validation service
+type Option func(*Service)
+
+// WithAttributeComparison compares the definition of the attributes with values in source and
+// destination, reporting the ones whose type, localizable or scopable flag differ
+func WithAttributeComparison(sourceStructure product.StructureRepository) Option {
+	return func(s *Service) {
+		s.sourceStructure = sourceStructure
+	}
+}
+
+// WithValueFilter only checks the values the product syncs send, leaving out the ones excluded by the filter
+func WithValueFilter(valueFilter *filter.Filter) Option {
+	return func(s *Service) {
+		s.valueFilter = valueFilter
+	}
+}
+
+// NewService creates a new instance of the validation service
+func NewService(
+	listRepo product.IdentifierListRepository,
+	sourceRepo product.SourceRepository,
+	destStructure product.StructureRepository,
+	opts ...Option,
+) *Service {
+	service := &Service{
+		listRepo:      listRepo,
+		sourceRepo:    sourceRepo,
+		destStructure: destStructure,
+	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
+
+// ValidateFile validates the hierarchies listed in a file, as read by sync-products-from-file
+func (s *Service) ValidateFile(ctx context.Context, path string) (*ValidationResult, error) {
+	identifiers, err := s.listRepo.Read(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading identifier list: %w", err)
+	}
+	return s.Validate(ctx, identifiers)
+}
+
+// Validate reads the hierarchies of the given identifiers from source, as sync-product would, and
+// checks that their families, family variants, categories, attributes and the options of their
+// select values exist in destination. Nothing is written.
+func (s *Service) Validate(ctx context.Context, identifiers []string) (*ValidationResult, error) {
+	result := &ValidationResult{Identifiers: identifiers}
+	check := &structureCheck{
+		structure:       s.destStructure,
+		sourceStructure: s.sourceStructure,
+		valueFilter:     s.valueFilter,
+		missing:         make(map[prerequisite]*Missing),
+		incompatible:    make(map[string]*Incompatibility),
+	}
+
+	for _, identifier := range identifiers {
+		products, models, err := s.hierarchy(ctx, identifier)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", identifier, err))
+			continue
+		}
+
+		for _, model := range models {
+			code, _ := model["code"].(string)
+			if err := check.item(ctx, "product model "+code, model, true); err != nil {
+				return nil, err
+			}
+		}
+		for _, prod := range products {
+			identifier, _ := prod["identifier"].(string)
+			if err := check.item(ctx, "product "+identifier, prod, false); err != nil {
+				return nil, err
+			}
+		}
+		result.Models += len(models)
+		result.Products += len(products)
+	}
+
+	result.Missing = check.sorted()
+	result.Incompatible = check.sortedIncompatible()
+	return result, nil
+}
+
+// hierarchy reads the products and models of the hierarchy of a common from source
+func (s *Service) hierarchy(ctx context.Context, root string) ([]product.Product, []product.ProductModel, error) {
+	if common, err := s.sourceRepo.FindByIdentifier(ctx, root); err == nil {
+		children, err := s.sourceRepo.FindProductsByParent(ctx, root)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error fetching child products: %w", err)
+		}
+		return append([]product.Product{common}, children...), nil, nil
+	}
+
+	common, err := s.sourceRepo.FindModelByCode(ctx, root)
+	if err != nil {
+		return nil, nil, fmt.Errorf("not found as product or model: %w", err)
+	}
+
+	models := []product.ProductModel{common}
+	var products []product.Product
+	for i := 0; i < len(models); i++ {
+		code, _ := models[i]["code"].(string)
+		if code == "" {
+			continue
+		}
+
+		subModels, err := s.sourceRepo.FindModelsByParent(ctx, code)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error fetching child models of %s: %w", code, err)
+		}
+		models = append(models, subModels...)
+
+		variants, err := s.sourceRepo.FindProductsByParent(ctx, code)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error fetching variants of %s: %w", code, err)
+		}
+		products = append(products, variants...)
+	}
+
+	return products, models, nil
+}
+
+// structureCheck looks up the destination structure once per run and collects what is missing.
+// The source attributes are compared with the destination ones when sourceStructure is set.
+type structureCheck struct {
+	structure       product.StructureRepository
+	sourceStructure product.StructureRepository
+	valueFilter     *filter.Filter
+
+	families    map[string]bool
+	variants    map[string]map[string]bool
+	categories  map[string]bool
+	attributes  map[string]product.AttributeDefinition
+	sourceAttrs map[string]product.AttributeDefinition
+	options     map[string]map[string]bool
+
+	missing      map[prerequisite]*Missing
+	incompatible map[string]*Incompatibility
+}
+
+// prerequisite identifies a missing prerequisite
+type prerequisite struct {
+	kind, scope, code string
+}
+
+// item checks the family, family variant, categories and values of a product or model
+func (c *structureCheck) item(ctx context.Context, name string, item map[string]interface{}, isModel bool) error {
+	if familyCode, _ := item["family"].(string); familyCode != "" {
+		exists, err := c.familyExists(ctx, familyCode)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			c.report(KindFamily, "", familyCode, name)
+		} else if variantCode, _ := item["family_variant"].(string); isModel && variantCode != "" {
+			variants, err := c.familyVariants(ctx, familyCode)
+			if err != nil {
+				return err
+			}
+			if !variants[variantCode] {
+				c.report(KindFamilyVariant, familyCode, variantCode, name)
+			}
+		}
+	}
+
+	categories, _ := item["categories"].([]interface{})
+	for _, category := range categories {
+		code, _ := category.(string)
+		if code == "" {
+			continue
+		}
+		exists, err := c.categoryExists(ctx, code)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			c.report(KindCategory, "", code, name)
+		}
+	}
+
+	values, _ := item["values"].(map[string]interface{})
+	for attributeCode, entries := range c.valueFilter.Apply(values) {
+		definition, exists, err := c.attribute(ctx, attributeCode)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			c.report(KindAttribute, "", attributeCode, name)
+			continue
+		}
+		if err := c.compare(ctx, attributeCode, definition, name); err != nil {
+			return err
+		}
+		if definition.Type != typeSimpleSelect && definition.Type != typeMultiSelect {
+			continue
+		}
+
+		options, err := c.optionCodes(ctx, attributeCode)
+		if err != nil {
+			return err
+		}
+		for _, optionCode := range optionCodes(entries) {
+			if !options[strings.ToLower(optionCode)] {
+				c.report(KindAttributeOption, attributeCode, optionCode, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// report records an item relying on a missing prerequisite
+func (c *structureCheck) report(kind, scope, code, name string) {
+	key := prerequisite{kind: kind, scope: scope, code: code}
+	missing, ok := c.missing[key]
+	if !ok {
+		missing = &Missing{Kind: kind, Scope: scope, Code: code}
+		c.missing[key] = missing
+	}
+	missing.Items++
+	if len(missing.UsedBy) < maxUsedBy {
+		missing.UsedBy = append(missing.UsedBy, name)
+	}
+}
+
+// compare records an item with values of an attribute defined differently in source, when the
+// source attributes are compared
+func (c *structureCheck) compare(ctx context.Context, code string, dest product.AttributeDefinition, name string) error {
+	if c.sourceStructure == nil {
+		return nil
+	}
+
+	if c.sourceAttrs == nil {
+		definitions, err := c.sourceStructure.FindAttributeDefinitions(ctx)
+		if err != nil {
+			return fmt.Errorf("error fetching source attributes: %w", err)
+		}
+		c.sourceAttrs = definitions
+	}
+	source, exists := c.sourceAttrs[code]
+	if !exists || source == dest {
+		return nil
+	}
+
+	incompatible, ok := c.incompatible[code]
+	if !ok {
+		incompatible = &Incompatibility{Attribute: code, Source: source, Dest: dest}
+		c.incompatible[code] = incompatible
+	}
+	incompatible.Items++
+	if len(incompatible.UsedBy) < maxUsedBy {
+		incompatible.UsedBy = append(incompatible.UsedBy, name)
+	}
+	return nil
+}
+
+// sortedIncompatible returns the incompatible attributes sorted by code
+func (c *structureCheck) sortedIncompatible() []Incompatibility {
+	result := make([]Incompatibility, 0, len(c.incompatible))
+	for _, incompatible := range c.incompatible {
+		result = append(result, *incompatible)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Attribute < result[j].Attribute
+	})
+	return result
+}
+
+// sorted returns the missing prerequisites in the order they have to be synced
+func (c *structureCheck) sorted() []Missing {
+	result := make([]Missing, 0, len(c.missing))
+	for _, missing := range c.missing {
+		result = append(result, *missing)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return kindOrder[result[i].Kind] < kindOrder[result[j].Kind]
+		}
+		if result[i].Scope != result[j].Scope {
+			return result[i].Scope < result[j].Scope
+		}
+		return result[i].Code < result[j].Code
+	})
+	return result
+}
+
+// familyExists tells whether a family exists in destination, the families being listed once
+func (c *structureCheck) familyExists(ctx context.Context, code string) (bool, error) {
+	if c.families == nil {
+		families, err := c.structure.FindFamilyCodes(ctx)
+		if err != nil {
+			return false, fmt.Errorf("error fetching destination families: %w", err)
+		}
+		c.families = families
+	}
+	return c.families[code], nil
+}
+
+// familyVariants returns the variants of a family existing in destination
+func (c *structureCheck) familyVariants(ctx context.Context, familyCode string) (map[string]bool, error) {
+	if variants, ok := c.variants[familyCode]; ok {
+		return variants, nil
+	}
+
+	variants, err := c.structure.FindFamilyVariantCodes(ctx, familyCode)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching destination variants of family %s: %w", familyCode, err)
+	}
+	if c.variants == nil {
+		c.variants = make(map[string]map[string]bool)
+	}
+	c.variants[familyCode] = variants
+	return variants, nil
+}
+
+// categoryExists tells whether a category exists in destination, each category being read once
+func (c *structureCheck) categoryExists(ctx context.Context, code string) (bool, error) {
+	if exists, ok := c.categories[code]; ok {
+		return exists, nil
+	}
+
+	_, exists, err := c.structure.FindCategoryParent(ctx, code)
+	if err != nil {
+		return false, fmt.Errorf("error fetching destination category %s: %w", code, err)
+	}
+	if c.categories == nil {
+		c.categories = make(map[string]bool)
+	}
+	c.categories[code] = exists
+	return exists, nil
+}
+
+// attribute returns the definition of an attribute in destination and whether it exists there
+func (c *structureCheck) attribute(ctx context.Context, code string) (product.AttributeDefinition, bool, error) {
+	if c.attributes == nil {
+		definitions, err := c.structure.FindAttributeDefinitions(ctx)
+		if err != nil {
+			return product.AttributeDefinition{}, false, fmt.Errorf("error fetching destination attributes: %w", err)
+		}
+		c.attributes = definitions
+	}
+	definition, exists := c.attributes[code]
+	return definition, exists, nil
+}
+
+// optionCodes returns the lowercased codes of the options of an attribute in destination, since
+// Akeneo matches option codes case-insensitively
+func (c *structureCheck) optionCodes(ctx context.Context, attributeCode string) (map[string]bool, error) {
+	if options, ok := c.options[attributeCode]; ok {
+		return options, nil
+	}
+
+	codes, err := c.structure.FindOptionCodes(ctx, attributeCode)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching destination options of attribute %s: %w", attributeCode, err)
+	}
+	options := make(map[string]bool, len(codes))
+	for code := range codes {
+		options[strings.ToLower(code)] = true
+	}
+	if c.options == nil {
+		c.options = make(map[string]map[string]bool)
+	}
+	c.options[attributeCode] = options
+	return options, nil
+}
+
+// optionCodes returns the option codes of the values of a select attribute
+func optionCodes(entries interface{}) []string {
+	list, _ := entries.([]interface{})
+
+	var codes []string
+	for _, entry := range list {
+		value, _ := entry.(map[string]interface{})
+		switch data := value["data"].(type) {
+		case string:
+			codes = append(codes, data)
+		case []interface{}:
+			for _, code := range data {
+				if code, ok := code.(string); ok {
+					codes = append(codes, code)
+				}
+			}
+		}
+	}
+	return codes
+}
@@ -0,0 +1,332 @@
+package validating_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"akeneo-migrator/internal/product"
+	"akeneo-migrator/internal/product/validating"
+	"akeneo-migrator/kit/filter"
+)
+
+// MockSourceRepository is a mock of the source repository serving a fixed catalog
+type MockSourceRepository struct {
+	products         map[string]product.Product
+	models           map[string]product.ProductModel
+	productsByParent map[string][]product.Product
+	modelsByParent   map[string][]product.ProductModel
+}
+
+func (m *MockSourceRepository) FindByIdentifier(ctx context.Context, identifier string) (product.Product, error) {
+	if prod, ok := m.products[identifier]; ok {
+		return prod, nil
+	}
+	return nil, errors.New("product not found")
+}
+
+func (m *MockSourceRepository) FindModelByCode(ctx context.Context, code string) (product.ProductModel, error) {
+	if model, ok := m.models[code]; ok {
+		return model, nil
+	}
+	return nil, errors.New("model not found")
+}
+
+func (m *MockSourceRepository) FindProductsByParent(ctx context.Context, parentCode string) ([]product.Product, error) {
+	return m.productsByParent[parentCode], nil
+}
+
+func (m *MockSourceRepository) FindModelsByParent(ctx context.Context, parentCode string) ([]product.ProductModel, error) {
+	return m.modelsByParent[parentCode], nil
+}
+
+func (m *MockSourceRepository) FindProductsUpdatedSince(ctx context.Context, updatedSince string) ([]product.Product, error) {
+	return nil, nil
+}
+
+func (m *MockSourceRepository) FindModelsUpdatedSince(ctx context.Context, updatedSince string) ([]product.ProductModel, error) {
+	return nil, nil
+}
+
+func (m *MockSourceRepository) StreamProductsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]product.Product) error) error {
+	return nil
+}
+
+func (m *MockSourceRepository) StreamModelsUpdatedSince(ctx context.Context, updatedSince, updatedUntil string, batchSize int, callback func([]product.ProductModel) error) error {
+	return nil
+}
+
+func (m *MockSourceRepository) StreamProductsBySearch(ctx context.Context, search string, batchSize int, callback func([]product.Product) error) error {
+	return nil
+}
+
+func (m *MockSourceRepository) CountUpdatedSince(ctx context.Context, updatedSince, updatedUntil string) (int, error) {
+	return 0, nil
+}
+
+func (m *MockSourceRepository) CountProductsBySearch(ctx context.Context, search string) (int, error) {
+	return 0, nil
+}
+
+func (m *MockSourceRepository) DownloadMediaFile(ctx context.Context, code string) (product.MediaFile, error) {
+	return product.MediaFile{}, errors.New("unexpected media download")
+}
+
+// MockIdentifierListRepository is a mock of the identifier list repository
+type MockIdentifierListRepository struct {
+	lists map[string][]string
+}
+
+func (m *MockIdentifierListRepository) Read(ctx context.Context, path string) ([]string, error) {
+	if list, ok := m.lists[path]; ok {
+		return list, nil
+	}
+	return nil, errors.New("file not found")
+}
+
+// MockStructureRepository is a mock of the destination structure
+type MockStructureRepository struct {
+	families       map[string]bool
+	variants       map[string]map[string]bool
+	attributeTypes map[string]string
+	options        map[string]map[string]bool
+	categories     map[string]string
+	// localizable are the attributes whose values have a locale
+	localizable map[string]bool
+	// familyLists counts the listings of the families, which are expected once per run
+	familyLists int
+}
+
+func (m *MockStructureRepository) FindFamilyCodes(ctx context.Context) (map[string]bool, error) {
+	m.familyLists++
+	return m.families, nil
+}
+
+func (m *MockStructureRepository) FindFamilyVariantCodes(ctx context.Context, familyCode string) (map[string]bool, error) {
+	return m.variants[familyCode], nil
+}
+
+func (m *MockStructureRepository) FindAttributeTypes(ctx context.Context) (map[string]string, error) {
+	return m.attributeTypes, nil
+}
+
+func (m *MockStructureRepository) FindAttributeDefinitions(ctx context.Context) (map[string]product.AttributeDefinition, error) {
+	definitions := make(map[string]product.AttributeDefinition, len(m.attributeTypes))
+	for code, attrType := range m.attributeTypes {
+		definitions[code] = product.AttributeDefinition{Type: attrType, Localizable: m.localizable[code]}
+	}
+	return definitions, nil
+}
+
+func (m *MockStructureRepository) FindOptionCodes(ctx context.Context, attributeCode string) (map[string]bool, error) {
+	return m.options[attributeCode], nil
+}
+
+func (m *MockStructureRepository) FindFamilyAttributes(ctx context.Context, familyCode string) ([]string, error) {
+	return nil, errors.New("unexpected family read")
+}
+
+func (m *MockStructureRepository) FindCategoryParent(ctx context.Context, code string) (string, bool, error) {
+	parent, exists := m.categories[code]
+	return parent, exists, nil
+}
+
+func values(data map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(data))
+	for attribute, value := range data {
+		result[attribute] = []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": value}}
+	}
+	return result
+}
+
+func newCatalog() (*MockSourceRepository, *MockStructureRepository) {
+	source := &MockSourceRepository{
+		products: map[string]product.Product{
+			"SKU-1": {"identifier": "SKU-1", "family": "shoes", "categories": []interface{}{"summer"}, "values": values(map[string]interface{}{"name": "Shoe", "color": "Red"})},
+		},
+		models: map[string]product.ProductModel{
+			"MODEL-1": {"code": "MODEL-1", "family": "boots", "family_variant": "boots_by_size", "values": values(map[string]interface{}{"material": "leather"})},
+		},
+		modelsByParent: map[string][]product.ProductModel{
+			"MODEL-1": {{"code": "MODEL-1-BLACK", "family": "boots", "family_variant": "boots_by_size", "values": values(map[string]interface{}{"color": "black"})}},
+		},
+		productsByParent: map[string][]product.Product{
+			"MODEL-1-BLACK": {
+				{"identifier": "BOOT-42", "family": "boots", "values": values(map[string]interface{}{"sizes": []interface{}{"42", "xl"}})},
+			},
+		},
+	}
+	structure := &MockStructureRepository{
+		families: map[string]bool{"shoes": true, "boots": true},
+		variants: map[string]map[string]bool{"boots": {"boots_by_size": true}},
+		attributeTypes: map[string]string{
+			"name":     "pim_catalog_text",
+			"color":    "pim_catalog_simpleselect",
+			"material": "pim_catalog_text",
+			"sizes":    "pim_catalog_multiselect",
+		},
+		options: map[string]map[string]bool{
+			"color": {"red": true, "black": true},
+			"sizes": {"42": true, "xl": true},
+		},
+		categories: map[string]string{"master": "", "summer": "master"},
+	}
+	return source, structure
+}
+
+func TestValidate_CompleteDestinationIsValid(t *testing.T) {
+	source, structure := newCatalog()
+	service := validating.NewService(&MockIdentifierListRepository{}, source, structure)
+
+	result, err := service.Validate(context.Background(), []string{"SKU-1", "MODEL-1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// "Red" matches the option red, as Akeneo matches option codes case-insensitively
+	if !result.Valid() {
+		t.Errorf("Expected the hierarchies to be valid, got %+v", result.Missing)
+	}
+	if result.Products != 2 || result.Models != 2 {
+		t.Errorf("Expected 2 products and 2 models checked, got %d and %d", result.Products, result.Models)
+	}
+	if structure.familyLists != 1 {
+		t.Errorf("Expected the destination families to be listed once, got %d", structure.familyLists)
+	}
+}
+
+func TestValidate_ReportsMissingPrerequisites(t *testing.T) {
+	source, structure := newCatalog()
+	delete(structure.families, "shoes")
+	delete(structure.attributeTypes, "material")
+	delete(structure.options["sizes"], "xl")
+	structure.variants["boots"] = map[string]bool{}
+	delete(structure.categories, "summer")
+	service := validating.NewService(&MockIdentifierListRepository{}, source, structure)
+
+	result, err := service.Validate(context.Background(), []string{"SKU-1", "MODEL-1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var missing []string
+	for _, m := range result.Missing {
+		missing = append(missing, m.String())
+	}
+	expected := []string{
+		"attribute material",
+		"attribute_option sizes.xl",
+		"category summer",
+		"family shoes",
+		"family_variant boots.boots_by_size",
+	}
+	if !reflect.DeepEqual(missing, expected) {
+		t.Errorf("Expected %v, got %v", expected, missing)
+	}
+
+	variant := result.Missing[4]
+	if variant.Items != 2 || !reflect.DeepEqual(variant.UsedBy, []string{"product model MODEL-1", "product model MODEL-1-BLACK"}) {
+		t.Errorf("Expected the variant to be used by both models, got %d: %v", variant.Items, variant.UsedBy)
+	}
+
+	expectedCommands := []string{
+		"sync-attribute material",
+		"sync-attribute sizes",
+		"sync-category summer",
+		"sync-family shoes",
+		"sync-family boots --variants-only",
+	}
+	if commands := result.SyncFirst(); !reflect.DeepEqual(commands, expectedCommands) {
+		t.Errorf("Expected %v, got %v", expectedCommands, commands)
+	}
+}
+
+func TestValidate_ReportsIncompatibleAttributes(t *testing.T) {
+	source, structure := newCatalog()
+	sourceStructure := &MockStructureRepository{
+		attributeTypes: map[string]string{
+			"name":     "pim_catalog_text",
+			"color":    "pim_catalog_simpleselect",
+			"material": "pim_catalog_textarea",
+			"sizes":    "pim_catalog_multiselect",
+		},
+		localizable: map[string]bool{"name": true},
+	}
+	service := validating.NewService(&MockIdentifierListRepository{}, source, structure, validating.WithAttributeComparison(sourceStructure))
+
+	result, err := service.Validate(context.Background(), []string{"SKU-1", "MODEL-1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Valid() || len(result.Missing) != 0 {
+		t.Fatalf("Expected only incompatible attributes, got %+v", result)
+	}
+	if len(result.Incompatible) != 2 {
+		t.Fatalf("Expected 2 incompatible attributes, got %+v", result.Incompatible)
+	}
+
+	material := result.Incompatible[0]
+	if material.Attribute != "material" || !reflect.DeepEqual(material.Differences(), []string{"type pim_catalog_textarea → pim_catalog_text"}) {
+		t.Errorf("Expected material to differ by type, got %s: %v", material.Attribute, material.Differences())
+	}
+	if material.Items != 1 || !reflect.DeepEqual(material.UsedBy, []string{"product model MODEL-1"}) {
+		t.Errorf("Expected material to be used by the root model, got %d: %v", material.Items, material.UsedBy)
+	}
+
+	name := result.Incompatible[1]
+	if name.Attribute != "name" || !reflect.DeepEqual(name.Differences(), []string{"localizable true → false"}) {
+		t.Errorf("Expected name to differ by localizable, got %s: %v", name.Attribute, name.Differences())
+	}
+
+	// Attributes left out of the sync are not checked
+	excluded, err := filter.New(filter.Rules{ExcludedAttributes: []string{"material", "name"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	service = validating.NewService(&MockIdentifierListRepository{}, source, structure,
+		validating.WithAttributeComparison(sourceStructure), validating.WithValueFilter(excluded))
+
+	result, err = service.Validate(context.Background(), []string{"SKU-1", "MODEL-1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !result.Valid() {
+		t.Errorf("Expected the excluded attributes not to be compared, got %+v", result.Incompatible)
+	}
+}
+
+func TestValidate_ReportsHierarchiesNotFound(t *testing.T) {
+	source, structure := newCatalog()
+	service := validating.NewService(&MockIdentifierListRepository{}, source, structure)
+
+	result, err := service.Validate(context.Background(), []string{"NOPE"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Valid() || len(result.Errors) != 1 {
+		t.Errorf("Expected the unknown identifier to be reported, got %+v", result)
+	}
+}
+
+func TestValidateFile_ValidatesListedHierarchies(t *testing.T) {
+	source, structure := newCatalog()
+	delete(structure.families, "boots")
+	lists := &MockIdentifierListRepository{lists: map[string][]string{"skus.txt": {"MODEL-1"}}}
+	service := validating.NewService(lists, source, structure)
+
+	result, err := service.ValidateFile(context.Background(), "skus.txt")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.Missing) != 1 || result.Missing[0].String() != "family boots" || result.Missing[0].Items != 3 {
+		t.Errorf("Expected the boots family to be missing for 3 items, got %+v", result.Missing)
+	}
+
+	if _, err := service.ValidateFile(context.Background(), "missing.txt"); err == nil {
+		t.Error("Expected an error for an unreadable list")
+	}
+}
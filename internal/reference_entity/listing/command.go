@@ -0,0 +1,13 @@
+package listing
+
+import "akeneo-migrator/kit/bus"
+
+const ListReferenceEntitiesCommandType bus.Type = "reference_entity.list"
+
+// ListReferenceEntitiesCommand represents a command to list the reference entities of both instances
+type ListReferenceEntitiesCommand struct{}
+
+// Type returns the command type
+func (c ListReferenceEntitiesCommand) Type() bus.Type {
+	return ListReferenceEntitiesCommandType
+}
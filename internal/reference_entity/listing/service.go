@@ -0,0 +1,94 @@
+package listing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"akeneo-migrator/internal/reference_entity"
+)
+
+// Service lists the Reference Entities of source and destination side by side
+type Service struct {
+	sourceRepo reference_entity.EntityRepository
+	destRepo   reference_entity.EntityRepository
+}
+
+// NewService creates a new instance of the list service
+func NewService(sourceRepo, destRepo reference_entity.EntityRepository) *Service {
+	return &Service{
+		sourceRepo: sourceRepo,
+		destRepo:   destRepo,
+	}
+}
+
+// EntitySummary describes a Reference Entity on both instances.
+// The label counts are -1 when the entity does not exist on the instance.
+type EntitySummary struct {
+	Code         string
+	SourceLabels int
+	DestLabels   int
+}
+
+// InSource tells whether the entity exists in source
+func (e EntitySummary) InSource() bool {
+	return e.SourceLabels >= 0
+}
+
+// InDest tells whether the entity exists in destination
+func (e EntitySummary) InDest() bool {
+	return e.DestLabels >= 0
+}
+
+// ListResult contains the Reference Entities of both instances, sorted by code
+type ListResult struct {
+	Entities []EntitySummary
+}
+
+// List retrieves the Reference Entities of both instances with their number of labels
+func (s *Service) List(ctx context.Context) (*ListResult, error) {
+	sourceEntities, err := s.sourceRepo.FindEntities(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing reference entities from source: %w", err)
+	}
+
+	destEntities, err := s.destRepo.FindEntities(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing reference entities from destination: %w", err)
+	}
+
+	summaries := make(map[string]*EntitySummary)
+	summary := func(code string) *EntitySummary {
+		if _, ok := summaries[code]; !ok {
+			summaries[code] = &EntitySummary{Code: code, SourceLabels: -1, DestLabels: -1}
+		}
+		return summaries[code]
+	}
+
+	for _, entity := range sourceEntities {
+		if code, ok := entity["code"].(string); ok {
+			summary(code).SourceLabels = countLabels(entity)
+		}
+	}
+	for _, entity := range destEntities {
+		if code, ok := entity["code"].(string); ok {
+			summary(code).DestLabels = countLabels(entity)
+		}
+	}
+
+	result := &ListResult{Entities: make([]EntitySummary, 0, len(summaries))}
+	for _, entity := range summaries {
+		result.Entities = append(result.Entities, *entity)
+	}
+	sort.Slice(result.Entities, func(i, j int) bool {
+		return result.Entities[i].Code < result.Entities[j].Code
+	})
+
+	return result, nil
+}
+
+// countLabels returns the number of locales an entity is labelled in
+func countLabels(entity reference_entity.Entity) int {
+	labels, _ := entity["labels"].(map[string]interface{})
+	return len(labels)
+}
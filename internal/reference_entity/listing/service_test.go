@@ -0,0 +1,62 @@
+package listing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"akeneo-migrator/internal/reference_entity"
+)
+
+// mockEntityRepository serves a fixed list of entities
+type mockEntityRepository struct {
+	entities []reference_entity.Entity
+	err      error
+}
+
+func (m *mockEntityRepository) FindEntities(ctx context.Context) ([]reference_entity.Entity, error) {
+	return m.entities, m.err
+}
+
+func TestList_MergesEntitiesOfBothInstances(t *testing.T) {
+	sourceRepo := &mockEntityRepository{entities: []reference_entity.Entity{
+		{"code": "designers", "labels": map[string]interface{}{"en_US": "Designers", "fr_FR": "Designers"}},
+		{"code": "brands", "labels": map[string]interface{}{"en_US": "Brands"}},
+	}}
+	destRepo := &mockEntityRepository{entities: []reference_entity.Entity{
+		{"code": "brands", "labels": map[string]interface{}{}},
+		{"code": "colors", "labels": map[string]interface{}{"en_US": "Colors"}},
+	}}
+
+	result, err := NewService(sourceRepo, destRepo).List(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []EntitySummary{
+		{Code: "brands", SourceLabels: 1, DestLabels: 0},
+		{Code: "colors", SourceLabels: -1, DestLabels: 1},
+		{Code: "designers", SourceLabels: 2, DestLabels: -1},
+	}
+	if len(result.Entities) != len(expected) {
+		t.Fatalf("Expected %d entities, got %v", len(expected), result.Entities)
+	}
+	for i, entity := range expected {
+		if result.Entities[i] != entity {
+			t.Errorf("Expected %+v, got %+v", entity, result.Entities[i])
+		}
+	}
+
+	if result.Entities[1].InSource() || !result.Entities[1].InDest() {
+		t.Errorf("Expected colors to only exist in destination")
+	}
+}
+
+func TestList_ReturnsErrorOfDestination(t *testing.T) {
+	sourceRepo := &mockEntityRepository{}
+	destRepo := &mockEntityRepository{err: errors.New("unauthorized")}
+
+	if _, err := NewService(sourceRepo, destRepo).List(context.Background()); err == nil {
+		t.Fatal("Expected an error")
+	}
+}
@@ -1,6 +1,12 @@
 package reference_entity
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned when a reference entity does not exist in the instance
+var ErrNotFound = errors.New("reference entity not found")
 
 // Record represents a Reference Entity record
 type Record map[string]interface{}
@@ -11,6 +17,19 @@ type Entity map[string]interface{}
 // Attribute represents a Reference Entity attribute definition
 type Attribute map[string]interface{}
 
+// MediaFile represents a file referenced by an image value of a record
+type MediaFile struct {
+	Code     string
+	Filename string
+	Content  []byte
+}
+
+// EntityRepository lists the Reference Entities of an instance
+type EntityRepository interface {
+	// FindEntities retrieves all Reference Entity definitions
+	FindEntities(ctx context.Context) ([]Entity, error)
+}
+
 // SourceRepository defines read-only operations for the source
 type SourceRepository interface {
 	// FindEntity retrieves a Reference Entity definition
@@ -21,6 +40,16 @@ type SourceRepository interface {
 
 	// FindAll retrieves all records from a Reference Entity
 	FindAll(ctx context.Context, entityName string) ([]Record, error)
+
+	// StreamRecords processes the records of a Reference Entity in batches, as they are fetched.
+	// The callback is called for each batch of records
+	StreamRecords(ctx context.Context, entityName string, batchSize int, callback func([]Record) error) error
+
+	// FindRecord retrieves a single record from a Reference Entity
+	FindRecord(ctx context.Context, entityName string, code string) (Record, error)
+
+	// DownloadMediaFile retrieves the content of a media file
+	DownloadMediaFile(ctx context.Context, code string) (MediaFile, error)
 }
 
 // DestRepository defines read and write operations for the destination
@@ -40,6 +69,22 @@ type DestRepository interface {
 	// FindAll retrieves all records from a Reference Entity
 	FindAll(ctx context.Context, entityName string) ([]Record, error)
 
+	// FindRecord retrieves a single record from a Reference Entity
+	FindRecord(ctx context.Context, entityName string, code string) (Record, error)
+
 	// Save creates or updates a record in a Reference Entity
 	Save(ctx context.Context, entityName string, code string, record Record) error
+
+	// SaveAll creates or updates several records of a Reference Entity in batches.
+	// It returns the errors of the records that were not written, indexed by code
+	SaveAll(ctx context.Context, entityName string, records []Record) (map[string]error, error)
+
+	// FindCodes retrieves the codes of all records of a Reference Entity
+	FindCodes(ctx context.Context, entityName string) ([]string, error)
+
+	// Delete deletes a record of a Reference Entity
+	Delete(ctx context.Context, entityName string, code string) error
+
+	// UploadMediaFile stores a media file and returns the code assigned to it
+	UploadMediaFile(ctx context.Context, file MediaFile) (string, error)
 }
@@ -1,16 +1,34 @@
 package syncing
 
-import "akeneo-migrator/kit/bus"
+import (
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/limit"
+	"akeneo-migrator/kit/retry"
+)
 
 const SyncReferenceEntityCommandType bus.Type = "reference_entity.sync"
 
 // SyncReferenceEntityCommand represents a command to sync a reference entity
 type SyncReferenceEntityCommand struct {
 	EntityName string
-	Debug      bool
+	// Records limits the sync to these record codes, skipping the entity definition and attributes
+	Records []string
+	// Prune deletes the destination records missing from source; ignored when Records is set
+	Prune bool
+	// Range selects the records synced, e.g. the first 50 for a trial run; ignored when Records is set
+	Range limit.Range
+	Debug bool
 }
 
 // Type returns the command type
 func (c SyncReferenceEntityCommand) Type() bus.Type {
 	return SyncReferenceEntityCommandType
 }
+
+// RetryItem returns the item targeted by the command
+func (c SyncReferenceEntityCommand) RetryItem() retry.Failure {
+	if len(c.Records) == 1 {
+		return retry.Failure{Kind: KindRecord, Scope: c.EntityName, Code: c.Records[0]}
+	}
+	return retry.Failure{Kind: KindReferenceEntity, Code: c.EntityName}
+}
@@ -25,7 +25,13 @@ func (h *CommandHandler) Handle(ctx context.Context, msg bus.Message) (bus.Respo
 		return bus.Response{}, nil
 	}
 
-	result, err := h.service.Sync(ctx, cmd.EntityName)
+	var result *SyncResult
+	var err error
+	if len(cmd.Records) > 0 {
+		result, err = h.service.SyncRecords(ctx, cmd.EntityName, cmd.Records)
+	} else {
+		result, err = h.service.Sync(ctx, cmd.EntityName, SyncOptions{Prune: cmd.Prune, Range: cmd.Range})
+	}
 	if err != nil {
 		return bus.Response{Error: err}, err
 	}
@@ -0,0 +1,143 @@
+package syncing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"akeneo-migrator/internal/reference_entity"
+	"akeneo-migrator/kit/dryrun"
+)
+
+// MediaAttributeType is the type of Reference Entity attributes holding media files
+const MediaAttributeType = "image"
+
+// KindMediaFile is a media file uploaded to destination, reported in the writes planned by dry runs
+const KindMediaFile = "media_file"
+
+// mediaCache remembers the media files already copied, indexed by source file code
+type mediaCache struct {
+	mu    sync.Mutex
+	codes map[string]string
+}
+
+// get returns the destination code of a source media file
+func (c *mediaCache) get(sourceCode string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	destCode, ok := c.codes[sourceCode]
+	return destCode, ok
+}
+
+// set records the destination code of a source media file
+func (c *mediaCache) set(sourceCode, destCode string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.codes == nil {
+		c.codes = make(map[string]string)
+	}
+	c.codes[sourceCode] = destCode
+}
+
+// FindMediaAttributes returns the codes of the attributes of an entity holding media files
+func (s *Service) FindMediaAttributes(ctx context.Context, entityName string) (map[string]bool, error) {
+	attributes, err := s.sourceRepo.FindAttributes(ctx, entityName)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching attributes from source: %w", err)
+	}
+
+	return mediaAttributes(attributes), nil
+}
+
+// mediaAttributes returns the codes of the given attributes holding media files
+func mediaAttributes(attributes []reference_entity.Attribute) map[string]bool {
+	codes := make(map[string]bool)
+	for _, attribute := range attributes {
+		attributeCode, _ := attribute["code"].(string)
+		if attributeType, _ := attribute["type"].(string); attributeType == MediaAttributeType && attributeCode != "" {
+			codes[attributeCode] = true
+		}
+	}
+
+	return codes
+}
+
+// CopyMediaFiles uploads the media files of a record to destination and returns a copy of the
+// record referencing the destination file codes, with the number of files uploaded.
+// Files already copied by the service are referenced without being uploaded again.
+// During a dry run, the uploads are recorded and the record keeps the source file codes.
+func (s *Service) CopyMediaFiles(ctx context.Context, record reference_entity.Record, mediaAttributes map[string]bool) (reference_entity.Record, int, error) {
+	values, ok := record["values"].(map[string]interface{})
+	if !ok || len(mediaAttributes) == 0 {
+		return record, 0, nil
+	}
+
+	copiedValues := make(map[string]interface{}, len(values))
+	for attributeCode, value := range values {
+		copiedValues[attributeCode] = value
+	}
+
+	uploads := 0
+	for attributeCode := range mediaAttributes {
+		entries, ok := values[attributeCode].([]interface{})
+		if !ok {
+			continue
+		}
+
+		copiedEntries := make([]interface{}, len(entries))
+		for i, entry := range entries {
+			copiedEntries[i] = entry
+
+			value, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			fileCode, ok := value["data"].(string)
+			if !ok || fileCode == "" {
+				continue
+			}
+
+			// The same file may be used by several locales, channels or records
+			destCode, done := s.mediaFiles.get(fileCode)
+			if !done && dryrun.Record(ctx, dryrun.Write{Kind: KindMediaFile, Code: fileCode}) {
+				// Dry runs neither download nor upload files: the record keeps the source file code
+				destCode, done = fileCode, true
+				s.mediaFiles.set(fileCode, destCode)
+				uploads++
+			}
+			if !done {
+				file, err := s.sourceRepo.DownloadMediaFile(ctx, fileCode)
+				if err != nil {
+					return nil, uploads, fmt.Errorf("error downloading media file %s: %w", fileCode, err)
+				}
+
+				destCode, err = s.destRepo.UploadMediaFile(ctx, file)
+				if err != nil {
+					return nil, uploads, fmt.Errorf("error uploading media file %s: %w", fileCode, err)
+				}
+
+				s.mediaFiles.set(fileCode, destCode)
+				uploads++
+			}
+
+			copiedValue := make(map[string]interface{}, len(value))
+			for key, field := range value {
+				copiedValue[key] = field
+			}
+			copiedValue["data"] = destCode
+			copiedEntries[i] = copiedValue
+		}
+
+		copiedValues[attributeCode] = copiedEntries
+	}
+
+	copied := make(reference_entity.Record, len(record))
+	for key, value := range record {
+		copied[key] = value
+	}
+	copied["values"] = copiedValues
+
+	return copied, uploads, nil
+}
@@ -2,23 +2,141 @@ package syncing
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"akeneo-migrator/internal/reference_entity"
+	"akeneo-migrator/kit/anonymize"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/filter"
+	"akeneo-migrator/kit/labels"
+	"akeneo-migrator/kit/limit"
+	"akeneo-migrator/kit/locales"
+	"akeneo-migrator/kit/logger"
+	"akeneo-migrator/kit/progress"
+	"akeneo-migrator/kit/prune"
+	"akeneo-migrator/kit/retry"
+	"akeneo-migrator/kit/transform"
+	"akeneo-migrator/kit/workers"
 )
 
+// Kinds of items reported as failures
+const (
+	KindReferenceEntity = "reference_entity"
+	// KindRecord is a record; its scope is the reference entity
+	KindRecord = "record"
+	// KindAttribute is an attribute of a reference entity, its scope; only reported in planned writes
+	KindAttribute = "reference_entity_attribute"
+	// KindRecordDeletion is a record deleted by a prune; only reported in planned writes
+	KindRecordDeletion = "record_deletion"
+)
+
+// LabelAttribute is the record attribute holding the record label
+const LabelAttribute = "label"
+
+// RecordBatchSize is the number of records fetched from source and written to destination at a time
+const RecordBatchSize = 100
+
 // Service handles the synchronization logic for Reference Entities
 type Service struct {
-	sourceRepo reference_entity.SourceRepository
-	destRepo   reference_entity.DestRepository
+	sourceRepo    reference_entity.SourceRepository
+	destRepo      reference_entity.DestRepository
+	labelStrategy labels.Strategy
+	anonymizer    *anonymize.Anonymizer
+	transformer   *transform.Transformer
+	valueFilter   *filter.Filter
+	localeChecker *locales.Checker
+	confirmPrune  prune.Confirm
+	mediaFiles    mediaCache
+	workers       int
+	logger        *logger.Logger
+}
+
+// Option configures the synchronization service
+type Option func(*Service)
+
+// WithLabelStrategy sets how record labels are merged with destination translations
+func WithLabelStrategy(strategy labels.Strategy) Option {
+	return func(s *Service) {
+		if strategy != "" {
+			s.labelStrategy = strategy
+		}
+	}
+}
+
+// WithAnonymizer anonymizes record values before they are written to destination
+func WithAnonymizer(anonymizer *anonymize.Anonymizer) Option {
+	return func(s *Service) {
+		s.anonymizer = anonymizer
+	}
+}
+
+// WithTransformer computes record values with expressions before they are written to destination
+func WithTransformer(transformer *transform.Transformer) Option {
+	return func(s *Service) {
+		s.transformer = transformer
+	}
+}
+
+// WithValueFilter removes the record values excluded by the filter before they are written to destination
+func WithValueFilter(valueFilter *filter.Filter) Option {
+	return func(s *Service) {
+		s.valueFilter = valueFilter
+	}
+}
+
+// WithLocaleChecker checks the locales of record values against the locales enabled in destination
+func WithLocaleChecker(checker *locales.Checker) Option {
+	return func(s *Service) {
+		s.localeChecker = checker
+	}
+}
+
+// WithPruneConfirmation sets how the deletion of destination records missing from source is confirmed.
+// Without it, prunes only report the records they would delete.
+func WithPruneConfirmation(confirm prune.Confirm) Option {
+	return func(s *Service) {
+		s.confirmPrune = confirm
+	}
+}
+
+// WithWorkers sets the number of record batches written to destination at the same time
+func WithWorkers(workers int) Option {
+	return func(s *Service) {
+		s.workers = workers
+	}
+}
+
+// WithLogger sends the messages of the syncs to log instead of the standard output
+func WithLogger(log *logger.Logger) Option {
+	return func(s *Service) {
+		s.logger = log
+	}
 }
 
 // NewService creates a new instance of the synchronization service
-func NewService(sourceRepo reference_entity.SourceRepository, destRepo reference_entity.DestRepository) *Service {
-	return &Service{
-		sourceRepo: sourceRepo,
-		destRepo:   destRepo,
+func NewService(sourceRepo reference_entity.SourceRepository, destRepo reference_entity.DestRepository, opts ...Option) *Service {
+	service := &Service{
+		sourceRepo:    sourceRepo,
+		destRepo:      destRepo,
+		labelStrategy: labels.Overwrite,
+		logger:        logger.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(service)
 	}
+
+	return service
+}
+
+// SyncOptions contains the options of a reference entity synchronization
+type SyncOptions struct {
+	// Prune deletes the destination records missing from source, once the deletion is confirmed
+	Prune bool
+	// Range selects the records synced, in the order source lists them, e.g. the first 50 for a trial run
+	Range limit.Range
 }
 
 // SyncResult contains the result of a synchronization operation
@@ -27,7 +145,43 @@ type SyncResult struct {
 	TotalRecords int
 	SuccessCount int
 	ErrorCount   int
+	MediaFiles   int
 	Errors       []SyncError
+	// PruneCandidates are the destination records missing from source, found by a prune
+	PruneCandidates []string
+	// Pruned is the number of records deleted; nothing is deleted when the prune is not confirmed
+	Pruned      int
+	PruneErrors []SyncError
+	// Planned are the writes recorded instead of being sent during a dry run
+	Planned []dryrun.Write
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// Failures returns the records that could not be synchronized
+func (r *SyncResult) Failures() []retry.Failure {
+	failures := make([]retry.Failure, 0, len(r.Errors))
+	for _, syncErr := range r.Errors {
+		failures = append(failures, retry.Failure{Kind: KindRecord, Scope: r.EntityName, Code: syncErr.Code, Error: syncErr.Message})
+	}
+	return failures
+}
+
+// Synced returns the number of records written
+func (r *SyncResult) Synced() int {
+	return r.SuccessCount
+}
+
+// add merges the outcome of a batch of records into the result
+func (r *SyncResult) add(batch *SyncResult) {
+	r.TotalRecords += batch.TotalRecords
+	r.SuccessCount += batch.SuccessCount
+	r.ErrorCount += batch.ErrorCount
+	r.MediaFiles += batch.MediaFiles
+	r.Errors = append(r.Errors, batch.Errors...)
 }
 
 // SyncError represents an error during synchronization
@@ -37,11 +191,20 @@ type SyncError struct {
 }
 
 // Sync synchronizes a Reference Entity (definition + attributes + records) from source to destination
-func (s *Service) Sync(ctx context.Context, entityName string) (*SyncResult, error) {
+func (s *Service) Sync(ctx context.Context, entityName string, opts SyncOptions) (*SyncResult, error) {
+	if err := opts.Range.Validate(); err != nil {
+		return nil, err
+	}
+	// A prune compares destination with every source record, so it would delete the records out of the range
+	if opts.Prune && !opts.Range.IsZero() {
+		return nil, fmt.Errorf("prune cannot be combined with a limit or an offset")
+	}
+
 	result := &SyncResult{
 		EntityName: entityName,
 		Errors:     make([]SyncError, 0),
 	}
+	ctx, planned := dryrun.Collect(ctx)
 
 	// 1. Get Reference Entity definition from source
 	entity, err := s.sourceRepo.FindEntity(ctx, entityName)
@@ -50,7 +213,9 @@ func (s *Service) Sync(ctx context.Context, entityName string) (*SyncResult, err
 	}
 
 	// 2. Create or update Reference Entity in destination
-	err = s.destRepo.SaveEntity(ctx, entityName, entity)
+	if !dryrun.Record(ctx, dryrun.Write{Kind: KindReferenceEntity, Code: entityName, Data: entity}) {
+		err = s.destRepo.SaveEntity(ctx, entityName, entity)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("error creating/updating reference entity in destination: %w", err)
 	}
@@ -68,20 +233,165 @@ func (s *Service) Sync(ctx context.Context, entityName string) (*SyncResult, err
 			return nil, fmt.Errorf("could not extract attribute code from attribute")
 		}
 
+		if dryrun.Record(ctx, dryrun.Write{Kind: KindAttribute, Scope: entityName, Code: attributeCode, Data: attribute}) {
+			continue
+		}
 		if attrErr := s.destRepo.SaveAttribute(ctx, entityName, attributeCode, attribute); attrErr != nil {
 			return nil, fmt.Errorf("error creating/updating attribute %s in destination: %w", attributeCode, attrErr)
 		}
 	}
 
-	// 5. Get all records from source
-	records, err := s.sourceRepo.FindAll(ctx, entityName)
+	// 5. Stream records from source, writing each batch as it arrives
+	destRecords, err := s.findDestRecords(ctx, entityName)
 	if err != nil {
+		return nil, fmt.Errorf("error fetching records from destination: %w", err)
+	}
+
+	mediaAttributes := mediaAttributes(attributes)
+	sourceCodes := make(map[string]bool)
+	pool := workers.New(s.workers)
+	cursor := opts.Range.Start()
+	err = s.sourceRepo.StreamRecords(ctx, entityName, RecordBatchSize, func(records []reference_entity.Record) error {
+		records, reached := limit.Take(cursor, records)
+		if opts.Prune {
+			for _, record := range records {
+				if code, ok := record["code"].(string); ok {
+					sourceCodes[code] = true
+				}
+			}
+		}
+
+		s.writeBatch(ctx, pool, entityName, records, destRecords, mediaAttributes, result)
+		return reached
+	})
+	pool.Wait()
+	if err != nil && !errors.Is(err, limit.ErrReached) {
 		return nil, fmt.Errorf("error fetching records from source: %w", err)
 	}
 
-	result.TotalRecords = len(records)
+	// 6. Delete the destination records missing from source
+	if opts.Prune {
+		if err := s.pruneRecords(ctx, entityName, sourceCodes, result); err != nil {
+			return nil, err
+		}
+	}
+
+	result.Planned = planned()
+	return result, nil
+}
+
+// pruneRecords deletes the destination records that are not in source, once the deletion is confirmed.
+// Dry runs only record the deletions.
+func (s *Service) pruneRecords(ctx context.Context, entityName string, sourceCodes map[string]bool, result *SyncResult) error {
+	destCodes, err := s.destRepo.FindCodes(ctx, entityName)
+	if err != nil {
+		return fmt.Errorf("error fetching record codes from destination: %w", err)
+	}
+
+	result.PruneCandidates = prune.Missing(sourceCodes, destCodes)
+	if len(result.PruneCandidates) == 0 {
+		return nil
+	}
+
+	if dryrun.Enabled(ctx) {
+		for _, code := range result.PruneCandidates {
+			dryrun.Record(ctx, dryrun.Write{Kind: KindRecordDeletion, Scope: entityName, Code: code})
+		}
+		return nil
+	}
+
+	if s.confirmPrune == nil || !s.confirmPrune(ctx, KindRecord, entityName, result.PruneCandidates) {
+		return nil
+	}
 
-	// 6. Sync each record to destination
+	for _, code := range result.PruneCandidates {
+		if err := s.destRepo.Delete(ctx, entityName, code); err != nil {
+			result.PruneErrors = append(result.PruneErrors, SyncError{Code: code, Message: err.Error()})
+			continue
+		}
+		result.Pruned++
+	}
+
+	return nil
+}
+
+// SyncRecords synchronizes only the given records of a Reference Entity, assuming its definition
+// and attributes already exist in destination. Codes not found in source are reported as errors.
+func (s *Service) SyncRecords(ctx context.Context, entityName string, codes []string) (*SyncResult, error) {
+	result := &SyncResult{
+		EntityName: entityName,
+		Errors:     make([]SyncError, 0),
+	}
+	ctx, planned := dryrun.Collect(ctx)
+
+	wanted := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		wanted[code] = true
+	}
+
+	destRecords, err := s.findDestRecords(ctx, entityName)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching records from destination: %w", err)
+	}
+
+	mediaAttributes, err := s.FindMediaAttributes(ctx, entityName)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := workers.New(s.workers)
+	err = s.sourceRepo.StreamRecords(ctx, entityName, RecordBatchSize, func(records []reference_entity.Record) error {
+		selected := make([]reference_entity.Record, 0, len(records))
+		for _, record := range records {
+			code, _ := record["code"].(string)
+			if wanted[code] {
+				selected = append(selected, record)
+				delete(wanted, code)
+			}
+		}
+
+		s.writeBatch(ctx, pool, entityName, selected, destRecords, mediaAttributes, result)
+		return nil
+	})
+	pool.Wait()
+	if err != nil {
+		return nil, fmt.Errorf("error fetching records from source: %w", err)
+	}
+
+	for _, code := range codes {
+		if wanted[code] {
+			result.TotalRecords++
+			result.ErrorCount++
+			result.Errors = append(result.Errors, SyncError{
+				Code:    code,
+				Message: "record not found in source",
+			})
+		}
+	}
+
+	result.Planned = planned()
+	return result, nil
+}
+
+// writeBatch writes a batch of records on the worker pool, merges its outcome into the result and
+// reports the records as processed
+func (s *Service) writeBatch(ctx context.Context, pool *workers.Pool, entityName string, records []reference_entity.Record, destRecords map[string]reference_entity.Record, mediaAttributes map[string]bool, result *SyncResult) {
+	pool.Go(func() {
+		batch := &SyncResult{}
+		s.syncRecords(ctx, entityName, records, destRecords, mediaAttributes, batch)
+		pool.Merge(func() { result.add(batch) })
+		progress.Done(ctx, batch.TotalRecords)
+	})
+}
+
+// syncRecords writes a batch of records to destination and reports the outcome of each one in the result.
+// Destination records are only used to merge labels. Media files are copied before the batch is written.
+func (s *Service) syncRecords(ctx context.Context, entityName string, records []reference_entity.Record, destRecords map[string]reference_entity.Record, mediaAttributes map[string]bool, result *SyncResult) {
+	result.TotalRecords += len(records)
+
+	// Prepare each record, then write the batch to destination
+	codes := make([]string, 0, len(records))
+	prepared := make([]reference_entity.Record, 0, len(records))
 	for _, record := range records {
 		code, ok := record["code"].(string)
 		if !ok {
@@ -93,17 +403,192 @@ func (s *Service) Sync(ctx context.Context, entityName string) (*SyncResult, err
 			continue
 		}
 
-		err := s.destRepo.Save(ctx, entityName, code, record)
+		destRecord, exists := destRecords[code]
+		preparedRecord, err := s.PrepareRecord(ctx, record, destRecord, exists)
+		if err == nil {
+			var uploads int
+			preparedRecord, uploads, err = s.CopyMediaFiles(ctx, preparedRecord, mediaAttributes)
+			result.MediaFiles += uploads
+		}
 		if err != nil {
 			result.ErrorCount++
 			result.Errors = append(result.Errors, SyncError{
 				Code:    code,
 				Message: err.Error(),
 			})
+			continue
+		}
+
+		codes = append(codes, code)
+		prepared = append(prepared, preparedRecord)
+	}
+
+	if len(prepared) == 0 {
+		return
+	}
+
+	failed, err := s.saveRecords(ctx, entityName, codes, prepared)
+	for _, code := range codes {
+		saveErr := err
+		if saveErr == nil {
+			saveErr = failed[code]
+		}
+
+		if saveErr != nil {
+			result.ErrorCount++
+			result.Errors = append(result.Errors, SyncError{
+				Code:    code,
+				Message: saveErr.Error(),
+			})
 		} else {
 			result.SuccessCount++
 		}
 	}
+}
+
+// saveRecords writes a batch of records to destination, or records the writes during a dry run
+func (s *Service) saveRecords(ctx context.Context, entityName string, codes []string, records []reference_entity.Record) (map[string]error, error) {
+	if !dryrun.Enabled(ctx) {
+		return s.destRepo.SaveAll(ctx, entityName, records)
+	}
+
+	for i, record := range records {
+		dryrun.Record(ctx, dryrun.Write{Kind: KindRecord, Scope: entityName, Code: codes[i], Data: record})
+	}
+	return nil, nil
+}
+
+// findDestRecords returns the destination records indexed by code.
+// They are only needed when labels are merged, so nothing is fetched with the overwrite strategy.
+func (s *Service) findDestRecords(ctx context.Context, entityName string) (map[string]reference_entity.Record, error) {
+	destRecords := make(map[string]reference_entity.Record)
+	if s.labelStrategy == labels.Overwrite {
+		return destRecords, nil
+	}
+
+	records, err := s.destRepo.FindAll(ctx, entityName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		if code, ok := record["code"].(string); ok {
+			destRecords[code] = record
+		}
+	}
+
+	return destRecords, nil
+}
+
+// PrepareRecord returns a copy of a source record ready to be written: values are transformed, anonymized, filtered,
+// checked against the destination locales, and the label is merged with the destination record when it exists
+func (s *Service) PrepareRecord(ctx context.Context, record, destRecord reference_entity.Record, exists bool) (reference_entity.Record, error) {
+	transformed, err := s.transformer.Apply(record)
+	if err != nil {
+		return nil, fmt.Errorf("error transforming record %v: %w", record["code"], err)
+	}
+	record = s.filterRecord(s.anonymizeRecord(transformed))
+
+	record, err = s.checkLocales(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mergeRecordLabel(record, destRecord, exists), nil
+}
+
+// checkLocales returns a copy of a record without the values in locales that are not enabled in destination,
+// or an error when the locale policy rejects such values
+func (s *Service) checkLocales(ctx context.Context, record reference_entity.Record) (reference_entity.Record, error) {
+	values, ok := record["values"].(map[string]interface{})
+	if !ok {
+		return record, nil
+	}
+
+	checked, disabled, err := s.localeChecker.Apply(ctx, values)
+	if err != nil {
+		return nil, fmt.Errorf("error checking locales of record %v: %w", record["code"], err)
+	}
+	if len(disabled) == 0 {
+		return record, nil
+	}
+
+	s.logger.Warn("   ⚠️  Dropped values in locales not enabled in destination", logger.F("record", record["code"]), logger.F("locales", strings.Join(disabled, ",")))
+
+	result := make(reference_entity.Record, len(record))
+	for key, value := range record {
+		result[key] = value
+	}
+	result["values"] = checked
 
 	return result, nil
 }
+
+// mergeRecordLabel applies the label strategy to the label value of a record without modifying the source data
+func (s *Service) mergeRecordLabel(record, destRecord reference_entity.Record, exists bool) reference_entity.Record {
+	values, ok := record["values"].(map[string]interface{})
+	if !ok || s.labelStrategy == labels.Overwrite {
+		return record
+	}
+
+	if _, hasLabel := values[LabelAttribute]; !hasLabel {
+		return record
+	}
+
+	var destLabel interface{}
+	if destValues, ok := destRecord["values"].(map[string]interface{}); ok {
+		destLabel = destValues[LabelAttribute]
+	}
+
+	mergedValues := make(map[string]interface{}, len(values))
+	for attributeCode, value := range values {
+		mergedValues[attributeCode] = value
+	}
+
+	label, send := labels.MergeValues(s.labelStrategy, values[LabelAttribute], destLabel, exists)
+	if send {
+		mergedValues[LabelAttribute] = label
+	} else {
+		delete(mergedValues, LabelAttribute)
+	}
+
+	merged := make(reference_entity.Record, len(record))
+	for key, value := range record {
+		merged[key] = value
+	}
+	merged["values"] = mergedValues
+
+	return merged
+}
+
+// filterRecord returns a copy of a record without the values excluded by the value filter
+func (s *Service) filterRecord(record reference_entity.Record) reference_entity.Record {
+	values, ok := record["values"].(map[string]interface{})
+	if !ok || !s.valueFilter.Enabled() {
+		return record
+	}
+
+	filtered := make(reference_entity.Record, len(record))
+	for key, value := range record {
+		filtered[key] = value
+	}
+	filtered["values"] = s.valueFilter.Apply(values)
+
+	return filtered
+}
+
+// anonymizeRecord returns a copy of a record with its values anonymized
+func (s *Service) anonymizeRecord(record reference_entity.Record) reference_entity.Record {
+	values, ok := record["values"].(map[string]interface{})
+	if !ok || !s.anonymizer.Enabled() {
+		return record
+	}
+
+	anonymized := make(reference_entity.Record, len(record))
+	for key, value := range record {
+		anonymized[key] = value
+	}
+	anonymized["values"] = s.anonymizer.Apply(values)
+
+	return anonymized
+}
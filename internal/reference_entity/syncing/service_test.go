@@ -3,10 +3,19 @@ package syncing_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 
 	"akeneo-migrator/internal/reference_entity"
 	"akeneo-migrator/internal/reference_entity/syncing"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/filter"
+	"akeneo-migrator/kit/labels"
+	"akeneo-migrator/kit/limit"
+	"akeneo-migrator/kit/locales"
+	"akeneo-migrator/kit/transform"
 )
 
 // MockSourceRepository is a mock of the source repository for testing
@@ -14,6 +23,8 @@ type MockSourceRepository struct {
 	findEntityFunc     func(ctx context.Context, entityCode string) (reference_entity.Entity, error)
 	findAttributesFunc func(ctx context.Context, entityCode string) ([]reference_entity.Attribute, error)
 	findAllFunc        func(ctx context.Context, entityName string) ([]reference_entity.Record, error)
+	findRecordFunc     func(ctx context.Context, entityName string, code string) (reference_entity.Record, error)
+	downloadFunc       func(ctx context.Context, code string) (reference_entity.MediaFile, error)
 }
 
 func (m *MockSourceRepository) FindEntity(ctx context.Context, entityCode string) (reference_entity.Entity, error) {
@@ -37,6 +48,38 @@ func (m *MockSourceRepository) FindAll(ctx context.Context, entityName string) (
 	return nil, nil
 }
 
+func (m *MockSourceRepository) StreamRecords(ctx context.Context, entityName string, batchSize int, callback func([]reference_entity.Record) error) error {
+	records, err := m.FindAll(ctx, entityName)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(records); start += batchSize {
+		end := start + batchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		if err := callback(records[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MockSourceRepository) FindRecord(ctx context.Context, entityName string, code string) (reference_entity.Record, error) {
+	if m.findRecordFunc != nil {
+		return m.findRecordFunc(ctx, entityName, code)
+	}
+	return reference_entity.Record{"code": code}, nil
+}
+
+func (m *MockSourceRepository) DownloadMediaFile(ctx context.Context, code string) (reference_entity.MediaFile, error) {
+	if m.downloadFunc != nil {
+		return m.downloadFunc(ctx, code)
+	}
+	return reference_entity.MediaFile{Code: code}, nil
+}
+
 // MockDestRepository is a mock of the destination repository for testing
 type MockDestRepository struct {
 	findEntityFunc     func(ctx context.Context, entityCode string) (reference_entity.Entity, error)
@@ -44,7 +87,12 @@ type MockDestRepository struct {
 	findAttributesFunc func(ctx context.Context, entityCode string) ([]reference_entity.Attribute, error)
 	saveAttributeFunc  func(ctx context.Context, entityCode string, attributeCode string, attribute reference_entity.Attribute) error
 	findAllFunc        func(ctx context.Context, entityName string) ([]reference_entity.Record, error)
+	findRecordFunc     func(ctx context.Context, entityName string, code string) (reference_entity.Record, error)
 	saveFunc           func(ctx context.Context, entityName string, code string, record reference_entity.Record) error
+	saveAllFunc        func(ctx context.Context, entityName string, records []reference_entity.Record) (map[string]error, error)
+	uploadFunc         func(ctx context.Context, file reference_entity.MediaFile) (string, error)
+	codes              []string
+	deleted            []string
 }
 
 func (m *MockDestRepository) FindEntity(ctx context.Context, entityCode string) (reference_entity.Entity, error) {
@@ -82,6 +130,13 @@ func (m *MockDestRepository) FindAll(ctx context.Context, entityName string) ([]
 	return nil, nil
 }
 
+func (m *MockDestRepository) FindRecord(ctx context.Context, entityName string, code string) (reference_entity.Record, error) {
+	if m.findRecordFunc != nil {
+		return m.findRecordFunc(ctx, entityName, code)
+	}
+	return nil, errors.New("record not found")
+}
+
 func (m *MockDestRepository) Save(ctx context.Context, entityName string, code string, record reference_entity.Record) error {
 	if m.saveFunc != nil {
 		return m.saveFunc(ctx, entityName, code, record)
@@ -89,6 +144,37 @@ func (m *MockDestRepository) Save(ctx context.Context, entityName string, code s
 	return nil
 }
 
+func (m *MockDestRepository) SaveAll(ctx context.Context, entityName string, records []reference_entity.Record) (map[string]error, error) {
+	if m.saveAllFunc != nil {
+		return m.saveAllFunc(ctx, entityName, records)
+	}
+
+	failed := map[string]error{}
+	for _, record := range records {
+		code, _ := record["code"].(string)
+		if err := m.Save(ctx, entityName, code, record); err != nil {
+			failed[code] = err
+		}
+	}
+	return failed, nil
+}
+
+func (m *MockDestRepository) FindCodes(ctx context.Context, entityName string) ([]string, error) {
+	return m.codes, nil
+}
+
+func (m *MockDestRepository) Delete(ctx context.Context, entityName string, code string) error {
+	m.deleted = append(m.deleted, code)
+	return nil
+}
+
+func (m *MockDestRepository) UploadMediaFile(ctx context.Context, file reference_entity.MediaFile) (string, error) {
+	if m.uploadFunc != nil {
+		return m.uploadFunc(ctx, file)
+	}
+	return file.Code, nil
+}
+
 func TestSync_Success(t *testing.T) {
 	// Arrange
 	mockRecords := []reference_entity.Record{
@@ -111,7 +197,7 @@ func TestSync_Success(t *testing.T) {
 	service := syncing.NewService(sourceRepo, destRepo)
 
 	// Act
-	result, err := service.Sync(context.Background(), "test_entity")
+	result, err := service.Sync(context.Background(), "test_entity", syncing.SyncOptions{})
 
 	// Assert
 	if err != nil {
@@ -156,7 +242,7 @@ func TestSync_WithErrors(t *testing.T) {
 	service := syncing.NewService(sourceRepo, destRepo)
 
 	// Act
-	result, err := service.Sync(context.Background(), "test_entity")
+	result, err := service.Sync(context.Background(), "test_entity", syncing.SyncOptions{})
 
 	// Assert
 	if err != nil {
@@ -189,10 +275,601 @@ func TestSync_SourceError(t *testing.T) {
 	service := syncing.NewService(sourceRepo, destRepo)
 
 	// Act
-	_, err := service.Sync(context.Background(), "test_entity")
+	_, err := service.Sync(context.Background(), "test_entity", syncing.SyncOptions{})
 
 	// Assert
 	if err == nil {
 		t.Error("Expected error, got nil")
 	}
 }
+
+func TestSync_KeepLabelStrategy(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findAllFunc: func(ctx context.Context, entityName string) ([]reference_entity.Record, error) {
+			return []reference_entity.Record{
+				{"code": "acme", "values": map[string]interface{}{
+					"label":   []interface{}{map[string]interface{}{"locale": "en_US", "channel": nil, "data": "Acme"}},
+					"country": []interface{}{map[string]interface{}{"locale": nil, "channel": nil, "data": "US"}},
+				}},
+				{"code": "globex", "values": map[string]interface{}{
+					"label": []interface{}{map[string]interface{}{"locale": "en_US", "channel": nil, "data": "Globex"}},
+				}},
+			}, nil
+		},
+	}
+
+	saved := map[string]reference_entity.Record{}
+	destRepo := &MockDestRepository{
+		findAllFunc: func(ctx context.Context, entityName string) ([]reference_entity.Record, error) {
+			return []reference_entity.Record{
+				{"code": "acme", "values": map[string]interface{}{
+					"label": []interface{}{map[string]interface{}{"locale": "en_US", "channel": nil, "data": "ACME Corporation"}},
+				}},
+			}, nil
+		},
+		saveFunc: func(ctx context.Context, entityName string, code string, record reference_entity.Record) error {
+			saved[code] = record
+			return nil
+		},
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithLabelStrategy(labels.Keep))
+	result, err := service.Sync(context.Background(), "brands", syncing.SyncOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.SuccessCount != 2 {
+		t.Errorf("Expected 2 successful records, got %d", result.SuccessCount)
+	}
+
+	acmeValues := saved["acme"]["values"].(map[string]interface{})
+	if _, hasLabel := acmeValues["label"]; hasLabel {
+		t.Error("Expected label of existing record not to be sent")
+	}
+	if _, hasCountry := acmeValues["country"]; !hasCountry {
+		t.Error("Expected other values of existing record to be sent")
+	}
+
+	globexValues := saved["globex"]["values"].(map[string]interface{})
+	if _, hasLabel := globexValues["label"]; !hasLabel {
+		t.Error("Expected label of new record to be sent")
+	}
+}
+
+func TestSync_RejectsRecordsWithDisabledLocales(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findAllFunc: func(ctx context.Context, entityName string) ([]reference_entity.Record, error) {
+			return []reference_entity.Record{
+				{"code": "acme", "values": map[string]interface{}{
+					"label": []interface{}{map[string]interface{}{"locale": "en_US", "channel": nil, "data": "Acme"}},
+				}},
+				{"code": "globex", "values": map[string]interface{}{
+					"label": []interface{}{map[string]interface{}{"locale": "ja_JP", "channel": nil, "data": "グローベックス"}},
+				}},
+			}, nil
+		},
+	}
+
+	saved := map[string]reference_entity.Record{}
+	destRepo := &MockDestRepository{
+		saveFunc: func(ctx context.Context, entityName string, code string, record reference_entity.Record) error {
+			saved[code] = record
+			return nil
+		},
+	}
+
+	checker := locales.NewChecker(func(ctx context.Context) (map[string]bool, error) {
+		return map[string]bool{"en_US": true}, nil
+	}, locales.Fail)
+
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithLocaleChecker(checker))
+	result, err := service.Sync(context.Background(), "brands", syncing.SyncOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.SuccessCount != 1 || result.ErrorCount != 1 {
+		t.Errorf("Expected 1 success and 1 error, got %d and %d", result.SuccessCount, result.ErrorCount)
+	}
+
+	if _, written := saved["globex"]; written {
+		t.Error("Expected the record with a disabled locale not to be sent")
+	}
+
+	if len(result.Errors) != 1 || result.Errors[0].Code != "globex" || !strings.Contains(result.Errors[0].Message, "ja_JP") {
+		t.Errorf("Expected globex to fail listing ja_JP, got %v", result.Errors)
+	}
+}
+
+func TestSync_FiltersRecordValues(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findAllFunc: func(ctx context.Context, entityName string) ([]reference_entity.Record, error) {
+			return []reference_entity.Record{
+				{"code": "acme", "values": map[string]interface{}{
+					"label": []interface{}{
+						map[string]interface{}{"locale": "en_US", "channel": nil, "data": "Acme"},
+						map[string]interface{}{"locale": "ja_JP", "channel": nil, "data": "アクメ"},
+					},
+					"tagline": []interface{}{
+						map[string]interface{}{"locale": "en_US", "channel": "ecommerce", "data": "Anything"},
+						map[string]interface{}{"locale": "en_US", "channel": "mobile", "data": "Anything, anywhere"},
+					},
+				}},
+			}, nil
+		},
+	}
+
+	saved := map[string]reference_entity.Record{}
+	destRepo := &MockDestRepository{
+		saveFunc: func(ctx context.Context, entityName string, code string, record reference_entity.Record) error {
+			saved[code] = record
+			return nil
+		},
+	}
+
+	valueFilter, err := filter.New(filter.Rules{Locales: []string{"en_US"}, Channels: []string{"ecommerce=web"}})
+	if err != nil {
+		t.Fatalf("Expected valid rules, got %v", err)
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithValueFilter(valueFilter))
+	if _, err := service.Sync(context.Background(), "brands", syncing.SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	values := saved["acme"]["values"].(map[string]interface{})
+	if labels := values["label"].([]interface{}); len(labels) != 1 || labels[0].(map[string]interface{})["locale"] != "en_US" {
+		t.Errorf("Expected only the en_US label to be sent, got %v", labels)
+	}
+	if taglines := values["tagline"].([]interface{}); len(taglines) != 1 || taglines[0].(map[string]interface{})["channel"] != "web" {
+		t.Errorf("Expected only the ecommerce tagline to be sent as web, got %v", taglines)
+	}
+}
+
+func TestSync_TransformsRecordValues(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findAllFunc: func(ctx context.Context, entityName string) ([]reference_entity.Record, error) {
+			return []reference_entity.Record{
+				{"code": "acme", "values": map[string]interface{}{
+					"website": []interface{}{map[string]interface{}{"locale": nil, "channel": "ecommerce", "data": "http://acme.com"}},
+				}},
+			}, nil
+		},
+	}
+
+	saved := map[string]reference_entity.Record{}
+	destRepo := &MockDestRepository{
+		saveFunc: func(ctx context.Context, entityName string, code string, record reference_entity.Record) error {
+			saved[code] = record
+			return nil
+		},
+	}
+
+	transformer, err := transform.New([]transform.Rule{{Attribute: "website", Expression: `replace(value, "http://", "https://")`, Scope: "ecommerce"}})
+	if err != nil {
+		t.Fatalf("Expected valid rules, got %v", err)
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithTransformer(transformer.ForRecords()))
+	if _, err := service.Sync(context.Background(), "brands", syncing.SyncOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	website := saved["acme"]["values"].(map[string]interface{})["website"].([]interface{})[0].(map[string]interface{})
+	if website["data"] != "https://acme.com" {
+		t.Errorf("Expected the website to be rewritten, got %v", website["data"])
+	}
+}
+
+func TestSyncRecords_OnlySelectedRecords(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findEntityFunc: func(ctx context.Context, entityCode string) (reference_entity.Entity, error) {
+			t.Error("Expected entity definition not to be fetched")
+			return nil, nil
+		},
+		findAllFunc: func(ctx context.Context, entityName string) ([]reference_entity.Record, error) {
+			return []reference_entity.Record{
+				{"code": "acme"},
+				{"code": "globex"},
+				{"code": "initech"},
+			}, nil
+		},
+	}
+
+	var saved []string
+	destRepo := &MockDestRepository{
+		saveFunc: func(ctx context.Context, entityName string, code string, record reference_entity.Record) error {
+			saved = append(saved, code)
+			return nil
+		},
+	}
+
+	service := syncing.NewService(sourceRepo, destRepo)
+	result, err := service.SyncRecords(context.Background(), "brands", []string{"globex", "missing"})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(saved) != 1 || saved[0] != "globex" {
+		t.Errorf("Expected only globex to be saved, got %v", saved)
+	}
+
+	if result.TotalRecords != 2 || result.SuccessCount != 1 || result.ErrorCount != 1 {
+		t.Errorf("Expected 2 total, 1 success and 1 error, got %d/%d/%d", result.TotalRecords, result.SuccessCount, result.ErrorCount)
+	}
+
+	failures := result.Failures()
+	if len(failures) != 1 || failures[0].Code != "missing" || failures[0].Scope != "brands" || failures[0].Kind != syncing.KindRecord {
+		t.Errorf("Expected missing record to be reported as failure, got %+v", failures)
+	}
+}
+
+func TestSync_WritesRecordsInOneBatch(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findAllFunc: func(ctx context.Context, entityName string) ([]reference_entity.Record, error) {
+			return []reference_entity.Record{{"code": "record1"}, {"code": "record2"}, {"code": "record3"}}, nil
+		},
+	}
+
+	batches := 0
+	destRepo := &MockDestRepository{
+		saveFunc: func(ctx context.Context, entityName string, code string, record reference_entity.Record) error {
+			t.Errorf("Expected records to be written in batch, got single save of %s", code)
+			return nil
+		},
+		saveAllFunc: func(ctx context.Context, entityName string, records []reference_entity.Record) (map[string]error, error) {
+			batches++
+			if len(records) != 3 {
+				t.Errorf("Expected 3 records in batch, got %d", len(records))
+			}
+			return map[string]error{"record3": errors.New("validation error in record record3")}, nil
+		},
+	}
+
+	result, err := syncing.NewService(sourceRepo, destRepo).Sync(context.Background(), "test_entity", syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if batches != 1 {
+		t.Errorf("Expected 1 batch, got %d", batches)
+	}
+
+	if result.SuccessCount != 2 || result.ErrorCount != 1 || result.Errors[0].Code != "record3" {
+		t.Errorf("Expected record3 to be reported as failed, got %+v", result)
+	}
+}
+
+func TestSync_BatchFailureFailsEveryRecord(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findAllFunc: func(ctx context.Context, entityName string) ([]reference_entity.Record, error) {
+			return []reference_entity.Record{{"code": "record1"}, {"code": "record2"}}, nil
+		},
+	}
+
+	destRepo := &MockDestRepository{
+		saveAllFunc: func(ctx context.Context, entityName string, records []reference_entity.Record) (map[string]error, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	result, err := syncing.NewService(sourceRepo, destRepo).Sync(context.Background(), "test_entity", syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.SuccessCount != 0 || result.ErrorCount != 2 {
+		t.Errorf("Expected both records to fail, got %+v", result)
+	}
+}
+
+func TestSync_WritesRecordsAsTheyAreStreamed(t *testing.T) {
+	records := make([]reference_entity.Record, syncing.RecordBatchSize+20)
+	for i := range records {
+		records[i] = reference_entity.Record{"code": fmt.Sprintf("record%d", i)}
+	}
+
+	sourceRepo := &MockSourceRepository{
+		findAllFunc: func(ctx context.Context, entityName string) ([]reference_entity.Record, error) {
+			return records, nil
+		},
+	}
+
+	var batches []int
+	destRepo := &MockDestRepository{
+		saveAllFunc: func(ctx context.Context, entityName string, records []reference_entity.Record) (map[string]error, error) {
+			batches = append(batches, len(records))
+			return nil, nil
+		},
+	}
+
+	result, err := syncing.NewService(sourceRepo, destRepo).Sync(context.Background(), "test_entity", syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(batches) != 2 || batches[0] != syncing.RecordBatchSize || batches[1] != 20 {
+		t.Errorf("Expected one write per streamed batch, got %v", batches)
+	}
+
+	if result.TotalRecords != len(records) || result.SuccessCount != len(records) {
+		t.Errorf("Expected %d records synced, got %+v", len(records), result)
+	}
+}
+
+func TestSync_WritesBatchesConcurrentlyWithWorkers(t *testing.T) {
+	records := make([]reference_entity.Record, 5*syncing.RecordBatchSize)
+	for i := range records {
+		records[i] = reference_entity.Record{"code": fmt.Sprintf("record%d", i)}
+	}
+
+	sourceRepo := &MockSourceRepository{
+		findAllFunc: func(ctx context.Context, entityName string) ([]reference_entity.Record, error) {
+			return records, nil
+		},
+	}
+
+	var mu sync.Mutex
+	batches := 0
+	destRepo := &MockDestRepository{
+		saveAllFunc: func(ctx context.Context, entityName string, records []reference_entity.Record) (map[string]error, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			batches++
+			code, _ := records[0]["code"].(string)
+			if code == "record200" {
+				return map[string]error{code: errors.New("validation error")}, nil
+			}
+			return nil, nil
+		},
+	}
+
+	result, err := syncing.NewService(sourceRepo, destRepo, syncing.WithWorkers(3)).Sync(context.Background(), "test_entity", syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if batches != 5 {
+		t.Errorf("Expected 5 batches written, got %d", batches)
+	}
+	if result.TotalRecords != len(records) || result.SuccessCount != len(records)-1 || result.ErrorCount != 1 || result.Errors[0].Code != "record200" {
+		t.Errorf("Expected the outcome of every batch to be merged, got %d/%d synced, errors %v", result.SuccessCount, result.TotalRecords, result.Errors)
+	}
+}
+
+func TestSync_OnlyRecordsInRange(t *testing.T) {
+	records := make([]reference_entity.Record, 3*syncing.RecordBatchSize)
+	for i := range records {
+		records[i] = reference_entity.Record{"code": fmt.Sprintf("record%d", i)}
+	}
+
+	sourceRepo := &MockSourceRepository{
+		findAllFunc: func(ctx context.Context, entityName string) ([]reference_entity.Record, error) {
+			return records, nil
+		},
+	}
+
+	var written []string
+	destRepo := &MockDestRepository{
+		saveAllFunc: func(ctx context.Context, entityName string, records []reference_entity.Record) (map[string]error, error) {
+			for _, record := range records {
+				written = append(written, record["code"].(string))
+			}
+			return nil, nil
+		},
+	}
+
+	opts := syncing.SyncOptions{Range: limit.Range{Offset: 150, Limit: 60}}
+	result, err := syncing.NewService(sourceRepo, destRepo).Sync(context.Background(), "test_entity", opts)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(written) != 60 || written[0] != "record150" || written[59] != "record209" {
+		t.Errorf("Expected records 150 to 209 written, got %d records", len(written))
+	}
+	if result.TotalRecords != 60 || result.SuccessCount != 60 {
+		t.Errorf("Expected 60 records synced, got %+v", result)
+	}
+}
+
+func TestSync_RejectsPruneWithRange(t *testing.T) {
+	opts := syncing.SyncOptions{Prune: true, Range: limit.Range{Limit: 50}}
+	_, err := syncing.NewService(&MockSourceRepository{}, &MockDestRepository{}).Sync(context.Background(), "test_entity", opts)
+	if err == nil {
+		t.Fatal("Expected a prune of a limited sync to be rejected")
+	}
+}
+
+func TestSync_CopiesMediaFilesOfRecords(t *testing.T) {
+	logo := func(code string) map[string]interface{} {
+		return map[string]interface{}{"logo": []interface{}{map[string]interface{}{"locale": nil, "channel": nil, "data": code}}}
+	}
+	sourceRepo := &MockSourceRepository{
+		findAttributesFunc: func(ctx context.Context, entityCode string) ([]reference_entity.Attribute, error) {
+			return []reference_entity.Attribute{{"code": "logo", "type": "image"}}, nil
+		},
+		findAllFunc: func(ctx context.Context, entityName string) ([]reference_entity.Record, error) {
+			return []reference_entity.Record{
+				{"code": "acme", "values": logo("a/b/1234_logo.png")},
+				{"code": "globex", "values": logo("a/b/1234_logo.png")},
+				{"code": "initech", "values": logo("missing.png")},
+			}, nil
+		},
+		downloadFunc: func(ctx context.Context, code string) (reference_entity.MediaFile, error) {
+			if code == "missing.png" {
+				return reference_entity.MediaFile{}, errors.New("not found")
+			}
+			return reference_entity.MediaFile{Code: code, Filename: "logo.png"}, nil
+		},
+	}
+
+	uploads := 0
+	var saved []reference_entity.Record
+	destRepo := &MockDestRepository{
+		uploadFunc: func(ctx context.Context, file reference_entity.MediaFile) (string, error) {
+			uploads++
+			return "c/d/5678_logo.png", nil
+		},
+		saveAllFunc: func(ctx context.Context, entityName string, records []reference_entity.Record) (map[string]error, error) {
+			saved = append(saved, records...)
+			return nil, nil
+		},
+	}
+
+	result, err := syncing.NewService(sourceRepo, destRepo).Sync(context.Background(), "brands", syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if uploads != 1 || result.MediaFiles != 1 {
+		t.Errorf("Expected the shared file to be uploaded once, got %d uploads", uploads)
+	}
+
+	if len(saved) != 2 {
+		t.Fatalf("Expected 2 records written, got %d", len(saved))
+	}
+	for _, record := range saved {
+		data := record["values"].(map[string]interface{})["logo"].([]interface{})[0].(map[string]interface{})["data"]
+		if data != "c/d/5678_logo.png" {
+			t.Errorf("Expected record %v to reference the destination file, got %v", record["code"], data)
+		}
+	}
+
+	if result.ErrorCount != 1 || result.Errors[0].Code != "initech" {
+		t.Errorf("Expected initech to be reported as failed, got %+v", result.Errors)
+	}
+}
+
+func TestSync_DryRunRecordsWrites(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findAttributesFunc: func(ctx context.Context, entityCode string) ([]reference_entity.Attribute, error) {
+			return []reference_entity.Attribute{{"code": "logo", "type": "image"}}, nil
+		},
+		findAllFunc: func(ctx context.Context, entityName string) ([]reference_entity.Record, error) {
+			return []reference_entity.Record{
+				{"code": "acme", "values": map[string]interface{}{"logo": []interface{}{map[string]interface{}{"data": "a/b/logo.png"}}}},
+			}, nil
+		},
+		downloadFunc: func(ctx context.Context, code string) (reference_entity.MediaFile, error) {
+			t.Error("Expected no media file to be downloaded during a dry run")
+			return reference_entity.MediaFile{}, nil
+		},
+	}
+
+	writes := 0
+	destRepo := &MockDestRepository{
+		saveEntityFunc: func(ctx context.Context, entityCode string, entity reference_entity.Entity) error {
+			writes++
+			return nil
+		},
+		saveAttributeFunc: func(ctx context.Context, entityCode, attributeCode string, attribute reference_entity.Attribute) error {
+			writes++
+			return nil
+		},
+		saveAllFunc: func(ctx context.Context, entityName string, records []reference_entity.Record) (map[string]error, error) {
+			writes++
+			return nil, nil
+		},
+	}
+
+	result, err := syncing.NewService(sourceRepo, destRepo).Sync(dryrun.With(context.Background()), "brands", syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if writes != 0 {
+		t.Errorf("Expected nothing written to destination, got %d writes", writes)
+	}
+
+	var planned []string
+	for _, write := range result.PlannedWrites() {
+		planned = append(planned, write.Kind+" "+write.Scope+"/"+write.Code)
+	}
+	expected := []string{
+		"reference_entity /brands",
+		"reference_entity_attribute brands/logo",
+		"media_file /a/b/logo.png",
+		"record brands/acme",
+	}
+	if strings.Join(planned, ", ") != strings.Join(expected, ", ") {
+		t.Errorf("Expected planned writes %v, got %v", expected, planned)
+	}
+	if result.SuccessCount != 1 {
+		t.Errorf("Expected the record to be counted as synced, got %+v", result)
+	}
+}
+
+func TestSync_PrunesDestinationRecordsOnceConfirmed(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findAllFunc: func(ctx context.Context, entityName string) ([]reference_entity.Record, error) {
+			return []reference_entity.Record{{"code": "acme"}, {"code": "globex"}}, nil
+		},
+	}
+
+	for _, confirmed := range []bool{true, false} {
+		destRepo := &MockDestRepository{codes: []string{"acme", "initech", "globex", "hooli"}}
+
+		var asked []string
+		service := syncing.NewService(sourceRepo, destRepo, syncing.WithPruneConfirmation(
+			func(ctx context.Context, kind, scope string, codes []string) bool {
+				asked = append(asked, kind+" "+scope+"/"+strings.Join(codes, ","))
+				return confirmed
+			},
+		))
+
+		result, err := service.Sync(context.Background(), "brands", syncing.SyncOptions{Prune: true})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if strings.Join(asked, "; ") != "record brands/hooli,initech" {
+			t.Errorf("Expected the destination-only records to be previewed, got %v", asked)
+		}
+		if strings.Join(result.PruneCandidates, ",") != "hooli,initech" {
+			t.Errorf("Expected the destination-only records as candidates, got %v", result.PruneCandidates)
+		}
+
+		if confirmed && (result.Pruned != 2 || strings.Join(destRepo.deleted, ",") != "hooli,initech") {
+			t.Errorf("Expected the confirmed prune to delete 2 records, got %d (%v)", result.Pruned, destRepo.deleted)
+		}
+		if !confirmed && (result.Pruned != 0 || len(destRepo.deleted) != 0) {
+			t.Errorf("Expected nothing deleted without confirmation, got %v", destRepo.deleted)
+		}
+	}
+}
+
+func TestSync_DryRunOnlyPlansPrune(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		findAllFunc: func(ctx context.Context, entityName string) ([]reference_entity.Record, error) {
+			return []reference_entity.Record{{"code": "acme"}}, nil
+		},
+	}
+	destRepo := &MockDestRepository{codes: []string{"acme", "initech"}}
+
+	service := syncing.NewService(sourceRepo, destRepo, syncing.WithPruneConfirmation(
+		func(ctx context.Context, kind, scope string, codes []string) bool {
+			t.Error("Expected no confirmation to be asked during a dry run")
+			return true
+		},
+	))
+
+	result, err := service.Sync(dryrun.With(context.Background()), "brands", syncing.SyncOptions{Prune: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(destRepo.deleted) != 0 {
+		t.Errorf("Expected nothing deleted during a dry run, got %v", destRepo.deleted)
+	}
+
+	planned := result.PlannedWrites()
+	last := planned[len(planned)-1]
+	if last.Kind != syncing.KindRecordDeletion || last.Scope != "brands" || last.Code != "initech" {
+		t.Errorf("Expected the deletion of initech to be planned, got %+v", last)
+	}
+}
@@ -0,0 +1,26 @@
+package syncing_record
+
+import (
+	"akeneo-migrator/internal/reference_entity/syncing"
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/retry"
+)
+
+const SyncRecordCommandType bus.Type = "reference_entity.sync_record"
+
+// SyncRecordCommand represents a command to sync a single record of a reference entity
+type SyncRecordCommand struct {
+	EntityName string
+	Code       string
+	Debug      bool
+}
+
+// Type returns the command type
+func (c SyncRecordCommand) Type() bus.Type {
+	return SyncRecordCommandType
+}
+
+// RetryItem returns the item targeted by the command
+func (c SyncRecordCommand) RetryItem() retry.Failure {
+	return retry.Failure{Kind: syncing.KindRecord, Scope: c.EntityName, Code: c.Code}
+}
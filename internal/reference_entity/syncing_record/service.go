@@ -0,0 +1,113 @@
+package syncing_record
+
+import (
+	"context"
+	"fmt"
+
+	"akeneo-migrator/internal/reference_entity"
+	"akeneo-migrator/internal/reference_entity/syncing"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/retry"
+)
+
+// Service handles the synchronization of a single Reference Entity record
+type Service struct {
+	sourceRepo     reference_entity.SourceRepository
+	destRepo       reference_entity.DestRepository
+	syncingService *syncing.Service
+}
+
+// NewService creates a new instance of the record sync service
+// Options are passed to the composed Reference Entity sync service
+func NewService(sourceRepo reference_entity.SourceRepository, destRepo reference_entity.DestRepository, opts ...syncing.Option) *Service {
+	return &Service{
+		sourceRepo:     sourceRepo,
+		destRepo:       destRepo,
+		syncingService: syncing.NewService(sourceRepo, destRepo, opts...),
+	}
+}
+
+// SyncResult contains the result of syncing a record
+type SyncResult struct {
+	EntityName string
+	Code       string
+	Created    bool
+	MediaFiles int
+	Success    bool
+	Error      string
+	// Planned are the writes recorded instead of being sent during a dry run
+	Planned []dryrun.Write
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// Failures returns the record when it could not be synchronized
+func (r *SyncResult) Failures() []retry.Failure {
+	if r.Success {
+		return nil
+	}
+	return []retry.Failure{{Kind: syncing.KindRecord, Scope: r.EntityName, Code: r.Code, Error: r.Error}}
+}
+
+// Synced returns the number of records written
+func (r *SyncResult) Synced() int {
+	if r.Success {
+		return 1
+	}
+	return 0
+}
+
+// Sync synchronizes one record and the media files of its image values.
+// The Reference Entity and its attributes must already exist in destination.
+func (s *Service) Sync(ctx context.Context, entityName, code string) (*SyncResult, error) {
+	result := &SyncResult{
+		EntityName: entityName,
+		Code:       code,
+	}
+	ctx, planned := dryrun.Collect(ctx)
+
+	// 1. Get the record from source
+	record, err := s.sourceRepo.FindRecord(ctx, entityName, code)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching record from source: %w", err)
+	}
+
+	// 2. Get the record from destination to merge its label
+	destRecord, destErr := s.destRepo.FindRecord(ctx, entityName, code)
+	exists := destErr == nil
+	result.Created = !exists
+
+	record, err = s.syncingService.PrepareRecord(ctx, record, destRecord, exists)
+	if err != nil {
+		result.Error = err.Error()
+		return result, err
+	}
+
+	// 3. Copy the media files referenced by the record
+	mediaAttributes, err := s.syncingService.FindMediaAttributes(ctx, entityName)
+	if err != nil {
+		result.Error = err.Error()
+		return result, err
+	}
+
+	record, result.MediaFiles, err = s.syncingService.CopyMediaFiles(ctx, record, mediaAttributes)
+	if err != nil {
+		result.Error = err.Error()
+		return result, err
+	}
+
+	// 4. Save the record to destination
+	if !dryrun.Record(ctx, dryrun.Write{Kind: syncing.KindRecord, Scope: entityName, Code: code, Data: record}) {
+		if err := s.destRepo.Save(ctx, entityName, code, record); err != nil {
+			result.Error = err.Error()
+			return result, fmt.Errorf("error saving record to destination: %w", err)
+		}
+	}
+
+	result.Planned = planned()
+	result.Success = true
+	return result, nil
+}
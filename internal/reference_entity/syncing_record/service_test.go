@@ -0,0 +1,188 @@
+package syncing_record_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"akeneo-migrator/internal/reference_entity"
+	"akeneo-migrator/internal/reference_entity/syncing_record"
+)
+
+// MockSourceRepository is a mock of the source repository for testing
+type MockSourceRepository struct {
+	records    map[string]reference_entity.Record
+	attributes []reference_entity.Attribute
+	downloads  []string
+}
+
+func (m *MockSourceRepository) FindEntity(ctx context.Context, entityCode string) (reference_entity.Entity, error) {
+	return reference_entity.Entity{"code": entityCode}, nil
+}
+
+func (m *MockSourceRepository) FindAttributes(ctx context.Context, entityCode string) ([]reference_entity.Attribute, error) {
+	return m.attributes, nil
+}
+
+func (m *MockSourceRepository) FindAll(ctx context.Context, entityName string) ([]reference_entity.Record, error) {
+	return nil, errors.New("unexpected FindAll")
+}
+
+func (m *MockSourceRepository) StreamRecords(ctx context.Context, entityName string, batchSize int, callback func([]reference_entity.Record) error) error {
+	return errors.New("unexpected StreamRecords")
+}
+
+func (m *MockSourceRepository) FindRecord(ctx context.Context, entityName string, code string) (reference_entity.Record, error) {
+	record, ok := m.records[code]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return record, nil
+}
+
+func (m *MockSourceRepository) DownloadMediaFile(ctx context.Context, code string) (reference_entity.MediaFile, error) {
+	m.downloads = append(m.downloads, code)
+	return reference_entity.MediaFile{Code: code, Filename: "logo.png", Content: []byte("png")}, nil
+}
+
+// MockDestRepository is a mock of the destination repository for testing
+type MockDestRepository struct {
+	records map[string]reference_entity.Record
+	saved   map[string]reference_entity.Record
+	saveErr error
+	uploads int
+}
+
+func (m *MockDestRepository) FindEntity(ctx context.Context, entityCode string) (reference_entity.Entity, error) {
+	return reference_entity.Entity{"code": entityCode}, nil
+}
+
+func (m *MockDestRepository) SaveEntity(ctx context.Context, entityCode string, entity reference_entity.Entity) error {
+	return errors.New("unexpected SaveEntity")
+}
+
+func (m *MockDestRepository) FindAttributes(ctx context.Context, entityCode string) ([]reference_entity.Attribute, error) {
+	return nil, nil
+}
+
+func (m *MockDestRepository) SaveAttribute(ctx context.Context, entityCode string, attributeCode string, attribute reference_entity.Attribute) error {
+	return errors.New("unexpected SaveAttribute")
+}
+
+func (m *MockDestRepository) FindAll(ctx context.Context, entityName string) ([]reference_entity.Record, error) {
+	return nil, errors.New("unexpected FindAll")
+}
+
+func (m *MockDestRepository) FindRecord(ctx context.Context, entityName string, code string) (reference_entity.Record, error) {
+	record, ok := m.records[code]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return record, nil
+}
+
+func (m *MockDestRepository) Save(ctx context.Context, entityName string, code string, record reference_entity.Record) error {
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	m.saved[code] = record
+	return nil
+}
+
+func (m *MockDestRepository) SaveAll(ctx context.Context, entityName string, records []reference_entity.Record) (map[string]error, error) {
+	return nil, errors.New("unexpected batch save")
+}
+
+func (m *MockDestRepository) FindCodes(ctx context.Context, entityName string) ([]string, error) {
+	return nil, errors.New("unexpected FindCodes")
+}
+
+func (m *MockDestRepository) Delete(ctx context.Context, entityName string, code string) error {
+	return errors.New("unexpected Delete")
+}
+
+func (m *MockDestRepository) UploadMediaFile(ctx context.Context, file reference_entity.MediaFile) (string, error) {
+	m.uploads++
+	return "dest/" + file.Filename, nil
+}
+
+func TestSync_CopiesRecordWithMedia(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		attributes: []reference_entity.Attribute{
+			{"code": "label", "type": "text"},
+			{"code": "logo", "type": "image"},
+		},
+		records: map[string]reference_entity.Record{
+			"acme": {"code": "acme", "values": map[string]interface{}{
+				"label": []interface{}{map[string]interface{}{"locale": "en_US", "channel": nil, "data": "Acme"}},
+				"logo": []interface{}{
+					map[string]interface{}{"locale": "en_US", "channel": nil, "data": "a/b/abc_logo.png"},
+					map[string]interface{}{"locale": "fr_FR", "channel": nil, "data": "a/b/abc_logo.png"},
+				},
+			}},
+		},
+	}
+	destRepo := &MockDestRepository{saved: map[string]reference_entity.Record{}}
+
+	service := syncing_record.NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background(), "brands", "acme")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !result.Success || !result.Created {
+		t.Errorf("Expected record to be created, got %+v", result)
+	}
+
+	if result.MediaFiles != 1 || destRepo.uploads != 1 || len(sourceRepo.downloads) != 1 {
+		t.Errorf("Expected shared media file to be copied once, got %d", destRepo.uploads)
+	}
+
+	logo := destRepo.saved["acme"]["values"].(map[string]interface{})["logo"].([]interface{})
+	for _, entry := range logo {
+		if data := entry.(map[string]interface{})["data"]; data != "dest/logo.png" {
+			t.Errorf("Expected value to reference destination media file, got %v", data)
+		}
+	}
+
+	sourceLogo := sourceRepo.records["acme"]["values"].(map[string]interface{})["logo"].([]interface{})
+	if data := sourceLogo[0].(map[string]interface{})["data"]; data != "a/b/abc_logo.png" {
+		t.Errorf("Expected source record not to be modified, got %v", data)
+	}
+}
+
+func TestSync_SaveError(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		records: map[string]reference_entity.Record{"acme": {"code": "acme"}},
+	}
+	destRepo := &MockDestRepository{
+		records: map[string]reference_entity.Record{"acme": {"code": "acme"}},
+		saved:   map[string]reference_entity.Record{},
+		saveErr: errors.New("attribute 'country' does not exist"),
+	}
+
+	service := syncing_record.NewService(sourceRepo, destRepo)
+	result, err := service.Sync(context.Background(), "brands", "acme")
+
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if result.Success || result.Created {
+		t.Errorf("Expected failed update of existing record, got %+v", result)
+	}
+
+	failures := result.Failures()
+	if len(failures) != 1 || failures[0].Scope != "brands" || failures[0].Code != "acme" {
+		t.Errorf("Expected record to be reported as failure, got %+v", failures)
+	}
+}
+
+func TestSync_RecordNotFound(t *testing.T) {
+	service := syncing_record.NewService(&MockSourceRepository{}, &MockDestRepository{})
+
+	if _, err := service.Sync(context.Background(), "brands", "missing"); err == nil {
+		t.Error("Expected error for missing record")
+	}
+}
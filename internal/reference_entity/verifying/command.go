@@ -0,0 +1,16 @@
+package verifying
+
+import "akeneo-migrator/kit/bus"
+
+const VerifyReferenceEntityCommandType bus.Type = "reference_entity.verify"
+
+// VerifyReferenceEntityCommand represents a command to verify a reference entity against the destination
+type VerifyReferenceEntityCommand struct {
+	EntityName string
+	Debug      bool
+}
+
+// Type returns the command type
+func (c VerifyReferenceEntityCommand) Type() bus.Type {
+	return VerifyReferenceEntityCommandType
+}
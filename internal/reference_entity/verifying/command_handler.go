@@ -0,0 +1,34 @@
+package verifying
+
+import (
+	"context"
+
+	"akeneo-migrator/kit/bus"
+)
+
+// CommandHandler handles VerifyReferenceEntityCommand
+type CommandHandler struct {
+	service *Service
+}
+
+// NewCommandHandler creates a new command handler
+func NewCommandHandler(service *Service) *CommandHandler {
+	return &CommandHandler{
+		service: service,
+	}
+}
+
+// Handle executes the verify command
+func (h *CommandHandler) Handle(ctx context.Context, msg bus.Message) (bus.Response, error) {
+	cmd, ok := msg.(VerifyReferenceEntityCommand)
+	if !ok {
+		return bus.Response{}, nil
+	}
+
+	report, err := h.service.Verify(ctx, cmd.EntityName)
+	if err != nil {
+		return bus.Response{Error: err}, err
+	}
+
+	return bus.Response{Data: report}, nil
+}
@@ -0,0 +1,132 @@
+package verifying
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"akeneo-migrator/internal/reference_entity"
+	"akeneo-migrator/kit/checksum"
+)
+
+// Service compares a Reference Entity between source and destination without writing
+type Service struct {
+	sourceRepo reference_entity.SourceRepository
+	destRepo   reference_entity.DestRepository
+}
+
+// NewService creates a new instance of the verify service
+func NewService(sourceRepo reference_entity.SourceRepository, destRepo reference_entity.DestRepository) *Service {
+	return &Service{
+		sourceRepo: sourceRepo,
+		destRepo:   destRepo,
+	}
+}
+
+// Verify compares the definition, attributes and records of a Reference Entity using normalized checksums
+func (s *Service) Verify(ctx context.Context, entityName string) (*checksum.Report, error) {
+	report := checksum.NewReport("reference_entity", entityName)
+
+	// 1. Compare the definition
+	sourceEntity, err := s.sourceRepo.FindEntity(ctx, entityName)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching reference entity definition from source: %w", err)
+	}
+
+	destEntity, err := s.destRepo.FindEntity(ctx, entityName)
+	if errors.Is(err, reference_entity.ErrNotFound) {
+		report.Missing("reference_entity", entityName, err.Error())
+		return report, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching reference entity definition from destination: %w", err)
+	}
+
+	if err := report.Compare("reference_entity", entityName, sourceEntity, destEntity); err != nil {
+		return nil, err
+	}
+
+	// 2. Compare attributes
+	sourceAttributes, err := s.sourceRepo.FindAttributes(ctx, entityName)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching attributes from source: %w", err)
+	}
+
+	destAttributes, err := s.destRepo.FindAttributes(ctx, entityName)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching attributes from destination: %w", err)
+	}
+
+	destAttributesByCode := make(map[string]map[string]interface{}, len(destAttributes))
+	for _, attribute := range destAttributes {
+		if code, ok := attribute["code"].(string); ok {
+			destAttributesByCode[code] = attribute
+		}
+	}
+
+	sourceAttributeItems := make([]map[string]interface{}, len(sourceAttributes))
+	for i, attribute := range sourceAttributes {
+		sourceAttributeItems[i] = attribute
+	}
+
+	if err := compareByCode(report, "reference_entity_attribute", sourceAttributeItems, destAttributesByCode); err != nil {
+		return nil, err
+	}
+
+	// 3. Compare records
+	sourceRecords, err := s.sourceRepo.FindAll(ctx, entityName)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching records from source: %w", err)
+	}
+
+	destRecords, err := s.destRepo.FindAll(ctx, entityName)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching records from destination: %w", err)
+	}
+
+	destRecordsByCode := make(map[string]map[string]interface{}, len(destRecords))
+	for _, record := range destRecords {
+		if code, ok := record["code"].(string); ok {
+			destRecordsByCode[code] = record
+		}
+	}
+
+	sourceRecordItems := make([]map[string]interface{}, len(sourceRecords))
+	for i, record := range sourceRecords {
+		sourceRecordItems[i] = record
+	}
+
+	if err := compareByCode(report, "record", sourceRecordItems, destRecordsByCode); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// compareByCode compares source items with destination items indexed by code.
+// Destination items left over after the comparison are reported as extra.
+func compareByCode(report *checksum.Report, kind string, sourceItems []map[string]interface{}, destByCode map[string]map[string]interface{}) error {
+	for _, item := range sourceItems {
+		code, ok := item["code"].(string)
+		if !ok {
+			continue
+		}
+
+		destItem, exists := destByCode[code]
+		delete(destByCode, code)
+		if !exists {
+			report.Missing(kind, code, "")
+			continue
+		}
+
+		if err := report.Compare(kind, code, item, destItem); err != nil {
+			return err
+		}
+	}
+
+	for code := range destByCode {
+		report.Extra(kind, code)
+	}
+
+	return nil
+}
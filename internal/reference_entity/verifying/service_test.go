@@ -0,0 +1,139 @@
+package verifying_test
+
+import (
+	"context"
+	"testing"
+
+	"akeneo-migrator/internal/reference_entity"
+	"akeneo-migrator/internal/reference_entity/verifying"
+	"akeneo-migrator/kit/checksum"
+)
+
+// MockSourceRepository is a mock of the source repository for testing
+type MockSourceRepository struct {
+	entity     reference_entity.Entity
+	attributes []reference_entity.Attribute
+	records    []reference_entity.Record
+}
+
+func (m *MockSourceRepository) FindEntity(ctx context.Context, entityCode string) (reference_entity.Entity, error) {
+	return m.entity, nil
+}
+
+func (m *MockSourceRepository) FindAttributes(ctx context.Context, entityCode string) ([]reference_entity.Attribute, error) {
+	return m.attributes, nil
+}
+
+func (m *MockSourceRepository) FindAll(ctx context.Context, entityName string) ([]reference_entity.Record, error) {
+	return m.records, nil
+}
+
+func (m *MockSourceRepository) StreamRecords(ctx context.Context, entityName string, batchSize int, callback func([]reference_entity.Record) error) error {
+	return callback(m.records)
+}
+
+func (m *MockSourceRepository) FindRecord(ctx context.Context, entityName string, code string) (reference_entity.Record, error) {
+	for _, record := range m.records {
+		if record["code"] == code {
+			return record, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockSourceRepository) DownloadMediaFile(ctx context.Context, code string) (reference_entity.MediaFile, error) {
+	return reference_entity.MediaFile{Code: code}, nil
+}
+
+// MockDestRepository is a mock of the destination repository for testing
+type MockDestRepository struct {
+	MockSourceRepository
+	saveCalls int
+}
+
+func (m *MockDestRepository) SaveEntity(ctx context.Context, entityCode string, entity reference_entity.Entity) error {
+	m.saveCalls++
+	return nil
+}
+
+func (m *MockDestRepository) SaveAttribute(ctx context.Context, entityCode string, attributeCode string, attribute reference_entity.Attribute) error {
+	m.saveCalls++
+	return nil
+}
+
+func (m *MockDestRepository) Save(ctx context.Context, entityName string, code string, record reference_entity.Record) error {
+	m.saveCalls++
+	return nil
+}
+
+func (m *MockDestRepository) SaveAll(ctx context.Context, entityName string, records []reference_entity.Record) (map[string]error, error) {
+	m.saveCalls++
+	return nil, nil
+}
+
+func (m *MockDestRepository) FindCodes(ctx context.Context, entityName string) ([]string, error) {
+	return nil, nil
+}
+
+func (m *MockDestRepository) Delete(ctx context.Context, entityName string, code string) error {
+	m.saveCalls++
+	return nil
+}
+
+func (m *MockDestRepository) UploadMediaFile(ctx context.Context, file reference_entity.MediaFile) (string, error) {
+	m.saveCalls++
+	return file.Code, nil
+}
+
+func TestVerify_ReportsMismatchesAndMissingRecords(t *testing.T) {
+	sourceRepo := &MockSourceRepository{
+		entity:     reference_entity.Entity{"code": "brands", "labels": map[string]interface{}{"en_US": "Brands"}},
+		attributes: []reference_entity.Attribute{{"code": "name", "type": "text"}},
+		records: []reference_entity.Record{
+			{"code": "acme", "values": map[string]interface{}{"name": []interface{}{map[string]interface{}{"locale": nil, "channel": nil, "data": "Acme"}}}},
+			{"code": "globex"},
+		},
+	}
+
+	destRepo := &MockDestRepository{
+		MockSourceRepository: MockSourceRepository{
+			entity:     reference_entity.Entity{"code": "brands", "labels": map[string]interface{}{"en_US": "Brands"}, "_links": map[string]interface{}{}},
+			attributes: []reference_entity.Attribute{{"code": "name", "type": "text"}},
+			records: []reference_entity.Record{
+				{"code": "acme", "values": map[string]interface{}{"name": []interface{}{map[string]interface{}{"locale": nil, "channel": nil, "data": "ACME Corp"}}}},
+				{"code": "initech"},
+			},
+		},
+	}
+
+	service := verifying.NewService(sourceRepo, destRepo)
+	report, err := service.Verify(context.Background(), "brands")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if report.Compared != 4 {
+		t.Errorf("Expected 4 compared items, got %d", report.Compared)
+	}
+
+	if report.Matches != 2 {
+		t.Errorf("Expected 2 matches, got %d", report.Matches)
+	}
+
+	if report.Count(checksum.StatusMismatch) != 1 {
+		t.Errorf("Expected 1 mismatch, got %d", report.Count(checksum.StatusMismatch))
+	}
+
+	if report.Count(checksum.StatusMissing) != 1 {
+		t.Errorf("Expected 1 missing record, got %d", report.Count(checksum.StatusMissing))
+	}
+
+	if report.Count(checksum.StatusExtra) != 1 {
+		t.Errorf("Expected 1 extra record, got %d", report.Count(checksum.StatusExtra))
+	}
+
+	if destRepo.saveCalls != 0 {
+		t.Errorf("Expected no writes to destination, got %d", destRepo.saveCalls)
+	}
+}
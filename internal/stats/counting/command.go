@@ -0,0 +1,18 @@
+package counting
+
+import (
+	"akeneo-migrator/internal/stats"
+	"akeneo-migrator/kit/bus"
+)
+
+const CountCommandType bus.Type = "stats.count"
+
+// CountCommand represents a command to count the objects of both instances
+type CountCommand struct {
+	Kinds []stats.Kind
+}
+
+// Type returns the command type
+func (c CountCommand) Type() bus.Type {
+	return CountCommandType
+}
@@ -0,0 +1,84 @@
+package counting
+
+import (
+	"context"
+
+	"akeneo-migrator/internal/stats"
+)
+
+// Service counts the objects of source and destination side by side
+type Service struct {
+	sourceRepo stats.Repository
+	destRepo   stats.Repository
+}
+
+// NewService creates a new instance of the count service
+func NewService(sourceRepo, destRepo stats.Repository) *Service {
+	return &Service{
+		sourceRepo: sourceRepo,
+		destRepo:   destRepo,
+	}
+}
+
+// Count is the number of objects of a kind on both instances. A count that failed is reported with
+// its error instead of stopping the others.
+type Count struct {
+	Kind        stats.Kind
+	Source      int
+	Dest        int
+	SourceError string
+	DestError   string
+}
+
+// Counted tells whether the kind was counted on both instances
+func (c Count) Counted() bool {
+	return c.SourceError == "" && c.DestError == ""
+}
+
+// Delta returns the number of objects destination has more than source; it is negative when
+// objects are missing in destination
+func (c Count) Delta() int {
+	return c.Dest - c.Source
+}
+
+// CountResult contains the counts of every kind, in the requested order
+type CountResult struct {
+	Counts []Count
+}
+
+// Matches tells whether every kind was counted and has as many objects on both instances
+func (r *CountResult) Matches() bool {
+	for _, count := range r.Counts {
+		if !count.Counted() || count.Delta() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Count counts the objects of each kind on both instances
+func (s *Service) Count(ctx context.Context, kinds []stats.Kind) (*CountResult, error) {
+	result := &CountResult{Counts: make([]Count, 0, len(kinds))}
+
+	for _, kind := range kinds {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		count := Count{Kind: kind}
+		if source, err := s.sourceRepo.Count(ctx, kind); err != nil {
+			count.SourceError = err.Error()
+		} else {
+			count.Source = source
+		}
+		if dest, err := s.destRepo.Count(ctx, kind); err != nil {
+			count.DestError = err.Error()
+		} else {
+			count.Dest = dest
+		}
+
+		result.Counts = append(result.Counts, count)
+	}
+
+	return result, nil
+}
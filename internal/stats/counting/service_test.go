@@ -0,0 +1,49 @@
+package counting
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"akeneo-migrator/internal/stats"
+)
+
+// mockRepository serves fixed counts, failing for the kinds without one
+type mockRepository struct {
+	counts map[stats.Kind]int
+}
+
+func (m *mockRepository) Count(ctx context.Context, kind stats.Kind) (int, error) {
+	count, ok := m.counts[kind]
+	if !ok {
+		return 0, errors.New("forbidden")
+	}
+	return count, nil
+}
+
+func TestCount_ComparesBothInstances(t *testing.T) {
+	sourceRepo := &mockRepository{counts: map[stats.Kind]int{stats.KindProducts: 120, stats.KindFamilies: 8, stats.KindCategories: 40}}
+	destRepo := &mockRepository{counts: map[stats.Kind]int{stats.KindProducts: 118, stats.KindFamilies: 8}}
+
+	result, err := NewService(sourceRepo, destRepo).Count(context.Background(), []stats.Kind{stats.KindProducts, stats.KindFamilies, stats.KindCategories})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.Counts) != 3 {
+		t.Fatalf("Expected 3 counts, got %v", result.Counts)
+	}
+	products, families, categories := result.Counts[0], result.Counts[1], result.Counts[2]
+	if products.Kind != stats.KindProducts || products.Delta() != -2 {
+		t.Errorf("Expected 2 products missing in destination, got %+v", products)
+	}
+	if !families.Counted() || families.Delta() != 0 {
+		t.Errorf("Expected families to match, got %+v", families)
+	}
+	if categories.Counted() || categories.Source != 40 || categories.DestError != "forbidden" {
+		t.Errorf("Expected the destination categories count to fail, got %+v", categories)
+	}
+	if result.Matches() {
+		t.Error("Expected the counts not to match")
+	}
+}
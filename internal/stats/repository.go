@@ -0,0 +1,31 @@
+package stats
+
+import "context"
+
+// Kind is a kind of object counted on an instance
+type Kind string
+
+const (
+	KindProducts         Kind = "products"
+	KindProductModels    Kind = "product models"
+	KindFamilies         Kind = "families"
+	KindAttributes       Kind = "attributes"
+	KindCategories       Kind = "categories"
+	KindReferenceRecords Kind = "reference entity records"
+)
+
+// Kinds are the kinds of objects counted by default, in display order
+var Kinds = []Kind{
+	KindProducts,
+	KindProductModels,
+	KindFamilies,
+	KindAttributes,
+	KindCategories,
+	KindReferenceRecords,
+}
+
+// Repository counts the objects of an Akeneo instance
+type Repository interface {
+	// Count returns the number of objects of a kind
+	Count(ctx context.Context, kind Kind) (int, error)
+}
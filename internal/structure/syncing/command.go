@@ -0,0 +1,19 @@
+package syncing
+
+import "akeneo-migrator/kit/bus"
+
+const SyncStructureCommandType bus.Type = "structure.sync"
+
+// SyncStructureCommand represents a command to migrate the whole catalog structure
+type SyncStructureCommand struct {
+	// AutoDeps lets channels activate the destination locales they use
+	AutoDeps bool
+	// Progress is called when each step starts; it may be nil
+	Progress ProgressFunc
+	Debug    bool
+}
+
+// Type returns the command type
+func (c SyncStructureCommand) Type() bus.Type {
+	return SyncStructureCommandType
+}
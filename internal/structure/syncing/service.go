@@ -0,0 +1,200 @@
+package syncing
+
+import (
+	"context"
+	"fmt"
+
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/retry"
+	"akeneo-migrator/kit/session"
+)
+
+// Step is a stage of the structure migration, synchronizing one kind of item
+type Step string
+
+const (
+	StepMeasurementFamilies Step = "measurement families"
+	StepReferenceEntities   Step = "reference entities"
+	StepAttributes          Step = "attributes"
+	StepCategoryTrees       Step = "category trees"
+	StepChannels            Step = "channels"
+	StepFamilies            Step = "families"
+	StepAssociationTypes    Step = "association types"
+)
+
+// Order is the order in which the steps run, each one only depending on the steps before it:
+// metric attributes need their measurement family and reference entity attributes their entity,
+// channels need their category tree, and family requirements need the attributes and channels.
+var Order = []Step{
+	StepMeasurementFamilies,
+	StepReferenceEntities,
+	StepAttributes,
+	StepCategoryTrees,
+	StepChannels,
+	StepFamilies,
+	StepAssociationTypes,
+}
+
+// Builder creates the commands of a step, listing the items to synchronize in source when needed
+type Builder func(ctx context.Context, opts SyncOptions) ([]bus.Message, error)
+
+// ProgressFunc is called when a step starts, with the number of commands it dispatches
+type ProgressFunc func(step Step, commands int)
+
+// Service migrates the catalog structure by dispatching the sync commands of each step in order
+type Service struct {
+	dispatcher bus.Bus
+	builders   map[Step]Builder
+}
+
+// Option configures the structure service
+type Option func(*Service)
+
+// WithStep registers how the commands of a step are created. Steps without a builder are skipped,
+// e.g. reference entities on editions that do not support them.
+func WithStep(step Step, builder Builder) Option {
+	return func(s *Service) {
+		s.builders[step] = builder
+	}
+}
+
+// NewService creates a new instance of the structure service
+func NewService(dispatcher bus.Bus, opts ...Option) *Service {
+	service := &Service{
+		dispatcher: dispatcher,
+		builders:   make(map[Step]Builder),
+	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
+
+// SyncOptions contains the options of a structure migration
+type SyncOptions struct {
+	// AutoDeps lets channels activate the destination locales they use
+	AutoDeps bool
+	// Progress is called when each step starts; it may be nil
+	Progress ProgressFunc
+	Debug    bool
+}
+
+// StepResult contains the outcome of one step
+type StepResult struct {
+	Step     Step
+	Skipped  bool
+	Commands int
+	Synced   int
+	Failed   int
+	// Error is set when the step could not run as a whole, which stops the migration
+	Error string
+}
+
+// SyncResult contains the result of a structure migration
+type SyncResult struct {
+	Steps []StepResult
+	// Stopped is the step that failed as a whole; the steps after it did not run
+	Stopped  Step
+	failures []retry.Failure
+	// Planned are the writes recorded instead of being sent during a dry run
+	Planned []dryrun.Write
+}
+
+// Synced returns the number of items written by every step
+func (r *SyncResult) Synced() int {
+	synced := 0
+	for _, step := range r.Steps {
+		synced += step.Synced
+	}
+	return synced
+}
+
+// Failures returns the items that could not be synchronized, whatever their step
+func (r *SyncResult) Failures() []retry.Failure {
+	return r.failures
+}
+
+// PlannedWrites returns the writes recorded during a dry run
+func (r *SyncResult) PlannedWrites() []dryrun.Write {
+	return r.Planned
+}
+
+// Err returns the error of the step that stopped the migration, or nil when every step ran
+func (r *SyncResult) Err() error {
+	for _, step := range r.Steps {
+		if step.Error != "" {
+			return fmt.Errorf("%s: %s", step.Step, step.Error)
+		}
+	}
+	return nil
+}
+
+// Sync runs every step in order. Items failing in a step are collected and the migration goes on,
+// but a step failing as a whole stops it, since the steps after it depend on its items.
+func (s *Service) Sync(ctx context.Context, opts SyncOptions) (*SyncResult, error) {
+	result := &SyncResult{}
+	ctx, planned := dryrun.Collect(ctx)
+
+	// Failures are collected by the migration itself instead of being queued by each command
+	stepCtx := retry.WithoutRecording(ctx)
+
+	for _, step := range Order {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		stepResult, stop := s.runStep(stepCtx, step, opts, result)
+		result.Steps = append(result.Steps, stepResult)
+		if stop {
+			result.Stopped = step
+			break
+		}
+	}
+
+	result.Planned = planned()
+	return result, nil
+}
+
+// runStep dispatches the commands of a step and reports whether the migration must stop
+func (s *Service) runStep(ctx context.Context, step Step, opts SyncOptions, result *SyncResult) (StepResult, bool) {
+	stepResult := StepResult{Step: step}
+
+	builder, ok := s.builders[step]
+	if !ok {
+		stepResult.Skipped = true
+		return stepResult, false
+	}
+
+	messages, err := builder(ctx, opts)
+	if err != nil {
+		stepResult.Error = fmt.Sprintf("error listing %s: %v", step, err)
+		return stepResult, true
+	}
+
+	stepResult.Commands = len(messages)
+	if opts.Progress != nil {
+		opts.Progress(step, len(messages))
+	}
+
+	for _, msg := range messages {
+		response, dispatchErr := s.dispatcher.Dispatch(ctx, msg)
+		if counter, ok := response.Data.(session.Counter); ok {
+			stepResult.Synced += counter.Synced()
+		}
+
+		failures := retry.Collect(msg, response, dispatchErr)
+		stepResult.Failed += len(failures)
+		result.failures = append(result.failures, failures...)
+
+		// A command that does not target a single item failed as a whole
+		if dispatchErr != nil && len(failures) == 0 {
+			stepResult.Error = dispatchErr.Error()
+			return stepResult, true
+		}
+	}
+
+	return stepResult, false
+}
@@ -0,0 +1,164 @@
+package syncing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"akeneo-migrator/internal/structure/syncing"
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/retry"
+)
+
+// syncCommand is a fake command syncing one kind of item
+type syncCommand struct {
+	Kind string
+	Code string
+}
+
+func (c syncCommand) Type() bus.Type {
+	return bus.Type(c.Kind + ".sync")
+}
+
+// syncResult reports the items written and the ones that failed
+type syncResult struct {
+	synced   int
+	failures []retry.Failure
+}
+
+func (r syncResult) Synced() int {
+	return r.synced
+}
+
+func (r syncResult) Failures() []retry.Failure {
+	return r.failures
+}
+
+// MockBus dispatches messages to a single function
+type MockBus struct {
+	dispatchFunc func(ctx context.Context, msg bus.Message) (bus.Response, error)
+}
+
+func (m *MockBus) Dispatch(ctx context.Context, msg bus.Message) (bus.Response, error) {
+	return m.dispatchFunc(ctx, msg)
+}
+
+func (m *MockBus) Register(msgType bus.Type, handler bus.Handler) {}
+
+// commands builds a step dispatching one command per code
+func commands(kind string, codes ...string) syncing.Builder {
+	return func(ctx context.Context, opts syncing.SyncOptions) ([]bus.Message, error) {
+		messages := make([]bus.Message, 0, len(codes))
+		for _, code := range codes {
+			messages = append(messages, syncCommand{Kind: kind, Code: code})
+		}
+		return messages, nil
+	}
+}
+
+func TestSync_RunsStepsInDependencyOrder(t *testing.T) {
+	var dispatched []string
+	dispatcher := &MockBus{dispatchFunc: func(ctx context.Context, msg bus.Message) (bus.Response, error) {
+		if !retry.RecordingDisabled(ctx) {
+			t.Error("Expected failures of nested commands not to be queued")
+		}
+		cmd := msg.(syncCommand)
+		dispatched = append(dispatched, cmd.Kind+":"+cmd.Code)
+
+		if cmd.Code == "mobile" {
+			return bus.Response{Data: syncResult{failures: []retry.Failure{{Kind: "channel", Code: "mobile", Error: "unknown currency"}}}}, nil
+		}
+		return bus.Response{Data: syncResult{synced: 1}}, nil
+	}}
+
+	// Registered out of order on purpose, without reference entities
+	service := syncing.NewService(dispatcher,
+		syncing.WithStep(syncing.StepAssociationTypes, commands("association_type", "X_SELL")),
+		syncing.WithStep(syncing.StepFamilies, commands("family", "all")),
+		syncing.WithStep(syncing.StepChannels, commands("channel", "ecommerce", "mobile")),
+		syncing.WithStep(syncing.StepCategoryTrees, commands("category_tree", "master")),
+		syncing.WithStep(syncing.StepAttributes, commands("attribute", "all")),
+		syncing.WithStep(syncing.StepMeasurementFamilies, commands("measurement_family", "all")),
+	)
+
+	var started []syncing.Step
+	result, err := service.Sync(context.Background(), syncing.SyncOptions{
+		Progress: func(step syncing.Step, commands int) { started = append(started, step) },
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{"measurement_family:all", "attribute:all", "category_tree:master", "channel:ecommerce", "channel:mobile", "family:all", "association_type:X_SELL"}
+	if len(dispatched) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, dispatched)
+	}
+	for i := range expected {
+		if dispatched[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, dispatched)
+			break
+		}
+	}
+
+	if len(result.Steps) != len(syncing.Order) || !result.Steps[1].Skipped || len(started) != 6 {
+		t.Errorf("Expected reference entities to be skipped, got %+v", result.Steps)
+	}
+	if result.Synced() != 6 || len(result.Failures()) != 1 || result.Failures()[0].Code != "mobile" {
+		t.Errorf("Expected 6 synced items and mobile to fail, got %d %v", result.Synced(), result.Failures())
+	}
+	if result.Stopped != "" {
+		t.Errorf("Expected the migration to complete, stopped at %s", result.Stopped)
+	}
+}
+
+func TestSync_StopsWhenStepFailsAsWhole(t *testing.T) {
+	var dispatched []string
+	dispatcher := &MockBus{dispatchFunc: func(ctx context.Context, msg bus.Message) (bus.Response, error) {
+		cmd := msg.(syncCommand)
+		dispatched = append(dispatched, cmd.Kind)
+		if cmd.Kind == "attribute" {
+			return bus.Response{}, errors.New("source unavailable")
+		}
+		return bus.Response{Data: syncResult{synced: 1}}, nil
+	}}
+
+	service := syncing.NewService(dispatcher,
+		syncing.WithStep(syncing.StepMeasurementFamilies, commands("measurement_family", "all")),
+		syncing.WithStep(syncing.StepAttributes, commands("attribute", "all")),
+		syncing.WithStep(syncing.StepFamilies, commands("family", "all")),
+	)
+
+	result, err := service.Sync(context.Background(), syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(dispatched) != 2 {
+		t.Errorf("Expected families not to be synchronized, got %v", dispatched)
+	}
+	if result.Stopped != syncing.StepAttributes || result.Steps[len(result.Steps)-1].Error != "source unavailable" {
+		t.Errorf("Expected the migration to stop at attributes, got %+v", result)
+	}
+}
+
+func TestSync_StopsWhenListingFails(t *testing.T) {
+	dispatcher := &MockBus{dispatchFunc: func(ctx context.Context, msg bus.Message) (bus.Response, error) {
+		t.Errorf("Expected nothing to be dispatched, got %v", msg)
+		return bus.Response{}, nil
+	}}
+
+	service := syncing.NewService(dispatcher,
+		syncing.WithStep(syncing.StepCategoryTrees, func(ctx context.Context, opts syncing.SyncOptions) ([]bus.Message, error) {
+			return nil, errors.New("forbidden")
+		}),
+		syncing.WithStep(syncing.StepChannels, commands("channel", "ecommerce")),
+	)
+
+	result, err := service.Sync(context.Background(), syncing.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Stopped != syncing.StepCategoryTrees {
+		t.Errorf("Expected the migration to stop at category trees, got %+v", result)
+	}
+}
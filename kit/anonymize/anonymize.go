@@ -0,0 +1,306 @@
+package anonymize
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Action defines how the data of an attribute is anonymized
+type Action string
+
+const (
+	// Hash replaces text data with a salted SHA-256 prefix; non-text data is cleared
+	Hash Action = "hash"
+	// Faker replaces data with a deterministic fake value of the same shape
+	Faker Action = "faker"
+	// Constant replaces data with a fixed value
+	Constant Action = "constant"
+	// Drop removes the attribute from the payload
+	Drop Action = "drop"
+	// Null sends null data, clearing the value in destination
+	Null Action = "null"
+)
+
+// Faker kinds for text data
+const (
+	FakeText   = "text"
+	FakeName   = "name"
+	FakeEmail  = "email"
+	FakeNumber = "number"
+)
+
+// Rule anonymizes the values of one attribute
+type Rule struct {
+	Attribute string
+	Action    Action
+	// Value is the data sent by the Constant action
+	Value interface{}
+	// Faker is the kind of fake text generated by the Faker action (text by default)
+	Faker string
+}
+
+// Anonymizer applies anonymization rules to Akeneo values and counts the values it scrubs.
+// It is safe for concurrent use.
+type Anonymizer struct {
+	rules map[string]Rule
+	salt  string
+
+	mu       sync.Mutex
+	scrubbed map[string]int
+}
+
+// Scrubbed is the number of values anonymized for an attribute
+type Scrubbed struct {
+	Attribute string
+	Action    Action
+	Values    int
+}
+
+// New validates the rules and creates an anonymizer.
+// The salt is mixed into hashes and fake value seeds so they cannot be reversed with a dictionary.
+func New(rules []Rule, salt string) (*Anonymizer, error) {
+	indexed := make(map[string]Rule, len(rules))
+	for _, rule := range rules {
+		if rule.Attribute == "" {
+			return nil, fmt.Errorf("anonymization rule without attribute")
+		}
+
+		switch rule.Action {
+		case Hash, Constant, Drop, Null:
+		case Faker:
+			switch rule.Faker {
+			case "", FakeText, FakeName, FakeEmail, FakeNumber:
+			default:
+				return nil, fmt.Errorf("invalid faker '%s' for attribute '%s' (expected text, name, email or number)", rule.Faker, rule.Attribute)
+			}
+		default:
+			return nil, fmt.Errorf("invalid anonymization action '%s' for attribute '%s' (expected hash, faker, constant, drop or null)", rule.Action, rule.Attribute)
+		}
+
+		if _, exists := indexed[rule.Attribute]; exists {
+			return nil, fmt.Errorf("duplicate anonymization rule for attribute '%s'", rule.Attribute)
+		}
+		indexed[rule.Attribute] = rule
+	}
+
+	return &Anonymizer{rules: indexed, salt: salt, scrubbed: make(map[string]int)}, nil
+}
+
+// Enabled reports whether the anonymizer has any rule
+func (a *Anonymizer) Enabled() bool {
+	return a != nil && len(a.rules) > 0
+}
+
+// Apply returns a copy of a values map ({attribute: [{locale, scope|channel, data}]})
+// with every rule applied. The input is never modified.
+func (a *Anonymizer) Apply(values map[string]interface{}) map[string]interface{} {
+	if !a.Enabled() || values == nil {
+		return values
+	}
+
+	result := make(map[string]interface{}, len(values))
+	for attributeCode, entries := range values {
+		rule, exists := a.rules[attributeCode]
+		if !exists {
+			result[attributeCode] = entries
+			continue
+		}
+
+		list, ok := entries.([]interface{})
+		if rule.Action == Drop {
+			a.count(attributeCode, len(list))
+			continue
+		}
+		if !ok {
+			result[attributeCode] = entries
+			continue
+		}
+
+		anonymized := make([]interface{}, 0, len(list))
+		for _, entry := range list {
+			value, ok := entry.(map[string]interface{})
+			if !ok {
+				anonymized = append(anonymized, entry)
+				continue
+			}
+
+			copied := make(map[string]interface{}, len(value))
+			for key, item := range value {
+				copied[key] = item
+			}
+			copied["data"] = a.anonymize(rule, value["data"])
+			anonymized = append(anonymized, copied)
+			if value["data"] != nil {
+				a.count(attributeCode, 1)
+			}
+		}
+		result[attributeCode] = anonymized
+	}
+
+	return result
+}
+
+// WithoutDropped returns a copy of a values map without the attributes of drop rules, so destination
+// values can be restricted to the ones a sync writes. Nothing is counted and the input is never modified.
+func (a *Anonymizer) WithoutDropped(values map[string]interface{}) map[string]interface{} {
+	if !a.Enabled() || values == nil {
+		return values
+	}
+
+	result := make(map[string]interface{}, len(values))
+	for attributeCode, entries := range values {
+		if rule, exists := a.rules[attributeCode]; exists && rule.Action == Drop {
+			continue
+		}
+		result[attributeCode] = entries
+	}
+
+	return result
+}
+
+// count records values scrubbed for an attribute
+func (a *Anonymizer) count(attributeCode string, values int) {
+	if values > 0 {
+		a.mu.Lock()
+		a.scrubbed[attributeCode] += values
+		a.mu.Unlock()
+	}
+}
+
+// Scrubbed returns the number of values anonymized so far for each attribute, sorted by attribute
+func (a *Anonymizer) Scrubbed() []Scrubbed {
+	if !a.Enabled() {
+		return nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	scrubbed := make([]Scrubbed, 0, len(a.scrubbed))
+	for attributeCode, values := range a.scrubbed {
+		scrubbed = append(scrubbed, Scrubbed{Attribute: attributeCode, Action: a.rules[attributeCode].Action, Values: values})
+	}
+	sort.Slice(scrubbed, func(i, j int) bool { return scrubbed[i].Attribute < scrubbed[j].Attribute })
+
+	return scrubbed
+}
+
+// anonymize applies a rule to the data of a single value
+func (a *Anonymizer) anonymize(rule Rule, data interface{}) interface{} {
+	if data == nil {
+		return nil
+	}
+
+	switch rule.Action {
+	case Null:
+		return nil
+	case Constant:
+		return rule.Value
+	case Hash:
+		return a.hash(data)
+	case Faker:
+		return a.fake(rule, data)
+	default:
+		return data
+	}
+}
+
+// hash replaces text, or lists of text, with a salted digest
+func (a *Anonymizer) hash(data interface{}) interface{} {
+	switch v := data.(type) {
+	case string:
+		return a.digest(v)[:16]
+	case []interface{}:
+		hashed := make([]interface{}, len(v))
+		for i, item := range v {
+			hashed[i] = a.hash(item)
+		}
+		return hashed
+	default:
+		return nil
+	}
+}
+
+// fake replaces data with a fake value of the same shape, seeded by the original data
+func (a *Anonymizer) fake(rule Rule, data interface{}) interface{} {
+	encoded, _ := json.Marshal(data) //nolint:errcheck // values decoded from JSON are always encodable
+	sum := sha256.Sum256([]byte(a.salt + string(encoded)))
+	random := rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(sum[:8])))) //nolint:gosec // not used for security
+
+	return fakeValue(random, rule.Faker, data)
+}
+
+// fakeValue generates a value with the same shape as data
+func fakeValue(random *rand.Rand, kind string, data interface{}) interface{} {
+	switch v := data.(type) {
+	case string:
+		if _, err := strconv.ParseFloat(v, 64); err == nil && kind == "" {
+			return fakeAmount(random)
+		}
+		return fakeText(random, kind)
+	case float64:
+		return float64(random.Intn(1000))
+	case bool:
+		return random.Intn(2) == 1
+	case []interface{}:
+		faked := make([]interface{}, len(v))
+		for i, item := range v {
+			faked[i] = fakeValue(random, kind, item)
+		}
+		return faked
+	case map[string]interface{}:
+		// Prices and metrics: only the amount is sensitive, currency and unit are kept
+		faked := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			faked[key] = item
+		}
+		if _, hasAmount := v["amount"]; hasAmount {
+			faked["amount"] = fakeAmount(random)
+		}
+		return faked
+	default:
+		return nil
+	}
+}
+
+var (
+	firstNames = []string{"Alex", "Sam", "Robin", "Charlie", "Jordan", "Taylor", "Morgan", "Casey"}
+	lastNames  = []string{"Smith", "Garcia", "Martin", "Rossi", "Müller", "Dubois", "Silva", "Jensen"}
+	words      = []string{"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "tempor"}
+)
+
+// fakeText generates fake text of the given kind
+func fakeText(random *rand.Rand, kind string) string {
+	switch kind {
+	case FakeName:
+		return firstNames[random.Intn(len(firstNames))] + " " + lastNames[random.Intn(len(lastNames))]
+	case FakeEmail:
+		return fmt.Sprintf("user%06d@example.com", random.Intn(1000000))
+	case FakeNumber:
+		return fakeAmount(random)
+	default:
+		text := words[random.Intn(len(words))]
+		for i := 0; i < 4; i++ {
+			text += " " + words[random.Intn(len(words))]
+		}
+		return text
+	}
+}
+
+// fakeAmount generates a decimal amount formatted the way Akeneo returns prices
+func fakeAmount(random *rand.Rand) string {
+	return fmt.Sprintf("%d.%02d", random.Intn(1000), random.Intn(100))
+}
+
+// digest returns the salted SHA-256 hex digest of a text
+func (a *Anonymizer) digest(text string) string {
+	sum := sha256.Sum256([]byte(a.salt + text))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,110 @@
+package anonymize
+
+import "testing"
+
+func TestApply(t *testing.T) {
+	anonymizer, err := New([]Rule{
+		{Attribute: "supplier_price", Action: Faker},
+		{Attribute: "customer_name", Action: Faker, Faker: FakeName},
+		{Attribute: "supplier_code", Action: Hash},
+		{Attribute: "internal_note", Action: Drop},
+		{Attribute: "contact", Action: Constant, Value: "anonymized"},
+	}, "salt")
+	if err != nil {
+		t.Fatalf("Expected valid rules, got %v", err)
+	}
+
+	values := map[string]interface{}{
+		"name": []interface{}{map[string]interface{}{"locale": "en_US", "scope": nil, "data": "Boot"}},
+		"supplier_price": []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": []interface{}{
+			map[string]interface{}{"amount": "12.50", "currency": "EUR"},
+		}}},
+		"customer_name": []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "Jane Doe"}},
+		"supplier_code": []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "ACME-42"}},
+		"internal_note": []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "secret"}},
+		"contact":       []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "jane@acme.com"}},
+	}
+
+	result := anonymizer.Apply(values)
+
+	if _, exists := result["internal_note"]; exists {
+		t.Error("Expected dropped attribute to be removed")
+	}
+
+	if data(result, "name") != "Boot" {
+		t.Errorf("Expected untouched attribute, got %v", data(result, "name"))
+	}
+
+	if data(result, "contact") != "anonymized" {
+		t.Errorf("Expected constant value, got %v", data(result, "contact"))
+	}
+
+	hashed := data(result, "supplier_code")
+	if hashed == "ACME-42" || len(hashed.(string)) != 16 {
+		t.Errorf("Expected hashed value, got %v", hashed)
+	}
+
+	if data(result, "customer_name") == "Jane Doe" {
+		t.Error("Expected fake name")
+	}
+
+	price := data(result, "supplier_price").([]interface{})[0].(map[string]interface{})
+	if price["currency"] != "EUR" || price["amount"] == "12.50" {
+		t.Errorf("Expected fake amount with same currency, got %v", price)
+	}
+
+	if data(values, "supplier_code") != "ACME-42" {
+		t.Error("Expected source values not to be modified")
+	}
+
+	again := anonymizer.Apply(values)
+	if data(again, "customer_name") != data(result, "customer_name") {
+		t.Error("Expected fake values to be deterministic")
+	}
+}
+
+func TestApply_CountsScrubbedValues(t *testing.T) {
+	anonymizer, err := New([]Rule{
+		{Attribute: "supplier_price", Action: Null},
+		{Attribute: "contact", Action: Drop},
+	}, "salt")
+	if err != nil {
+		t.Fatalf("Expected valid rules, got %v", err)
+	}
+
+	values := map[string]interface{}{
+		"name":           []interface{}{map[string]interface{}{"locale": "en_US", "scope": nil, "data": "Boot"}},
+		"supplier_price": []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "12.50"}},
+		"contact": []interface{}{
+			map[string]interface{}{"locale": "en_US", "scope": nil, "data": "jane@acme.com"},
+			map[string]interface{}{"locale": "fr_FR", "scope": nil, "data": "jane@acme.com"},
+		},
+	}
+
+	result := anonymizer.Apply(values)
+	anonymizer.Apply(values)
+
+	if price, exists := result["supplier_price"]; !exists || data(result, "supplier_price") != nil {
+		t.Errorf("Expected the price to be sent as null, got %v", price)
+	}
+
+	scrubbed := anonymizer.Scrubbed()
+	expected := []Scrubbed{{Attribute: "contact", Action: Drop, Values: 4}, {Attribute: "supplier_price", Action: Null, Values: 2}}
+	if len(scrubbed) != len(expected) || scrubbed[0] != expected[0] || scrubbed[1] != expected[1] {
+		t.Errorf("Expected %v, got %v", expected, scrubbed)
+	}
+}
+
+func TestNew_InvalidRules(t *testing.T) {
+	if _, err := New([]Rule{{Attribute: "price", Action: "shuffle"}}, ""); err == nil {
+		t.Error("Expected error for unknown action")
+	}
+
+	if _, err := New([]Rule{{Attribute: "price", Action: Faker, Faker: "iban"}}, ""); err == nil {
+		t.Error("Expected error for unknown faker")
+	}
+}
+
+func data(values map[string]interface{}, attributeCode string) interface{} {
+	return values[attributeCode].([]interface{})[0].(map[string]interface{})["data"]
+}
@@ -0,0 +1,79 @@
+package attributes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Loader returns the attribute codes of an instance
+type Loader func(ctx context.Context) (map[string]bool, error)
+
+// Checker strips the values of attributes that do not exist in destination, which would otherwise
+// make destination reject the whole item. The destination attributes are loaded the first time
+// values are checked, and again on the next check when loading failed.
+type Checker struct {
+	load Loader
+
+	mu       sync.Mutex
+	existing map[string]bool
+}
+
+// NewChecker creates a checker reading the destination attributes with load
+func NewChecker(load Loader) *Checker {
+	return &Checker{load: load}
+}
+
+// Apply checks a values map ({attribute: [{locale, scope, data}]}). It returns a copy of the map
+// without the attributes missing in destination, along with their sorted codes. The input is never
+// modified, and a nil checker returns the values unchanged.
+func (c *Checker) Apply(ctx context.Context, values map[string]interface{}) (map[string]interface{}, []string, error) {
+	if c == nil || values == nil {
+		return values, nil, nil
+	}
+
+	existing, err := c.attributes(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error fetching destination attributes: %w", err)
+	}
+
+	var missing []string
+	for attributeCode := range values {
+		if !existing[attributeCode] {
+			missing = append(missing, attributeCode)
+		}
+	}
+	if len(missing) == 0 {
+		return values, nil, nil
+	}
+	sort.Strings(missing)
+
+	result := make(map[string]interface{}, len(values)-len(missing))
+	for attributeCode, entries := range values {
+		if existing[attributeCode] {
+			result[attributeCode] = entries
+		}
+	}
+
+	return result, missing, nil
+}
+
+// attributes returns the destination attributes, loading them unless a previous load succeeded
+func (c *Checker) attributes(ctx context.Context) (map[string]bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.existing == nil {
+		existing, err := c.load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			existing = map[string]bool{}
+		}
+		c.existing = existing
+	}
+
+	return c.existing, nil
+}
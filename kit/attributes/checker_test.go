@@ -0,0 +1,86 @@
+package attributes
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestChecker_StripsMissingAttributes(t *testing.T) {
+	calls := 0
+	checker := NewChecker(func(ctx context.Context) (map[string]bool, error) {
+		calls++
+		return map[string]bool{"name": true, "weight": true}, nil
+	})
+	values := map[string]interface{}{
+		"name":       []interface{}{map[string]interface{}{"locale": "en_US", "scope": nil, "data": "Shoe"}},
+		"weight":     []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "12"}},
+		"legacy_ref": []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "X-1"}},
+		"erp_code":   []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "E-1"}},
+	}
+
+	result, missing, err := checker.Apply(context.Background(), values)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(missing) != 2 || missing[0] != "erp_code" || missing[1] != "legacy_ref" {
+		t.Errorf("Expected erp_code and legacy_ref, got %v", missing)
+	}
+	if len(result) != 2 || result["name"] == nil || result["weight"] == nil {
+		t.Errorf("Expected only name and weight to be kept, got %v", result)
+	}
+	if len(values) != 4 {
+		t.Error("Expected the input values not to be modified")
+	}
+
+	// Destination attributes are loaded once
+	_, _, _ = checker.Apply(context.Background(), values)
+	if calls != 1 {
+		t.Errorf("Expected destination attributes to be loaded once, got %d", calls)
+	}
+}
+
+func TestChecker_LoadError(t *testing.T) {
+	checker := NewChecker(func(ctx context.Context) (map[string]bool, error) {
+		return nil, errors.New("unavailable")
+	})
+
+	if _, _, err := checker.Apply(context.Background(), map[string]interface{}{"name": nil}); err == nil {
+		t.Error("Expected the load error to be returned")
+	}
+}
+
+func TestChecker_NilChecker(t *testing.T) {
+	var checker *Checker
+	values := map[string]interface{}{"legacy_ref": nil}
+
+	result, missing, err := checker.Apply(context.Background(), values)
+	if err != nil || missing != nil || len(result) != 1 {
+		t.Errorf("Expected values unchanged, got %v %v %v", result, missing, err)
+	}
+}
+
+func TestChecker_RetriesFailedLoad(t *testing.T) {
+	calls := 0
+	checker := NewChecker(func(ctx context.Context) (map[string]bool, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("unavailable")
+		}
+		return map[string]bool{"name": true}, nil
+	})
+	values := map[string]interface{}{"name": nil, "legacy_ref": nil}
+
+	if _, _, err := checker.Apply(context.Background(), values); err == nil {
+		t.Fatal("Expected the load error to be returned")
+	}
+	for i := 0; i < 2; i++ {
+		if _, missing, err := checker.Apply(context.Background(), values); err != nil || len(missing) != 1 {
+			t.Fatalf("Expected the attributes to be loaded again, got %v (%v)", missing, err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("Expected the successful load to be kept, got %d loads", calls)
+	}
+}
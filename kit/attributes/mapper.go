@@ -0,0 +1,119 @@
+package attributes
+
+// Mapper renames attribute codes in Akeneo data, e.g. color in source → main_color in destination
+type Mapper struct {
+	codes map[string]string
+}
+
+// NewMapper creates a mapper from a source → destination attribute code mapping
+func NewMapper(codes map[string]string) *Mapper {
+	return &Mapper{codes: codes}
+}
+
+// Enabled reports whether the mapper renames anything; a nil mapper keeps every code
+func (m *Mapper) Enabled() bool {
+	return m != nil && len(m.codes) > 0
+}
+
+// Code returns the destination code of an attribute
+func (m *Mapper) Code(code string) string {
+	if m != nil {
+		if mapped, exists := m.codes[code]; exists {
+			return mapped
+		}
+	}
+	return code
+}
+
+// Apply returns a copy of decoded JSON data with the mapped attribute codes renamed wherever Akeneo
+// references attributes: the keys of "values", the "attributes" and "axes" lists of families, family
+// variants and attribute groups, family "attribute_requirements", "attribute_as_label",
+// "attribute_as_image" and the "attribute" of options. The input is never modified.
+func (m *Mapper) Apply(data interface{}) interface{} {
+	if !m.Enabled() {
+		return data
+	}
+	return m.apply(data)
+}
+
+// apply walks a JSON value and returns its renamed copy
+func (m *Mapper) apply(data interface{}) interface{} {
+	switch typed := data.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for key, value := range typed {
+			switch key {
+			case "values":
+				value = m.renameKeys(value)
+			case "attributes", "axes":
+				value = m.renameList(value)
+			case "attribute_requirements":
+				value = m.renameLists(value)
+			case "attribute", "attribute_as_label", "attribute_as_image":
+				value = m.rename(value)
+			default:
+				value = m.apply(value)
+			}
+			result[key] = value
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(typed))
+		for i, value := range typed {
+			result[i] = m.apply(value)
+		}
+		return result
+	default:
+		return data
+	}
+}
+
+// rename returns the destination code of an attribute code, other values unchanged
+func (m *Mapper) rename(value interface{}) interface{} {
+	if code, ok := value.(string); ok {
+		return m.Code(code)
+	}
+	return m.apply(value)
+}
+
+// renameList renames the codes of a list of attributes
+func (m *Mapper) renameList(value interface{}) interface{} {
+	list, ok := value.([]interface{})
+	if !ok {
+		return m.apply(value)
+	}
+
+	result := make([]interface{}, len(list))
+	for i, item := range list {
+		result[i] = m.rename(item)
+	}
+	return result
+}
+
+// renameLists renames the codes of lists of attributes indexed by another code, e.g. a channel
+func (m *Mapper) renameLists(value interface{}) interface{} {
+	lists, ok := value.(map[string]interface{})
+	if !ok {
+		return m.apply(value)
+	}
+
+	result := make(map[string]interface{}, len(lists))
+	for key, list := range lists {
+		result[key] = m.renameList(list)
+	}
+	return result
+}
+
+// renameKeys renames the keys of a map indexed by attribute code, such as values
+func (m *Mapper) renameKeys(value interface{}) interface{} {
+	byAttribute, ok := value.(map[string]interface{})
+	if !ok {
+		return m.apply(value)
+	}
+
+	result := make(map[string]interface{}, len(byAttribute))
+	for code, item := range byAttribute {
+		result[m.Code(code)] = m.apply(item)
+	}
+	return result
+}
@@ -0,0 +1,51 @@
+package attributes
+
+import "testing"
+
+func TestMapper_RenamesAttributes(t *testing.T) {
+	mapper := NewMapper(map[string]string{"color": "main_color", "name": "title"})
+
+	product := map[string]interface{}{
+		"values": map[string]interface{}{
+			"color":  []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "red"}},
+			"weight": []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "12"}},
+		},
+	}
+	family := map[string]interface{}{
+		"code":                   "shoes",
+		"attributes":             []interface{}{"sku", "name", "color"},
+		"attribute_as_label":     "name",
+		"attribute_requirements": map[string]interface{}{"ecommerce": []interface{}{"sku", "name"}},
+	}
+	variant := map[string]interface{}{
+		"code": "shoes_by_color",
+		"variant_attribute_sets": []interface{}{
+			map[string]interface{}{"level": 1, "axes": []interface{}{"color"}, "attributes": []interface{}{"color", "weight"}},
+		},
+	}
+
+	values := mapper.Apply(product).(map[string]interface{})["values"].(map[string]interface{})
+	if _, exists := values["color"]; exists || values["main_color"] == nil || values["weight"] == nil {
+		t.Errorf("Expected color values to be renamed, got %v", values)
+	}
+
+	mappedFamily := mapper.Apply(family).(map[string]interface{})
+	if attrs := mappedFamily["attributes"].([]interface{}); attrs[0] != "sku" || attrs[1] != "title" || attrs[2] != "main_color" {
+		t.Errorf("Expected family attributes to be renamed, got %v", attrs)
+	}
+	if mappedFamily["attribute_as_label"] != "title" {
+		t.Errorf("Expected the label attribute to be renamed, got %v", mappedFamily["attribute_as_label"])
+	}
+	if required := mappedFamily["attribute_requirements"].(map[string]interface{})["ecommerce"].([]interface{}); required[1] != "title" {
+		t.Errorf("Expected required attributes to be renamed, got %v", required)
+	}
+
+	set := mapper.Apply(variant).(map[string]interface{})["variant_attribute_sets"].([]interface{})[0].(map[string]interface{})
+	if axes := set["axes"].([]interface{}); axes[0] != "main_color" {
+		t.Errorf("Expected variant axes to be renamed, got %v", axes)
+	}
+
+	if family["attributes"].([]interface{})[1] != "name" {
+		t.Error("Expected input data to be untouched")
+	}
+}
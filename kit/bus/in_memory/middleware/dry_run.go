@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+	"sort"
+
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/bus/in_memory"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/logger"
+)
+
+// DryRun creates a middleware that summarizes the writes planned by each command executed
+// during a dry run. The planned payloads are part of the command result, e.g. in the session report.
+func DryRun(log *logger.Logger) inmemory.Middleware {
+	return func(ctx context.Context, msg bus.Message, next inmemory.NextFunc) (bus.Response, error) {
+		response, err := next(ctx, msg)
+
+		planner, ok := response.Data.(dryrun.Planner)
+		if !ok || !dryrun.Enabled(ctx) {
+			return response, err
+		}
+
+		writes := planner.PlannedWrites()
+		log.Info("🔎 Dry run, nothing was sent to destination", logger.F("command", msg.Type()), logger.F("planned", len(writes)))
+
+		counts := make(map[string]int)
+		for _, write := range writes {
+			counts[write.Kind]++
+		}
+		kinds := make([]string, 0, len(counts))
+		for kind := range counts {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		for _, kind := range kinds {
+			log.Info("   Planned writes", logger.F("kind", kind), logger.F("count", counts[kind]))
+		}
+
+		return response, err
+	}
+}
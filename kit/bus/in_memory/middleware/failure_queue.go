@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"context"
+
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/bus/in_memory"
+	"akeneo-migrator/kit/dryrun"
+	"akeneo-migrator/kit/logger"
+	"akeneo-migrator/kit/retry"
+)
+
+// FailureQueue creates a middleware that stores the items that failed during a command
+// so they can be reprocessed later with retry-failed
+func FailureQueue(record retry.RecordFunc, log *logger.Logger) inmemory.Middleware {
+	return func(ctx context.Context, msg bus.Message, next inmemory.NextFunc) (bus.Response, error) {
+		response, err := next(ctx, msg)
+
+		// Nothing was written during a dry run, so there is nothing to retry either
+		if retry.RecordingDisabled(ctx) || dryrun.Enabled(ctx) {
+			return response, err
+		}
+
+		failures := retry.Collect(msg, response, err)
+		if len(failures) == 0 {
+			return response, err
+		}
+
+		jobID, recordErr := record(ctx, msg.Type(), failures)
+		if recordErr != nil {
+			log.Warn("⚠️  Could not queue failed items", logger.F("failed", len(failures)), logger.Err(recordErr))
+			return response, err
+		}
+
+		log.Info("🗂️  Failed items queued, run retry-failed with the job", logger.F("failed", len(failures)), logger.F("job", jobID))
+
+		return response, err
+	}
+}
@@ -2,28 +2,28 @@ package middleware
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"akeneo-migrator/kit/bus"
 	"akeneo-migrator/kit/bus/in_memory"
+	"akeneo-migrator/kit/logger"
 )
 
 // Logging creates a middleware that logs command execution
-func Logging() inmemory.Middleware {
+func Logging(log *logger.Logger) inmemory.Middleware {
 	return func(ctx context.Context, msg bus.Message, next inmemory.NextFunc) (bus.Response, error) {
 		start := time.Now()
 
-		fmt.Printf("🔄 Executing command: %s\n", msg.Type())
+		log.Info("🔄 Executing command", logger.F("command", msg.Type()))
 
 		response, err := next(ctx, msg)
 
 		duration := time.Since(start)
 
 		if err != nil {
-			fmt.Printf("❌ Command failed: %s (took %v)\n", msg.Type(), duration)
+			log.Error("❌ Command failed", logger.F("command", msg.Type()), logger.F("took", duration), logger.Err(err))
 		} else {
-			fmt.Printf("✅ Command completed: %s (took %v)\n", msg.Type(), duration)
+			log.Info("✅ Command completed", logger.F("command", msg.Type()), logger.F("took", duration))
 		}
 
 		return response, err
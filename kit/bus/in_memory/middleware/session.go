@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"akeneo-migrator/kit/bus"
+	"akeneo-migrator/kit/bus/in_memory"
+	"akeneo-migrator/kit/retry"
+	"akeneo-migrator/kit/session"
+)
+
+// Session creates a middleware that records the result of every executed command as a step
+// of the session. Commands dispatching other commands are represented by their nested steps.
+func Session(s *session.Session) inmemory.Middleware {
+	return func(ctx context.Context, msg bus.Message, next inmemory.NextFunc) (bus.Response, error) {
+		ctx, nested := session.Enter(ctx)
+		start := time.Now()
+
+		response, err := next(ctx, msg)
+
+		if nested() {
+			return response, err
+		}
+
+		failures := retry.Collect(msg, response, err)
+		step := session.Step{
+			Command:   string(msg.Type()),
+			StartedAt: start.UTC(),
+			Duration:  time.Since(start),
+			Failed:    len(failures),
+			Failures:  failures,
+			Result:    response.Data,
+		}
+		if counter, ok := response.Data.(session.Counter); ok {
+			step.Synced = counter.Synced()
+		}
+		if err != nil {
+			step.Error = err.Error()
+		}
+		s.Record(step)
+
+		return response, err
+	}
+}
@@ -0,0 +1,80 @@
+package channels
+
+// Mapper renames channel codes in Akeneo data, e.g. ecommerce in source → web in destination
+type Mapper struct {
+	codes map[string]string
+}
+
+// NewMapper creates a mapper from a source → destination channel code mapping
+func NewMapper(codes map[string]string) *Mapper {
+	return &Mapper{codes: codes}
+}
+
+// Enabled reports whether the mapper renames anything; a nil mapper keeps every code
+func (m *Mapper) Enabled() bool {
+	return m != nil && len(m.codes) > 0
+}
+
+// Code returns the destination code of a channel
+func (m *Mapper) Code(code string) string {
+	if m != nil {
+		if mapped, exists := m.codes[code]; exists {
+			return mapped
+		}
+	}
+	return code
+}
+
+// Apply returns a copy of decoded JSON data with the mapped channel codes renamed wherever Akeneo
+// references channels: the "scope" of product values and completenesses, the "channel" of record
+// and asset values, and the keys of family "attribute_requirements". The input is never modified.
+func (m *Mapper) Apply(data interface{}) interface{} {
+	if !m.Enabled() {
+		return data
+	}
+	return m.apply(data)
+}
+
+// apply walks a JSON value and returns its renamed copy
+func (m *Mapper) apply(data interface{}) interface{} {
+	switch typed := data.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for key, value := range typed {
+			switch key {
+			case "scope", "channel":
+				if code, ok := value.(string); ok {
+					value = m.Code(code)
+				}
+			case "attribute_requirements":
+				value = m.renameKeys(value)
+			default:
+				value = m.apply(value)
+			}
+			result[key] = value
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(typed))
+		for i, value := range typed {
+			result[i] = m.apply(value)
+		}
+		return result
+	default:
+		return data
+	}
+}
+
+// renameKeys renames the keys of a map indexed by channel code
+func (m *Mapper) renameKeys(value interface{}) interface{} {
+	byChannel, ok := value.(map[string]interface{})
+	if !ok {
+		return m.apply(value)
+	}
+
+	result := make(map[string]interface{}, len(byChannel))
+	for code, item := range byChannel {
+		result[m.Code(code)] = item
+	}
+	return result
+}
@@ -0,0 +1,57 @@
+package channels
+
+import "testing"
+
+func TestMapper_RenamesChannels(t *testing.T) {
+	mapper := NewMapper(map[string]string{"ecommerce": "web"})
+
+	product := map[string]interface{}{
+		"values": map[string]interface{}{
+			"description": []interface{}{
+				map[string]interface{}{"locale": "en_US", "scope": "ecommerce", "data": "Web text"},
+				map[string]interface{}{"locale": "en_US", "scope": "print", "data": "Print text"},
+			},
+			"weight": []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "12"}},
+		},
+	}
+	record := map[string]interface{}{
+		"values": map[string]interface{}{
+			"photo": []interface{}{map[string]interface{}{"locale": nil, "channel": "ecommerce", "data": "photo.jpg"}},
+		},
+	}
+	family := map[string]interface{}{
+		"code": "shoes",
+		"attribute_requirements": map[string]interface{}{
+			"ecommerce": []interface{}{"sku", "name"},
+			"print":     []interface{}{"sku"},
+		},
+	}
+
+	descriptions := mapper.Apply(product).(map[string]interface{})["values"].(map[string]interface{})["description"].([]interface{})
+	if descriptions[0].(map[string]interface{})["scope"] != "web" || descriptions[1].(map[string]interface{})["scope"] != "print" {
+		t.Errorf("Expected only ecommerce to be renamed, got %v", descriptions)
+	}
+
+	photo := mapper.Apply(record).(map[string]interface{})["values"].(map[string]interface{})["photo"].([]interface{})[0].(map[string]interface{})
+	if photo["channel"] != "web" {
+		t.Errorf("Expected the record channel to be renamed, got %v", photo["channel"])
+	}
+
+	requirements := mapper.Apply(family).(map[string]interface{})["attribute_requirements"].(map[string]interface{})
+	if _, exists := requirements["ecommerce"]; exists || requirements["web"] == nil || requirements["print"] == nil {
+		t.Errorf("Expected the ecommerce requirements to be renamed, got %v", requirements)
+	}
+
+	if family["attribute_requirements"].(map[string]interface{})["ecommerce"] == nil {
+		t.Error("Expected input data to be untouched")
+	}
+}
+
+func TestMapper_Code(t *testing.T) {
+	var disabled *Mapper
+	mapper := NewMapper(map[string]string{"ecommerce": "web"})
+
+	if mapper.Code("ecommerce") != "web" || mapper.Code("print") != "print" || disabled.Code("ecommerce") != "ecommerce" {
+		t.Error("Expected only mapped codes to be renamed")
+	}
+}
@@ -0,0 +1,115 @@
+package checksum
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+)
+
+// DefaultIgnoredFields are metadata fields that always differ between instances
+var DefaultIgnoredFields = []string{"_links", "created", "updated"}
+
+// Compute returns a normalized SHA-256 checksum of an Akeneo item.
+// Top-level ignored fields, "_links" at any depth, null values and empty
+// collections are dropped, and lists are compared regardless of their order.
+func Compute(item map[string]interface{}, ignoredFields ...string) (string, error) {
+	if len(ignoredFields) == 0 {
+		ignoredFields = DefaultIgnoredFields
+	}
+
+	ignored := make(map[string]bool, len(ignoredFields))
+	for _, field := range ignoredFields {
+		ignored[field] = true
+	}
+
+	normalized := make(map[string]interface{}, len(item))
+	for key, value := range item {
+		if ignored[key] {
+			continue
+		}
+		if normalizedValue, keep := normalize(value); keep {
+			normalized[key] = normalizedValue
+		}
+	}
+
+	// encoding/json sorts map keys, so the output is canonical
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// normalize prepares a value for canonical encoding, reporting whether it should be kept
+func normalize(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case nil:
+		return nil, false
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return nil, false
+		}
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if key == "_links" {
+				continue
+			}
+			if normalizedItem, keep := normalize(item); keep {
+				result[key] = normalizedItem
+			}
+		}
+		if len(result) == 0 {
+			return nil, false
+		}
+		return result, true
+	case map[string]string:
+		if len(v) == 0 {
+			return nil, false
+		}
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[key] = item
+		}
+		return result, true
+	case []interface{}:
+		if len(v) == 0 {
+			return nil, false
+		}
+		type entry struct {
+			value   interface{}
+			encoded string
+		}
+		entries := make([]entry, 0, len(v))
+		for _, item := range v {
+			normalizedItem, keep := normalize(item)
+			if !keep {
+				continue
+			}
+			encoded, _ := json.Marshal(normalizedItem) //nolint:errcheck // normalized values are always encodable
+			entries = append(entries, entry{value: normalizedItem, encoded: string(encoded)})
+		}
+		if len(entries) == 0 {
+			return nil, false
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].encoded < entries[j].encoded })
+		result := make([]interface{}, len(entries))
+		for i, e := range entries {
+			result[i] = e.value
+		}
+		return result, true
+	case []string:
+		if len(v) == 0 {
+			return nil, false
+		}
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = item
+		}
+		return normalize(result)
+	default:
+		return v, true
+	}
+}
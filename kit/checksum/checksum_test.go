@@ -0,0 +1,89 @@
+package checksum
+
+import "testing"
+
+func TestCompute_IgnoresMetadataAndOrder(t *testing.T) {
+	source := map[string]interface{}{
+		"code":    "shoes",
+		"updated": "2024-01-01T00:00:00+00:00",
+		"_links":  map[string]interface{}{"self": map[string]interface{}{"href": "http://source"}},
+		"attributes": []interface{}{
+			"sku", "name", "description",
+		},
+		"labels": map[string]interface{}{"en_US": "Shoes"},
+		"parent": nil,
+	}
+
+	dest := map[string]interface{}{
+		"code":    "shoes",
+		"updated": "2024-06-01T00:00:00+00:00",
+		"attributes": []interface{}{
+			"description", "sku", "name",
+		},
+		"labels": map[string]string{"en_US": "Shoes"},
+	}
+
+	sourceChecksum, err := Compute(source)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	destChecksum, err := Compute(dest)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if sourceChecksum != destChecksum {
+		t.Errorf("Expected equal checksums, got %s and %s", sourceChecksum, destChecksum)
+	}
+}
+
+func TestCompute_DetectsChanges(t *testing.T) {
+	source := map[string]interface{}{"code": "shoes", "labels": map[string]interface{}{"en_US": "Shoes"}}
+	dest := map[string]interface{}{"code": "shoes", "labels": map[string]interface{}{"en_US": "Sneakers"}}
+
+	sourceChecksum, _ := Compute(source)
+	destChecksum, _ := Compute(dest)
+
+	if sourceChecksum == destChecksum {
+		t.Error("Expected different checksums")
+	}
+}
+
+func TestReport_Compare(t *testing.T) {
+	report := NewReport("family", "shoes")
+
+	item := map[string]interface{}{"code": "shoes"}
+	changed := map[string]interface{}{"code": "shoes", "attribute_as_label": "name"}
+
+	if err := report.Compare("family", "shoes", item, item); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := report.Compare("family_variant", "by_size", item, changed); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := report.Compare("family_variant", "by_color", item, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	report.Extra("family_variant", "legacy")
+
+	if report.Compared != 3 {
+		t.Errorf("Expected 3 compared items, got %d", report.Compared)
+	}
+
+	if report.Matches != 1 {
+		t.Errorf("Expected 1 match, got %d", report.Matches)
+	}
+
+	if report.Count(StatusMismatch) != 1 || report.Count(StatusMissing) != 1 || report.Count(StatusExtra) != 1 {
+		t.Errorf("Unexpected differences: %+v", report.Differences)
+	}
+
+	if report.SourceCount() != 3 || report.DestCount() != 3 {
+		t.Errorf("Expected 3 items on each side, got %d and %d", report.SourceCount(), report.DestCount())
+	}
+
+	if report.OK() {
+		t.Error("Expected report not to be OK")
+	}
+}
@@ -0,0 +1,129 @@
+package checksum
+
+import "fmt"
+
+// Status represents the verification status of an item
+type Status string
+
+const (
+	// StatusMismatch means the item exists on both sides with different content
+	StatusMismatch Status = "mismatch"
+	// StatusMissing means the item exists on the source but not on the destination
+	StatusMissing Status = "missing"
+	// StatusExtra means the item only exists on the destination
+	StatusExtra Status = "extra"
+)
+
+// Difference describes an item that is not identical on both instances
+type Difference struct {
+	Kind           string `json:"kind"`
+	Code           string `json:"code"`
+	Status         Status `json:"status"`
+	SourceChecksum string `json:"source_checksum,omitempty"`
+	DestChecksum   string `json:"dest_checksum,omitempty"`
+	Detail         string `json:"detail,omitempty"`
+}
+
+// Report contains the result of comparing items between two instances
+type Report struct {
+	Scope string `json:"scope"`
+	Code  string `json:"code"`
+	// Compared counts the source items, each compared with its destination counterpart
+	Compared    int          `json:"compared"`
+	Matches     int          `json:"matches"`
+	Differences []Difference `json:"differences"`
+}
+
+// NewReport creates an empty report for a scope
+func NewReport(scope, code string) *Report {
+	return &Report{
+		Scope:       scope,
+		Code:        code,
+		Differences: []Difference{},
+	}
+}
+
+// Compare compares a source item with its destination counterpart
+func (r *Report) Compare(kind, code string, source, dest map[string]interface{}) error {
+	r.Compared++
+
+	sourceChecksum, err := Compute(source)
+	if err != nil {
+		return fmt.Errorf("error computing source checksum for %s %s: %w", kind, code, err)
+	}
+
+	if dest == nil {
+		r.Differences = append(r.Differences, Difference{
+			Kind:           kind,
+			Code:           code,
+			Status:         StatusMissing,
+			SourceChecksum: sourceChecksum,
+		})
+		return nil
+	}
+
+	destChecksum, err := Compute(dest)
+	if err != nil {
+		return fmt.Errorf("error computing destination checksum for %s %s: %w", kind, code, err)
+	}
+
+	if sourceChecksum == destChecksum {
+		r.Matches++
+		return nil
+	}
+
+	r.Differences = append(r.Differences, Difference{
+		Kind:           kind,
+		Code:           code,
+		Status:         StatusMismatch,
+		SourceChecksum: sourceChecksum,
+		DestChecksum:   destChecksum,
+	})
+	return nil
+}
+
+// Missing records an item that could not be found on the destination
+func (r *Report) Missing(kind, code, detail string) {
+	r.Compared++
+	r.Differences = append(r.Differences, Difference{
+		Kind:   kind,
+		Code:   code,
+		Status: StatusMissing,
+		Detail: detail,
+	})
+}
+
+// Extra records an item that only exists on the destination
+func (r *Report) Extra(kind, code string) {
+	r.Differences = append(r.Differences, Difference{
+		Kind:   kind,
+		Code:   code,
+		Status: StatusExtra,
+	})
+}
+
+// Count returns the number of differences with the given status
+func (r *Report) Count(status Status) int {
+	count := 0
+	for _, difference := range r.Differences {
+		if difference.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// SourceCount returns the number of items of the scope in source
+func (r *Report) SourceCount() int {
+	return r.Compared
+}
+
+// DestCount returns the number of items of the scope in destination
+func (r *Report) DestCount() int {
+	return r.Compared - r.Count(StatusMissing) + r.Count(StatusExtra)
+}
+
+// OK reports whether both instances are identical for the verified scope
+func (r *Report) OK() bool {
+	return len(r.Differences) == 0
+}
@@ -0,0 +1,108 @@
+package conflict
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Strategy defines what happens to an item edited in destination since it was last synced
+type Strategy string
+
+const (
+	// None disables conflict detection: items are written without being compared to the last sync
+	None Strategy = ""
+	// SourceWins writes the source item anyway and reports the conflict
+	SourceWins Strategy = "source-wins"
+	// DestWins keeps the destination item and skips the source one
+	DestWins Strategy = "dest-wins"
+	// Abort stops the sync before the conflicting item is written
+	Abort Strategy = "abort"
+	// Interactive asks whether to overwrite each conflicting item
+	Interactive Strategy = "interactive"
+)
+
+// ParseStrategy validates a strategy name; an empty name disables conflict detection
+func ParseStrategy(name string) (Strategy, error) {
+	switch Strategy(name) {
+	case None, SourceWins, DestWins, Abort, Interactive:
+		return Strategy(name), nil
+	default:
+		return "", fmt.Errorf("invalid conflict strategy '%s' (expected source-wins, dest-wins, abort or interactive)", name)
+	}
+}
+
+// ErrConflict is returned when the Abort strategy stops a sync
+var ErrConflict = errors.New("item edited in destination since the last sync")
+
+// Conflict is an item whose destination version changed since it was last synced
+type Conflict struct {
+	Kind string
+	Code string
+	// Synced is the destination update date recorded after the last sync, Updated the current one
+	Synced  string
+	Updated string
+	// Overwritten reports whether the source item was written over the destination edits
+	Overwritten bool
+}
+
+// Baselines stores the destination update date of each item once it has been synced
+type Baselines interface {
+	// Find returns the update date recorded for an item, false when it was never synced
+	Find(ctx context.Context, kind, code string) (string, bool, error)
+
+	// Save records the update date of an item after it was synced
+	Save(ctx context.Context, kind, code, updated string) error
+}
+
+// Ask is asked whether to overwrite an item with the Interactive strategy
+type Ask func(ctx context.Context, c Conflict) bool
+
+// Detector compares the destination update date of items to the one recorded after their last sync
+type Detector struct {
+	baselines Baselines
+	ask       Ask
+}
+
+// NewDetector creates a detector recording the update dates in baselines. Ask is only used by
+// the Interactive strategy; without it, conflicting items are kept.
+func NewDetector(baselines Baselines, ask Ask) *Detector {
+	return &Detector{baselines: baselines, ask: ask}
+}
+
+// Check resolves a possible conflict on an item whose destination version was last updated at updated.
+// It returns the conflict found, nil when the item is unchanged or was never synced, and whether the
+// source item must be written. The Abort strategy returns an error wrapping ErrConflict.
+func (d *Detector) Check(ctx context.Context, strategy Strategy, kind, code, updated string) (*Conflict, bool, error) {
+	if strategy == None || updated == "" {
+		return nil, true, nil
+	}
+
+	synced, found, err := d.baselines.Find(ctx, kind, code)
+	if err != nil {
+		return nil, false, fmt.Errorf("error reading last sync of %s %s: %w", kind, code, err)
+	}
+	if !found || synced == updated {
+		return nil, true, nil
+	}
+
+	c := &Conflict{Kind: kind, Code: code, Synced: synced, Updated: updated}
+	switch strategy {
+	case SourceWins:
+		c.Overwritten = true
+	case Abort:
+		return c, false, fmt.Errorf("%s %s (synced at %s, updated at %s): %w", kind, code, synced, updated, ErrConflict)
+	case Interactive:
+		c.Overwritten = d.ask != nil && d.ask(ctx, *c)
+	}
+
+	return c, c.Overwritten, nil
+}
+
+// Record saves the destination update date of an item that was just synced
+func (d *Detector) Record(ctx context.Context, kind, code, updated string) error {
+	if updated == "" {
+		return nil
+	}
+	return d.baselines.Save(ctx, kind, code, updated)
+}
@@ -0,0 +1,81 @@
+package conflict_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"akeneo-migrator/kit/conflict"
+)
+
+type memoryBaselines map[string]string
+
+func (b memoryBaselines) Find(ctx context.Context, kind, code string) (string, bool, error) {
+	updated, ok := b[kind+"/"+code]
+	return updated, ok, nil
+}
+
+func (b memoryBaselines) Save(ctx context.Context, kind, code, updated string) error {
+	b[kind+"/"+code] = updated
+	return nil
+}
+
+func TestDetector_Strategies(t *testing.T) {
+	baselines := memoryBaselines{"product/SKU-1": "2024-01-01T10:00:00+00:00"}
+	detector := conflict.NewDetector(baselines, func(ctx context.Context, c conflict.Conflict) bool { return c.Code == "SKU-1" })
+	ctx := context.Background()
+	edited := "2024-02-01T10:00:00+00:00"
+
+	tests := []struct {
+		strategy  conflict.Strategy
+		write     bool
+		conflicts bool
+	}{
+		{conflict.None, true, false},
+		{conflict.SourceWins, true, true},
+		{conflict.DestWins, false, true},
+		{conflict.Interactive, true, true},
+	}
+
+	for _, tt := range tests {
+		c, write, err := detector.Check(ctx, tt.strategy, "product", "SKU-1", edited)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", tt.strategy, err)
+		}
+		if write != tt.write || (c != nil) != tt.conflicts {
+			t.Errorf("%s: expected write=%v conflict=%v, got write=%v conflict=%+v", tt.strategy, tt.write, tt.conflicts, write, c)
+		}
+	}
+
+	if _, _, err := detector.Check(ctx, conflict.Abort, "product", "SKU-1", edited); !errors.Is(err, conflict.ErrConflict) {
+		t.Errorf("Expected abort to return ErrConflict, got %v", err)
+	}
+}
+
+func TestDetector_NoConflictWithoutEdits(t *testing.T) {
+	baselines := memoryBaselines{}
+	detector := conflict.NewDetector(baselines, nil)
+	ctx := context.Background()
+
+	// Items never synced have no baseline to compare to
+	if c, write, err := detector.Check(ctx, conflict.Abort, "product", "SKU-1", "2024-01-01T10:00:00+00:00"); c != nil || !write || err != nil {
+		t.Fatalf("Expected no conflict before the first sync, got %+v %v %v", c, write, err)
+	}
+
+	if err := detector.Record(ctx, "product", "SKU-1", "2024-01-01T10:00:00+00:00"); err != nil {
+		t.Fatal(err)
+	}
+
+	if c, write, err := detector.Check(ctx, conflict.Abort, "product", "SKU-1", "2024-01-01T10:00:00+00:00"); c != nil || !write || err != nil {
+		t.Errorf("Expected no conflict on an unchanged item, got %+v %v %v", c, write, err)
+	}
+}
+
+func TestParseStrategy(t *testing.T) {
+	if strategy, err := conflict.ParseStrategy("dest-wins"); err != nil || strategy != conflict.DestWins {
+		t.Errorf("Expected dest-wins, got %s (%v)", strategy, err)
+	}
+	if _, err := conflict.ParseStrategy("newest-wins"); err == nil {
+		t.Error("Expected an error for an unknown strategy")
+	}
+}
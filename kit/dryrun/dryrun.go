@@ -0,0 +1,87 @@
+package dryrun
+
+import (
+	"context"
+	"sync"
+)
+
+// Write is an item a sync would have written to destination
+type Write struct {
+	// Kind is the type of item, using the kinds of the failures: product, record, attribute...
+	Kind string `json:"kind"`
+	// Scope is the parent of the item when its code is not unique on its own (e.g. the reference entity of a record)
+	Scope string `json:"scope,omitempty"`
+	Code  string `json:"code"`
+	// Data is the payload that would have been sent
+	Data interface{} `json:"data,omitempty"`
+}
+
+// Planner is implemented by sync results that can list the writes planned during a dry run
+type Planner interface {
+	PlannedWrites() []Write
+}
+
+type enabledKey struct{}
+
+type planKey struct{}
+
+// plan collects the writes recorded while a sync runs. Writes recorded in a nested plan
+// are added to the parent plan when the nested one is collected.
+type plan struct {
+	mu     sync.Mutex
+	parent *plan
+	writes []Write
+}
+
+func (p *plan) add(writes ...Write) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.writes = append(p.writes, writes...)
+}
+
+// With returns a context in which nothing is written to destination: syncs read and validate
+// the items as usual, then record the writes they would send
+func With(ctx context.Context) context.Context {
+	return context.WithValue(ctx, enabledKey{}, true)
+}
+
+// Enabled reports whether writes must only be recorded in this context
+func Enabled(ctx context.Context) bool {
+	enabled, _ := ctx.Value(enabledKey{}).(bool)
+	return enabled
+}
+
+// Collect returns the context in which a sync runs and a function returning, once the sync
+// is done, the writes it recorded. The function returns nothing outside of a dry run.
+func Collect(ctx context.Context) (context.Context, func() []Write) {
+	if !Enabled(ctx) {
+		return ctx, func() []Write { return nil }
+	}
+
+	parent, _ := ctx.Value(planKey{}).(*plan)
+	current := &plan{parent: parent}
+
+	return context.WithValue(ctx, planKey{}, current), func() []Write {
+		current.mu.Lock()
+		writes := append([]Write(nil), current.writes...)
+		current.mu.Unlock()
+
+		if current.parent != nil {
+			current.parent.add(writes...)
+		}
+		return writes
+	}
+}
+
+// Record adds a write to the plan of the context and reports whether the context is a dry run,
+// in which case the caller must not send the write
+func Record(ctx context.Context, write Write) bool {
+	if !Enabled(ctx) {
+		return false
+	}
+
+	if current, ok := ctx.Value(planKey{}).(*plan); ok {
+		current.add(write)
+	}
+	return true
+}
@@ -0,0 +1,34 @@
+package dryrun
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRecord_OutsideDryRun(t *testing.T) {
+	ctx, planned := Collect(context.Background())
+
+	if Record(ctx, Write{Kind: "product", Code: "SKU-001"}) {
+		t.Error("Expected writes to be sent outside of a dry run")
+	}
+	if writes := planned(); len(writes) != 0 {
+		t.Errorf("Expected no planned writes, got %v", writes)
+	}
+}
+
+func TestCollect_AddsNestedWritesToParent(t *testing.T) {
+	ctx, planned := Collect(With(context.Background()))
+	if !Record(ctx, Write{Kind: "family", Code: "shoes"}) {
+		t.Fatal("Expected the write to be recorded during a dry run")
+	}
+
+	nestedCtx, nestedPlanned := Collect(ctx)
+	Record(nestedCtx, Write{Kind: "family_variant", Scope: "shoes", Code: "by_size"})
+
+	if writes := nestedPlanned(); len(writes) != 1 || writes[0].Code != "by_size" {
+		t.Errorf("Expected the nested plan to hold its own write, got %v", writes)
+	}
+	if writes := planned(); len(writes) != 2 || writes[0].Code != "shoes" || writes[1].Code != "by_size" {
+		t.Errorf("Expected the parent plan to hold both writes, got %v", writes)
+	}
+}
@@ -0,0 +1,189 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+func (n *literalNode) eval(map[string]interface{}) (interface{}, error) {
+	return n.value, nil
+}
+
+func (n *identNode) eval(env map[string]interface{}) (interface{}, error) {
+	return env[n.name], nil
+}
+
+func (n *memberNode) eval(env map[string]interface{}) (interface{}, error) {
+	object, err := n.object.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if fields, ok := object.(map[string]interface{}); ok {
+		return fields[n.name], nil
+	}
+	return nil, nil
+}
+
+func (n *callNode) eval(env map[string]interface{}) (interface{}, error) {
+	args := make([]interface{}, len(n.args))
+	for i, arg := range n.args {
+		value, err := arg.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+
+	return functions[n.name](args)
+}
+
+func (n *unaryNode) eval(env map[string]interface{}) (interface{}, error) {
+	operand, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.operator == "!" {
+		return !Truthy(operand), nil
+	}
+
+	number, ok := toNumber(operand)
+	if !ok {
+		return nil, fmt.Errorf("cannot negate %v", operand)
+	}
+	return -number, nil
+}
+
+func (n *conditionalNode) eval(env map[string]interface{}) (interface{}, error) {
+	condition, err := n.condition.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if Truthy(condition) {
+		return n.then.eval(env)
+	}
+	return n.otherwise.eval(env)
+}
+
+func (n *binaryNode) eval(env map[string]interface{}) (interface{}, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	// Logical operators short-circuit
+	switch n.operator {
+	case "&&":
+		if !Truthy(left) {
+			return false, nil
+		}
+		right, err := n.right.eval(env)
+		return Truthy(right), err
+	case "||":
+		if Truthy(left) {
+			return true, nil
+		}
+		right, err := n.right.eval(env)
+		return Truthy(right), err
+	}
+
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.operator {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	case "+":
+		leftNumber, leftIsNumber := left.(float64)
+		rightNumber, rightIsNumber := right.(float64)
+		if leftIsNumber && rightIsNumber {
+			return leftNumber + rightNumber, nil
+		}
+		return toString(left) + toString(right), nil
+	}
+
+	leftNumber, leftOK := toNumber(left)
+	rightNumber, rightOK := toNumber(right)
+	if !leftOK || !rightOK {
+		return nil, fmt.Errorf("operator '%s' needs numbers, got %v and %v", n.operator, left, right)
+	}
+
+	switch n.operator {
+	case "-":
+		return leftNumber - rightNumber, nil
+	case "*":
+		return leftNumber * rightNumber, nil
+	case "/":
+		if rightNumber == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return leftNumber / rightNumber, nil
+	case "%":
+		if rightNumber == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return math.Mod(leftNumber, rightNumber), nil
+	case "<":
+		return leftNumber < rightNumber, nil
+	case "<=":
+		return leftNumber <= rightNumber, nil
+	case ">":
+		return leftNumber > rightNumber, nil
+	case ">=":
+		return leftNumber >= rightNumber, nil
+	}
+
+	return nil, fmt.Errorf("unknown operator '%s'", n.operator)
+}
+
+// toNumber converts numbers and numeric strings (Akeneo returns decimals as strings)
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case string:
+		number, err := strconv.ParseFloat(v, 64)
+		return number, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// toString converts a value to text, null being the empty string
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// equal compares two values, numerically when both are numbers or numeric strings
+func equal(left, right interface{}) bool {
+	_, leftIsString := left.(string)
+	_, rightIsString := right.(string)
+	if !leftIsString || !rightIsString {
+		leftNumber, leftOK := toNumber(left)
+		rightNumber, rightOK := toNumber(right)
+		if leftOK && rightOK {
+			return leftNumber == rightNumber
+		}
+	}
+
+	return reflect.DeepEqual(left, right)
+}
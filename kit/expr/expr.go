@@ -0,0 +1,60 @@
+package expr
+
+import "fmt"
+
+// Program is a compiled expression that can be evaluated many times
+type Program struct {
+	source string
+	root   node
+}
+
+// Compile parses an expression such as `upper(value)`, `values.brand + " " + values.name`
+// or `values.price > 100 ? true : false`
+func Compile(source string) (*Program, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression '%s': %w", source, err)
+	}
+
+	root, err := parse(tokens)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression '%s': %w", source, err)
+	}
+
+	return &Program{source: source, root: root}, nil
+}
+
+// Eval evaluates the expression. Identifiers are resolved in env and nested
+// maps are reached with dots; unknown identifiers and fields evaluate to null.
+func (p *Program) Eval(env map[string]interface{}) (interface{}, error) {
+	result, err := p.root.eval(env)
+	if err != nil {
+		return nil, fmt.Errorf("error evaluating '%s': %w", p.source, err)
+	}
+	return result, nil
+}
+
+// String returns the source of the expression
+func (p *Program) String() string {
+	return p.source
+}
+
+// Truthy reports whether a value is considered true in a condition
+func Truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	default:
+		return true
+	}
+}
@@ -0,0 +1,69 @@
+package expr
+
+import "testing"
+
+func TestEval(t *testing.T) {
+	env := map[string]interface{}{
+		"value": "boot",
+		"values": map[string]interface{}{
+			"brand": "Acme",
+			"name":  "Trail boot",
+			"price": "129.9900",
+			"tags":  []interface{}{"outdoor", "winter"},
+		},
+		"family": "shoes",
+	}
+
+	tests := []struct {
+		expression string
+		expected   interface{}
+	}{
+		{`upper(value)`, "BOOT"},
+		{`values.brand + " " + values.name`, "Acme Trail boot"},
+		{`concat(values.brand, "-", family)`, "Acme-shoes"},
+		{`values.price > 100 ? true : false`, true},
+		{`values.price >= 100 && family == "bags"`, false},
+		{`!contains(values.tags, "summer")`, true},
+		{`round(number(values.price) * 1.2, 2)`, 155.99},
+		{`default(values.missing, 'n/a')`, "n/a"},
+		{`1 + 2 * 3`, float64(7)},
+		{`(1 + 2) * 3`, float64(9)},
+		{`values.unknown.deep == null`, true},
+		{`len(values.tags) - 1`, float64(1)},
+	}
+
+	for _, test := range tests {
+		program, err := Compile(test.expression)
+		if err != nil {
+			t.Fatalf("Expected %s to compile, got %v", test.expression, err)
+		}
+
+		result, err := program.Eval(env)
+		if err != nil {
+			t.Fatalf("Expected %s to evaluate, got %v", test.expression, err)
+		}
+
+		if result != test.expected {
+			t.Errorf("Expected %s to be %v, got %v", test.expression, test.expected, result)
+		}
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	for _, expression := range []string{`upper(`, `1 +`, `"unterminated`, `unknown(value)`, `a ? b`, `value #`} {
+		if _, err := Compile(expression); err == nil {
+			t.Errorf("Expected %s not to compile", expression)
+		}
+	}
+}
+
+func TestEval_Errors(t *testing.T) {
+	program, err := Compile(`value / 0`)
+	if err != nil {
+		t.Fatalf("Expected expression to compile, got %v", err)
+	}
+
+	if _, err := program.Eval(map[string]interface{}{"value": 1.0}); err == nil {
+		t.Error("Expected division by zero error")
+	}
+}
@@ -0,0 +1,127 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// functions available in expressions
+var functions = map[string]func(args []interface{}) (interface{}, error){
+	"upper": func(args []interface{}) (interface{}, error) {
+		return stringFunction("upper", args, strings.ToUpper)
+	},
+	"lower": func(args []interface{}) (interface{}, error) {
+		return stringFunction("lower", args, strings.ToLower)
+	},
+	"trim": func(args []interface{}) (interface{}, error) {
+		return stringFunction("trim", args, strings.TrimSpace)
+	},
+	"concat": func(args []interface{}) (interface{}, error) {
+		var builder strings.Builder
+		for _, arg := range args {
+			builder.WriteString(toString(arg))
+		}
+		return builder.String(), nil
+	},
+	"replace": func(args []interface{}) (interface{}, error) {
+		if err := checkArgs("replace", args, 3); err != nil {
+			return nil, err
+		}
+		return strings.ReplaceAll(toString(args[0]), toString(args[1]), toString(args[2])), nil
+	},
+	"contains": func(args []interface{}) (interface{}, error) {
+		if err := checkArgs("contains", args, 2); err != nil {
+			return nil, err
+		}
+		if list, ok := args[0].([]interface{}); ok {
+			for _, item := range list {
+				if equal(item, args[1]) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		return strings.Contains(toString(args[0]), toString(args[1])), nil
+	},
+	"join": func(args []interface{}) (interface{}, error) {
+		if err := checkArgs("join", args, 2); err != nil {
+			return nil, err
+		}
+		list, _ := args[0].([]interface{})
+		parts := make([]string, len(list))
+		for i, item := range list {
+			parts[i] = toString(item)
+		}
+		return strings.Join(parts, toString(args[1])), nil
+	},
+	"len": func(args []interface{}) (interface{}, error) {
+		if err := checkArgs("len", args, 1); err != nil {
+			return nil, err
+		}
+		switch v := args[0].(type) {
+		case []interface{}:
+			return float64(len(v)), nil
+		case map[string]interface{}:
+			return float64(len(v)), nil
+		default:
+			return float64(len([]rune(toString(v)))), nil
+		}
+	},
+	"string": func(args []interface{}) (interface{}, error) {
+		if err := checkArgs("string", args, 1); err != nil {
+			return nil, err
+		}
+		return toString(args[0]), nil
+	},
+	"number": func(args []interface{}) (interface{}, error) {
+		if err := checkArgs("number", args, 1); err != nil {
+			return nil, err
+		}
+		number, ok := toNumber(args[0])
+		if !ok {
+			return nil, fmt.Errorf("number: cannot convert %v", args[0])
+		}
+		return number, nil
+	},
+	"round": func(args []interface{}) (interface{}, error) {
+		if err := checkArgs("round", args, 2); err != nil {
+			return nil, err
+		}
+		number, numberOK := toNumber(args[0])
+		decimals, decimalsOK := toNumber(args[1])
+		if !numberOK || !decimalsOK {
+			return nil, fmt.Errorf("round: needs numbers, got %v and %v", args[0], args[1])
+		}
+		factor := math.Pow(10, decimals)
+		return math.Round(number*factor) / factor, nil
+	},
+	"default": func(args []interface{}) (interface{}, error) {
+		if err := checkArgs("default", args, 2); err != nil {
+			return nil, err
+		}
+		if args[0] == nil || args[0] == "" {
+			return args[1], nil
+		}
+		return args[0], nil
+	},
+}
+
+// stringFunction applies a one-argument text function, keeping null as null
+func stringFunction(name string, args []interface{}, apply func(string) string) (interface{}, error) {
+	if err := checkArgs(name, args, 1); err != nil {
+		return nil, err
+	}
+	if args[0] == nil {
+		return nil, nil
+	}
+	return apply(toString(args[0])), nil
+}
+
+// checkArgs validates the number of arguments of a function call
+func checkArgs(name string, args []interface{}, expected int) error {
+	if len(args) != expected {
+		return fmt.Errorf("%s: expected %d arguments, got %d", name, expected, len(args))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOperator
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value interface{}
+	pos   int
+}
+
+// operators are sorted so that two-character operators are matched first
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "+", "-", "*", "/", "%", "<", ">", "!", "(", ")", ",", ".", "?", ":"}
+
+// tokenize splits an expression into tokens
+func tokenize(source string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(source)
+
+	for pos := 0; pos < len(runes); {
+		r := runes[pos]
+
+		switch {
+		case unicode.IsSpace(r):
+			pos++
+
+		case unicode.IsDigit(r):
+			start := pos
+			for pos < len(runes) && (unicode.IsDigit(runes[pos]) || runes[pos] == '.') {
+				pos++
+			}
+			text := string(runes[start:pos])
+			number, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number '%s' at position %d", text, start)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: text, value: number, pos: start})
+
+		case r == '"' || r == '\'':
+			start := pos
+			pos++
+			var builder strings.Builder
+			for pos < len(runes) && runes[pos] != r {
+				if runes[pos] == '\\' && pos+1 < len(runes) {
+					pos++
+				}
+				builder.WriteRune(runes[pos])
+				pos++
+			}
+			if pos >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+			pos++
+			tokens = append(tokens, token{kind: tokenString, text: builder.String(), value: builder.String(), pos: start})
+
+		case unicode.IsLetter(r) || r == '_':
+			start := pos
+			for pos < len(runes) && (unicode.IsLetter(runes[pos]) || unicode.IsDigit(runes[pos]) || runes[pos] == '_') {
+				pos++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[start:pos]), pos: start})
+
+		default:
+			matched := ""
+			for _, operator := range operators {
+				if strings.HasPrefix(string(runes[pos:]), operator) {
+					matched = operator
+					break
+				}
+			}
+			if matched == "" {
+				return nil, fmt.Errorf("unexpected character '%c' at position %d", r, pos)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: matched, pos: pos})
+			pos += len([]rune(matched))
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
@@ -0,0 +1,240 @@
+package expr
+
+import "fmt"
+
+// node is an element of the expression tree
+type node interface {
+	eval(env map[string]interface{}) (interface{}, error)
+}
+
+type literalNode struct{ value interface{} }
+
+type identNode struct{ name string }
+
+type memberNode struct {
+	object node
+	name   string
+}
+
+type callNode struct {
+	name string
+	args []node
+}
+
+type unaryNode struct {
+	operator string
+	operand  node
+}
+
+type binaryNode struct {
+	operator    string
+	left, right node
+}
+
+type conditionalNode struct {
+	condition, then, otherwise node
+}
+
+// precedence of binary operators, higher binds tighter
+var precedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parse builds the expression tree of a token list
+func parse(tokens []token) (node, error) {
+	p := &parser{tokens: tokens}
+
+	root, err := p.parseConditional()
+	if err != nil {
+		return nil, err
+	}
+
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected '%s' at position %d", next.text, next.pos)
+	}
+
+	return root, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expect(operator string) error {
+	t := p.next()
+	if t.kind != tokenOperator || t.text != operator {
+		return fmt.Errorf("expected '%s' at position %d", operator, t.pos)
+	}
+	return nil
+}
+
+func (p *parser) isOperator(operator string) bool {
+	t := p.peek()
+	return t.kind == tokenOperator && t.text == operator
+}
+
+// parseConditional parses "condition ? then : otherwise"
+func (p *parser) parseConditional() (node, error) {
+	condition, err := p.parseBinary(1)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.isOperator("?") {
+		return condition, nil
+	}
+	p.next()
+
+	then, err := p.parseConditional()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	otherwise, err := p.parseConditional()
+	if err != nil {
+		return nil, err
+	}
+
+	return &conditionalNode{condition: condition, then: then, otherwise: otherwise}, nil
+}
+
+// parseBinary parses binary operators with at least the given precedence
+func (p *parser) parseBinary(minPrecedence int) (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		level, isBinary := precedence[t.text]
+		if t.kind != tokenOperator || !isBinary || level < minPrecedence {
+			return left, nil
+		}
+		p.next()
+
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{operator: t.text, left: left, right: right}
+	}
+}
+
+// parseUnary parses "!" and "-" prefixes
+func (p *parser) parseUnary() (node, error) {
+	if p.isOperator("!") || p.isOperator("-") {
+		operator := p.next().text
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{operator: operator, operand: operand}, nil
+	}
+
+	return p.parsePostfix()
+}
+
+// parsePostfix parses member access on a primary expression
+func (p *parser) parsePostfix() (node, error) {
+	result, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOperator(".") {
+		p.next()
+		name := p.next()
+		if name.kind != tokenIdent {
+			return nil, fmt.Errorf("expected field name at position %d", name.pos)
+		}
+		result = &memberNode{object: result, name: name.text}
+	}
+
+	return result, nil
+}
+
+// parsePrimary parses literals, identifiers, calls and parenthesized expressions
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenNumber, tokenString:
+		return &literalNode{value: t.value}, nil
+
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "null":
+			return &literalNode{value: nil}, nil
+		}
+
+		if !p.isOperator("(") {
+			return &identNode{name: t.text}, nil
+		}
+		p.next()
+
+		if _, exists := functions[t.text]; !exists {
+			return nil, fmt.Errorf("unknown function '%s' at position %d", t.text, t.pos)
+		}
+
+		args := []node{}
+		for !p.isOperator(")") {
+			arg, err := p.parseConditional()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+
+			if !p.isOperator(",") {
+				break
+			}
+			p.next()
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+
+		return &callNode{name: t.text, args: args}, nil
+
+	case tokenOperator:
+		if t.text == "(" {
+			inner, err := p.parseConditional()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return inner, nil
+		}
+	}
+
+	if t.kind == tokenEOF {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected '%s' at position %d", t.text, t.pos)
+}
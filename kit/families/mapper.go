@@ -0,0 +1,60 @@
+package families
+
+// Mapper renames family codes in Akeneo data, e.g. shoes in source → footwear in destination
+type Mapper struct {
+	codes map[string]string
+}
+
+// NewMapper creates a mapper from a source → destination family code mapping
+func NewMapper(codes map[string]string) *Mapper {
+	return &Mapper{codes: codes}
+}
+
+// Enabled reports whether the mapper renames anything; a nil mapper keeps every code
+func (m *Mapper) Enabled() bool {
+	return m != nil && len(m.codes) > 0
+}
+
+// Code returns the destination code of a family
+func (m *Mapper) Code(code string) string {
+	if m != nil {
+		if mapped, exists := m.codes[code]; exists {
+			return mapped
+		}
+	}
+	return code
+}
+
+// Apply returns a copy of decoded JSON data with the mapped family codes renamed in the "family"
+// field of products and product models. The input is never modified.
+func (m *Mapper) Apply(data interface{}) interface{} {
+	if !m.Enabled() {
+		return data
+	}
+	return m.apply(data)
+}
+
+// apply walks a JSON value and returns its renamed copy
+func (m *Mapper) apply(data interface{}) interface{} {
+	switch typed := data.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for key, value := range typed {
+			if code, ok := value.(string); ok && key == "family" {
+				value = m.Code(code)
+			} else {
+				value = m.apply(value)
+			}
+			result[key] = value
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(typed))
+		for i, value := range typed {
+			result[i] = m.apply(value)
+		}
+		return result
+	default:
+		return data
+	}
+}
@@ -0,0 +1,37 @@
+package families
+
+import "testing"
+
+func TestMapper_RenamesFamilies(t *testing.T) {
+	mapper := NewMapper(map[string]string{"shoes": "footwear"})
+
+	page := map[string]interface{}{
+		"_embedded": map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"identifier": "SKU-1", "family": "shoes"},
+				map[string]interface{}{"identifier": "SKU-2", "family": "bags"},
+				map[string]interface{}{"identifier": "SKU-3", "family": nil},
+			},
+		},
+	}
+
+	items := mapper.Apply(page).(map[string]interface{})["_embedded"].(map[string]interface{})["items"].([]interface{})
+	if family := items[0].(map[string]interface{})["family"]; family != "footwear" {
+		t.Errorf("Expected shoes to be renamed footwear, got %v", family)
+	}
+	if family := items[1].(map[string]interface{})["family"]; family != "bags" {
+		t.Errorf("Expected unmapped families to be kept, got %v", family)
+	}
+	if family := items[2].(map[string]interface{})["family"]; family != nil {
+		t.Errorf("Expected products without family to be kept, got %v", family)
+	}
+}
+
+func TestMapper_Code(t *testing.T) {
+	var disabled *Mapper
+	mapper := NewMapper(map[string]string{"shoes": "footwear"})
+
+	if mapper.Code("shoes") != "footwear" || mapper.Code("bags") != "bags" || disabled.Code("shoes") != "shoes" {
+		t.Error("Expected only mapped codes to be renamed")
+	}
+}
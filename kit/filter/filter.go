@@ -0,0 +1,207 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rules lists what is kept of the values synced to destination. Empty lists keep everything.
+type Rules struct {
+	// Locales keeps localizable values in these locales only; non-localizable values are always kept
+	Locales []string
+	// Channels keeps scoped values in these channels only; non-scopable values are always kept.
+	// An entry written "source=dest" keeps the values of the source channel under the dest channel.
+	Channels []string
+	// Attributes keeps the values of these attributes only
+	Attributes []string
+	// ExcludedAttributes removes the values of these attributes, even when they are listed in Attributes
+	ExcludedAttributes []string
+}
+
+// Filter restricts Akeneo values ({attribute: [{locale, scope|channel, data}]}) to a subset of
+// attributes, locales and channels, renaming channels when asked to
+type Filter struct {
+	attributes map[string]bool
+	excluded   map[string]bool
+	locales    map[string]bool
+	// channels maps each kept channel to its destination code
+	channels map[string]string
+}
+
+// New validates the rules and creates a filter
+func New(rules Rules) (*Filter, error) {
+	f := &Filter{
+		attributes: toSet(rules.Attributes),
+		excluded:   toSet(rules.ExcludedAttributes),
+		locales:    toSet(rules.Locales),
+	}
+
+	for _, entry := range rules.Channels {
+		from, to, renamed := strings.Cut(entry, "=")
+		if !renamed {
+			to = from
+		}
+		if from == "" || to == "" {
+			return nil, fmt.Errorf("invalid channel '%s' (expected code or source=dest)", entry)
+		}
+		if _, exists := f.channels[from]; exists {
+			return nil, fmt.Errorf("duplicate channel '%s'", from)
+		}
+
+		if f.channels == nil {
+			f.channels = make(map[string]string, len(rules.Channels))
+		}
+		f.channels[from] = to
+	}
+
+	return f, nil
+}
+
+// Enabled reports whether the filter changes anything; a nil filter keeps every value
+func (f *Filter) Enabled() bool {
+	return f != nil && (len(f.attributes) > 0 || len(f.excluded) > 0 || len(f.locales) > 0 || len(f.channels) > 0)
+}
+
+// Apply returns a copy of a values map without the values excluded by the rules and with the kept
+// channels renamed. Attributes left without values are removed. The input is never modified.
+func (f *Filter) Apply(values map[string]interface{}) map[string]interface{} {
+	if !f.Enabled() {
+		return values
+	}
+
+	result := make(map[string]interface{}, len(values))
+	for attributeCode, entries := range values {
+		if !f.keepsAttribute(attributeCode) {
+			continue
+		}
+
+		list, ok := entries.([]interface{})
+		if !ok {
+			result[attributeCode] = entries
+			continue
+		}
+
+		kept := make([]interface{}, 0, len(list))
+		for _, entry := range list {
+			if value, ok := f.apply(entry); ok {
+				kept = append(kept, value)
+			}
+		}
+		if len(kept) > 0 {
+			result[attributeCode] = kept
+		}
+	}
+
+	return result
+}
+
+// ApplyDest returns a copy of destination values restricted to what the rules write: the kept attributes
+// and locales, and the destination channels of the kept channels. Values left out on purpose can then be
+// told apart from values to clear. The input is never modified.
+func (f *Filter) ApplyDest(values map[string]interface{}) map[string]interface{} {
+	if !f.Enabled() {
+		return values
+	}
+
+	destChannels := make(map[string]bool, len(f.channels))
+	for _, to := range f.channels {
+		destChannels[to] = true
+	}
+
+	result := make(map[string]interface{}, len(values))
+	for attributeCode, entries := range values {
+		if !f.keepsAttribute(attributeCode) {
+			continue
+		}
+
+		list, ok := entries.([]interface{})
+		if !ok {
+			result[attributeCode] = entries
+			continue
+		}
+
+		kept := make([]interface{}, 0, len(list))
+		for _, entry := range list {
+			value, ok := entry.(map[string]interface{})
+			if !ok {
+				kept = append(kept, entry)
+				continue
+			}
+			if locale, _ := value["locale"].(string); locale != "" && len(f.locales) > 0 && !f.locales[locale] {
+				continue
+			}
+			channel, _ := value["scope"].(string)
+			if channel == "" {
+				channel, _ = value["channel"].(string)
+			}
+			if channel != "" && len(destChannels) > 0 && !destChannels[channel] {
+				continue
+			}
+			kept = append(kept, entry)
+		}
+		if len(kept) > 0 {
+			result[attributeCode] = kept
+		}
+	}
+
+	return result
+}
+
+// keepsAttribute reports whether the values of an attribute pass the rules
+func (f *Filter) keepsAttribute(code string) bool {
+	if f.excluded[code] {
+		return false
+	}
+	return len(f.attributes) == 0 || f.attributes[code]
+}
+
+// apply returns a value with its channel renamed, or false when the rules exclude it
+func (f *Filter) apply(entry interface{}) (interface{}, bool) {
+	value, ok := entry.(map[string]interface{})
+	if !ok {
+		return entry, true
+	}
+
+	if locale, _ := value["locale"].(string); locale != "" && len(f.locales) > 0 && !f.locales[locale] {
+		return nil, false
+	}
+
+	// Products name the channel of a value "scope", records "channel"
+	key := "scope"
+	if _, isRecordValue := value["channel"]; isRecordValue {
+		key = "channel"
+	}
+	channel, _ := value[key].(string)
+	if channel == "" || len(f.channels) == 0 {
+		return value, true
+	}
+
+	to, kept := f.channels[channel]
+	if !kept {
+		return nil, false
+	}
+	if to == channel {
+		return value, true
+	}
+
+	renamed := make(map[string]interface{}, len(value))
+	for k, v := range value {
+		renamed[k] = v
+	}
+	renamed[key] = to
+
+	return renamed, true
+}
+
+// toSet indexes a list of codes, nil when it is empty
+func toSet(codes []string) map[string]bool {
+	if len(codes) == 0 {
+		return nil
+	}
+
+	set := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		set[code] = true
+	}
+	return set
+}
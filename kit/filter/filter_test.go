@@ -0,0 +1,152 @@
+package filter
+
+import "testing"
+
+func TestApply_Locales(t *testing.T) {
+	f, err := New(Rules{Locales: []string{"en_US", "fr_FR"}})
+	if err != nil {
+		t.Fatalf("Expected valid rules, got %v", err)
+	}
+
+	values := map[string]interface{}{
+		"name": []interface{}{
+			map[string]interface{}{"locale": "en_US", "scope": nil, "data": "Boot"},
+			map[string]interface{}{"locale": "de_DE", "scope": nil, "data": "Stiefel"},
+		},
+		"description": []interface{}{
+			map[string]interface{}{"locale": "de_DE", "scope": "ecommerce", "data": "Ein Stiefel"},
+		},
+		"weight": []interface{}{
+			map[string]interface{}{"locale": nil, "scope": nil, "data": "12"},
+		},
+	}
+
+	result := f.Apply(values)
+
+	if name := result["name"].([]interface{}); len(name) != 1 {
+		t.Errorf("Expected only the en_US name, got %v", name)
+	}
+	if _, exists := result["description"]; exists {
+		t.Error("Expected attribute without kept values to be removed")
+	}
+	if _, exists := result["weight"]; !exists {
+		t.Error("Expected non-localizable value to be kept")
+	}
+	if len(values["name"].([]interface{})) != 2 {
+		t.Error("Expected input values to be untouched")
+	}
+}
+
+func TestApply_WithoutRules(t *testing.T) {
+	var f *Filter
+	values := map[string]interface{}{"name": []interface{}{map[string]interface{}{"locale": "de_DE", "data": "Stiefel"}}}
+
+	empty, err := New(Rules{})
+	if err != nil {
+		t.Fatalf("Expected valid rules, got %v", err)
+	}
+
+	if f.Enabled() || empty.Enabled() {
+		t.Error("Expected filters without rules to be disabled")
+	}
+	if result := f.Apply(values); len(result) != 1 {
+		t.Errorf("Expected every value to be kept, got %v", result)
+	}
+}
+
+func TestApply_Channels(t *testing.T) {
+	f, err := New(Rules{Channels: []string{"ecommerce=web", "print"}})
+	if err != nil {
+		t.Fatalf("Expected valid rules, got %v", err)
+	}
+
+	values := map[string]interface{}{
+		"description": []interface{}{
+			map[string]interface{}{"locale": "en_US", "scope": "ecommerce", "data": "Web text"},
+			map[string]interface{}{"locale": "en_US", "scope": "print", "data": "Print text"},
+			map[string]interface{}{"locale": "en_US", "scope": "mobile", "data": "Mobile text"},
+		},
+		"photo": []interface{}{
+			map[string]interface{}{"locale": nil, "channel": "ecommerce", "data": "photo.jpg"},
+		},
+		"weight": []interface{}{
+			map[string]interface{}{"locale": nil, "scope": nil, "data": "12"},
+		},
+	}
+
+	result := f.Apply(values)
+
+	descriptions := result["description"].([]interface{})
+	if len(descriptions) != 2 {
+		t.Fatalf("Expected the mobile description to be dropped, got %v", descriptions)
+	}
+	if scope := descriptions[0].(map[string]interface{})["scope"]; scope != "web" {
+		t.Errorf("Expected ecommerce to be renamed web, got %v", scope)
+	}
+	if channel := result["photo"].([]interface{})[0].(map[string]interface{})["channel"]; channel != "web" {
+		t.Errorf("Expected the record channel to be renamed web, got %v", channel)
+	}
+	if _, exists := result["weight"]; !exists {
+		t.Error("Expected non-scopable value to be kept")
+	}
+	if scope := values["description"].([]interface{})[0].(map[string]interface{})["scope"]; scope != "ecommerce" {
+		t.Error("Expected input values to be untouched")
+	}
+}
+
+func TestNew_InvalidChannels(t *testing.T) {
+	for _, channels := range [][]string{{"=web"}, {"ecommerce="}, {"ecommerce", "ecommerce=web"}} {
+		if _, err := New(Rules{Channels: channels}); err == nil {
+			t.Errorf("Expected an error for %v", channels)
+		}
+	}
+}
+
+func TestApply_Attributes(t *testing.T) {
+	f, err := New(Rules{Attributes: []string{"name", "description", "erp_cost"}, ExcludedAttributes: []string{"erp_cost"}})
+	if err != nil {
+		t.Fatalf("Expected valid rules, got %v", err)
+	}
+
+	values := map[string]interface{}{
+		"name":        []interface{}{map[string]interface{}{"locale": "en_US", "scope": nil, "data": "Boot"}},
+		"description": []interface{}{map[string]interface{}{"locale": "en_US", "scope": "ecommerce", "data": "A boot"}},
+		"erp_cost":    []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "12"}},
+		"erp_stock":   []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "3"}},
+	}
+
+	result := f.Apply(values)
+
+	if len(result) != 2 || result["name"] == nil || result["description"] == nil {
+		t.Errorf("Expected only name and description to be kept, got %v", result)
+	}
+}
+
+func TestApplyDest(t *testing.T) {
+	f, err := New(Rules{Locales: []string{"en_US"}, Channels: []string{"ecommerce=web"}, ExcludedAttributes: []string{"erp_code"}})
+	if err != nil {
+		t.Fatalf("Expected valid rules, got %v", err)
+	}
+
+	// Destination values are named after the destination channels
+	values := map[string]interface{}{
+		"description": []interface{}{
+			map[string]interface{}{"locale": "en_US", "scope": "web", "data": "Web text"},
+			map[string]interface{}{"locale": "en_US", "scope": "ecommerce", "data": "Old text"},
+			map[string]interface{}{"locale": "fr_FR", "scope": "web", "data": "Texte"},
+		},
+		"erp_code": []interface{}{
+			map[string]interface{}{"locale": nil, "scope": nil, "data": "E-1"},
+		},
+	}
+
+	result := f.ApplyDest(values)
+
+	descriptions := result["description"].([]interface{})
+	if len(descriptions) != 1 || descriptions[0].(map[string]interface{})["scope"] != "web" {
+		t.Errorf("Expected only the en_US web description, got %v", descriptions)
+	}
+	if _, exists := result["erp_code"]; exists {
+		t.Error("Expected the excluded attribute to be left out")
+	}
+}
@@ -0,0 +1,109 @@
+package labels
+
+import "fmt"
+
+// Strategy defines how source labels are merged with labels already present in destination
+type Strategy string
+
+const (
+	// Overwrite sends the source labels, replacing destination translations for every source locale
+	Overwrite Strategy = "overwrite"
+	// Keep never touches the labels of items that already exist in destination
+	Keep Strategy = "keep"
+	// Union keeps destination translations and only adds locales missing in destination
+	Union Strategy = "union"
+)
+
+// ParseStrategy validates a strategy name; an empty name defaults to Overwrite
+func ParseStrategy(name string) (Strategy, error) {
+	switch Strategy(name) {
+	case "":
+		return Overwrite, nil
+	case Overwrite, Keep, Union:
+		return Strategy(name), nil
+	default:
+		return "", fmt.Errorf("invalid label merge strategy '%s' (expected overwrite, keep or union)", name)
+	}
+}
+
+// MergeLabels merges a per-locale label map such as {"en_US": "Shoes"}.
+// It returns the labels to send and false when the labels must not be sent at all.
+func MergeLabels(strategy Strategy, source, dest interface{}, destExists bool) (interface{}, bool) {
+	if !destExists || strategy == Overwrite || strategy == "" {
+		return source, true
+	}
+
+	if strategy == Keep {
+		return nil, false
+	}
+
+	merged := make(map[string]interface{})
+	if sourceLabels, ok := source.(map[string]interface{}); ok {
+		for locale, label := range sourceLabels {
+			merged[locale] = label
+		}
+	}
+	if destLabels, ok := dest.(map[string]interface{}); ok {
+		for locale, label := range destLabels {
+			if !isEmpty(label) {
+				merged[locale] = label
+			}
+		}
+	}
+
+	return merged, true
+}
+
+// MergeValues merges a localizable value list such as [{"locale": "en_US", "channel": null, "data": "Shoes"}].
+// It returns the values to send and false when the values must not be sent at all.
+func MergeValues(strategy Strategy, source, dest interface{}, destExists bool) (interface{}, bool) {
+	if !destExists || strategy == Overwrite || strategy == "" {
+		return source, true
+	}
+
+	if strategy == Keep {
+		return nil, false
+	}
+
+	merged := []interface{}{}
+	destKeys := make(map[string]bool)
+
+	if destValues, ok := dest.([]interface{}); ok {
+		for _, value := range destValues {
+			entry, ok := value.(map[string]interface{})
+			if !ok || isEmpty(entry["data"]) {
+				continue
+			}
+			destKeys[valueKey(entry)] = true
+			merged = append(merged, entry)
+		}
+	}
+
+	if sourceValues, ok := source.([]interface{}); ok {
+		for _, value := range sourceValues {
+			entry, ok := value.(map[string]interface{})
+			if !ok || destKeys[valueKey(entry)] {
+				continue
+			}
+			merged = append(merged, entry)
+		}
+	}
+
+	return merged, true
+}
+
+// valueKey identifies a value by its locale and channel
+func valueKey(entry map[string]interface{}) string {
+	return fmt.Sprintf("%v|%v", entry["locale"], entry["channel"])
+}
+
+// isEmpty reports whether a label has no translation
+func isEmpty(label interface{}) bool {
+	if label == nil {
+		return true
+	}
+	if text, ok := label.(string); ok {
+		return text == ""
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package labels
+
+import "testing"
+
+func TestParseStrategy(t *testing.T) {
+	strategy, err := ParseStrategy("")
+	if err != nil || strategy != Overwrite {
+		t.Errorf("Expected default strategy overwrite, got %s (%v)", strategy, err)
+	}
+
+	if _, err := ParseStrategy("merge"); err == nil {
+		t.Error("Expected error for unknown strategy, got nil")
+	}
+}
+
+func TestMergeLabels(t *testing.T) {
+	source := map[string]interface{}{"en_US": "Red", "fr_FR": "Rouge", "de_DE": "Rot"}
+	dest := map[string]interface{}{"fr_FR": "Rouge vif", "de_DE": ""}
+
+	merged, send := MergeLabels(Union, source, dest, true)
+	if !send {
+		t.Fatal("Expected labels to be sent with union strategy")
+	}
+
+	labels := merged.(map[string]interface{})
+	if labels["fr_FR"] != "Rouge vif" {
+		t.Errorf("Expected destination translation to win, got %v", labels["fr_FR"])
+	}
+	if labels["en_US"] != "Red" || labels["de_DE"] != "Rot" {
+		t.Errorf("Expected missing locales to come from source, got %v", labels)
+	}
+
+	if _, send := MergeLabels(Keep, source, dest, true); send {
+		t.Error("Expected labels not to be sent with keep strategy")
+	}
+
+	if _, send := MergeLabels(Keep, source, nil, false); !send {
+		t.Error("Expected labels to be sent for new items with keep strategy")
+	}
+
+	if merged, _ := MergeLabels(Overwrite, source, dest, true); merged.(map[string]interface{})["fr_FR"] != "Rouge" {
+		t.Error("Expected source translation with overwrite strategy")
+	}
+}
+
+func TestMergeValues(t *testing.T) {
+	source := []interface{}{
+		map[string]interface{}{"locale": "en_US", "channel": nil, "data": "Acme"},
+		map[string]interface{}{"locale": "fr_FR", "channel": nil, "data": "Acme FR"},
+	}
+	dest := []interface{}{
+		map[string]interface{}{"locale": "fr_FR", "channel": nil, "data": "Acmé"},
+	}
+
+	merged, send := MergeValues(Union, source, dest, true)
+	if !send {
+		t.Fatal("Expected values to be sent with union strategy")
+	}
+
+	values := merged.([]interface{})
+	if len(values) != 2 {
+		t.Fatalf("Expected 2 values, got %d", len(values))
+	}
+
+	for _, value := range values {
+		entry := value.(map[string]interface{})
+		if entry["locale"] == "fr_FR" && entry["data"] != "Acmé" {
+			t.Errorf("Expected destination translation to win, got %v", entry["data"])
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package limit
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrReached stops the stream of a bulk sync once the last item of its range has been read
+var ErrReached = errors.New("limit reached")
+
+// Range selects a slice of the items listed by a bulk sync, in the order source lists them, e.g.
+// the first 50 records of an entity for a trial run. The zero Range selects every item.
+type Range struct {
+	// Offset is the number of items skipped at the start of the list
+	Offset int
+	// Limit is the maximum number of items selected, no limit when 0
+	Limit int
+}
+
+// Validate checks that the offset and the limit are not negative
+func (r Range) Validate() error {
+	if r.Offset < 0 {
+		return fmt.Errorf("invalid offset %d, it must not be negative", r.Offset)
+	}
+	if r.Limit < 0 {
+		return fmt.Errorf("invalid limit %d, it must not be negative", r.Limit)
+	}
+	return nil
+}
+
+// IsZero reports whether the range selects every item
+func (r Range) IsZero() bool {
+	return r.Offset == 0 && r.Limit == 0
+}
+
+// String describes the range for messages, e.g. "items 51 to 100"
+func (r Range) String() string {
+	switch {
+	case r.Limit == 0:
+		return fmt.Sprintf("items after the first %d", r.Offset)
+	case r.Offset == 0:
+		return fmt.Sprintf("first %d items", r.Limit)
+	default:
+		return fmt.Sprintf("items %d to %d", r.Offset+1, r.Offset+r.Limit)
+	}
+}
+
+// Count returns the number of items selected in a list of total items
+func (r Range) Count(total int) int {
+	from, to := r.bounds(0, total)
+	return to - from
+}
+
+// bounds returns the indexes delimiting the range in a list of n items starting at position start
+func (r Range) bounds(start, n int) (int, int) {
+	from := clamp(r.Offset-start, n)
+	to := n
+	if r.Limit > 0 {
+		to = clamp(r.Offset+r.Limit-start, n)
+	}
+	if to < from {
+		to = from
+	}
+	return from, to
+}
+
+// clamp bounds i to [0, n]
+func clamp(i, n int) int {
+	return max(0, min(i, n))
+}
+
+// Slice returns the items of a list falling in the range
+func Slice[T any](r Range, items []T) []T {
+	from, to := r.bounds(0, len(items))
+	return items[from:to]
+}
+
+// Cursor tracks the position of a stream in a range
+type Cursor struct {
+	r    Range
+	read int
+}
+
+// Start returns a cursor at the start of a stream
+func (r Range) Start() *Cursor {
+	return &Cursor{r: r}
+}
+
+// Take returns the items of the next streamed batch falling in the range. Once the last item of the
+// range has been read, it also returns ErrReached, to be returned by the stream callback after the
+// items are processed so no further page is fetched.
+func Take[T any](c *Cursor, items []T) ([]T, error) {
+	from, to := c.r.bounds(c.read, len(items))
+	c.read += len(items)
+
+	if c.r.Limit > 0 && c.read >= c.r.Offset+c.r.Limit {
+		return items[from:to], ErrReached
+	}
+	return items[from:to], nil
+}
@@ -0,0 +1,77 @@
+package limit
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTake_SelectsTheRangeAcrossBatches(t *testing.T) {
+	cursor := Range{Offset: 3, Limit: 4}.Start()
+	var taken []int
+
+	batches := [][]int{{1, 2}, {3, 4, 5}, {6, 7, 8}, {9}}
+	for i, batch := range batches {
+		items, err := Take(cursor, batch)
+		taken = append(taken, items...)
+		if errors.Is(err, ErrReached) {
+			if i != 2 {
+				t.Errorf("Expected the range to be reached in batch 2, got %d", i)
+			}
+			break
+		}
+	}
+
+	if expected := []int{4, 5, 6, 7}; !reflect.DeepEqual(taken, expected) {
+		t.Errorf("Expected %v, got %v", expected, taken)
+	}
+}
+
+func TestTake_WithoutLimitNeverStops(t *testing.T) {
+	cursor := Range{Offset: 1}.Start()
+
+	first, err := Take(cursor, []string{"a", "b"})
+	if err != nil || !reflect.DeepEqual(first, []string{"b"}) {
+		t.Errorf("Expected [b] and no error, got %v and %v", first, err)
+	}
+	second, err := Take(cursor, []string{"c"})
+	if err != nil || !reflect.DeepEqual(second, []string{"c"}) {
+		t.Errorf("Expected [c] and no error, got %v and %v", second, err)
+	}
+}
+
+func TestSlice(t *testing.T) {
+	items := []string{"a", "b", "c", "d"}
+
+	tests := []struct {
+		name     string
+		r        Range
+		expected []string
+	}{
+		{"zero range", Range{}, items},
+		{"limit", Range{Limit: 2}, []string{"a", "b"}},
+		{"offset and limit", Range{Offset: 1, Limit: 2}, []string{"b", "c"}},
+		{"offset past the end", Range{Offset: 10, Limit: 2}, []string{}},
+		{"limit past the end", Range{Offset: 2, Limit: 10}, []string{"c", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slice(tt.r, items); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRange_Validate(t *testing.T) {
+	if err := (Range{Offset: -1}).Validate(); err == nil {
+		t.Error("Expected a negative offset to be rejected")
+	}
+	if err := (Range{Limit: -5}).Validate(); err == nil {
+		t.Error("Expected a negative limit to be rejected")
+	}
+	if err := (Range{Offset: 10, Limit: 50}).Validate(); err != nil {
+		t.Errorf("Expected a valid range, got %v", err)
+	}
+}
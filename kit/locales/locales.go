@@ -0,0 +1,142 @@
+package locales
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Policy defines what happens to values in locales that are not enabled in destination
+type Policy string
+
+const (
+	// Fail rejects the item before it is sent, listing the locales to enable
+	Fail Policy = "fail"
+	// Drop removes the values of those locales and writes the rest of the item
+	Drop Policy = "drop"
+)
+
+// ParsePolicy validates a policy name; an empty name defaults to Fail
+func ParsePolicy(name string) (Policy, error) {
+	switch Policy(name) {
+	case "":
+		return Fail, nil
+	case Fail, Drop:
+		return Policy(name), nil
+	default:
+		return "", fmt.Errorf("invalid disabled locales policy '%s' (expected fail or drop)", name)
+	}
+}
+
+// Loader returns the locale codes of an instance with their activation status
+type Loader func(ctx context.Context) (map[string]bool, error)
+
+// Checker validates the locales of Akeneo values against the locales enabled in destination.
+// The destination locales are loaded the first time values are checked, and again on the next
+// check when loading failed, so a transient error does not fail every item of the run.
+type Checker struct {
+	load   Loader
+	policy Policy
+
+	mu      sync.Mutex
+	enabled map[string]bool
+}
+
+// NewChecker creates a checker reading the destination locales with load
+func NewChecker(load Loader, policy Policy) *Checker {
+	return &Checker{load: load, policy: policy}
+}
+
+// Apply checks a values map ({attribute: [{locale, scope|channel, data}]}). It returns the values
+// to send and the locales that are not enabled in destination. With the Fail policy an error is
+// returned when such locales are found; with Drop their values are left out of a copy of the map.
+// The input is never modified, and a nil checker returns the values unchanged.
+func (c *Checker) Apply(ctx context.Context, values map[string]interface{}) (map[string]interface{}, []string, error) {
+	if c == nil || values == nil {
+		return values, nil, nil
+	}
+
+	enabled, err := c.locales(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error fetching destination locales: %w", err)
+	}
+
+	disabled := disabledLocales(enabled, values)
+	if len(disabled) == 0 {
+		return values, nil, nil
+	}
+
+	if c.policy != Drop {
+		return nil, disabled, fmt.Errorf("locales not enabled in destination: %s", strings.Join(disabled, ", "))
+	}
+
+	result := make(map[string]interface{}, len(values))
+	for attributeCode, entries := range values {
+		list, ok := entries.([]interface{})
+		if !ok {
+			result[attributeCode] = entries
+			continue
+		}
+
+		kept := make([]interface{}, 0, len(list))
+		for _, entry := range list {
+			if locale := entryLocale(entry); locale == "" || enabled[locale] {
+				kept = append(kept, entry)
+			}
+		}
+		if len(kept) > 0 {
+			result[attributeCode] = kept
+		}
+	}
+
+	return result, disabled, nil
+}
+
+// locales returns the destination locales, loading them unless a previous load succeeded
+func (c *Checker) locales(ctx context.Context) (map[string]bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.enabled == nil {
+		enabled, err := c.load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if enabled == nil {
+			enabled = map[string]bool{}
+		}
+		c.enabled = enabled
+	}
+
+	return c.enabled, nil
+}
+
+// disabledLocales returns the sorted locales of the values that are not enabled in destination
+func disabledLocales(enabled map[string]bool, values map[string]interface{}) []string {
+	found := make(map[string]bool)
+	for _, entries := range values {
+		list, _ := entries.([]interface{})
+		for _, entry := range list {
+			if locale := entryLocale(entry); locale != "" && !enabled[locale] {
+				found[locale] = true
+			}
+		}
+	}
+
+	disabled := make([]string, 0, len(found))
+	for locale := range found {
+		disabled = append(disabled, locale)
+	}
+	sort.Strings(disabled)
+
+	return disabled
+}
+
+// entryLocale returns the locale of a value, or "" when the value is not localizable
+func entryLocale(entry interface{}) string {
+	value, _ := entry.(map[string]interface{})
+	locale, _ := value["locale"].(string)
+	return locale
+}
@@ -0,0 +1,131 @@
+package locales
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func destLocales(calls *int) Loader {
+	return func(ctx context.Context) (map[string]bool, error) {
+		*calls++
+		return map[string]bool{"en_US": true, "fr_FR": true, "de_DE": false}, nil
+	}
+}
+
+func newValues() map[string]interface{} {
+	return map[string]interface{}{
+		"name": []interface{}{
+			map[string]interface{}{"locale": "en_US", "scope": nil, "data": "Shoe"},
+			map[string]interface{}{"locale": "de_DE", "scope": nil, "data": "Schuh"},
+		},
+		"subtitle": []interface{}{
+			map[string]interface{}{"locale": "it_IT", "scope": nil, "data": "Scarpa"},
+		},
+		"weight": []interface{}{
+			map[string]interface{}{"locale": nil, "scope": nil, "data": "12"},
+		},
+	}
+}
+
+func TestChecker_FailListsDisabledLocales(t *testing.T) {
+	calls := 0
+	checker := NewChecker(destLocales(&calls), Fail)
+
+	_, disabled, err := checker.Apply(context.Background(), newValues())
+
+	if err == nil {
+		t.Fatal("Expected an error for disabled locales")
+	}
+	if len(disabled) != 2 || disabled[0] != "de_DE" || disabled[1] != "it_IT" {
+		t.Errorf("Expected de_DE and it_IT, got %v", disabled)
+	}
+}
+
+func TestChecker_DropRemovesDisabledValues(t *testing.T) {
+	calls := 0
+	checker := NewChecker(destLocales(&calls), Drop)
+	values := newValues()
+
+	result, disabled, err := checker.Apply(context.Background(), values)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(disabled) != 2 {
+		t.Errorf("Expected 2 disabled locales, got %v", disabled)
+	}
+	if names := result["name"].([]interface{}); len(names) != 1 {
+		t.Errorf("Expected only the en_US name to be kept, got %v", names)
+	}
+	if _, exists := result["subtitle"]; exists {
+		t.Error("Expected an attribute without enabled values to be dropped")
+	}
+	if _, exists := result["weight"]; !exists {
+		t.Error("Expected non-localizable values to be kept")
+	}
+	if len(values["name"].([]interface{})) != 2 {
+		t.Error("Expected the input values not to be modified")
+	}
+
+	// Destination locales are loaded once
+	_, _, _ = checker.Apply(context.Background(), newValues())
+	if calls != 1 {
+		t.Errorf("Expected destination locales to be loaded once, got %d", calls)
+	}
+}
+
+func TestChecker_LoadError(t *testing.T) {
+	checker := NewChecker(func(ctx context.Context) (map[string]bool, error) {
+		return nil, errors.New("unavailable")
+	}, Drop)
+
+	if _, _, err := checker.Apply(context.Background(), newValues()); err == nil {
+		t.Error("Expected the load error to be returned")
+	}
+}
+
+func TestChecker_RetriesFailedLoad(t *testing.T) {
+	calls := 0
+	load := destLocales(&calls)
+	failed := false
+	checker := NewChecker(func(ctx context.Context) (map[string]bool, error) {
+		if !failed {
+			failed = true
+			return nil, errors.New("unavailable")
+		}
+		return load(ctx)
+	}, Drop)
+
+	if _, _, err := checker.Apply(context.Background(), newValues()); err == nil {
+		t.Fatal("Expected the load error to be returned")
+	}
+	if _, _, err := checker.Apply(context.Background(), newValues()); err != nil {
+		t.Fatalf("Expected the locales to be loaded again, got %v", err)
+	}
+	if _, _, err := checker.Apply(context.Background(), newValues()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected the successful load to be kept, got %d loads", calls)
+	}
+}
+
+func TestChecker_NilChecker(t *testing.T) {
+	var checker *Checker
+	values := newValues()
+
+	result, disabled, err := checker.Apply(context.Background(), values)
+	if err != nil || disabled != nil || len(result) != len(values) {
+		t.Errorf("Expected values unchanged, got %v %v %v", result, disabled, err)
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	if policy, err := ParsePolicy(""); err != nil || policy != Fail {
+		t.Errorf("Expected fail by default, got %s (%v)", policy, err)
+	}
+	if _, err := ParsePolicy("ignore"); err == nil {
+		t.Error("Expected an error for an unknown policy")
+	}
+}
@@ -0,0 +1,86 @@
+package locales
+
+// Mapper renames locale codes in Akeneo data, e.g. en_GB in source → en_US in destination
+type Mapper struct {
+	codes map[string]string
+}
+
+// NewMapper creates a mapper from a source → destination locale code mapping
+func NewMapper(codes map[string]string) *Mapper {
+	return &Mapper{codes: codes}
+}
+
+// Enabled reports whether the mapper renames anything; a nil mapper keeps every code
+func (m *Mapper) Enabled() bool {
+	return m != nil && len(m.codes) > 0
+}
+
+// Apply returns a copy of decoded JSON data with the mapped locale codes renamed wherever Akeneo
+// uses locales: the "locale" of values, "locales" and "available_locales" lists, and the keys of
+// translation maps such as labels. The input is never modified.
+func (m *Mapper) Apply(data interface{}) interface{} {
+	if !m.Enabled() {
+		return data
+	}
+	return m.apply(data)
+}
+
+// apply walks a JSON value and returns its renamed copy
+func (m *Mapper) apply(data interface{}) interface{} {
+	switch typed := data.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for key, value := range typed {
+			switch key {
+			case "locale":
+				value = m.rename(value)
+			case "locales", "available_locales":
+				value = m.renameList(value)
+			default:
+				value = m.apply(value)
+			}
+			result[m.renameKey(key)] = value
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(typed))
+		for i, value := range typed {
+			result[i] = m.apply(value)
+		}
+		return result
+	default:
+		return data
+	}
+}
+
+// rename returns the destination code of a locale code, other values unchanged
+func (m *Mapper) rename(value interface{}) interface{} {
+	if code, ok := value.(string); ok {
+		if mapped, exists := m.codes[code]; exists {
+			return mapped
+		}
+	}
+	return value
+}
+
+// renameList renames the codes of a list of locales
+func (m *Mapper) renameList(value interface{}) interface{} {
+	list, ok := value.([]interface{})
+	if !ok {
+		return m.apply(value)
+	}
+
+	result := make([]interface{}, len(list))
+	for i, code := range list {
+		result[i] = m.rename(code)
+	}
+	return result
+}
+
+// renameKey renames a map key that is a mapped locale code, as in translation maps
+func (m *Mapper) renameKey(key string) string {
+	if mapped, exists := m.codes[key]; exists {
+		return mapped
+	}
+	return key
+}
@@ -0,0 +1,51 @@
+package locales
+
+import "testing"
+
+func TestMapper_RenamesLocales(t *testing.T) {
+	mapper := NewMapper(map[string]string{"en_GB": "en_US"})
+
+	product := map[string]interface{}{
+		"identifier": "SKU-1",
+		"values": map[string]interface{}{
+			"name":   []interface{}{map[string]interface{}{"locale": "en_GB", "scope": nil, "data": "Trainer"}},
+			"weight": []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "12"}},
+		},
+	}
+	channel := map[string]interface{}{
+		"code":    "ecommerce",
+		"locales": []interface{}{"en_GB", "fr_FR"},
+		"labels":  map[string]interface{}{"en_GB": "E-commerce", "fr_FR": "E-commerce"},
+	}
+
+	mappedProduct := mapper.Apply(product).(map[string]interface{})
+	name := mappedProduct["values"].(map[string]interface{})["name"].([]interface{})[0].(map[string]interface{})
+	if name["locale"] != "en_US" || name["data"] != "Trainer" {
+		t.Errorf("Expected the name to be in en_US, got %v", name)
+	}
+
+	mappedChannel := mapper.Apply(channel).(map[string]interface{})
+	if locales := mappedChannel["locales"].([]interface{}); locales[0] != "en_US" || locales[1] != "fr_FR" {
+		t.Errorf("Expected en_GB to be renamed in the channel locales, got %v", locales)
+	}
+	labels := mappedChannel["labels"].(map[string]interface{})
+	if _, exists := labels["en_GB"]; exists || labels["en_US"] != "E-commerce" {
+		t.Errorf("Expected the en_GB label to be renamed, got %v", labels)
+	}
+
+	if channel["locales"].([]interface{})[0] != "en_GB" {
+		t.Error("Expected input data to be untouched")
+	}
+}
+
+func TestMapper_Disabled(t *testing.T) {
+	var mapper *Mapper
+	data := map[string]interface{}{"locale": "en_GB"}
+
+	if mapper.Enabled() || NewMapper(nil).Enabled() {
+		t.Error("Expected mappers without codes to be disabled")
+	}
+	if mapped := mapper.Apply(data).(map[string]interface{}); mapped["locale"] != "en_GB" {
+		t.Errorf("Expected data to be unchanged, got %v", mapped)
+	}
+}
@@ -0,0 +1,245 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Level is the severity of a message
+type Level int
+
+const (
+	// Debug messages detail the work of a sync, e.g. the children found in a hierarchy
+	Debug Level = iota
+	// Info messages report the normal progress of a sync
+	Info
+	// Warn messages report data that was dropped or items that could not be synced
+	Warn
+	// Error messages report failures of a whole command
+	Error
+)
+
+// String returns the name of the level, as written in JSON messages
+func (l Level) String() string {
+	switch l {
+	case Debug:
+		return "debug"
+	case Info:
+		return "info"
+	case Warn:
+		return "warn"
+	case Error:
+		return "error"
+	default:
+		return "level(" + strconv.Itoa(int(l)) + ")"
+	}
+}
+
+// Format is the encoding of the messages
+type Format string
+
+const (
+	// Console writes the message as is, followed by its fields as key=value pairs
+	Console Format = "console"
+	// JSON writes one JSON object per message, with its time, level, message and fields
+	JSON Format = "json"
+)
+
+// ParseFormat returns the format named s
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case Console, JSON:
+		return Format(s), nil
+	default:
+		return "", fmt.Errorf("invalid log format %q, expected %s or %s", s, Console, JSON)
+	}
+}
+
+// Field is a value attached to a message, e.g. the identifier of the product being synced
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// F returns a field
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err returns the field of an error
+func Err(err error) Field {
+	return Field{Key: "error", Value: err}
+}
+
+// output is shared by a logger and the loggers derived from it, so their lines are never interleaved
+type output struct {
+	mu     sync.Mutex
+	w      io.Writer
+	format Format
+	now    func() time.Time
+}
+
+// Logger writes leveled messages with fields. It is safe for concurrent use, e.g. by the workers of a sync.
+type Logger struct {
+	out    *output
+	level  Level
+	fields []Field
+}
+
+// New creates a logger writing the messages of level and above to w
+func New(w io.Writer, level Level, format Format) *Logger {
+	return &Logger{out: &output{w: w, format: format, now: time.Now}, level: level}
+}
+
+// Default returns the logger used by services created without one: info messages written to
+// the standard output of the moment, so it follows a redirection of os.Stdout
+func Default() *Logger {
+	return New(stdout{}, Info, Console)
+}
+
+// Discard returns a logger dropping every message
+func Discard() *Logger {
+	return New(io.Discard, Error+1, Console)
+}
+
+// stdout writes to the current os.Stdout
+type stdout struct{}
+
+func (stdout) Write(p []byte) (int, error) {
+	return os.Stdout.Write(p)
+}
+
+// With returns a logger adding fields to every message
+func (l *Logger) With(fields ...Field) *Logger {
+	derived := *l
+	derived.fields = append(append([]Field(nil), l.fields...), fields...)
+	return &derived
+}
+
+// AtLevel returns a logger writing the messages of level and above to the same output
+func (l *Logger) AtLevel(level Level) *Logger {
+	derived := *l
+	derived.level = level
+	return &derived
+}
+
+// Enabled reports whether the messages of level are written, so costly fields are only built when needed
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.level
+}
+
+// Debug writes a debug message
+func (l *Logger) Debug(msg string, fields ...Field) {
+	l.Log(Debug, msg, fields...)
+}
+
+// Info writes an info message
+func (l *Logger) Info(msg string, fields ...Field) {
+	l.Log(Info, msg, fields...)
+}
+
+// Warn writes a warning
+func (l *Logger) Warn(msg string, fields ...Field) {
+	l.Log(Warn, msg, fields...)
+}
+
+// Error writes an error message
+func (l *Logger) Error(msg string, fields ...Field) {
+	l.Log(Error, msg, fields...)
+}
+
+// Log writes a message of level when it is enabled
+func (l *Logger) Log(level Level, msg string, fields ...Field) {
+	if !l.Enabled(level) {
+		return
+	}
+	if len(l.fields) > 0 {
+		fields = append(append([]Field(nil), l.fields...), fields...)
+	}
+
+	var line []byte
+	if l.out.format == JSON {
+		line = encodeJSON(l.out.now(), level, msg, fields)
+	} else {
+		line = encodeConsole(msg, fields)
+	}
+
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	l.out.w.Write(line) //nolint:errcheck // a message that cannot be written is not worth failing a sync
+}
+
+// encodeConsole writes the message followed by its fields, e.g. "   ✅ Synced product identifier=SKU-1"
+func encodeConsole(msg string, fields []Field) []byte {
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, field := range fields {
+		b.WriteByte(' ')
+		b.WriteString(field.Key)
+		b.WriteByte('=')
+		b.WriteString(quote(fmt.Sprint(value(field.Value))))
+	}
+	b.WriteByte('\n')
+	return []byte(b.String())
+}
+
+// quote quotes values that would not be read back as a single value
+func quote(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// encodeJSON writes the message as a JSON object. The indentation and emoji of console messages
+// are left out of the msg key.
+func encodeJSON(at time.Time, level Level, msg string, fields []Field) []byte {
+	var b strings.Builder
+	b.WriteString(`{"time":`)
+	writeJSON(&b, at.Format(time.RFC3339Nano))
+	b.WriteString(`,"level":`)
+	writeJSON(&b, level.String())
+	b.WriteString(`,"msg":`)
+	writeJSON(&b, strings.TrimLeftFunc(msg, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}))
+	for _, field := range fields {
+		b.WriteByte(',')
+		writeJSON(&b, field.Key)
+		b.WriteByte(':')
+		writeJSON(&b, value(field.Value))
+	}
+	b.WriteString("}\n")
+	return []byte(b.String())
+}
+
+// writeJSON writes v as JSON, or as a string when it cannot be encoded
+func writeJSON(b *strings.Builder, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(v)) //nolint:errcheck // strings are always encoded
+	}
+	b.Write(data)
+}
+
+// value returns the form of a field value written in messages: errors and values with a String
+// method, such as durations, are written as text
+func value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return v
+	}
+}
@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLogger_FiltersMessagesBelowItsLevel(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(&buf, Warn, Console)
+
+	log.Debug("debug")
+	log.Info("info")
+	log.Warn("warn")
+	log.Error("error")
+
+	if expected := "warn\nerror\n"; buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestLogger_ConsoleWritesFieldsAfterTheMessage(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(&buf, Info, Console).With(F("command", "sync_product"))
+
+	log.Warn("   ⚠️  Error syncing product", F("identifier", "SKU-1"), Err(errors.New("not found")))
+
+	expected := "   ⚠️  Error syncing product command=sync_product identifier=SKU-1 error=\"not found\"\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestLogger_JSONWritesOneObjectPerMessage(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(&buf, Debug, JSON)
+	log.out.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	log.Info("   ✅ Synced model", F("code", "shoe"), F("took", 2*time.Second), F("children", 3))
+
+	var message map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &message); err != nil {
+		t.Fatalf("Expected a JSON object, got %q: %v", buf.String(), err)
+	}
+	expected := map[string]interface{}{
+		"time":     "2024-01-02T03:04:05Z",
+		"level":    "info",
+		"msg":      "Synced model",
+		"code":     "shoe",
+		"took":     "2s",
+		"children": float64(3),
+	}
+	for key, value := range expected {
+		if message[key] != value {
+			t.Errorf("Expected %s to be %v, got %v", key, value, message[key])
+		}
+	}
+}
+
+func TestLogger_WithDoesNotChangeTheParent(t *testing.T) {
+	var buf bytes.Buffer
+	parent := New(&buf, Info, Console)
+	parent.With(F("a", 1))
+
+	parent.Info("msg")
+
+	if expected := "msg\n"; buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if format, err := ParseFormat("json"); err != nil || format != JSON {
+		t.Errorf("Expected json, got %v and %v", format, err)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("Expected an unknown format to be rejected")
+	}
+}
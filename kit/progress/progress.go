@@ -0,0 +1,138 @@
+package progress
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Reporter receives the progress of the syncs running in a context
+type Reporter interface {
+	// Expect adds items to the number of items the syncs will process
+	Expect(items int)
+	// Done adds items to the number of items processed so far
+	Done(items int)
+}
+
+type reporterKey struct{}
+
+// With returns a context in which syncs report their progress to reporter
+func With(ctx context.Context, reporter Reporter) context.Context {
+	return context.WithValue(ctx, reporterKey{}, reporter)
+}
+
+// Expect adds items to the total of the reporter of the context, if any. Syncs call it once they
+// know how many items they will process, e.g. from the items_count of a list endpoint.
+func Expect(ctx context.Context, items int) {
+	if reporter, ok := ctx.Value(reporterKey{}).(Reporter); ok && items > 0 {
+		reporter.Expect(items)
+	}
+}
+
+// Done reports processed items, written or failed, to the reporter of the context, if any
+func Done(ctx context.Context, items int) {
+	if reporter, ok := ctx.Value(reporterKey{}).(Reporter); ok && items > 0 {
+		reporter.Done(items)
+	}
+}
+
+// barWidth is the number of characters of the bar
+const barWidth = 30
+
+// Bar prints the progress reported by syncs as lines with a bar, the processing rate and the
+// estimated time left. Lines are printed at most once per interval, so they can be mixed with the
+// messages of the syncs and streamed to the web UI. Without a known total, the bar is left out and
+// only the number of items and the rate are printed.
+type Bar struct {
+	w        io.Writer
+	interval time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	total     int
+	done      int
+	startedAt time.Time
+	printedAt time.Time
+	printed   int
+}
+
+// NewBar creates a bar printing to w at most once per interval
+func NewBar(w io.Writer, interval time.Duration) *Bar {
+	return &Bar{w: w, interval: interval, now: time.Now}
+}
+
+// Expect adds items to the total of the bar
+func (b *Bar) Expect(items int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.start(b.now())
+	b.total += items
+}
+
+// Done adds processed items and prints the progress when the interval has elapsed
+func (b *Bar) Done(items int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.start(now)
+	b.done += items
+
+	if now.Sub(b.printedAt) >= b.interval {
+		b.print(now)
+	}
+}
+
+// Finish prints the final progress of syncs long enough to have printed their progress, unless
+// it was already printed
+func (b *Bar) Finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.printed > 0 && b.done != b.printed {
+		b.print(b.now())
+	}
+}
+
+// start starts measuring the rate when the first progress is reported; the caller holds the lock
+func (b *Bar) start(now time.Time) {
+	if b.startedAt.IsZero() {
+		b.startedAt = now
+		b.printedAt = now
+	}
+}
+
+// print writes a progress line; the caller holds the lock
+func (b *Bar) print(now time.Time) {
+	b.printedAt = now
+	b.printed = b.done
+	_, _ = fmt.Fprintf(b.w, "   ⏳ %s\n", b.line(now.Sub(b.startedAt)))
+}
+
+// line formats the progress after elapsed time, e.g.
+// [#########.....................] 1500/5000 (30%) · 250 items/s · ETA 14s
+func (b *Bar) line(elapsed time.Duration) string {
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(b.done) / elapsed.Seconds()
+	}
+
+	// Totals may be estimates, e.g. when items are added while the sync runs
+	if b.total < b.done {
+		return fmt.Sprintf("%d items · %.0f items/s", b.done, rate)
+	}
+
+	filled := barWidth * b.done / b.total
+	line := fmt.Sprintf("[%s%s] %d/%d (%d%%) · %.0f items/s",
+		strings.Repeat("#", filled), strings.Repeat(".", barWidth-filled),
+		b.done, b.total, 100*b.done/b.total, rate)
+	if rate > 0 && b.done < b.total {
+		eta := time.Duration(float64(b.total-b.done) / rate * float64(time.Second))
+		line += " · ETA " + eta.Round(time.Second).String()
+	}
+	return line
+}
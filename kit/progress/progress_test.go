@@ -0,0 +1,77 @@
+package progress
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBar_PrintsRateAndETA(t *testing.T) {
+	var out bytes.Buffer
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bar := NewBar(&out, time.Second)
+	bar.now = func() time.Time { return clock }
+
+	ctx := With(context.Background(), bar)
+	Expect(ctx, 1000)
+	Done(ctx, 100)
+
+	// Lines are throttled
+	clock = clock.Add(500 * time.Millisecond)
+	Done(ctx, 100)
+	if out.Len() != 0 {
+		t.Fatalf("Expected nothing printed before the interval, got %q", out.String())
+	}
+
+	clock = clock.Add(1500 * time.Millisecond)
+	Done(ctx, 200)
+	expected := "[############..................] 400/1000 (40%) · 200 items/s · ETA 3s"
+	if !strings.Contains(out.String(), expected) {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+
+	bar.Finish()
+	if lines := strings.Count(out.String(), "\n"); lines != 1 {
+		t.Errorf("Expected the last progress not to be printed twice, got %q", out.String())
+	}
+}
+
+func TestBar_WithoutTotal(t *testing.T) {
+	var out bytes.Buffer
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bar := NewBar(&out, 10*time.Second)
+	bar.now = func() time.Time { return clock }
+
+	bar.Done(50)
+	clock = clock.Add(10 * time.Second)
+	bar.Done(50)
+	clock = clock.Add(5 * time.Second)
+	bar.Done(50)
+	bar.Finish()
+
+	expected := "   ⏳ 100 items · 10 items/s\n   ⏳ 150 items · 10 items/s\n"
+	if out.String() != expected {
+		t.Errorf("Expected the items and rate only, got %q", out.String())
+	}
+}
+
+func TestBar_ShortSyncsPrintNothing(t *testing.T) {
+	var out bytes.Buffer
+	bar := NewBar(&out, time.Minute)
+
+	bar.Expect(2)
+	bar.Done(2)
+	bar.Finish()
+
+	if out.Len() != 0 {
+		t.Errorf("Expected nothing printed, got %q", out.String())
+	}
+}
+
+func TestDone_WithoutReporter(t *testing.T) {
+	// Syncs report their progress whether or not a reporter is set
+	Expect(context.Background(), 10)
+	Done(context.Background(), 10)
+}
@@ -0,0 +1,25 @@
+package prune
+
+import (
+	"context"
+	"sort"
+)
+
+// Confirm is asked before the destination items missing from source are deleted, with their kind,
+// their scope (e.g. the reference entity of records) and their codes. Nothing is deleted unless it returns true.
+type Confirm func(ctx context.Context, kind, scope string, codes []string) bool
+
+// Missing returns the destination codes that are not in source, sorted
+func Missing(source map[string]bool, dest []string) []string {
+	missing := make([]string, 0)
+	seen := make(map[string]bool, len(dest))
+	for _, code := range dest {
+		if !source[code] && !seen[code] {
+			seen[code] = true
+			missing = append(missing, code)
+		}
+	}
+
+	sort.Strings(missing)
+	return missing
+}
@@ -0,0 +1,27 @@
+package prune_test
+
+import (
+	"reflect"
+	"testing"
+
+	"akeneo-migrator/kit/prune"
+)
+
+func TestMissing(t *testing.T) {
+	source := map[string]bool{"acme": true, "globex": true}
+	dest := []string{"initech", "acme", "globex", "hooli", "initech"}
+
+	missing := prune.Missing(source, dest)
+
+	if !reflect.DeepEqual(missing, []string{"hooli", "initech"}) {
+		t.Errorf("Expected the destination-only codes once and sorted, got %v", missing)
+	}
+}
+
+func TestMissing_NothingToPrune(t *testing.T) {
+	missing := prune.Missing(map[string]bool{"acme": true}, []string{"acme"})
+
+	if missing == nil || len(missing) != 0 {
+		t.Errorf("Expected an empty list, got %#v", missing)
+	}
+}
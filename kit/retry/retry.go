@@ -0,0 +1,66 @@
+package retry
+
+import (
+	"context"
+
+	"akeneo-migrator/kit/bus"
+)
+
+// Failure is an item that could not be synchronized and can be retried later
+type Failure struct {
+	// Kind is the type of item: product, product_model, record, attribute, category, family, family_variants, channel...
+	Kind string `json:"kind"`
+	// Scope is the parent of the item when its code is not unique on its own (e.g. the reference entity of a record)
+	Scope string `json:"scope,omitempty"`
+	Code  string `json:"code"`
+	Error string `json:"error"`
+}
+
+// Reporter is implemented by sync results that can list the items that failed
+type Reporter interface {
+	Failures() []Failure
+}
+
+// Item is implemented by commands targeting a single item, so the item can be queued
+// when the whole command fails
+type Item interface {
+	RetryItem() Failure
+}
+
+// RecordFunc stores the failures of a command and returns the identifier of the stored job
+type RecordFunc func(ctx context.Context, command bus.Type, failures []Failure) (string, error)
+
+type contextKey struct{}
+
+// WithoutRecording returns a context in which failures are not queued, used while
+// failures are being retried so they are collected by the retry itself
+func WithoutRecording(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, true)
+}
+
+// RecordingDisabled reports whether failures must not be queued in this context
+func RecordingDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(contextKey{}).(bool)
+	return disabled
+}
+
+// Collect returns the failures of a dispatched command: those reported by its result or,
+// when the command failed as a whole, the item targeted by the command
+func Collect(msg bus.Message, response bus.Response, err error) []Failure {
+	if reporter, ok := response.Data.(Reporter); ok {
+		return reporter.Failures()
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	item, ok := msg.(Item)
+	if !ok {
+		return nil
+	}
+
+	failure := item.RetryItem()
+	failure.Error = err.Error()
+	return []Failure{failure}
+}
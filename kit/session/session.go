@@ -0,0 +1,212 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"akeneo-migrator/kit/retry"
+)
+
+// Counter is implemented by sync results that can report how many items they wrote
+type Counter interface {
+	Synced() int
+}
+
+// Totals are the combined counters of a session or a step
+type Totals struct {
+	Steps  int `json:"steps"`
+	Synced int `json:"synced"`
+	Failed int `json:"failed"`
+	Errors int `json:"errors"`
+}
+
+// Step is one command executed during a session
+type Step struct {
+	Command   string        `json:"command"`
+	StartedAt time.Time     `json:"startedAt"`
+	Duration  time.Duration `json:"duration"`
+	Synced    int           `json:"synced"`
+	Failed    int           `json:"failed"`
+	Error     string        `json:"error,omitempty"`
+	// Failures are the items of the step that could not be written
+	Failures []retry.Failure `json:"failures,omitempty"`
+	// Result is the result returned by the command, kept for the machine-readable report
+	Result interface{} `json:"result,omitempty"`
+}
+
+// Session aggregates the results of every step executed in one invocation
+type Session struct {
+	mu sync.Mutex
+	// ID identifies the run, e.g. in the correlation header of the API requests
+	ID         string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Steps      []Step
+}
+
+// New starts a session
+func New() *Session {
+	return &Session{
+		ID:        newID(),
+		StartedAt: time.Now().UTC(),
+		Steps:     make([]Step, 0),
+	}
+}
+
+// newID generates a random run identifier
+func newID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return time.Now().UTC().Format("20060102T150405.000000000")
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// Record adds a step to the session
+func (s *Session) Record(step Step) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Steps = append(s.Steps, step)
+}
+
+// Len returns the number of recorded steps
+func (s *Session) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.Steps)
+}
+
+// Totals combines the counters of all steps
+func (s *Session) Totals() Totals {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	totals := Totals{Steps: len(s.Steps)}
+	for _, step := range s.Steps {
+		totals.Synced += step.Synced
+		totals.Failed += step.Failed
+		if step.Error != "" {
+			totals.Errors++
+		}
+	}
+	return totals
+}
+
+// Finish marks the end of the session
+func (s *Session) Finish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.FinishedAt = time.Now().UTC()
+}
+
+// report is the machine-readable representation of a session
+type report struct {
+	ID         string    `json:"id"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	Totals     Totals    `json:"totals"`
+	Steps      []Step    `json:"steps"`
+}
+
+// marshal encodes the session report: its totals and every step with its result
+func (s *Session) marshal() ([]byte, error) {
+	totals := s.Totals()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.MarshalIndent(report{
+		ID:         s.ID,
+		StartedAt:  s.StartedAt,
+		FinishedAt: s.FinishedAt,
+		Totals:     totals,
+		Steps:      s.Steps,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding session report: %w", err)
+	}
+	return data, nil
+}
+
+// Encode writes the session report to w, e.g. to stdout for scripts reading the result of a run
+func (s *Session) Encode(w io.Writer) error {
+	data, err := s.marshal()
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "%s\n", data); err != nil {
+		return fmt.Errorf("error writing session report: %w", err)
+	}
+	return nil
+}
+
+// WriteJSON writes the session report to a file, creating its directory if needed
+func (s *Session) WriteJSON(path string) error {
+	data, err := s.marshal()
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("error creating report directory: %w", err)
+		}
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("error writing session report: %w", err)
+	}
+
+	return nil
+}
+
+// Print writes a human-readable summary with a line per step and the combined totals
+func (s *Session) Print(w io.Writer) {
+	totals := s.Totals()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, _ = fmt.Fprintf(w, "\n🧾 Session summary (run %s):\n", s.ID)
+	for i, step := range s.Steps {
+		status := "✅"
+		if step.Error != "" || step.Failed > 0 {
+			status = "⚠️ "
+		}
+		_, _ = fmt.Fprintf(w, "   %s %2d. %-32s synced: %-6d failed: %-6d (%v)\n",
+			status, i+1, step.Command, step.Synced, step.Failed, step.Duration.Round(time.Millisecond))
+		if step.Error != "" {
+			_, _ = fmt.Fprintf(w, "         error: %s\n", step.Error)
+		}
+	}
+	_, _ = fmt.Fprintf(w, "   📊 Total: %d steps, %d synced, %d failed, %d steps with errors\n",
+		totals.Steps, totals.Synced, totals.Failed, totals.Errors)
+}
+
+type scopeKey struct{}
+
+// scope tracks whether a step dispatched nested steps
+type scope struct {
+	nested bool
+}
+
+// Enter returns the context in which a step runs and a function reporting, once the step is
+// done, whether it dispatched nested steps. Orchestrating commands are then left out of the
+// session in favour of the steps they ran.
+func Enter(ctx context.Context) (context.Context, func() bool) {
+	if parent, ok := ctx.Value(scopeKey{}).(*scope); ok {
+		parent.nested = true
+	}
+
+	current := &scope{}
+	return context.WithValue(ctx, scopeKey{}, current), func() bool { return current.nested }
+}
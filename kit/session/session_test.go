@@ -0,0 +1,110 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"akeneo-migrator/kit/retry"
+)
+
+func TestSession_Totals(t *testing.T) {
+	s := New()
+	s.Record(Step{Command: "family.sync", Synced: 3})
+	s.Record(Step{Command: "product.sync", Synced: 10, Failed: 2})
+	s.Record(Step{Command: "channel.sync", Error: "missing locale"})
+
+	totals := s.Totals()
+
+	if totals.Steps != 3 || totals.Synced != 13 || totals.Failed != 2 || totals.Errors != 1 {
+		t.Errorf("Unexpected totals: %+v", totals)
+	}
+}
+
+func TestSession_WriteJSON(t *testing.T) {
+	s := New()
+	s.Record(Step{Command: "family.sync", Synced: 3, Result: map[string]interface{}{"Code": "shoes"}})
+	s.Finish()
+
+	path := filepath.Join(t.TempDir(), "reports", "session.json")
+	if err := s.WriteJSON(path); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected report file, got %v", err)
+	}
+
+	var decoded struct {
+		ID     string `json:"id"`
+		Totals Totals `json:"totals"`
+		Steps  []Step `json:"steps"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+
+	if decoded.ID != s.ID || decoded.Totals.Synced != 3 || len(decoded.Steps) != 1 || decoded.Steps[0].Command != "family.sync" {
+		t.Errorf("Unexpected report: %s", data)
+	}
+}
+
+func TestSession_Encode(t *testing.T) {
+	s := New()
+	s.Record(Step{
+		Command:  "product.sync",
+		Synced:   2,
+		Failed:   1,
+		Failures: []retry.Failure{{Kind: "product", Code: "SKU-1", Error: "validation error"}},
+	})
+	s.Finish()
+
+	var out bytes.Buffer
+	if err := s.Encode(&out); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var decoded struct {
+		Totals Totals `json:"totals"`
+		Steps  []Step `json:"steps"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("Expected a single JSON document, got %v:\n%s", err, out.String())
+	}
+
+	if decoded.Totals.Failed != 1 || len(decoded.Steps) != 1 || len(decoded.Steps[0].Failures) != 1 || decoded.Steps[0].Failures[0].Code != "SKU-1" {
+		t.Errorf("Expected the failures of the step to be encoded, got %s", out.String())
+	}
+}
+
+func TestSession_Print(t *testing.T) {
+	s := New()
+	s.Record(Step{Command: "family.sync", Synced: 3})
+	s.Record(Step{Command: "product.sync", Error: "boom"})
+
+	var out bytes.Buffer
+	s.Print(&out)
+
+	for _, expected := range []string{"run " + s.ID, "family.sync", "product.sync", "error: boom", "2 steps, 3 synced"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected summary to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+}
+
+func TestEnter_ReportsNestedSteps(t *testing.T) {
+	parentCtx, parentNested := Enter(context.Background())
+	_, childNested := Enter(parentCtx)
+
+	if !parentNested() {
+		t.Error("Expected parent step to report nested steps")
+	}
+	if childNested() {
+		t.Error("Expected leaf step not to report nested steps")
+	}
+}
@@ -0,0 +1,204 @@
+package transform
+
+import (
+	"fmt"
+
+	"akeneo-migrator/kit/expr"
+)
+
+// Rule computes the data of an attribute with an expression
+type Rule struct {
+	Attribute string
+	// Expression computes the new data
+	Expression string
+	// When is an optional condition; the rule is skipped when it evaluates to false
+	When string
+	// Locale and Scope restrict the rule to the values in this locale and channel; empty matches any
+	Locale string
+	Scope  string
+}
+
+type compiledRule struct {
+	attribute  string
+	expression *expr.Program
+	when       *expr.Program
+	locale     string
+	scope      string
+}
+
+// Transformer applies expression rules to the values of Akeneo items
+type Transformer struct {
+	rules []compiledRule
+	// channelKey is the field holding the channel of a value: "scope" for products, "channel" for records
+	channelKey string
+}
+
+// New compiles the rules and creates a transformer
+func New(rules []Rule) (*Transformer, error) {
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Attribute == "" {
+			return nil, fmt.Errorf("transformation rule without attribute")
+		}
+
+		expression, err := expr.Compile(rule.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("attribute '%s': %w", rule.Attribute, err)
+		}
+
+		var when *expr.Program
+		if rule.When != "" {
+			if when, err = expr.Compile(rule.When); err != nil {
+				return nil, fmt.Errorf("attribute '%s': %w", rule.Attribute, err)
+			}
+		}
+
+		compiled = append(compiled, compiledRule{
+			attribute:  rule.Attribute,
+			expression: expression,
+			when:       when,
+			locale:     rule.Locale,
+			scope:      rule.Scope,
+		})
+	}
+
+	return &Transformer{rules: compiled, channelKey: "scope"}, nil
+}
+
+// ForRecords returns a transformer applying the same rules to reference entity records,
+// whose values hold their channel in "channel" instead of "scope"
+func (t *Transformer) ForRecords() *Transformer {
+	if t == nil {
+		return nil
+	}
+	return &Transformer{rules: t.rules, channelKey: "channel"}
+}
+
+// Enabled reports whether the transformer has any rule
+func (t *Transformer) Enabled() bool {
+	return t != nil && len(t.rules) > 0
+}
+
+// Apply returns a copy of an item with the rules applied to its values, in order.
+//
+// Expressions see the top-level fields of the item (identifier, family, categories...),
+// "values" with the data of every attribute in the locale and channel of the value being
+// computed, and "value", "locale" and "scope" for that value. Attributes that do not exist
+// on the item are created as a non-localizable, non-scopable value unless the result is null or
+// the rule is restricted to a locale or channel.
+func (t *Transformer) Apply(item map[string]interface{}) (map[string]interface{}, error) {
+	if !t.Enabled() {
+		return item, nil
+	}
+
+	values, _ := item["values"].(map[string]interface{})
+	transformed := make(map[string]interface{}, len(values))
+	for attributeCode, entries := range values {
+		transformed[attributeCode] = entries
+	}
+
+	for _, rule := range t.rules {
+		entries, exists := transformed[rule.attribute].([]interface{})
+		if !exists {
+			if rule.locale != "" || rule.scope != "" {
+				continue
+			}
+			entries = []interface{}{map[string]interface{}{"locale": nil, t.channelKey: nil, "data": nil}}
+		}
+
+		result := make([]interface{}, 0, len(entries))
+		for _, entry := range entries {
+			value, ok := entry.(map[string]interface{})
+			if !ok || !rule.matches(value, t.channelKey) {
+				result = append(result, entry)
+				continue
+			}
+
+			data, apply, err := rule.evaluate(item, transformed, value, t.channelKey)
+			if err != nil {
+				return nil, fmt.Errorf("attribute '%s': %w", rule.attribute, err)
+			}
+			if !apply || (!exists && data == nil) {
+				if exists {
+					result = append(result, value)
+				}
+				continue
+			}
+
+			computed := make(map[string]interface{}, len(value))
+			for key, field := range value {
+				computed[key] = field
+			}
+			computed["data"] = data
+			result = append(result, computed)
+		}
+
+		if len(result) > 0 {
+			transformed[rule.attribute] = result
+		}
+	}
+
+	copied := make(map[string]interface{}, len(item))
+	for key, value := range item {
+		copied[key] = value
+	}
+	copied["values"] = transformed
+
+	return copied, nil
+}
+
+// matches reports whether a value is in the locale and channel the rule is restricted to
+func (r compiledRule) matches(value map[string]interface{}, channelKey string) bool {
+	locale, _ := value["locale"].(string)
+	scope, _ := value[channelKey].(string)
+	return (r.locale == "" || r.locale == locale) && (r.scope == "" || r.scope == scope)
+}
+
+// evaluate computes the data of a value, reporting false when the condition does not match
+func (r compiledRule) evaluate(item, values, value map[string]interface{}, channelKey string) (interface{}, bool, error) {
+	env := make(map[string]interface{}, len(item)+4)
+	for key, field := range item {
+		env[key] = field
+	}
+	env["values"] = flatten(values, value["locale"], value[channelKey], channelKey)
+	env["value"] = value["data"]
+	env["locale"] = value["locale"]
+	env["scope"] = value[channelKey]
+
+	if r.when != nil {
+		condition, err := r.when.Eval(env)
+		if err != nil {
+			return nil, false, err
+		}
+		if !expr.Truthy(condition) {
+			return nil, false, nil
+		}
+	}
+
+	data, err := r.expression.Eval(env)
+	return data, true, err
+}
+
+// flatten returns the data of every attribute for a locale and channel.
+// Non-localizable and non-scopable values always match; when the locale or channel
+// is null, the first value of a localizable or scopable attribute is used.
+func flatten(values map[string]interface{}, locale, scope interface{}, channelKey string) map[string]interface{} {
+	flat := make(map[string]interface{}, len(values))
+	for attributeCode, entries := range values {
+		list, _ := entries.([]interface{})
+		for _, entry := range list {
+			value, ok := entry.(map[string]interface{})
+			if !ok || !matches(value["locale"], locale) || !matches(value[channelKey], scope) {
+				continue
+			}
+			flat[attributeCode] = value["data"]
+			break
+		}
+	}
+	return flat
+}
+
+// matches reports whether a value context (locale or channel) is visible from the current one
+func matches(valueContext, current interface{}) bool {
+	return valueContext == nil || current == nil || valueContext == current
+}
@@ -0,0 +1,136 @@
+package transform
+
+import "testing"
+
+func TestApply(t *testing.T) {
+	transformer, err := New([]Rule{
+		{Attribute: "name", Expression: "upper(value)"},
+		{Attribute: "title", Expression: `values.brand + " " + values.name`},
+		{Attribute: "is_premium", Expression: "values.price > 100"},
+		{Attribute: "outlet", Expression: "true", When: `family == "bags"`},
+	})
+	if err != nil {
+		t.Fatalf("Expected valid rules, got %v", err)
+	}
+
+	item := map[string]interface{}{
+		"identifier": "SKU-1",
+		"family":     "shoes",
+		"values": map[string]interface{}{
+			"brand": []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "Acme"}},
+			"name": []interface{}{
+				map[string]interface{}{"locale": "en_US", "scope": nil, "data": "Boot"},
+				map[string]interface{}{"locale": "fr_FR", "scope": nil, "data": "Botte"},
+			},
+			"title": []interface{}{
+				map[string]interface{}{"locale": "en_US", "scope": nil, "data": ""},
+				map[string]interface{}{"locale": "fr_FR", "scope": nil, "data": ""},
+			},
+			"price": []interface{}{map[string]interface{}{"locale": nil, "scope": nil, "data": "129.99"}},
+		},
+	}
+
+	result, err := transformer.Apply(item)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	values := result["values"].(map[string]interface{})
+
+	if data(values, "name", 1) != "BOTTE" {
+		t.Errorf("Expected uppercased name, got %v", data(values, "name", 1))
+	}
+
+	if data(values, "title", 1) != "Acme BOTTE" {
+		t.Errorf("Expected title built from the same locale, got %v", data(values, "title", 1))
+	}
+
+	if data(values, "is_premium", 0) != true {
+		t.Errorf("Expected premium flag, got %v", values["is_premium"])
+	}
+
+	if _, exists := values["outlet"]; exists {
+		t.Error("Expected conditional rule to be skipped")
+	}
+
+	if data(item["values"].(map[string]interface{}), "name", 0) != "Boot" {
+		t.Error("Expected source item not to be modified")
+	}
+}
+
+func TestApply_RestrictedToLocaleAndScope(t *testing.T) {
+	transformer, err := New([]Rule{
+		{Attribute: "url", Expression: `replace(value, "http://", "https://")`, Scope: "ecommerce"},
+		{Attribute: "name", Expression: `concat("[UK] ", value)`, Locale: "en_GB"},
+		{Attribute: "badge", Expression: `"new"`, Locale: "en_GB"},
+	})
+	if err != nil {
+		t.Fatalf("Expected valid rules, got %v", err)
+	}
+
+	item := map[string]interface{}{
+		"values": map[string]interface{}{
+			"url": []interface{}{
+				map[string]interface{}{"locale": nil, "scope": "ecommerce", "data": "http://acme.com/boot"},
+				map[string]interface{}{"locale": nil, "scope": "print", "data": "http://acme.com/boot"},
+			},
+			"name": []interface{}{
+				map[string]interface{}{"locale": "en_GB", "scope": nil, "data": "Boot"},
+				map[string]interface{}{"locale": "fr_FR", "scope": nil, "data": "Botte"},
+			},
+		},
+	}
+
+	result, err := transformer.Apply(item)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	values := result["values"].(map[string]interface{})
+	if data(values, "url", 0) != "https://acme.com/boot" || data(values, "url", 1) != "http://acme.com/boot" {
+		t.Errorf("Expected only the ecommerce url to be rewritten, got %v", values["url"])
+	}
+	if data(values, "name", 0) != "[UK] Boot" || data(values, "name", 1) != "Botte" {
+		t.Errorf("Expected only the en_GB name to be prefixed, got %v", values["name"])
+	}
+	if _, exists := values["badge"]; exists {
+		t.Error("Expected restricted rules not to create missing attributes")
+	}
+}
+
+func TestForRecords_ReadsRecordChannels(t *testing.T) {
+	transformer, err := New([]Rule{{Attribute: "description", Expression: "upper(value)", Scope: "ecommerce"}})
+	if err != nil {
+		t.Fatalf("Expected valid rules, got %v", err)
+	}
+
+	record := map[string]interface{}{
+		"code": "acme",
+		"values": map[string]interface{}{
+			"description": []interface{}{
+				map[string]interface{}{"locale": "en_US", "channel": "ecommerce", "data": "Anvils"},
+				map[string]interface{}{"locale": "en_US", "channel": "mobile", "data": "Anvils"},
+			},
+		},
+	}
+
+	result, err := transformer.ForRecords().Apply(record)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	values := result["values"].(map[string]interface{})
+	if data(values, "description", 0) != "ANVILS" || data(values, "description", 1) != "Anvils" {
+		t.Errorf("Expected only the ecommerce description to be transformed, got %v", values["description"])
+	}
+}
+
+func TestNew_InvalidExpression(t *testing.T) {
+	if _, err := New([]Rule{{Attribute: "name", Expression: "upper("}}); err == nil {
+		t.Error("Expected error for invalid expression")
+	}
+}
+
+func data(values map[string]interface{}, attributeCode string, index int) interface{} {
+	return values[attributeCode].([]interface{})[index].(map[string]interface{})["data"]
+}
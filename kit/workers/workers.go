@@ -0,0 +1,59 @@
+package workers
+
+import "sync"
+
+// Pool runs tasks on a bounded number of goroutines, e.g. the batch writes of a sync against the
+// destination. With a single worker, tasks run inline, one after the other, in submission order.
+type Pool struct {
+	size int
+	sem  chan struct{}
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+}
+
+// New creates a pool running at most size tasks at the same time; sizes below 1 mean 1
+func New(size int) *Pool {
+	if size < 1 {
+		size = 1
+	}
+	return &Pool{
+		size: size,
+		sem:  make(chan struct{}, size),
+	}
+}
+
+// Size returns the number of tasks the pool runs at the same time
+func (p *Pool) Size() int {
+	return p.size
+}
+
+// Go runs a task, waiting for a worker to be free first, so callers producing tasks (e.g. streaming
+// pages from source) never get ahead of the workers by more than the pool size
+func (p *Pool) Go(task func()) {
+	if p.size == 1 {
+		task()
+		return
+	}
+
+	p.sem <- struct{}{}
+	p.wg.Add(1)
+	go func() {
+		defer func() {
+			<-p.sem
+			p.wg.Done()
+		}()
+		task()
+	}()
+}
+
+// Merge runs fn while no other task of the pool merges, so tasks can add their outcome to a shared result
+func (p *Pool) Merge(fn func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fn()
+}
+
+// Wait blocks until every task submitted so far has finished
+func (p *Pool) Wait() {
+	p.wg.Wait()
+}
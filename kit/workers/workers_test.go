@@ -0,0 +1,57 @@
+package workers
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPool_BoundsConcurrency(t *testing.T) {
+	pool := New(3)
+	var running, peak int32
+	total := 0
+
+	for i := 0; i < 20; i++ {
+		pool.Go(func() {
+			current := atomic.AddInt32(&running, 1)
+			for {
+				max := atomic.LoadInt32(&peak)
+				if current <= max || atomic.CompareAndSwapInt32(&peak, max, current) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+
+			pool.Merge(func() { total++ })
+		})
+	}
+	pool.Wait()
+
+	if peak > 3 || peak < 2 {
+		t.Errorf("Expected at most 3 tasks at the same time, got %d", peak)
+	}
+	if total != 20 {
+		t.Errorf("Expected every task to run, got %d", total)
+	}
+}
+
+func TestPool_SingleWorkerRunsInline(t *testing.T) {
+	pool := New(0)
+	var order []int
+
+	for i := 0; i < 5; i++ {
+		pool.Go(func() { order = append(order, i) })
+	}
+	pool.Wait()
+
+	if pool.Size() != 1 || len(order) != 5 {
+		t.Fatalf("Expected 5 inline tasks on 1 worker, got %v (size %d)", order, pool.Size())
+	}
+	for i, value := range order {
+		if value != i {
+			t.Errorf("Expected tasks in submission order, got %v", order)
+			break
+		}
+	}
+}